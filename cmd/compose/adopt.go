@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type adoptOptions struct {
+	*ProjectOptions
+	timeChanged bool
+	timeout     int
+}
+
+func adoptCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := adoptOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "adopt [OPTIONS] CONTAINER SERVICE",
+		Short: "Adopt an orphan container into a service declared by the project",
+		Long: `Adopt an orphan container into a service declared by the project.
+
+Orphan containers, as listed by "compose ps --orphans", are replaced by a service container
+inheriting their anonymous volumes. Container must run the image the service is configured with.`,
+		Args: cli.ExactArgs(2),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			opts.timeChanged = cmd.Flags().Changed("timeout")
+		},
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runAdopt(ctx, dockerCli, backendOptions, opts, args[0], args[1])
+		}),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 1 {
+				return completeServiceNames(dockerCli, p)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	flags := cmd.Flags()
+	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds for the orphan container")
+	return cmd
+}
+
+func runAdopt(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts adoptOptions, ctr string, service string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+
+	var timeout *time.Duration
+	if opts.timeChanged {
+		timeoutValue := time.Duration(opts.timeout) * time.Second
+		timeout = &timeoutValue
+	}
+	return backend.Adopt(ctx, project, api.AdoptOptions{
+		Container: ctr,
+		Service:   service,
+		Timeout:   timeout,
+	})
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/internal"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+// apiSchema describes the Compose API options and compose file extensions supported by this version,
+// for tools to generate forms and validate programmatic invocations
+type apiSchema struct {
+	Schema     string                     `json:"$schema"`
+	Version    string                     `json:"version"`
+	Options    map[string]*api.JSONSchema `json:"options"`
+	Extensions []compose.ExtensionSchema  `json:"extensions"`
+}
+
+func apiSchemaCommand(dockerCli command.Cli) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "api-schema [OPTIONS]",
+		Short: "Describe the Compose API options and compose file extensions supported by this version",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return printAPISchema(dockerCli.Out(), asJSON)
+		}),
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output JSON schemas")
+	return cmd
+}
+
+func printAPISchema(out io.Writer, asJSON bool) error {
+	schema := apiSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Version:    internal.Version,
+		Options:    api.OptionsSchemas(),
+		Extensions: compose.ExtensionsSchemas(),
+	}
+	if asJSON {
+		s, err := formatter.ToStandardJSON(schema)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(out, s)
+		return err
+	}
+	return formatter.Print(schema, formatter.TABLE, out,
+		func(w io.Writer) {
+			for _, name := range slices.Sorted(maps.Keys(schema.Options)) {
+				_, _ = fmt.Fprintf(w, "%s\toptions\t%s\n", name, strings.Join(slices.Sorted(maps.Keys(schema.Options[name].Properties)), ", "))
+			}
+			for _, e := range schema.Extensions {
+				_, _ = fmt.Fprintf(w, "%s\textension (%s)\t%s\n", e.Name, strings.Join(e.Scopes, ", "), e.Description)
+			}
+		},
+		"NAME", "KIND", "DESCRIPTION")
+}
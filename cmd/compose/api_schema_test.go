@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func TestPrintAPISchemaJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printAPISchema(&out, true))
+	var decoded apiSchema
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Assert(t, decoded.Options["DownOptions"] != nil)
+	assert.Equal(t, decoded.Options["DownOptions"].Properties["RemoveOrphans"].Type, "boolean")
+
+	names := make([]string, 0, len(decoded.Extensions))
+	for _, e := range decoded.Extensions {
+		assert.Assert(t, strings.HasPrefix(e.Name, "x-"), e.Name)
+		assert.Assert(t, len(e.Scopes) > 0, e.Name)
+		names = append(names, e.Name)
+	}
+	assert.Assert(t, slices.IsSorted(names))
+	assert.Assert(t, slices.Contains(names, compose.ChownExtension))
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type applyOptions struct {
+	*ProjectOptions
+	prune     bool
+	assumeYes bool
+	wait      bool
+}
+
+func applyCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := applyOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS]",
+		Short: "Converge the project to the state declared by the Compose file",
+		Long: `Converge the project to the state declared by the Compose file
+
+Containers, networks and volumes are created or recreated as needed, and the changes
+are listed for confirmation before being applied. Running apply again once the project
+has converged makes no change.
+
+With --prune, containers, networks and volumes labeled with the project but no longer
+declared by the Compose file are removed.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runApply(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.prune, "prune", false, "Remove containers, networks and volumes no longer declared by the Compose file")
+	flags.BoolVarP(&opts.assumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
+	flags.BoolVar(&opts.wait, "wait", false, "Wait for services to be running|healthy")
+	return cmd
+}
+
+func runApply(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts applyOptions) error {
+	if opts.assumeYes {
+		backendOptions.Options = append(backendOptions.Options, compose.WithPrompt(compose.AlwaysOkPrompt()))
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = backend.Apply(ctx, project, api.ApplyOptions{
+		Prune:     opts.prune,
+		AssumeYes: opts.assumeYes,
+		Wait:      opts.wait,
+	})
+	return err
+}
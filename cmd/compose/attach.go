@@ -57,6 +57,7 @@ func attachCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	}
 
 	runCmd.Flags().IntVar(&opts.index, "index", 0, "index of the container if service has multiple replicas.")
+	runCmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	runCmd.Flags().StringVarP(&opts.detachKeys, "detach-keys", "", "", "Override the key sequence for detaching from a container.")
 
 	runCmd.Flags().BoolVar(&opts.noStdin, "no-stdin", false, "Do not attach STDIN")
@@ -0,0 +1,236 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type bootOptions struct {
+	*ProjectOptions
+	user  bool
+	print bool
+}
+
+func enableOnBootCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := bootOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "enable-on-boot [OPTIONS]",
+		Short: "Start the project when host boots, honoring dependencies order and health",
+		Long: `Start the project when host boots, honoring dependencies order and health.
+
+Installs and enables a systemd unit running "compose up --wait" once the Docker daemon
+has started, so services are started after their dependencies are healthy. Engine restart
+policies don't honor depends_on, so services with restart policy "always" or "unless-stopped"
+are started by the engine in no particular order before the unit runs.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runEnableOnBoot(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	cmd.Flags().BoolVar(&opts.user, "user", false, "Install a systemd user unit")
+	cmd.Flags().BoolVar(&opts.print, "print", false, "Print the systemd unit without installing it")
+	return cmd
+}
+
+func disableOnBootCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
+	opts := bootOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "disable-on-boot [OPTIONS]",
+		Short: "Stop starting the project when host boots",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runDisableOnBoot(ctx, dockerCli, opts)
+		}),
+	}
+	cmd.Flags().BoolVar(&opts.user, "user", false, "Remove a systemd user unit")
+	return cmd
+}
+
+func runEnableOnBoot(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts bootOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	unit := bootUnit(project, executable, opts)
+	if opts.print {
+		_, err = fmt.Fprint(dockerCli.Out(), unit)
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		switch restart := project.Services[name].Restart; restart {
+		case types.RestartPolicyAlways, types.RestartPolicyUnlessStopped:
+			logrus.Warnf("service %q has restart policy %q, engine will start it at boot regardless of its dependencies", name, restart)
+		}
+	}
+
+	dir, err := bootUnitDir(opts.user)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := bootUnitName(project.Name)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(unit), 0o644); err != nil {
+		return err
+	}
+	if err := systemctl(ctx, opts.user, "daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctl(ctx, opts.user, "enable", name); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Project %s will be started on boot by %s\n", project.Name, filepath.Join(dir, name))
+	return nil
+}
+
+func runDisableOnBoot(ctx context.Context, dockerCli command.Cli, opts bootOptions) error {
+	_, projectName, err := opts.projectOrName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	dir, err := bootUnitDir(opts.user)
+	if err != nil {
+		return err
+	}
+	name := bootUnitName(projectName)
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("project %s is not enabled on boot, %s doesn't exist", projectName, path)
+	}
+	if err := systemctl(ctx, opts.user, "disable", name); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := systemctl(ctx, opts.user, "daemon-reload"); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Project %s won't be started on boot\n", projectName)
+	return nil
+}
+
+func bootUnitName(projectName string) string {
+	return fmt.Sprintf("docker-compose-%s.service", projectName)
+}
+
+// bootUnitDir returns the directory systemd loads system or user units from
+func bootUnitDir(user bool) (string, error) {
+	if !user {
+		return "/etc/systemd/system", nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config, "systemd", "user"), nil
+}
+
+// systemctl runs a systemctl command, as a variable so tests can stub it
+var systemctl = func(ctx context.Context, user bool, args ...string) error {
+	if user {
+		args = append([]string{"--user"}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "systemctl", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// bootUnit generates a systemd unit starting project with compose once Docker daemon is running
+func bootUnit(project *types.Project, executable string, opts bootOptions) string {
+	args := []string{"--project-name", project.Name, "--project-directory", project.WorkingDir}
+	for _, f := range project.ComposeFiles {
+		args = append(args, "--file", f)
+	}
+	for _, f := range opts.EnvFiles {
+		args = append(args, "--env-file", f)
+	}
+	for _, p := range opts.Profiles {
+		args = append(args, "--profile", p)
+	}
+	command := quoteUnitArgs(append([]string{executable}, args...))
+
+	wantedBy := "multi-user.target"
+	if opts.user {
+		wantedBy = "default.target"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[Unit]\n")
+	fmt.Fprintf(&sb, "Description=Docker Compose project %s\n", project.Name)
+	if !opts.user {
+		// user units can't depend on system units, Docker daemon is expected to be running
+		sb.WriteString("Requires=docker.service\n")
+		sb.WriteString("After=docker.service network-online.target\n")
+		sb.WriteString("Wants=network-online.target\n")
+	}
+	sb.WriteString("\n[Service]\n")
+	sb.WriteString("Type=oneshot\n")
+	sb.WriteString("RemainAfterExit=yes\n")
+	fmt.Fprintf(&sb, "WorkingDirectory=%s\n", strings.ReplaceAll(project.WorkingDir, "%", "%%"))
+	fmt.Fprintf(&sb, "ExecStart=%s up --detach --wait --no-recreate\n", command)
+	fmt.Fprintf(&sb, "ExecStop=%s stop\n", command)
+	sb.WriteString("TimeoutStartSec=0\n")
+	sb.WriteString("\n[Install]\n")
+	fmt.Fprintf(&sb, "WantedBy=%s\n", wantedBy)
+	return sb.String()
+}
+
+// quoteUnitArgs quotes command line arguments for a systemd unit Exec directive
+func quoteUnitArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\$%;") {
+			arg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`).Replace(arg) + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestBootUnit(t *testing.T) {
+	project := &types.Project{
+		Name:         "myproject",
+		WorkingDir:   "/srv/my project",
+		ComposeFiles: []string{"/srv/my project/compose.yaml"},
+	}
+	opts := bootOptions{ProjectOptions: &ProjectOptions{Profiles: []string{"prod"}}}
+	assert.Equal(t, bootUnit(project, "/usr/libexec/docker/cli-plugins/docker-compose", opts), `[Unit]
+Description=Docker Compose project myproject
+Requires=docker.service
+After=docker.service network-online.target
+Wants=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+WorkingDirectory=/srv/my project
+ExecStart=/usr/libexec/docker/cli-plugins/docker-compose --project-name myproject --project-directory "/srv/my project" --file "/srv/my project/compose.yaml" --profile prod up --detach --wait --no-recreate
+ExecStop=/usr/libexec/docker/cli-plugins/docker-compose --project-name myproject --project-directory "/srv/my project" --file "/srv/my project/compose.yaml" --profile prod stop
+TimeoutStartSec=0
+
+[Install]
+WantedBy=multi-user.target
+`)
+
+	opts.user = true
+	unit := bootUnit(project, "docker-compose", opts)
+	assert.Assert(t, !strings.Contains(unit, "docker.service"))
+	assert.Assert(t, strings.HasSuffix(unit, "WantedBy=default.target\n"))
+}
+
+func TestQuoteUnitArgs(t *testing.T) {
+	assert.Equal(t, quoteUnitArgs([]string{"compose", "-f", "/a b/c.yaml", "", `50%$"x"`}),
+		`compose -f "/a b/c.yaml" "" "50%%$$\"x\""`)
+}
@@ -0,0 +1,303 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/prompt"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type bugreportOptions struct {
+	*ProjectOptions
+	output    string
+	since     string
+	tail      string
+	redact    []string
+	assumeYes bool
+}
+
+func bugreportCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := bugreportOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "bugreport [OPTIONS]",
+		Short: "Collect project and engine state in an archive to be attached to a bug report",
+		Long: `Collect project and engine state in an archive to be attached to a bug report.
+
+The archive includes the resolved Compose model, container states, recent events, service
+logs, and Docker engine information. Environment values, inlined secrets and values of
+variables or labels which name suggests a secret are redacted.
+
+Collected files are first written to a temporary directory, so they can be reviewed and
+edited to redact more information before the archive is created.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runBugreport(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Path of the archive to write (default \"compose-bugreport-<timestamp>.tar.gz\")")
+	flags.StringVar(&opts.since, "since", "1h", "Collect events and logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+	flags.StringVar(&opts.tail, "tail", "500", "Number of log lines to collect for each container")
+	flags.StringArrayVar(&opts.redact, "redact", nil, "Additional value to redact")
+	flags.BoolVarP(&opts.assumeYes, "yes", "y", false, "Write the archive without reviewing collected files")
+	return cmd
+}
+
+func runBugreport(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts bugreportOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	// bug report is also collected for a broken project
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		logrus.Warnf("failed to load project, resolved model won't be collected: %v", err)
+		project = nil
+	}
+
+	files, err := engineBundleFiles(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	projectFiles, err := collectProjectState(ctx, backend, project, opts)
+	if err != nil {
+		return err
+	}
+	files = append(files, projectFiles...)
+	redactor := newRedactor(project, opts.redact)
+	for i, f := range files {
+		files[i].content = []byte(redactor.Replace(string(f.content)))
+	}
+
+	output := opts.output
+	if output == "" {
+		output = fmt.Sprintf("compose-bugreport-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+	if !opts.assumeYes {
+		files, err = reviewBundleFiles(dockerCli, files, output)
+		if err != nil || files == nil {
+			return err
+		}
+	}
+	if err := writeBundle(output, files); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Err(), "Bug report written to %s\n", output)
+	return nil
+}
+
+// collectProjectState collects redacted project model, container states, recent events and service logs
+func collectProjectState(ctx context.Context, backend api.Compose, project *types.Project, opts bugreportOptions) ([]bundleFile, error) {
+	name := opts.ProjectName
+	var files []bundleFile
+	if project != nil {
+		name = project.Name
+		redacted, err := redactProject(project)
+		if err != nil {
+			return nil, err
+		}
+		content, err := redacted.MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{name: "compose.yaml", content: content})
+	}
+	if name == "" {
+		return files, nil
+	}
+
+	containers, err := backend.Ps(ctx, name, api.PsOptions{Project: project, All: true})
+	if err != nil {
+		logrus.Warnf("failed to collect containers: %v", err)
+	} else {
+		for i, c := range containers {
+			containers[i].Labels = redactLabels(c.Labels)
+		}
+		content, err := json.MarshalIndent(containers, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{name: "containers.json", content: content})
+	}
+
+	var events bytes.Buffer
+	err = backend.Events(ctx, name, api.EventsOptions{
+		Since: opts.since,
+		Until: strconv.FormatInt(time.Now().Unix(), 10),
+		Consumer: func(event api.Event) error {
+			event.Attributes = redactLabels(event.Attributes)
+			return json.NewEncoder(&events).Encode(event)
+		},
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		logrus.Warnf("failed to collect events: %v", err)
+	}
+	files = append(files, bundleFile{name: "events.json", content: events.Bytes()})
+
+	logs := &bugreportLogConsumer{}
+	err = backend.Logs(ctx, name, logs, api.LogOptions{
+		Project:    project,
+		Since:      opts.since,
+		Tail:       opts.tail,
+		Timestamps: true,
+	})
+	if err != nil {
+		logrus.Warnf("failed to collect logs: %v", err)
+	}
+	files = append(files, bundleFile{name: "logs.txt", content: logs.buf.Bytes()})
+	return files, nil
+}
+
+// bugreportLogConsumer collects service logs
+type bugreportLogConsumer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *bugreportLogConsumer) Log(containerName, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = fmt.Fprintf(&l.buf, "%s | %s\n", containerName, message)
+}
+
+func (l *bugreportLogConsumer) Err(containerName, message string) {
+	l.Log(containerName, message)
+}
+
+func (l *bugreportLogConsumer) Status(containerName, message string) {}
+
+func redactLabels(labels map[string]string) map[string]string {
+	redacted := map[string]string{}
+	for k, v := range labels {
+		if v != "" && compose.IsSensitiveName(k) {
+			v = *redactedValue()
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// newRedactor replaces values which could be secrets: project environment values and inlined secrets for
+// names suggesting a secret, and additional values set by user
+func newRedactor(project *types.Project, values []string) *strings.Replacer {
+	sensitive := slices.Clone(values)
+	if project != nil {
+		for _, s := range project.Services {
+			for k, v := range s.Environment {
+				if v != nil && *v != "" && compose.IsSensitiveName(k) {
+					sensitive = append(sensitive, *v)
+				}
+			}
+		}
+		for _, s := range project.Secrets {
+			if s.Content != "" {
+				sensitive = append(sensitive, s.Content)
+			}
+		}
+	}
+	// longest values first, so a value containing another one is fully redacted
+	slices.SortFunc(sensitive, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	var oldnew []string
+	for _, v := range slices.Compact(sensitive) {
+		if v != "" {
+			oldnew = append(oldnew, v, *redactedValue())
+		}
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// reviewBundleFiles writes files to a temporary directory for user to review and edit them, then reads them back.
+// Returns nil if user declines to write the archive.
+func reviewBundleFiles(dockerCli command.Cli, files []bundleFile, output string) ([]bundleFile, error) {
+	dir, err := os.MkdirTemp("", "compose-bugreport-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.content, 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Collected files have been written to %s for review:\n", dir)
+	for _, f := range files {
+		_, _ = fmt.Fprintf(dockerCli.Out(), "  - %s (%d bytes)\n", f.name, len(f.content))
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), "Edit or delete files to redact more information before the archive is created.")
+	msg := fmt.Sprintf("Write %s? [Y/n]: ", output)
+	confirmed, err := prompt.NewPrompt(dockerCli.In(), dockerCli.Out()).Confirm(msg, true)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, nil
+	}
+	return readBundleFiles(dir)
+}
+
+// readBundleFiles reads files from dir, sorted by name
+func readBundleFiles(dir string) ([]bundleFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string][]byte{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		content[e.Name()] = b
+	}
+	files := []bundleFile{}
+	for _, name := range slices.Sorted(maps.Keys(content)) {
+		files = append(files, bundleFile{name: name, content: content[name]})
+	}
+	return files, nil
+}
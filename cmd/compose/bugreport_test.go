@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func TestNewRedactor(t *testing.T) {
+	password := "s3cr3t"
+	level := "debug"
+	project := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name: "web",
+				Environment: types.MappingWithEquals{
+					"DB_PASSWORD": &password,
+					"LOG_LEVEL":   &level,
+				},
+			},
+		},
+		Secrets: types.Secrets{
+			"key": {Content: "inlined"},
+		},
+	}
+	redactor := newRedactor(project, []string{"internal.example.com"})
+	assert.Equal(t,
+		redactor.Replace("connecting to internal.example.com with s3cr3t, debug: inlined"),
+		"connecting to <redacted> with <redacted>, debug: <redacted>")
+}
+
+func TestCollectProjectState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(ctrl)
+	backend.EXPECT().Ps(gomock.Any(), "myproject", api.PsOptions{All: true}).Return([]api.ContainerSummary{{
+		Name:   "myproject-web-1",
+		State:  "running",
+		Labels: map[string]string{"com.example.api-token": "abc", "com.example.team": "core"},
+	}}, nil)
+	backend.EXPECT().Events(gomock.Any(), "myproject", gomock.Any()).DoAndReturn(
+		func(_ any, _ string, options api.EventsOptions) error {
+			assert.Equal(t, options.Since, "1h")
+			assert.NilError(t, options.Consumer(api.Event{Service: "web", Status: "die"}))
+			return io.EOF
+		})
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, _ string, consumer api.LogConsumer, options api.LogOptions) error {
+			assert.Equal(t, options.Tail, "10")
+			consumer.Log("myproject-web-1", "listening on :80")
+			return nil
+		})
+
+	files, err := collectProjectState(t.Context(), backend, nil, bugreportOptions{
+		ProjectOptions: &ProjectOptions{ProjectName: "myproject"},
+		since:          "1h",
+		tail:           "10",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 3)
+	assert.Equal(t, files[0].name, "containers.json")
+	assert.Assert(t, !bytes.Contains(files[0].content, []byte("abc")))
+	assert.Assert(t, bytes.Contains(files[0].content, []byte("core")))
+	assert.Equal(t, files[1].name, "events.json")
+	assert.Assert(t, bytes.Contains(files[1].content, []byte(`"Status":"die"`)))
+	assert.Equal(t, files[2].name, "logs.txt")
+	assert.Equal(t, string(files[2].content), "myproject-web-1 | listening on :80\n")
+}
+
+func TestReadBundleFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "logs.txt"), []byte("logs"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("services: {}"), 0o600))
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "ignored"), 0o700))
+
+	files, err := readBundleFiles(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, []bundleFile{
+		{name: "compose.yaml", content: []byte("services: {}")},
+		{name: "logs.txt", content: []byte("logs")},
+	}, cmp.AllowUnexported(bundleFile{}))
+}
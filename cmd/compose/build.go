@@ -30,24 +30,28 @@ import (
 	"github.com/docker/compose/v5/pkg/compose"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose/v5/cmd/prompt"
 	"github.com/docker/compose/v5/pkg/api"
 )
 
 type buildOptions struct {
 	*ProjectOptions
-	quiet      bool
-	pull       bool
-	push       bool
-	args       []string
-	noCache    bool
-	memory     cliopts.MemBytes
-	ssh        string
-	builder    string
-	deps       bool
-	print      bool
-	check      bool
-	sbom       string
-	provenance string
+	quiet       bool
+	pull        bool
+	push        bool
+	args        []string
+	noCache     bool
+	memory      cliopts.MemBytes
+	ssh         string
+	builder     string
+	deps        bool
+	print       bool
+	check       bool
+	sbom        string
+	provenance  string
+	interactive bool
+	targets     []string
+	set         []string
 }
 
 func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions, error) {
@@ -72,11 +76,17 @@ func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions,
 		uiMode = "rawjson"
 	}
 
+	args, overrides, err := opts.buildOverrides()
+	if err != nil {
+		return api.BuildOptions{}, err
+	}
+
 	return api.BuildOptions{
 		Pull:       opts.pull,
 		Push:       opts.push,
 		Progress:   uiMode,
-		Args:       types.NewMappingWithEquals(opts.args),
+		Args:       types.NewMappingWithEquals(args),
+		Overrides:  overrides,
 		NoCache:    opts.noCache,
 		Quiet:      opts.quiet,
 		Services:   services,
@@ -91,6 +101,50 @@ func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions,
 	}, nil
 }
 
+// buildOverrides collects per-service overrides set by `--build-arg service:KEY=VALUE`, `--target [service=]stage`
+// and `--set service.attribute=value`, and returns build args applying to all services
+func (opts buildOptions) buildOverrides() ([]string, api.BuildOverrides, error) {
+	var (
+		args      []string
+		overrides api.BuildOverrides
+	)
+	for _, arg := range opts.args {
+		key, value, hasValue := strings.Cut(arg, "=")
+		service, key, scoped := strings.Cut(key, ":")
+		if !scoped {
+			args = append(args, arg)
+			continue
+		}
+		if service == "" || key == "" {
+			return nil, nil, fmt.Errorf("invalid build arg %q, expected service:KEY=VALUE", arg)
+		}
+		if !hasValue {
+			// as for unscoped build args, value is read from environment
+			value = os.Getenv(key)
+		}
+		overrides = append(overrides, api.BuildOverride{Service: service, Attribute: "args." + key, Value: value})
+	}
+	for _, target := range opts.targets {
+		service, stage, scoped := strings.Cut(target, "=")
+		if !scoped {
+			service, stage = "*", target
+		}
+		if service == "" || stage == "" {
+			return nil, nil, fmt.Errorf("invalid target %q, expected [service=]stage", target)
+		}
+		overrides = append(overrides, api.BuildOverride{Service: service, Attribute: "target", Value: stage})
+	}
+	for _, set := range opts.set {
+		path, value, ok := strings.Cut(set, "=")
+		service, attribute, hasAttribute := strings.Cut(path, ".")
+		if !ok || !hasAttribute || service == "" || attribute == "" {
+			return nil, nil, fmt.Errorf("invalid override %q, expected service.attribute=value", set)
+		}
+		overrides = append(overrides, api.BuildOverride{Service: service, Attribute: attribute, Value: value})
+	}
+	return args, overrides, nil
+}
+
 func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
 	opts := buildOptions{
 		ProjectOptions: p,
@@ -99,6 +153,9 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 		Use:   "build [OPTIONS] [SERVICE...]",
 		Short: "Build or rebuild services",
 		PreRunE: Adapt(func(ctx context.Context, args []string) error {
+			if err := checkInteractive(opts.interactive, args); err != nil {
+				return err
+			}
 			if opts.quiet {
 				display.Mode = display.ModeQuiet
 				devnull, err := os.Open(os.DevNull)
@@ -124,7 +181,9 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	flags.BoolVar(&opts.push, "push", false, "Push service images")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the build output")
 	flags.BoolVar(&opts.pull, "pull", false, "Always attempt to pull a newer version of the image")
-	flags.StringArrayVar(&opts.args, "build-arg", []string{}, "Set build-time variables for services")
+	flags.StringArrayVar(&opts.args, "build-arg", []string{}, `Set build-time variables for services, or for a single service as "service:KEY=VALUE"`)
+	flags.StringArrayVar(&opts.targets, "target", []string{}, `Set the build stage to target, or for a single service as "service=stage"`)
+	flags.StringArrayVar(&opts.set, "set", []string{}, `Override a service build attribute (e.g. "web.args.KEY=value", "*.no_cache=true")`)
 	flags.StringVar(&opts.ssh, "ssh", "", "Set SSH authentications used when building service images. (use 'default' for using your default SSH Agent)")
 	flags.StringVar(&opts.builder, "builder", "", "Set builder to use")
 	flags.BoolVar(&opts.deps, "with-dependencies", false, "Also build dependencies (transitively)")
@@ -145,6 +204,7 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	flags.MarkHidden("progress") //nolint:errcheck
 	flags.BoolVar(&opts.print, "print", false, "Print equivalent bake file")
 	flags.BoolVar(&opts.check, "check", false, "Check build configuration")
+	flags.BoolVar(&opts.interactive, "interactive", false, interactiveFlagUsage)
 
 	return cmd
 }
@@ -168,6 +228,14 @@ func runBuild(ctx context.Context, dockerCli command.Cli, backendOptions *Backen
 		return err
 	}
 
+	if opts.interactive {
+		ui := prompt.NewPrompt(dockerCli.In(), dockerCli.Out())
+		services, err = selectServices(ctx, backend, ui, project, project.Name, "Select services to build")
+		if err != nil {
+			return err
+		}
+	}
+
 	apiBuildOptions, err := opts.toAPIBuildOptions(services)
 	if err != nil {
 		return err
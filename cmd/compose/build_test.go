@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestBuildOverrides(t *testing.T) {
+	t.Setenv("TOKEN", "from-env")
+	opts := buildOptions{
+		args:    []string{"GLOBAL=1", "URL=http://example.com", "web:VERSION=1.2", "web:TOKEN"},
+		targets: []string{"dev", "api=test"},
+		set:     []string{"web.labels.team=core", "*.no_cache=true"},
+	}
+	args, overrides, err := opts.buildOverrides()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{"GLOBAL=1", "URL=http://example.com"})
+	assert.DeepEqual(t, overrides, api.BuildOverrides{
+		{Service: "web", Attribute: "args.VERSION", Value: "1.2"},
+		{Service: "web", Attribute: "args.TOKEN", Value: "from-env"},
+		{Service: "*", Attribute: "target", Value: "dev"},
+		{Service: "api", Attribute: "target", Value: "test"},
+		{Service: "web", Attribute: "labels.team", Value: "core"},
+		{Service: "*", Attribute: "no_cache", Value: "true"},
+	})
+
+	_, _, err = buildOptions{args: []string{":KEY=value"}}.buildOverrides()
+	assert.Error(t, err, `invalid build arg ":KEY=value", expected service:KEY=VALUE`)
+	_, _, err = buildOptions{targets: []string{"web="}}.buildOverrides()
+	assert.Error(t, err, `invalid target "web=", expected [service=]stage`)
+	_, _, err = buildOptions{set: []string{"web=value"}}.buildOverrides()
+	assert.Error(t, err, `invalid override "web=value", expected service.attribute=value`)
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func certsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "certs CMD [OPTIONS]",
+		Short:            "Manage TLS certificates generated for services declaring x-tls",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		certsRenewCommand(p, dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type certsRenewOptions struct {
+	*ProjectOptions
+	ca bool
+}
+
+func certsRenewCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := certsRenewOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "renew [OPTIONS] [SERVICE...]",
+		Short: "Renew services TLS certificates",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runCertsRenew(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().BoolVar(&opts.ca, "ca", false, "Also renew the project certificate authority")
+	return cmd
+}
+
+func runCertsRenew(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts certsRenewOptions, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+
+	return backend.RenewCertificates(ctx, project, api.CertificatesOptions{
+		Services: services,
+		CA:       opts.ca,
+	})
+}
@@ -17,7 +17,11 @@
 package compose
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"os"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/opts"
@@ -31,6 +35,9 @@ type commitOptions struct {
 
 	service   string
 	reference string
+	services  []string
+	tag       string
+	override  string
 
 	pause   bool
 	comment string
@@ -45,10 +52,24 @@ func commitCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 		ProjectOptions: p,
 	}
 	cmd := &cobra.Command{
-		Use:   "commit [OPTIONS] SERVICE [REPOSITORY[:TAG]]",
+		Use:   "commit [OPTIONS] SERVICE [REPOSITORY[:TAG]] | --tag TAG [SERVICE...]",
 		Short: "Create a new image from a service container's changes",
-		Args:  cobra.RangeArgs(1, 2),
+		Long: `Create a new image from a service container's changes.
+
+With --tag, a container of each selected service, or of all services, is committed
+as PROJECT/SERVICE:TAG, and --override-file writes a compose file pinning services
+to the committed images.`,
 		PreRunE: Adapt(func(ctx context.Context, args []string) error {
+			if options.tag != "" {
+				options.services = args
+				return nil
+			}
+			if options.override != "" {
+				return errors.New("--override-file requires --tag")
+			}
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New(`"commit" requires SERVICE [REPOSITORY[:TAG]] arguments, or --tag`)
+			}
 			options.service = args[0]
 			if len(args) > 1 {
 				options.reference = args[1]
@@ -64,12 +85,15 @@ func commitCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 
 	flags := cmd.Flags()
 	flags.IntVar(&options.index, "index", 0, "index of the container if service has multiple replicas.")
+	cmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 
 	flags.BoolVarP(&options.pause, "pause", "p", true, "Pause container during commit")
 	flags.StringVarP(&options.comment, "message", "m", "", "Commit message")
 	flags.StringVarP(&options.author, "author", "a", "", `Author (e.g., "John Hannibal Smith <hannibal@a-team.com>")`)
 	options.changes = opts.NewListOpts(nil)
 	flags.VarP(&options.changes, "change", "c", "Apply Dockerfile instruction to the created image")
+	flags.StringVar(&options.tag, "tag", "", "Commit all services, or the selected ones, as PROJECT/SERVICE:TAG images")
+	flags.StringVar(&options.override, "override-file", "", "Write a compose file pinning services to the committed images")
 
 	return cmd
 }
@@ -84,13 +108,26 @@ func runCommit(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 	if err != nil {
 		return err
 	}
-	return backend.Commit(ctx, projectName, api.CommitOptions{
+
+	var override io.Writer
+	buf := &bytes.Buffer{}
+	if options.override != "" {
+		override = buf
+	}
+	err = backend.Commit(ctx, projectName, api.CommitOptions{
 		Service:   options.service,
 		Reference: options.reference,
+		Services:  options.services,
+		Tag:       options.tag,
+		Override:  override,
 		Pause:     options.pause,
 		Comment:   options.comment,
 		Author:    options.author,
 		Changes:   options.changes,
 		Index:     options.index,
 	})
+	if err != nil || options.override == "" {
+		return err
+	}
+	return os.WriteFile(options.override, buf.Bytes(), 0o644)
 }
@@ -17,9 +17,13 @@
 package compose
 
 import (
+	"context"
+	"os"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/compose"
@@ -35,26 +39,143 @@ func noCompletion() validArgsFn {
 	}
 }
 
+// completionProject loads the project to complete values from. When compose files can't be loaded but project
+// name is known, typically set by -p, only the project name is returned so values are queried from the engine.
+func completionProject(ctx context.Context, dockerCli command.Cli, p *ProjectOptions) (*types.Project, string) {
+	p.Offline = true
+	_ = p.applyAlias(aliasRegistryPath())
+	backend, err := compose.NewComposeService(dockerCli)
+	if err != nil {
+		return nil, ""
+	}
+	project, _, err := p.ToProject(ctx, dockerCli, backend, nil)
+	if err == nil {
+		return project, project.Name
+	}
+	if p.ProjectName != "" {
+		return nil, p.ProjectName
+	}
+	return nil, os.Getenv(ComposeProjectName)
+}
+
+// completionContainers lists containers of a project, ignoring errors as completion is best effort
+func completionContainers(ctx context.Context, dockerCli command.Cli, projectName string) []api.ContainerSummary {
+	backend, err := compose.NewComposeService(dockerCli)
+	if err != nil {
+		return nil
+	}
+	containers, err := backend.Ps(ctx, projectName, api.PsOptions{All: true})
+	if err != nil {
+		return nil
+	}
+	return containers
+}
+
+// filterCompletions returns sorted unique values starting with toComplete
+func filterCompletions(values []string, toComplete string) []string {
+	var filtered []string
+	for _, v := range values {
+		if strings.HasPrefix(v, toComplete) && !slices.Contains(filtered, v) {
+			filtered = append(filtered, v)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
 func completeServiceNames(dockerCli command.Cli, p *ProjectOptions) validArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		p.Offline = true
-		backend, err := compose.NewComposeService(dockerCli)
-		if err != nil {
+		project, name := completionProject(cmd.Context(), dockerCli, p)
+		var serviceNames []string
+		switch {
+		case project != nil:
+			serviceNames = append(project.ServiceNames(), project.DisabledServiceNames()...)
+		case name != "":
+			for _, c := range completionContainers(cmd.Context(), dockerCli, name) {
+				serviceNames = append(serviceNames, c.Service)
+			}
+		}
+		return filterCompletions(serviceNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeNetworkNames completes the network name as first argument, then service names
+func completeNetworkNames(dockerCli command.Cli, p *ProjectOptions) validArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return completeServiceNames(dockerCli, p)(cmd, args, toComplete)
+		}
+		project, name := completionProject(cmd.Context(), dockerCli, p)
+		var networkNames []string
+		switch {
+		case project != nil:
+			networkNames = project.NetworkNames()
+		case name != "":
+			backend, err := compose.NewComposeService(dockerCli)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			networks, err := backend.Networks(cmd.Context(), name, api.NetworksOptions{})
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, n := range networks {
+				networkNames = append(networkNames, n.Network)
+			}
+		}
+		return filterCompletions(networkNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeVolumeNames(dockerCli command.Cli, p *ProjectOptions) validArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
+		project, name := completionProject(cmd.Context(), dockerCli, p)
+		var volumeNames []string
+		switch {
+		case project != nil:
+			volumeNames = project.VolumeNames()
+		case name != "":
+			backend, err := compose.NewComposeService(dockerCli)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			volumes, err := backend.Volumes(cmd.Context(), name, api.VolumesOptions{})
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			for _, v := range volumes {
+				if key := v.Labels[api.VolumeLabel]; key != "" {
+					volumeNames = append(volumeNames, key)
+				}
+			}
+		}
+		return filterCompletions(volumeNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
 
-		project, _, err := p.ToProject(cmd.Context(), dockerCli, backend, nil)
-		if err != nil {
+// completeContainerIndexes completes --index with the numbers of containers for the service set as first argument
+func completeContainerIndexes(dockerCli command.Cli, p *ProjectOptions) validArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-		var values []string
-		serviceNames := append(project.ServiceNames(), project.DisabledServiceNames()...)
-		for _, s := range serviceNames {
-			if toComplete == "" || strings.HasPrefix(s, toComplete) {
-				values = append(values, s)
+		// cp sets service as SERVICE:PATH
+		service, _, _ := strings.Cut(args[0], ":")
+		_, name := completionProject(cmd.Context(), dockerCli, p)
+		if name == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var indexes []string
+		for _, c := range completionContainers(cmd.Context(), dockerCli, name) {
+			if c.Service != service || c.Labels[api.OneoffLabel] == "True" {
+				continue
 			}
+			indexes = append(indexes, c.Labels[api.ContainerNumberLabel])
 		}
-		return values, cobra.ShellCompDirectiveNoFileComp
+		return filterCompletions(indexes, toComplete), cobra.ShellCompDirectiveNoFileComp
 	}
 }
 
@@ -83,27 +204,11 @@ func completeProjectNames(dockerCli command.Cli, backendOptions *BackendOptions)
 
 func completeProfileNames(dockerCli command.Cli, p *ProjectOptions) validArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		p.Offline = true
-		backend, err := compose.NewComposeService(dockerCli)
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-
-		project, _, err := p.ToProject(cmd.Context(), dockerCli, backend, nil)
-		if err != nil {
+		project, _ := completionProject(cmd.Context(), dockerCli, p)
+		if project == nil {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-
-		allProfileNames := project.AllServices().GetProfiles()
-		sort.Strings(allProfileNames)
-
-		var values []string
-		for _, profileName := range allProfileNames {
-			if strings.HasPrefix(profileName, toComplete) {
-				values = append(values, profileName)
-			}
-		}
-		return values, cobra.ShellCompDirectiveNoFileComp
+		return filterCompletions(project.AllServices().GetProfiles(), toComplete), cobra.ShellCompDirectiveNoFileComp
 	}
 }
 
@@ -0,0 +1,30 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFilterCompletions(t *testing.T) {
+	values := []string{"web", "db", "worker", "web", "cache"}
+	assert.DeepEqual(t, filterCompletions(values, "w"), []string{"web", "worker"})
+	assert.DeepEqual(t, filterCompletions(values, ""), []string{"cache", "db", "web", "worker"})
+	assert.Assert(t, filterCompletions(values, "x") == nil)
+}
@@ -69,6 +69,19 @@ const (
 	ComposeMenu = "COMPOSE_MENU"
 	// ComposeProgress defines type of progress output, if --progress isn't used
 	ComposeProgress = "COMPOSE_PROGRESS"
+	// ComposePolicy defines a policy (rego file or command) evaluated against project before up, down and run
+	ComposePolicy = "COMPOSE_POLICY"
+	// ComposeClassifiedExitCodes enables exit codes listed by `compose exit-codes` for classified failures,
+	// which otherwise exit with code 1
+	ComposeClassifiedExitCodes = "COMPOSE_CLASSIFIED_EXIT_CODES"
+	// ComposePreset selects a preset of command line flags defined by compose.settings.yaml, if --preset isn't used
+	ComposePreset = "COMPOSE_PRESET"
+	// ComposeSyncHosts maintains hosts file entries for services declaring hostnames, if --sync-hosts isn't used
+	ComposeSyncHosts = "COMPOSE_SYNC_HOSTS"
+	// ComposeProjectAlias selects a project registered by `compose project add`, if --project-alias isn't used
+	ComposeProjectAlias = "COMPOSE_PROJECT_ALIAS"
+	// ComposeStateCache enables recording state reported by ps, ls and images, to be used with --offline
+	ComposeStateCache = "COMPOSE_STATE_CACHE"
 )
 
 // rawEnv load a dot env file using docker/cli key=value parser, without attempt to interpolate or evaluate values
@@ -119,11 +132,15 @@ func AdaptCmd(fn CobraCommand) func(cmd *cobra.Command, args []string) error {
 		err := fn(ctx, cmd, args)
 		if api.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled) {
 			err = dockercli.StatusError{
-				StatusCode: 130,
+				StatusCode: ExitCodeCanceled,
 			}
 		}
+		code := errorCode(err)
+		if utils.StringToBool(os.Getenv(ComposeClassifiedExitCodes)) {
+			err = withExitCode(err, code)
+		}
 		if display.Mode == display.ModeJSON {
-			err = makeJSONError(err)
+			err = makeJSONError(err, code)
 		}
 		return err
 	}
@@ -147,7 +164,11 @@ type ProjectOptions struct {
 	Progress           string
 	Offline            bool
 	All                bool
+	Alias              string
+	PinRefs            bool
+	Refresh            bool
 	insecureRegistries []string
+	aliasApplied       bool
 }
 
 // ProjectFunc does stuff within a types.Project
@@ -188,14 +209,16 @@ func (o *ProjectOptions) WithServices(dockerCli command.Cli, fn ProjectServicesF
 }
 
 type jsonErrorData struct {
-	Error   bool   `json:"error,omitempty"`
-	Message string `json:"message,omitempty"`
+	Error   bool        `json:"error,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Code    api.ErrCode `json:"code,omitempty"`
 }
 
-func errorAsJSON(message string) string {
+func errorAsJSON(message string, code api.ErrCode) string {
 	errorMessage := &jsonErrorData{
 		Error:   true,
 		Message: message,
+		Code:    code,
 	}
 	marshal, err := json.Marshal(errorMessage)
 	if err == nil {
@@ -205,7 +228,7 @@ func errorAsJSON(message string) string {
 	}
 }
 
-func makeJSONError(err error) error {
+func makeJSONError(err error, code api.ErrCode) error {
 	if err == nil {
 		return nil
 	}
@@ -213,24 +236,28 @@ func makeJSONError(err error) error {
 	if errors.As(err, &statusErr) {
 		return dockercli.StatusError{
 			StatusCode: statusErr.StatusCode,
-			Status:     errorAsJSON(statusErr.Status),
+			Status:     errorAsJSON(statusErr.Status, code),
 		}
 	}
-	return fmt.Errorf("%s", errorAsJSON(err.Error()))
+	return fmt.Errorf("%s", errorAsJSON(err.Error(), code))
 }
 
 func (o *ProjectOptions) addProjectFlags(f *pflag.FlagSet) {
 	f.StringArrayVar(&o.Profiles, "profile", []string{}, "Specify a profile to enable")
 	f.StringVarP(&o.ProjectName, "project-name", "p", "", "Project name")
+	f.StringVarP(&o.Alias, "project-alias", "P", "", "Run command on a project registered by `compose project add`")
 	f.StringArrayVarP(&o.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 	f.StringArrayVar(&o.insecureRegistries, "insecure-registry", []string{}, "Use insecure registry to pull Compose OCI artifacts. Doesn't apply to images")
 	_ = f.MarkHidden("insecure-registry")
-	f.StringArrayVar(&o.EnvFiles, "env-file", defaultStringArrayVar(ComposeEnvFiles), "Specify an alternate environment file")
-	f.StringVar(&o.ProjectDir, "project-directory", "", "Specify an alternate working directory\n(default: the path of the, first specified, Compose file)")
+	f.StringArrayVar(&o.EnvFiles, "env-file", defaultStringArrayVar(ComposeEnvFiles), "Specify an alternate environment file. Can be repeated, a file overrides the ones set before")
+	f.StringVar(&o.ProjectDir, "project-directory", "", "Specify an alternate working directory, or a git repository URL\n(default: the path of the, first specified, Compose file)")
 	f.StringVar(&o.WorkDir, "workdir", "", "DEPRECATED! USE --project-directory INSTEAD.\nSpecify an alternate working directory\n(default: the path of the, first specified, Compose file)")
 	f.BoolVar(&o.Compatibility, "compatibility", false, "Run compose in backward compatibility mode")
 	f.StringVar(&o.Progress, "progress", os.Getenv(ComposeProgress), fmt.Sprintf(`Set type of progress output (%s)`, strings.Join(printerModes, ", ")))
 	f.BoolVar(&o.All, "all-resources", false, "Include all resources, even those not used by services")
+	f.BoolVar(&o.Offline, "offline", false, "Don't load remote resources. ps, ls and images report state cached with COMPOSE_STATE_CACHE when Docker engine is unreachable")
+	f.BoolVar(&o.PinRefs, "pin-refs", false, "Pin git refs of remote compose files and project directory to the commits they resolve to when first checked out")
+	f.BoolVar(&o.Refresh, "refresh", false, "With --pin-refs, resolve git refs again and pin the commits they now resolve to")
 	_ = f.MarkHidden("workdir")
 }
 
@@ -292,7 +319,7 @@ func (o *ProjectOptions) ToModel(ctx context.Context, dockerCli command.Cli, ser
 		po = append(po, cli.WithResourceLoader(r))
 	}
 
-	options, err := o.toProjectOptions(po...)
+	options, err := o.toProjectOptions(ctx, remotes, po...)
 	if err != nil {
 		return nil, err
 	}
@@ -349,6 +376,10 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, b
 		OCI: api.OCIOptions{
 			InsecureRegistries: o.insecureRegistries,
 		},
+		Git: api.GitOptions{
+			Pin:     o.PinRefs,
+			Refresh: o.Refresh,
+		},
 	}
 
 	project, err := backend.LoadProject(ctx, loadOpts)
@@ -363,14 +394,19 @@ func (o *ProjectOptions) remoteLoaders(dockerCli command.Cli) []loader.ResourceL
 	if o.Offline {
 		return nil
 	}
-	git := remote.NewGitRemoteLoader(dockerCli, o.Offline)
+	git := remote.NewGitRemoteLoader(dockerCli, o.Offline, api.GitOptions{Pin: o.PinRefs, Refresh: o.Refresh})
 	oci := remote.NewOCIRemoteLoader(dockerCli, o.Offline, api.OCIOptions{})
 	return []loader.ResourceLoader{git, oci}
 }
 
-func (o *ProjectOptions) toProjectOptions(po ...cli.ProjectOptionsFn) (*cli.ProjectOptions, error) {
+// toProjectOptions builds compose-go options to load the project, checking out project directory first when remote
+func (o *ProjectOptions) toProjectOptions(ctx context.Context, remotes []loader.ResourceLoader, po ...cli.ProjectOptionsFn) (*cli.ProjectOptions, error) {
+	workingDir, err := remote.ResolveProjectDir(ctx, o.ProjectDir, o.ConfigPaths, remotes)
+	if err != nil {
+		return nil, err
+	}
 	opts := []cli.ProjectOptionsFn{
-		cli.WithWorkingDirectory(o.ProjectDir),
+		cli.WithWorkingDirectory(workingDir),
 		// First apply os.Environment, always win
 		cli.WithOsEnv,
 	}
@@ -398,6 +434,8 @@ func (o *ProjectOptions) toProjectOptions(po ...cli.ProjectOptionsFn) (*cli.Proj
 		// eventually COMPOSE_PROFILES should have been set
 		cli.WithDefaultProfiles(o.Profiles...),
 		cli.WithName(o.ProjectName),
+		compose.WithVariableDefaults,
+		compose.WithInterpolationFunctions,
 	)
 
 	return cli.NewProjectOptions(o.ConfigPaths, append(po, opts...)...)
@@ -439,6 +477,8 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		version  bool
 		parallel int
 		dryRun   bool
+		jsonRPC  bool
+		preset   string
 	)
 	c := &cobra.Command{
 		Short:            "Docker Compose",
@@ -447,11 +487,14 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		TraverseChildren: true,
 		// By default (no Run/RunE in parent c) for typos in subcommands, cobra displays the help of parent c but exit(0) !
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonRPC {
+				return runJSONRPC(cmd.Context(), dockerCli, backendOptions)
+			}
 			if len(args) == 0 {
 				return cmd.Help()
 			}
 			if version {
-				return versionCommand(dockerCli).Execute()
+				return versionCommand(dockerCli, backendOptions).Execute()
 			}
 			_ = cmd.Help()
 			return dockercli.StatusError{
@@ -475,6 +518,10 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 				logrus.SetLevel(logrus.TraceLevel)
 			}
 
+			if err := opts.applyAlias(aliasRegistryPath()); err != nil {
+				return err
+			}
+
 			err := setEnvWithDotEnv(opts)
 			if err != nil {
 				return err
@@ -528,6 +575,13 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 				}
 				display.Mode = display.ModePlain
 				ep = display.Plain(dockerCli.Err())
+			case display.ModeOrdered:
+				if ansi == "always" {
+					return fmt.Errorf("can't use --progress ordered while ANSI support is forced")
+				}
+				// build output is rendered by buildkit, which doesn't support ordered mode
+				display.Mode = display.ModePlain
+				ep = display.Ordered(dockerCli.Err())
 			case display.ModeQuiet, "none":
 				display.Mode = display.ModeQuiet
 				ep = display.Quiet()
@@ -566,6 +620,15 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 				composeCmd = composeCmd.Parent()
 			}
 
+			if v, ok := os.LookupEnv(ComposePreset); ok && !composeCmd.Flags().Changed("preset") {
+				preset = v
+			}
+			if preset != "" && cmd != composeCmd {
+				if err := applyPreset(cmd, composeCmd, settingsDir(opts), preset); err != nil {
+					return err
+				}
+			}
+
 			if v, ok := os.LookupEnv(ComposeParallelLimit); ok && !composeCmd.Flags().Changed("parallel") {
 				i, err := strconv.Atoi(v)
 				if err != nil {
@@ -582,18 +645,24 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 			if dryRun {
 				backendOptions.Add(compose.WithDryRun)
 			}
+
+			if policy, ok := os.LookupEnv(ComposePolicy); ok && policy != "" {
+				backendOptions.Add(compose.WithPolicy(policy))
+			}
 			return nil
 		},
 	}
 
 	c.AddCommand(
 		upCommand(&opts, dockerCli, backendOptions),
+		applyCommand(&opts, dockerCli, backendOptions),
+		resumeCommand(&opts, dockerCli, backendOptions),
 		downCommand(&opts, dockerCli, backendOptions),
 		startCommand(&opts, dockerCli, backendOptions),
 		restartCommand(&opts, dockerCli, backendOptions),
 		stopCommand(&opts, dockerCli, backendOptions),
 		psCommand(&opts, dockerCli, backendOptions),
-		listCommand(dockerCli, backendOptions),
+		listCommand(&opts, dockerCli, backendOptions),
 		logsCommand(&opts, dockerCli, backendOptions),
 		configCommand(&opts, dockerCli),
 		killCommand(&opts, dockerCli, backendOptions),
@@ -609,7 +678,10 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		eventsCommand(&opts, dockerCli, backendOptions),
 		portCommand(&opts, dockerCli, backendOptions),
 		imagesCommand(&opts, dockerCli, backendOptions),
-		versionCommand(dockerCli),
+		versionCommand(dockerCli, backendOptions),
+		exitCodesCommand(dockerCli),
+		apiSchemaCommand(dockerCli),
+		featuresCommand(&opts, dockerCli, backendOptions),
 		buildCommand(&opts, dockerCli, backendOptions),
 		pushCommand(&opts, dockerCli, backendOptions),
 		pullCommand(&opts, dockerCli, backendOptions),
@@ -619,10 +691,25 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		scaleCommand(&opts, dockerCli, backendOptions),
 		statsCommand(&opts, dockerCli),
 		watchCommand(&opts, dockerCli, backendOptions),
+		watchStateCommand(&opts, dockerCli, backendOptions),
+		migrateCommand(&opts, dockerCli, backendOptions),
+		moveCommand(&opts, dockerCli, backendOptions),
+		diskUsageCommand(&opts, dockerCli, backendOptions),
+		projectCommand(&opts, dockerCli, backendOptions),
+		adoptCommand(&opts, dockerCli, backendOptions),
+		deployCommand(&opts, dockerCli, backendOptions),
 		publishCommand(&opts, dockerCli, backendOptions),
 		alphaCommand(&opts, dockerCli, backendOptions),
 		bridgeCommand(&opts, dockerCli),
 		volumesCommand(&opts, dockerCli, backendOptions),
+		certsCommand(&opts, dockerCli, backendOptions),
+		networkCommand(&opts, dockerCli, backendOptions),
+		securityCommand(&opts, dockerCli, backendOptions),
+		doctorCommand(&opts, dockerCli, backendOptions),
+		bugreportCommand(&opts, dockerCli, backendOptions),
+		enableOnBootCommand(&opts, dockerCli, backendOptions),
+		disableOnBootCommand(&opts, dockerCli),
+		serveCommand(dockerCli, backendOptions),
 	)
 
 	c.Flags().SetInterspersed(false)
@@ -631,6 +718,10 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		"project-name",
 		completeProjectNames(dockerCli, backendOptions),
 	)
+	c.RegisterFlagCompletionFunc( //nolint:errcheck
+		"project-alias",
+		completeProjectAliases(),
+	)
 	c.RegisterFlagCompletionFunc( //nolint:errcheck
 		"project-directory",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -654,6 +745,8 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 
 	c.Flags().StringVar(&ansi, "ansi", "auto", `Control when to print ANSI control characters ("never"|"always"|"auto")`)
 	c.Flags().IntVar(&parallel, "parallel", -1, `Control max parallelism, -1 for unlimited`)
+	c.Flags().StringVar(&preset, "preset", "", fmt.Sprintf("Apply a preset of command flags defined by %s", SettingsFileName))
+	c.Flags().BoolVar(&jsonRPC, "json-rpc", false, "Serve the Compose API as line-delimited JSON-RPC over stdin and stdout")
 	c.Flags().BoolVarP(&version, "version", "v", false, "Show the Docker Compose version information")
 	c.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Execute command in dry run mode")
 	c.Flags().MarkHidden("version") //nolint:errcheck
@@ -699,6 +792,7 @@ var printerModes = []string{
 	display.ModeAuto,
 	display.ModeTTY,
 	display.ModePlain,
+	display.ModeOrdered,
 	display.ModeJSON,
 	display.ModeQuiet,
 }
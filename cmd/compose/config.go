@@ -19,18 +19,24 @@ package compose
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/dotenv"
 	"github.com/compose-spec/compose-go/v2/template"
 	"github.com/compose-spec/compose-go/v2/types"
+	composegoutils "github.com/compose-spec/compose-go/v2/utils"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
@@ -58,7 +64,24 @@ type configOptions struct {
 	noConsistency       bool
 	variables           bool
 	environment         bool
+	envResolution       bool
 	lockImageDigests    bool
+	minify              bool
+	sortKeys            bool
+	preserveAnchors     bool
+	fromRuntime         bool
+}
+
+// renderOptions are the options to render the compose model
+func (o *configOptions) renderOptions() api.ConfigOptions {
+	return api.ConfigOptions{
+		Format:              o.Format,
+		Output:              o.Output,
+		ResolveImageDigests: o.resolveImageDigests,
+		Minify:              o.minify,
+		SortKeys:            o.sortKeys,
+		PreserveAnchors:     o.preserveAnchors,
+	}
 }
 
 func (o *configOptions) ToProject(ctx context.Context, dockerCli command.Cli, backend api.Compose, services []string) (*types.Project, error) {
@@ -108,9 +131,15 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 			if opts.lockImageDigests {
 				opts.resolveImageDigests = true
 			}
+			if opts.preserveAnchors && (opts.minify || opts.sortKeys || opts.resolveImageDigests) {
+				return errors.New("--preserve-anchors can't be combined with --minify, --sort-keys or image digests resolution")
+			}
 			return nil
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
+			if opts.fromRuntime {
+				return runConfigFromRuntime(ctx, dockerCli, opts)
+			}
 			if opts.services {
 				return runServices(ctx, dockerCli, opts)
 			}
@@ -138,6 +167,9 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 			if opts.environment {
 				return runEnvironment(ctx, dockerCli, opts, args)
 			}
+			if opts.envResolution {
+				return runEnvResolution(ctx, dockerCli, opts)
+			}
 
 			if opts.Format == "" {
 				opts.Format = "yaml"
@@ -156,6 +188,10 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.noResolvePath, "no-path-resolution", false, "Don't resolve file paths")
 	flags.BoolVar(&opts.noConsistency, "no-consistency", false, "Don't check model consistency - warning: may produce invalid Compose output")
 	flags.BoolVar(&opts.noResolveEnv, "no-env-resolution", false, "Don't resolve service env files")
+	flags.BoolVar(&opts.minify, "minify", false, "Drop null and empty values from the output")
+	flags.BoolVar(&opts.sortKeys, "sort-keys", false, "Sort mapping keys, for a stable output to be diffed")
+	flags.BoolVar(&opts.preserveAnchors, "preserve-anchors", false, "Validate and print the compose file as is, preserving YAML anchors and extensions")
+	flags.BoolVar(&opts.fromRuntime, "from-runtime", false, "Reconstruct a best-effort compose file from the project resources, when the original one is lost")
 
 	flags.BoolVar(&opts.services, "services", false, "Print the service names, one per line.")
 	flags.BoolVar(&opts.volumes, "volumes", false, "Print the volume names, one per line.")
@@ -165,7 +201,8 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.images, "images", false, "Print the image names, one per line.")
 	flags.StringVar(&opts.hash, "hash", "", "Print the service config hash, one per line.")
 	flags.BoolVar(&opts.variables, "variables", false, "Print model variables and default values.")
-	flags.BoolVar(&opts.environment, "environment", false, "Print environment used for interpolation.")
+	flags.BoolVar(&opts.environment, "environment", false, "Print environment used for interpolation, or the resolved containers environment of the selected services.")
+	flags.BoolVar(&opts.envResolution, "env-resolution", false, "Print variables set by env files, with the file setting each value.")
 	flags.StringVarP(&opts.Output, "output", "o", "", "Save to file (default to stdout)")
 
 	return cmd
@@ -173,7 +210,12 @@ func configCommand(p *ProjectOptions, dockerCli command.Cli) *cobra.Command {
 
 func runConfig(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) (err error) {
 	var content []byte
-	if opts.noInterpolate {
+	if opts.preserveAnchors {
+		content, err = runConfigPreserveAnchors(ctx, dockerCli, opts, services)
+		if err != nil {
+			return err
+		}
+	} else if opts.noInterpolate {
 		content, err = runConfigNoInterpolate(ctx, dockerCli, opts, services)
 		if err != nil {
 			return err
@@ -185,7 +227,7 @@ func runConfig(ctx context.Context, dockerCli command.Cli, opts configOptions, s
 		}
 	}
 
-	if !opts.noInterpolate {
+	if !opts.noInterpolate && !opts.preserveAnchors {
 		content = escapeDollarSign(content)
 	}
 
@@ -212,9 +254,14 @@ func runConfigInterpolate(ctx context.Context, dockerCli command.Cli, opts confi
 	}
 
 	if opts.resolveImageDigests {
-		project, err = project.WithImagesResolved(compose.ImageDigestResolver(ctx, dockerCli.ConfigFile(), dockerCli.Client()))
-		if err != nil {
+		resolved, err := project.WithImagesResolved(compose.ImageDigestResolver(ctx, dockerCli.ConfigFile(), dockerCli.Client()))
+		switch {
+		case err != nil && opts.Offline && isEngineUnreachable(err):
+			_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING: Docker engine is unreachable, image digests are not resolved")
+		case err != nil:
 			return nil, err
+		default:
+			project = resolved
 		}
 	}
 
@@ -236,19 +283,7 @@ func runConfigInterpolate(ctx context.Context, dockerCli command.Cli, opts confi
 		project = imagesOnly(project)
 	}
 
-	var content []byte
-	switch opts.Format {
-	case "json":
-		content, err = project.MarshalJSON()
-	case "yaml":
-		content, err = project.MarshalYAML()
-	default:
-		return nil, fmt.Errorf("unsupported format %q", opts.Format)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return content, nil
+	return compose.MarshalProject(project, opts.renderOptions())
 }
 
 // imagesOnly return project with all attributes removed but service.images
@@ -294,7 +329,30 @@ func runConfigNoInterpolate(ctx context.Context, dockerCli command.Cli, opts con
 		}
 	}
 
-	return formatModel(model, opts.Format)
+	return compose.MarshalModel(model, opts.renderOptions())
+}
+
+// runConfigPreserveAnchors validates the project, then returns the source compose file unchanged, as anchors,
+// aliases and comments are lost once the model has been loaded
+func runConfigPreserveAnchors(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) ([]byte, error) {
+	if opts.Format != "yaml" {
+		return nil, errors.New("--preserve-anchors only supports yaml format")
+	}
+	backend, err := compose.NewComposeService(dockerCli)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := opts.ToProject(ctx, dockerCli, backend, services); err != nil {
+		return nil, err
+	}
+	projectOptions, err := opts.toProjectOptions(ctx, opts.remoteLoaders(dockerCli))
+	if err != nil {
+		return nil, err
+	}
+	if len(projectOptions.ConfigPaths) != 1 || projectOptions.ConfigPaths[0] == "-" {
+		return nil, errors.New("--preserve-anchors requires a single compose file")
+	}
+	return os.ReadFile(projectOptions.ConfigPaths[0])
 }
 
 func resolveImageDigests(ctx context.Context, dockerCli command.Cli, model map[string]any) (err error) {
@@ -330,21 +388,6 @@ func resolveImageDigests(ctx context.Context, dockerCli command.Cli, model map[s
 	return nil
 }
 
-func formatModel(model map[string]any, format string) (content []byte, err error) {
-	switch format {
-	case "json":
-		return json.MarshalIndent(model, "", "  ")
-	case "yaml":
-		buf := bytes.NewBuffer([]byte{})
-		encoder := yaml.NewEncoder(buf)
-		encoder.SetIndent(2)
-		err = encoder.Encode(model)
-		return buf.Bytes(), err
-	default:
-		return nil, fmt.Errorf("unsupported format %q", format)
-	}
-}
-
 func runServices(ctx context.Context, dockerCli command.Cli, opts configOptions) error {
 	if opts.noInterpolate {
 		// we can't use ToProject, so the model we render here is only partially resolved
@@ -518,6 +561,13 @@ func runConfigImages(ctx context.Context, dockerCli command.Cli, opts configOpti
 	return nil
 }
 
+// variableDoc documents a variable used by the model, and its declaration by compose.VariablesExtension if any
+type variableDoc struct {
+	template.Variable `yaml:",inline"`
+	Type              string `json:",omitempty" yaml:",omitempty"`
+	Description       string `json:",omitempty" yaml:",omitempty"`
+}
+
 func runVariables(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
 	opts.noInterpolate = true
 	model, err := opts.ToModel(ctx, dockerCli, services, cli.WithoutEnvironmentResolution)
@@ -525,7 +575,7 @@ func runVariables(ctx context.Context, dockerCli command.Cli, opts configOptions
 		return err
 	}
 
-	variables := template.ExtractVariables(model, template.DefaultPattern)
+	variables := documentVariables(model)
 
 	if opts.Format == "yaml" {
 		result, err := yaml.Marshal(variables)
@@ -537,10 +587,45 @@ func runVariables(ctx context.Context, dockerCli command.Cli, opts configOptions
 	}
 
 	return formatter.Print(variables, opts.Format, dockerCli.Out(), func(w io.Writer) {
-		for name, variable := range variables {
-			_, _ = fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", name, variable.Required, variable.DefaultValue, variable.PresenceValue)
+		for _, name := range slices.Sorted(maps.Keys(variables)) {
+			variable := variables[name]
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", name, variable.Type, variable.Required, variable.DefaultValue, variable.PresenceValue, variable.Description)
 		}
-	}, "NAME", "REQUIRED", "DEFAULT VALUE", "ALTERNATE VALUE")
+	}, "NAME", "TYPE", "REQUIRED", "DEFAULT VALUE", "ALTERNATE VALUE", "DESCRIPTION")
+}
+
+// documentVariables merges variables used by model with the ones declared by compose.VariablesExtension
+func documentVariables(model map[string]any) map[string]variableDoc {
+	variables := map[string]variableDoc{}
+	for name, v := range template.ExtractVariables(model, template.DefaultPattern) {
+		variables[name] = variableDoc{Variable: v}
+	}
+	declarations, err := compose.GetVariableDeclarations(model)
+	if err != nil {
+		// invalid declarations are reported when project is loaded
+		logrus.Warn(err)
+		return variables
+	}
+	for name, d := range declarations {
+		doc, ok := variables[name]
+		if !ok {
+			doc.Name = name
+		}
+		doc.Type = d.Type
+		if doc.Type == "" {
+			doc.Type = compose.VariableTypeString
+		}
+		if d.Type == compose.VariableTypeEnum {
+			doc.Type = fmt.Sprintf("enum(%s)", strings.Join(d.Values, "|"))
+		}
+		doc.Description = d.Description
+		doc.Required = doc.Required || (d.Required && d.Default == nil)
+		if doc.DefaultValue == "" {
+			doc.DefaultValue = d.DefaultValue()
+		}
+		variables[name] = doc
+	}
+	return variables
 }
 
 func runEnvironment(ctx context.Context, dockerCli command.Cli, opts configOptions, services []string) error {
@@ -549,6 +634,10 @@ func runEnvironment(ctx context.Context, dockerCli command.Cli, opts configOptio
 		return err
 	}
 
+	if len(services) > 0 {
+		return runServicesEnvironment(ctx, dockerCli, backend, opts, services)
+	}
+
 	project, err := opts.ToProject(ctx, dockerCli, backend, services)
 	if err != nil {
 		return err
@@ -560,8 +649,220 @@ func runEnvironment(ctx context.Context, dockerCli command.Cli, opts configOptio
 	return nil
 }
 
+// Sources of the variables set in a service container environment
+const (
+	envSourceEnvironment = "environment"
+	envSourceProject     = "environment, from shell or .env"
+	envSourceUnset       = "environment, unset in shell and .env"
+	envSourceEnvFile     = "env_file "
+	envSourceProxy       = "proxy configuration"
+)
+
+// serviceVariable is a variable of a service container environment, with the source setting its value
+type serviceVariable struct {
+	Service string  `json:"service"`
+	Name    string  `json:"name"`
+	Value   *string `json:"value"`
+	Source  string  `json:"source"`
+}
+
+// runServicesEnvironment prints the environment services containers would receive, with values which could be
+// secrets redacted
+func runServicesEnvironment(ctx context.Context, dockerCli command.Cli, backend api.Compose, opts configOptions, services []string) error {
+	project, _, err := opts.ProjectOptions.ToProject(ctx, dockerCli, backend, services, cli.WithoutEnvironmentResolution)
+	if err != nil {
+		return err
+	}
+	proxy := types.MappingWithEquals(dockerCli.ConfigFile().ParseProxyConfig(dockerCli.Client().DaemonHost(), nil))
+
+	var variables []serviceVariable
+	for _, name := range services {
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		resolved, err := resolveServiceEnvironment(project, service, proxy)
+		if err != nil {
+			return err
+		}
+		variables = append(variables, resolved...)
+	}
+	for i, v := range variables {
+		if v.Value != nil && *v.Value != "" && compose.IsSensitiveName(v.Name) {
+			variables[i].Value = redactedValue()
+		}
+	}
+
+	format := opts.Format
+	if format == "" || format == "yaml" {
+		format = formatter.TABLE
+	}
+	return formatter.Print(variables, format, dockerCli.Out(), func(w io.Writer) {
+		for _, v := range variables {
+			value := "<unset>"
+			if v.Value != nil {
+				value = *v.Value
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Service, v.Name, value, v.Source)
+		}
+	}, "SERVICE", "VARIABLE", "VALUE", "SOURCE")
+}
+
+// resolveServiceEnvironment resolves the environment of a service container, applying the same precedence as
+// the loader: `environment` overrides `env_file` in declaration order, which overrides proxy configuration.
+// project must be loaded without environment resolution, so sources can be identified.
+func resolveServiceEnvironment(project *types.Project, service types.ServiceConfig, proxy types.MappingWithEquals) ([]serviceVariable, error) {
+	variables := map[string]serviceVariable{}
+	set := func(name string, value *string, source string) {
+		variables[name] = serviceVariable{Service: service.Name, Name: name, Value: value, Source: source}
+	}
+	for name, value := range proxy {
+		set(name, value, envSourceProxy)
+	}
+	for _, envFile := range service.EnvFiles {
+		// resolve env files one by one, so the one setting a variable is known
+		single := types.Project{
+			Environment: project.Environment,
+			Services: types.Services{
+				service.Name: {Name: service.Name, Environment: service.Environment, EnvFiles: []types.EnvFile{envFile}},
+			},
+		}
+		resolved, err := single.WithServicesEnvironmentResolved(true)
+		if err != nil {
+			return nil, err
+		}
+		path := relativePath(project.WorkingDir, envFile.Path)
+		for name, value := range resolved.Services[service.Name].Environment {
+			if _, declared := service.Environment[name]; declared {
+				continue
+			}
+			set(name, value, envSourceEnvFile+path)
+		}
+	}
+	for name, value := range service.Environment {
+		// loader already resolved variables declared without a value, so those can only be identified as
+		// matching the project environment, which also covers `NAME: ${NAME}`
+		v, inProject := project.Environment[name]
+		switch {
+		case value == nil:
+			set(name, nil, envSourceUnset)
+		case inProject && v == *value:
+			set(name, value, envSourceProject)
+		default:
+			set(name, value, envSourceEnvironment)
+		}
+	}
+
+	result := make([]serviceVariable, 0, len(variables))
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		result = append(result, variables[name])
+	}
+	return result, nil
+}
+
 func escapeDollarSign(marshal []byte) []byte {
 	dollar := []byte{'$'}
 	escDollar := []byte{'$', '$'}
 	return bytes.ReplaceAll(marshal, dollar, escDollar)
 }
+
+// relativePath returns path relative to dir when inside it, for a more readable output
+func relativePath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// envSourceShell is set as source of a variable set in the shell environment, which has precedence over env files
+const envSourceShell = "shell"
+
+// envFileVariable is a variable set by an env file, with the file setting its value and the ones it overrides
+type envFileVariable struct {
+	Name       string   `json:"name"`
+	Value      string   `json:"value"`
+	Source     string   `json:"source"`
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+func runEnvResolution(ctx context.Context, dockerCli command.Cli, opts configOptions) error {
+	projectOptions, err := opts.toProjectOptions(ctx, opts.remoteLoaders(dockerCli))
+	if err != nil {
+		return err
+	}
+	variables, err := resolveEnvFiles(composegoutils.GetAsEqualsMap(os.Environ()), projectOptions.EnvFiles)
+	if err != nil {
+		return err
+	}
+	workingDir, err := projectOptions.GetWorkingDir()
+	if err != nil {
+		return err
+	}
+	for i, v := range variables {
+		variables[i].Source = relativePath(workingDir, v.Source)
+		for j, file := range v.Overridden {
+			variables[i].Overridden[j] = relativePath(workingDir, file)
+		}
+		if v.Value != "" && compose.IsSensitiveName(v.Name) {
+			variables[i].Value = *redactedValue()
+		}
+	}
+
+	format := opts.Format
+	if format == "" || format == "yaml" {
+		format = formatter.TABLE
+	}
+	return formatter.Print(variables, format, dockerCli.Out(), func(w io.Writer) {
+		for _, v := range variables {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Value, v.Source, strings.Join(v.Overridden, ", "))
+		}
+	}, "VARIABLE", "VALUE", "SOURCE", "OVERRIDDEN")
+}
+
+// resolveEnvFiles applies env files in order as the project loader does: a file overrides variables set by
+// the previous ones, and can reference them, while the shell environment has precedence over all of them.
+func resolveEnvFiles(environment map[string]string, files []string) ([]envFileVariable, error) {
+	// use the loader's own parsing, so references resolve against the shell environment then all values parsed
+	// so far, including the ones set earlier by the same file
+	values, err := dotenv.GetEnvFromFile(environment, files)
+	if err != nil {
+		return nil, err
+	}
+	definedBy := map[string][]string{}
+	for _, file := range files {
+		// only the variables file defines matter here, values have been resolved above
+		env, err := dotenv.ReadFile(file, func(name string) (string, bool) {
+			if v, ok := environment[name]; ok {
+				return v, true
+			}
+			v, ok := values[name]
+			return v, ok
+		})
+		if err != nil {
+			return nil, err
+		}
+		for name := range env {
+			definedBy[name] = append(definedBy[name], file)
+		}
+	}
+
+	result := make([]envFileVariable, 0, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		files := definedBy[name]
+		variable := envFileVariable{
+			Name:   name,
+			Value:  values[name],
+			Source: files[len(files)-1],
+		}
+		if len(files) > 1 {
+			variable.Overridden = files[:len(files)-1]
+		}
+		if v, ok := environment[name]; ok {
+			variable.Value = v
+			variable.Source = envSourceShell
+			variable.Overridden = files
+		}
+		result = append(result, variable)
+	}
+	return result, nil
+}
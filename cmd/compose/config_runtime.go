@@ -0,0 +1,244 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+// runtimeUnrecoverable lists attributes of a compose file which leave no trace at runtime
+var runtimeUnrecoverable = []string{
+	"env_file, secrets and configs are resolved at creation, their content may appear as environment or mounts",
+	"profiles, extends, include, x-* extensions and YAML anchors are not recorded",
+	"services without any container, and external volumes and networks, are not listed",
+}
+
+// runConfigFromRuntime renders a best-effort compose file reconstructed from project resources, when the
+// original one is lost. Attributes which could not be recovered are flagged in comments.
+func runConfigFromRuntime(ctx context.Context, dockerCli command.Cli, opts configOptions) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return fmt.Errorf("project name can't be guessed, set it with --project-name: %w", err)
+	}
+	backend, err := compose.NewComposeService(dockerCli)
+	if err != nil {
+		return err
+	}
+	project, err := backend.Describe(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	containers, err := backend.Ps(ctx, projectName, api.PsOptions{All: true})
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(containers, func(a, b api.ContainerSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	unrecovered := slices.Clone(runtimeUnrecoverable)
+	for _, name := range project.ServiceNames() {
+		i := slices.IndexFunc(containers, func(c api.ContainerSummary) bool {
+			return c.Service == name
+		})
+		if i < 0 {
+			continue
+		}
+		ctr, err := dockerCli.Client().ContainerInspect(ctx, containers[i].ID)
+		if err != nil {
+			return err
+		}
+		img, err := dockerCli.Client().ImageInspect(ctx, ctr.Image)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+		service, flagged := recoverService(project.Services[name], project.Name, ctr, img)
+		project.Services[name] = service
+		unrecovered = append(unrecovered, flagged...)
+	}
+	recoverResources(project)
+
+	if opts.Format == "" {
+		opts.Format = "yaml"
+	}
+	content, err := compose.MarshalProject(project, opts.renderOptions())
+	if err != nil {
+		return err
+	}
+	if opts.Format == "yaml" {
+		content = append([]byte(runtimeHeader(project.Name, unrecovered)), content...)
+	} else {
+		for _, u := range unrecovered {
+			logrus.Warnf("not recovered: %s", u)
+		}
+	}
+
+	if opts.Output != "" {
+		return os.WriteFile(opts.Output, content, 0o666)
+	}
+	_, err = fmt.Fprint(dockerCli.Out(), string(content))
+	return err
+}
+
+func runtimeHeader(projectName string, unrecovered []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Reconstructed from runtime state of project %q, review before use.\n", projectName)
+	sb.WriteString("# The following could not be recovered:\n")
+	for _, u := range unrecovered {
+		fmt.Fprintf(&sb, "#   - %s\n", u)
+	}
+	return sb.String()
+}
+
+// recoverService completes a service described by labels with container configuration, only keeping values
+// which differ from image defaults. Returns attributes which could not be recovered.
+func recoverService(service types.ServiceConfig, projectName string, ctr container.InspectResponse, img image.InspectResponse) (types.ServiceConfig, []string) {
+	var (
+		flagged  []string
+		imgLabel map[string]string
+		imgCfg   container.Config
+	)
+	if img.Config != nil {
+		imgLabel = img.Config.Labels
+		imgCfg = container.Config{
+			Cmd:        img.Config.Cmd,
+			Entrypoint: img.Config.Entrypoint,
+			Env:        img.Config.Env,
+			User:       img.Config.User,
+			WorkingDir: img.Config.WorkingDir,
+		}
+	}
+
+	service.Labels = runtimeLabels(service.Labels, imgLabel)
+	if service.Scale != nil && *service.Scale == 1 {
+		service.Scale = nil
+	}
+	if service.Image == api.GetImageNameOrDefault(types.ServiceConfig{Name: service.Name}, projectName) {
+		flagged = append(flagged, fmt.Sprintf("services.%s.build: image was built by compose, build configuration is unknown", service.Name))
+	}
+
+	name := strings.TrimPrefix(ctr.Name, "/")
+	if !strings.HasPrefix(name, projectName+api.Separator+service.Name+api.Separator) {
+		service.ContainerName = name
+	}
+
+	if ctr.Config != nil {
+		if !slices.Equal(ctr.Config.Cmd, imgCfg.Cmd) {
+			service.Command = types.ShellCommand(ctr.Config.Cmd)
+		}
+		if !slices.Equal(ctr.Config.Entrypoint, imgCfg.Entrypoint) {
+			service.Entrypoint = types.ShellCommand(ctr.Config.Entrypoint)
+		}
+		if ctr.Config.User != imgCfg.User {
+			service.User = ctr.Config.User
+		}
+		if ctr.Config.WorkingDir != imgCfg.WorkingDir {
+			service.WorkingDir = ctr.Config.WorkingDir
+		}
+		for _, e := range ctr.Config.Env {
+			if slices.Contains(imgCfg.Env, e) {
+				continue
+			}
+			k, v, _ := strings.Cut(e, "=")
+			if service.Environment == nil {
+				service.Environment = types.MappingWithEquals{}
+			}
+			if compose.IsSensitiveName(k) {
+				// value is not written, so it gets resolved from shell environment or .env file
+				service.Environment[k] = nil
+				flagged = append(flagged, fmt.Sprintf("services.%s.environment.%s: value looks like a secret and was not written", service.Name, k))
+				continue
+			}
+			service.Environment[k] = &v
+		}
+		if ctr.Config.Healthcheck != nil && len(ctr.Config.Healthcheck.Test) > 0 {
+			flagged = append(flagged, fmt.Sprintf("services.%s.healthcheck: check if it's declared by image or compose file", service.Name))
+		}
+	}
+
+	if hc := ctr.HostConfig; hc != nil {
+		if hc.RestartPolicy.Name != "" && hc.RestartPolicy.Name != container.RestartPolicyDisabled {
+			service.Restart = string(hc.RestartPolicy.Name)
+			if hc.RestartPolicy.Name == container.RestartPolicyOnFailure && hc.RestartPolicy.MaximumRetryCount > 0 {
+				service.Restart += ":" + strconv.Itoa(hc.RestartPolicy.MaximumRetryCount)
+			}
+		}
+		service.Privileged = hc.Privileged
+		service.ReadOnly = hc.ReadonlyRootfs
+		for _, port := range slices.Sorted(maps.Keys(hc.PortBindings)) {
+			for _, binding := range hc.PortBindings[port] {
+				service.Ports = append(service.Ports, types.ServicePortConfig{
+					Mode:      "ingress",
+					HostIP:    binding.HostIP,
+					Target:    uint32(port.Int()),
+					Published: binding.HostPort,
+					Protocol:  port.Proto(),
+				})
+			}
+		}
+	}
+	return service, flagged
+}
+
+// recoverResources drops labels set by compose on volumes and networks, and names compose would set by default
+func recoverResources(project *types.Project) {
+	for key, vol := range project.Volumes {
+		vol.Labels = runtimeLabels(vol.Labels, nil)
+		if vol.Name == project.Name+"_"+key {
+			vol.Name = ""
+		}
+		project.Volumes[key] = vol
+	}
+	for key, nw := range project.Networks {
+		nw.Labels = runtimeLabels(nw.Labels, nil)
+		if nw.Name == project.Name+"_"+key {
+			nw.Name = ""
+		}
+		project.Networks[key] = nw
+	}
+}
+
+// runtimeLabels removes labels set by compose, or inherited from image
+func runtimeLabels(labels types.Labels, imageLabels map[string]string) types.Labels {
+	var recovered types.Labels
+	for k, v := range labels {
+		if strings.HasPrefix(k, "com.docker.compose.") {
+			continue
+		}
+		if iv, ok := imageLabels[k]; ok && iv == v {
+			continue
+		}
+		recovered = recovered.Add(k, v)
+	}
+	return recovered
+}
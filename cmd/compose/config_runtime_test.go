@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/go-connections/nat"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestRecoverService(t *testing.T) {
+	one := 1
+	service := types.ServiceConfig{
+		Name:  "web",
+		Image: "myproject-web",
+		Scale: &one,
+		Labels: types.Labels{
+			api.ProjectLabel:    "myproject",
+			"maintainer":        "image",
+			"traefik.enable":    "true",
+			api.ConfigHashLabel: "abc",
+		},
+	}
+	ctr := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			Name: "/myproject-web-1",
+			HostConfig: &container.HostConfig{
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyUnlessStopped},
+				PortBindings: nat.PortMap{
+					"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}},
+				},
+			},
+		},
+		Config: &container.Config{
+			Cmd: []string{"nginx", "-g", "daemon off;"},
+			Env: []string{"PATH=/usr/bin", "MODE=prod", "DB_PASSWORD=s3cr3t"},
+		},
+	}
+	img := image.InspectResponse{
+		Config: &dockerspec.DockerOCIImageConfig{
+			ImageConfig: ocispec.ImageConfig{
+				Cmd:    []string{"nginx", "-g", "daemon off;"},
+				Env:    []string{"PATH=/usr/bin"},
+				Labels: map[string]string{"maintainer": "image"},
+			},
+		},
+	}
+
+	recovered, flagged := recoverService(service, "myproject", ctr, img)
+	mode := "prod"
+	assert.DeepEqual(t, recovered, types.ServiceConfig{
+		Name:        "web",
+		Image:       "myproject-web",
+		Labels:      types.Labels{"traefik.enable": "true"},
+		Restart:     "unless-stopped",
+		Environment: types.MappingWithEquals{"MODE": &mode, "DB_PASSWORD": nil},
+		Ports: []types.ServicePortConfig{
+			{Mode: "ingress", HostIP: "127.0.0.1", Target: 80, Published: "8080", Protocol: "tcp"},
+		},
+	})
+	assert.DeepEqual(t, flagged, []string{
+		"services.web.build: image was built by compose, build configuration is unknown",
+		"services.web.environment.DB_PASSWORD: value looks like a secret and was not written",
+	})
+}
+
+func TestRecoverResources(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Volumes: types.Volumes{
+			"data":   {Name: "myproject_data", Labels: types.Labels{api.VolumeLabel: "data", "backup": "daily"}},
+			"shared": {Name: "shared", Labels: types.Labels{api.VolumeLabel: "shared"}},
+		},
+		Networks: types.Networks{
+			"default": {Name: "myproject_default", Driver: "bridge", Labels: types.Labels{api.NetworkLabel: "default"}},
+		},
+	}
+	recoverResources(project)
+	assert.DeepEqual(t, project.Volumes, types.Volumes{
+		"data":   {Labels: types.Labels{"backup": "daily"}},
+		"shared": {Name: "shared"},
+	})
+	assert.DeepEqual(t, project.Networks, types.Networks{
+		"default": {Driver: "bridge"},
+	})
+}
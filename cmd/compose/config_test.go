@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveServiceEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.env")
+	second := filepath.Join(dir, "second.env")
+	assert.NilError(t, os.WriteFile(first, []byte("A=1\nSHARED=first\nFOO=file\n"), 0o600))
+	assert.NilError(t, os.WriteFile(second, []byte("SHARED=second\n"), 0o600))
+
+	project := &types.Project{
+		WorkingDir:  dir,
+		Environment: types.Mapping{"SHELL_VAR": "shell"},
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				EnvFiles: []types.EnvFile{
+					{Path: first, Required: true},
+					{Path: second, Required: true},
+				},
+				Environment: types.NewMappingWithEquals([]string{"FOO=env", "SHELL_VAR=shell", "MISSING", "HTTP_PROXY=direct"}),
+			},
+		},
+	}
+	value := func(v string) *string { return &v }
+	proxy := types.MappingWithEquals{"HTTP_PROXY": value("proxy"), "NO_PROXY": value("localhost")}
+
+	variables, err := resolveServiceEnvironment(project, project.Services["app"], proxy)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []serviceVariable{
+		{Service: "app", Name: "A", Value: value("1"), Source: envSourceEnvFile + "first.env"},
+		{Service: "app", Name: "FOO", Value: value("env"), Source: envSourceEnvironment},
+		{Service: "app", Name: "HTTP_PROXY", Value: value("direct"), Source: envSourceEnvironment},
+		{Service: "app", Name: "MISSING", Source: envSourceUnset},
+		{Service: "app", Name: "NO_PROXY", Value: value("localhost"), Source: envSourceProxy},
+		{Service: "app", Name: "SHARED", Value: value("second"), Source: envSourceEnvFile + "second.env"},
+		{Service: "app", Name: "SHELL_VAR", Value: value("shell"), Source: envSourceProject},
+	})
+}
+
+func TestResolveEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.env")
+	local := filepath.Join(dir, "local.env")
+	assert.NilError(t, os.WriteFile(base, []byte("A=base\nB=base\nURL=http://${A}\n"), 0o600))
+	assert.NilError(t, os.WriteFile(local, []byte("A=local\nC=${B}-local\n"), 0o600))
+
+	variables, err := resolveEnvFiles(map[string]string{"B": "shell"}, []string{base, local})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []envFileVariable{
+		{Name: "A", Value: "local", Source: local, Overridden: []string{base}},
+		{Name: "B", Value: "shell", Source: envSourceShell, Overridden: []string{base}},
+		{Name: "C", Value: "shell-local", Source: local},
+		{Name: "URL", Value: "http://base", Source: base},
+	})
+}
+
+func TestResolveEnvFilesReferenceSameFile(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.env")
+	dotEnv := filepath.Join(dir, ".env")
+	assert.NilError(t, os.WriteFile(local, []byte("A=2\n"), 0o600))
+	assert.NilError(t, os.WriteFile(dotEnv, []byte("A=1\nB=${A}x\n"), 0o600))
+
+	// B references A as set by the same file, which overrides the previous one
+	variables, err := resolveEnvFiles(map[string]string{}, []string{local, dotEnv})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []envFileVariable{
+		{Name: "A", Value: "1", Source: dotEnv, Overridden: []string{local}},
+		{Name: "B", Value: "1x", Source: dotEnv},
+	})
+}
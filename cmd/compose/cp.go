@@ -67,6 +67,7 @@ func copyCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 
 	flags := copyCmd.Flags()
 	flags.IntVar(&opts.index, "index", 0, "Index of the container if service has multiple replicas")
+	copyCmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	flags.BoolVar(&opts.all, "all", false, "Include containers created by the run command")
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/platforms"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/pkg/compose"
 	"github.com/sirupsen/logrus"
@@ -35,21 +36,26 @@ import (
 )
 
 type createOptions struct {
-	Build         bool
-	noBuild       bool
-	Pull          string
-	pullChanged   bool
-	removeOrphans bool
-	ignoreOrphans bool
-	forceRecreate bool
-	noRecreate    bool
-	recreateDeps  bool
-	noInherit     bool
-	timeChanged   bool
-	timeout       int
-	quietPull     bool
-	scale         []string
-	AssumeYes     bool
+	Build            bool
+	noBuild          bool
+	Pull             string
+	pullChanged      bool
+	removeOrphans    bool
+	ignoreOrphans    bool
+	forceRecreate    bool
+	noRecreate       bool
+	recreateDeps     bool
+	noRecreateFor    []string
+	recreatePolicies []string
+	noInherit        bool
+	preserveAnon     bool
+	timeChanged      bool
+	timeout          int
+	quietPull        bool
+	scale            []string
+	AssumeYes        bool
+	noBindChecks     bool
+	platform         string
 }
 
 func createCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -82,9 +88,13 @@ func createCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	flags.BoolVar(&opts.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&opts.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed")
 	flags.BoolVar(&opts.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.StringArrayVar(&opts.noRecreateFor, "no-recreate-for", []string{}, "If SERVICE containers already exist, don't recreate them")
+	flags.StringArrayVar(&opts.recreatePolicies, "recreate-policy", []string{}, `Set recreate policy for a service as SERVICE=POLICY ("force"|"never"|"if-changed")`)
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
 	flags.StringArrayVar(&opts.scale, "scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
 	flags.BoolVarP(&opts.AssumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
+	flags.BoolVar(&opts.noBindChecks, "no-bind-checks", false, "Don't validate bind mounts sources before creating containers")
+	flags.StringVar(&opts.platform, "platform", "", "Override services platform, used to both build and run images (e.g. linux/amd64)")
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		// assumeYes was introduced by mistake as `--y`
 		if name == "y" {
@@ -114,6 +124,11 @@ func runCreate(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		backendOptions.Options = append(backendOptions.Options, compose.WithPrompt(compose.AlwaysOkPrompt()))
 	}
 
+	recreateServices, err := createOpts.recreateServices(project)
+	if err != nil {
+		return err
+	}
+
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
@@ -125,9 +140,11 @@ func runCreate(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		IgnoreOrphans:        createOpts.ignoreOrphans,
 		Recreate:             createOpts.recreateStrategy(),
 		RecreateDependencies: createOpts.dependenciesRecreateStrategy(),
+		RecreateServices:     recreateServices,
 		Inherit:              !createOpts.noInherit,
 		Timeout:              createOpts.GetTimeout(),
 		QuietPull:            createOpts.quietPull,
+		SkipBindChecks:       createOpts.noBindChecks,
 	})
 }
 
@@ -154,6 +171,52 @@ func (opts createOptions) dependenciesRecreateStrategy() string {
 	return api.RecreateDiverged
 }
 
+// recreatePolicyStrategies maps user facing recreate policies to api strategies
+var recreatePolicyStrategies = map[string]string{
+	"force":      api.RecreateForce,
+	"never":      api.RecreateNever,
+	"if-changed": api.RecreateDiverged,
+}
+
+// recreateServices returns the per-service recreate strategies set by --no-recreate-for and --recreate-policy
+func (opts createOptions) recreateServices(project *types.Project) (map[string]string, error) {
+	strategies := map[string]string{}
+	set := func(service, strategy string) error {
+		_, enabled := project.Services[service]
+		_, disabled := project.DisabledServices[service]
+		if !enabled && !disabled {
+			return fmt.Errorf("no such service: %s", service)
+		}
+		if s, ok := strategies[service]; ok && s != strategy {
+			return fmt.Errorf("conflicting recreate policies for service %q", service)
+		}
+		strategies[service] = strategy
+		return nil
+	}
+	for _, service := range opts.noRecreateFor {
+		if err := set(service, api.RecreateNever); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range opts.recreatePolicies {
+		service, policy, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recreate policy %q, expected SERVICE=POLICY", p)
+		}
+		strategy, ok := recreatePolicyStrategies[policy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported recreate policy %q for service %q, expected one of force, never or if-changed", policy, service)
+		}
+		if err := set(service, strategy); err != nil {
+			return nil, err
+		}
+	}
+	if len(strategies) == 0 {
+		return nil, nil
+	}
+	return strategies, nil
+}
+
 func (opts createOptions) GetTimeout() *time.Duration {
 	if opts.timeChanged {
 		t := time.Duration(opts.timeout) * time.Second
@@ -185,6 +248,16 @@ func (opts createOptions) Apply(project *types.Project) error {
 		}
 	}
 
+	if opts.platform != "" {
+		if _, err := platforms.Parse(opts.platform); err != nil {
+			return fmt.Errorf("invalid --platform %q: %w", opts.platform, err)
+		}
+		for i, service := range project.Services {
+			service.Platform = opts.platform
+			project.Services[i] = service
+		}
+	}
+
 	if err := applyPlatforms(project, true); err != nil {
 		return err
 	}
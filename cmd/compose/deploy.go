@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type deployOptions struct {
+	*ProjectOptions
+	swarm        bool
+	prune        bool
+	resolveImage string
+	wait         bool
+	waitTimeout  int
+}
+
+func deployCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := deployOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "deploy [OPTIONS]",
+		Short: "Deploy the project to an orchestrator",
+		Long: `Deploy the project to an orchestrator.
+
+With --swarm, the project is deployed as a Swarm stack: networks, configs, secrets and services
+are created or updated on the Swarm cluster the Docker engine is a manager of. The stack can also be
+managed with "docker stack" commands. Service images must be available from a registry.`,
+		Args: cli.NoArgs,
+		PreRunE: Adapt(func(ctx context.Context, args []string) error {
+			if !opts.swarm {
+				return errors.New("deploy requires a target orchestrator, use --swarm")
+			}
+			switch opts.resolveImage {
+			case api.ResolveImageAlways, api.ResolveImageChanged, api.ResolveImageNever:
+				return nil
+			default:
+				return fmt.Errorf("invalid --resolve-image value %q, must be one of %s, %s or %s", opts.resolveImage,
+					api.ResolveImageAlways, api.ResolveImageChanged, api.ResolveImageNever)
+			}
+		}),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runDeploy(ctx, dockerCli, backendOptions, opts)
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.swarm, "swarm", false, "Deploy project as a Swarm stack")
+	flags.BoolVar(&opts.prune, "prune", false, "Remove stack services not declared by the compose file")
+	flags.StringVar(&opts.resolveImage, "resolve-image", api.ResolveImageAlways, `Query registry to pin images to a digest ("always", "changed", "never")`)
+	flags.BoolVar(&opts.wait, "wait", false, "Wait for services to have all their tasks running")
+	flags.IntVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum duration in seconds to wait for services to converge")
+	return cmd
+}
+
+func runDeploy(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts deployOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+	return backend.SwarmDeploy(ctx, project, api.SwarmDeployOptions{
+		Prune:        opts.prune,
+		ResolveImage: opts.resolveImage,
+		Wait:         opts.wait,
+		WaitTimeout:  time.Duration(opts.waitTimeout) * time.Second,
+	})
+}
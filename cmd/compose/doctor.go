@@ -0,0 +1,271 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/internal"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type doctorOptions struct {
+	*ProjectOptions
+	format string
+	bundle string
+}
+
+func doctorCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := doctorOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "doctor [OPTIONS]",
+		Short: "Diagnose Docker environment and project configuration",
+		Long: `Diagnose Docker environment and project configuration.
+
+Checks daemon connectivity and version, BuildKit support, Docker context, credential
+helpers, Docker Desktop file sharing and common project issues, then suggests fixes.
+Use --bundle to collect diagnostics in an archive to be attached to a bug report.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runDoctor(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	cmd.Flags().StringVar(&opts.bundle, "bundle", "", "Write a diagnostics bundle (tar.gz) to the given path")
+	return cmd
+}
+
+func runDoctor(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts doctorOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	// doctor also runs outside a project, or with a broken one
+	project, _, loadErr := opts.ToProject(ctx, dockerCli, backend, nil)
+	if loadErr != nil {
+		logrus.Debugf("failed to load project: %v", loadErr)
+		project = nil
+	}
+
+	report, err := backend.Doctor(ctx, project)
+	if err != nil {
+		return err
+	}
+	if loadErr != nil {
+		report.Checks = append(report.Checks, api.DoctorCheck{
+			Name:    "project",
+			Status:  api.DoctorWarning,
+			Message: loadErr.Error(),
+			Fix:     "Run from the project directory, or select compose file with --file",
+		})
+	}
+
+	if opts.bundle != "" {
+		if err := writeDoctorBundle(ctx, dockerCli, opts.bundle, report, project); err != nil {
+			return err
+		}
+	}
+
+	switch opts.format {
+	case formatter.JSON:
+		out, err := formatter.ToJSON(report, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(dockerCli.Out(), out)
+	case formatter.TABLE, "":
+		err = formatter.Print(report.Checks, formatter.TABLE, dockerCli.Out(),
+			func(w io.Writer) {
+				for _, c := range report.Checks {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Name, c.Status, c.Message, c.Fix)
+				}
+			},
+			"CHECK", "STATUS", "MESSAGE", "FIX")
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("format value %q could not be parsed: %w", opts.format, api.ErrParsingFailed)
+	}
+	if opts.bundle != "" {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Diagnostics bundle written to %s\n", opts.bundle)
+	}
+
+	var failed int
+	for _, c := range report.Checks {
+		if c.Status == api.DoctorError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// writeDoctorBundle writes a tar.gz archive with doctor report, engine information and redacted project model
+func writeDoctorBundle(ctx context.Context, dockerCli command.Cli, path string, report api.DoctorReport, project *types.Project) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	files := []bundleFile{{name: "doctor.json", content: content}}
+	engine, err := engineBundleFiles(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	files = append(files, engine...)
+	if project != nil {
+		redacted, err := redactProject(project)
+		if err != nil {
+			return err
+		}
+		content, err := redacted.MarshalYAML()
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{name: "compose.yaml", content: content})
+	}
+	return writeBundle(path, files)
+}
+
+// bundleFile is a file to be added to a diagnostics bundle
+type bundleFile struct {
+	name    string
+	content []byte
+}
+
+// engineBundleFiles collects compose version, Docker context and engine information, ignoring engine errors
+// so a bundle can be produced while engine is unreachable
+func engineBundleFiles(ctx context.Context, dockerCli command.Cli) ([]bundleFile, error) {
+	values := map[string]any{
+		"compose.json": map[string]string{
+			"version": internal.Version,
+			"context": dockerCli.CurrentContext(),
+			"host":    dockerCli.DockerEndpoint().Host,
+		},
+	}
+	if version, err := dockerCli.Client().ServerVersion(ctx); err == nil {
+		values["version.json"] = version
+	}
+	if info, err := dockerCli.Client().Info(ctx); err == nil {
+		values["info.json"] = info
+	}
+	var files []bundleFile
+	for _, name := range []string{"compose.json", "version.json", "info.json"} {
+		v, ok := values[name]
+		if !ok {
+			continue
+		}
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{name: name, content: content})
+	}
+	return files, nil
+}
+
+// writeBundle writes files as a tar.gz archive
+func writeBundle(path string, files []bundleFile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		if err := addBundleFile(tw, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func addBundleFile(tw *tar.Writer, name string, content []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
+
+// redactProject hides environment values and inlined secrets, so the project model can be shared
+func redactProject(project *types.Project) (*types.Project, error) {
+	redacted, err := project.WithServicesTransform(func(_ string, s types.ServiceConfig) (types.ServiceConfig, error) {
+		environment := types.MappingWithEquals{}
+		for k, v := range s.Environment {
+			if v != nil {
+				v = redactedValue()
+			}
+			environment[k] = v
+		}
+		s.Environment = environment
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	secrets := types.Secrets{}
+	for name, secret := range redacted.Secrets {
+		if secret.Content != "" {
+			secret.Content = *redactedValue()
+		}
+		secrets[name] = secret
+	}
+	if len(secrets) > 0 {
+		redacted.Secrets = secrets
+	}
+	return redacted, nil
+}
+
+func redactedValue() *string {
+	v := "<redacted>"
+	return &v
+}
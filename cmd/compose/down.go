@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/docker/compose/v5/cmd/prompt"
 	"github.com/docker/compose/v5/pkg/api"
 )
 
@@ -38,7 +40,11 @@ type downOptions struct {
 	timeChanged   bool
 	timeout       int
 	volumes       bool
+	includeCache  bool
+	snapshot      bool
 	images        string
+	interactive   bool
+	timings       bool
 }
 
 func downCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -50,6 +56,15 @@ func downCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 		Short: "Stop and remove containers, networks",
 		PreRunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
 			opts.timeChanged = cmd.Flags().Changed("timeout")
+			if err := checkInteractive(opts.interactive, args); err != nil {
+				return err
+			}
+			if opts.includeCache && !opts.volumes {
+				return errors.New("--include-cache requires --volumes")
+			}
+			if opts.snapshot && !opts.volumes {
+				return errors.New("--snapshot requires --volumes")
+			}
 			if opts.images != "" {
 				if opts.images != "all" && opts.images != "local" {
 					return fmt.Errorf("invalid value for --rmi: %q", opts.images)
@@ -67,7 +82,11 @@ func downCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", removeOrphans, "Remove containers for services not defined in the Compose file")
 	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds")
 	flags.BoolVarP(&opts.volumes, "volumes", "v", false, `Remove named volumes declared in the "volumes" section of the Compose file and anonymous volumes attached to containers`)
+	flags.BoolVar(&opts.includeCache, "include-cache", false, "Also remove cache volumes declared by develop.x-cache_volumes, used with --volumes")
+	flags.BoolVar(&opts.snapshot, "snapshot", false, "Save a snapshot of volumes before they are removed, used with --volumes")
 	flags.StringVar(&opts.images, "rmi", "", `Remove images used by services. "local" remove only images that don't have a custom tag ("local"|"all")`)
+	flags.BoolVar(&opts.interactive, "interactive", false, interactiveFlagUsage)
+	flags.BoolVar(&opts.timings, "timings", false, timingsFlagUsage)
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		if name == "volume" {
 			name = "volumes"
@@ -89,16 +108,35 @@ func runDown(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		timeoutValue := time.Duration(opts.timeout) * time.Second
 		timeout = &timeoutValue
 	}
+	var summary timingsSummary
+	if opts.timings {
+		backendOptions.Add(compose.WithServiceTimings(summary.hook))
+	}
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
 	}
-	return backend.Down(ctx, name, api.DownOptions{
+	if opts.interactive {
+		ui := prompt.NewPrompt(dockerCli.In(), dockerCli.Out())
+		services, err = selectServices(ctx, backend, ui, project, name, "Select services to stop and remove")
+		if err != nil {
+			return err
+		}
+	}
+	err = backend.Down(ctx, name, api.DownOptions{
 		RemoveOrphans: opts.removeOrphans,
 		Project:       project,
 		Timeout:       timeout,
 		Images:        opts.images,
 		Volumes:       opts.volumes,
+		IncludeCache:  opts.includeCache,
+		Snapshot:      opts.snapshot,
 		Services:      services,
 	})
+	if opts.timings {
+		if err := summary.print(dockerCli); err != nil {
+			return err
+		}
+	}
+	return err
 }
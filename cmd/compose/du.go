@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type diskUsageOptions struct {
+	*ProjectOptions
+	format string
+}
+
+func diskUsageCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := diskUsageOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "du [OPTIONS] [SERVICE...]",
+		Short: "Show disk usage of project images, containers, volumes, logs and build cache",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runDiskUsage(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runDiskUsage(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts diskUsageOptions, services []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	report, err := backend.DiskUsage(ctx, projectName, api.DiskUsageOptions{
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(opts.format) {
+	case formatter.TABLE, "":
+		return printDiskUsage(dockerCli.Out(), report)
+	default:
+		return formatter.Print(report, opts.format, dockerCli.Out(), nil)
+	}
+}
+
+func printDiskUsage(out io.Writer, report api.DiskUsageReport) error {
+	if len(report.Services) > 0 {
+		err := formatter.PrintPrettySection(out, func(w io.Writer) {
+			for _, s := range report.Services {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Service, s.Image,
+					humanSize(s.ImageSize), humanSize(s.WritableSize), humanSize(s.LogSize))
+			}
+		}, "SERVICE", "IMAGE", "IMAGE SIZE", "WRITABLE", "LOGS")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	if len(report.Volumes) > 0 {
+		err := formatter.PrintPrettySection(out, func(w io.Writer) {
+			for _, v := range report.Volumes {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", v.Volume, v.Name, humanSize(v.Size))
+			}
+		}, "VOLUME", "NAME", "SIZE")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	if report.BuildCache > 0 {
+		_, _ = fmt.Fprintf(out, "Build cache (shared with other builds): %s\n", humanSize(report.BuildCache))
+	}
+	_, _ = fmt.Fprintf(out, "Total: %s\n", humanSize(report.Total))
+	return nil
+}
+
+// humanSize formats a size in bytes, a negative size being unknown
+func humanSize(size int64) string {
+	if size < 0 {
+		return "N/A"
+	}
+	return units.HumanSizeWithPrecision(float64(size), 3)
+}
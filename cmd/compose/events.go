@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/docker/cli/cli/command"
@@ -33,6 +34,8 @@ type eventsOpts struct {
 	json  bool
 	since string
 	until string
+	after string
+	limit int
 }
 
 func eventsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -45,6 +48,9 @@ func eventsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 		Use:   "events [OPTIONS] [SERVICE...]",
 		Short: "Receive real time events from containers",
 		RunE: Adapt(func(ctx context.Context, args []string) error {
+			if opts.since != "" && opts.after != "" {
+				return errors.New("--since and --after are incompatible")
+			}
 			return runEvents(ctx, dockerCli, backendOptions, opts, args)
 		}),
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
@@ -53,6 +59,8 @@ func eventsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output events as a stream of json objects")
 	cmd.Flags().StringVar(&opts.since, "since", "", "Show all events created since timestamp")
 	cmd.Flags().StringVar(&opts.until, "until", "", "Stream events until this timestamp")
+	cmd.Flags().StringVar(&opts.after, "after", "", "Resume events after the one identified by this cursor, as reported by --json output")
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "Stop after this number of events")
 	return cmd
 }
 
@@ -70,6 +78,8 @@ func runEvents(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		Services: services,
 		Since:    opts.since,
 		Until:    opts.until,
+		After:    opts.after,
+		Limit:    opts.limit,
 		Consumer: func(event api.Event) error {
 			if opts.json {
 				marshal, err := json.Marshal(map[string]interface{}{
@@ -79,6 +89,7 @@ func runEvents(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 					"id":         event.Container,
 					"action":     event.Status,
 					"attributes": event.Attributes,
+					"cursor":     event.Cursor,
 				})
 				if err != nil {
 					return err
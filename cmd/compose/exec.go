@@ -46,6 +46,7 @@ type execOpts struct {
 	index       int
 	privileged  bool
 	interactive bool
+	record      string
 }
 
 func execCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -61,6 +62,9 @@ func execCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 		PreRunE: Adapt(func(ctx context.Context, args []string) error {
 			opts.service = args[0]
 			opts.command = args[1:]
+			if opts.record != "" && opts.detach {
+				return errors.New("--record and --detach are incompatible")
+			}
 			return nil
 		}),
 		RunE: Adapt(func(ctx context.Context, args []string) error {
@@ -80,10 +84,12 @@ func execCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	runCmd.Flags().BoolVarP(&opts.detach, "detach", "d", false, "Detached mode: Run command in the background")
 	runCmd.Flags().StringArrayVarP(&opts.environment, "env", "e", []string{}, "Set environment variables")
 	runCmd.Flags().IntVar(&opts.index, "index", 0, "Index of the container if service has multiple replicas")
+	runCmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	runCmd.Flags().BoolVarP(&opts.privileged, "privileged", "", false, "Give extended privileges to the process")
 	runCmd.Flags().StringVarP(&opts.user, "user", "u", "", "Run the command as this user")
 	runCmd.Flags().BoolVarP(&opts.noTty, "no-tty", "T", !dockerCli.Out().IsTerminal(), "Disable pseudo-TTY allocation. By default 'docker compose exec' allocates a TTY.")
 	runCmd.Flags().StringVarP(&opts.workingDir, "workdir", "w", "", "Path to workdir directory for this command")
+	runCmd.Flags().StringVar(&opts.record, "record", "", "Record the session to an asciicast file, with output timing and terminal resizes")
 
 	runCmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", true, "Keep STDIN open even if not attached")
 	runCmd.Flags().MarkHidden("interactive") //nolint:errcheck
@@ -105,7 +111,7 @@ func runExec(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 	if err != nil {
 		return err
 	}
-	projectOptions, err := opts.composeOptions.toProjectOptions() //nolint:staticcheck
+	projectOptions, err := opts.composeOptions.toProjectOptions(ctx, opts.remoteLoaders(dockerCli)) //nolint:staticcheck
 	if err != nil {
 		return err
 	}
@@ -113,7 +119,7 @@ func runExec(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		v, ok := projectOptions.Environment[k]
 		return v, ok
 	}
-	execOpts := api.RunOptions{
+	execOpts := api.ExecOptions{
 		Service:     opts.service,
 		Command:     opts.command,
 		Environment: compose.ToMobyEnv(types.NewMappingWithEquals(opts.environment).Resolve(lookupFn)),
@@ -124,13 +130,14 @@ func runExec(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		Detach:      opts.detach,
 		WorkingDir:  opts.workingDir,
 		Interactive: opts.interactive,
+		Record:      opts.record,
 	}
 
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
 	}
-	exitCode, err := backend.Exec(ctx, projectName, execOpts)
+	exitCode, err := backend.ExecCommand(ctx, projectName, execOpts)
 	if exitCode != 0 {
 		errMsg := fmt.Sprintf("exit status %d", exitCode)
 		if err != nil && err.Error() != "" {
@@ -0,0 +1,131 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	dockercli "github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// ExitCodeError is the exit code for failures which are not classified
+	ExitCodeError = 1
+	// ExitCodeCanceled is the exit code when command is interrupted by user
+	ExitCodeCanceled = 130
+)
+
+// exitCode documents a process exit code of the compose CLI
+type exitCode struct {
+	Code        int         `json:"code"`
+	Name        api.ErrCode `json:"name"`
+	Description string      `json:"description"`
+}
+
+// exitCodes is the contract for process exit codes, following sysexits.h conventions for classified failures.
+// Codes must not change once released, as automation relies on them to decide to retry or abort.
+var exitCodes = []exitCode{
+	{Code: 0, Name: "success", Description: "Command completed successfully"},
+	{Code: ExitCodeError, Name: "error", Description: "Command failed for an unclassified reason"},
+	{Code: 64, Name: api.ErrCodeInvalidConfig, Description: "Compose model is invalid or can't be loaded"},
+	{Code: 66, Name: api.ErrCodeNotFound, Description: "A required resource doesn't exist"},
+	{Code: 69, Name: api.ErrCodeDaemonUnavailable, Description: "Docker daemon can't be reached"},
+	{Code: 70, Name: api.ErrCodeDependencyFailed, Description: "A service dependency failed to start or complete"},
+	{Code: 71, Name: api.ErrCodePortConflict, Description: "A published port is already in use"},
+	{Code: 75, Name: api.ErrCodeHealthTimeout, Description: "Services didn't become healthy in time, command can be retried"},
+	{Code: 77, Name: api.ErrCodePullDenied, Description: "Registry denied an image pull"},
+	{Code: 124, Name: api.ErrCodeTimedOut, Description: "Command didn't complete before its deadline, as timeout(1) reports"},
+	{Code: ExitCodeCanceled, Name: "canceled", Description: "Command was interrupted by user"},
+}
+
+// errorCode returns the API error code for err, classifying errors reported by the Docker client
+func errorCode(err error) api.ErrCode {
+	if code := api.ErrorCode(err); code != "" {
+		return code
+	}
+	if client.IsErrConnectionFailed(err) {
+		return api.ErrCodeDaemonUnavailable
+	}
+	return ""
+}
+
+// withExitCode converts an error with an API error code into a StatusError, so the process exits with the
+// matching exit code
+func withExitCode(err error, code api.ErrCode) error {
+	if err == nil || code == "" {
+		return err
+	}
+	var statusErr dockercli.StatusError
+	if errors.As(err, &statusErr) {
+		return err
+	}
+	for _, c := range exitCodes {
+		if c.Name == code {
+			return dockercli.StatusError{
+				StatusCode: c.Code,
+				Status:     err.Error(),
+			}
+		}
+	}
+	return err
+}
+
+func exitCodesCommand(dockerCli command.Cli) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "exit-codes [OPTIONS]",
+		Short: "List the exit codes used by Compose to report failures",
+		Long: `List the exit codes used by Compose to report failures.
+
+Classified failures only exit with their dedicated code when ` + "`COMPOSE_CLASSIFIED_EXIT_CODES`" + ` is set,
+otherwise they exit with code 1, as unclassified failures do. Error code is always reported by
+JSON errors when ` + "`--progress=json`" + ` is used.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return printExitCodes(dockerCli.Out(), asJSON)
+		}),
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Format the output as JSON")
+	return cmd
+}
+
+func printExitCodes(out io.Writer, asJSON bool) error {
+	if asJSON {
+		s, err := formatter.ToStandardJSON(exitCodes)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(out, s)
+		return err
+	}
+	return formatter.Print(exitCodes, formatter.TABLE, out,
+		func(w io.Writer) {
+			for _, c := range exitCodes {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", c.Code, c.Name, c.Description)
+			}
+		},
+		"CODE", "NAME", "DESCRIPTION")
+}
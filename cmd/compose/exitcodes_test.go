@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	dockercli "github.com/docker/cli/cli"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// TestExitCodesContract asserts released exit codes don't change, as automation relies on them
+func TestExitCodesContract(t *testing.T) {
+	expected := map[api.ErrCode]int{
+		"success":                    0,
+		"error":                      1,
+		api.ErrCodeInvalidConfig:     64,
+		api.ErrCodeNotFound:          66,
+		api.ErrCodeDaemonUnavailable: 69,
+		api.ErrCodeDependencyFailed:  70,
+		api.ErrCodePortConflict:      71,
+		api.ErrCodeHealthTimeout:     75,
+		api.ErrCodePullDenied:        77,
+		api.ErrCodeTimedOut:          124,
+		"canceled":                   130,
+	}
+	actual := map[api.ErrCode]int{}
+	seen := map[int]bool{}
+	for _, c := range exitCodes {
+		assert.Assert(t, !seen[c.Code], "exit code %d is not distinct", c.Code)
+		seen[c.Code] = true
+		actual[c.Name] = c.Code
+	}
+	assert.DeepEqual(t, actual, expected)
+}
+
+func TestWithExitCode(t *testing.T) {
+	err := fmt.Errorf("service web: %w", api.WithErrCode(errors.New("application not healthy after 10s"), api.ErrCodeHealthTimeout))
+	assert.DeepEqual(t, withExitCode(err, errorCode(err)), dockercli.StatusError{
+		StatusCode: 75,
+		Status:     "service web: application not healthy after 10s",
+	})
+
+	err = client.ErrorConnectionFailed("unix:///var/run/docker.sock")
+	assert.Equal(t, errorCode(err), api.ErrCodeDaemonUnavailable)
+	assert.Equal(t, withExitCode(err, errorCode(err)).(dockercli.StatusError).StatusCode, 69)
+
+	err = fmt.Errorf("up: %w", api.ErrTimedOut)
+	assert.Equal(t, errorCode(err), api.ErrCodeTimedOut)
+	assert.DeepEqual(t, withExitCode(err, errorCode(err)), dockercli.StatusError{
+		StatusCode: 124,
+		Status:     "up: operation timed out",
+	})
+
+	err = errors.New("unclassified")
+	assert.Equal(t, withExitCode(err, errorCode(err)), err)
+
+	status := dockercli.StatusError{StatusCode: ExitCodeCanceled}
+	assert.DeepEqual(t, withExitCode(status, api.ErrCodeNotFound), status)
+}
+
+func TestAdaptCmdClassifiedExitCodes(t *testing.T) {
+	notFound := api.WithErrCode(errors.New("no such service: web"), api.ErrCodeNotFound)
+	run := AdaptCmd(func(context.Context, *cobra.Command, []string) error {
+		return notFound
+	})
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	// exit code is left unchanged unless opted in
+	assert.Equal(t, run(cmd, nil), notFound)
+
+	t.Setenv(ComposeClassifiedExitCodes, "1")
+	assert.DeepEqual(t, run(cmd, nil), dockercli.StatusError{StatusCode: 66, Status: "no such service: web"})
+}
+
+func TestPrintExitCodesJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, printExitCodes(&out, true))
+	var decoded []exitCode
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.DeepEqual(t, decoded, exitCodes)
+}
+
+func TestMakeJSONErrorCode(t *testing.T) {
+	err := makeJSONError(errors.New("port is already allocated"), api.ErrCodePortConflict)
+	assert.Equal(t, err.Error(), `{"error":true,"message":"port is already allocated","code":"port_conflict"}`)
+}
@@ -54,6 +54,7 @@ func exportCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 
 	flags := cmd.Flags()
 	flags.IntVar(&options.index, "index", 0, "index of the container if service has multiple replicas.")
+	cmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	flags.StringVarP(&options.output, "output", "o", "", "Write to a file, instead of STDOUT")
 
 	return cmd
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/compose"
+	"github.com/docker/compose/v5/pkg/features"
+)
+
+func featuresCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "features CMD [OPTIONS]",
+		Short:            "Manage optional and experimental Compose features",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		featuresListCommand(p, dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type featuresListOptions struct {
+	*ProjectOptions
+	format string
+}
+
+func featuresListCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := featuresListOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "list [OPTIONS]",
+		Short: "List features, whether they are enabled and by which source",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runFeaturesList(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runFeaturesList(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts featuresListOptions) error {
+	set, err := features.Load()
+	if err != nil {
+		return err
+	}
+
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	// project overrides are listed when command runs within a compose project
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	switch {
+	case errors.Is(err, errdefs.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		if set, err = set.ForProject(project); err != nil {
+			return err
+		}
+	}
+
+	list := set.List()
+	return formatter.Print(list, opts.format, dockerCli.Out(), func(w io.Writer) {
+		for _, f := range list {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", f.Name, f.Stage, f.Enabled, f.Source, f.Since, f.Description)
+		}
+	}, "NAME", "STAGE", "ENABLED", "SOURCE", "SINCE", "DESCRIPTION")
+}
@@ -23,6 +23,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/containerd/platforms"
@@ -38,8 +39,10 @@ import (
 
 type imageOptions struct {
 	*ProjectOptions
-	Quiet  bool
-	Format string
+	Quiet      bool
+	Format     string
+	Tree       bool
+	PullPolicy bool
 }
 
 func imagesCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -56,10 +59,18 @@ func imagesCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	}
 	imgCmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
 	imgCmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
+	imgCmd.Flags().BoolVar(&opts.Tree, "tree", false, "Display images as a tree of shared layers, with storage they consume")
+	imgCmd.Flags().BoolVar(&opts.PullPolicy, "pull-policy", false, "Audit services pull policy, local images freshness and which would be pulled by next up")
 	return imgCmd
 }
 
 func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts imageOptions, services []string) error {
+	if opts.PullPolicy {
+		if opts.Tree || opts.Quiet {
+			return fmt.Errorf("--pull-policy can't be combined with --tree or --quiet")
+		}
+		return runImagesPullAudit(ctx, dockerCli, backendOptions, opts, services)
+	}
 	projectName, err := opts.toProjectName(ctx, dockerCli)
 	if err != nil {
 		return err
@@ -69,8 +80,19 @@ func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 	if err != nil {
 		return err
 	}
-	images, err := backend.Images(ctx, projectName, api.ImagesOptions{
-		Services: services,
+	if opts.Tree {
+		tree, err := backend.ImagesTree(ctx, projectName, api.ImagesOptions{
+			Services: services,
+		})
+		if err != nil {
+			return err
+		}
+		return printImagesTree(dockerCli.Out(), tree, opts.Format)
+	}
+	images, stale, err := withStateCache(dockerCli, opts.Offline, stateCacheName(dockerCli.CurrentContext(), "images", projectName, false, services), func() (map[string]api.ImageSummary, error) {
+		return backend.Images(ctx, projectName, api.ImagesOptions{
+			Services: services,
+		})
 	})
 	if err != nil {
 		return err
@@ -103,6 +125,8 @@ func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 			Size          int64      `json:"Size"`
 			Created       *time.Time `json:"Created,omitempty"`
 			LastTagTime   time.Time  `json:"LastTagTime,omitzero"`
+			Revision      string     `json:"Revision,omitempty"`
+			Branch        string     `json:"Branch,omitempty"`
 		}
 		// Convert map to slice
 		var imageList []img
@@ -117,6 +141,8 @@ func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 				Size:          i.Size,
 				Created:       i.Created,
 				LastTagTime:   lastTagTime,
+				Revision:      i.Revision,
+				Branch:        i.Branch,
 			})
 		}
 		json, err := formatter.ToJSON(imageList, "", "")
@@ -127,6 +153,14 @@ func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		return err
 	}
 
+	// git metadata is only displayed when images have been built with it, see x-build tag-template
+	headers := []string{"CONTAINER", "REPOSITORY", "TAG", "PLATFORM", "IMAGE ID", "SIZE", "CREATED"}
+	withRevision := slices.ContainsFunc(slices.Collect(maps.Values(images)), func(img api.ImageSummary) bool {
+		return img.Revision != ""
+	})
+	if withRevision {
+		headers = append(headers, "REVISION")
+	}
 	return formatter.Print(images, opts.Format, dockerCli.Out(),
 		func(w io.Writer) {
 			for _, container := range slices.Sorted(maps.Keys(images)) {
@@ -145,9 +179,128 @@ func runImages(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 				if img.Created != nil {
 					created = units.HumanDuration(time.Now().UTC().Sub(*img.Created)) + " ago"
 				}
-				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				if stale {
+					created += " " + staleMarker
+				}
+				line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
 					container, repo, tag, platforms.Format(img.Platform), id, size, created)
+				if withRevision {
+					line += "\t" + formatRevision(img)
+				}
+				_, _ = fmt.Fprintln(w, line)
 			}
 		},
-		"CONTAINER", "REPOSITORY", "TAG", "PLATFORM", "IMAGE ID", "SIZE", "CREATED")
+		headers...)
+}
+
+func runImagesPullAudit(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts imageOptions, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+	audits, err := backend.ImagesPullAudit(ctx, project, api.ImagesOptions{
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+	return formatter.Print(audits, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, a := range audits {
+				next := "-"
+				if a.WillPull {
+					next = "pull"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Service, a.Image, a.PullPolicy, pullAuditStatus(a), next)
+			}
+		},
+		"SERVICE", "IMAGE", "PULL POLICY", "LOCAL IMAGE", "NEXT UP")
+}
+
+// pullAuditStatus describes local image freshness relative to registry
+func pullAuditStatus(a api.ImagePullAudit) string {
+	switch {
+	case !a.Local:
+		return "missing"
+	case a.Error != "":
+		return "unknown (registry unavailable)"
+	case a.LocalDigest == "":
+		return "not pulled from registry"
+	case a.Stale:
+		return "stale"
+	default:
+		return "up to date"
+	}
+}
+
+// formatRevision displays short commit and branch an image was built from
+func formatRevision(img api.ImageSummary) string {
+	revision := img.Revision
+	if len(revision) > 7 {
+		revision = revision[:7]
+	}
+	switch {
+	case revision == "":
+		return "N/A"
+	case img.Branch != "":
+		return fmt.Sprintf("%s (%s)", revision, img.Branch)
+	default:
+		return revision
+	}
+}
+
+func printImagesTree(out io.Writer, tree api.ImageTree, format string) error {
+	if format == "json" {
+		json, err := formatter.ToJSON(tree, "", "")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, json)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "IMAGE\tLAYERS\tSIZE\tSERVICES")
+	var printNode func(node api.ImageTreeNode, prefix, branch string, child bool)
+	printNode = func(node api.ImageTreeNode, prefix, branch string, child bool) {
+		name := node.Image
+		if name == "" {
+			name = "<shared layers>"
+		}
+		size := units.HumanSizeWithPrecision(float64(node.Size), 3)
+		if node.SizeUnknown {
+			size = "~" + size
+		}
+		if child {
+			size = "+" + size
+		}
+		_, _ = fmt.Fprintf(w, "%s%s%s\t%d\t%s\t%s\n", prefix, branch, name, node.Layers, size, strings.Join(node.Services, ", "))
+		switch branch {
+		case "├─ ":
+			prefix += "│  "
+		case "└─ ":
+			prefix += "   "
+		}
+		for i, c := range node.Children {
+			b := "├─ "
+			if i == len(node.Children)-1 {
+				b = "└─ "
+			}
+			printNode(c, prefix, b, true)
+		}
+	}
+	for _, root := range tree.Roots {
+		printNode(root, "", "", false)
+	}
+	_ = w.Flush()
+
+	_, err := fmt.Fprintf(out, "\nTotal size %s, unique storage %s (%s shared)\n",
+		units.HumanSizeWithPrecision(float64(tree.TotalSize), 3),
+		units.HumanSizeWithPrecision(float64(tree.UniqueSize), 3),
+		units.HumanSizeWithPrecision(float64(max(tree.TotalSize-tree.UniqueSize, 0)), 3))
+	return err
 }
@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestPrintImagesTree(t *testing.T) {
+	tree := api.ImageTree{
+		Roots: []api.ImageTreeNode{
+			{
+				Services: []string{"web", "worker"},
+				Layers:   2,
+				Size:     75_000_000,
+				Children: []api.ImageTreeNode{
+					{Image: "myproject-web", Services: []string{"web"}, Layers: 1, Size: 100_000_000},
+					{Image: "myproject-worker", Services: []string{"worker"}, Layers: 2, Size: 30_000_000, SizeUnknown: true},
+				},
+			},
+		},
+		TotalSize:  280_000_000,
+		UniqueSize: 205_000_000,
+	}
+	var out bytes.Buffer
+	assert.NilError(t, printImagesTree(&out, tree, "table"))
+	assert.Equal(t, out.String(), `IMAGE                 LAYERS   SIZE     SERVICES
+<shared layers>       2        75MB     web, worker
+├─ myproject-web      1        +100MB   web
+└─ myproject-worker   2        +~30MB   worker
+
+Total size 280MB, unique storage 205MB (75MB shared)
+`)
+}
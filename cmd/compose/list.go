@@ -39,13 +39,13 @@ type lsOptions struct {
 	Filter opts.FilterOpt
 }
 
-func listCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+func listCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
 	lsOpts := lsOptions{Filter: opts.NewFilterOpt()}
 	lsCmd := &cobra.Command{
 		Use:   "ls [OPTIONS]",
 		Short: "List running compose projects",
 		RunE: Adapt(func(ctx context.Context, args []string) error {
-			return runList(ctx, dockerCli, backendOptions, lsOpts)
+			return runList(ctx, dockerCli, backendOptions, lsOpts, p.Offline)
 		}),
 		Args:              cobra.NoArgs,
 		ValidArgsFunction: noCompletion(),
@@ -62,7 +62,7 @@ var acceptedListFilters = map[string]bool{
 	"name": true,
 }
 
-func runList(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, lsOpts lsOptions) error {
+func runList(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, lsOpts lsOptions, offline bool) error {
 	filters := lsOpts.Filter.Value()
 	err := filters.Validate(acceptedListFilters)
 	if err != nil {
@@ -73,10 +73,18 @@ func runList(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 	if err != nil {
 		return err
 	}
-	stackList, err := backend.List(ctx, api.ListOptions{All: lsOpts.All})
+	cacheName := stateCacheName(dockerCli.CurrentContext(), "ls", "", lsOpts.All, nil)
+	stackList, stale, err := withStateCache(dockerCli, offline, cacheName, func() ([]api.Stack, error) {
+		return backend.List(ctx, api.ListOptions{All: lsOpts.All})
+	})
 	if err != nil {
 		return err
 	}
+	if stale {
+		for i := range stackList {
+			stackList[i].Reason = strings.TrimSpace(stackList[i].Reason + " " + staleMarker)
+		}
+	}
 
 	if filters.Len() > 0 {
 		var filtered []api.Stack
@@ -19,10 +19,15 @@ package compose
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/compose/v5/cmd/display"
 	"github.com/docker/compose/v5/pkg/compose"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/docker/compose/v5/cmd/formatter"
 	"github.com/docker/compose/v5/pkg/api"
@@ -38,7 +43,13 @@ type logsOptions struct {
 	until      string
 	noColor    bool
 	noPrefix   bool
-	timestamps bool
+	timestamps timestampsOpt
+	truncate   bool
+	maxSize    string
+	recreate   bool
+	oneOff     bool
+	bufferSize int
+	overflow   string
 }
 
 func logsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -55,6 +66,15 @@ func logsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 			if opts.index > 0 && len(args) != 1 {
 				return errors.New("--index requires one service to be selected")
 			}
+			if opts.oneOff && opts.index > 0 {
+				return errors.New("--one-off can't be used with --index")
+			}
+			if opts.maxSize != "" && !opts.truncate {
+				return errors.New("--max-size can only be used with --truncate")
+			}
+			if opts.recreate && !opts.truncate {
+				return errors.New("--recreate can only be used with --truncate")
+			}
 			return nil
 		},
 		ValidArgsFunction: completeServiceNames(dockerCli, p),
@@ -62,16 +82,26 @@ func logsCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	flags := logsCmd.Flags()
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.IntVar(&opts.index, "index", 0, "index of the container if service has multiple replicas")
+	logsCmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	flags.StringVar(&opts.until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+	flags.BoolVar(&opts.oneOff, "one-off", false, "Include logs of one-off containers created by \"compose run\"")
+	flags.IntVar(&opts.bufferSize, "buffer-size", 0, "Number of log lines buffered while output is busy")
+	flags.StringVar(&opts.overflow, "overflow", string(api.LogOverflowBlock), "How log lines are handled when buffer is full (block, drop)")
 	flags.BoolVar(&opts.noColor, "no-color", false, "Produce monochrome output")
 	flags.BoolVar(&opts.noPrefix, "no-log-prefix", false, "Don't print prefix in logs")
-	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
+	addTimestampsFlag(flags, &opts.timestamps, "t")
 	flags.StringVarP(&opts.tail, "tail", "n", "all", "Number of lines to show from the end of the logs for each container")
+	flags.BoolVar(&opts.truncate, "truncate", false, "Discard logs of service containers")
+	flags.StringVar(&opts.maxSize, "max-size", "", "With --truncate, recreate service containers with this log size limit (e.g. 10m)")
+	flags.BoolVar(&opts.recreate, "recreate", false, "With --truncate, recreate service containers which log files can't be truncated")
 	return logsCmd
 }
 
 func runLogs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts logsOptions, services []string) error {
+	if opts.truncate {
+		return runTruncateLogs(ctx, dockerCli, backendOptions, opts, services)
+	}
 	project, name, err := opts.projectOrName(ctx, dockerCli, services...)
 	if err != nil {
 		return err
@@ -90,7 +120,27 @@ func runLogs(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 	if err != nil {
 		return err
 	}
-	consumer := formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), !opts.noColor, !opts.noPrefix, false)
+	start := time.Now()
+	if opts.timestamps.mode == formatter.TimestampsRelative {
+		// relative timestamps are computed from the creation of the oldest container, so services are aligned
+		containers, err := backend.Ps(ctx, name, api.PsOptions{Project: project, All: true, Services: services})
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			if created := time.Unix(c.Created, 0); created.Before(start) {
+				start = created
+			}
+		}
+	}
+	consumerOptions := []formatter.LogConsumerOption{formatter.WithTimestamps(opts.timestamps.mode, start)}
+	if opts.timestamps.mode != formatter.TimestampsNone {
+		consumerOptions = append(consumerOptions, formatter.WithEngineTimestamps())
+	}
+	if display.Mode == display.ModeJSON {
+		consumerOptions = append(consumerOptions, formatter.WithJSONOutput())
+	}
+	consumer := formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), !opts.noColor, !opts.noPrefix, false, consumerOptions...)
 	return backend.Logs(ctx, name, consumer, api.LogOptions{
 		Project:    project,
 		Services:   services,
@@ -99,10 +149,78 @@ func runLogs(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		Tail:       opts.tail,
 		Since:      opts.since,
 		Until:      opts.until,
-		Timestamps: opts.timestamps,
+		Timestamps: opts.timestamps.mode != formatter.TimestampsNone,
+		OneOff:     opts.oneOff,
+		BufferSize: opts.bufferSize,
+		Overflow:   api.LogOverflowPolicy(opts.overflow),
 	})
 }
 
+func runTruncateLogs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts logsOptions, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+	err = backend.TruncateLogs(ctx, project, api.TruncateLogsOptions{
+		Services: services,
+		MaxSize:  opts.maxSize,
+		Recreate: opts.recreate,
+	})
+	if err == nil && opts.maxSize != "" {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Log size limit applies until containers are recreated from the compose file, "+
+			"set `logging.options.max-size` to make it permanent\n")
+	}
+	return err
+}
+
+// timestampsOpt is the value of the --timestamps flag, which can be used as a boolean flag or set to "relative"
+type timestampsOpt struct {
+	mode formatter.Timestamps
+}
+
+func addTimestampsFlag(flags *pflag.FlagSet, t *timestampsOpt, shorthand string) {
+	f := flags.VarPF(t, "timestamps", shorthand, `Show timestamps. Set to "relative" to show elapsed time since start of the run`)
+	f.NoOptDefVal = "true"
+}
+
+func (t *timestampsOpt) String() string {
+	switch t.mode {
+	case formatter.TimestampsAbsolute:
+		return "true"
+	case formatter.TimestampsRelative:
+		return string(formatter.TimestampsRelative)
+	default:
+		return "false"
+	}
+}
+
+func (t *timestampsOpt) Set(value string) error {
+	switch value {
+	case string(formatter.TimestampsRelative):
+		t.mode = formatter.TimestampsRelative
+	case string(formatter.TimestampsAbsolute):
+		t.mode = formatter.TimestampsAbsolute
+	default:
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf(`invalid value %q, expected "true", "false" or "relative"`, value)
+		}
+		t.mode = formatter.TimestampsNone
+		if enabled {
+			t.mode = formatter.TimestampsAbsolute
+		}
+	}
+	return nil
+}
+
+func (t *timestampsOpt) Type() string {
+	return "string"
+}
+
 var _ api.LogConsumer = &logConsumer{}
 
 type logConsumer struct {
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/config"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// ComposeMenuKeymap sets the file defining custom key bindings for the navigation menu
+const ComposeMenuKeymap = "COMPOSE_MENU_KEYMAP"
+
+// menuKeymap is the content of the keymap file
+type menuKeymap struct {
+	Bindings []api.MenuKeyBinding `yaml:"bindings"`
+}
+
+// menuKeymapFile returns the keymap file to load, and whether it has been explicitly set by user
+func menuKeymapFile() (string, bool) {
+	if file := os.Getenv(ComposeMenuKeymap); file != "" {
+		return file, true
+	}
+	return filepath.Join(config.Dir(), "compose", "keymap.yaml"), false
+}
+
+// loadMenuKeyBindings reads custom key bindings for the navigation menu. Default keymap file is optional.
+func loadMenuKeyBindings() ([]api.MenuKeyBinding, error) {
+	file, explicit := menuKeymapFile()
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keymap menuKeymap
+	if err := yaml.Unmarshal(content, &keymap); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return keymap.Bindings, nil
+}
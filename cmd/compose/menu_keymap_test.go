@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestLoadMenuKeyBindings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keymap.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(`
+bindings:
+  - key: s
+    action: exec
+    service: web
+    command: [bash]
+  - key: R
+    action: restart
+    description: Restart everything
+`), 0o600))
+	t.Setenv(ComposeMenuKeymap, file)
+
+	bindings, err := loadMenuKeyBindings()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bindings, []api.MenuKeyBinding{
+		{Key: "s", Action: api.MenuActionExec, Service: "web", Command: []string{"bash"}},
+		{Key: "R", Action: api.MenuActionRestart, Description: "Restart everything"},
+	})
+}
+
+func TestLoadMenuKeyBindingsMissingFile(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	t.Setenv(ComposeMenuKeymap, "")
+	bindings, err := loadMenuKeyBindings()
+	assert.NilError(t, err)
+	assert.Assert(t, bindings == nil)
+
+	// explicitly configured keymap must exist
+	t.Setenv(ComposeMenuKeymap, filepath.Join(t.TempDir(), "keymap.yaml"))
+	_, err = loadMenuKeyBindings()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
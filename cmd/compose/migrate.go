@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type migrateOptions struct {
+	*ProjectOptions
+	toProject   string
+	timeChanged bool
+	timeout     int
+	assumeYes   bool
+}
+
+func migrateCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := migrateOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS] --to-project NAME",
+		Short: "Rename a project without losing data",
+		Long: `Rename a project without losing data
+
+Containers and networks can't be relabeled by the engine, so they are removed and
+recreated under the new project name. Named volumes are copied into volumes of the
+new project, and source volumes are only removed once the new project has been created.
+Volumes declared with an explicit name are shared by both projects and are left unchanged.
+Services which were running are started again.
+
+Anonymous volumes are not migrated.`,
+		Args: cobra.NoArgs,
+		RunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			opts.timeChanged = cmd.Flags().Changed("timeout")
+			return runMigrate(ctx, dockerCli, backendOptions, opts)
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.toProject, "to-project", "", "New project name")
+	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Specify a shutdown timeout in seconds")
+	flags.BoolVarP(&opts.assumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
+	_ = cmd.MarkFlagRequired("to-project")
+	return cmd
+}
+
+func runMigrate(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts migrateOptions) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	if opts.assumeYes {
+		backendOptions.Options = append(backendOptions.Options, compose.WithPrompt(compose.AlwaysOkPrompt()))
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	// target project is loaded from the same Compose file, so resource names and labels are computed for the new name
+	opts.ProjectName = opts.toProject
+	target, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+
+	var timeout *time.Duration
+	if opts.timeChanged {
+		timeoutValue := time.Duration(opts.timeout) * time.Second
+		timeout = &timeoutValue
+	}
+	return backend.Migrate(ctx, projectName, target, api.MigrateOptions{
+		Timeout: timeout,
+	})
+}
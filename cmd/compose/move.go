@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type moveOptions struct {
+	*ProjectOptions
+	toContext   string
+	push        bool
+	cutover     bool
+	waitTimeout int
+}
+
+func moveCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := moveOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "move [OPTIONS] --to-context CONTEXT",
+		Short: "Move the project to another Docker context",
+		Long: `Move the project to another Docker context
+
+Images used by the project are transferred to the target engine, or pushed to their
+registry with --push for the target engine to pull them. Named volumes are copied,
+then the project is created and started on the target context, and move completes
+once services are running|healthy.
+
+By default the source project keeps running, and volumes data is copied live.
+Use --cutover to stop the source project before volumes are copied, so data is
+consistent and the project only runs on the target context.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runMove(ctx, dockerCli, backendOptions, opts)
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.toContext, "to-context", "", "Docker context to move the project to")
+	flags.BoolVar(&opts.push, "push", false, "Push images to their registry instead of transferring them")
+	flags.BoolVar(&opts.cutover, "cutover", false, "Stop the source project before copying volumes")
+	flags.IntVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum duration in seconds to wait for the project to be running|healthy")
+	_ = cmd.MarkFlagRequired("to-context")
+	return cmd
+}
+
+func runMove(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts moveOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+	return backend.Move(ctx, project, api.MoveOptions{
+		Context:     opts.toContext,
+		Push:        opts.push,
+		Cutover:     opts.cutover,
+		WaitTimeout: time.Duration(opts.waitTimeout) * time.Second,
+	})
+}
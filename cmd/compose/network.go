@@ -0,0 +1,362 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func networkCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "network CMD [OPTIONS]",
+		Short:            "Manage and troubleshoot project networks",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		networkListCommand(p, dockerCli, backendOptions),
+		networkInspectCommand(p, dockerCli, backendOptions),
+		networkConnectCommand(p, dockerCli, backendOptions),
+		networkDisconnectCommand(p, dockerCli, backendOptions),
+		networkRecreateCommand(p, dockerCli, backendOptions),
+		networkDoctorCommand(p, dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type networkListOptions struct {
+	*ProjectOptions
+	quiet  bool
+	format string
+}
+
+func networkListCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := networkListOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS] [SERVICE...]",
+		Aliases: []string{"list"},
+		Short:   "List project networks and the services attached to them",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkList(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Only display network names")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runNetworkList(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts networkListOptions, services []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	networks, err := backend.Networks(ctx, projectName, api.NetworksOptions{
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.quiet {
+		for _, nw := range networks {
+			_, _ = fmt.Fprintln(dockerCli.Out(), nw.Name)
+		}
+		return nil
+	}
+	return formatter.Print(networks, opts.format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, nw := range networks {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n",
+					nw.Network, nw.Name, nw.Driver, nw.Scope, nw.Internal, strings.Join(nw.Services, ", "))
+			}
+		},
+		"NETWORK", "NAME", "DRIVER", "SCOPE", "INTERNAL", "SERVICES")
+}
+
+func networkInspectCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect NETWORK",
+		Short: "Display detailed information on a project network",
+		Args:  cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkInspect(ctx, dockerCli, backendOptions, p, args[0])
+		}),
+		ValidArgsFunction: completeNetworkNames(dockerCli, p),
+	}
+}
+
+func runNetworkInspect(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, p *ProjectOptions, network string) error {
+	projectName, err := p.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	inspect, err := backend.NetworkInspect(ctx, projectName, network)
+	if err != nil {
+		return err
+	}
+	out, err := formatter.ToJSON(inspect, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(dockerCli.Out(), out)
+	return nil
+}
+
+type networkConnectOptions struct {
+	*ProjectOptions
+	aliases []string
+}
+
+func networkConnectCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := networkConnectOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "connect [OPTIONS] NETWORK [SERVICE...]",
+		Short: "Connect service containers to a project network",
+		Long: `Connect service containers to a project network.
+
+Connection is applied at runtime and is not persisted: containers are reconciled with
+the compose file on next ` + "`up`" + `.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkConnect(ctx, dockerCli, backendOptions, opts, args[0], args[1:])
+		}),
+		ValidArgsFunction: completeNetworkNames(dockerCli, p),
+	}
+	cmd.Flags().StringSliceVar(&opts.aliases, "alias", nil, "Add network-scoped alias for the containers")
+	return cmd
+}
+
+func runNetworkConnect(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts networkConnectOptions, network string, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+	return backend.NetworkConnect(ctx, project, api.NetworkConnectOptions{
+		Network:  network,
+		Services: services,
+		Aliases:  opts.aliases,
+	})
+}
+
+type networkDisconnectOptions struct {
+	*ProjectOptions
+	force bool
+}
+
+func networkDisconnectCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := networkDisconnectOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "disconnect [OPTIONS] NETWORK [SERVICE...]",
+		Short: "Disconnect service containers from a project network",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkDisconnect(ctx, dockerCli, backendOptions, opts, args[0], args[1:])
+		}),
+		ValidArgsFunction: completeNetworkNames(dockerCli, p),
+	}
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force the containers to disconnect from the network")
+	return cmd
+}
+
+func runNetworkDisconnect(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts networkDisconnectOptions, network string, services []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	return backend.NetworkDisconnect(ctx, projectName, api.NetworkDisconnectOptions{
+		Network:  network,
+		Services: services,
+		Force:    opts.force,
+	})
+}
+
+func networkRecreateCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "recreate NETWORK",
+		Short: "Recreate a project network to apply configuration changes",
+		Long: `Recreate a project network to apply configuration changes.
+
+Containers attached to the network are stopped, the network is recreated according to the
+compose file, then containers are reconnected and restarted, without a full down/up cycle.`,
+		Args: cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkRecreate(ctx, dockerCli, backendOptions, p, args[0])
+		}),
+		ValidArgsFunction: completeNetworkNames(dockerCli, p),
+	}
+}
+
+func runNetworkRecreate(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, p *ProjectOptions, network string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := p.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+	return backend.NetworkRecreate(ctx, project, api.NetworkRecreateOptions{
+		Network: network,
+	})
+}
+
+type networkDoctorOptions struct {
+	*ProjectOptions
+	image   string
+	timeout time.Duration
+}
+
+func networkDoctorCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := networkDoctorOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "doctor [OPTIONS] [SERVICE...]",
+		Short: "Check DNS resolution and connectivity between services",
+		Long: `Check DNS resolution and connectivity between services.
+
+For each project network, probes are run from every running service container to
+the other services attached to the same network, resolving their name and connecting
+to the ports they publish or expose. Results are reported as a matrix, followed by
+detected misconfigurations.`,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runNetworkDoctor(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.image, "image", compose.DefaultNetworkDoctorImage, "Image used to run probes, must provide nslookup and nc")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 2*time.Second, "Timeout for each connectivity probe")
+	return cmd
+}
+
+func runNetworkDoctor(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts networkDoctorOptions, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+
+	diagnosis, err := backend.NetworkDoctor(ctx, project, api.NetworkDoctorOptions{
+		Services: services,
+		Image:    opts.image,
+		Timeout:  opts.timeout,
+	})
+	if err != nil {
+		return err
+	}
+	printNetworkDiagnosis(dockerCli.Out(), diagnosis)
+	return nil
+}
+
+func printNetworkDiagnosis(out io.Writer, diagnosis api.NetworkDiagnosis) {
+	for i, report := range diagnosis.Networks {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		title := "NETWORK " + report.Network
+		if report.Internal {
+			title += " (internal)"
+		}
+		_, _ = fmt.Fprintln(out, title)
+
+		probes := map[string]api.NetworkProbe{}
+		for _, probe := range report.Probes {
+			probes[probe.From+"/"+probe.To] = probe
+		}
+		w := tabwriter.NewWriter(out, 4, 1, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FROM \\ TO\t"+strings.Join(report.Services, "\t"))
+		for _, from := range report.Services {
+			row := []string{from}
+			for _, to := range report.Services {
+				if from == to {
+					row = append(row, "-")
+					continue
+				}
+				row = append(row, probeStatus(probes[from+"/"+to]))
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		_ = w.Flush()
+	}
+
+	if len(diagnosis.Issues) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "ISSUES")
+		for _, issue := range diagnosis.Issues {
+			_, _ = fmt.Fprintln(out, " - "+issue)
+		}
+	}
+}
+
+func probeStatus(probe api.NetworkProbe) string {
+	switch {
+	case probe.From == "":
+		return "?"
+	case probe.Error != "":
+		return "n/a"
+	case len(probe.Addresses) == 0:
+		return "no dns"
+	}
+	var failed []string
+	for _, port := range probe.Ports {
+		if !port.Reachable {
+			failed = append(failed, fmt.Sprint(port.Port))
+		}
+	}
+	if len(failed) > 0 {
+		return "closed " + strings.Join(failed, ",")
+	}
+	return "ok"
+}
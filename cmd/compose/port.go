@@ -60,6 +60,7 @@ func portCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	}
 	cmd.Flags().StringVar(&opts.protocol, "protocol", "tcp", "tcp or udp")
 	cmd.Flags().IntVar(&opts.index, "index", 0, "Index of the container if service has multiple replicas")
+	cmd.RegisterFlagCompletionFunc("index", completeContainerIndexes(dockerCli, p)) //nolint:errcheck
 	return cmd
 }
 
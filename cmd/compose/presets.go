@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsFileName is the file defining named presets of command line flags, looked up in the project directory
+const SettingsFileName = "compose.settings.yaml"
+
+// settings is the content of SettingsFileName
+type settings struct {
+	// Presets maps a preset name to flags values, indexed by command (e.g. `up`, `alpha watch`) and flag name
+	Presets map[string]map[string]map[string]any `yaml:"presets"`
+}
+
+// settingsDir returns the directory to look up SettingsFileName, as project directory would be
+func settingsDir(opts ProjectOptions) string {
+	if opts.ProjectDir != "" {
+		return opts.ProjectDir
+	}
+	if len(opts.ConfigPaths) > 0 && opts.ConfigPaths[0] != "-" {
+		return filepath.Dir(opts.ConfigPaths[0])
+	}
+	return "."
+}
+
+func loadSettings(dir string) (*settings, error) {
+	file := filepath.Join(dir, SettingsFileName)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var s settings
+	if err := yaml.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return &s, nil
+}
+
+// applyPreset sets the flags defined by preset for the command being run, unless set on the command line
+func applyPreset(cmd *cobra.Command, composeCmd *cobra.Command, dir string, name string) error {
+	s, err := loadSettings(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("preset %q requires a %s file in %s", name, SettingsFileName, dir)
+	}
+	if err != nil {
+		return err
+	}
+	preset, ok := s.Presets[name]
+	if !ok {
+		return fmt.Errorf("no such preset %q, available presets: %s", name, strings.Join(slices.Sorted(maps.Keys(s.Presets)), ", "))
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), composeCmd.CommandPath()+" ")
+	flags := preset[command]
+	for _, flag := range slices.Sorted(maps.Keys(flags)) {
+		if cmd.Flags().Lookup(flag) == nil {
+			return fmt.Errorf("preset %q: unknown flag --%s for command %q", name, flag, command)
+		}
+		if cmd.Flags().Changed(flag) {
+			continue
+		}
+		var values []any
+		switch v := flags[flag].(type) {
+		case []any:
+			values = v
+		default:
+			values = []any{v}
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(flag, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("preset %q: invalid value for --%s: %w", name, flag, err)
+			}
+		}
+	}
+	return nil
+}
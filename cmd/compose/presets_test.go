@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyPreset(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, SettingsFileName), []byte(`
+presets:
+  ci:
+    up:
+      wait: true
+      pull: always
+      profile: [test, debug]
+  broken:
+    up:
+      unknown: true
+`), 0o600)
+	assert.NilError(t, err)
+
+	var (
+		wait     bool
+		pull     string
+		profiles []string
+	)
+	root := &cobra.Command{Use: PluginName}
+	up := &cobra.Command{Use: "up"}
+	up.Flags().BoolVar(&wait, "wait", false, "")
+	up.Flags().StringVar(&pull, "pull", "policy", "")
+	up.Flags().StringArrayVar(&profiles, "profile", nil, "")
+	root.AddCommand(up)
+
+	// flags set on command line take precedence
+	assert.NilError(t, up.Flags().Set("pull", "never"))
+	assert.NilError(t, applyPreset(up, root, dir, "ci"))
+	assert.Equal(t, wait, true)
+	assert.Equal(t, pull, "never")
+	assert.DeepEqual(t, profiles, []string{"test", "debug"})
+
+	err = applyPreset(up, root, dir, "broken")
+	assert.Error(t, err, `preset "broken": unknown flag --unknown for command "up"`)
+
+	err = applyPreset(up, root, dir, "missing")
+	assert.Error(t, err, `no such preset "missing", available presets: broken, ci`)
+
+	err = applyPreset(up, root, t.TempDir(), "ci")
+	assert.ErrorContains(t, err, "requires a compose.settings.yaml file")
+}
+
+func TestSettingsDir(t *testing.T) {
+	assert.Equal(t, settingsDir(ProjectOptions{ProjectDir: "/project"}), "/project")
+	assert.Equal(t, settingsDir(ProjectOptions{ConfigPaths: []string{"/project/compose.yaml"}}), "/project")
+	assert.Equal(t, settingsDir(ProjectOptions{ConfigPaths: []string{"-"}}), ".")
+	assert.Equal(t, settingsDir(ProjectOptions{}), ".")
+}
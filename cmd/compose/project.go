@@ -0,0 +1,442 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+// aliasRegistryFile is the user-level registry of project aliases, relative to docker config directory
+const aliasRegistryFile = "compose/projects.json"
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// projectAlias records the options to load a project from anywhere
+type projectAlias struct {
+	WorkingDir string   `json:"working_dir"`
+	Files      []string `json:"files,omitempty"`
+	EnvFiles   []string `json:"env_files,omitempty"`
+	Profiles   []string `json:"profiles,omitempty"`
+}
+
+// aliasRegistry is the content of aliasRegistryFile
+type aliasRegistry struct {
+	// Current is the alias selected by `compose project use`
+	Current  string                  `json:"current,omitempty"`
+	Projects map[string]projectAlias `json:"projects"`
+}
+
+func aliasRegistryPath() string {
+	return filepath.Join(config.Dir(), aliasRegistryFile)
+}
+
+// loadAliasRegistry reads registry from path, an empty registry is returned if file doesn't exist
+func loadAliasRegistry(path string) (*aliasRegistry, error) {
+	r := &aliasRegistry{Projects: map[string]projectAlias{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, r); err != nil {
+		return nil, fmt.Errorf("invalid project aliases registry %s: %w", path, err)
+	}
+	if r.Projects == nil {
+		r.Projects = map[string]projectAlias{}
+	}
+	return r, nil
+}
+
+func (r *aliasRegistry) save(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+func (r *aliasRegistry) get(name string) (projectAlias, error) {
+	alias, ok := r.Projects[name]
+	if !ok {
+		return projectAlias{}, fmt.Errorf("no such project alias %q, use `docker compose project add` to register it", name)
+	}
+	return alias, nil
+}
+
+// applyAlias sets project options from the alias selected by --project-alias, COMPOSE_PROJECT_ALIAS, or
+// `compose project use`. The latter only applies when no other project is selected, and no compose file can be
+// found from the working directory.
+func (o *ProjectOptions) applyAlias(path string) error {
+	if o.aliasApplied {
+		return nil
+	}
+	name := o.Alias
+	if name == "" {
+		name = os.Getenv(ComposeProjectAlias)
+	}
+	explicit := name != ""
+	if explicit && (len(o.ConfigPaths) > 0 || o.ProjectDir != "") {
+		return errors.New("a project alias can't be combined with --file or --project-directory")
+	}
+	if !explicit && o.selectsProject() {
+		return nil
+	}
+
+	registry, err := loadAliasRegistry(path)
+	if err != nil {
+		return err
+	}
+	if !explicit {
+		name = registry.Current
+		if name == "" {
+			return nil
+		}
+	}
+	alias, err := registry.get(name)
+	if err != nil {
+		return err
+	}
+	o.ProjectDir = alias.WorkingDir
+	o.ConfigPaths = alias.Files
+	if len(o.EnvFiles) == 0 {
+		o.EnvFiles = alias.EnvFiles
+	}
+	if len(o.Profiles) == 0 {
+		o.Profiles = alias.Profiles
+	}
+	o.aliasApplied = true
+	return nil
+}
+
+// selectsProject tells if a project is selected by command line flags, environment, or a compose file found
+// from the working directory
+func (o *ProjectOptions) selectsProject() bool {
+	if o.ProjectName != "" || len(o.ConfigPaths) > 0 || o.ProjectDir != "" {
+		return true
+	}
+	if os.Getenv(ComposeProjectName) != "" || os.Getenv("COMPOSE_FILE") != "" {
+		return true
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return true
+	}
+	for {
+		for _, f := range cli.DefaultFileNames {
+			if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// newProjectAlias records the project selected by options
+func newProjectAlias(o *ProjectOptions) (projectAlias, error) {
+	alias := projectAlias{
+		EnvFiles: o.EnvFiles,
+		Profiles: o.Profiles,
+	}
+	for _, f := range o.ConfigPaths {
+		if f == "-" {
+			return projectAlias{}, errors.New("a project alias can't be registered for a compose file read from stdin")
+		}
+		if !filepath.IsAbs(f) && !strings.Contains(f, "://") {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				return projectAlias{}, err
+			}
+			f = abs
+		}
+		alias.Files = append(alias.Files, f)
+	}
+	dir := o.ProjectDir
+	if dir == "" && len(alias.Files) > 0 && filepath.IsAbs(alias.Files[0]) {
+		dir = filepath.Dir(alias.Files[0])
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return projectAlias{}, err
+	}
+	alias.WorkingDir = dir
+	return alias, nil
+}
+
+func projectCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "project CMD [OPTIONS]",
+		Short:            "Manage project aliases, to run commands on a project from any directory",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		projectAddCommand(p, dockerCli, backendOptions),
+		projectListCommand(dockerCli),
+		projectUseCommand(dockerCli),
+		projectRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+type projectAddOptions struct {
+	*ProjectOptions
+	force bool
+}
+
+func projectAddCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := projectAddOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] ALIAS",
+		Short: "Register the project selected by --file, --project-directory, --env-file and --profile flags as ALIAS",
+		Example: `  # register project from current directory
+  docker compose project add shop
+  # register project with its compose files and profiles
+  docker compose -f ~/src/shop/compose.yaml -f ~/src/shop/compose.dev.yaml --profile debug project add shop
+  # run a command on the registered project
+  docker compose -P shop up -d`,
+		Args: cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runProjectAdd(ctx, dockerCli, backendOptions, opts, args[0])
+		}),
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Replace an existing alias")
+	return cmd
+}
+
+func runProjectAdd(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts projectAddOptions, name string) error {
+	if !aliasPattern.MatchString(name) {
+		return fmt.Errorf("invalid project alias %q, must match %s", name, aliasPattern)
+	}
+	alias, err := newProjectAlias(opts.ProjectOptions)
+	if err != nil {
+		return err
+	}
+
+	// check project can be loaded with the recorded options
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	check := *opts.ProjectOptions
+	check.ProjectDir = alias.WorkingDir
+	check.ConfigPaths = alias.Files
+	project, _, err := check.ToProject(ctx, dockerCli, backend, nil, cli.WithoutEnvironmentResolution)
+	if err != nil {
+		return err
+	}
+
+	path := aliasRegistryPath()
+	registry, err := loadAliasRegistry(path)
+	if err != nil {
+		return err
+	}
+	if _, exists := registry.Projects[name]; exists && !opts.force {
+		return fmt.Errorf("project alias %q already exists, use --force to replace it", name)
+	}
+	registry.Projects[name] = alias
+	if err := registry.save(path); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Project %q registered as %s\n", project.Name, name)
+	return nil
+}
+
+type projectListOptions struct {
+	format string
+	quiet  bool
+}
+
+func projectListCommand(dockerCli command.Cli) *cobra.Command {
+	opts := projectListOptions{}
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List project aliases",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runProjectList(dockerCli.Out(), aliasRegistryPath(), opts)
+		}),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noCompletion(),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Only display aliases")
+	return cmd
+}
+
+type projectAliasView struct {
+	Alias      string
+	Current    bool
+	WorkingDir string
+	Files      []string
+	EnvFiles   []string
+	Profiles   []string
+}
+
+func runProjectList(out io.Writer, path string, opts projectListOptions) error {
+	registry, err := loadAliasRegistry(path)
+	if err != nil {
+		return err
+	}
+	names := slices.Sorted(maps.Keys(registry.Projects))
+	if opts.quiet {
+		for _, name := range names {
+			_, _ = fmt.Fprintln(out, name)
+		}
+		return nil
+	}
+
+	view := make([]projectAliasView, len(names))
+	for i, name := range names {
+		alias := registry.Projects[name]
+		view[i] = projectAliasView{
+			Alias:      name,
+			Current:    name == registry.Current,
+			WorkingDir: alias.WorkingDir,
+			Files:      alias.Files,
+			EnvFiles:   alias.EnvFiles,
+			Profiles:   alias.Profiles,
+		}
+	}
+	return formatter.Print(view, opts.format, out, func(w io.Writer) {
+		for _, v := range view {
+			name := v.Alias
+			if v.Current {
+				name += " *"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, v.WorkingDir, strings.Join(v.Files, ","), strings.Join(v.Profiles, ","))
+		}
+	}, "ALIAS", "WORKING DIR", "FILES", "PROFILES")
+}
+
+type projectUseOptions struct {
+	unset bool
+}
+
+func projectUseCommand(dockerCli command.Cli) *cobra.Command {
+	opts := projectUseOptions{}
+	cmd := &cobra.Command{
+		Use: "use [OPTIONS] [ALIAS]",
+		Short: "Select the project to run commands on when no compose file is found from working directory, " +
+			"nor a project is selected by flags or environment",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runProjectUse(dockerCli.Out(), aliasRegistryPath(), opts, args)
+		}),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjectAliases(),
+	}
+	cmd.Flags().BoolVar(&opts.unset, "unset", false, "Unset the selected project")
+	return cmd
+}
+
+func runProjectUse(out io.Writer, path string, opts projectUseOptions, args []string) error {
+	if opts.unset == (len(args) == 1) {
+		return errors.New("either an alias or --unset must be set")
+	}
+	registry, err := loadAliasRegistry(path)
+	if err != nil {
+		return err
+	}
+	if opts.unset {
+		registry.Current = ""
+		return registry.save(path)
+	}
+	if _, err := registry.get(args[0]); err != nil {
+		return err
+	}
+	registry.Current = args[0]
+	if err := registry.save(path); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Using project %s\n", args[0])
+	return nil
+}
+
+func projectRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm ALIAS...",
+		Aliases: []string{"remove"},
+		Short:   "Remove project aliases",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runProjectRemove(dockerCli.Out(), aliasRegistryPath(), args)
+		}),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeProjectAliases(),
+	}
+}
+
+func runProjectRemove(out io.Writer, path string, names []string) error {
+	registry, err := loadAliasRegistry(path)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := registry.get(name); err != nil {
+			return err
+		}
+		delete(registry.Projects, name)
+		if registry.Current == name {
+			registry.Current = ""
+		}
+	}
+	if err := registry.save(path); err != nil {
+		return err
+	}
+	for _, name := range names {
+		_, _ = fmt.Fprintln(out, name)
+	}
+	return nil
+}
+
+// completeProjectAliases completes registered aliases
+func completeProjectAliases() validArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		registry, err := loadAliasRegistry(aliasRegistryPath())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(slices.Collect(maps.Keys(registry.Projects)), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeAliasRegistry(t *testing.T, registry aliasRegistry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "compose", "projects.json")
+	assert.NilError(t, registry.save(path))
+	return path
+}
+
+func TestApplyAlias(t *testing.T) {
+	path := writeAliasRegistry(t, aliasRegistry{Projects: map[string]projectAlias{
+		"shop": {
+			WorkingDir: "/src/shop",
+			Files:      []string{"/src/shop/compose.yaml", "/src/shop/compose.dev.yaml"},
+			Profiles:   []string{"debug"},
+		},
+	}})
+
+	opts := ProjectOptions{Alias: "shop", Profiles: []string{"tools"}}
+	assert.NilError(t, opts.applyAlias(path))
+	assert.Equal(t, opts.ProjectDir, "/src/shop")
+	assert.DeepEqual(t, opts.ConfigPaths, []string{"/src/shop/compose.yaml", "/src/shop/compose.dev.yaml"})
+	assert.DeepEqual(t, opts.Profiles, []string{"tools"})
+	// applying twice, as completion does, is a no-op
+	assert.NilError(t, opts.applyAlias(path))
+
+	opts = ProjectOptions{Alias: "shop", ConfigPaths: []string{"compose.yaml"}}
+	assert.Error(t, opts.applyAlias(path), "a project alias can't be combined with --file or --project-directory")
+
+	opts = ProjectOptions{Alias: "unknown"}
+	assert.ErrorContains(t, opts.applyAlias(path), `no such project alias "unknown"`)
+}
+
+func TestApplyAliasFromEnv(t *testing.T) {
+	path := writeAliasRegistry(t, aliasRegistry{Projects: map[string]projectAlias{
+		"shop": {WorkingDir: "/src/shop"},
+	}})
+	t.Setenv(ComposeProjectAlias, "shop")
+	opts := ProjectOptions{}
+	assert.NilError(t, opts.applyAlias(path))
+	assert.Equal(t, opts.ProjectDir, "/src/shop")
+}
+
+func TestApplyCurrentAlias(t *testing.T) {
+	path := writeAliasRegistry(t, aliasRegistry{
+		Current:  "shop",
+		Projects: map[string]projectAlias{"shop": {WorkingDir: "/src/shop"}},
+	})
+	t.Setenv(ComposeProjectName, "")
+	t.Setenv("COMPOSE_FILE", "")
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	opts := ProjectOptions{}
+	assert.NilError(t, opts.applyAlias(path))
+	assert.Equal(t, opts.ProjectDir, "/src/shop")
+
+	// a compose file in working directory has precedence over the current alias
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("services: {}"), 0o600))
+	opts = ProjectOptions{}
+	assert.NilError(t, opts.applyAlias(path))
+	assert.Equal(t, opts.ProjectDir, "")
+
+	opts = ProjectOptions{ProjectName: "other"}
+	assert.NilError(t, opts.applyAlias(path))
+	assert.Equal(t, opts.ProjectDir, "")
+}
+
+func TestNewProjectAlias(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	alias, err := newProjectAlias(&ProjectOptions{
+		ConfigPaths: []string{"sub/compose.yaml"},
+		Profiles:    []string{"debug"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, alias, projectAlias{
+		WorkingDir: filepath.Join(dir, "sub"),
+		Files:      []string{filepath.Join(dir, "sub", "compose.yaml")},
+		Profiles:   []string{"debug"},
+	})
+
+	_, err = newProjectAlias(&ProjectOptions{ConfigPaths: []string{"-"}})
+	assert.ErrorContains(t, err, "stdin")
+}
+
+func TestProjectUseAndRemove(t *testing.T) {
+	path := writeAliasRegistry(t, aliasRegistry{Projects: map[string]projectAlias{
+		"shop": {WorkingDir: "/src/shop", Files: []string{"/src/shop/compose.yaml"}},
+		"blog": {WorkingDir: "/src/blog"},
+	}})
+
+	var out bytes.Buffer
+	assert.NilError(t, runProjectUse(&out, path, projectUseOptions{}, []string{"shop"}))
+	assert.ErrorContains(t, runProjectUse(&out, path, projectUseOptions{}, []string{"unknown"}), "no such project alias")
+	assert.ErrorContains(t, runProjectUse(&out, path, projectUseOptions{unset: true}, []string{"shop"}), "either an alias or --unset")
+
+	out.Reset()
+	assert.NilError(t, runProjectList(&out, path, projectListOptions{format: "table"}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 3)
+	assert.DeepEqual(t, strings.Fields(lines[1]), []string{"blog", "/src/blog"})
+	assert.DeepEqual(t, strings.Fields(lines[2]), []string{"shop", "*", "/src/shop", "/src/shop/compose.yaml"})
+
+	out.Reset()
+	assert.NilError(t, runProjectRemove(&out, path, []string{"shop"}))
+	registry, err := loadAliasRegistry(path)
+	assert.NilError(t, err)
+	assert.Equal(t, registry.Current, "")
+	assert.DeepEqual(t, registry.Projects, map[string]projectAlias{"blog": {WorkingDir: "/src/blog"}})
+}
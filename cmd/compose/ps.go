@@ -17,17 +17,21 @@
 package compose
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"slices"
-	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/compose/v5/cmd/formatter"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/compose"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	cliformatter "github.com/docker/cli/cli/command/formatter"
 	cliflags "github.com/docker/cli/cli/flags"
@@ -43,7 +47,39 @@ type psOptions struct {
 	Filter   string
 	Status   []string
 	noTrunc  bool
-	Orphans  bool
+	Orphans  orphansOpt
+	Diff     bool
+	Sort     string
+}
+
+// orphansOpt is the value of the --orphans flag, which can be used as a boolean flag or set to "only"
+type orphansOpt struct {
+	include bool
+	only    bool
+}
+
+func (o *orphansOpt) String() string {
+	if o.only {
+		return "only"
+	}
+	return strconv.FormatBool(o.include)
+}
+
+func (o *orphansOpt) Set(value string) error {
+	if value == "only" {
+		o.include, o.only = true, true
+		return nil
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf(`invalid value %q, expected "true", "false" or "only"`, value)
+	}
+	o.include, o.only = include, false
+	return nil
+}
+
+func (o *orphansOpt) Type() string {
+	return "string"
 }
 
 func (p *psOptions) parseFilter() error {
@@ -68,6 +104,7 @@ func (p *psOptions) parseFilter() error {
 func psCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
 	opts := psOptions{
 		ProjectOptions: p,
+		Orphans:        orphansOpt{include: true},
 	}
 	psCmd := &cobra.Command{
 		Use:   "ps [OPTIONS] [SERVICE...]",
@@ -86,9 +123,12 @@ func psCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backend
 	flags.StringArrayVar(&opts.Status, "status", []string{}, "Filter services by status. Values: [paused | restarting | removing | running | dead | created | exited]")
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
 	flags.BoolVar(&opts.Services, "services", false, "Display services")
-	flags.BoolVar(&opts.Orphans, "orphans", true, "Include orphaned services (not declared by project)")
+	orphans := flags.VarPF(&opts.Orphans, "orphans", "", `Include orphaned services (not declared by project). Set to "only", or pass without value, to list only orphans with the reason why`)
+	orphans.NoOptDefVal = "only"
 	flags.BoolVarP(&opts.All, "all", "a", false, "Show all stopped containers (including those created by the run command)")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.BoolVar(&opts.Diff, "diff", false, "Show which aspects of service configuration changed since containers were created")
+	flags.StringVar(&opts.Sort, "sort", "name", "Sort containers, prefix with - for descending order. Values: [name | created | started | finished | health-since | restarts]")
 	return psCmd
 }
 
@@ -98,6 +138,14 @@ func runPs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOp
 		return err
 	}
 
+	if opts.Orphans.only {
+		return runPsOrphans(ctx, dockerCli, backendOptions, project, opts)
+	}
+
+	if opts.Diff && project == nil {
+		return errors.New("--diff requires the compose file to be loaded, to compare with containers configuration")
+	}
+
 	if project != nil {
 		names := project.ServiceNames()
 		if len(services) > 0 {
@@ -106,7 +154,7 @@ func runPs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOp
 					return fmt.Errorf("no such service: %s", service)
 				}
 			}
-		} else if !opts.Orphans {
+		} else if !opts.Orphans.include {
 			// until user asks to list orphaned services, we only include those declared in project
 			services = names
 		}
@@ -116,22 +164,31 @@ func runPs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOp
 	if err != nil {
 		return err
 	}
-	containers, err := backend.Ps(ctx, name, api.PsOptions{
-		Project:  project,
-		All:      opts.All || len(opts.Status) != 0,
-		Services: services,
+	all := opts.All || len(opts.Status) != 0
+	containers, stale, err := withStateCache(dockerCli, opts.Offline, stateCacheName(dockerCli.CurrentContext(), "ps", name, all, services), func() ([]api.ContainerSummary, error) {
+		return backend.Ps(ctx, name, api.PsOptions{
+			Project:  project,
+			All:      all,
+			Services: services,
+			Diff:     opts.Diff,
+		})
 	})
 	if err != nil {
 		return err
 	}
+	if stale {
+		for i := range containers {
+			containers[i].Status = strings.TrimSpace(containers[i].Status + " " + staleMarker)
+		}
+	}
 
 	if len(opts.Status) != 0 {
 		containers = filterByStatus(containers, opts.Status)
 	}
 
-	sort.Slice(containers, func(i, j int) bool {
-		return containers[i].Name < containers[j].Name
-	})
+	if err := sortContainers(containers, opts.Sort); err != nil {
+		return err
+	}
 
 	if opts.Quiet {
 		for _, c := range containers {
@@ -156,14 +213,87 @@ func runPs(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOp
 		opts.Format = dockerCli.ConfigFile().PsFormat
 	}
 
+	format := formatter.NewContainerFormat(opts.Format, opts.Quiet, false)
+	if opts.Diff && opts.Format == cliformatter.TableFormatKey {
+		format += `\t{{.Diff}}`
+	}
 	containerCtx := cliformatter.Context{
 		Output: dockerCli.Out(),
-		Format: formatter.NewContainerFormat(opts.Format, opts.Quiet, false),
+		Format: format,
 		Trunc:  !opts.noTrunc,
 	}
 	return formatter.ContainerWrite(containerCtx, containers)
 }
 
+func runPsOrphans(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, project *types.Project, opts psOptions) error {
+	if project == nil {
+		return errors.New("--orphans requires the compose file to be loaded, to compare with project containers")
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	orphans, err := backend.Orphans(ctx, project)
+	if err != nil {
+		return err
+	}
+	if opts.Quiet {
+		for _, o := range orphans {
+			_, _ = fmt.Fprintln(dockerCli.Out(), o.ID)
+		}
+		return nil
+	}
+	return formatter.Print(orphans, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, o := range orphans {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", o.Name, o.Service, o.State, o.Reason)
+			}
+		},
+		"NAME", "SERVICE", "STATE", "REASON")
+}
+
+// psSortKeys are the values of the --sort flag
+var psSortKeys = map[string]func(a, b api.ContainerSummary) int{
+	"name": func(a, b api.ContainerSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	"created": func(a, b api.ContainerSummary) int {
+		return cmp.Compare(a.Created, b.Created)
+	},
+	"started": func(a, b api.ContainerSummary) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	},
+	"finished": func(a, b api.ContainerSummary) int {
+		return a.FinishedAt.Compare(b.FinishedAt)
+	},
+	"health-since": func(a, b api.ContainerSummary) int {
+		return a.HealthSince.Compare(b.HealthSince)
+	},
+	"restarts": func(a, b api.ContainerSummary) int {
+		return cmp.Compare(a.RestartCount, b.RestartCount)
+	},
+}
+
+// sortContainers sorts containers by key, in descending order when prefixed by `-`. Ties are sorted by name.
+func sortContainers(containers []api.ContainerSummary, key string) error {
+	name, descending := strings.CutPrefix(key, "-")
+	compare, ok := psSortKeys[name]
+	if !ok {
+		return fmt.Errorf("invalid --sort %q, expected one of %s", key, strings.Join(slices.Sorted(maps.Keys(psSortKeys)), ", "))
+	}
+	slices.SortStableFunc(containers, func(a, b api.ContainerSummary) int {
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		if c == 0 {
+			c = strings.Compare(a.Name, b.Name)
+		}
+		return c
+	})
+	return nil
+}
+
 func filterByStatus(containers []api.ContainerSummary, statuses []string) []api.ContainerSummary {
 	var filtered []api.ContainerSummary
 	for _, c := range containers {
@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestSortContainers(t *testing.T) {
+	now := time.Now()
+	containers := []api.ContainerSummary{
+		{Name: "c", RestartCount: 3, StartedAt: now},
+		{Name: "a", RestartCount: 0, StartedAt: now.Add(-time.Hour)},
+		{Name: "b", RestartCount: 3, StartedAt: now.Add(-time.Minute)},
+	}
+	names := func() []string {
+		var n []string
+		for _, c := range containers {
+			n = append(n, c.Name)
+		}
+		return n
+	}
+
+	assert.NilError(t, sortContainers(containers, "name"))
+	assert.DeepEqual(t, names(), []string{"a", "b", "c"})
+	assert.NilError(t, sortContainers(containers, "-restarts"))
+	assert.DeepEqual(t, names(), []string{"b", "c", "a"})
+	assert.NilError(t, sortContainers(containers, "started"))
+	assert.DeepEqual(t, names(), []string{"a", "b", "c"})
+	assert.ErrorContains(t, sortContainers(containers, "uptime"), `invalid --sort "uptime", expected one of created, finished, health-since, name, restarts, started`)
+}
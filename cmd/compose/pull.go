@@ -28,6 +28,7 @@ import (
 	"github.com/morikuni/aec"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose/v5/cmd/prompt"
 	"github.com/docker/compose/v5/pkg/api"
 )
 
@@ -41,6 +42,7 @@ type pullOptions struct {
 	ignorePullFailures bool
 	noBuildable        bool
 	policy             string
+	interactive        bool
 }
 
 func pullCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -51,6 +53,9 @@ func pullCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 		Use:   "pull [OPTIONS] [SERVICE...]",
 		Short: "Pull service images",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkInteractive(opts.interactive, args); err != nil {
+				return err
+			}
 			if cmd.Flags().Changed("no-parallel") {
 				fmt.Fprint(os.Stderr, aec.Apply("option '--no-parallel' is DEPRECATED and will be ignored.\n", aec.RedF))
 			}
@@ -74,6 +79,7 @@ func pullCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	cmd.Flags().BoolVar(&opts.ignorePullFailures, "ignore-pull-failures", false, "Pull what it can and ignores images with pull failures")
 	cmd.Flags().BoolVar(&opts.noBuildable, "ignore-buildable", false, "Ignore images that can be built")
 	cmd.Flags().StringVar(&opts.policy, "policy", "", `Apply pull policy ("missing"|"always")`)
+	cmd.Flags().BoolVar(&opts.interactive, "interactive", false, interactiveFlagUsage)
 	return cmd
 }
 
@@ -109,6 +115,14 @@ func runPull(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		return err
 	}
 
+	if opts.interactive {
+		ui := prompt.NewPrompt(dockerCli.In(), dockerCli.Out())
+		services, err = selectServices(ctx, backend, ui, project, project.Name, "Select services to pull")
+		if err != nil {
+			return err
+		}
+	}
+
 	project, err = opts.apply(project, services)
 	if err != nil {
 		return err
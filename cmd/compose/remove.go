@@ -29,9 +29,10 @@ import (
 
 type removeOptions struct {
 	*ProjectOptions
-	force   bool
-	stop    bool
-	volumes bool
+	force    bool
+	stop     bool
+	volumes  bool
+	keepLast int
 }
 
 func removeCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -46,7 +47,16 @@ func removeCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 By default, anonymous volumes attached to containers will not be removed. You
 can override this with -v. To list all volumes, use "docker volume ls".
 
-Any data which is not in a volume will be lost.`,
+Any data which is not in a volume will be lost.
+
+With --keep-last, the given number of most recent replicas of each service
+are kept, which is useful to clean up scaled services.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.keepLast < 0 {
+				return fmt.Errorf("invalid --keep-last %d: must be positive", opts.keepLast)
+			}
+			return nil
+		},
 		RunE: Adapt(func(ctx context.Context, args []string) error {
 			return runRemove(ctx, dockerCli, backendOptions, opts, args)
 		}),
@@ -56,6 +66,7 @@ Any data which is not in a volume will be lost.`,
 	f.BoolVarP(&opts.force, "force", "f", false, "Don't ask to confirm removal")
 	f.BoolVarP(&opts.stop, "stop", "s", false, "Stop the containers, if required, before removing")
 	f.BoolVarP(&opts.volumes, "volumes", "v", false, "Remove any anonymous volumes attached to containers")
+	f.IntVar(&opts.keepLast, "keep-last", 0, "Keep the given number of containers with the highest replica number, for each service")
 	f.BoolP("all", "a", false, "Deprecated - no effect")
 	f.MarkHidden("all") //nolint:errcheck
 
@@ -78,6 +89,7 @@ func runRemove(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		Volumes:  opts.volumes,
 		Project:  project,
 		Stop:     opts.stop,
+		KeepLast: opts.keepLast,
 	})
 	if errors.Is(err, api.ErrNoResources) {
 		_, _ = fmt.Fprintln(stdinfo(dockerCli), "No stopped containers")
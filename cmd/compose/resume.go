@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func resumeCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [OPTIONS]",
+		Short: "Resume an interrupted up",
+		Long: `Resume an interrupted up.
+
+Up records completed steps (pulled and built images) in a journal. After an interruption,
+resume continues the operation with the same options, skipping steps already completed.
+Networks and volumes are checked again and recreated if missing. Services are started
+in the background.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runResume(ctx, dockerCli, backendOptions, p)
+		}),
+	}
+}
+
+func runResume(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts *ProjectOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+	return backend.Resume(ctx, project)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	composecli "github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/dotenv"
@@ -50,6 +51,7 @@ type runOptions struct {
 	envFiles      []string
 	Detach        bool
 	Remove        bool
+	removeAfter   time.Duration
 	noTty         bool
 	interactive   bool
 	user          string
@@ -69,6 +71,7 @@ type runOptions struct {
 	removeOrphans bool
 	quiet         bool
 	quietPull     bool
+	record        string
 }
 
 func (options runOptions) apply(project *types.Project) (*types.Project, error) {
@@ -171,6 +174,12 @@ func runCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backen
 			if len(options.publish) > 0 && options.servicePorts {
 				return fmt.Errorf("--service-ports and --publish are incompatible")
 			}
+			if options.Remove && options.removeAfter > 0 {
+				return fmt.Errorf("--rm and --rm-after are incompatible")
+			}
+			if options.record != "" && options.Detach {
+				return fmt.Errorf("--record and --detach are incompatible")
+			}
 			if cmd.Flags().Changed("entrypoint") {
 				command, err := shellwords.Parse(options.entrypoint)
 				if err != nil {
@@ -234,6 +243,7 @@ func runCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backen
 	flags.StringArrayVar(&options.envFiles, "env-from-file", []string{}, "Set environment variables from file")
 	flags.StringArrayVarP(&options.labels, "label", "l", []string{}, "Add or override a label")
 	flags.BoolVar(&options.Remove, "rm", false, "Automatically remove the container when it exits")
+	flags.DurationVar(&options.removeAfter, "rm-after", 0, "Remove the container, once stopped, after this duration (e.g. 1h) by up or down")
 	flags.BoolVarP(&options.noTty, "no-TTY", "T", !dockerCli.Out().IsTerminal(), "Disable pseudo-TTY allocation (default: auto-detected)")
 	flags.StringVar(&options.name, "name", "", "Assign a name to the container")
 	flags.StringVarP(&options.user, "user", "u", "", "Run as specified username or uid")
@@ -251,7 +261,9 @@ func runCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backen
 	flags.BoolVar(&buildOpts.quiet, "quiet-build", false, "Suppress progress output from the build process")
 	flags.BoolVar(&options.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&createOpts.Build, "build", false, "Build image before starting container")
+	flags.StringVar(&createOpts.platform, "platform", "", "Override service platform, used to both build and run image (e.g. linux/amd64)")
 	flags.BoolVar(&options.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
+	flags.StringVar(&options.record, "record", "", "Record the session to an asciicast file, with output timing and terminal resizes")
 
 	cmd.Flags().BoolVarP(&options.interactive, "interactive", "i", true, "Keep STDIN open even if not attached")
 	cmd.Flags().BoolVarP(&ttyFlag, "tty", "t", true, "Allocate a pseudo-TTY")
@@ -311,7 +323,7 @@ func runRun(ctx context.Context, backend api.Compose, project *types.Project, op
 	}
 
 	// start container and attach to container streams
-	runOpts := api.RunOptions{
+	runOpts := api.RunOneOffOptions{
 		CreateOptions: api.CreateOptions{
 			Build:         buildForRun,
 			RemoveOrphans: options.removeOrphans,
@@ -323,6 +335,7 @@ func runRun(ctx context.Context, backend api.Compose, project *types.Project, op
 		Command:           options.Command,
 		Detach:            options.Detach,
 		AutoRemove:        options.Remove,
+		RemoveAfter:       options.removeAfter,
 		Tty:               !options.noTty,
 		Interactive:       options.interactive,
 		WorkingDir:        options.workdir,
@@ -334,7 +347,7 @@ func runRun(ctx context.Context, backend api.Compose, project *types.Project, op
 		Labels:            labels,
 		UseNetworkAliases: options.useAliases,
 		NoDeps:            options.noDeps,
-		Index:             0,
+		Record:            options.record,
 	}
 
 	for name, service := range project.Services {
@@ -344,7 +357,7 @@ func runRun(ctx context.Context, backend api.Compose, project *types.Project, op
 		}
 	}
 
-	exitCode, err := backend.RunOneOffContainer(ctx, project, runOpts)
+	exitCode, err := backend.RunOneOff(ctx, project, runOpts)
 	if exitCode != 0 {
 		errMsg := ""
 		if err != nil {
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
@@ -33,7 +34,9 @@ import (
 
 type scaleOptions struct {
 	*ProjectOptions
-	noDeps bool
+	noDeps    bool
+	scaleDown string
+	indexes   []int
 }
 
 func scaleCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -55,11 +58,28 @@ func scaleCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	}
 	flags := scaleCmd.Flags()
 	flags.BoolVar(&opts.noDeps, "no-deps", false, "Don't start linked services")
+	flags.StringVar(&opts.scaleDown, "scale-down", "", "Replicas removed first when scaling down. Values: [oldest | newest | unhealthy-first]")
+	flags.IntSliceVar(&opts.indexes, "index", nil, "Index of a replica to remove when scaling down a single service")
 
 	return scaleCmd
 }
 
 func runScale(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts scaleOptions, serviceReplicaTuples map[string]int) error {
+	scaleDown := api.ScaleDownOptions{Policy: api.ScaleDownPolicy(opts.scaleDown)}
+	switch scaleDown.Policy {
+	case api.ScaleDownDefault, api.ScaleDownOldest, api.ScaleDownNewest, api.ScaleDownUnhealthyFirst:
+	default:
+		return fmt.Errorf("invalid --scale-down %q, expected one of %s, %s or %s", opts.scaleDown, api.ScaleDownOldest, api.ScaleDownNewest, api.ScaleDownUnhealthyFirst)
+	}
+	if len(opts.indexes) > 0 {
+		if len(serviceReplicaTuples) != 1 {
+			return errors.New("--index requires a single service to be scaled")
+		}
+		for service := range serviceReplicaTuples {
+			scaleDown.Indexes = map[string][]int{service: opts.indexes}
+		}
+	}
+
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
@@ -86,7 +106,7 @@ func runScale(ctx context.Context, dockerCli command.Cli, backendOptions *Backen
 		project.Services[key] = service
 	}
 
-	return backend.Scale(ctx, project, api.ScaleOptions{Services: services})
+	return backend.Scale(ctx, project, api.ScaleOptions{Services: services, ScaleDown: scaleDown})
 }
 
 func parseServicesReplicasArgs(args []string) (map[string]int, error) {
@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func securityCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "security CMD [OPTIONS]",
+		Short:            "Audit project security settings",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		securityAuditCommand(p, dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type securityAuditOptions struct {
+	*ProjectOptions
+	format string
+}
+
+func securityAuditCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := securityAuditOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "audit [OPTIONS] [SERVICE...]",
+		Short: "Report privileged mode, added capabilities, host namespaces and other insecure settings, with a security score",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runSecurityAudit(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runSecurityAudit(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts securityAuditOptions, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, services)
+	if err != nil {
+		return err
+	}
+
+	report, err := backend.SecurityAudit(ctx, project, api.SecurityAuditOptions{
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case formatter.JSON:
+		out, err := formatter.ToJSON(report, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(dockerCli.Out(), out)
+		return nil
+	case formatter.TABLE, "":
+		err = formatter.Print(report.Findings, formatter.TABLE, dockerCli.Out(),
+			func(w io.Writer) {
+				for _, f := range report.Findings {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Service, f.Severity, f.Check, f.Message, f.Remediation)
+				}
+			},
+			"SERVICE", "SEVERITY", "CHECK", "FINDING", "REMEDIATION")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCli.Out(), "\nSecurity score: %d/100\n", report.Score)
+		return nil
+	default:
+		return fmt.Errorf("format value %q could not be parsed: %w", opts.format, api.ErrParsingFailed)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/cmd/prompt"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const interactiveFlagUsage = "Select services to apply the command to, rather than all of them"
+
+// checkInteractive rejects services passed as arguments while services are to be selected interactively
+func checkInteractive(interactive bool, services []string) error {
+	if interactive && len(services) > 0 {
+		return errors.New("--interactive can't be used with services passed as arguments")
+	}
+	return nil
+}
+
+// selectServices lets user pick the services a command applies to, listed with the state of their containers.
+// Services are read from the project model when available, from containers otherwise.
+func selectServices(ctx context.Context, backend api.Compose, ui prompt.UI, project *types.Project, projectName string, message string) ([]string, error) {
+	containers, err := backend.Ps(ctx, projectName, api.PsOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	states := map[string][]string{}
+	for _, c := range containers {
+		if !slices.Contains(states[c.Service], c.State) {
+			states[c.Service] = append(states[c.Service], c.State)
+		}
+	}
+
+	var services []string
+	if project != nil {
+		services = project.ServiceNames()
+	} else {
+		services = slices.Collect(maps.Keys(states))
+	}
+	slices.Sort(services)
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no service found in project %q", projectName)
+	}
+
+	options := make([]string, len(services))
+	for i, service := range services {
+		state := "not created"
+		if s := states[service]; len(s) > 0 {
+			state = strings.Join(s, ", ")
+		}
+		options[i] = fmt.Sprintf("%s (%s)", service, state)
+	}
+	selected, err := ui.MultiSelect(message, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no service selected")
+	}
+	var result []string
+	for _, s := range selected {
+		if i := slices.Index(options, s); i >= 0 {
+			result = append(result, services[i])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/cmd/prompt"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func TestSelectServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(ctrl)
+	ui := prompt.NewMockUI(ctrl)
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"web": {Name: "web"},
+			"db":  {Name: "db"},
+		},
+	}
+	backend.EXPECT().Ps(gomock.Any(), "myproject", api.PsOptions{All: true}).Return([]api.ContainerSummary{
+		{Service: "web", State: "running"},
+		{Service: "web", State: "exited"},
+	}, nil)
+	ui.EXPECT().MultiSelect("Select services", []string{"db (not created)", "web (running, exited)"}).
+		Return([]string{"web (running, exited)"}, nil)
+
+	services, err := selectServices(t.Context(), backend, ui, project, "myproject", "Select services")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, services, []string{"web"})
+}
+
+func TestSelectServicesWithoutProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(ctrl)
+	ui := prompt.NewMockUI(ctrl)
+	backend.EXPECT().Ps(gomock.Any(), "myproject", api.PsOptions{All: true}).Return([]api.ContainerSummary{
+		{Service: "web", State: "running"},
+	}, nil)
+	ui.EXPECT().MultiSelect(gomock.Any(), []string{"web (running)"}).Return(nil, nil)
+
+	_, err := selectServices(t.Context(), backend, ui, nil, "myproject", "Select services")
+	assert.Error(t, err, "no service selected")
+}
+
+func TestCheckInteractive(t *testing.T) {
+	assert.NilError(t, checkInteractive(true, nil))
+	assert.NilError(t, checkInteractive(false, []string{"web"}))
+	assert.Error(t, checkInteractive(true, []string{"web"}), "--interactive can't be used with services passed as arguments")
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+	"github.com/docker/compose/v5/pkg/server"
+)
+
+type serveOptions struct {
+	listen string
+}
+
+func serveCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := serveOptions{}
+	cmd := &cobra.Command{
+		Use:   "serve [OPTIONS]",
+		Short: "Serve the Compose API over gRPC",
+		Long: `Serve the Compose API over gRPC, so tools can drive Compose without running a command per operation.
+
+Progress, logs and events are streamed to clients. Only unix sockets are supported,
+access is restricted to the current user by socket file permissions.`,
+		Args: cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runServe(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	cmd.Flags().StringVar(&opts.listen, "listen", "", "Address to listen on, as unix://<path>. Defaults to a socket in the Docker config directory")
+	return cmd
+}
+
+func defaultServeAddress() string {
+	return "unix://" + filepath.Join(config.Dir(), "run", "compose.sock")
+}
+
+// newServer creates a server running each request with a dedicated backend, reporting progress to the client
+func newServer(dockerCli command.Cli, backendOptions *BackendOptions, extra ...compose.Option) *server.Server {
+	return server.NewServer(func(events api.EventProcessor) (api.Compose, error) {
+		options := append(append([]compose.Option{}, backendOptions.Options...), extra...)
+		options = append(options, compose.WithEventProcessor(events))
+		return compose.NewComposeService(dockerCli, options...)
+	})
+}
+
+func runServe(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts serveOptions) error {
+	if opts.listen == "" {
+		opts.listen = defaultServeAddress()
+	}
+	srv := newServer(dockerCli, backendOptions)
+	_, _ = fmt.Fprintf(dockerCli.Err(), "Serving Compose API on %s\n", opts.listen)
+	return srv.Serve(ctx, opts.listen)
+}
+
+func runJSONRPC(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions) error {
+	// stdin and stdout carry the protocol, so prompts are reported on stderr and answered with their default
+	srv := newServer(dockerCli, backendOptions, compose.WithPrompt(stderrPrompt(dockerCli.Err())))
+	return srv.ServeJSONRPC(ctx, dockerCli.In(), dockerCli.Out())
+}
+
+// stderrPrompt writes prompt message to w, and answers with the default value without reading user input
+func stderrPrompt(w io.Writer) compose.Prompt {
+	return func(message string, defaultValue bool) (bool, error) {
+		_, _ = fmt.Fprintln(w, strings.TrimSpace(message))
+		return defaultValue, nil
+	}
+}
@@ -0,0 +1,172 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/utils"
+)
+
+// stateCacheDirectory stores the last state reported by read-only commands, relative to docker config directory.
+// State is only recorded when COMPOSE_STATE_CACHE is set. With --offline, it is used when the engine is unreachable,
+// for example during a daemon restart.
+const stateCacheDirectory = "compose/state"
+
+const (
+	// stateCacheMaxAge is the age after which cached state is ignored and removed
+	stateCacheMaxAge = 24 * time.Hour
+	// stateCacheMaxEntries is the number of cache files kept, oldest ones being removed first
+	stateCacheMaxEntries = 32
+)
+
+// staleMarker is appended to status of resources reported from cached state
+const staleMarker = "(cached)"
+
+// cachedState is the content of a state cache file
+type cachedState[T any] struct {
+	Updated time.Time `json:"updated"`
+	Value   T         `json:"value"`
+}
+
+// stateCacheName identifies the cached result of a query on project resources, as reported by the engine selected by
+// Docker context. "@" can't be used in a context name, so distinct contexts never share a cache entry
+func stateCacheName(contextName string, kind string, projectName string, all bool, services []string) string {
+	name := contextName + "@" + kind
+	if projectName != "" {
+		name += "-" + projectName
+	}
+	if all {
+		name += "-all"
+	}
+	if len(services) > 0 {
+		name += "-" + strings.Join(slices.Sorted(slices.Values(services)), "+")
+	}
+	return name
+}
+
+func stateCachePath(name string) string {
+	return filepath.Join(config.Dir(), stateCacheDirectory, name+".json")
+}
+
+// withStateCache runs a read-only query, recording result in state cache if COMPOSE_STATE_CACHE is set. When engine
+// is unreachable and offline is set, the last recorded state is returned instead, with a warning telling how old it is.
+func withStateCache[T any](dockerCli command.Cli, offline bool, name string, query func() (T, error)) (T, bool, error) {
+	path := stateCachePath(name)
+	value, err := query()
+	if err == nil {
+		if utils.StringToBool(os.Getenv(ComposeStateCache)) {
+			if err := saveCachedState(path, value); err != nil {
+				logrus.Debugf("failed to record state to %s: %v", path, err)
+			}
+		}
+		return value, false, nil
+	}
+	if !offline || !isEngineUnreachable(err) {
+		return value, false, err
+	}
+	cached, cacheErr := loadCachedState[T](path)
+	if cacheErr != nil {
+		return value, false, fmt.Errorf("%w, and no cached state is available", err)
+	}
+	if time.Since(cached.Updated) > stateCacheMaxAge {
+		_ = os.Remove(path)
+		return value, false, fmt.Errorf("%w, and cached state is older than %s", err, units.HumanDuration(stateCacheMaxAge))
+	}
+	_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: Docker engine is unreachable, showing state cached %s ago (%s)\n",
+		units.HumanDuration(time.Since(cached.Updated)), cached.Updated.Format(time.RFC3339))
+	return cached.Value, true, nil
+}
+
+func isEngineUnreachable(err error) bool {
+	return client.IsErrConnectionFailed(err)
+}
+
+func saveCachedState[T any](path string, value T) error {
+	content, err := json.Marshal(cachedState[T]{Updated: time.Now(), Value: value})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
+	}
+	return pruneStateCache(filepath.Dir(path))
+}
+
+// pruneStateCache removes cache files older than stateCacheMaxAge, then the oldest ones exceeding stateCacheMaxEntries
+func pruneStateCache(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	type cacheFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []cacheFile
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if time.Since(info.ModTime()) > stateCacheMaxAge {
+			errs = append(errs, os.Remove(path))
+			continue
+		}
+		files = append(files, cacheFile{path: path, modTime: info.ModTime()})
+	}
+	if len(files) > stateCacheMaxEntries {
+		slices.SortFunc(files, func(a, b cacheFile) int {
+			return b.modTime.Compare(a.modTime)
+		})
+		for _, f := range files[stateCacheMaxEntries:] {
+			errs = append(errs, os.Remove(f.path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func loadCachedState[T any](path string) (cachedState[T], error) {
+	var cached cachedState[T]
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cached, err
+	}
+	err = json.Unmarshal(content, &cached)
+	return cached, err
+}
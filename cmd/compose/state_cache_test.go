@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func TestWithStateCache(t *testing.T) {
+	previous := config.Dir()
+	config.SetDir(t.TempDir())
+	t.Cleanup(func() {
+		config.SetDir(previous)
+	})
+
+	mockCtrl := gomock.NewController(t)
+	cli := mocks.NewMockCli(mockCtrl)
+	stderr := &bytes.Buffer{}
+	cli.EXPECT().Err().Return(streams.NewOut(stderr)).AnyTimes()
+
+	name := stateCacheName("default", "ps", "test", false, nil)
+	value, stale, err := withStateCache(cli, true, name, func() ([]string, error) {
+		return []string{"web", "db"}, nil
+	})
+	assert.NilError(t, err)
+	assert.Check(t, !stale)
+	assert.DeepEqual(t, value, []string{"web", "db"})
+	_, err = os.Stat(stateCachePath(name))
+	assert.Check(t, os.IsNotExist(err), "state must not be recorded unless %s is set", ComposeStateCache)
+
+	t.Setenv(ComposeStateCache, "1")
+	_, _, err = withStateCache(cli, true, name, func() ([]string, error) {
+		return []string{"web", "db"}, nil
+	})
+	assert.NilError(t, err)
+
+	apiClient, err := client.NewClientWithOpts(client.WithHost("unix:///nonexistent/docker.sock"))
+	assert.NilError(t, err)
+	unreachable := func() ([]string, error) {
+		_, err := apiClient.Ping(context.TODO())
+		return nil, err
+	}
+
+	_, _, err = withStateCache(cli, false, name, unreachable)
+	assert.Check(t, isEngineUnreachable(err))
+
+	value, stale, err = withStateCache(cli, true, name, unreachable)
+	assert.NilError(t, err)
+	assert.Check(t, stale)
+	assert.DeepEqual(t, value, []string{"web", "db"})
+	assert.Check(t, bytes.Contains(stderr.Bytes(), []byte("Docker engine is unreachable")))
+
+	_, _, err = withStateCache(cli, true, stateCacheName("default", "ps", "other", false, nil), unreachable)
+	assert.ErrorContains(t, err, "no cached state is available")
+
+	_, _, err = withStateCache(cli, true, name, func() ([]string, error) {
+		return nil, errors.New("boom")
+	})
+	assert.Error(t, err, "boom")
+
+	content, err := json.Marshal(cachedState[[]string]{Updated: time.Now().Add(-2 * stateCacheMaxAge)})
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(stateCachePath(name), content, 0o600))
+	_, _, err = withStateCache(cli, true, name, unreachable)
+	assert.ErrorContains(t, err, "cached state is older than")
+}
+
+func TestStateCacheNameContext(t *testing.T) {
+	previous := config.Dir()
+	config.SetDir(t.TempDir())
+	t.Cleanup(func() {
+		config.SetDir(previous)
+	})
+	t.Setenv(ComposeStateCache, "1")
+
+	mockCtrl := gomock.NewController(t)
+	cli := mocks.NewMockCli(mockCtrl)
+	cli.EXPECT().Err().Return(streams.NewOut(&bytes.Buffer{})).AnyTimes()
+
+	local := stateCacheName("default", "ps", "test", false, nil)
+	remote := stateCacheName("remote", "ps", "test", false, nil)
+	assert.Check(t, local != remote)
+	assert.Equal(t, stateCacheName("default", "ls", "", true, nil), "default@ls-all")
+
+	_, _, err := withStateCache(cli, true, local, func() ([]string, error) {
+		return []string{"web"}, nil
+	})
+	assert.NilError(t, err)
+
+	_, _, err = withStateCache(cli, true, remote, func() ([]string, error) {
+		return nil, client.ErrorConnectionFailed("tcp://remote:2376")
+	})
+	assert.ErrorContains(t, err, "no cached state is available")
+}
+
+func TestPruneStateCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i := range stateCacheMaxEntries + 2 {
+		path := filepath.Join(dir, fmt.Sprintf("ps-%d.json", i))
+		assert.NilError(t, os.WriteFile(path, []byte("{}"), 0o600))
+		modTime := now.Add(-time.Duration(i) * time.Minute)
+		assert.NilError(t, os.Chtimes(path, modTime, modTime))
+	}
+	expired := filepath.Join(dir, "ls-expired.json")
+	assert.NilError(t, os.WriteFile(expired, []byte("{}"), 0o600))
+	assert.NilError(t, os.Chtimes(expired, now.Add(-2*stateCacheMaxAge), now.Add(-2*stateCacheMaxAge)))
+
+	assert.NilError(t, pruneStateCache(dir))
+
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), stateCacheMaxEntries)
+	_, err = os.Stat(expired)
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, fmt.Sprintf("ps-%d.json", stateCacheMaxEntries+1)))
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "ps-0.json"))
+	assert.NilError(t, err)
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+
+	"github.com/docker/compose/v5/cmd/display"
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const timingsFlagUsage = "Print a summary of time spent on each service by phase"
+
+// timingsColumn is a phase reported by the summary of an operation
+type timingsColumn struct {
+	header string
+	value  func(api.ServiceTimings) time.Duration
+}
+
+var timingsColumns = map[string][]timingsColumn{
+	"up": {
+		{"PULL", func(t api.ServiceTimings) time.Duration { return t.Pull }},
+		{"BUILD", func(t api.ServiceTimings) time.Duration { return t.Build }},
+		{"CREATE", func(t api.ServiceTimings) time.Duration { return t.Create }},
+		{"START", func(t api.ServiceTimings) time.Duration { return t.Start }},
+		{"HEALTH WAIT", func(t api.ServiceTimings) time.Duration { return t.HealthWait }},
+	},
+	"down": {
+		{"STOP", func(t api.ServiceTimings) time.Duration { return t.Stop }},
+		{"REMOVE", func(t api.ServiceTimings) time.Duration { return t.Remove }},
+	},
+}
+
+// serviceTimings is the JSON representation of api.ServiceTimings, with durations in seconds
+type serviceTimings struct {
+	Service    string  `json:"service"`
+	Pull       float64 `json:"pull,omitempty"`
+	Build      float64 `json:"build,omitempty"`
+	Create     float64 `json:"create,omitempty"`
+	Start      float64 `json:"start,omitempty"`
+	HealthWait float64 `json:"health_wait,omitempty"`
+	Stop       float64 `json:"stop,omitempty"`
+	Remove     float64 `json:"remove,omitempty"`
+	Total      float64 `json:"total"`
+}
+
+// timingsSummary collects services timings reported as an operation completes, see compose.WithServiceTimings
+type timingsSummary struct {
+	operation string
+	timings   []api.ServiceTimings
+}
+
+func (s *timingsSummary) hook(operation string, timings []api.ServiceTimings) {
+	s.operation = operation
+	s.timings = timings
+}
+
+// print writes services timings, slowest first, as a table or as JSON when progress is reported as JSON
+func (s *timingsSummary) print(dockerCli command.Cli) error {
+	columns, ok := timingsColumns[s.operation]
+	if !ok || len(s.timings) == 0 {
+		return nil
+	}
+	timings := slices.Clone(s.timings)
+	slices.SortStableFunc(timings, func(a, b api.ServiceTimings) int {
+		return cmp.Compare(b.Total(), a.Total())
+	})
+
+	format := formatter.TABLE
+	if display.Mode == display.ModeJSON {
+		format = formatter.JSON
+	}
+	headers := []string{"SERVICE"}
+	for _, c := range columns {
+		headers = append(headers, c.header)
+	}
+	headers = append(headers, "TOTAL")
+	return formatter.Print(toServiceTimings(timings), format, stdinfo(dockerCli), func(w io.Writer) {
+		for _, t := range timings {
+			values := []string{t.Service}
+			for _, c := range columns {
+				values = append(values, formatTiming(c.value(t)))
+			}
+			values = append(values, formatTiming(t.Total()))
+			_, _ = fmt.Fprintln(w, strings.Join(values, "\t"))
+		}
+	}, headers...)
+}
+
+func toServiceTimings(timings []api.ServiceTimings) []serviceTimings {
+	result := make([]serviceTimings, 0, len(timings))
+	for _, t := range timings {
+		result = append(result, serviceTimings{
+			Service:    t.Service,
+			Pull:       t.Pull.Seconds(),
+			Build:      t.Build.Seconds(),
+			Create:     t.Create.Seconds(),
+			Start:      t.Start.Seconds(),
+			HealthWait: t.HealthWait.Seconds(),
+			Stop:       t.Stop.Seconds(),
+			Remove:     t.Remove.Seconds(),
+			Total:      t.Total().Seconds(),
+		})
+	}
+	return result
+}
+
+func formatTiming(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
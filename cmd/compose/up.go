@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/cmd/display"
@@ -56,12 +57,15 @@ type upOptions struct {
 	attachDependencies    bool
 	attach                []string
 	noAttach              []string
-	timestamp             bool
+	timestamps            timestampsOpt
 	wait                  bool
 	waitTimeout           int
 	watch                 bool
 	navigationMenu        bool
 	navigationMenuChanged bool
+	quietCreate           bool
+	syncHosts             bool
+	timings               bool
 }
 
 func (opts upOptions) apply(project *types.Project, services []string) (*types.Project, error) {
@@ -124,6 +128,9 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backend
 			if !cmd.Flags().Changed("remove-orphans") {
 				create.removeOrphans = utils.StringToBool(os.Getenv(ComposeRemoveOrphans))
 			}
+			if !cmd.Flags().Changed("sync-hosts") {
+				up.syncHosts = utils.StringToBool(os.Getenv(ComposeSyncHosts))
+			}
 			return validateFlags(&up, &create)
 		}),
 		RunE: p.WithServices(dockerCli, func(ctx context.Context, project *types.Project, services []string) error {
@@ -156,17 +163,24 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backend
 	flags.BoolVar(&up.noPrefix, "no-log-prefix", false, "Don't print prefix in logs")
 	flags.BoolVar(&create.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed")
 	flags.BoolVar(&create.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.StringArrayVar(&create.noRecreateFor, "no-recreate-for", []string{}, "If SERVICE containers already exist, don't recreate them")
+	flags.StringVar(&create.platform, "platform", "", "Override services platform, used to both build and run images (e.g. linux/amd64)")
+	flags.StringArrayVar(&create.recreatePolicies, "recreate-policy", []string{}, `Set recreate policy for a service as SERVICE=POLICY ("force"|"never"|"if-changed")`)
 	flags.BoolVar(&up.noStart, "no-start", false, "Don't start the services after creating them")
 	flags.BoolVar(&up.cascadeStop, "abort-on-container-exit", false, "Stops all containers if any container was stopped. Incompatible with -d")
 	flags.BoolVar(&up.cascadeFail, "abort-on-container-failure", false, "Stops all containers if any container exited with failure. Incompatible with -d")
 	flags.StringVar(&up.exitCodeFrom, "exit-code-from", "", "Return the exit code of the selected service container. Implies --abort-on-container-exit")
 	flags.IntVarP(&create.timeout, "timeout", "t", 0, "Use this timeout in seconds for container shutdown when attached or when containers are already running")
-	flags.BoolVar(&up.timestamp, "timestamps", false, "Show timestamps")
+	addTimestampsFlag(flags, &up.timestamps, "")
 	flags.BoolVar(&up.noDeps, "no-deps", false, "Don't start linked services")
 	flags.BoolVar(&create.recreateDeps, "always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
 	flags.BoolVarP(&create.noInherit, "renew-anon-volumes", "V", false, "Recreate anonymous volumes instead of retrieving data from the previous containers")
+	flags.BoolVar(&create.preserveAnon, "preserve-anon-volumes", false, "Re-attach all anonymous volumes of the previous containers, even those no longer declared")
 	flags.BoolVar(&create.quietPull, "quiet-pull", false, "Pull without printing progress information")
+	flags.BoolVar(&create.noBindChecks, "no-bind-checks", false, "Don't validate bind mounts sources before creating containers")
 	flags.BoolVar(&build.quiet, "quiet-build", false, "Suppress the build output")
+	flags.BoolVar(&up.quietCreate, "quiet-create", false, "Don't report progress of containers, networks and volumes creation")
+	flags.BoolVar(&up.syncHosts, "sync-hosts", false, "Add hosts file entries for hostnames declared by services with x-hostnames, removed by down. May require administrator privileges")
 	flags.StringArrayVar(&up.attach, "attach", []string{}, "Restrict attaching to the specified services. Incompatible with --attach-dependencies.")
 	flags.StringArrayVar(&up.noAttach, "no-attach", []string{}, "Do not attach (stream logs) to the specified services")
 	flags.BoolVar(&up.attachDependencies, "attach-dependencies", false, "Automatically attach to log output of dependent services")
@@ -174,6 +188,7 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backend
 	flags.IntVar(&up.waitTimeout, "wait-timeout", 0, "Maximum duration in seconds to wait for the project to be running|healthy")
 	flags.BoolVarP(&up.watch, "watch", "w", false, "Watch source code and rebuild/refresh containers when files are updated.")
 	flags.BoolVar(&up.navigationMenu, "menu", false, "Enable interactive shortcuts when running attached. Incompatible with --detach. Can also be enable/disable by setting COMPOSE_MENU environment var.")
+	flags.BoolVar(&up.timings, "timings", false, timingsFlagUsage+". Requires detached mode")
 	flags.BoolVarP(&create.AssumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		// assumeYes was introduced by mistake as `--y`
@@ -200,6 +215,9 @@ func validateFlags(up *upOptions, create *createOptions) error {
 		}
 		up.Detach = true
 	}
+	if up.timings && (!up.Detach || up.noStart) {
+		return fmt.Errorf("--timings requires --detach or --wait, and can't be combined with --no-start")
+	}
 	if create.Build && create.noBuild {
 		return fmt.Errorf("--build and --no-build are incompatible")
 	}
@@ -213,6 +231,9 @@ func validateFlags(up *upOptions, create *createOptions) error {
 	if create.noInherit && create.noRecreate {
 		return fmt.Errorf("--no-recreate and --renew-anon-volumes are incompatible")
 	}
+	if create.noInherit && create.preserveAnon {
+		return fmt.Errorf("--renew-anon-volumes and --preserve-anon-volumes are incompatible")
+	}
 	if create.forceRecreate && create.noRecreate {
 		return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
 	}
@@ -236,6 +257,8 @@ func runUp(
 	project *types.Project,
 	services []string,
 ) error {
+	// start of the run, used as reference for relative timestamps
+	start := time.Now()
 	if err := checksForRemoteStack(ctx, dockerCli, project, buildOptions, createOptions.AssumeYes, []string{}); err != nil {
 		return err
 	}
@@ -268,22 +291,35 @@ func runUp(
 		build = &bo
 	}
 
+	recreateServices, err := createOptions.recreateServices(project)
+	if err != nil {
+		return err
+	}
+
 	create := api.CreateOptions{
-		Build:                build,
-		Services:             services,
-		RemoveOrphans:        createOptions.removeOrphans,
-		IgnoreOrphans:        createOptions.ignoreOrphans,
-		Recreate:             createOptions.recreateStrategy(),
-		RecreateDependencies: createOptions.dependenciesRecreateStrategy(),
-		Inherit:              !createOptions.noInherit,
-		Timeout:              createOptions.GetTimeout(),
-		QuietPull:            createOptions.quietPull,
+		Build:                    build,
+		Services:                 services,
+		RemoveOrphans:            createOptions.removeOrphans,
+		IgnoreOrphans:            createOptions.ignoreOrphans,
+		Recreate:                 createOptions.recreateStrategy(),
+		RecreateDependencies:     createOptions.dependenciesRecreateStrategy(),
+		RecreateServices:         recreateServices,
+		Inherit:                  !createOptions.noInherit,
+		PreserveAnonymousVolumes: createOptions.preserveAnon,
+		Timeout:                  createOptions.GetTimeout(),
+		QuietPull:                createOptions.quietPull,
+		SkipBindChecks:           createOptions.noBindChecks,
 	}
 
 	if createOptions.AssumeYes {
 		backendOptions.Options = append(backendOptions.Options, compose.WithPrompt(compose.AlwaysOkPrompt()))
 	}
 
+	var summary timingsSummary
+	if upOptions.timings {
+		backendOptions.Add(compose.WithServiceTimings(summary.hook))
+	}
+
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
@@ -296,7 +332,11 @@ func runUp(
 	var consumer api.LogConsumer
 	var attach []string
 	if !upOptions.Detach {
-		consumer = formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), !upOptions.noColor, !upOptions.noPrefix, upOptions.timestamp)
+		consumerOptions := []formatter.LogConsumerOption{formatter.WithTimestamps(upOptions.timestamps.mode, start)}
+		if display.Mode == display.ModeJSON {
+			consumerOptions = append(consumerOptions, formatter.WithJSONOutput())
+		}
+		consumer = formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), !upOptions.noColor, !upOptions.noPrefix, false, consumerOptions...)
 
 		var attachSet utils.Set[string]
 		if len(upOptions.attach) != 0 {
@@ -329,8 +369,50 @@ func runUp(
 	}
 
 	timeout := time.Duration(upOptions.waitTimeout) * time.Second
-	return backend.Up(ctx, project, api.UpOptions{
-		Create: create,
+	var quiet api.QuietPhase
+	if createOptions.quietPull {
+		quiet |= api.QuietPhasePull
+	}
+	if buildOptions.quiet {
+		quiet |= api.QuietPhaseBuild
+	}
+	if upOptions.quietCreate {
+		quiet |= api.QuietPhaseCreate
+	}
+
+	// reload applies the same transformations as the initial project load, so changes can be compared
+	reload := func(ctx context.Context) (*types.Project, error) {
+		project, _, err := buildOptions.ToProject(ctx, dockerCli, backend, services, cli.WithoutEnvironmentResolution)
+		if err != nil {
+			return nil, err
+		}
+		project, err = project.WithServicesEnvironmentResolved(true)
+		if err != nil {
+			return nil, err
+		}
+		if err := createOptions.Apply(project); err != nil {
+			return nil, err
+		}
+		return upOptions.apply(project, services)
+	}
+
+	navigationMenu := upOptions.navigationMenu && display.Mode != "plain" && dockerCli.In().IsTerminal()
+	var keyBindings []api.MenuKeyBinding
+	if navigationMenu {
+		keyBindings, err = loadMenuKeyBindings()
+		if err != nil {
+			return err
+		}
+		if err := compose.ValidateKeyBindings(project, keyBindings); err != nil {
+			return err
+		}
+	}
+
+	err = backend.Up(ctx, project, api.UpOptions{
+		Create:      create,
+		QuietPhases: quiet,
+		Reload:      reload,
+		SyncHosts:   upOptions.syncHosts,
 		Start: api.StartOptions{
 			Project:        project,
 			Attach:         consumer,
@@ -341,9 +423,16 @@ func runUp(
 			WaitTimeout:    timeout,
 			Watch:          upOptions.watch,
 			Services:       services,
-			NavigationMenu: upOptions.navigationMenu && display.Mode != "plain" && dockerCli.In().IsTerminal(),
+			NavigationMenu: navigationMenu,
+			KeyBindings:    keyBindings,
 		},
 	})
+	if upOptions.timings {
+		if err := summary.print(dockerCli); err != nil {
+			return err
+		}
+	}
+	return err
 }
 
 func setServiceScale(project *types.Project, name string, replicas int) error {
@@ -21,6 +21,8 @@ import (
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
 )
 
 func TestApplyScaleOpt(t *testing.T) {
@@ -48,3 +50,52 @@ func TestApplyScaleOpt(t *testing.T) {
 	assert.Equal(t, *bar.Scale, 3)
 	assert.Equal(t, *bar.Deploy.Replicas, 3)
 }
+
+func TestRecreateServices(t *testing.T) {
+	p := &types.Project{
+		Services: types.Services{
+			"db":  {Name: "db"},
+			"app": {Name: "app"},
+		},
+	}
+	opts := createOptions{
+		noRecreateFor:    []string{"db"},
+		recreatePolicies: []string{"app=force", "db=never"},
+	}
+	strategies, err := opts.recreateServices(p)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, strategies, map[string]string{"db": api.RecreateNever, "app": api.RecreateForce})
+
+	opts = createOptions{recreatePolicies: []string{"db=if-changed"}, noRecreateFor: []string{"db"}}
+	_, err = opts.recreateServices(p)
+	assert.Error(t, err, `conflicting recreate policies for service "db"`)
+
+	opts = createOptions{recreatePolicies: []string{"db=sometimes"}}
+	_, err = opts.recreateServices(p)
+	assert.ErrorContains(t, err, "unsupported recreate policy")
+
+	opts = createOptions{noRecreateFor: []string{"cache"}}
+	_, err = opts.recreateServices(p)
+	assert.Error(t, err, "no such service: cache")
+}
+
+func TestApplyPlatformOverride(t *testing.T) {
+	p := &types.Project{
+		Services: types.Services{
+			"app": {Name: "app", Platform: "linux/amd64", Build: &types.BuildConfig{Context: "."}},
+			"db":  {Name: "db", Image: "postgres"},
+		},
+	}
+	opts := createOptions{platform: "linux/arm64"}
+	assert.NilError(t, opts.Apply(p))
+	assert.Equal(t, p.Services["app"].Platform, "linux/arm64")
+	assert.DeepEqual(t, p.Services["app"].Build.Platforms, types.StringList{"linux/arm64"})
+	assert.Equal(t, p.Services["db"].Platform, "linux/arm64")
+
+	p.Services["app"].Build.Platforms = []string{"linux/amd64"}
+	opts = createOptions{platform: "linux/s390x"}
+	assert.Error(t, opts.Apply(p), `service "app" build configuration does not support platform: linux/s390x`)
+
+	opts = createOptions{platform: "not a platform"}
+	assert.ErrorContains(t, opts.Apply(p), `invalid --platform "not a platform"`)
+}
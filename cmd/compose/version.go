@@ -17,7 +17,9 @@
 package compose
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/docker/cli/cli/command"
@@ -26,20 +28,26 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose/v5/internal"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
 )
 
 type versionOptions struct {
-	format string
-	short  bool
+	format   string
+	short    bool
+	features bool
 }
 
-func versionCommand(dockerCli command.Cli) *cobra.Command {
+func versionCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
 	opts := versionOptions{}
 	cmd := &cobra.Command{
 		Use:   "version [OPTIONS]",
 		Short: "Show the Docker Compose version information",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.features {
+				return runFeatures(cmd.Context(), dockerCli, backendOptions, opts)
+			}
 			runVersion(opts, dockerCli)
 			return nil
 		},
@@ -53,6 +61,7 @@ func versionCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
 	flags.BoolVar(&opts.short, "short", false, "Shows only Compose's version number")
+	flags.BoolVar(&opts.features, "features", false, "List optional features supported by Compose and Docker Engine")
 
 	return cmd
 }
@@ -68,3 +77,35 @@ func runVersion(opts versionOptions, dockerCli command.Cli) {
 	}
 	_, _ = fmt.Fprintln(dockerCli.Out(), "Docker Compose version", internal.Version)
 }
+
+func runFeatures(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts versionOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	features, err := backend.Features(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.format == formatter.JSON {
+		out, err := formatter.ToJSON(struct {
+			Version  string        `json:"version"`
+			Features []api.Feature `json:"features"`
+		}{
+			Version:  internal.Version,
+			Features: features,
+		}, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(dockerCli.Out(), out)
+		return nil
+	}
+	return formatter.Print(features, formatter.TABLE, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, f := range features {
+				_, _ = fmt.Fprintf(w, "%s\t%t\t%s\n", f.Name, f.Supported, f.Reason)
+			}
+		},
+		"FEATURE", "SUPPORTED", "REASON")
+}
@@ -65,7 +65,7 @@ func TestVersionCommand(t *testing.T) {
 			cli := mocks.NewMockCli(ctrl)
 			cli.EXPECT().Out().Return(streams.NewOut(buf)).AnyTimes()
 
-			cmd := versionCommand(cli)
+			cmd := versionCommand(cli, &BackendOptions{})
 			cmd.SetArgs(test.args)
 			err := cmd.Execute()
 			assert.NilError(t, err)
@@ -19,14 +19,21 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
 	"slices"
+	"strconv"
+	"time"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/flags"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/compose"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
+
+	composeformatter "github.com/docker/compose/v5/cmd/formatter"
 )
 
 type volumesOptions struct {
@@ -52,9 +59,314 @@ func volumesCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Ba
 	cmd.Flags().BoolVarP(&options.Quiet, "quiet", "q", false, "Only display volume names")
 	cmd.Flags().StringVar(&options.Format, "format", "table", flags.FormatHelp)
 
+	cmd.AddCommand(
+		volumesInspectCommand(p, dockerCli, backendOptions),
+		volumesBrowseCommand(p, dockerCli, backendOptions),
+		volumesDiskUsageCommand(p, dockerCli, backendOptions),
+		volumesSnapshotCommand(p, dockerCli, backendOptions),
+	)
 	return cmd
 }
 
+func volumesInspectCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect VOLUME",
+		Short: "Display detailed information on a project volume, and services mounting it",
+		Args:  cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumeInspect(ctx, dockerCli, backendOptions, p, args[0])
+		}),
+		ValidArgsFunction: completeVolumeNames(dockerCli, p),
+	}
+}
+
+func runVolumeInspect(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, p *ProjectOptions, volume string) error {
+	projectName, err := p.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	inspect, err := backend.VolumeInspect(ctx, projectName, volume)
+	if err != nil {
+		return err
+	}
+	out, err := composeformatter.ToJSON(inspect, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(dockerCli.Out(), out)
+	return nil
+}
+
+type volumesBrowseOptions struct {
+	*ProjectOptions
+	image    string
+	readOnly bool
+}
+
+func volumesBrowseCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := volumesBrowseOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "browse [OPTIONS] VOLUME [COMMAND] [ARGS...]",
+		Short: "Open a shell in a one-off container with a project volume mounted",
+		Long: `Open a shell in a one-off container with a project volume mounted.
+
+The volume is mounted under /volume, which is the container working directory.
+The container is removed on exit.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumeBrowse(ctx, dockerCli, backendOptions, opts, args[0], args[1:])
+		}),
+		ValidArgsFunction: completeVolumeNames(dockerCli, p),
+	}
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVar(&opts.image, "image", compose.DefaultVolumeBrowseImage, "Image used to browse the volume")
+	cmd.Flags().BoolVar(&opts.readOnly, "read-only", false, "Mount the volume read-only")
+	return cmd
+}
+
+func runVolumeBrowse(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts volumesBrowseOptions, volume string, command []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	exitCode, err := backend.VolumeBrowse(ctx, projectName, api.VolumeBrowseOptions{
+		Volume:   volume,
+		Image:    opts.image,
+		Command:  command,
+		ReadOnly: opts.readOnly,
+	})
+	if exitCode != 0 {
+		errMsg := fmt.Sprintf("exit status %d", exitCode)
+		if err != nil && err.Error() != "" {
+			errMsg = err.Error()
+		}
+		return cli.StatusError{StatusCode: exitCode, Status: errMsg}
+	}
+	return err
+}
+
+type volumesDiskUsageOptions struct {
+	*ProjectOptions
+	format string
+}
+
+func volumesDiskUsageCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := volumesDiskUsageOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "du [OPTIONS] [SERVICE...]",
+		Short: "Show project volumes disk usage",
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumeDiskUsage(ctx, dockerCli, backendOptions, opts, args)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runVolumeDiskUsage(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts volumesDiskUsageOptions, services []string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	volumes, err := backend.Volumes(ctx, projectName, api.VolumesOptions{
+		Services: services,
+		Usage:    true,
+	})
+	if err != nil {
+		return err
+	}
+	return composeformatter.Print(volumes, opts.format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, v := range volumes {
+				size, links := "N/A", "N/A"
+				if v.UsageData != nil && v.UsageData.Size >= 0 {
+					size = units.HumanSizeWithPrecision(float64(v.UsageData.Size), 3)
+					links = strconv.FormatInt(v.UsageData.RefCount, 10)
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, links, size)
+			}
+		},
+		"VOLUME", "LINKS", "SIZE")
+}
+
+func volumesSnapshotCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage snapshots of project volumes",
+		Long: `Manage snapshots of project volumes.
+
+Snapshots are tar archives of the volume content, stored under the directory set by COMPOSE_SNAPSHOTS_DIR,
+or the compose/snapshots directory of the docker configuration by default.`,
+	}
+	cmd.AddCommand(
+		volumesSnapshotCreateCommand(p, dockerCli, backendOptions),
+		volumesSnapshotListCommand(p, dockerCli, backendOptions),
+		volumesSnapshotRestoreCommand(p, dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type volumesSnapshotCreateOptions struct {
+	*ProjectOptions
+	dir    string
+	keep   int
+	maxAge time.Duration
+}
+
+func volumesSnapshotCreateCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := volumesSnapshotCreateOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] VOLUME",
+		Short: "Save a snapshot of a project volume",
+		Args:  cobra.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runVolumeSnapshotCreate(ctx, dockerCli, backendOptions, opts, args[0])
+		}),
+		ValidArgsFunction: completeVolumeNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory snapshots are stored in")
+	cmd.Flags().IntVar(&opts.keep, "keep", 0, "Number of most recent snapshots of the volume to retain, older ones are removed")
+	cmd.Flags().DurationVar(&opts.maxAge, "max-age", 0, "Remove snapshots of the volume older than this duration")
+	return cmd
+}
+
+func runVolumeSnapshotCreate(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts volumesSnapshotCreateOptions, volume string) error {
+	if opts.keep < 0 {
+		return fmt.Errorf("--keep must be positive")
+	}
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	_, err = backend.VolumeSnapshot(ctx, projectName, api.VolumeSnapshotOptions{
+		Volume: volume,
+		Dir:    opts.dir,
+		Keep:   opts.keep,
+		MaxAge: opts.maxAge,
+	})
+	return err
+}
+
+type volumesSnapshotListOptions struct {
+	*ProjectOptions
+	dir    string
+	format string
+}
+
+func volumesSnapshotListCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := volumesSnapshotListOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:     "list [OPTIONS] [VOLUME]",
+		Aliases: []string{"ls"},
+		Short:   "List snapshots of project volumes",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			var volume string
+			if len(args) > 0 {
+				volume = args[0]
+			}
+			return runVolumeSnapshotList(ctx, dockerCli, backendOptions, opts, volume)
+		}),
+		ValidArgsFunction: completeVolumeNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory snapshots are stored in")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runVolumeSnapshotList(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts volumesSnapshotListOptions, volume string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	snapshots, err := backend.VolumeSnapshots(ctx, projectName, api.VolumeSnapshotsOptions{
+		Volume: volume,
+		Dir:    opts.dir,
+	})
+	if err != nil {
+		return err
+	}
+	return composeformatter.Print(snapshots, opts.format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, s := range snapshots {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Volume, s.Name,
+					units.HumanDuration(time.Since(s.Created))+" ago", units.HumanSizeWithPrecision(float64(s.Size), 3))
+			}
+		},
+		"VOLUME", "SNAPSHOT", "CREATED", "SIZE")
+}
+
+type volumesSnapshotRestoreOptions struct {
+	*ProjectOptions
+	dir string
+}
+
+func volumesSnapshotRestoreCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := volumesSnapshotRestoreOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] VOLUME [SNAPSHOT]",
+		Short: "Replace the content of a project volume with a snapshot, the most recent one by default",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			var snapshot string
+			if len(args) > 1 {
+				snapshot = args[1]
+			}
+			return runVolumeSnapshotRestore(ctx, dockerCli, backendOptions, opts, args[0], snapshot)
+		}),
+		ValidArgsFunction: completeVolumeNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory snapshots are stored in")
+	return cmd
+}
+
+func runVolumeSnapshotRestore(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts volumesSnapshotRestoreOptions, volume string, snapshot string) error {
+	projectName, err := opts.toProjectName(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	return backend.VolumeRestore(ctx, projectName, api.VolumeRestoreOptions{
+		Volume:   volume,
+		Snapshot: snapshot,
+		Dir:      opts.dir,
+	})
+}
+
 func runVol(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, services []string, options volumesOptions) error {
 	project, name, err := options.projectOrName(ctx, dockerCli, services...)
 	if err != nil {
@@ -0,0 +1,163 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type watchStateOptions struct {
+	*ProjectOptions
+	interval time.Duration
+	once     bool
+	format   string
+	webhook  string
+}
+
+func watchStateCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := watchStateOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "watch-state [OPTIONS]",
+		Short: "Report drift between running state and the Compose file",
+		Long: `Report drift between running state and the Compose file
+
+Drift is checked periodically and on engine events. A report is emitted on first check,
+then each time drift changes: stopped or missing containers, containers which image or
+configuration (including environment) changed out-of-band, and resources labeled with the
+project but not declared by the Compose file.
+
+With --once, the command exits with status 1 when drift is detected.`,
+		Args: cobra.NoArgs,
+		PreRunE: Adapt(func(ctx context.Context, args []string) error {
+			switch opts.format {
+			case formatter.PRETTY, formatter.JSON:
+				return nil
+			default:
+				return fmt.Errorf("unsupported format %q", opts.format)
+			}
+		}),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runWatchState(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.DurationVar(&opts.interval, "interval", 30*time.Second, "Interval between periodic checks, 0 to only check on engine events")
+	flags.BoolVar(&opts.once, "once", false, "Check drift once and exit with status 1 if drift is detected")
+	flags.StringVar(&opts.format, "format", formatter.PRETTY, "Format the output. Values: [pretty | json]")
+	flags.StringVar(&opts.webhook, "webhook", "", "URL to POST JSON drift reports to")
+	return cmd
+}
+
+func runWatchState(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts watchStateOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+
+	drift := false
+	err = backend.WatchState(ctx, project, api.WatchStateOptions{
+		Interval: opts.interval,
+		Once:     opts.once,
+		Consumer: func(report api.DriftReport) error {
+			drift = len(report.Drifts) > 0
+			if opts.webhook != "" {
+				if err := postDriftReport(ctx, opts.webhook, report); err != nil {
+					logrus.Warnf("failed to send drift report to webhook: %v", err)
+				}
+			}
+			return printDriftReport(dockerCli.Out(), opts.format, report)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if opts.once && drift {
+		return cli.StatusError{StatusCode: 1, Status: "drift detected"}
+	}
+	return nil
+}
+
+func printDriftReport(out io.Writer, format string, report api.DriftReport) error {
+	if format == formatter.JSON {
+		s, err := formatter.ToJSON(report, "", "")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(out, s)
+		return err
+	}
+	timestamp := report.Timestamp.Format(time.RFC3339)
+	if len(report.Drifts) == 0 {
+		_, err := fmt.Fprintf(out, "%s project %s matches the Compose file\n", timestamp, report.Project)
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%s project %s drifted from the Compose file:\n", timestamp, report.Project)
+	for _, d := range report.Drifts {
+		line := fmt.Sprintf("  %s %s %s", d.Kind, d.Resource, d.Name)
+		if d.Details != "" {
+			line += " (" + d.Details + ")"
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func postDriftReport(ctx context.Context, url string, report api.DriftReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
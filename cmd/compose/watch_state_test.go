@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestPrintDriftReport(t *testing.T) {
+	report := api.DriftReport{
+		Project:   "myproject",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Drifts: []api.Drift{
+			{Kind: api.DriftStopped, Resource: "container", Name: "myproject-web-1"},
+			{Kind: api.DriftChanged, Resource: "container", Name: "myproject-db-1", Details: "environment"},
+		},
+	}
+	var out bytes.Buffer
+	assert.NilError(t, printDriftReport(&out, formatter.PRETTY, report))
+	assert.Equal(t, out.String(), `2024-01-02T03:04:05Z project myproject drifted from the Compose file:
+  stopped container myproject-web-1
+  changed container myproject-db-1 (environment)
+`)
+
+	out.Reset()
+	assert.NilError(t, printDriftReport(&out, formatter.PRETTY, api.DriftReport{Project: "myproject", Timestamp: report.Timestamp}))
+	assert.Equal(t, out.String(), "2024-01-02T03:04:05Z project myproject matches the Compose file\n")
+
+	out.Reset()
+	assert.NilError(t, printDriftReport(&out, formatter.JSON, report))
+	var decoded api.DriftReport
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.DeepEqual(t, decoded, report)
+}
+
+func TestPostDriftReport(t *testing.T) {
+	var received api.DriftReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Project == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	report := api.DriftReport{Project: "myproject", Drifts: []api.Drift{{Kind: api.DriftMissing, Resource: "volume", Name: "myproject_data"}}}
+	assert.NilError(t, postDriftReport(t.Context(), server.URL, report))
+	assert.DeepEqual(t, received, report)
+
+	err := postDriftReport(t.Context(), server.URL, api.DriftReport{Project: "fail"})
+	assert.ErrorContains(t, err, "500 Internal Server Error")
+}
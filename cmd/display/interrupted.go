@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package display
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// interruptedSummary reports the state an interrupted operation left resources in, according to the last event
+// received for each of them: completed, still in progress when interrupted, failed, or skipped.
+// Child resources, like image layers, are ignored.
+func interruptedSummary(operation string, resources []api.Resource) string {
+	var completed, inProgress, failed, skipped []string
+	for _, r := range resources {
+		if r.ParentID != "" {
+			continue
+		}
+		line := r.ID
+		if r.Text != "" {
+			line += ": " + r.Text
+		}
+		switch {
+		case strings.HasPrefix(r.Text, api.StatusSkipped):
+			if reason := strings.TrimPrefix(r.Text, api.StatusSkipped+": "); reason != r.Text {
+				skipped = append(skipped, r.ID+": "+reason)
+			} else {
+				skipped = append(skipped, r.ID)
+			}
+		case r.Status == api.Working:
+			inProgress = append(inProgress, line)
+		case r.Status == api.Error && strings.Contains(r.Details, context.Canceled.Error()):
+			// resource failed because operation was interrupted
+			inProgress = append(inProgress, r.ID)
+		case r.Status == api.Error:
+			failed = append(failed, line)
+		default:
+			completed = append(completed, line)
+		}
+	}
+
+	var sb strings.Builder
+	if operation == "" {
+		operation = "operation"
+	}
+	fmt.Fprintf(&sb, "%s interrupted: %d completed, %d in progress, %d failed, %d skipped\n",
+		operation, len(completed), len(inProgress), len(failed), len(skipped))
+	for _, group := range []struct {
+		title     string
+		resources []string
+	}{
+		{"Completed", completed},
+		{"In progress, state unknown", inProgress},
+		{"Failed", failed},
+		{"Skipped", skipped},
+	} {
+		if len(group.resources) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s:\n", group.title)
+		for _, r := range group.resources {
+			fmt.Fprintf(&sb, "  %s\n", r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package display
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+	"gotest.tools/v3/assert"
+)
+
+func TestPlainWriter_Interrupted(t *testing.T) {
+	var out bytes.Buffer
+	w := Plain(&out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx, "up")
+	w.On(
+		api.Resource{ID: "Network test_default", Status: api.Done, Text: api.StatusCreated},
+		api.Resource{ID: "Image nginx", Status: api.Working, Text: api.StatusPulling},
+		api.Resource{ID: "layer1", ParentID: "Image nginx", Status: api.Working, Text: "Downloading"},
+		api.Resource{ID: "Container test-db-1", Status: api.Working, Text: api.StatusCreating},
+	)
+	cancel()
+	w.On(
+		api.Resource{ID: "Container test-db-1", Status: api.Error, Text: api.StatusError, Details: "context canceled"},
+		api.Resource{ID: "Service web", Status: api.Warning, Text: "Skipped: operation interrupted"},
+	)
+	out.Reset()
+	w.Done("up", false)
+
+	assert.Equal(t, out.String(), `up interrupted: 1 completed, 2 in progress, 0 failed, 1 skipped
+Completed:
+  Network test_default: Created
+In progress, state unknown:
+  Image nginx: Pulling
+  Container test-db-1
+Skipped:
+  Service web: operation interrupted
+`)
+}
+
+func TestPlainWriter_NotInterrupted(t *testing.T) {
+	var out bytes.Buffer
+	w := Plain(&out)
+
+	w.Start(context.Background(), "up")
+	w.On(api.Resource{ID: "Container test-db-1", Status: api.Error, Text: api.StatusError, Details: "port is already allocated"})
+	out.Reset()
+	w.Done("up", false)
+	assert.Equal(t, out.String(), "")
+}
+
+// TestRunInterrupted drives the interrupted summary through compose.Run, which reports operation success to display
+func TestRunInterrupted(t *testing.T) {
+	var out bytes.Buffer
+	w := Plain(&out)
+
+	err := compose.Run(t.Context(), func(ctx context.Context) error {
+		w.On(api.Resource{ID: "Container test-db-1", Status: api.Done, Text: api.StatusStarted})
+		return nil
+	}, "up", w)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), " Container test-db-1 Started \n")
+
+	out.Reset()
+	ctx, cancel := context.WithCancel(t.Context())
+	err = compose.Run(ctx, func(ctx context.Context) error {
+		w.On(api.Resource{ID: "Container test-db-1", Status: api.Working, Text: api.StatusStarting})
+		cancel()
+		return ctx.Err()
+	}, "up", w)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, out.String(), " Container test-db-1 Starting \n"+`up interrupted: 0 completed, 1 in progress, 0 failed, 0 skipped
+In progress, state unknown:
+  Container test-db-1: Starting
+`)
+}
@@ -26,6 +26,8 @@ const (
 	ModeTTY = "tty"
 	// ModePlain dump raw events to output
 	ModePlain = "plain"
+	// ModeOrdered dump events to output as ModePlain does, grouped by resource in a stable order once operation completes
+	ModeOrdered = "ordered"
 	// ModeQuiet don't display events
 	ModeQuiet = "quiet"
 	// ModeJSON outputs a machine-readable JSON stream
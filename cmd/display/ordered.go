@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package display
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// resourceKinds is the order resources are reported by Ordered, which is the order resources depend on each others
+var resourceKinds = []string{"Image", "Network", "Volume", "Secret", "Config", "Container", "Service"}
+
+// Ordered renders events as Plain does, but buffers them per resource while an operation is running. Once operation
+// completes, resources are reported one after the other, in dependency order, then sorted by name. Progress of
+// child resources, like image layers being pulled, is reduced to the last event. This makes output diffable
+// between runs.
+func Ordered(out io.Writer) api.EventProcessor {
+	return &orderedWriter{
+		plain: &plainWriter{
+			out:  out,
+			last: map[string]api.Resource{},
+		},
+		buffered: map[string][]api.Resource{},
+		children: map[string][]string{},
+	}
+}
+
+type orderedWriter struct {
+	plain *plainWriter
+
+	mtx sync.Mutex
+	// running is the number of operations started and not done yet, as operations can be nested
+	running  int
+	ids      []string
+	buffered map[string][]api.Resource
+	children map[string][]string
+}
+
+func (o *orderedWriter) Start(ctx context.Context, operation string) {
+	o.mtx.Lock()
+	o.running++
+	o.mtx.Unlock()
+	o.plain.Start(ctx, operation)
+}
+
+func (o *orderedWriter) On(events ...api.Resource) {
+	o.mtx.Lock()
+	if o.running == 0 {
+		// not part of an operation, like events reported while attached to containers
+		o.mtx.Unlock()
+		o.plain.On(events...)
+		return
+	}
+	defer o.mtx.Unlock()
+	for _, e := range events {
+		previous, seen := o.buffered[e.ID]
+		if !seen {
+			o.ids = append(o.ids, e.ID)
+			if e.ParentID != "" {
+				o.children[e.ParentID] = append(o.children[e.ParentID], e.ID)
+			}
+		}
+		switch {
+		case e.ParentID != "":
+			o.buffered[e.ID] = []api.Resource{e}
+		case len(previous) > 0 && sameState(previous[len(previous)-1], e):
+			continue
+		default:
+			o.buffered[e.ID] = append(previous, e)
+		}
+	}
+}
+
+func sameState(a, b api.Resource) bool {
+	return a.Status == b.Status && a.Text == b.Text && a.Details == b.Details
+}
+
+func (o *orderedWriter) Done(operation string, success bool) {
+	o.mtx.Lock()
+	o.running--
+	var events []api.Resource
+	if o.running <= 0 {
+		o.running = 0
+		events = o.flush()
+	}
+	o.mtx.Unlock()
+	if len(events) > 0 {
+		o.plain.On(events...)
+	}
+	o.plain.Done(operation, success)
+}
+
+// flush returns buffered events, sorted by resource, and resets buffer
+func (o *orderedWriter) flush() []api.Resource {
+	var roots []string
+	for _, id := range o.ids {
+		parent := o.buffered[id][0].ParentID
+		if _, ok := o.buffered[parent]; parent == "" || !ok {
+			roots = append(roots, id)
+		}
+	}
+	var events []api.Resource
+	var visit func(ids []string)
+	visit = func(ids []string) {
+		slices.SortStableFunc(ids, compareResources)
+		for _, id := range ids {
+			events = append(events, o.buffered[id]...)
+			visit(o.children[id])
+		}
+	}
+	visit(roots)
+
+	o.ids = nil
+	o.buffered = map[string][]api.Resource{}
+	o.children = map[string][]string{}
+	return events
+}
+
+func compareResources(a, b string) int {
+	return cmp.Or(cmp.Compare(resourceKind(a), resourceKind(b)), strings.Compare(a, b))
+}
+
+// resourceKind returns the rank of resource kind in resourceKinds, unknown kinds being reported last
+func resourceKind(id string) int {
+	kind, _, _ := strings.Cut(id, " ")
+	if i := slices.Index(resourceKinds, kind); i >= 0 {
+		return i
+	}
+	return len(resourceKinds)
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package display
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestOrderedWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := Ordered(&out)
+
+	w.Start(context.Background(), "up")
+	w.On(
+		api.Resource{ID: "Container test-web-1", Status: api.Working, Text: api.StatusCreating},
+		api.Resource{ID: "Image nginx", Status: api.Working, Text: api.StatusPulling},
+		api.Resource{ID: "layer2", ParentID: "Image nginx", Status: api.Working, Text: "Downloading", Percent: 10},
+		api.Resource{ID: "Container test-db-1", Status: api.Working, Text: api.StatusCreating},
+		api.Resource{ID: "layer1", ParentID: "Image nginx", Status: api.Done, Text: "Pull complete"},
+		api.Resource{ID: "Network test_default", Status: api.Done, Text: api.StatusCreated},
+	)
+	w.On(
+		api.Resource{ID: "layer2", ParentID: "Image nginx", Status: api.Done, Text: "Pull complete"},
+		api.Resource{ID: "Image nginx", Status: api.Working, Text: api.StatusPulling},
+		api.Resource{ID: "Image nginx", Status: api.Done, Text: api.StatusPulled},
+		api.Resource{ID: "Container test-db-1", Status: api.Done, Text: api.StatusCreated},
+		api.Resource{ID: "Container test-web-1", Status: api.Done, Text: api.StatusCreated},
+	)
+	assert.Equal(t, out.String(), "", "events must be buffered while operation is running")
+
+	w.Done("up", true)
+	assert.Equal(t, out.String(), ` Image nginx Pulling 
+ Image nginx Pulled 
+ layer1 Pull complete 
+ layer2 Pull complete 
+ Network test_default Created 
+ Container test-db-1 Creating 
+ Container test-db-1 Created 
+ Container test-web-1 Creating 
+ Container test-web-1 Created 
+`)
+
+	out.Reset()
+	w.On(api.Resource{ID: "Container test-web-1", Status: api.Done, Text: api.StatusStarted})
+	assert.Equal(t, out.String(), " Container test-web-1 Started \n", "events out of an operation must not be buffered")
+}
@@ -20,22 +20,35 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/docker/compose/v5/pkg/api"
 )
 
 func Plain(out io.Writer) api.EventProcessor {
 	return &plainWriter{
-		out: out,
+		out:  out,
+		last: map[string]api.Resource{},
 	}
 }
 
 type plainWriter struct {
 	out    io.Writer
 	dryRun bool
+
+	mtx       sync.Mutex
+	ctx       context.Context
+	operation string
+	// ids and last track resources state, to report what an interrupted operation left behind
+	ids  []string
+	last map[string]api.Resource
 }
 
 func (p *plainWriter) Start(ctx context.Context, operation string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.ctx = ctx
+	p.operation = operation
 }
 
 func (p *plainWriter) Event(e api.Resource) {
@@ -47,10 +60,26 @@ func (p *plainWriter) Event(e api.Resource) {
 }
 
 func (p *plainWriter) On(events ...api.Resource) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
 	for _, e := range events {
+		if _, ok := p.last[e.ID]; !ok {
+			p.ids = append(p.ids, e.ID)
+		}
+		p.last[e.ID] = e
 		p.Event(e)
 	}
 }
 
-func (p *plainWriter) Done(_ string, _ bool) {
+func (p *plainWriter) Done(operation string, success bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if success || p.ctx == nil || p.ctx.Err() == nil {
+		return
+	}
+	resources := make([]api.Resource, 0, len(p.ids))
+	for _, id := range p.ids {
+		resources = append(resources, p.last[id])
+	}
+	_, _ = fmt.Fprint(p.out, interruptedSummary(p.operation, resources))
 }
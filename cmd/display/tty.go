@@ -86,16 +86,19 @@ func (w *ttyWriter) Start(ctx context.Context, operation string) {
 	w.ticker = time.NewTicker(100 * time.Millisecond)
 	w.operation = operation
 	go func() {
+		interrupted := ctx.Done()
 		for {
 			select {
-			case <-ctx.Done():
-				// interrupted
-				w.ticker.Stop()
-				return
-			case <-w.done:
+			case <-interrupted:
+				// keep rendering until operation completes, so resources are reported in the state they're left in
+				interrupted = nil
+			case success := <-w.done:
 				w.print()
 				w.mtx.Lock()
 				w.ticker.Stop()
+				if !success && ctx.Err() != nil {
+					_, _ = fmt.Fprint(w.out, interruptedSummary(w.operation, w.resources()))
+				}
 				w.operation = ""
 				w.mtx.Unlock()
 				return
@@ -107,7 +110,23 @@ func (w *ttyWriter) Start(ctx context.Context, operation string) {
 }
 
 func (w *ttyWriter) Done(operation string, success bool) {
-	w.done <- true
+	w.done <- success
+}
+
+// resources returns the last known state of tasks, in the order they first appeared
+func (w *ttyWriter) resources() []api.Resource {
+	resources := make([]api.Resource, 0, len(w.ids))
+	for _, id := range w.ids {
+		t := w.tasks[id]
+		resources = append(resources, api.Resource{
+			ID:       t.ID,
+			ParentID: t.parentID,
+			Text:     t.text,
+			Details:  t.details,
+			Status:   t.status,
+		})
+	}
+	return resources
 }
 
 func (w *ttyWriter) On(events ...api.Resource) {
@@ -32,14 +32,20 @@ import (
 const (
 	defaultContainerTableFormat = "table {{.Name}}\t{{.Image}}\t{{.Command}}\t{{.Service}}\t{{.RunningFor}}\t{{.Status}}\t{{.Ports}}"
 
-	nameHeader       = "NAME"
-	projectHeader    = "PROJECT"
-	serviceHeader    = "SERVICE"
-	commandHeader    = "COMMAND"
-	runningForHeader = "CREATED"
-	mountsHeader     = "MOUNTS"
-	localVolumes     = "LOCAL VOLUMES"
-	networksHeader   = "NETWORKS"
+	nameHeader         = "NAME"
+	projectHeader      = "PROJECT"
+	serviceHeader      = "SERVICE"
+	commandHeader      = "COMMAND"
+	runningForHeader   = "CREATED"
+	mountsHeader       = "MOUNTS"
+	localVolumes       = "LOCAL VOLUMES"
+	networksHeader     = "NETWORKS"
+	endpointsHeader    = "ENDPOINTS"
+	diffHeader         = "DIFF"
+	startedAtHeader    = "STARTED AT"
+	finishedAtHeader   = "FINISHED AT"
+	healthSinceHeader  = "HEALTH SINCE"
+	restartCountHeader = "RESTARTS"
 )
 
 // NewContainerFormat returns a Format for rendering using a Context
@@ -111,19 +117,25 @@ type ContainerContext struct {
 func NewContainerContext() *ContainerContext {
 	containerCtx := ContainerContext{}
 	containerCtx.Header = formatter.SubHeaderContext{
-		"ID":         formatter.ContainerIDHeader,
-		"Name":       nameHeader,
-		"Project":    projectHeader,
-		"Service":    serviceHeader,
-		"Image":      formatter.ImageHeader,
-		"Command":    commandHeader,
-		"CreatedAt":  formatter.CreatedAtHeader,
-		"RunningFor": runningForHeader,
-		"Ports":      formatter.PortsHeader,
-		"State":      formatter.StateHeader,
-		"Status":     formatter.StatusHeader,
-		"Size":       formatter.SizeHeader,
-		"Labels":     formatter.LabelsHeader,
+		"ID":           formatter.ContainerIDHeader,
+		"Name":         nameHeader,
+		"Project":      projectHeader,
+		"Service":      serviceHeader,
+		"Image":        formatter.ImageHeader,
+		"Command":      commandHeader,
+		"CreatedAt":    formatter.CreatedAtHeader,
+		"RunningFor":   runningForHeader,
+		"Ports":        formatter.PortsHeader,
+		"State":        formatter.StateHeader,
+		"Status":       formatter.StatusHeader,
+		"Size":         formatter.SizeHeader,
+		"Labels":       formatter.LabelsHeader,
+		"Endpoints":    endpointsHeader,
+		"Diff":         diffHeader,
+		"StartedAt":    startedAtHeader,
+		"FinishedAt":   finishedAtHeader,
+		"HealthSince":  healthSinceHeader,
+		"RestartCount": restartCountHeader,
 	}
 	return &containerCtx
 }
@@ -207,6 +219,32 @@ func (c *ContainerContext) Health() string {
 	return c.c.Health
 }
 
+// StartedAt returns the time container was last started, or an empty string
+func (c *ContainerContext) StartedAt() string {
+	return formatStateTime(c.c.StartedAt)
+}
+
+// FinishedAt returns the time container last exited, or an empty string
+func (c *ContainerContext) FinishedAt() string {
+	return formatStateTime(c.c.FinishedAt)
+}
+
+// HealthSince returns the time container health reached its current status, or an empty string
+func (c *ContainerContext) HealthSince() string {
+	return formatStateTime(c.c.HealthSince)
+}
+
+func (c *ContainerContext) RestartCount() int {
+	return c.c.RestartCount
+}
+
+func formatStateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func (c *ContainerContext) Publishers() api.PortPublishers {
 	return c.c.Publishers
 }
@@ -270,6 +308,18 @@ func (c *ContainerContext) Networks() string {
 	return strings.Join(c.c.Networks, ",")
 }
 
+// Endpoints returns the addresses and DNS names of the container on each network
+// it is attached to.
+func (c *ContainerContext) Endpoints() api.ContainerEndpoints {
+	return c.c.Endpoints
+}
+
+// Diff returns a comma-separated list of the service configuration aspects
+// which changed since the container was created.
+func (c *ContainerContext) Diff() string {
+	return strings.Join(c.c.Diff, ",")
+}
+
 // Size returns the container's size and virtual size (e.g. "2B (virtual 21.5MB)")
 func (c *ContainerContext) Size() string {
 	if c.FieldsUsed == nil {
@@ -18,6 +18,7 @@ package formatter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -30,6 +31,18 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 )
 
+// Timestamps defines how log lines are timestamped
+type Timestamps string
+
+const (
+	// TimestampsNone doesn't add timestamps to log lines
+	TimestampsNone Timestamps = ""
+	// TimestampsAbsolute prefixes log lines with the time they were produced
+	TimestampsAbsolute Timestamps = "absolute"
+	// TimestampsRelative prefixes log lines with the elapsed time since start of the run
+	TimestampsRelative Timestamps = "relative"
+)
+
 // LogConsumer consume logs from services and format them
 type logConsumer struct {
 	ctx        context.Context
@@ -39,12 +52,43 @@ type logConsumer struct {
 	stderr     io.Writer
 	color      bool
 	prefix     bool
-	timestamp  bool
+	timestamps Timestamps
+	// start is the reference time for relative timestamps
+	start time.Time
+	// engineTimestamps is set when log lines are prefixed by the timestamp set by engine
+	engineTimestamps bool
+	json             bool
+}
+
+// LogConsumerOption configures a LogConsumer
+type LogConsumerOption func(*logConsumer)
+
+// WithTimestamps sets how log lines are timestamped, relative timestamps being computed from start
+func WithTimestamps(timestamps Timestamps, start time.Time) LogConsumerOption {
+	return func(l *logConsumer) {
+		l.timestamps = timestamps
+		l.start = start
+	}
+}
+
+// WithEngineTimestamps tells the LogConsumer log lines are prefixed by the timestamp set by engine,
+// which is used rather than the time lines are received
+func WithEngineTimestamps() LogConsumerOption {
+	return func(l *logConsumer) {
+		l.engineTimestamps = true
+	}
+}
+
+// WithJSONOutput makes the LogConsumer write log lines as JSON objects
+func WithJSONOutput() LogConsumerOption {
+	return func(l *logConsumer) {
+		l.json = true
+	}
 }
 
 // NewLogConsumer creates a new LogConsumer
-func NewLogConsumer(ctx context.Context, stdout, stderr io.Writer, color, prefix, timestamp bool) api.LogConsumer {
-	return &logConsumer{
+func NewLogConsumer(ctx context.Context, stdout, stderr io.Writer, color, prefix, timestamp bool, options ...LogConsumerOption) api.LogConsumer {
+	l := &logConsumer{
 		ctx:        ctx,
 		presenters: sync.Map{},
 		width:      0,
@@ -52,8 +96,15 @@ func NewLogConsumer(ctx context.Context, stdout, stderr io.Writer, color, prefix
 		stderr:     stderr,
 		color:      color,
 		prefix:     prefix,
-		timestamp:  timestamp,
+		start:      time.Now(),
 	}
+	if timestamp {
+		l.timestamps = TimestampsAbsolute
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
 }
 
 func (l *logConsumer) register(name string) *presenter {
@@ -105,30 +156,99 @@ func (l *logConsumer) getPresenter(container string) *presenter {
 
 // Log formats a log message as received from name/container
 func (l *logConsumer) Log(container, message string) {
-	l.write(l.stdout, container, message)
+	l.write(l.stdout, "stdout", container, message)
 }
 
 // Err formats a log message as received from name/container
 func (l *logConsumer) Err(container, message string) {
-	l.write(l.stderr, container, message)
+	l.write(l.stderr, "stderr", container, message)
+}
+
+// jsonLogLine is a log line written by a LogConsumer configured for JSON output
+type jsonLogLine struct {
+	Container string `json:"container"`
+	Stream    string `json:"stream,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Status    string `json:"status,omitempty"`
 }
 
-func (l *logConsumer) write(w io.Writer, container, message string) {
+func (l *logConsumer) write(w io.Writer, stream, container, message string) {
 	if l.ctx.Err() != nil {
 		return
 	}
 	p := l.getPresenter(container)
-	timestamp := time.Now().Format(jsonmessage.RFC3339NanoFixed)
+	received := time.Now()
 	for _, line := range strings.Split(message, "\n") {
-		if l.timestamp {
+		t := received
+		if l.engineTimestamps {
+			t, line = splitEngineTimestamp(line, received)
+		}
+		timestamp := l.formatTimestamp(t)
+		switch {
+		case l.json:
+			l.writeJSON(w, jsonLogLine{Container: container, Stream: stream, Timestamp: timestamp, Message: line})
+		case timestamp != "":
 			_, _ = fmt.Fprintf(w, "%s%s %s\n", p.prefix, timestamp, line)
-		} else {
+		default:
 			_, _ = fmt.Fprintf(w, "%s%s\n", p.prefix, line)
 		}
 	}
 }
 
+func (l *logConsumer) writeJSON(w io.Writer, line jsonLogLine) {
+	b, err := json.Marshal(line)
+	if err == nil {
+		_, _ = fmt.Fprintln(w, string(b))
+	}
+}
+
+// formatTimestamp renders t according to the configured timestamps mode, so all services
+// share the same reference and format
+func (l *logConsumer) formatTimestamp(t time.Time) string {
+	switch l.timestamps {
+	case TimestampsAbsolute:
+		return t.Format(jsonmessage.RFC3339NanoFixed)
+	case TimestampsRelative:
+		return formatElapsed(t.Sub(l.start))
+	default:
+		return ""
+	}
+}
+
+// formatElapsed renders a duration with a fixed width, so log lines stay aligned
+func formatElapsed(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	return fmt.Sprintf("%s%02d:%02d:%02d.%03d", sign, h, m, s, ms)
+}
+
+// splitEngineTimestamp extracts the timestamp engine prefixes log lines with, when requested to.
+// fallback is used if line has no valid timestamp.
+func splitEngineTimestamp(line string, fallback time.Time) (time.Time, string) {
+	ts, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		ts = line
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return fallback, line
+	}
+	return t, rest
+}
+
 func (l *logConsumer) Status(container, msg string) {
+	if l.json {
+		l.writeJSON(l.stdout, jsonLogLine{Container: container, Timestamp: l.formatTimestamp(time.Now()), Status: msg})
+		return
+	}
 	p := l.getPresenter(container)
 	s := p.colors(fmt.Sprintf("%s%s %s\n", goterm.RESET_LINE, container, msg))
 	l.stdout.Write([]byte(s)) //nolint:errcheck
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLogConsumerRelativeTimestamps(t *testing.T) {
+	start := time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC)
+	out := &bytes.Buffer{}
+	consumer := NewLogConsumer(t.Context(), out, out, false, false, false,
+		WithTimestamps(TimestampsRelative, start),
+		WithEngineTimestamps())
+
+	consumer.Log("db", "2024-01-02T13:00:01.5Z ready")
+	consumer.Log("app", "2024-01-02T14:02:03.004000000Z started")
+	consumer.Log("app", "no timestamp")
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Equal(t, string(lines[0]), "+00:00:01.500 ready")
+	assert.Equal(t, string(lines[1]), "+01:02:03.004 started")
+	// lines without engine timestamp are timestamped on reception
+	assert.Assert(t, !bytes.HasPrefix(lines[2], []byte("+00:00:00")))
+}
+
+func TestLogConsumerJSON(t *testing.T) {
+	start := time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	consumer := NewLogConsumer(t.Context(), stdout, stderr, true, true, false,
+		WithTimestamps(TimestampsAbsolute, start),
+		WithEngineTimestamps(),
+		WithJSONOutput())
+
+	consumer.Log("db", "2024-01-02T13:00:01.5Z ready")
+	consumer.Err("db", "2024-01-02T13:00:02Z oops")
+	assert.Equal(t, stdout.String(), `{"container":"db","stream":"stdout","timestamp":"2024-01-02T13:00:01.500000000Z","message":"ready"}`+"\n")
+	assert.Equal(t, stderr.String(), `{"container":"db","stream":"stderr","timestamp":"2024-01-02T13:00:02.000000000Z","message":"oops"}`+"\n")
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, formatElapsed(90*time.Second+5*time.Millisecond), "+00:01:30.005")
+	assert.Equal(t, formatElapsed(-2*time.Second), "-00:00:02.000")
+}
@@ -23,6 +23,7 @@ import (
 	"math"
 	"os"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -80,6 +81,12 @@ type Feature interface {
 	Stop() error
 }
 
+// KeyboardReload offers to apply compose files changes detected while running
+type KeyboardReload struct {
+	pending atomic.Bool
+	Apply   func(context.Context) error
+}
+
 type KEYBOARD_LOG_LEVEL int
 
 const (
@@ -91,10 +98,14 @@ const (
 type LogKeyboard struct {
 	kError                KeyboardError
 	Watch                 *KeyboardWatch
+	Reload                *KeyboardReload
 	Detach                func()
 	IsDockerDesktopActive bool
 	logLevel              KEYBOARD_LOG_LEVEL
 	signalChannel         chan<- os.Signal
+	bindings              []KeyBinding
+	resumed               chan (<-chan keyboard.KeyEvent)
+	held                  *heldConsumer
 }
 
 func NewKeyboardManager(isDockerDesktopActive bool, sc chan<- os.Signal) *LogKeyboard {
@@ -106,11 +117,14 @@ func NewKeyboardManager(isDockerDesktopActive bool, sc chan<- os.Signal) *LogKey
 }
 
 func (lk *LogKeyboard) Decorate(l api.LogConsumer) api.LogConsumer {
-	return logDecorator{
-		decorated: l,
-		Before:    lk.clearNavigationMenu,
-		After:     lk.PrintKeyboardInfo,
+	lk.held = &heldConsumer{
+		decorated: logDecorator{
+			decorated: l,
+			Before:    lk.clearNavigationMenu,
+			After:     lk.PrintKeyboardInfo,
+		},
 	}
+	return lk.held
 }
 
 func (lk *LogKeyboard) PrintKeyboardInfo() {
@@ -177,6 +191,12 @@ func (lk *LogKeyboard) navigationMenu() string {
 		isEnabled = " Disable"
 	}
 	items = append(items, shortcutKeyColor("w")+navColor(isEnabled+" Watch"))
+	if lk.Reload != nil && lk.Reload.pending.Load() {
+		items = append(items, shortcutKeyColor("r")+navColor(" Apply config changes"))
+	}
+	for _, b := range lk.bindings {
+		items = append(items, shortcutKeyColor(string(b.Key))+navColor(" "+b.Description))
+	}
 	items = append(items, shortcutKeyColor("d")+navColor(" Detach"))
 
 	return strings.Join(items, "   ")
@@ -310,6 +330,10 @@ func (lk *LogKeyboard) HandleKeyEvents(ctx context.Context, event keyboard.KeyEv
 		lk.ToggleWatch(ctx, options)
 	case 'o':
 		lk.openDDComposeUI(ctx, project)
+	case 'r':
+		lk.applyReload(ctx)
+	default:
+		lk.runKeyBinding(ctx, kRune)
 	}
 	switch key := event.Key; key {
 	case keyboard.KeyCtrlC:
@@ -335,6 +359,37 @@ func (lk *LogKeyboard) EnableWatch(enabled bool, watcher Feature) {
 	}
 }
 
+func (lk *LogKeyboard) applyReload(ctx context.Context) {
+	if lk.Reload == nil || !lk.Reload.pending.CompareAndSwap(true, false) {
+		return
+	}
+	lk.printNavigationMenu()
+	go func() {
+		_ = tracing.EventWrapFuncForErrGroup(ctx, "menu/reload", tracing.SpanOptions{},
+			func(ctx context.Context) error {
+				err := lk.Reload.Apply(ctx)
+				if err != nil {
+					lk.keyboardError("Reload", err)
+				}
+				return err
+			})()
+	}()
+}
+
+// EnableReload adds a menu entry to apply compose files changes, once SetReloadPending reported some
+func (lk *LogKeyboard) EnableReload(apply func(context.Context) error) {
+	lk.Reload = &KeyboardReload{Apply: apply}
+}
+
+// SetReloadPending tells if compose files changes are waiting to be applied
+func (lk *LogKeyboard) SetReloadPending(pending bool) {
+	if lk.Reload == nil {
+		return
+	}
+	lk.Reload.pending.Store(pending)
+	lk.PrintKeyboardInfo()
+}
+
 func (lk *LogKeyboard) EnableDetach(detach func()) {
 	lk.Detach = detach
 }
@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eiannone/keyboard"
+
+	"github.com/docker/compose/v5/internal/tracing"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// ReservedKeys are used by the built-in navigation menu entries, and can't be bound to custom actions
+const ReservedKeys = "dvwor"
+
+// KeyBinding is a custom navigation menu entry
+type KeyBinding struct {
+	Key         rune
+	Description string
+	Run         func(context.Context) error
+}
+
+// AddKeyBinding adds a custom entry to the navigation menu
+func (lk *LogKeyboard) AddKeyBinding(binding KeyBinding) {
+	lk.bindings = append(lk.bindings, binding)
+}
+
+func (lk *LogKeyboard) runKeyBinding(ctx context.Context, key rune) {
+	for _, b := range lk.bindings {
+		if b.Key != key {
+			continue
+		}
+		go func() {
+			_ = tracing.EventWrapFuncForErrGroup(ctx, "menu/custom", tracing.SpanOptions{},
+				func(ctx context.Context) error {
+					err := b.Run(ctx)
+					if err != nil {
+						lk.keyboardError(b.Description, err)
+					}
+					return err
+				})()
+		}()
+		return
+	}
+}
+
+// Listen forwards keyboard events to the returned channel, which remains open while keyboard is
+// suspended to run an interactive command
+func (lk *LogKeyboard) Listen(ctx context.Context, events <-chan keyboard.KeyEvent) <-chan keyboard.KeyEvent {
+	out := make(chan keyboard.KeyEvent)
+	lk.resumed = make(chan (<-chan keyboard.KeyEvent))
+	go func() {
+		for {
+			for e := range events {
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			// keyboard has been closed, wait for it to be resumed
+			select {
+			case events = <-lk.resumed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Suspend releases the terminal so an interactive command can run, logs being held until the returned
+// resume function is called
+func (lk *LogKeyboard) Suspend() (resume func() error) {
+	lk.held.hold()
+	lk.clearNavigationMenu()
+	showCursor()
+	lk.logLevel = NONE
+	_ = keyboard.Close()
+	return func() error {
+		defer lk.held.release()
+		events, err := keyboard.GetKeys(100)
+		if err != nil {
+			return err
+		}
+		lk.logLevel = INFO
+		if lk.resumed != nil {
+			lk.resumed <- events
+		}
+		return nil
+	}
+}
+
+// heldConsumer buffers logs while terminal is used by an interactive command
+type heldConsumer struct {
+	decorated api.LogConsumer
+	mu        sync.Mutex
+	held      bool
+	buffer    []func()
+}
+
+func (h *heldConsumer) hold() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = true
+}
+
+func (h *heldConsumer) release() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = false
+	for _, fn := range h.buffer {
+		fn()
+	}
+	h.buffer = nil
+}
+
+func (h *heldConsumer) do(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.held {
+		h.buffer = append(h.buffer, fn)
+		return
+	}
+	fn()
+}
+
+func (h *heldConsumer) Log(containerName, message string) {
+	h.do(func() { h.decorated.Log(containerName, message) })
+}
+
+func (h *heldConsumer) Err(containerName, message string) {
+	h.do(func() { h.decorated.Err(containerName, message) })
+}
+
+func (h *heldConsumer) Status(container, msg string) {
+	h.do(func() { h.decorated.Status(container, msg) })
+}
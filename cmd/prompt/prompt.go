@@ -17,8 +17,13 @@
 package prompt
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/docker/cli/cli/streams"
@@ -30,6 +35,7 @@ import (
 // UI - prompt user input
 type UI interface {
 	Confirm(message string, defaultValue bool) (bool, error)
+	MultiSelect(message string, options []string) ([]string, error)
 }
 
 func NewPrompt(stdin *streams.In, stdout *streams.Out) UI {
@@ -86,6 +92,21 @@ func (u User) Confirm(message string, defaultValue bool) (bool, error) {
 	return b, err
 }
 
+// MultiSelect asks to pick any number of options
+func (u User) MultiSelect(message string, options []string) ([]string, error) {
+	qs := &survey.MultiSelect{
+		Message: message,
+		Options: options,
+	}
+	var selected []string
+	err := survey.AskOne(qs, &selected, func(options *survey.AskOptions) error {
+		options.Stdio.In = u.stdin
+		options.Stdio.Out = u.stdout
+		return nil
+	})
+	return selected, err
+}
+
 // Pipe - aggregates prompt methods
 type Pipe struct {
 	stdout io.Writer
@@ -99,3 +120,26 @@ func (u Pipe) Confirm(message string, defaultValue bool) (bool, error) {
 	_, _ = fmt.Fscanln(u.stdin, &answer)
 	return utils.StringToBool(answer), nil
 }
+
+// MultiSelect lists numbered options, and reads the numbers of the selected ones separated by spaces or commas
+func (u Pipe) MultiSelect(message string, options []string) ([]string, error) {
+	_, _ = fmt.Fprintln(u.stdout, message)
+	for i, o := range options {
+		_, _ = fmt.Fprintf(u.stdout, "%d) %s\n", i+1, o)
+	}
+	line, err := bufio.NewReader(u.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var selected []string
+	for _, f := range strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		i, err := strconv.Atoi(f)
+		if err != nil || i < 1 || i > len(options) {
+			return nil, fmt.Errorf("invalid selection %q", f)
+		}
+		selected = append(selected, options[i-1])
+	}
+	return selected, nil
+}
@@ -25,8 +25,25 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.19.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+type disabledKey struct{}
+
+// Disable returns a context in which Compose doesn't record spans nor events, whatever the configured
+// TracerProvider is. The span already set on ctx, if any, is hidden so attributes and events aren't added to it.
+func Disable(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, disabledKey{}, true)
+	return trace.ContextWithSpan(ctx, noop.Span{})
+}
+
+func tracer(ctx context.Context) trace.Tracer {
+	if disabled, _ := ctx.Value(disabledKey{}).(bool); disabled {
+		return noop.NewTracerProvider().Tracer("")
+	}
+	return otel.Tracer("")
+}
+
 // SpanWrapFunc wraps a function that takes a context with a trace.Span, marking the status as codes.Error if the
 // wrapped function returns an error.
 //
@@ -37,7 +54,7 @@ import (
 // adding even more levels of function wrapping/indirection.
 func SpanWrapFunc(spanName string, opts SpanOptions, fn func(ctx context.Context) error) func(context.Context) error {
 	return func(ctx context.Context) error {
-		ctx, span := otel.Tracer("").Start(ctx, spanName, opts.SpanStartOptions()...)
+		ctx, span := tracer(ctx).Start(ctx, spanName, opts.SpanStartOptions()...)
 		defer span.End()
 
 		if err := fn(ctx); err != nil {
@@ -60,7 +77,7 @@ func SpanWrapFunc(spanName string, opts SpanOptions, fn func(ctx context.Context
 // adding even more levels of function wrapping/indirection.
 func SpanWrapFuncForErrGroup(ctx context.Context, spanName string, opts SpanOptions, fn func(ctx context.Context) error) func() error {
 	return func() error {
-		ctx, span := otel.Tracer("").Start(ctx, spanName, opts.SpanStartOptions()...)
+		ctx, span := tracer(ctx).Start(ctx, spanName, opts.SpanStartOptions()...)
 		defer span.End()
 
 		if err := fn(ctx); err != nil {
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/docker/compose/v5/internal/tracing"
+)
+
+func TestDisable(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+
+	ctx, root := otel.Tracer("").Start(t.Context(), "root")
+	work := func(ctx context.Context) error {
+		tracing.AddAttributeToSpan(ctx)
+		return tracing.EventWrapFuncForErrGroup(ctx, "event", nil, func(context.Context) error {
+			return nil
+		})()
+	}
+
+	err := tracing.SpanWrapFunc("enabled", nil, work)(ctx)
+	require.NoError(t, err)
+	err = tracing.SpanWrapFunc("disabled", nil, work)(tracing.Disable(ctx))
+	require.NoError(t, err)
+	err = tracing.SpanWrapFuncForErrGroup(tracing.Disable(ctx), "disabled", nil, work)()
+	require.NoError(t, err)
+	root.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "enabled", spans[0].Name())
+	require.Len(t, spans[0].Events(), 1)
+	require.Equal(t, "root", spans[1].Name())
+	require.Empty(t, spans[1].Events())
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/platforms"
 	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 )
 
@@ -71,12 +73,21 @@ type ProjectLoadOptions struct {
 	LoadListeners []LoadListener
 
 	OCI OCIOptions
+	Git GitOptions
 }
 
 type OCIOptions struct {
 	InsecureRegistries []string
 }
 
+type GitOptions struct {
+	// Pin records the commit git refs resolve to when first checked out, and keeps using it until Refresh is set.
+	// By default, refs are resolved again each time the project is loaded
+	Pin bool
+	// Refresh resolves pinned git refs again, and pins the commit they now resolve to
+	Refresh bool
+}
+
 // Compose is the API interface one can use to programmatically use docker/compose in a third-party software
 // Use [compose.NewComposeService] to get an actual instance
 type Compose interface {
@@ -107,11 +118,19 @@ type Compose interface {
 	// Kill executes the equivalent to a `compose kill`
 	Kill(ctx context.Context, projectName string, options KillOptions) error
 	// RunOneOffContainer creates a service oneoff container and starts its dependencies
+	//
+	// Deprecated: use RunOneOff
 	RunOneOffContainer(ctx context.Context, project *types.Project, opts RunOptions) (int, error)
+	// RunOneOff creates a service oneoff container and starts its dependencies
+	RunOneOff(ctx context.Context, project *types.Project, opts RunOneOffOptions) (int, error)
 	// Remove executes the equivalent to a `compose rm`
 	Remove(ctx context.Context, projectName string, options RemoveOptions) error
 	// Exec executes a command in a running service container
+	//
+	// Deprecated: use ExecCommand
 	Exec(ctx context.Context, projectName string, options RunOptions) (int, error)
+	// ExecCommand executes a command in a running service container
+	ExecCommand(ctx context.Context, projectName string, options ExecOptions) (int, error)
 	// Attach STDIN,STDOUT,STDERR to a running service container
 	Attach(ctx context.Context, projectName string, options AttachOptions) error
 	// Copy copies a file/folder between a service container and the local filesystem
@@ -146,18 +165,559 @@ type Compose interface {
 	Generate(ctx context.Context, options GenerateOptions) (*types.Project, error)
 	// Volumes executes the equivalent to a `docker volume ls`
 	Volumes(ctx context.Context, project string, options VolumesOptions) ([]VolumesSummary, error)
+	// VolumeInspect returns low-level information about a project volume, and the services mounting it
+	VolumeInspect(ctx context.Context, project string, volume string) (VolumeInspect, error)
+	// VolumeBrowse runs a one-off container with a project volume mounted, for inspection
+	VolumeBrowse(ctx context.Context, project string, options VolumeBrowseOptions) (int, error)
+	// VolumeSnapshot saves the content of a project volume as a tar archive
+	VolumeSnapshot(ctx context.Context, project string, options VolumeSnapshotOptions) (VolumeSnapshot, error)
+	// VolumeSnapshots lists the snapshots of project volumes
+	VolumeSnapshots(ctx context.Context, project string, options VolumeSnapshotsOptions) ([]VolumeSnapshot, error)
+	// VolumeRestore replaces the content of a project volume with a snapshot
+	VolumeRestore(ctx context.Context, project string, options VolumeRestoreOptions) error
 	// LoadProject loads and validates a Compose project from configuration files.
 	LoadProject(ctx context.Context, options ProjectLoadOptions) (*types.Project, error)
+	// RenewCertificates regenerates TLS certificates for services declaring `x-tls`
+	RenewCertificates(ctx context.Context, project *types.Project, options CertificatesOptions) error
+	// NetworkDoctor checks DNS resolution and connectivity between services on project networks
+	NetworkDoctor(ctx context.Context, project *types.Project, options NetworkDoctorOptions) (NetworkDiagnosis, error)
+	// Networks lists project networks with the services attached to them
+	Networks(ctx context.Context, projectName string, options NetworksOptions) ([]NetworkSummary, error)
+	// NetworkInspect returns low-level information about a project network
+	NetworkInspect(ctx context.Context, projectName string, network string) (NetworkInspect, error)
+	// NetworkConnect connects service containers to a project network at runtime
+	NetworkConnect(ctx context.Context, project *types.Project, options NetworkConnectOptions) error
+	// NetworkDisconnect disconnects service containers from a project network at runtime
+	NetworkDisconnect(ctx context.Context, projectName string, options NetworkDisconnectOptions) error
+	// NetworkRecreate recreates a project network to apply configuration changes, attached containers are reconnected
+	NetworkRecreate(ctx context.Context, project *types.Project, options NetworkRecreateOptions) error
+	// SecurityAudit inspects services configuration for insecure settings and computes a security score
+	SecurityAudit(ctx context.Context, project *types.Project, options SecurityAuditOptions) (SecurityReport, error)
+	// Doctor diagnoses the environment compose runs in, and the project when set
+	Doctor(ctx context.Context, project *types.Project) (DoctorReport, error)
+	// Resume continues an interrupted Up operation, skipping steps it already completed
+	Resume(ctx context.Context, project *types.Project) error
+	// ExplainRecreate tells, for each container of a service, whether it would be recreated by Up and why
+	ExplainRecreate(ctx context.Context, project *types.Project, service string) ([]RecreateExplanation, error)
+	// Features reports which optional features are supported by Compose and the Docker Engine it is connected to
+	Features(ctx context.Context) ([]Feature, error)
+	// Apply converges the engine to the project state, after confirmation of the changes to be made, and returns the applied changes
+	Apply(ctx context.Context, project *types.Project, options ApplyOptions) ([]ApplyChange, error)
+	// WatchState compares running state with the project model, and reports drift until context is canceled
+	WatchState(ctx context.Context, project *types.Project, options WatchStateOptions) error
+	// Migrate renames a project, moving its containers, networks and volumes data to the project passed as target
+	Migrate(ctx context.Context, projectName string, target *types.Project, options MigrateOptions) error
+	// Move recreates a project on another Docker context, transferring images and named volumes data
+	Move(ctx context.Context, project *types.Project, options MoveOptions) error
+	// Describe reconstructs the model of a project from labels of its containers, networks and volumes
+	Describe(ctx context.Context, projectName string) (*types.Project, error)
+	// ImagesTree tells how images used by the project containers share layers, and the storage they consume
+	ImagesTree(ctx context.Context, projectName string, options ImagesOptions) (ImageTree, error)
+	// ImagesPullAudit reports services effective pull policy, local images freshness and which would be pulled by next Up
+	ImagesPullAudit(ctx context.Context, project *types.Project, options ImagesOptions) ([]ImagePullAudit, error)
+	// DiskUsage reports storage consumed by project images, containers, volumes, logs and build cache
+	DiskUsage(ctx context.Context, projectName string, options DiskUsageOptions) (DiskUsageReport, error)
+	// TruncateLogs discards logs of service containers, recreating them if allowed when log files can't be truncated
+	TruncateLogs(ctx context.Context, project *types.Project, options TruncateLogsOptions) error
+	// Orphans lists project containers which are not declared by the compose file, with the reason they're orphans
+	Orphans(ctx context.Context, project *types.Project) ([]OrphanSummary, error)
+	// Adopt turns an orphan container into a container of a declared service with a matching configuration
+	Adopt(ctx context.Context, project *types.Project, options AdoptOptions) error
+	// SwarmDeploy deploys the project as a Swarm stack, converting services, networks, volumes, configs and secrets
+	SwarmDeploy(ctx context.Context, project *types.Project, options SwarmDeployOptions) error
+}
+
+// SwarmDeployOptions group options of the SwarmDeploy API
+type SwarmDeployOptions struct {
+	// Prune removes stack services which are not declared by the compose file anymore
+	Prune bool
+	// ResolveImage queries registry to pin service images to a digest, as "always", "changed" or "never"
+	ResolveImage string
+	// Wait for services to converge, i.e. all their tasks to be running
+	Wait bool
+	// WaitTimeout is the maximum duration to wait for services to converge
+	WaitTimeout time.Duration
+}
+
+const (
+	// ResolveImageAlways queries registry for all services
+	ResolveImageAlways = "always"
+	// ResolveImageChanged queries registry only for services with a new image
+	ResolveImageChanged = "changed"
+	// ResolveImageNever doesn't query registry, swarm nodes use image reference as-is
+	ResolveImageNever = "never"
+)
+
+// OrphanSummary describes a project container not declared by the compose file
+type OrphanSummary struct {
+	ID      string
+	Name    string
+	Service string
+	Image   string
+	State   string
+	// Reason explains why container is considered an orphan
+	Reason string
+}
+
+// AdoptOptions group options of the Adopt API
+type AdoptOptions struct {
+	// Container is the name or ID of the orphan container to adopt
+	Container string
+	// Service is the declared service to adopt container into
+	Service string
+	// Timeout is used to stop orphan container while replaced
+	Timeout *time.Duration
+}
+
+// TruncateLogsOptions group options of the TruncateLogs API
+type TruncateLogsOptions struct {
+	Services []string
+	// MaxSize is set as `max-size` logging option of service containers, which are recreated to apply it
+	MaxSize string
+	// Recreate allows to recreate containers which log files can't be truncated, so they get a new one
+	Recreate bool
+}
+
+// DiskUsageOptions group options of the DiskUsage API
+type DiskUsageOptions struct {
+	// Services to report disk usage for, all project services if empty
+	Services []string
+}
+
+// DiskUsageReport is the storage consumed by a project
+type DiskUsageReport struct {
+	Services []ServiceDiskUsage
+	Volumes  []VolumeDiskUsage
+	// BuildCache is the size of builder cache records not shared with images, only set when project has images built
+	// by Compose. Engine doesn't track the build a cache record was created by, so this includes cache of other builds
+	BuildCache int64 `json:",omitempty"`
+	// Total is the storage consumed by images, containers, volumes and logs, images used by multiple services being
+	// counted once. Build cache is not included
+	Total int64
+}
+
+// ServiceDiskUsage is the storage consumed by a service
+type ServiceDiskUsage struct {
+	Service   string
+	Image     string
+	ImageSize int64
+	// WritableSize is the size of files created or modified by service containers
+	WritableSize int64
+	// LogSize is the size of service containers log files, -1 if those can't be accessed, typically on a remote engine
+	LogSize int64
+}
+
+// VolumeDiskUsage is the storage consumed by a project volume
+type VolumeDiskUsage struct {
+	// Volume is the volume name as declared in the compose file
+	Volume string
+	Name   string
+	// Size is -1 if engine can't compute it, typically for volumes not using the local driver
+	Size int64
+}
+
+// ImagePullAudit describes how pull policy applies to a service image
+type ImagePullAudit struct {
+	Service    string
+	Image      string
+	PullPolicy string
+	// Local tells the image is available in the engine image store
+	Local bool
+	// LocalDigest is the registry digest the local image was pulled from, if any
+	LocalDigest string
+	// RemoteDigest is the digest currently published on registry for the image reference
+	RemoteDigest string
+	// Stale tells the local image was pulled from registry, which now publishes another one
+	Stale bool
+	// WillPull tells the next Up would pull the image
+	WillPull bool
+	// Error reports failure to resolve the image digest on registry
+	Error string
+}
+
+// ImageTree describes images used by a project as a tree of shared layers
+type ImageTree struct {
+	Roots []ImageTreeNode
+	// TotalSize is the sum of images size, as if they didn't share any layer
+	TotalSize int64
+	// UniqueSize is the storage consumed by images, shared layers being counted once
+	UniqueSize int64
+}
+
+// ImageTreeNode is a sequence of layers, shared by all images of the subtree
+type ImageTreeNode struct {
+	// Image is set when last layer of the node is the top layer of an image
+	Image string `json:",omitempty"`
+	// Services using images of the subtree
+	Services []string
+	Layers   int
+	// Size of node layers, not including parent ones
+	Size int64
+	// SizeUnknown is set when size of some layers could not be computed from image history
+	SizeUnknown bool            `json:",omitempty"`
+	Children    []ImageTreeNode `json:",omitempty"`
+}
+
+// MoveOptions group options of the Move API
+type MoveOptions struct {
+	// Context is the Docker context the project is moved to
+	Context string
+	// Push images to their registry for the target engine to pull them, rather than transferring them engine to engine
+	Push bool
+	// Cutover stops the source project before volumes data is copied, and leaves it stopped
+	Cutover bool
+	// WaitTimeout is the maximum duration to wait for services to be running|healthy on the target context
+	WaitTimeout time.Duration
+}
+
+// MigrateOptions group options of the Migrate API
+type MigrateOptions struct {
+	// Timeout for stopping containers of the source project
+	Timeout *time.Duration
+}
+
+// WatchStateOptions group options of the WatchState API
+type WatchStateOptions struct {
+	// Interval between checks. Drift is also checked on engine events, so 0 disables periodic checks
+	Interval time.Duration
+	// Once checks drift a single time and returns
+	Once bool
+	// Consumer receives a report on first check, then each time drift changes
+	Consumer func(report DriftReport) error
+}
+
+const (
+	// DriftMissing is reported for a declared resource which doesn't exist
+	DriftMissing = "missing"
+	// DriftChanged is reported for a container which configuration or image doesn't match the project model
+	DriftChanged = "changed"
+	// DriftStopped is reported for a service container which is not running
+	DriftStopped = "stopped"
+	// DriftUnexpected is reported for a resource labeled with the project but not declared, or exceeding service scale
+	DriftUnexpected = "unexpected"
+)
+
+// Drift is a difference between running state and the project model
+type Drift struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Details  string `json:"details,omitempty"`
+}
+
+// DriftReport is the result of a drift check
+type DriftReport struct {
+	Project   string    `json:"project"`
+	Timestamp time.Time `json:"timestamp"`
+	Drifts    []Drift   `json:"drifts"`
+}
+
+// ApplyOptions group options of the Apply API
+type ApplyOptions struct {
+	// Prune removes containers, networks and volumes of the project which are no longer declared
+	Prune bool
+	// AssumeYes applies changes without asking for confirmation
+	AssumeYes bool
+	// Wait for services to be running or healthy once changes have been applied
+	Wait bool
+}
+
+const (
+	ApplyCreate   = "create"
+	ApplyRecreate = "recreate"
+	ApplyStart    = "start"
+	ApplyRemove   = "remove"
+)
+
+// ApplyChange is a change made by Apply to converge a project
+type ApplyChange struct {
+	// Action is one of ApplyCreate, ApplyRecreate, ApplyStart or ApplyRemove
+	Action string `json:"action"`
+	// Resource is either container, network or volume
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	// Reason tells which aspects of configuration changed for a container to be recreated
+	Reason string `json:"reason,omitempty"`
+}
+
+// String describes change for a plan to be confirmed by user
+func (c ApplyChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)
+	if c.Reason != "" {
+		s += " (" + c.Reason + ")"
+	}
+	return s
+}
+
+// Feature reports whether an optional feature is supported, so tools can degrade gracefully
+type Feature struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	// Reason explains why feature is not supported
+	Reason string `json:"reason,omitempty"`
+}
+
+// SecurityAuditOptions group options of the SecurityAudit API
+type SecurityAuditOptions struct {
+	// Services to audit, all project services if empty
+	Services []string
+}
+
+// Severity of a security finding
+type Severity string
+
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// SecurityReport is the result of SecurityAudit
+type SecurityReport struct {
+	// Score from 0 to 100, decreased by each finding according to its severity
+	Score    int               `json:"score"`
+	Findings []SecurityFinding `json:"findings"`
+}
+
+// SecurityFinding is an insecure setting detected on a service
+type SecurityFinding struct {
+	Service  string   `json:"service"`
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Remediation suggests how to fix the finding
+	Remediation string `json:"remediation"`
+}
+
+// DoctorStatus is the outcome of a Doctor check
+type DoctorStatus string
+
+const (
+	DoctorOK      DoctorStatus = "ok"
+	DoctorWarning DoctorStatus = "warning"
+	DoctorError   DoctorStatus = "error"
+)
+
+// DoctorReport is the result of Doctor
+type DoctorReport struct {
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// DoctorCheck is the result of a single environment check
+type DoctorCheck struct {
+	Name    string       `json:"name"`
+	Status  DoctorStatus `json:"status"`
+	Message string       `json:"message"`
+	// Fix suggests how to resolve a warning or error
+	Fix string `json:"fix,omitempty"`
+}
+
+// RecreateExplanation is the result of ExplainRecreate for a single container
+type RecreateExplanation struct {
+	Container string         `json:"container"`
+	Recreate  bool           `json:"recreate"`
+	Changes   []ConfigChange `json:"changes,omitempty"`
+}
+
+// ConfigChange describes a change in service configuration since container was created.
+// Values which could be secrets are redacted.
+type ConfigChange struct {
+	// Component is the aspect of service configuration which changed, as tracked by ConfigHashComponentsLabel
+	Component string `json:"component"`
+	// Attribute is the changed attribute within Component, unset when change can't be detailed
+	Attribute string `json:"attribute,omitempty"`
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+}
+
+// CertificatesOptions group options of the RenewCertificates API
+type CertificatesOptions struct {
+	// Services to renew certificate for, all services declaring `x-tls` if empty
+	Services []string
+	// CA also renews the project certificate authority, so all certificates are re-issued
+	CA bool
+}
+
+// NetworkDoctorOptions group options of the NetworkDoctor API
+type NetworkDoctorOptions struct {
+	// Services to run checks from and to, all services if empty
+	Services []string
+	// Image used to run probes, must provide `nslookup` and `nc`
+	Image string
+	// Timeout applied to each connectivity probe
+	Timeout time.Duration
+}
+
+// NetworkDiagnosis is the result of NetworkDoctor checks
+type NetworkDiagnosis struct {
+	Networks []NetworkReport
+	// Issues are common misconfigurations detected in the project model or by probes
+	Issues []string
+}
+
+// NetworkReport holds checks ran between services attached to a network
+type NetworkReport struct {
+	// Network is the network name as declared in the compose file
+	Network string
+	// Internal is true for networks without external connectivity
+	Internal bool
+	// Services attached to the network
+	Services []string
+	Probes   []NetworkProbe
+}
+
+// NetworkProbe is the result of DNS resolution and port connectivity checks from a service to another
+type NetworkProbe struct {
+	From string
+	To   string
+	// Addresses To service name resolves to, as seen by From service, empty if resolution failed
+	Addresses []string
+	Ports     []PortProbe
+	// Error is set if probe could not run, typically as From service is not running
+	Error string
+}
+
+// PortProbe is the result of a TCP connection attempt
+type PortProbe struct {
+	Port      uint32
+	Reachable bool
+}
+
+// NetworksOptions group options of the Networks API
+type NetworksOptions struct {
+	// Services to list networks for, all project networks if empty
+	Services []string
+}
+
+// NetworkSummary describes a project network and the services attached to it
+type NetworkSummary struct {
+	// Network is the network name as declared in the compose file
+	Network  string
+	ID       string
+	Name     string
+	Driver   string
+	Scope    string
+	Internal bool
+	Services []string
+}
+
+type NetworkInspect = network.Inspect
+
+// NetworkConnectOptions group options of the NetworkConnect API
+type NetworkConnectOptions struct {
+	// Network to connect to, as declared in the compose file
+	Network  string
+	Services []string
+	// Aliases are additional network-scoped names for the service containers
+	Aliases []string
+}
+
+// NetworkDisconnectOptions group options of the NetworkDisconnect API
+type NetworkDisconnectOptions struct {
+	// Network to disconnect from, as declared in the compose file
+	Network  string
+	Services []string
+	// Force disconnection, even if container is not running
+	Force bool
+}
+
+// NetworkRecreateOptions group options of the NetworkRecreate API
+type NetworkRecreateOptions struct {
+	// Network to recreate, as declared in the compose file
+	Network string
 }
 
 type VolumesOptions struct {
 	Services []string
+	// Usage computes volumes disk usage, which can be slow for large volumes
+	Usage bool
 }
 
 type VolumesSummary = *volume.Volume
 
+// VolumeInspect describes a project volume and the services mounting it
+type VolumeInspect struct {
+	Volume     volume.Volume
+	Services   []string
+	Containers []string
+}
+
+// VolumeBrowseOptions group options of the VolumeBrowse API
+type VolumeBrowseOptions struct {
+	// Volume to browse, as declared in the compose file
+	Volume string
+	// Image used to run the browse container
+	Image string
+	// Command to run, defaults to an interactive shell
+	Command []string
+	// ReadOnly mounts the volume read-only
+	ReadOnly bool
+}
+
+// VolumeSnapshotOptions group options of the VolumeSnapshot API
+type VolumeSnapshotOptions struct {
+	// Volume to snapshot, as declared in the compose file
+	Volume string
+	// Dir is the directory snapshots are stored in, set by COMPOSE_SNAPSHOTS_DIR by default
+	Dir string
+	// Keep is the number of most recent snapshots of the volume to retain, zero retains all
+	Keep int
+	// MaxAge is the age after which snapshots of the volume are removed, zero retains all
+	MaxAge time.Duration
+}
+
+// VolumeSnapshotsOptions group options of the VolumeSnapshots API
+type VolumeSnapshotsOptions struct {
+	// Volume selects the snapshots of a single volume, as declared in the compose file
+	Volume string
+	// Dir is the directory snapshots are stored in, set by COMPOSE_SNAPSHOTS_DIR by default
+	Dir string
+}
+
+// VolumeRestoreOptions group options of the VolumeRestore API
+type VolumeRestoreOptions struct {
+	// Volume to restore, as declared in the compose file
+	Volume string
+	// Snapshot to restore, the most recent one by default
+	Snapshot string
+	// Dir is the directory snapshots are stored in, set by COMPOSE_SNAPSHOTS_DIR by default
+	Dir string
+}
+
+// VolumeSnapshot describes a snapshot of a project volume
+type VolumeSnapshot struct {
+	Volume  string    `json:"volume"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+	Path    string    `json:"path"`
+}
+
 type ScaleOptions struct {
 	Services []string
+	// ScaleDown selects replicas removed when services are scaled down
+	ScaleDown ScaleDownOptions
+}
+
+// ScaleDownPolicy selects which replicas are removed first when a service is scaled down
+type ScaleDownPolicy string
+
+const (
+	// ScaleDownDefault removes obsolete replicas first, then those with the highest container number
+	ScaleDownDefault ScaleDownPolicy = ""
+	// ScaleDownOldest removes the replicas created first
+	ScaleDownOldest ScaleDownPolicy = "oldest"
+	// ScaleDownNewest removes the replicas created last
+	ScaleDownNewest ScaleDownPolicy = "newest"
+	// ScaleDownUnhealthyFirst removes replicas which are not running or unhealthy first, then those still starting
+	ScaleDownUnhealthyFirst ScaleDownPolicy = "unhealthy-first"
+)
+
+// ScaleDownOptions selects replicas removed when services are scaled down
+type ScaleDownOptions struct {
+	Policy ScaleDownPolicy
+	// Indexes are the container numbers of replicas to be removed, by service, before Policy applies
+	Indexes map[string][]int
 }
 
 type WaitOptions struct {
@@ -225,10 +785,102 @@ type BuildOptions struct {
 	SBOM string
 	// Out is the stream to write build progress
 	Out io.Writer
+	// Deadline aborts the operation when reached, zero value means no deadline
+	Deadline time.Time
+	// Overrides set build attributes of individual services, with precedence over the compose model and Args
+	Overrides BuildOverrides
+}
+
+// BuildOverride sets a build attribute of a service, as `compose build --set service.attribute=value` does
+type BuildOverride struct {
+	// Service the override applies to, or "*" for all services
+	Service string
+	// Attribute is the build attribute to set: `args.KEY`, `labels.KEY`, `target`, `dockerfile`, `network`,
+	// `no_cache`, `pull`, `cache_from`, `cache_to`, `platforms` or `tags`. Platforms and tags are set by comma-separated
+	// values, while cache_from and cache_to are set to a single cache spec which includes commas.
+	Attribute string
+	Value     string
+}
+
+// BuildOverrides is a list of BuildOverride, applied in order
+type BuildOverrides []BuildOverride
+
+// Args returns the build args set for service
+func (o BuildOverrides) Args(service string) types.MappingWithEquals {
+	args := types.MappingWithEquals{}
+	for _, override := range o {
+		if key, ok := strings.CutPrefix(override.Attribute, "args."); ok && override.appliesTo(service) {
+			value := override.Value
+			args[key] = &value
+		}
+	}
+	return args
+}
+
+func (o BuildOverride) appliesTo(service string) bool {
+	return o.Service == "*" || o.Service == service
+}
+
+// apply sets attribute on build config. Build args are ignored, as they get merged with other sources by Args
+func (o BuildOverride) apply(build *types.BuildConfig) error {
+	var err error
+	switch attr := o.Attribute; {
+	case strings.HasPrefix(attr, "args.") && len(attr) > len("args."):
+	case strings.HasPrefix(attr, "labels.") && len(attr) > len("labels."):
+		if build.Labels == nil {
+			build.Labels = types.Labels{}
+		}
+		build.Labels[strings.TrimPrefix(attr, "labels.")] = o.Value
+	case attr == "target":
+		build.Target = o.Value
+	case attr == "dockerfile":
+		build.Dockerfile = o.Value
+		build.DockerfileInline = ""
+	case attr == "network":
+		build.Network = o.Value
+	case attr == "no_cache":
+		build.NoCache, err = strconv.ParseBool(o.Value)
+	case attr == "pull":
+		build.Pull, err = strconv.ParseBool(o.Value)
+	case attr == "cache_from":
+		build.CacheFrom = types.StringList{o.Value}
+	case attr == "cache_to":
+		build.CacheTo = types.StringList{o.Value}
+	case attr == "platforms":
+		build.Platforms = splitOverrideList(o.Value)
+	case attr == "tags":
+		build.Tags = splitOverrideList(o.Value)
+	default:
+		return fmt.Errorf("unsupported build attribute %q", attr)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for build attribute %q: %w", o.Attribute, err)
+	}
+	return nil
+}
+
+func splitOverrideList(value string) types.StringList {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
 }
 
 // Apply mutates project according to build options
 func (o BuildOptions) Apply(project *types.Project) error {
+	for _, override := range o.Overrides {
+		if override.Service == "*" {
+			continue
+		}
+		service, ok := project.Services[override.Service]
+		if !ok {
+			return fmt.Errorf("build override for %s: no such service %q", override.Attribute, override.Service)
+		}
+		if service.Build == nil {
+			return fmt.Errorf("build override for %s: service %q has no build section", override.Attribute, override.Service)
+		}
+	}
+
 	platform := project.Environment["DOCKER_DEFAULT_PLATFORM"]
 	for name, service := range project.Services {
 		if service.Provider == nil && service.Image == "" && service.Build == nil {
@@ -252,6 +904,14 @@ func (o BuildOptions) Apply(project *types.Project) error {
 
 		service.Build.Pull = service.Build.Pull || o.Pull
 		service.Build.NoCache = service.Build.NoCache || o.NoCache
+		for _, override := range o.Overrides {
+			if !override.appliesTo(name) {
+				continue
+			}
+			if err := override.apply(service.Build); err != nil {
+				return fmt.Errorf("service %q: %w", name, err)
+			}
+		}
 
 		project.Services[name] = service
 	}
@@ -271,12 +931,23 @@ type CreateOptions struct {
 	Recreate string
 	// RecreateDependencies define the strategy to apply on dependencies services
 	RecreateDependencies string
+	// RecreateServices overrides the Recreate or RecreateDependencies strategy for the given services
+	RecreateServices map[string]string
 	// Inherit reuse anonymous volumes from previous container
 	Inherit bool
+	// PreserveAnonymousVolumes re-attaches all anonymous volumes of replaced containers, even those the image
+	// or service don't declare anymore. Carried over volumes are reported on progress events.
+	PreserveAnonymousVolumes bool
 	// Timeout set delay to wait for container to gracefully stop before sending SIGKILL
 	Timeout *time.Duration
 	// QuietPull makes the pulling process quiet
 	QuietPull bool
+	// SkipBindChecks disables validation of bind mounts sources
+	SkipBindChecks bool
+	// Deadline aborts the operation when reached, zero value means no deadline
+	Deadline time.Time
+	// ScaleDown selects replicas removed when services are scaled down
+	ScaleDown ScaleDownOptions
 }
 
 // StartOptions group options of the Start API
@@ -298,6 +969,29 @@ type StartOptions struct {
 	Services       []string
 	Watch          bool
 	NavigationMenu bool
+	// KeyBindings adds custom entries to the navigation menu
+	KeyBindings []MenuKeyBinding
+	// Deadline aborts the operation when reached, zero value means no deadline
+	Deadline time.Time
+}
+
+// Actions a MenuKeyBinding can run
+const (
+	// MenuActionRestart restarts the service, or all services if not set
+	MenuActionRestart = "restart"
+	// MenuActionExec runs an interactive command in the service container, a shell by default
+	MenuActionExec = "exec"
+	// MenuActionToggleLogs hides, or shows again, the service logs
+	MenuActionToggleLogs = "toggle-logs"
+)
+
+// MenuKeyBinding binds a key of the navigation menu to a compose action
+type MenuKeyBinding struct {
+	Key         string   `yaml:"key" json:"key"`
+	Action      string   `yaml:"action" json:"action"`
+	Service     string   `yaml:"service,omitempty" json:"service,omitempty"`
+	Command     []string `yaml:"command,omitempty" json:"command,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
 type Cascade int
@@ -328,12 +1022,70 @@ type StopOptions struct {
 	Timeout *time.Duration
 	// Services passed in the command line to be stopped
 	Services []string
+	// Deadline aborts the operation when reached, zero value means no deadline
+	Deadline time.Time
 }
 
 // UpOptions group options of the Up API
 type UpOptions struct {
 	Create CreateOptions
 	Start  StartOptions
+	// Notify, if set, receives lifecycle notifications for each service as Up progresses.
+	// Calls are serialized, so the function doesn't need to be safe for concurrent use.
+	Notify func(ServiceEvent)
+	// QuietPhases selects the phases which progress isn't reported. Errors are always reported.
+	QuietPhases QuietPhase
+	// Reload, if set, loads the project again from its compose files. Changes to compose files are then
+	// detected while attached, and can be applied from the navigation menu.
+	Reload func(ctx context.Context) (*types.Project, error)
+	// SyncHosts maintains hosts file entries for hostnames declared by services with published ports
+	SyncHosts bool
+}
+
+// QuietPhase is a bitmask of Up phases which progress can be silenced
+type QuietPhase uint
+
+const (
+	// QuietPhasePull silences image pull progress, same as CreateOptions.QuietPull
+	QuietPhasePull QuietPhase = 1 << iota
+	// QuietPhaseBuild silences image build output
+	QuietPhaseBuild
+	// QuietPhaseCreate silences creation, or recreation, of containers, networks and volumes
+	QuietPhaseCreate
+)
+
+// Has tells if phase is silenced
+func (q QuietPhase) Has(phase QuietPhase) bool {
+	return q&phase != 0
+}
+
+// ServiceEventStatus is a step in a service lifecycle reported by ServiceEvent
+type ServiceEventStatus string
+
+const (
+	// ServicePulling is reported as service image is being pulled
+	ServicePulling ServiceEventStatus = "pulling"
+	// ServiceBuilding is reported as service image is being built
+	ServiceBuilding ServiceEventStatus = "building"
+	// ServiceCreating is reported as service containers are being created, or recreated when configuration changed
+	ServiceCreating ServiceEventStatus = "creating"
+	// ServiceStarting is reported as service containers are being started
+	ServiceStarting ServiceEventStatus = "starting"
+	// ServiceStarted is reported once service containers have been started
+	ServiceStarted ServiceEventStatus = "started"
+	// ServiceHealthy is reported once service containers are healthy, or running for a service without healthcheck,
+	// as Compose waits for a dependency or for --wait
+	ServiceHealthy ServiceEventStatus = "healthy"
+	// ServiceFailed is reported when a step failed, with the error
+	ServiceFailed ServiceEventStatus = "failed"
+)
+
+// ServiceEvent is a typed lifecycle notification for a service, sent to UpOptions.Notify
+type ServiceEvent struct {
+	Service string
+	Status  ServiceEventStatus
+	// Error is set for ServiceFailed
+	Error error
 }
 
 // DownOptions group options of the Down API
@@ -348,6 +1100,10 @@ type DownOptions struct {
 	Images string
 	// Volumes remove volumes, both declared in the `volumes` section and anonymous ones
 	Volumes bool
+	// IncludeCache also removes cache volumes declared by develop.x-cache_volumes when Volumes is set
+	IncludeCache bool
+	// Snapshot saves a snapshot of the volumes before they get removed, when Volumes is set
+	Snapshot bool
 	// Services passed in the command line to be stopped
 	Services []string
 }
@@ -360,6 +1116,12 @@ type ConfigOptions struct {
 	Output string
 	// Resolve image reference to digests
 	ResolveImageDigests bool
+	// Minify drops null and empty values from the rendered model
+	Minify bool
+	// SortKeys renders mappings with keys sorted alphabetically, for a stable output to be diffed
+	SortKeys bool
+	// PreserveAnchors renders the source compose file as is, with YAML anchors, aliases and extensions
+	PreserveAnchors bool
 }
 
 // PushOptions group options of the Push API
@@ -374,6 +1136,8 @@ type PullOptions struct {
 	Quiet           bool
 	IgnoreFailures  bool
 	IgnoreBuildable bool
+	// Deadline aborts the operation when reached, zero value means no deadline
+	Deadline time.Time
 }
 
 // ImagesOptions group options of the Images API
@@ -407,9 +1171,58 @@ type RemoveOptions struct {
 	Force bool
 	// Services passed in the command line to be removed
 	Services []string
+	// KeepLast excludes from removal the given number of containers with the highest replica number, for each service
+	KeepLast int
 }
 
-// RunOptions group options of the Run API
+// RunOneOffOptions group options of the RunOneOff API
+type RunOneOffOptions struct {
+	CreateOptions
+	// Name of the one-off container, generated if not set
+	Name              string
+	Service           string
+	Command           []string
+	Entrypoint        []string
+	Detach            bool
+	AutoRemove        bool
+	Tty               bool
+	Interactive       bool
+	WorkingDir        string
+	User              string
+	Environment       []string
+	CapAdd            []string
+	CapDrop           []string
+	Labels            types.Labels
+	Privileged        bool
+	UseNetworkAliases bool
+	NoDeps            bool
+	// RemoveAfter is the time after which the container, once stopped, is removed by Up or Down
+	RemoveAfter time.Duration
+	// Record is the path to an asciicast file capturing the attached session
+	Record string
+}
+
+// ExecOptions group options of the ExecCommand API
+type ExecOptions struct {
+	Service string
+	// Index of the service container to run command in, first one if not set
+	Index       int
+	Command     []string
+	Detach      bool
+	Tty         bool
+	Interactive bool
+	WorkingDir  string
+	User        string
+	Environment []string
+	Privileged  bool
+	// Record is the path to an asciicast file capturing the attached session
+	Record string
+}
+
+// RunOptions group options of both the RunOneOffContainer and Exec APIs
+//
+// Deprecated: use RunOneOffOptions or ExecOptions, which only expose the options supported by each operation.
+// RunOneOffOptions and ExecOptions methods can be used to convert existing RunOptions.
 type RunOptions struct {
 	CreateOptions
 	// Project is the compose project used to define this app. Might be nil if user ran command just with project name
@@ -435,6 +1248,46 @@ type RunOptions struct {
 	Index int
 }
 
+// RunOneOffOptions converts RunOptions to the options of the RunOneOff API
+func (o RunOptions) RunOneOffOptions() RunOneOffOptions {
+	return RunOneOffOptions{
+		CreateOptions:     o.CreateOptions,
+		Name:              o.Name,
+		Service:           o.Service,
+		Command:           o.Command,
+		Entrypoint:        o.Entrypoint,
+		Detach:            o.Detach,
+		AutoRemove:        o.AutoRemove,
+		Tty:               o.Tty,
+		Interactive:       o.Interactive,
+		WorkingDir:        o.WorkingDir,
+		User:              o.User,
+		Environment:       o.Environment,
+		CapAdd:            o.CapAdd,
+		CapDrop:           o.CapDrop,
+		Labels:            o.Labels,
+		Privileged:        o.Privileged,
+		UseNetworkAliases: o.UseNetworkAliases,
+		NoDeps:            o.NoDeps,
+	}
+}
+
+// ExecOptions converts RunOptions to the options of the ExecCommand API
+func (o RunOptions) ExecOptions() ExecOptions {
+	return ExecOptions{
+		Service:     o.Service,
+		Index:       o.Index,
+		Command:     o.Command,
+		Detach:      o.Detach,
+		Tty:         o.Tty,
+		Interactive: o.Interactive,
+		WorkingDir:  o.WorkingDir,
+		User:        o.User,
+		Environment: o.Environment,
+		Privileged:  o.Privileged,
+	}
+}
+
 // AttachOptions group options of the Attach API
 type AttachOptions struct {
 	Project    *types.Project
@@ -449,8 +1302,14 @@ type AttachOptions struct {
 type EventsOptions struct {
 	Services []string
 	Consumer func(event Event) error
-	Since    string
-	Until    string
+	// Since replays events from engine history created since this timestamp, before live events
+	Since string
+	// Until stops once events created until this timestamp have been consumed
+	Until string
+	// After resumes events following the one identified by this cursor, see Event.Cursor. Can't be set with Since
+	After string
+	// Limit stops once this number of events have been consumed, so events can be paginated
+	Limit int
 }
 
 // Event is a container runtime event served by Events API
@@ -460,6 +1319,8 @@ type Event struct {
 	Container  string
 	Status     string
 	Attributes map[string]string
+	// Cursor identifies the event, so a subsequent call can resume after it with EventsOptions.After
+	Cursor string
 }
 
 // PortOptions group options of the Port API
@@ -491,6 +1352,9 @@ type PublishOptions struct {
 	Application         bool
 	WithEnvironment     bool
 	OCIVersion          OCIVersion
+	// Platforms all service images must support, checked before anything is pushed. This doesn't
+	// build images nor publish per-platform artifacts, the published application is platform-agnostic
+	Platforms []string
 	// Use plain HTTP to access registry. Should only be used for testing purpose
 	InsecureRegistry bool
 }
@@ -514,6 +1378,8 @@ type PsOptions struct {
 	Project  *types.Project
 	All      bool
 	Services []string
+	// Diff reports which aspects of service configuration changed since containers were created, requires Project
+	Diff bool
 }
 
 // CopyOptions group options of the cp API
@@ -536,25 +1402,63 @@ type PortPublisher struct {
 
 // ContainerSummary hold high-level description of a container
 type ContainerSummary struct {
-	ID           string
-	Name         string
-	Names        []string
-	Image        string
-	Command      string
-	Project      string
-	Service      string
-	Created      int64
-	State        string
-	Status       string
-	Health       string
-	ExitCode     int
+	ID       string
+	Name     string
+	Names    []string
+	Image    string
+	Command  string
+	Project  string
+	Service  string
+	Created  int64
+	State    string
+	Status   string
+	Health   string
+	ExitCode int
+	// StartedAt is the time container was last started
+	StartedAt time.Time `json:",omitzero"`
+	// FinishedAt is the time container last exited
+	FinishedAt time.Time `json:",omitzero"`
+	// HealthSince is the time container health reached its current status, approximated from the health check log
+	HealthSince time.Time `json:",omitzero"`
+	// RestartCount is the number of times container has been restarted by engine
+	RestartCount int
 	Publishers   PortPublishers
 	Labels       map[string]string
 	SizeRw       int64 `json:",omitempty"`
 	SizeRootFs   int64 `json:",omitempty"`
 	Mounts       []string
 	Networks     []string
+	// Endpoints describes how the container can be reached on each network it is attached to
+	Endpoints    ContainerEndpoints `json:",omitempty"`
 	LocalVolumes int
+	Diff         []string `json:",omitempty"`
+}
+
+// ContainerEndpoint describes the addresses and names of a container on a network
+type ContainerEndpoint struct {
+	Network     string
+	IPAddress   string   `json:",omitempty"`
+	IPv6Address string   `json:",omitempty"`
+	Aliases     []string `json:",omitempty"`
+	// DNSNames are the names other containers attached to Network can use to resolve this container
+	DNSNames []string `json:",omitempty"`
+	Links    []string `json:",omitempty"`
+}
+
+// ContainerEndpoints is a slice of ContainerEndpoint
+type ContainerEndpoints []ContainerEndpoint
+
+// String renders endpoints as a comma-separated list of `network:address(names)`
+func (e ContainerEndpoints) String() string {
+	var endpoints []string
+	for _, endpoint := range e {
+		s := endpoint.Network + ":" + endpoint.IPAddress
+		if len(endpoint.DNSNames) > 0 {
+			s += "(" + strings.Join(endpoint.DNSNames, " ") + ")"
+		}
+		endpoints = append(endpoints, s)
+	}
+	return strings.Join(endpoints, ",")
 }
 
 // PortPublishers is a slice of PortPublisher
@@ -605,6 +1509,10 @@ type ImageSummary struct {
 	Size        int64
 	Created     *time.Time
 	LastTagTime time.Time
+	// Revision is the git commit the image was built from, if known
+	Revision string
+	// Branch is the git branch the image was built from, if known
+	Branch string
 }
 
 // ServiceStatus hold status about a service
@@ -627,8 +1535,24 @@ type LogOptions struct {
 	Until      string
 	Follow     bool
 	Timestamps bool
+	// OneOff includes logs of one-off containers created by `compose run`
+	OneOff bool
+	// BufferSize is the number of log lines buffered while consumer is busy, 0 to pass lines to consumer directly
+	BufferSize int
+	// Overflow defines how log lines are handled when buffer is full, default to LogOverflowBlock
+	Overflow LogOverflowPolicy
 }
 
+// LogOverflowPolicy defines how log lines are handled when LogConsumer can't keep up
+type LogOverflowPolicy string
+
+const (
+	// LogOverflowBlock pauses reading container logs until LogConsumer catches up
+	LogOverflowBlock LogOverflowPolicy = "block"
+	// LogOverflowDrop discards log lines, reporting the number of dropped lines once completed
+	LogOverflowDrop LogOverflowPolicy = "drop"
+)
+
 // PauseOptions group options of the Pause API
 type PauseOptions struct {
 	// Services passed in the command line to be started
@@ -648,6 +1572,12 @@ type ExportOptions struct {
 type CommitOptions struct {
 	Service   string
 	Reference string
+	// Services to commit when Service isn't set, all services with a container if empty
+	Services []string
+	// Tag of images committed for Services, as `project/service:tag`
+	Tag string
+	// Override receives a compose file pinning Services to the committed images
+	Override io.Writer
 
 	Pause   bool
 	Comment string
@@ -36,3 +36,76 @@ func TestRunOptionsEnvironmentMap(t *testing.T) {
 	assert.Equal(t, *env["ZOT"], "")
 	assert.Check(t, env["QIX"] == nil)
 }
+
+func TestRunOneOffOptionsEnvironmentMap(t *testing.T) {
+	opts := RunOptions{ //nolint:staticcheck
+		Service: "app",
+		Environment: []string{
+			"FOO=BAR",
+			"QIX",
+		},
+	}
+	oneOff := opts.RunOneOffOptions()
+	env := types.NewMappingWithEquals(oneOff.Environment)
+	assert.Equal(t, *env["FOO"], "BAR")
+	assert.Check(t, env["QIX"] == nil)
+
+	exec := opts.ExecOptions()
+	assert.DeepEqual(t, exec.Environment, []string{"FOO=BAR", "QIX"})
+}
+
+func TestRunOptionsConversion(t *testing.T) {
+	opts := RunOptions{ //nolint:staticcheck
+		Service:    "app",
+		Command:    []string{"sh"},
+		Entrypoint: []string{"/entrypoint"},
+		User:       "nobody",
+		Index:      2,
+	}
+	assert.DeepEqual(t, opts.ExecOptions(), ExecOptions{
+		Service: "app",
+		Command: []string{"sh"},
+		User:    "nobody",
+		Index:   2,
+	})
+	assert.DeepEqual(t, opts.RunOneOffOptions(), RunOneOffOptions{
+		Service:    "app",
+		Command:    []string{"sh"},
+		Entrypoint: []string{"/entrypoint"},
+		User:       "nobody",
+	})
+}
+
+func TestBuildOptionsOverrides(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {Name: "web", Build: &types.BuildConfig{Context: ".", Target: "prod"}},
+			"api": {Name: "api", Build: &types.BuildConfig{Context: "api"}},
+			"db":  {Name: "db", Image: "postgres"},
+		},
+	}
+	options := BuildOptions{Overrides: BuildOverrides{
+		{Service: "web", Attribute: "target", Value: "dev"},
+		{Service: "web", Attribute: "args.VERSION", Value: "1.2"},
+		{Service: "*", Attribute: "no_cache", Value: "true"},
+		{Service: "api", Attribute: "labels.team", Value: "core"},
+		{Service: "api", Attribute: "cache_from", Value: "type=registry,ref=api:cache"},
+	}}
+	assert.NilError(t, options.Apply(project))
+	assert.Equal(t, project.Services["web"].Build.Target, "dev")
+	assert.Check(t, project.Services["web"].Build.NoCache)
+	assert.Check(t, project.Services["api"].Build.NoCache)
+	assert.DeepEqual(t, project.Services["api"].Build.Labels, types.Labels{"team": "core"})
+	assert.DeepEqual(t, project.Services["api"].Build.CacheFrom, types.StringList{"type=registry,ref=api:cache"})
+
+	args := options.Overrides.Args("web")
+	assert.Equal(t, *args["VERSION"], "1.2")
+	assert.Equal(t, len(options.Overrides.Args("api")), 0)
+
+	err := BuildOptions{Overrides: BuildOverrides{{Service: "db", Attribute: "target", Value: "dev"}}}.Apply(project)
+	assert.Error(t, err, `build override for target: service "db" has no build section`)
+	err = BuildOptions{Overrides: BuildOverrides{{Service: "unknown", Attribute: "target", Value: "dev"}}}.Apply(project)
+	assert.Error(t, err, `build override for target: no such service "unknown"`)
+	err = BuildOptions{Overrides: BuildOverrides{{Service: "web", Attribute: "context", Value: "."}}}.Apply(project)
+	assert.Error(t, err, `service "web": unsupported build attribute "context"`)
+}
@@ -0,0 +1,299 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package conformance is a black-box test suite for implementations of the Compose API. It checks the contract
+// the Compose CLI relies on, which any backend must honor, then, when a Docker engine is available, runs a project
+// and checks the behavior Compose users and tools rely on: Up/Ps/Logs/Down semantics, labels set on resources,
+// and orphan containers handling.
+//
+// Example usage, from a test of an alternate implementation:
+//
+//	func TestConformance(t *testing.T) {
+//	    apiClient, _ := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+//	    conformance.Suite{Backend: newBackend(apiClient), Client: apiClient}.Run(t)
+//	}
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// DefaultImage is used by test services when Suite doesn't set one
+const DefaultImage = "alpine:latest"
+
+// Suite runs conformance tests against Backend
+type Suite struct {
+	// Backend is the Compose API implementation under test
+	Backend api.Compose
+	// Client is used to inspect resources created by Backend on the Docker engine. When not set, only the
+	// backend contract is checked, as for backends which don't manage containers with a Docker engine
+	Client client.APIClient
+	// Image used by test services, which must provide a shell. Defaults to DefaultImage
+	Image string
+	// Timeout for each operation, defaults to 2 minutes
+	Timeout time.Duration
+}
+
+const projectTemplate = `
+services:
+  app:
+    image: %[1]s
+    command: ["sleep", "infinity"]
+    init: true
+    scale: 2
+  echo:
+    image: %[1]s
+    command: ["sh", "-c", "echo hello conformance && sleep infinity"]
+    init: true
+`
+
+// Run executes all conformance tests. Each test running containers uses its own project, removed when test completes.
+func (s Suite) Run(t *testing.T) {
+	t.Helper()
+	if s.Image == "" {
+		s.Image = DefaultImage
+	}
+	if s.Timeout == 0 {
+		s.Timeout = 2 * time.Minute
+	}
+	t.Run("load project", s.testLoadProject)
+	t.Run("query missing project", s.testQueryMissingProject)
+	t.Run("lookup missing resource", s.testLookupMissingResource)
+	t.Run("operations", s.testOperations)
+	if s.Client == nil {
+		t.Log("Client is not set, skipping tests running projects on a Docker engine")
+		return
+	}
+	t.Run("up ps down", s.testUpPsDown)
+	t.Run("labels", s.testLabels)
+	t.Run("logs", s.testLogs)
+	t.Run("orphans", s.testOrphans)
+}
+
+// loadProject loads the test project, with a unique name, and registers its removal on test cleanup.
+// Services listed in without are removed from the model, as if user deleted them from compose file.
+func (s Suite) loadProject(t *testing.T, without ...string) *types.Project {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte(fmt.Sprintf(projectTemplate, s.Image)), 0o600))
+
+	name := strings.ToLower(strings.NewReplacer("/", "-", " ", "-", "_", "-").Replace("conformance-" + t.Name()))
+	project, err := s.Backend.LoadProject(context.TODO(), api.ProjectLoadOptions{
+		ProjectName: name,
+		ConfigPaths: []string{file},
+		WorkingDir:  dir,
+	})
+	assert.NilError(t, err)
+	for _, service := range without {
+		// not using WithServicesDisabled, as disabled services are not considered orphans
+		delete(project.Services, service)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+		defer cancel()
+		_ = s.Backend.Down(ctx, project.Name, api.DownOptions{RemoveOrphans: true, Volumes: true})
+	})
+	return project
+}
+
+func (s Suite) context(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func (s Suite) up(t *testing.T, project *types.Project) {
+	t.Helper()
+	err := s.Backend.Up(s.context(t), project, api.UpOptions{
+		Create: api.CreateOptions{
+			Services: project.ServiceNames(),
+			Recreate: api.RecreateDiverged,
+		},
+		Start: api.StartOptions{
+			Project:  project,
+			Services: project.ServiceNames(),
+			Wait:     true,
+		},
+	})
+	assert.NilError(t, err)
+}
+
+func (s Suite) testUpPsDown(t *testing.T) {
+	project := s.loadProject(t)
+	ctx := s.context(t)
+	s.up(t, project)
+
+	containers, err := s.Backend.Ps(ctx, project.Name, api.PsOptions{})
+	assert.NilError(t, err)
+	var services []string
+	for _, c := range containers {
+		assert.Equal(t, c.Project, project.Name)
+		assert.Equal(t, c.State, container.StateRunning, "container %s", c.Name)
+		services = append(services, c.Service)
+	}
+	slices.Sort(services)
+	assert.DeepEqual(t, services, []string{"app", "app", "echo"})
+
+	containers, err = s.Backend.Ps(ctx, project.Name, api.PsOptions{Services: []string{"echo"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 1)
+
+	// Up is idempotent: running it again with the same model doesn't recreate containers
+	s.up(t, project)
+	again, err := s.Backend.Ps(ctx, project.Name, api.PsOptions{Services: []string{"echo"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(again), 1)
+	assert.Equal(t, again[0].ID, containers[0].ID)
+
+	err = s.Backend.Down(ctx, project.Name, api.DownOptions{Project: project})
+	assert.NilError(t, err)
+	containers, err = s.Backend.Ps(ctx, project.Name, api.PsOptions{All: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 0)
+
+	networks, err := s.Client.NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", api.ProjectLabel+"="+project.Name)),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(networks), 0, "down must remove project networks")
+}
+
+func (s Suite) testLabels(t *testing.T) {
+	project := s.loadProject(t)
+	ctx := s.context(t)
+	s.up(t, project)
+
+	containers, err := s.Client.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", api.ProjectLabel+"="+project.Name)),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 3)
+	numbers := map[string][]string{}
+	for _, c := range containers {
+		labels := c.Labels
+		service := labels[api.ServiceLabel]
+		assert.Check(t, slices.Contains(project.ServiceNames(), service), "unexpected %s label %q", api.ServiceLabel, service)
+		assert.Check(t, labels[api.ConfigHashLabel] != "", "missing %s label", api.ConfigHashLabel)
+		assert.Check(t, labels[api.VersionLabel] != "", "missing %s label", api.VersionLabel)
+		assert.Equal(t, labels[api.OneoffLabel], "False")
+		assert.Equal(t, labels[api.WorkingDirLabel], project.WorkingDir)
+		numbers[service] = append(numbers[service], labels[api.ContainerNumberLabel])
+	}
+	slices.Sort(numbers["app"])
+	assert.DeepEqual(t, numbers, map[string][]string{"app": {"1", "2"}, "echo": {"1"}})
+
+	networks, err := s.Client.NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", api.ProjectLabel+"="+project.Name)),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(networks), 1)
+	assert.Equal(t, networks[0].Labels[api.NetworkLabel], "default")
+}
+
+// logCollector is a LogConsumer recording log lines by container
+type logCollector struct {
+	mu    sync.Mutex
+	lines map[string][]string
+}
+
+func (l *logCollector) Log(containerName, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines[containerName] = append(l.lines[containerName], message)
+}
+
+func (l *logCollector) Err(containerName, message string) {
+	l.Log(containerName, message)
+}
+
+func (l *logCollector) Status(string, string) {}
+
+func (l *logCollector) contains(message string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, lines := range l.lines {
+		if slices.ContainsFunc(lines, func(line string) bool {
+			return strings.Contains(line, message)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Suite) testLogs(t *testing.T) {
+	project := s.loadProject(t)
+	ctx := s.context(t)
+	s.up(t, project)
+
+	poll.WaitOn(t, func(t poll.LogT) poll.Result {
+		consumer := &logCollector{lines: map[string][]string{}}
+		err := s.Backend.Logs(ctx, project.Name, consumer, api.LogOptions{Services: []string{"echo"}})
+		if err != nil {
+			return poll.Error(err)
+		}
+		if !consumer.contains("hello conformance") {
+			return poll.Continue("echo service didn't log yet")
+		}
+		if len(consumer.lines) != 1 {
+			return poll.Error(fmt.Errorf("logs must be restricted to selected service, got %d containers", len(consumer.lines)))
+		}
+		return poll.Success()
+	}, poll.WithTimeout(30*time.Second))
+}
+
+func (s Suite) testOrphans(t *testing.T) {
+	project := s.loadProject(t)
+	ctx := s.context(t)
+	s.up(t, project)
+
+	// service echo is removed from the model, its container becomes an orphan
+	reduced := s.loadProject(t, "echo")
+	s.up(t, reduced)
+	containers, err := s.Backend.Ps(ctx, project.Name, api.PsOptions{Services: []string{"echo"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 1, "orphan containers must be kept unless RemoveOrphans is set")
+
+	orphans, err := s.Backend.Orphans(ctx, reduced)
+	assert.NilError(t, err)
+	assert.Equal(t, len(orphans), 1)
+	assert.Equal(t, orphans[0].Service, "echo")
+
+	err = s.Backend.Down(ctx, project.Name, api.DownOptions{Project: reduced, RemoveOrphans: true})
+	assert.NilError(t, err)
+	containers, err = s.Backend.Ps(ctx, project.Name, api.PsOptions{All: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 0, "down with RemoveOrphans must remove orphan containers")
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// missingProject is the name of a project tests expect to have no resources
+const missingProject = "conformance-missing"
+
+// testLoadProject checks projects can be loaded, as all commands rely on LoadProject
+func (s Suite) testLoadProject(t *testing.T) {
+	project := s.loadProject(t)
+	assert.DeepEqual(t, project.ServiceNames(), []string{"app", "echo"})
+}
+
+// testQueryMissingProject checks queries on a project without resources report no resources rather than failing
+func (s Suite) testQueryMissingProject(t *testing.T) {
+	ctx := s.context(t)
+	containers, err := s.Backend.Ps(ctx, missingProject, api.PsOptions{All: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 0)
+
+	images, err := s.Backend.Images(ctx, missingProject, api.ImagesOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(images), 0)
+
+	_, err = s.Backend.List(ctx, api.ListOptions{All: true})
+	assert.NilError(t, err)
+}
+
+// testLookupMissingResource checks lookup of a missing resource fails with api.ErrNotFound
+func (s Suite) testLookupMissingResource(t *testing.T) {
+	ctx := s.context(t)
+	_, err := s.Backend.Describe(ctx, missingProject)
+	assert.Check(t, api.IsNotFoundError(err), "Describe: %v", err)
+
+	_, err = s.Backend.VolumeInspect(ctx, missingProject, "missing")
+	assert.Check(t, api.IsNotFoundError(err), "VolumeInspect: %v", err)
+}
+
+// testOperations checks operations on a project either succeed or fail with api.ErrNotImplemented, when backend
+// doesn't support them
+func (s Suite) testOperations(t *testing.T) {
+	ctx := s.context(t)
+	operations := map[string]func() error{
+		"Stop": func() error {
+			return s.Backend.Stop(ctx, missingProject, api.StopOptions{})
+		},
+		"Down": func() error {
+			return s.Backend.Down(ctx, missingProject, api.DownOptions{})
+		},
+		"Kill": func() error {
+			return s.Backend.Kill(ctx, missingProject, api.KillOptions{})
+		},
+		"Remove": func() error {
+			return s.Backend.Remove(ctx, missingProject, api.RemoveOptions{})
+		},
+	}
+	for name, operation := range operations {
+		err := operation()
+		assert.Check(t, err == nil || api.IsErrNotImplemented(err), "%s: %v", name, err)
+	}
+}
@@ -18,6 +18,8 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 const (
@@ -45,8 +47,70 @@ var (
 	ErrParsingFailed = errors.New("parsing failed")
 	// ErrNoResources is returned when operation didn't selected any resource
 	ErrNoResources = errors.New("no resources")
+	// ErrTimedOut is returned when an operation didn't complete before its deadline
+	ErrTimedOut = errors.New("operation timed out")
 )
 
+// ErrCode classifies errors returned by the API, so callers can decide to retry or abort without parsing error messages
+type ErrCode string
+
+const (
+	// ErrCodeNotFound is set on errors for a missing resource
+	ErrCodeNotFound ErrCode = "not_found"
+	// ErrCodeDependencyFailed is set on errors for a service dependency which failed to start or complete
+	ErrCodeDependencyFailed ErrCode = "dependency_failed"
+	// ErrCodePullDenied is set on errors for an image pull denied by registry
+	ErrCodePullDenied ErrCode = "pull_denied"
+	// ErrCodePortConflict is set on errors for a container which can't start as a published port is already in use
+	ErrCodePortConflict ErrCode = "port_conflict"
+	// ErrCodeHealthTimeout is set on errors for services which didn't become healthy in time
+	ErrCodeHealthTimeout ErrCode = "health_timeout"
+	// ErrCodeInvalidConfig is set on errors loading the Compose model
+	ErrCodeInvalidConfig ErrCode = "config_invalid"
+	// ErrCodeDaemonUnavailable is set on errors for a Docker daemon which can't be reached
+	ErrCodeDaemonUnavailable ErrCode = "daemon_unavailable"
+	// ErrCodeTimedOut is set on errors for an operation which didn't complete before its deadline
+	ErrCodeTimedOut ErrCode = "timed_out"
+)
+
+// CodedError attaches an ErrCode to an error
+type CodedError struct {
+	Code ErrCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrCode wraps err with code, unless err is nil
+func WithErrCode(err error, code ErrCode) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCode returns the code attached to the unwrapped error, ErrCodeNotFound for ErrNotFound,
+// ErrCodeTimedOut for ErrTimedOut, or an empty code
+func ErrorCode(err error) ErrCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	if IsNotFoundError(err) {
+		return ErrCodeNotFound
+	}
+	if IsTimedOutError(err) {
+		return ErrCodeTimedOut
+	}
+	return ""
+}
+
 // IsNotFoundError returns true if the unwrapped error is ErrNotFound
 func IsNotFoundError(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -67,6 +131,11 @@ func IsUnknownError(err error) bool {
 	return errors.Is(err, ErrUnknown)
 }
 
+// IsTimedOutError returns true if the unwrapped error is ErrTimedOut
+func IsTimedOutError(err error) bool {
+	return errors.Is(err, ErrTimedOut)
+}
+
 // IsErrUnsupportedFlag returns true if the unwrapped error is ErrUnsupportedFlag
 func IsErrUnsupportedFlag(err error) bool {
 	return errors.Is(err, ErrUnsupportedFlag)
@@ -86,3 +155,39 @@ func IsErrParsingFailed(err error) bool {
 func IsErrCanceled(err error) bool {
 	return errors.Is(err, ErrCanceled)
 }
+
+// PolicyViolation is reported by a policy denying an operation
+type PolicyViolation struct {
+	// Rule is the identifier of the policy rule being violated
+	Rule string `json:"rule,omitempty"`
+	// Service is the service violating the rule, if any
+	Service string `json:"service,omitempty"`
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// PolicyDeniedError is returned when a policy denies an operation
+type PolicyDeniedError struct {
+	Operation  string
+	Violations []PolicyViolation
+}
+
+func (e *PolicyDeniedError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s denied by policy:", e.Operation)
+	for _, v := range e.Violations {
+		sb.WriteString("\n - ")
+		if v.Rule != "" {
+			fmt.Fprintf(&sb, "[%s] ", v.Rule)
+		}
+		if v.Service != "" {
+			fmt.Fprintf(&sb, "service %q: ", v.Service)
+		}
+		sb.WriteString(v.Message)
+	}
+	return sb.String()
+}
+
+func (e *PolicyDeniedError) Unwrap() error {
+	return ErrForbidden
+}
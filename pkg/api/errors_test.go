@@ -31,6 +31,16 @@ func TestIsNotFound(t *testing.T) {
 	assert.Assert(t, !IsNotFoundError(errors.New("another error")))
 }
 
+func TestErrorCode(t *testing.T) {
+	err := fmt.Errorf("service web: %w", WithErrCode(errors.New("port is already allocated"), ErrCodePortConflict))
+	assert.Equal(t, ErrorCode(err), ErrCodePortConflict)
+	assert.Equal(t, err.Error(), "service web: port is already allocated")
+
+	assert.Equal(t, ErrorCode(fmt.Errorf(`object "name": %w`, ErrNotFound)), ErrCodeNotFound)
+	assert.Equal(t, ErrorCode(errors.New("another error")), ErrCode(""))
+	assert.NilError(t, WithErrCode(nil, ErrCodeHealthTimeout))
+}
+
 func TestIsAlreadyExists(t *testing.T) {
 	err := fmt.Errorf(`object "name": %w`, ErrAlreadyExists)
 	assert.Assert(t, IsAlreadyExistsError(err))
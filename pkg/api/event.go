@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"time"
 )
 
 // EventStatus indicates the status of an action
@@ -65,6 +66,12 @@ const (
 	StatusCopied     = "Copied"
 	StatusExporting  = "Exporting"
 	StatusExported   = "Exported"
+	StatusExecuted   = "Executed"
+	StatusRetrying   = "Retrying"
+	StatusSkipped    = "Skipped"
+
+	// StatusWouldRemove reports a resource a dry-run operation would remove
+	StatusWouldRemove = "Would remove"
 )
 
 // Resource represents status change and progress for a compose resource.
@@ -101,3 +108,39 @@ type EventProcessor interface {
 	// Done is triggered as a Compose operation completed
 	Done(operation string, success bool)
 }
+
+// OperationMetrics reports telemetry about a completed Compose operation
+type OperationMetrics struct {
+	// Operation is the name of the Compose operation, i.e. "up" or "down"
+	Operation string
+	// Duration is the time spent running the operation
+	Duration time.Duration
+	// Success is false if the operation failed
+	Success bool
+	// Services is the number of services the operation applied to, 0 when unknown
+	Services int
+}
+
+// MetricsHook is notified as a Compose operation completed
+type MetricsHook func(ctx context.Context, metrics OperationMetrics)
+
+// ServiceTimings reports time spent on a service by a Compose operation, per phase. When a service has
+// multiple containers, phase duration is the longest one.
+type ServiceTimings struct {
+	Service    string
+	Pull       time.Duration
+	Build      time.Duration
+	Create     time.Duration
+	Start      time.Duration
+	HealthWait time.Duration
+	Stop       time.Duration
+	Remove     time.Duration
+}
+
+// Total is the time spent on all phases for the service
+func (t ServiceTimings) Total() time.Duration {
+	return t.Pull + t.Build + t.Create + t.Start + t.HealthWait + t.Stop + t.Remove
+}
+
+// ServiceTimingsHook is notified with services timings as a Compose up or down operation completed
+type ServiceTimingsHook func(operation string, timings []ServiceTimings)
@@ -29,10 +29,16 @@ const (
 	ServiceLabel = "com.docker.compose.service"
 	// ConfigHashLabel stores configuration hash for a compose service
 	ConfigHashLabel = "com.docker.compose.config-hash"
+	// ConfigHashComponentsLabel stores hashes of the distinct aspects of a compose service configuration
+	ConfigHashComponentsLabel = "com.docker.compose.config-hash.components"
+	// LabelsSchemaLabel stores the version of the labels schema used by compose to create a container
+	LabelsSchemaLabel = "com.docker.compose.labels-schema"
 	// ContainerNumberLabel stores the container index of a replicated service
 	ContainerNumberLabel = "com.docker.compose.container-number"
 	// VolumeLabel allow to track resource related to a compose volume
 	VolumeLabel = "com.docker.compose.volume"
+	// CacheVolumeLabel stores value 'True' for volumes created to store a service cache path declared by develop.x-cache_volumes
+	CacheVolumeLabel = "com.docker.compose.volume.cache"
 	// NetworkLabel allow to track resource related to a compose network
 	NetworkLabel = "com.docker.compose.network"
 	// WorkingDirLabel stores absolute path to compose project working directory
@@ -55,8 +61,29 @@ const (
 	ImageBuilderLabel = "com.docker.compose.image.builder"
 	// ContainerReplaceLabel is set when container is created to replace another container (recreated)
 	ContainerReplaceLabel = "com.docker.compose.replace"
+	// InitContainerLabel stores the name of the init container, for containers run before service starts
+	InitContainerLabel = "com.docker.compose.init-container"
+	// InitJobLabel stores the name of the init job, for containers run once per volume lifetime after service started
+	InitJobLabel = "com.docker.compose.init-job"
+	// InitJobVolumeLabel stores the volume, by name and creation date, an init job completed for
+	InitJobVolumeLabel = "com.docker.compose.init-job.volume"
+	// SidecarOfLabel stores the name of the primary service a sidecar service is attached to
+	SidecarOfLabel = "com.docker.compose.sidecar-of"
+	// OneoffTTLLabel stores the duration after which a one-off container, once stopped, can be removed
+	OneoffTTLLabel = "com.docker.compose.oneoff.ttl"
+	// GitRevisionLabel stores the git commit an image was built from
+	GitRevisionLabel = "org.opencontainers.image.revision"
+	// GitBranchLabel stores the git branch an image was built from
+	GitBranchLabel = "com.docker.compose.git.branch"
+	// BuildTimeLabel stores the time an image was built, as RFC3339
+	BuildTimeLabel = "org.opencontainers.image.created"
 )
 
+// LabelsSchemaVersion is the current version of the labels schema.
+// Containers created by older compose releases don't have LabelsSchemaLabel set, and are considered
+// as schema version 1, which only stores the global ConfigHashLabel.
+const LabelsSchemaVersion = "2"
+
 // ComposeVersion is the compose tool version as declared by label VersionLabel
 var ComposeVersion string
 
@@ -0,0 +1,228 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// ComposeSpecSchema is the JSON schema of the compose file format, referenced by option schemas for the project model
+const ComposeSpecSchema = "https://raw.githubusercontent.com/compose-spec/compose-spec/main/schema/compose-spec.json"
+
+// JSONSchema is the subset of JSON schema used to describe Compose API options and compose file extensions
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	timeType          = reflect.TypeOf(time.Time{})
+	projectType       = reflect.TypeOf(types.Project{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaOf generates the JSON schema of Go type t. Struct fields are named after the tag key, if set, then
+// `json` tag, or Go field name. Functions, channels and interfaces (but `any`) can't be set by a serialized
+// invocation, so such fields are omitted.
+func SchemaOf(t reflect.Type, tag string) *JSONSchema {
+	return schemaOf(t, tag, map[reflect.Type]bool{})
+}
+
+func schemaOf(t reflect.Type, tag string, visiting map[reflect.Type]bool) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType:
+		return &JSONSchema{Type: "integer", Description: "duration in nanoseconds"}
+	case timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case projectType:
+		return &JSONSchema{Ref: ComposeSpecSchema}
+	}
+	if t.Kind() != reflect.String && t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: "byte"}
+		}
+		return &JSONSchema{Type: "array", Items: schemaOf(t.Elem(), tag, visiting)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), tag, visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			// recursive type, not expanded
+			return &JSONSchema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		addProperties(schema, t, tag, visiting)
+		return schema
+	default:
+		// `any`, accepts all values
+		return &JSONSchema{}
+	}
+}
+
+func addProperties(schema *JSONSchema, t reflect.Type, tag string, visiting map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if (!field.IsExported() && !field.Anonymous) || !serializable(field.Type) {
+			continue
+		}
+		name, ok := fieldName(field, tag)
+		if !ok {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addProperties(schema, embedded, tag, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemaOf(field.Type, tag, visiting)
+	}
+}
+
+// fieldName returns the name set by struct tag for field, or false if field is ignored
+func fieldName(field reflect.StructField, tag string) (string, bool) {
+	for _, key := range []string{tag, "json"} {
+		value, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(value, ",")
+		if name == "-" {
+			return "", false
+		}
+		if name == "" && strings.Contains(value, ",squash") {
+			return "", true
+		}
+		return name, true
+	}
+	return "", true
+}
+
+func serializable(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	default:
+		return true
+	}
+}
+
+// OptionsSchemas returns the JSON schemas of the options accepted by Compose API operations, indexed by type name
+func OptionsSchemas() map[string]*JSONSchema {
+	schemas := map[string]*JSONSchema{}
+	for _, o := range []any{
+		AdoptOptions{},
+		ApplyOptions{},
+		AttachOptions{},
+		BuildOptions{},
+		CertificatesOptions{},
+		CommitOptions{},
+		ConfigOptions{},
+		CopyOptions{},
+		CreateOptions{},
+		DiskUsageOptions{},
+		DownOptions{},
+		EventsOptions{},
+		ExecOptions{},
+		ExportOptions{},
+		GenerateOptions{},
+		ImagesOptions{},
+		KillOptions{},
+		ListOptions{},
+		LogOptions{},
+		MigrateOptions{},
+		MoveOptions{},
+		NetworkConnectOptions{},
+		NetworkDisconnectOptions{},
+		NetworkDoctorOptions{},
+		NetworkRecreateOptions{},
+		NetworksOptions{},
+		PauseOptions{},
+		PortOptions{},
+		PsOptions{},
+		PublishOptions{},
+		PullOptions{},
+		PushOptions{},
+		RemoveOptions{},
+		RestartOptions{},
+		RunOneOffOptions{},
+		ScaleOptions{},
+		SecurityAuditOptions{},
+		StartOptions{},
+		StopOptions{},
+		SwarmDeployOptions{},
+		TruncateLogsOptions{},
+		UpOptions{},
+		VizOptions{},
+		VolumeBrowseOptions{},
+		VolumesOptions{},
+		WaitOptions{},
+		WatchOptions{},
+		WatchStateOptions{},
+	} {
+		t := reflect.TypeOf(o)
+		schemas[t.Name()] = SchemaOf(t, "json")
+	}
+	return schemas
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSchemaOf(t *testing.T) {
+	type node struct {
+		Name     string `mapstructure:"name"`
+		Children []node `mapstructure:"children"`
+	}
+	type embedded struct {
+		Labels map[string]string
+	}
+	type options struct {
+		embedded
+		Project  *AttachOptions
+		Timeout  *time.Duration
+		Since    time.Time
+		Tree     node
+		Renamed  string `json:"renamed,omitempty"`
+		Ignored  string `json:"-"`
+		Value    any
+		Consumer func(Event) error
+		Events   chan Event
+	}
+
+	assert.DeepEqual(t, SchemaOf(reflect.TypeOf(options{}), "mapstructure"), &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"Labels": {Type: "object", AdditionalProperties: &JSONSchema{Type: "string"}},
+			"Project": {Type: "object", Properties: map[string]*JSONSchema{
+				"Project":    {Ref: ComposeSpecSchema},
+				"Service":    {Type: "string"},
+				"Index":      {Type: "integer"},
+				"DetachKeys": {Type: "string"},
+				"NoStdin":    {Type: "boolean"},
+				"Proxy":      {Type: "boolean"},
+			}},
+			"Timeout": {Type: "integer", Description: "duration in nanoseconds"},
+			"Since":   {Type: "string", Format: "date-time"},
+			"Tree": {Type: "object", Properties: map[string]*JSONSchema{
+				"name":     {Type: "string"},
+				"children": {Type: "array", Items: &JSONSchema{Type: "object"}},
+			}},
+			"renamed": {Type: "string"},
+			"Value":   {},
+		},
+	})
+}
+
+func TestOptionsSchemas(t *testing.T) {
+	schemas := OptionsSchemas()
+	up, ok := schemas["UpOptions"]
+	assert.Assert(t, ok)
+	assert.Equal(t, up.Properties["Create"].Properties["Recreate"].Type, "string")
+	_, ok = up.Properties["Start"].Properties["Attach"]
+	assert.Assert(t, !ok, "LogConsumer interface can't be serialized")
+	for name, schema := range schemas {
+		assert.Equal(t, schema.Type, "object", name)
+	}
+}
@@ -0,0 +1,314 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package nop is a sample Compose backend, accepting all operations without doing anything and reporting
+// no resources. It demonstrates how alternate backends are registered and selected by Docker context metadata.
+package nop
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+// Name is the name the no-op backend is registered with, to be set as compose-backend Docker context metadata
+const Name = "nop"
+
+// Register makes the no-op backend available to be selected by Docker context metadata
+func Register() error {
+	return compose.RegisterBackend(Name, New)
+}
+
+// Backend implements api.Compose without doing anything
+type Backend struct {
+	// loader is used to load projects, which doesn't involve Docker engine
+	loader api.Compose
+}
+
+var _ api.Compose = &Backend{}
+
+// New creates a no-op backend
+func New(dockerCli command.Cli, options ...compose.Option) (api.Compose, error) {
+	loader, err := compose.NewDockerBackend(dockerCli, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{loader: loader}, nil
+}
+
+func (b *Backend) Adopt(context.Context, *types.Project, api.AdoptOptions) error {
+	return nil
+}
+
+func (b *Backend) Apply(context.Context, *types.Project, api.ApplyOptions) ([]api.ApplyChange, error) {
+	return nil, nil
+}
+
+func (b *Backend) Attach(context.Context, string, api.AttachOptions) error {
+	return nil
+}
+
+func (b *Backend) Build(context.Context, *types.Project, api.BuildOptions) error {
+	return nil
+}
+
+func (b *Backend) Commit(context.Context, string, api.CommitOptions) error {
+	return nil
+}
+
+func (b *Backend) Copy(context.Context, string, api.CopyOptions) error {
+	return nil
+}
+
+func (b *Backend) Create(context.Context, *types.Project, api.CreateOptions) error {
+	return nil
+}
+
+func (b *Backend) Describe(context.Context, string) (*types.Project, error) {
+	return nil, api.ErrNotFound
+}
+
+func (b *Backend) DiskUsage(context.Context, string, api.DiskUsageOptions) (api.DiskUsageReport, error) {
+	return api.DiskUsageReport{}, nil
+}
+
+func (b *Backend) Doctor(context.Context, *types.Project) (api.DoctorReport, error) {
+	return api.DoctorReport{}, nil
+}
+
+func (b *Backend) Down(context.Context, string, api.DownOptions) error {
+	return nil
+}
+
+func (b *Backend) Events(context.Context, string, api.EventsOptions) error {
+	return nil
+}
+
+func (b *Backend) Exec(context.Context, string, api.RunOptions) (int, error) { //nolint:staticcheck
+	return 0, nil
+}
+
+func (b *Backend) ExecCommand(context.Context, string, api.ExecOptions) (int, error) {
+	return 0, nil
+}
+
+func (b *Backend) ExplainRecreate(context.Context, *types.Project, string) ([]api.RecreateExplanation, error) {
+	return nil, nil
+}
+
+func (b *Backend) Export(context.Context, string, api.ExportOptions) error {
+	return nil
+}
+
+func (b *Backend) Features(context.Context) ([]api.Feature, error) {
+	return nil, nil
+}
+
+func (b *Backend) Generate(context.Context, api.GenerateOptions) (*types.Project, error) {
+	return nil, api.ErrNotImplemented
+}
+
+func (b *Backend) Images(context.Context, string, api.ImagesOptions) (map[string]api.ImageSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) ImagesTree(context.Context, string, api.ImagesOptions) (api.ImageTree, error) {
+	return api.ImageTree{}, nil
+}
+
+func (b *Backend) ImagesPullAudit(context.Context, *types.Project, api.ImagesOptions) ([]api.ImagePullAudit, error) {
+	return nil, nil
+}
+
+func (b *Backend) Kill(context.Context, string, api.KillOptions) error {
+	return nil
+}
+
+func (b *Backend) List(context.Context, api.ListOptions) ([]api.Stack, error) {
+	return nil, nil
+}
+
+func (b *Backend) LoadProject(ctx context.Context, options api.ProjectLoadOptions) (*types.Project, error) {
+	return b.loader.LoadProject(ctx, options)
+}
+
+func (b *Backend) Logs(context.Context, string, api.LogConsumer, api.LogOptions) error {
+	return nil
+}
+
+func (b *Backend) Migrate(context.Context, string, *types.Project, api.MigrateOptions) error {
+	return nil
+}
+
+func (b *Backend) Move(context.Context, *types.Project, api.MoveOptions) error {
+	return nil
+}
+
+func (b *Backend) NetworkConnect(context.Context, *types.Project, api.NetworkConnectOptions) error {
+	return nil
+}
+
+func (b *Backend) NetworkDisconnect(context.Context, string, api.NetworkDisconnectOptions) error {
+	return nil
+}
+
+func (b *Backend) NetworkDoctor(context.Context, *types.Project, api.NetworkDoctorOptions) (api.NetworkDiagnosis, error) {
+	return api.NetworkDiagnosis{}, nil
+}
+
+func (b *Backend) NetworkInspect(context.Context, string, string) (api.NetworkInspect, error) {
+	return api.NetworkInspect{}, api.ErrNotFound
+}
+
+func (b *Backend) NetworkRecreate(context.Context, *types.Project, api.NetworkRecreateOptions) error {
+	return nil
+}
+
+func (b *Backend) Networks(context.Context, string, api.NetworksOptions) ([]api.NetworkSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) Orphans(context.Context, *types.Project) ([]api.OrphanSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) Pause(context.Context, string, api.PauseOptions) error {
+	return nil
+}
+
+func (b *Backend) Port(context.Context, string, string, uint16, api.PortOptions) (string, int, error) {
+	return "", 0, api.ErrNotFound
+}
+
+func (b *Backend) Ps(context.Context, string, api.PsOptions) ([]api.ContainerSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) Publish(context.Context, *types.Project, string, api.PublishOptions) error {
+	return nil
+}
+
+func (b *Backend) Pull(context.Context, *types.Project, api.PullOptions) error {
+	return nil
+}
+
+func (b *Backend) Push(context.Context, *types.Project, api.PushOptions) error {
+	return nil
+}
+
+func (b *Backend) Remove(context.Context, string, api.RemoveOptions) error {
+	return nil
+}
+
+func (b *Backend) RenewCertificates(context.Context, *types.Project, api.CertificatesOptions) error {
+	return nil
+}
+
+func (b *Backend) Restart(context.Context, string, api.RestartOptions) error {
+	return nil
+}
+
+func (b *Backend) Resume(context.Context, *types.Project) error {
+	return nil
+}
+
+func (b *Backend) RunOneOff(context.Context, *types.Project, api.RunOneOffOptions) (int, error) {
+	return 0, nil
+}
+
+func (b *Backend) RunOneOffContainer(context.Context, *types.Project, api.RunOptions) (int, error) { //nolint:staticcheck
+	return 0, nil
+}
+
+func (b *Backend) Scale(context.Context, *types.Project, api.ScaleOptions) error {
+	return nil
+}
+
+func (b *Backend) SecurityAudit(context.Context, *types.Project, api.SecurityAuditOptions) (api.SecurityReport, error) {
+	return api.SecurityReport{}, nil
+}
+
+func (b *Backend) Start(context.Context, string, api.StartOptions) error {
+	return nil
+}
+
+func (b *Backend) Stop(context.Context, string, api.StopOptions) error {
+	return nil
+}
+
+func (b *Backend) SwarmDeploy(context.Context, *types.Project, api.SwarmDeployOptions) error {
+	return nil
+}
+
+func (b *Backend) Top(context.Context, string, []string) ([]api.ContainerProcSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) TruncateLogs(context.Context, *types.Project, api.TruncateLogsOptions) error {
+	return nil
+}
+
+func (b *Backend) UnPause(context.Context, string, api.PauseOptions) error {
+	return nil
+}
+
+func (b *Backend) Up(context.Context, *types.Project, api.UpOptions) error {
+	return nil
+}
+
+func (b *Backend) Viz(context.Context, *types.Project, api.VizOptions) (string, error) {
+	return "", nil
+}
+
+func (b *Backend) VolumeBrowse(context.Context, string, api.VolumeBrowseOptions) (int, error) {
+	return 0, nil
+}
+
+func (b *Backend) VolumeInspect(context.Context, string, string) (api.VolumeInspect, error) {
+	return api.VolumeInspect{}, api.ErrNotFound
+}
+
+func (b *Backend) VolumeRestore(context.Context, string, api.VolumeRestoreOptions) error {
+	return nil
+}
+
+func (b *Backend) VolumeSnapshot(context.Context, string, api.VolumeSnapshotOptions) (api.VolumeSnapshot, error) {
+	return api.VolumeSnapshot{}, nil
+}
+
+func (b *Backend) VolumeSnapshots(context.Context, string, api.VolumeSnapshotsOptions) ([]api.VolumeSnapshot, error) {
+	return nil, nil
+}
+
+func (b *Backend) Volumes(context.Context, string, api.VolumesOptions) ([]api.VolumesSummary, error) {
+	return nil, nil
+}
+
+func (b *Backend) Wait(context.Context, string, api.WaitOptions) (int64, error) {
+	return 0, nil
+}
+
+func (b *Backend) Watch(context.Context, *types.Project, api.WatchOptions) error {
+	return nil
+}
+
+func (b *Backend) WatchState(context.Context, *types.Project, api.WatchStateOptions) error {
+	return nil
+}
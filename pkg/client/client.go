@@ -0,0 +1,308 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	dockerclient "github.com/docker/docker/client"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+// Client runs Compose operations against a Docker engine
+type Client struct {
+	backend api.Compose
+}
+
+// Option configures a Client
+type Option func(*settings)
+
+type settings struct {
+	progress func(Progress)
+}
+
+// Progress reports a step of an operation, as a resource is being processed
+type Progress struct {
+	// ID identifies the resource, e.g. "Container myproject-web-1"
+	ID string
+	// Status is one of "Working", "Done", "Warning" or "Error"
+	Status string
+	// Text is a short description of the step, e.g. "Started"
+	Text string
+	// Details is additional information, e.g. an error message
+	Details string
+}
+
+// WithProgress sets a function receiving progress of operations, which are otherwise silent
+func WithProgress(fn func(Progress)) Option {
+	return func(s *settings) {
+		s.progress = fn
+	}
+}
+
+// New creates a Client using the Docker API client
+func New(apiClient dockerclient.APIClient, options ...Option) (*Client, error) {
+	var s settings
+	for _, option := range options {
+		option(&s)
+	}
+	composeOptions := []compose.Option{compose.WithAPIClient(apiClient)}
+	if s.progress != nil {
+		composeOptions = append(composeOptions, compose.WithEventProcessor(progressFunc(s.progress)))
+	}
+	backend, err := compose.NewComposeService(nil, composeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{backend: backend}, nil
+}
+
+// progressFunc adapts a progress function to api.EventProcessor
+type progressFunc func(Progress)
+
+func (p progressFunc) Start(context.Context, string) {}
+
+func (p progressFunc) On(events ...api.Resource) {
+	for _, e := range events {
+		p(Progress{ID: e.ID, Status: e.StatusText(), Text: e.Text, Details: e.Details})
+	}
+}
+
+func (p progressFunc) Done(string, bool) {}
+
+// LoadOptions configures how a project is loaded
+type LoadOptions struct {
+	// ProjectName overrides the project name, which is otherwise inferred from compose files or working directory
+	ProjectName string
+	// ConfigPaths are paths to compose files, compose.yaml in working directory being used if not set
+	ConfigPaths []string
+	// WorkingDir is the project directory, defaults to the directory of the first compose file
+	WorkingDir string
+	// EnvFiles are paths to .env files
+	EnvFiles []string
+	// Profiles to enable
+	Profiles []string
+	// Services to select, all services being selected if not set
+	Services []string
+}
+
+// LoadProject loads and validates a project from compose files
+func (c *Client) LoadProject(ctx context.Context, options LoadOptions) (*types.Project, error) {
+	return c.backend.LoadProject(ctx, api.ProjectLoadOptions{
+		ProjectName: options.ProjectName,
+		ConfigPaths: options.ConfigPaths,
+		WorkingDir:  options.WorkingDir,
+		EnvFiles:    options.EnvFiles,
+		Profiles:    options.Profiles,
+		Services:    options.Services,
+	})
+}
+
+// Recreate is the policy applied to existing containers by Up
+type Recreate string
+
+const (
+	// RecreateChanged recreates containers which configuration changed, this is the default
+	RecreateChanged Recreate = "changed"
+	// RecreateAlways recreates all containers
+	RecreateAlways Recreate = "always"
+	// RecreateNever keeps existing containers
+	RecreateNever Recreate = "never"
+)
+
+func (r Recreate) strategy() (string, error) {
+	switch r {
+	case "", RecreateChanged:
+		return api.RecreateDiverged, nil
+	case RecreateAlways:
+		return api.RecreateForce, nil
+	case RecreateNever:
+		return api.RecreateNever, nil
+	default:
+		return "", fmt.Errorf("unsupported recreate policy %q", r)
+	}
+}
+
+// UpOptions configures Up
+type UpOptions struct {
+	// Services to create and start, with their dependencies. All services are started if not set
+	Services []string
+	// Build images before starting containers
+	Build bool
+	// Recreate is the policy applied to existing containers
+	Recreate Recreate
+	// RemoveOrphans removes containers for services not defined in the project
+	RemoveOrphans bool
+	// Wait for services to be running or healthy before returning
+	Wait bool
+	// WaitTimeout is the maximum duration to wait for, when Wait is set. No timeout if zero
+	WaitTimeout time.Duration
+}
+
+// Up creates and starts containers for project services
+func (c *Client) Up(ctx context.Context, project *types.Project, options UpOptions) error {
+	recreate, err := options.Recreate.strategy()
+	if err != nil {
+		return err
+	}
+	var build *api.BuildOptions
+	if options.Build {
+		build = &api.BuildOptions{Services: options.Services}
+	}
+	return c.backend.Up(ctx, project, api.UpOptions{
+		Create: api.CreateOptions{
+			Build:                build,
+			Services:             options.Services,
+			RemoveOrphans:        options.RemoveOrphans,
+			Recreate:             recreate,
+			RecreateDependencies: recreate,
+		},
+		Start: api.StartOptions{
+			Project:     project,
+			Services:    options.Services,
+			Wait:        options.Wait,
+			WaitTimeout: options.WaitTimeout,
+		},
+	})
+}
+
+// DownOptions configures Down
+type DownOptions struct {
+	// Services to stop and remove, all services being removed if not set
+	Services []string
+	// RemoveOrphans removes containers for services not defined in the project
+	RemoveOrphans bool
+	// Volumes removes named volumes declared by the project, and anonymous volumes
+	Volumes bool
+	// Images removes images used by services, "all" or "local" for images without a custom tag
+	Images string
+	// Timeout overrides containers stop timeout when not zero
+	Timeout time.Duration
+}
+
+// Down stops and removes containers and networks created by Up for the project
+func (c *Client) Down(ctx context.Context, projectName string, options DownOptions) error {
+	var timeout *time.Duration
+	if options.Timeout != 0 {
+		timeout = &options.Timeout
+	}
+	return c.backend.Down(ctx, projectName, api.DownOptions{
+		Services:      options.Services,
+		RemoveOrphans: options.RemoveOrphans,
+		Volumes:       options.Volumes,
+		Images:        options.Images,
+		Timeout:       timeout,
+	})
+}
+
+// PsOptions configures Ps
+type PsOptions struct {
+	// Services to list containers for, all services being listed if not set
+	Services []string
+	// All includes stopped containers
+	All bool
+}
+
+// Container is a container of a project service
+type Container struct {
+	ID      string
+	Name    string
+	Service string
+	Image   string
+	// State is the container state, e.g. "running" or "exited"
+	State string
+	// Health is the container health status, empty if the container has no healthcheck
+	Health   string
+	ExitCode int
+}
+
+// Ps lists containers of a project
+func (c *Client) Ps(ctx context.Context, projectName string, options PsOptions) ([]Container, error) {
+	summaries, err := c.backend.Ps(ctx, projectName, api.PsOptions{
+		Services: options.Services,
+		All:      options.All,
+	})
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]Container, 0, len(summaries))
+	for _, s := range summaries {
+		containers = append(containers, Container{
+			ID:       s.ID,
+			Name:     s.Name,
+			Service:  s.Service,
+			Image:    s.Image,
+			State:    s.State,
+			Health:   s.Health,
+			ExitCode: s.ExitCode,
+		})
+	}
+	return containers, nil
+}
+
+// LogsOptions configures Logs
+type LogsOptions struct {
+	// Services to get logs for, all services being included if not set
+	Services []string
+	// Follow log output until ctx is done
+	Follow bool
+	// Tail is the number of lines to show from the end of the logs, or "all"
+	Tail string
+	// Since and Until filter logs by time, as a timestamp or a relative duration (e.g. "42m")
+	Since string
+	Until string
+	// Timestamps prefixes log lines with their timestamp
+	Timestamps bool
+}
+
+// LogLine is a line of container output
+type LogLine struct {
+	Container string
+	// Stderr is set when line was written by container to stderr
+	Stderr  bool
+	Message string
+}
+
+// Logs passes logs of project containers to fn
+func (c *Client) Logs(ctx context.Context, projectName string, options LogsOptions, fn func(LogLine)) error {
+	return c.backend.Logs(ctx, projectName, logFunc(fn), api.LogOptions{
+		Services:   options.Services,
+		Follow:     options.Follow,
+		Tail:       options.Tail,
+		Since:      options.Since,
+		Until:      options.Until,
+		Timestamps: options.Timestamps,
+	})
+}
+
+// logFunc adapts a log function to api.LogConsumer
+type logFunc func(LogLine)
+
+func (l logFunc) Log(containerName, message string) {
+	l(LogLine{Container: containerName, Message: message})
+}
+
+func (l logFunc) Err(containerName, message string) {
+	l(LogLine{Container: containerName, Stderr: true, Message: message})
+}
+
+func (l logFunc) Status(string, string) {}
@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func TestUp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	project := &types.Project{Name: "myproject"}
+	backend.EXPECT().Up(gomock.Any(), project, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *types.Project, options api.UpOptions) error {
+			assert.Equal(t, options.Create.Recreate, api.RecreateForce)
+			assert.Equal(t, options.Create.RecreateDependencies, api.RecreateForce)
+			assert.DeepEqual(t, options.Create.Build, &api.BuildOptions{Services: []string{"web"}})
+			assert.Equal(t, options.Start.Project, project)
+			assert.Assert(t, options.Start.Wait)
+			return nil
+		})
+
+	c := &Client{backend: backend}
+	err := c.Up(t.Context(), project, UpOptions{
+		Services: []string{"web"},
+		Build:    true,
+		Recreate: RecreateAlways,
+		Wait:     true,
+	})
+	assert.NilError(t, err)
+
+	err = c.Up(t.Context(), project, UpOptions{Recreate: "sometimes"})
+	assert.ErrorContains(t, err, `unsupported recreate policy "sometimes"`)
+}
+
+func TestPs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Ps(gomock.Any(), "myproject", api.PsOptions{All: true}).Return([]api.ContainerSummary{
+		{ID: "123", Name: "myproject-web-1", Service: "web", Image: "nginx", State: "running", Health: "healthy"},
+	}, nil)
+
+	c := &Client{backend: backend}
+	containers, err := c.Ps(t.Context(), "myproject", PsOptions{All: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, containers, []Container{
+		{ID: "123", Name: "myproject-web-1", Service: "web", Image: "nginx", State: "running", Health: "healthy"},
+	})
+}
+
+func TestLogs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), api.LogOptions{Tail: "5"}).
+		DoAndReturn(func(_ context.Context, _ string, consumer api.LogConsumer, _ api.LogOptions) error {
+			consumer.Log("myproject-web-1", "ready")
+			consumer.Status("myproject-web-1", "exited with code 0")
+			consumer.Err("myproject-web-1", "warning")
+			return nil
+		})
+
+	c := &Client{backend: backend}
+	var lines []LogLine
+	err := c.Logs(t.Context(), "myproject", LogsOptions{Tail: "5"}, func(l LogLine) {
+		lines = append(lines, l)
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, lines, []LogLine{
+		{Container: "myproject-web-1", Message: "ready"},
+		{Container: "myproject-web-1", Stderr: true, Message: "warning"},
+	})
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	dockerclient "github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/client"
+)
+
+// Signatures of the stable API. These must never change within a major version: if this fails to compile,
+// the change breaks downstream tools and must be reverted.
+var (
+	_ func(dockerclient.APIClient, ...client.Option) (*client.Client, error)                        = client.New
+	_ func(func(client.Progress)) client.Option                                                     = client.WithProgress
+	_ func(*client.Client, context.Context, client.LoadOptions) (*types.Project, error)             = (*client.Client).LoadProject
+	_ func(*client.Client, context.Context, *types.Project, client.UpOptions) error                 = (*client.Client).Up
+	_ func(*client.Client, context.Context, string, client.DownOptions) error                       = (*client.Client).Down
+	_ func(*client.Client, context.Context, string, client.PsOptions) ([]client.Container, error)   = (*client.Client).Ps
+	_ func(*client.Client, context.Context, string, client.LogsOptions, func(client.LogLine)) error = (*client.Client).Logs
+)
+
+// TestStableTypes checks fields of the stable API types. New fields can be added, but existing ones must
+// keep their name and type.
+func TestStableTypes(t *testing.T) {
+	stringSlice := reflect.TypeFor[[]string]()
+	str := reflect.TypeFor[string]()
+	boolean := reflect.TypeFor[bool]()
+	duration := reflect.TypeFor[time.Duration]()
+
+	tests := []struct {
+		typ    reflect.Type
+		fields map[string]reflect.Type
+	}{
+		{
+			typ:    reflect.TypeFor[client.Progress](),
+			fields: map[string]reflect.Type{"ID": str, "Status": str, "Text": str, "Details": str},
+		},
+		{
+			typ: reflect.TypeFor[client.LoadOptions](),
+			fields: map[string]reflect.Type{
+				"ProjectName": str, "ConfigPaths": stringSlice, "WorkingDir": str,
+				"EnvFiles": stringSlice, "Profiles": stringSlice, "Services": stringSlice,
+			},
+		},
+		{
+			typ: reflect.TypeFor[client.UpOptions](),
+			fields: map[string]reflect.Type{
+				"Services": stringSlice, "Build": boolean, "Recreate": reflect.TypeFor[client.Recreate](),
+				"RemoveOrphans": boolean, "Wait": boolean, "WaitTimeout": duration,
+			},
+		},
+		{
+			typ: reflect.TypeFor[client.DownOptions](),
+			fields: map[string]reflect.Type{
+				"Services": stringSlice, "RemoveOrphans": boolean, "Volumes": boolean, "Images": str, "Timeout": duration,
+			},
+		},
+		{
+			typ:    reflect.TypeFor[client.PsOptions](),
+			fields: map[string]reflect.Type{"Services": stringSlice, "All": boolean},
+		},
+		{
+			typ: reflect.TypeFor[client.Container](),
+			fields: map[string]reflect.Type{
+				"ID": str, "Name": str, "Service": str, "Image": str, "State": str, "Health": str,
+				"ExitCode": reflect.TypeFor[int](),
+			},
+		},
+		{
+			typ: reflect.TypeFor[client.LogsOptions](),
+			fields: map[string]reflect.Type{
+				"Services": stringSlice, "Follow": boolean, "Tail": str, "Since": str, "Until": str, "Timestamps": boolean,
+			},
+		},
+		{
+			typ:    reflect.TypeFor[client.LogLine](),
+			fields: map[string]reflect.Type{"Container": str, "Stderr": boolean, "Message": str},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ.Name(), func(t *testing.T) {
+			for name, typ := range tt.fields {
+				field, ok := tt.typ.FieldByName(name)
+				assert.Assert(t, ok, "field %s.%s has been removed", tt.typ.Name(), name)
+				assert.Equal(t, field.Type, typ, "field %s.%s type has changed", tt.typ.Name(), name)
+			}
+		})
+	}
+}
+
+func TestStableConstants(t *testing.T) {
+	assert.Equal(t, string(client.RecreateChanged), "changed")
+	assert.Equal(t, string(client.RecreateAlways), "always")
+	assert.Equal(t, string(client.RecreateNever), "never")
+}
@@ -0,0 +1,29 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package client is the stable Go API to drive Docker Compose from other tools.
+//
+// Unlike pkg/api and pkg/compose, which evolve with the Compose CLI, this package follows semantic versioning:
+// within a major version, exported identifiers are never removed or changed in an incompatible way. New options
+// may be added as struct fields, so options should always be set using field names.
+//
+// Example usage:
+//
+//	apiClient, _ := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+//	c, _ := composeclient.New(apiClient)
+//	project, _ := c.LoadProject(ctx, composeclient.LoadOptions{ConfigPaths: []string{"compose.yaml"}})
+//	err := c.Up(ctx, project, composeclient.UpOptions{Wait: true})
+package client
@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// anonymousVolumeName matches names generated by the engine for anonymous volumes
+var anonymousVolumeName = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// preservedAnonymousVolumes lists the anonymous volumes mounted by replaced container, as explicit volume
+// mounts to be re-attached to the replacing one. Volumes are preserved even if neither the image nor the service
+// declares them anymore, unless service now mounts another source on the same path.
+func preservedAnonymousVolumes(service types.ServiceConfig, replaced container.Summary) []types.ServiceVolumeConfig {
+	var preserved []types.ServiceVolumeConfig
+	for _, m := range replaced.Mounts {
+		if m.Type != mount.TypeVolume || !anonymousVolumeName.MatchString(m.Name) {
+			continue
+		}
+		if slices.ContainsFunc(service.Volumes, func(v types.ServiceVolumeConfig) bool {
+			return path.Clean(v.Target) == path.Clean(m.Destination) && (v.Source != "" || v.Type != types.VolumeTypeVolume)
+		}) || slices.ContainsFunc(service.Tmpfs, func(t string) bool {
+			target, _, _ := strings.Cut(t, ":")
+			return path.Clean(target) == path.Clean(m.Destination)
+		}) {
+			continue
+		}
+		preserved = append(preserved, types.ServiceVolumeConfig{
+			Type:     types.VolumeTypeVolume,
+			Source:   m.Name,
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		})
+	}
+	slices.SortFunc(preserved, func(a, b types.ServiceVolumeConfig) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+	return preserved
+}
+
+// withPreservedVolumes replaces anonymous volumes declared by service with the preserved ones
+func withPreservedVolumes(service types.ServiceConfig, preserved []types.ServiceVolumeConfig) types.ServiceConfig {
+	if len(preserved) == 0 {
+		return service
+	}
+	volumes := slices.DeleteFunc(slices.Clone(service.Volumes), func(v types.ServiceVolumeConfig) bool {
+		return v.Source == "" && v.Type == types.VolumeTypeVolume && slices.ContainsFunc(preserved, func(p types.ServiceVolumeConfig) bool {
+			return path.Clean(p.Target) == path.Clean(v.Target)
+		})
+	})
+	service.Volumes = append(volumes, preserved...)
+	return service
+}
+
+// describePreservedVolumes reports preserved anonymous volumes as `<short name>:<path>`
+func describePreservedVolumes(preserved []types.ServiceVolumeConfig) string {
+	names := make([]string, len(preserved))
+	for i, v := range preserved {
+		names[i] = fmt.Sprintf("%s:%s", v.Source[:12], v.Target)
+	}
+	return "preserved anonymous volumes " + strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+)
+
+func TestPreservedAnonymousVolumes(t *testing.T) {
+	anon1 := strings.Repeat("a", 64)
+	anon2 := strings.Repeat("b", 64)
+	anon3 := strings.Repeat("c", 64)
+	replaced := container.Summary{
+		Mounts: []container.MountPoint{
+			{Type: mount.TypeVolume, Name: anon2, Destination: "/var/lib/data", RW: true},
+			{Type: mount.TypeVolume, Name: anon1, Destination: "/cache", RW: false},
+			{Type: mount.TypeVolume, Name: anon3, Destination: "/now/named", RW: true},
+			{Type: mount.TypeVolume, Name: "demo_named", Destination: "/named", RW: true},
+			{Type: mount.TypeBind, Source: "/src", Destination: "/src", RW: true},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "app",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: types.VolumeTypeVolume, Target: "/var/lib/data"},
+			{Type: types.VolumeTypeVolume, Source: "named", Target: "/now/named"},
+		},
+	}
+
+	preserved := preservedAnonymousVolumes(service, replaced)
+	assert.DeepEqual(t, preserved, []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeVolume, Source: anon1, Target: "/cache", ReadOnly: true},
+		{Type: types.VolumeTypeVolume, Source: anon2, Target: "/var/lib/data"},
+	})
+	assert.Equal(t, describePreservedVolumes(preserved), "preserved anonymous volumes aaaaaaaaaaaa:/cache, bbbbbbbbbbbb:/var/lib/data")
+
+	service = withPreservedVolumes(service, preserved)
+	assert.DeepEqual(t, service.Volumes, []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeVolume, Source: "named", Target: "/now/named"},
+		{Type: types.VolumeTypeVolume, Source: anon1, Target: "/cache", ReadOnly: true},
+		{Type: types.VolumeTypeVolume, Source: anon2, Target: "/var/lib/data"},
+	})
+}
@@ -0,0 +1,235 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Apply(ctx context.Context, project *types.Project, options api.ApplyOptions) ([]api.ApplyChange, error) {
+	ctx = s.withOperationServices(ctx, project, nil)
+	changes, err := s.planApply(ctx, project, options.Prune)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(s.stdout(), "Project is up to date")
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Going to apply changes:\n")
+	for _, c := range changes {
+		sb.WriteString("  " + c.String() + "\n")
+	}
+	msg := strings.TrimSuffix(sb.String(), "\n")
+	if options.AssumeYes {
+		_, _ = fmt.Fprintln(s.stdout(), msg)
+	} else {
+		confirm, err := s.prompt(msg+"\nContinue?", false)
+		if err != nil {
+			return nil, err
+		}
+		if !confirm {
+			return nil, nil
+		}
+	}
+
+	err = Run(ctx, func(ctx context.Context) error {
+		return s.apply(ctx, project, changes, options)
+	}, "apply", s.events)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (s *composeService) apply(ctx context.Context, project *types.Project, changes []api.ApplyChange, options api.ApplyOptions) error {
+	err := s.create(ctx, project, api.CreateOptions{
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		RemoveOrphans:        options.Prune,
+	})
+	if err != nil {
+		return err
+	}
+	err = s.start(ctx, project.Name, api.StartOptions{
+		Project: project,
+		Wait:    options.Wait,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if !options.Prune {
+		return nil
+	}
+
+	// containers have been removed as orphans, so pruned networks and volumes are not in use anymore
+	networks, err := s.actualNetworks(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(networks)) {
+		name := networks[key].Name
+		if !slices.Contains(changes, api.ApplyChange{Action: api.ApplyRemove, Resource: "network", Name: name}) {
+			continue
+		}
+		if err := s.removeNetwork(ctx, key, project.Name, name); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		if c.Action != api.ApplyRemove || c.Resource != "volume" {
+			continue
+		}
+		if err := s.removeVolume(ctx, c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planApply compares project model with the actual resources, and lists changes required to converge
+func (s *composeService) planApply(ctx context.Context, project *types.Project, prune bool) ([]api.ApplyChange, error) {
+	if _, err := s.getLocalImagesDigests(ctx, project); err != nil {
+		return nil, err
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	actualNetworks, err := s.actualNetworks(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	actualVolumes, err := s.actualVolumes(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	return planChanges(project, containers, actualNetworks, actualVolumes, prune)
+}
+
+// planChanges computes the changes required for actual containers, networks and volumes to match project
+func planChanges(project *types.Project, containers Containers, networks types.Networks, volumes types.Volumes, prune bool) ([]api.ApplyChange, error) {
+	var changes []api.ApplyChange
+
+	for _, key := range slices.Sorted(maps.Keys(project.Networks)) {
+		nw := project.Networks[key]
+		if _, ok := networks[key]; ok || bool(nw.External) {
+			continue
+		}
+		changes = append(changes, api.ApplyChange{Action: api.ApplyCreate, Resource: "network", Name: nw.Name})
+	}
+	for _, key := range slices.Sorted(maps.Keys(project.Volumes)) {
+		vol := project.Volumes[key]
+		if _, ok := volumes[key]; ok || bool(vol.External) {
+			continue
+		}
+		changes = append(changes, api.ApplyChange{Action: api.ApplyCreate, Resource: "volume", Name: vol.Name})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		if service.Provider != nil || isExternalService(service) {
+			continue
+		}
+		serviceChanges, err := planServiceChanges(project.Name, service, containers.filter(isService(name), isNotOneOff))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, serviceChanges...)
+	}
+
+	if !prune {
+		return changes, nil
+	}
+	for _, c := range containers.filter(isOrphaned(project)).sorted() {
+		changes = append(changes, api.ApplyChange{Action: api.ApplyRemove, Resource: "container", Name: getCanonicalContainerName(c)})
+	}
+	for _, key := range slices.Sorted(maps.Keys(networks)) {
+		if _, ok := project.Networks[key]; ok {
+			continue
+		}
+		changes = append(changes, api.ApplyChange{Action: api.ApplyRemove, Resource: "network", Name: networks[key].Name})
+	}
+	for _, key := range slices.Sorted(maps.Keys(volumes)) {
+		vol := volumes[key]
+		if _, ok := project.Volumes[key]; ok || isCacheVolume(vol) {
+			continue
+		}
+		changes = append(changes, api.ApplyChange{Action: api.ApplyRemove, Resource: "volume", Name: vol.Name})
+	}
+	return changes, nil
+}
+
+// planServiceChanges computes the changes required for service containers to match service configuration and scale
+func planServiceChanges(projectName string, service types.ServiceConfig, containers Containers) ([]api.ApplyChange, error) {
+	type observed struct {
+		ctr  container.Summary
+		diff []string
+	}
+	var actual []observed
+	for _, c := range containers {
+		diff, err := configDiff(service, c)
+		if err != nil {
+			return nil, err
+		}
+		actual = append(actual, observed{ctr: c, diff: diff})
+	}
+	// as convergence does, keep up-to-date containers with lowest numbers, so obsolete ones are removed on scale down
+	slices.SortStableFunc(actual, func(a, b observed) int {
+		if (len(a.diff) > 0) != (len(b.diff) > 0) {
+			if len(a.diff) > 0 {
+				return 1
+			}
+			return -1
+		}
+		na, _ := strconv.Atoi(a.ctr.Labels[api.ContainerNumberLabel])
+		nb, _ := strconv.Atoi(b.ctr.Labels[api.ContainerNumberLabel])
+		return na - nb
+	})
+
+	var changes []api.ApplyChange
+	scale := service.GetScale()
+	for i, o := range actual {
+		name := getCanonicalContainerName(o.ctr)
+		switch {
+		case i >= scale:
+			changes = append(changes, api.ApplyChange{Action: api.ApplyRemove, Resource: "container", Name: name})
+		case len(o.diff) > 0:
+			changes = append(changes, api.ApplyChange{Action: api.ApplyRecreate, Resource: "container", Name: name, Reason: strings.Join(o.diff, ", ")})
+		case o.ctr.State != container.StateRunning:
+			changes = append(changes, api.ApplyChange{Action: api.ApplyStart, Resource: "container", Name: name})
+		}
+	}
+	next := nextContainerNumber(containers)
+	for i := len(actual); i < scale; i++ {
+		changes = append(changes, api.ApplyChange{Action: api.ApplyCreate, Resource: "container", Name: getContainerName(projectName, service, next)})
+		next++
+	}
+	return changes, nil
+}
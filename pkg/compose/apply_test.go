@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestPlanChanges(t *testing.T) {
+	web := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(2)}
+	db := types.ServiceConfig{Name: "db", Image: "postgres"}
+	project := &types.Project{
+		Name:     "myproject",
+		Services: types.Services{"web": web, "db": db},
+		Networks: types.Networks{
+			"default": {Name: "myproject_default"},
+			"front":   {Name: "myproject_front"},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "myproject_data"},
+		},
+	}
+
+	summary := func(service types.ServiceConfig, number int, state container.ContainerState) container.Summary {
+		labels, err := (&composeService{}).prepareLabels(types.Labels{}, service, number)
+		assert.NilError(t, err)
+		labels[api.ServiceLabel] = service.Name
+		labels[api.OneoffLabel] = "False"
+		return container.Summary{
+			Names:  []string{"/" + getContainerName(project.Name, service, number)},
+			Labels: labels,
+			State:  state,
+		}
+	}
+	updated := db
+	updated.Command = types.ShellCommand{"postgres", "-d"}
+	containers := Containers{
+		summary(web, 1, container.StateExited),
+		summary(updated, 1, container.StateRunning),
+		summary(types.ServiceConfig{Name: "legacy", Image: "alpine"}, 1, container.StateRunning),
+	}
+	networks := types.Networks{
+		"default": {Name: "myproject_default"},
+		"old":     {Name: "myproject_old"},
+	}
+	volumes := types.Volumes{
+		"old":   {Name: "myproject_old"},
+		"cache": {Name: "myproject_cache", Labels: types.Labels{api.CacheVolumeLabel: "True"}},
+	}
+
+	changes, err := planChanges(project, containers, networks, volumes, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []api.ApplyChange{
+		{Action: api.ApplyCreate, Resource: "network", Name: "myproject_front"},
+		{Action: api.ApplyCreate, Resource: "volume", Name: "myproject_data"},
+		{Action: api.ApplyRecreate, Resource: "container", Name: "myproject-db-1", Reason: hashComponentCommand},
+		{Action: api.ApplyStart, Resource: "container", Name: "myproject-web-1"},
+		{Action: api.ApplyCreate, Resource: "container", Name: "myproject-web-2"},
+	})
+
+	changes, err = planChanges(project, containers, networks, volumes, true)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes[5:], []api.ApplyChange{
+		{Action: api.ApplyRemove, Resource: "container", Name: "myproject-legacy-1"},
+		{Action: api.ApplyRemove, Resource: "network", Name: "myproject_old"},
+		{Action: api.ApplyRemove, Resource: "volume", Name: "myproject_old"},
+	})
+}
+
+func TestPlanServiceChangesScaleDown(t *testing.T) {
+	service := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(1)}
+	var containers Containers
+	for _, number := range []int{1, 2} {
+		labels, err := (&composeService{}).prepareLabels(types.Labels{}, service, number)
+		assert.NilError(t, err)
+		labels[api.ServiceLabel] = service.Name
+		containers = append(containers, container.Summary{
+			Names:  []string{"/" + getContainerName("myproject", service, number)},
+			Labels: labels,
+			State:  container.StateRunning,
+		})
+	}
+	// first container is obsolete, so it is the one removed on scale down
+	containers[0].Labels[api.ConfigHashLabel] = "obsolete"
+
+	changes, err := planServiceChanges("myproject", service, containers)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []api.ApplyChange{
+		{Action: api.ApplyRemove, Resource: "container", Name: "myproject-web-1"},
+	})
+}
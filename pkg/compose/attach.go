@@ -46,7 +46,7 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, lis
 
 	var names []string
 	for _, c := range containers {
-		names = append(names, getContainerNameWithoutProject(c))
+		names = append(names, getContainerLogName(c))
 	}
 
 	_, _ = fmt.Fprintf(s.stdout(), "Attaching to %s\n", strings.Join(names, ", "))
@@ -62,7 +62,7 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, lis
 
 func (s *composeService) attachContainer(ctx context.Context, container containerType.Summary, listener api.ContainerEventListener) error {
 	service := container.Labels[api.ServiceLabel]
-	name := getContainerNameWithoutProject(container)
+	name := getContainerLogName(container)
 	return s.doAttachContainer(ctx, service, container.ID, name, listener)
 }
 
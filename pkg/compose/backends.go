@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// BackendContextField is the Docker context metadata field selecting the Compose backend, set in the
+	// context `meta.json` file:
+	//
+	//	{"Name": "my-cloud", "Metadata": {"compose-backend": "my-cloud"}, "Endpoints": {...}}
+	BackendContextField = "compose-backend"
+	// DockerBackend is the default backend, managing resources with the Docker engine
+	DockerBackend = "docker"
+)
+
+// BackendFactory creates an alternate implementation of the Compose API, for example targeting a cloud
+// platform or a remote agent. Options are the ones set for the Docker backend, which an alternate backend
+// can pass to NewDockerBackend to delegate operations it doesn't handle.
+type BackendFactory func(dockerCli command.Cli, options ...Option) (api.Compose, error)
+
+var backends = struct {
+	sync.RWMutex
+	factories map[string]BackendFactory
+}{
+	factories: map[string]BackendFactory{},
+}
+
+// RegisterBackend makes a Compose backend available to be selected by Docker context metadata.
+// It is typically called from an init function of the package implementing the backend.
+func RegisterBackend(name string, factory BackendFactory) error {
+	if name == "" || name == DockerBackend {
+		return fmt.Errorf("invalid backend name %q", name)
+	}
+	if factory == nil {
+		return fmt.Errorf("backend %q has no factory", name)
+	}
+	backends.Lock()
+	defer backends.Unlock()
+	if _, ok := backends.factories[name]; ok {
+		return fmt.Errorf("backend %q is already registered", name)
+	}
+	backends.factories[name] = factory
+	return nil
+}
+
+// Backends lists names of the available Compose backends
+func Backends() []string {
+	backends.RLock()
+	defer backends.RUnlock()
+	return backendNames()
+}
+
+func backendNames() []string {
+	names := append(slices.Collect(maps.Keys(backends.factories)), DockerBackend)
+	slices.Sort(names)
+	return names
+}
+
+// selectBackend returns the factory of the alternate backend selected by current Docker context,
+// or nil to use the Docker backend
+func selectBackend(dockerCli command.Cli) (BackendFactory, error) {
+	backends.RLock()
+	defer backends.RUnlock()
+	if len(backends.factories) == 0 || dockerCli == nil {
+		return nil, nil
+	}
+	name, err := backendFromContext(dockerCli.ContextStore(), dockerCli.CurrentContext())
+	if err != nil || name == "" || name == DockerBackend {
+		return nil, err
+	}
+	factory, ok := backends.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("docker context %q selects unknown compose backend %q, available backends: %s",
+			dockerCli.CurrentContext(), name, strings.Join(backendNames(), ", "))
+	}
+	return factory, nil
+}
+
+// backendFromContext inspects Docker context metadata for the name of the selected Compose backend
+func backendFromContext(st store.Store, contextName string) (string, error) {
+	if st == nil {
+		return "", nil
+	}
+	meta, err := st.GetMetadata(contextName)
+	if err != nil {
+		// default context has no metadata stored
+		return "", nil
+	}
+	var value any
+	switch m := meta.Metadata.(type) {
+	case command.DockerContext:
+		value = m.AdditionalFields[BackendContextField]
+	case map[string]any:
+		value = m[BackendContextField]
+	}
+	if value == nil {
+		return "", nil
+	}
+	name, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected type for context field %q: %T (expected: string)", BackendContextField, value)
+	}
+	return name, nil
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func registerTestBackend(t *testing.T, name string, factory BackendFactory) {
+	t.Helper()
+	assert.NilError(t, RegisterBackend(name, factory))
+	t.Cleanup(func() {
+		backends.Lock()
+		defer backends.Unlock()
+		delete(backends.factories, name)
+	})
+}
+
+func TestRegisterBackend(t *testing.T) {
+	factory := func(command.Cli, ...Option) (api.Compose, error) {
+		return nil, nil
+	}
+	registerTestBackend(t, "test", factory)
+	assert.DeepEqual(t, Backends(), []string{DockerBackend, "test"})
+
+	assert.ErrorContains(t, RegisterBackend("test", factory), "already registered")
+	assert.ErrorContains(t, RegisterBackend(DockerBackend, factory), "invalid backend name")
+	assert.ErrorContains(t, RegisterBackend("", factory), "invalid backend name")
+}
+
+func testContextStore(t *testing.T, contextName string, backend string) store.Store {
+	t.Helper()
+	st := store.New(t.TempDir(), store.NewConfig(func() any {
+		return &map[string]any{}
+	}))
+	err := st.CreateOrUpdate(store.Metadata{
+		Name: contextName,
+		Metadata: command.DockerContext{
+			AdditionalFields: map[string]any{BackendContextField: backend},
+		},
+		Endpoints: map[string]any{},
+	})
+	assert.NilError(t, err)
+	return st
+}
+
+func TestNewComposeServiceSelectsBackend(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	selected := mocks.NewMockCompose(mockCtrl)
+	registerTestBackend(t, "test", func(command.Cli, ...Option) (api.Compose, error) {
+		return selected, nil
+	})
+
+	cli := mocks.NewMockCli(mockCtrl)
+	cli.EXPECT().CurrentContext().Return("cloud").AnyTimes()
+	cli.EXPECT().ContextStore().Return(testContextStore(t, "cloud", "test")).AnyTimes()
+	backend, err := NewComposeService(cli)
+	assert.NilError(t, err)
+	assert.Equal(t, backend, api.Compose(selected))
+}
+
+func TestNewComposeServiceUnknownBackend(t *testing.T) {
+	registerTestBackend(t, "test", func(command.Cli, ...Option) (api.Compose, error) {
+		return nil, nil
+	})
+
+	mockCtrl := gomock.NewController(t)
+	cli := mocks.NewMockCli(mockCtrl)
+	cli.EXPECT().CurrentContext().Return("cloud").AnyTimes()
+	cli.EXPECT().ContextStore().Return(testContextStore(t, "cloud", "missing")).AnyTimes()
+	_, err := NewComposeService(cli)
+	assert.ErrorContains(t, err, `selects unknown compose backend "missing", available backends: docker, test`)
+}
@@ -0,0 +1,207 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/sirupsen/logrus"
+)
+
+// desktopVirtualPaths are bind mount sources Docker Desktop handles without file sharing
+var desktopVirtualPaths = []string{
+	"/var/run/docker.sock",
+	"/run/host-services",
+}
+
+// checkBindMounts validates bind mounts sources, as the engine would otherwise silently create missing
+// ones as root-owned directories, or fail with a cryptic error when not shared with Docker Desktop VM
+func (s *composeService) checkBindMounts(ctx context.Context, project *types.Project) error {
+	if !s.isLocalEngine() {
+		// bind mount sources are resolved on a remote host
+		return nil
+	}
+	var shared []string
+	if runtime.GOOS != "windows" {
+		desktop, err := s.isDesktopIntegrationActive(ctx)
+		if err != nil {
+			return err
+		}
+		if desktop {
+			shared = desktopFileSharingDirectories()
+		}
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		for _, v := range service.Volumes {
+			if v.Type != types.VolumeTypeBind || isNamedPipe(v.Source) {
+				continue
+			}
+			if err := checkBindMount(service.Name, v, shared, runtime.GOOS == "darwin"); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *composeService) isLocalEngine() bool {
+	host := s.dockerCli.DockerEndpoint().Host
+	return host == "" || strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
+// checkBindMount validates a bind mount source, warnings are logged while blocking issues are returned as error
+func checkBindMount(service string, v types.ServiceVolumeConfig, shared []string, checkCase bool) error {
+	source := v.Source
+	if slices.ContainsFunc(desktopVirtualPaths, func(p string) bool {
+		return source == p || strings.HasPrefix(source, p+"/")
+	}) {
+		return nil
+	}
+
+	_, err := os.Stat(source)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if v.Bind != nil && !v.Bind.CreateHostPath {
+			return fmt.Errorf("service %q: bind mount source %s doesn't exist, create it or remove `create_host_path: false`", service, source)
+		}
+		logrus.Warnf("service %q: bind mount source %s doesn't exist, it will be created as a root-owned directory. "+
+			"Create it before running compose, or use --no-bind-checks to skip this check", service, source)
+		return nil
+	case err != nil:
+		return fmt.Errorf("service %q: can't access bind mount source %s: %w", service, source, err)
+	}
+
+	if checkCase {
+		actual, err := actualPathCase(source)
+		if err == nil && actual != source {
+			logrus.Warnf("service %q: bind mount source %s doesn't match actual path case %s, "+
+				"this can break file watching and case-sensitive tooling in containers", service, source, actual)
+		}
+	}
+
+	if len(shared) > 0 && !isSharedPath(source, shared) {
+		return fmt.Errorf("service %q: bind mount source %s is not shared with Docker Desktop. "+
+			"Add it, or a parent directory, to Settings > Resources > File sharing", service, source)
+	}
+	return nil
+}
+
+// actualPathCase returns path with the case used by the filesystem for each path element
+func actualPathCase(path string) (string, error) {
+	dir, base := filepath.Split(filepath.Clean(path))
+	if base == "" {
+		return path, nil
+	}
+	dir = filepath.Clean(dir)
+	parent := dir
+	if dir != path {
+		var err error
+		parent, err = actualPathCase(dir)
+		if err != nil {
+			return "", err
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if e.Name() == base {
+			return filepath.Join(parent, base), nil
+		}
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.Name(), base) {
+			return filepath.Join(parent, e.Name()), nil
+		}
+	}
+	return filepath.Join(parent, base), nil
+}
+
+func isSharedPath(path string, shared []string) bool {
+	candidates := []string{path}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+		candidates = append(candidates, resolved)
+	}
+	for _, p := range candidates {
+		for _, dir := range shared {
+			if p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// desktopFileSharingDirectories reads directories shared with Docker Desktop VM from Desktop settings.
+// Returns nil if settings can't be found, so check is skipped.
+func desktopFileSharingDirectories() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	switch runtime.GOOS {
+	case "darwin":
+		dirs = []string{filepath.Join(home, "Library", "Group Containers", "group.com.docker")}
+	case "linux":
+		dirs = []string{filepath.Join(home, ".docker", "desktop")}
+	}
+	for _, dir := range dirs {
+		for _, file := range []string{"settings-store.json", "settings.json"} {
+			content, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				continue
+			}
+			if shared := parseFileSharingDirectories(content); shared != nil {
+				return shared
+			}
+		}
+	}
+	return nil
+}
+
+func parseFileSharingDirectories(content []byte) []string {
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(content, &settings); err != nil {
+		return nil
+	}
+	for k, v := range settings {
+		if !strings.EqualFold(k, "filesharingDirectories") {
+			continue
+		}
+		var shared []string
+		if err := json.Unmarshal(v, &shared); err != nil {
+			return nil
+		}
+		return shared
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckBindMount(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "data")
+	assert.NilError(t, os.Mkdir(existing, 0o755))
+	missing := filepath.Join(dir, "missing")
+
+	bind := func(source string, createHostPath bool) types.ServiceVolumeConfig {
+		return types.ServiceVolumeConfig{
+			Type:   types.VolumeTypeBind,
+			Source: source,
+			Target: "/data",
+			Bind:   &types.ServiceVolumeBind{CreateHostPath: createHostPath},
+		}
+	}
+
+	assert.NilError(t, checkBindMount("app", bind(existing, true), nil, false))
+	// missing source is only a warning, as engine creates it
+	assert.NilError(t, checkBindMount("app", bind(missing, true), nil, false))
+	assert.ErrorContains(t, checkBindMount("app", bind(missing, false), nil, false), "doesn't exist")
+
+	assert.NilError(t, checkBindMount("app", bind(existing, true), []string{dir}, false))
+	assert.ErrorContains(t, checkBindMount("app", bind(existing, true), []string{"/nowhere"}, false), "not shared with Docker Desktop")
+	assert.NilError(t, checkBindMount("app", bind("/var/run/docker.sock", true), []string{"/nowhere"}, false))
+}
+
+func TestActualPathCase(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "Data"), 0o755))
+
+	actual, err := actualPathCase(filepath.Join(dir, "Data"))
+	assert.NilError(t, err)
+	assert.Equal(t, actual, filepath.Join(dir, "Data"))
+
+	actual, err = actualPathCase(filepath.Join(dir, "data"))
+	assert.NilError(t, err)
+	assert.Equal(t, actual, filepath.Join(dir, "Data"))
+}
+
+func TestParseFileSharingDirectories(t *testing.T) {
+	shared := parseFileSharingDirectories([]byte(`{"FilesharingDirectories": ["/Users", "/tmp"], "other": true}`))
+	assert.DeepEqual(t, shared, []string{"/Users", "/tmp"})
+	shared = parseFileSharingDirectories([]byte(`{"filesharingDirectories": ["/Users"]}`))
+	assert.DeepEqual(t, shared, []string{"/Users"})
+	assert.Assert(t, parseFileSharingDirectories([]byte(`{}`)) == nil)
+}
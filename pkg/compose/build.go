@@ -19,13 +19,17 @@ package compose
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/platforms"
 	"github.com/docker/compose/v5/internal/tracing"
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
 	"github.com/docker/compose/v5/pkg/utils"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
@@ -36,11 +40,14 @@ func (s *composeService) Build(ctx context.Context, project *types.Project, opti
 	if err != nil {
 		return err
 	}
+	ctx = s.withOperationServices(ctx, project, options.Services)
 	return Run(ctx, func(ctx context.Context) error {
 		return tracing.SpanWrapFunc("project/build", tracing.ProjectOptions(ctx, project),
 			func(ctx context.Context) error {
-				_, err := s.build(ctx, project, options, nil)
-				return err
+				return withDeadline(ctx, options.Deadline, "build", func(ctx context.Context) error {
+					_, err := s.build(ctx, project, options, nil)
+					return err
+				})
 			})(ctx)
 	}, "build", s.events)
 }
@@ -81,6 +88,10 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		if localImagePresent && service.PullPolicy != types.PullPolicyBuild {
 			return nil
 		}
+		if _, built := getJournal(ctx).completed("build:" + image); built && localImagePresent {
+			// already built by the interrupted operation being resumed
+			return nil
+		}
 		serviceToBuild[serviceName] = *service
 		return nil
 	}, policy)
@@ -88,14 +99,34 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		return imageIDs, err
 	}
 
-	bake, err := buildWithBake(s.dockerCli)
+	if err := applyTagTemplate(ctx, project, serviceToBuild, time.Now()); err != nil {
+		return nil, err
+	}
+
+	bake, err := s.featureEnabled(project, features.Bake)
 	if err != nil {
 		return nil, err
 	}
 	if bake {
-		return s.doBuildBake(ctx, project, serviceToBuild, options)
+		bake, err = buildWithBake(s.dockerCli)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(serviceToBuild)) {
+		notifyService(ctx, name, api.ServiceBuilding, nil)
+	}
+	if bake {
+		imageIDs, err = s.doBuildBake(ctx, project, serviceToBuild, options)
+	} else {
+		imageIDs, err = s.doBuildClassic(ctx, project, serviceToBuild, options)
 	}
-	return s.doBuildClassic(ctx, project, serviceToBuild, options)
+	if err != nil {
+		for _, name := range slices.Sorted(maps.Keys(serviceToBuild)) {
+			notifyService(ctx, name, api.ServiceFailed, err)
+		}
+	}
+	return imageIDs, err
 }
 
 func (s *composeService) ensureImagesExists(ctx context.Context, project *types.Project, buildOpts *api.BuildOptions, quietPull bool) error {
@@ -122,12 +153,21 @@ func (s *composeService) ensureImagesExists(ctx context.Context, project *types.
 	if buildOpts != nil {
 		err = tracing.SpanWrapFunc("project/build", tracing.ProjectOptions(ctx, project),
 			func(ctx context.Context) error {
-				builtImages, err := s.build(ctx, project, *buildOpts, images)
+				var builtImages map[string]string
+				err := withDeadline(ctx, buildOpts.Deadline, "build", func(ctx context.Context) error {
+					var err error
+					builtImages, err = s.build(ctx, project, *buildOpts, images)
+					return err
+				})
 				if err != nil {
 					return err
 				}
+				if err := s.checkBuiltImagesPlatform(ctx, project, builtImages); err != nil {
+					return err
+				}
 
 				for name, digest := range builtImages {
+					getJournal(ctx).done("build:"+name, digest)
 					images[name] = api.ImageSummary{
 						Repository:  name,
 						ID:          digest,
@@ -163,6 +203,15 @@ func (s *composeService) getLocalImagesDigests(ctx context.Context, project *typ
 				imageNames.Add(volume.Source)
 			}
 		}
+		inits, err := getInitContainers(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, init := range inits {
+			if init.Image != "" {
+				imageNames.Add(init.Image)
+			}
+		}
 	}
 	imgs, err := s.getImageSummaries(ctx, imageNames.Elements())
 	if err != nil {
@@ -205,6 +254,47 @@ func (s *composeService) getLocalImagesDigests(ctx context.Context, project *typ
 	return imgs, nil
 }
 
+// checkBuiltImagesPlatform verifies images built for services declaring a platform actually target it, as
+// a Dockerfile can force another one (`FROM --platform=...`) and mismatch would only surface at runtime
+// as an exec format error
+func (s *composeService) checkBuiltImagesPlatform(ctx context.Context, project *types.Project, builtImages map[string]string) error {
+	if s.dryRun {
+		return nil
+	}
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Build == nil || service.Platform == "" {
+			continue
+		}
+		imageName := api.GetImageNameOrDefault(service, project.Name)
+		if _, ok := builtImages[imageName]; !ok {
+			continue
+		}
+		expected, err := platforms.Parse(service.Platform)
+		if err != nil {
+			return err
+		}
+		inspect, err := s.apiClient().ImageInspect(ctx, imageName)
+		if errdefs.IsNotFound(err) {
+			// image was not loaded into the engine image store
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		actual := specs.Platform{
+			Architecture: inspect.Architecture,
+			OS:           inspect.Os,
+			Variant:      inspect.Variant,
+		}
+		if !platforms.NewMatcher(expected).Match(actual) {
+			return fmt.Errorf("service %q: image %s was built for platform %s, but service requires %s",
+				name, imageName, platforms.Format(actual), service.Platform)
+		}
+	}
+	return nil
+}
+
 // resolveAndMergeBuildArgs returns the final set of build arguments to use for the service image build.
 //
 // First, args directly defined via `build.args` in YAML are considered.
@@ -218,6 +308,7 @@ func resolveAndMergeBuildArgs(proxyConfig map[string]string, project *types.Proj
 	result := make(types.MappingWithEquals).
 		OverrideBy(service.Build.Args).
 		OverrideBy(opts.Args).
+		OverrideBy(opts.Overrides.Args(service.Name)).
 		Resolve(envResolver(project.Environment))
 
 	// proxy arguments do NOT override and should NOT have env resolution applied,
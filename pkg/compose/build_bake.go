@@ -214,7 +214,10 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 
 		target := targets[serviceName]
 
-		secrets, env := toBakeSecrets(project, buildConfig.Secrets)
+		secrets, env, err := toBakeSecrets(project, buildConfig.Secrets)
+		if _, build := serviceToBeBuild[serviceName]; build && err != nil {
+			return nil, fmt.Errorf("service %q: %w", serviceName, err)
+		}
 		secretsEnv = append(secretsEnv, env...)
 
 		cfg.Targets[target] = bakeTarget{
@@ -335,6 +338,7 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 	}
 	cmd.Env = append(cmd.Env, endpoint...)
 	cmd.Env = append(cmd.Env, secretsEnv...)
+	masker := newSecretsMasker(secretsEnv)
 	defer cleanup()
 
 	cmd.Stdout = s.stdout()
@@ -368,6 +372,7 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 		var status client.SolveStatus
 		err := decoder.Decode(&status)
 		if err != nil {
+			line = masker.Replace(line)
 			if strings.HasPrefix(line, "ERROR: ") {
 				errMessage = append(errMessage, line[7:])
 			} else {
@@ -375,6 +380,7 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 			}
 			continue
 		}
+		maskSolveStatus(&status, masker)
 		ch <- &status
 	}
 	close(ch) // stop build progress UI
@@ -483,26 +489,6 @@ func toBakeSSH(ssh types.SSHConfig) []string {
 	return s
 }
 
-func toBakeSecrets(project *types.Project, secrets []types.ServiceSecretConfig) ([]string, []string) {
-	var s []string
-	var env []string
-	for _, ref := range secrets {
-		def := project.Secrets[ref.Source]
-		target := ref.Target
-		if target == "" {
-			target = ref.Source
-		}
-		switch {
-		case def.Environment != "":
-			env = append(env, fmt.Sprintf("%s=%s", def.Environment, project.Environment[def.Environment]))
-			s = append(s, fmt.Sprintf("id=%s,type=env,env=%s", target, def.Environment))
-		case def.File != "":
-			s = append(s, fmt.Sprintf("id=%s,type=file,src=%s", target, def.File))
-		}
-	}
-	return s, env
-}
-
 func toBakeAttest(buildConfig types.BuildConfig) []string {
 	var attests []string
 
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/buildkit/client"
+)
+
+// secretMask replaces secret values in build output
+const secretMask = "****"
+
+// toBakeSecrets converts the secrets used by a service build to bake secret specs, and the environment variables
+// to be passed to bake. A secret declared with `environment: PREFIX_*` is an environment group, exposing each
+// variable with this prefix as a distinct build secret using the variable name as ID.
+func toBakeSecrets(project *types.Project, secrets []types.ServiceSecretConfig) ([]string, []string, error) {
+	var s []string
+	var env []string
+	for _, ref := range secrets {
+		def, ok := project.Secrets[ref.Source]
+		if !ok {
+			return nil, nil, fmt.Errorf("build secret %q is not defined", ref.Source)
+		}
+		target := ref.Target
+		if target == "" {
+			target = ref.Source
+		}
+		switch {
+		case strings.HasSuffix(def.Environment, "*"):
+			prefix := strings.TrimSuffix(def.Environment, "*")
+			var found bool
+			for _, name := range slices.Sorted(maps.Keys(project.Environment)) {
+				if !strings.HasPrefix(name, prefix) {
+					continue
+				}
+				found = true
+				env = append(env, fmt.Sprintf("%s=%s", name, project.Environment[name]))
+				s = append(s, fmt.Sprintf("id=%s,type=env,env=%s", name, name))
+			}
+			if !found {
+				return nil, nil, fmt.Errorf("build secret %q: no environment variable is set matching %s", ref.Source, def.Environment)
+			}
+		case def.Environment != "":
+			value, ok := project.Environment[def.Environment]
+			if !ok {
+				return nil, nil, fmt.Errorf("build secret %q: environment variable %s is not set", ref.Source, def.Environment)
+			}
+			env = append(env, fmt.Sprintf("%s=%s", def.Environment, value))
+			s = append(s, fmt.Sprintf("id=%s,type=env,env=%s", target, def.Environment))
+		case def.File != "":
+			if _, err := os.Stat(def.File); err != nil {
+				return nil, nil, fmt.Errorf("build secret %q: %w", ref.Source, err)
+			}
+			s = append(s, fmt.Sprintf("id=%s,type=file,src=%s", target, def.File))
+		default:
+			return nil, nil, fmt.Errorf("build secret %q must be set by file or environment", ref.Source)
+		}
+	}
+	return s, env, nil
+}
+
+// newSecretsMasker creates a Replacer masking the values of secrets passed to bake as `KEY=value` environment
+func newSecretsMasker(env []string) *strings.Replacer {
+	var values []string
+	for _, e := range env {
+		_, v, _ := strings.Cut(e, "=")
+		if v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	// mask longest values first, so a secret containing another one is fully masked
+	slices.SortFunc(values, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	var pairs []string
+	for _, v := range values {
+		pairs = append(pairs, v, secretMask)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// maskSolveStatus masks secret values in BuildKit progress, before it gets displayed
+func maskSolveStatus(status *client.SolveStatus, masker *strings.Replacer) {
+	for _, v := range status.Vertexes {
+		v.Name = masker.Replace(v.Name)
+		v.Error = masker.Replace(v.Error)
+	}
+	for _, l := range status.Logs {
+		l.Data = []byte(masker.Replace(string(l.Data)))
+	}
+	for _, w := range status.Warnings {
+		w.Short = []byte(masker.Replace(string(w.Short)))
+		for i, d := range w.Detail {
+			w.Detail[i] = []byte(masker.Replace(string(d)))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/buildkit/client"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestToBakeSecretsEnvironmentGroup(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yaml")
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+name: test
+services:
+  app:
+    build:
+      context: .
+      secrets:
+        - npm
+        - source: token
+          target: api_token
+secrets:
+  npm:
+    environment: NPM_*
+  token:
+    environment: API_TOKEN
+`), 0o600))
+	t.Setenv("NPM_TOKEN", "s3cr3t")
+	t.Setenv("NPM_REGISTRY", "https://npm.example.com")
+	t.Setenv("API_TOKEN", "t0k3n")
+
+	service, err := NewComposeService(nil)
+	assert.NilError(t, err)
+	project, err := service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	assert.NilError(t, err)
+
+	secrets, env, err := toBakeSecrets(project, project.Services["app"].Build.Secrets)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, secrets, []string{
+		"id=NPM_REGISTRY,type=env,env=NPM_REGISTRY",
+		"id=NPM_TOKEN,type=env,env=NPM_TOKEN",
+		"id=api_token,type=env,env=API_TOKEN",
+	})
+	assert.DeepEqual(t, env, []string{
+		"NPM_REGISTRY=https://npm.example.com",
+		"NPM_TOKEN=s3cr3t",
+		"API_TOKEN=t0k3n",
+	})
+}
+
+func TestToBakeSecretsUndefined(t *testing.T) {
+	project := &types.Project{
+		Environment: types.Mapping{"OTHER": "value"},
+		Secrets: types.Secrets{
+			"group": {Environment: "NPM_*"},
+			"env":   {Environment: "API_TOKEN"},
+			"file":  {File: filepath.Join(t.TempDir(), "missing")},
+		},
+	}
+	_, _, err := toBakeSecrets(project, []types.ServiceSecretConfig{{Source: "unknown"}})
+	assert.Error(t, err, `build secret "unknown" is not defined`)
+	_, _, err = toBakeSecrets(project, []types.ServiceSecretConfig{{Source: "group"}})
+	assert.Error(t, err, `build secret "group": no environment variable is set matching NPM_*`)
+	_, _, err = toBakeSecrets(project, []types.ServiceSecretConfig{{Source: "env"}})
+	assert.Error(t, err, `build secret "env": environment variable API_TOKEN is not set`)
+	_, _, err = toBakeSecrets(project, []types.ServiceSecretConfig{{Source: "file"}})
+	assert.ErrorContains(t, err, `build secret "file": `)
+}
+
+func TestMaskSolveStatus(t *testing.T) {
+	masker := newSecretsMasker([]string{"NPM_TOKEN=s3cr3t", "LONG=s3cr3t-and-more", "EMPTY="})
+	status := &client.SolveStatus{
+		Vertexes: []*client.Vertex{{Name: "RUN echo s3cr3t", Error: "failed with s3cr3t-and-more"}},
+		Logs:     []*client.VertexLog{{Data: []byte("token is s3cr3t\n")}},
+		Warnings: []*client.VertexWarning{{Short: []byte("s3cr3t"), Detail: [][]byte{[]byte("detail s3cr3t")}}},
+	}
+	maskSolveStatus(status, masker)
+	assert.Equal(t, status.Vertexes[0].Name, "RUN echo ****")
+	assert.Equal(t, status.Vertexes[0].Error, "failed with ****")
+	assert.Equal(t, string(status.Logs[0].Data), "token is ****\n")
+	assert.Equal(t, string(status.Warnings[0].Short), "****")
+	assert.Equal(t, string(status.Warnings[0].Detail[0]), "detail ****")
+}
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/image"
+	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
 )
 
@@ -55,3 +57,31 @@ func Test_addBuildDependencies(t *testing.T) {
 	slices.Sort(expected)
 	assert.DeepEqual(t, services, expected)
 }
+
+func TestCheckBuiltImagesPlatform(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"app": {Name: "app", Platform: "linux/arm64", Build: &types.BuildConfig{Context: "."}},
+			"web": {Name: "web", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	built := map[string]string{"test-app": "sha256:1", "test-web": "sha256:2"}
+
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "test-app").Return(image.InspectResponse{
+		Os:           "linux",
+		Architecture: "arm64",
+	}, nil)
+	assert.NilError(t, tested.checkBuiltImagesPlatform(t.Context(), project, built))
+
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "test-app").Return(image.InspectResponse{
+		Os:           "linux",
+		Architecture: "amd64",
+	}, nil)
+	err := tested.checkBuiltImagesPlatform(t.Context(), project, built)
+	assert.Error(t, err, `service "app": image test-app was built for platform linux/amd64, but service requires linux/arm64`)
+}
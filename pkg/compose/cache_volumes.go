@@ -0,0 +1,136 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// CacheVolumesExtension declares, within the `develop` section of a service, container paths (like `node_modules`
+// or `/root/.m2`) to be stored on named cache volumes managed by compose
+const CacheVolumesExtension = "x-cache_volumes"
+
+var cacheVolumeNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// getCacheVolumes returns the container paths declared by CacheVolumesExtension, relative ones being resolved
+// against service working_dir
+func getCacheVolumes(service types.ServiceConfig) ([]string, error) {
+	if service.Develop == nil {
+		return nil, nil
+	}
+	v, ok := service.Develop.Extensions[CacheVolumesExtension]
+	if !ok {
+		return nil, nil
+	}
+	var paths []string
+	switch p := v.(type) {
+	case string:
+		paths = []string{p}
+	case []any:
+		for _, e := range p {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("service %q has invalid develop.%s: expected string, got %T", service.Name, CacheVolumesExtension, e)
+			}
+			paths = append(paths, s)
+		}
+	case []string:
+		paths = p
+	default:
+		return nil, fmt.Errorf("service %q has invalid develop.%s: expected string or list of strings", service.Name, CacheVolumesExtension)
+	}
+
+	for i, p := range paths {
+		if !path.IsAbs(p) {
+			if service.WorkingDir == "" {
+				return nil, fmt.Errorf("service %q: relative cache volume path %q requires working_dir to be set", service.Name, p)
+			}
+			p = path.Join(service.WorkingDir, p)
+		}
+		paths[i] = path.Clean(p)
+	}
+	return paths, nil
+}
+
+// cacheVolumeKey computes the project volume key used to store a service cache path
+func cacheVolumeKey(service string, target string) string {
+	return fmt.Sprintf("%s_cache%s", service, cacheVolumeNameInvalidChars.ReplaceAllString(strings.ReplaceAll(target, "/", "_"), ""))
+}
+
+// applyCacheVolumes mounts named cache volumes on paths declared by CacheVolumesExtension. Cache volumes are
+// labeled by compose so `down --volumes` keeps them unless `--include-cache` is set, and are excluded from watch
+// sync rules, so dependencies installed inside container don't get overwritten by host content.
+func applyCacheVolumes(project *types.Project) (*types.Project, error) {
+	for name, service := range project.Services {
+		paths, err := getCacheVolumes(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range paths {
+			if slices.ContainsFunc(service.Volumes, func(v types.ServiceVolumeConfig) bool {
+				return path.Clean(v.Target) == target
+			}) {
+				// explicit mount declared by user takes precedence
+				continue
+			}
+			key := cacheVolumeKey(name, target)
+			if project.Volumes == nil {
+				project.Volumes = types.Volumes{}
+			}
+			if _, ok := project.Volumes[key]; !ok {
+				project.Volumes[key] = types.VolumeConfig{
+					Name: fmt.Sprintf("%s_%s", project.Name, key),
+					Labels: types.Labels{
+						api.CacheVolumeLabel: "True",
+					},
+				}
+			}
+			service.Volumes = append(service.Volumes, types.ServiceVolumeConfig{
+				Type:   types.VolumeTypeVolume,
+				Source: key,
+				Target: target,
+			})
+
+			for i, trigger := range service.Develop.Watch {
+				if trigger.Target == "" {
+					continue
+				}
+				rel := strings.TrimPrefix(target, strings.TrimSuffix(trigger.Target, "/")+"/")
+				if rel == target || slices.Contains(trigger.Ignore, rel) {
+					continue
+				}
+				trigger.Ignore = append(trigger.Ignore, rel)
+				service.Develop.Watch[i] = trigger
+			}
+		}
+		project.Services[name] = service
+	}
+	return project, nil
+}
+
+// isCacheVolume returns true if volume was created by compose to store a service cache path
+func isCacheVolume(volume types.VolumeConfig) bool {
+	return volume.Labels[api.CacheVolumeLabel] == "True"
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestApplyCacheVolumes(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				WorkingDir: "/app",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "gradle", Target: "/root/.gradle"},
+				},
+				Develop: &types.DevelopConfig{
+					Watch: []types.Trigger{
+						{Path: "/src", Target: "/app", Action: types.WatchActionSync},
+					},
+					Extensions: types.Extensions{
+						CacheVolumesExtension: []any{"node_modules", "/root/.m2", "/root/.gradle"},
+					},
+				},
+			},
+		},
+	}
+	project, err := applyCacheVolumes(project)
+	assert.NilError(t, err)
+
+	service := project.Services["app"]
+	assert.DeepEqual(t, service.Volumes, []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeVolume, Source: "gradle", Target: "/root/.gradle"},
+		{Type: types.VolumeTypeVolume, Source: "app_cache_app_node_modules", Target: "/app/node_modules"},
+		{Type: types.VolumeTypeVolume, Source: "app_cache_root_.m2", Target: "/root/.m2"},
+	})
+	assert.DeepEqual(t, service.Develop.Watch[0].Ignore, []string{"node_modules"})
+
+	assert.Equal(t, len(project.Volumes), 2)
+	vol := project.Volumes["app_cache_app_node_modules"]
+	assert.Equal(t, vol.Name, "demo_app_cache_app_node_modules")
+	assert.Equal(t, vol.Labels[api.CacheVolumeLabel], "True")
+	assert.Check(t, isCacheVolume(vol))
+}
+
+func TestApplyCacheVolumesRequiresWorkingDir(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				Develop: &types.DevelopConfig{
+					Extensions: types.Extensions{
+						CacheVolumesExtension: "node_modules",
+					},
+				},
+			},
+		},
+	}
+	_, err := applyCacheVolumes(project)
+	assert.ErrorContains(t, err, `relative cache volume path "node_modules" requires working_dir`)
+}
@@ -0,0 +1,336 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// TLSExtension enables generation of a TLS certificate for a service, signed by a project local certificate authority.
+// Certificates are only meant for local development.
+const TLSExtension = "x-tls"
+
+const (
+	certsDirectory  = "compose/certs"
+	caName          = "ca"
+	caValidity      = 10 * 365 * 24 * time.Hour
+	certValidity    = 365 * 24 * time.Hour
+	certRenewBefore = 7 * 24 * time.Hour
+
+	// secret targets, relative to /run/secrets
+	tlsCertTarget = "tls.crt"
+	tlsKeyTarget  = "tls.key"
+	tlsCATarget   = "ca.crt"
+)
+
+func (s *composeService) RenewCertificates(ctx context.Context, project *types.Project, options api.CertificatesOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		renew := options.Services
+		for _, name := range options.Services {
+			service, err := project.GetService(name)
+			if err != nil {
+				return err
+			}
+			if !hasTLS(service) {
+				return fmt.Errorf("service %q doesn't declare %s", name, TLSExtension)
+			}
+		}
+		if len(renew) == 0 {
+			renew = project.ServiceNames()
+		}
+		return s.applyCertificates(project, certificatesDir(project.Name), options.CA, renew)
+	}, "certs", s.events)
+}
+
+// ensureCertificates generates missing or outdated certificates for services declaring TLSExtension
+// and mounts them as secrets
+func (s *composeService) ensureCertificates(project *types.Project) error {
+	return s.applyCertificates(project, certificatesDir(project.Name), false, nil)
+}
+
+func certificatesDir(projectName string) string {
+	return filepath.Join(config.Dir(), certsDirectory, projectName)
+}
+
+func hasTLS(service types.ServiceConfig) bool {
+	var enabled bool
+	_, _ = service.Extensions.Get(TLSExtension, &enabled)
+	return enabled
+}
+
+// applyCertificates issues certificates for services declaring TLSExtension, unless a valid one already exists
+// and service isn't selected for renewal, then declares them as project secrets so they get mounted in service containers.
+func (s *composeService) applyCertificates(project *types.Project, dir string, renewCA bool, renew []string) error {
+	var services []string
+	for name, service := range project.Services {
+		if hasTLS(service) {
+			services = append(services, name)
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	slices.Sort(services)
+
+	// in dry-run mode, certificates are generated in memory so they can be reported, but never written
+	if !s.dryRun {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+	ca, created, err := loadOrCreateCA(dir, renewCA, s.dryRun)
+	if err != nil {
+		return err
+	}
+	if created {
+		s.events.On(newEvent("Certificate authority "+project.Name, api.Done, "Generated"))
+	}
+
+	if project.Secrets == nil {
+		project.Secrets = types.Secrets{}
+	}
+	caSecret := project.Name + "-tls-ca"
+	project.Secrets[caSecret] = types.SecretConfig{Name: caSecret, File: filepath.Join(dir, caName+".crt")}
+
+	for _, name := range services {
+		service := project.Services[name]
+		issued, err := ca.issue(dir, name, serviceDNSNames(project, service), created || slices.Contains(renew, name))
+		if err != nil {
+			return err
+		}
+		if issued {
+			s.events.On(newEvent("Certificate "+name, api.Done, "Generated"))
+		}
+
+		certSecret := fmt.Sprintf("%s-%s-tls-cert", project.Name, name)
+		keySecret := fmt.Sprintf("%s-%s-tls-key", project.Name, name)
+		project.Secrets[certSecret] = types.SecretConfig{Name: certSecret, File: filepath.Join(dir, name+".crt")}
+		project.Secrets[keySecret] = types.SecretConfig{Name: keySecret, File: filepath.Join(dir, name+".key")}
+		service.Secrets = slices.DeleteFunc(service.Secrets, func(secret types.ServiceSecretConfig) bool {
+			return slices.Contains([]string{caSecret, certSecret, keySecret}, secret.Source)
+		})
+		service.Secrets = append(service.Secrets,
+			types.ServiceSecretConfig{Source: caSecret, Target: tlsCATarget},
+			types.ServiceSecretConfig{Source: certSecret, Target: tlsCertTarget},
+			types.ServiceSecretConfig{Source: keySecret, Target: tlsKeyTarget},
+		)
+		project.Services[name] = service
+	}
+	return nil
+}
+
+// serviceDNSNames computes the names a service can be reached by, to be used as certificate SANs
+func serviceDNSNames(project *types.Project, service types.ServiceConfig) []string {
+	names := []string{service.Name, "localhost"}
+	if service.Hostname != "" {
+		names = append(names, service.Hostname)
+	}
+	if service.ContainerName != "" {
+		names = append(names, service.ContainerName)
+	}
+	for _, nw := range service.Networks {
+		if nw != nil {
+			names = append(names, nw.Aliases...)
+		}
+	}
+	for i := 1; i <= service.GetScale(); i++ {
+		names = append(names, getContainerName(project.Name, service, i))
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+type certificateAuthority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	// dryRun prevents certificates and keys to be written
+	dryRun bool
+}
+
+// loadOrCreateCA loads the project certificate authority from dir, or creates a new one
+func loadOrCreateCA(dir string, renew bool, dryRun bool) (*certificateAuthority, bool, error) {
+	certFile, keyFile := filepath.Join(dir, caName+".crt"), filepath.Join(dir, caName+".key")
+	if !renew {
+		cert, key, err := loadKeyPair(certFile, keyFile)
+		if err == nil && time.Now().Before(cert.NotAfter) {
+			return &certificateAuthority{cert: cert, key: key, dryRun: dryRun}, false, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, false, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, false, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, false, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Docker Compose development CA"},
+			CommonName:   "Docker Compose development CA " + filepath.Base(dir),
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, false, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, false, err
+	}
+	if !dryRun {
+		if err := writeKeyPair(certFile, keyFile, der, key); err != nil {
+			return nil, false, err
+		}
+	}
+	return &certificateAuthority{cert: cert, key: key, dryRun: dryRun}, true, nil
+}
+
+// issue writes a certificate signed by ca for dnsNames, unless a valid one already exists and renew is false
+func (ca *certificateAuthority) issue(dir string, name string, dnsNames []string, renew bool) (bool, error) {
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if !renew {
+		cert, _, err := loadKeyPair(certFile, keyFile)
+		if err == nil && ca.isValid(cert, dnsNames) {
+			return false, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return false, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return false, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Docker Compose development"},
+			CommonName:   name,
+		},
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return false, err
+	}
+	if ca.dryRun {
+		return true, nil
+	}
+	return true, writeKeyPair(certFile, keyFile, der, key)
+}
+
+// isValid checks cert has been signed by ca, covers dnsNames and won't expire soon
+func (ca *certificateAuthority) isValid(cert *x509.Certificate, dnsNames []string) bool {
+	if time.Now().Add(certRenewBefore).After(cert.NotAfter) {
+		return false
+	}
+	if cert.CheckSignatureFrom(ca.cert) != nil {
+		return false
+	}
+	actual := slices.Sorted(slices.Values(cert.DNSNames))
+	return slices.Equal(actual, dnsNames)
+}
+
+func loadKeyPair(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("invalid certificate %s", certFile)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("invalid private key %s", keyFile)
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// writeKeyPair writes a certificate and its private key, which is only readable by the current user
+func writeKeyPair(certFile, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		return err
+	}
+	// restrict the key to owner explicitly, as WriteFile keeps the mode of an existing file
+	if err := os.Chmod(keyFile, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
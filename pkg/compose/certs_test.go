@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyCertificates(t *testing.T) {
+	dir := t.TempDir()
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"web": {
+				Name:       "web",
+				Extensions: types.Extensions{TLSExtension: true},
+				Networks: map[string]*types.ServiceNetworkConfig{
+					"default": {Aliases: []string{"www"}},
+				},
+			},
+			"db": {Name: "db"},
+		},
+	}
+	s := &composeService{events: &ignore{}}
+	err := s.applyCertificates(project, dir, false, nil)
+	assert.NilError(t, err)
+
+	web := project.Services["web"]
+	assert.Equal(t, len(web.Secrets), 3)
+	assert.Equal(t, len(project.Services["db"].Secrets), 0)
+	assert.Equal(t, project.Secrets["test-web-tls-cert"].File, filepath.Join(dir, "web.crt"))
+
+	ca, created, err := loadOrCreateCA(dir, false, false)
+	assert.NilError(t, err)
+	assert.Assert(t, !created)
+	cert, _, err := loadKeyPair(filepath.Join(dir, "web.crt"), filepath.Join(dir, "web.key"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cert.DNSNames, []string{"localhost", "test-web-1", "web", "www"})
+	assert.Assert(t, ca.isValid(cert, serviceDNSNames(project, web)))
+	info, err := os.Stat(filepath.Join(dir, "web.key"))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o600))
+
+	// applying again keeps existing certificate and doesn't duplicate secrets
+	err = s.applyCertificates(project, dir, false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(project.Services["web"].Secrets), 3)
+	again, _, err := loadKeyPair(filepath.Join(dir, "web.crt"), filepath.Join(dir, "web.key"))
+	assert.NilError(t, err)
+	assert.Equal(t, again.SerialNumber.String(), cert.SerialNumber.String())
+
+	// renewal issues a new certificate
+	err = s.applyCertificates(project, dir, false, []string{"web"})
+	assert.NilError(t, err)
+	renewed, _, err := loadKeyPair(filepath.Join(dir, "web.crt"), filepath.Join(dir, "web.key"))
+	assert.NilError(t, err)
+	assert.Assert(t, renewed.SerialNumber.String() != cert.SerialNumber.String())
+}
+
+func TestIssueOnAliasChange(t *testing.T) {
+	dir := t.TempDir()
+	ca, _, err := loadOrCreateCA(dir, false, false)
+	assert.NilError(t, err)
+	issued, err := ca.issue(dir, "web", []string{"web"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, issued)
+	issued, err = ca.issue(dir, "web", []string{"web"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, !issued)
+	issued, err = ca.issue(dir, "web", []string{"api", "web"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, issued)
+}
+
+func TestApplyCertificatesDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "certs")
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"web": {Name: "web", Extensions: types.Extensions{TLSExtension: true}},
+		},
+	}
+	s := &composeService{events: &ignore{}, dryRun: true}
+	err := s.applyCertificates(project, dir, false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(project.Services["web"].Secrets), 3)
+	_, err = os.Stat(dir)
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,208 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// ChownExtension sets the owner (as `uid[:gid]`) of a volume or bind mount, enforced before services are started.
+	// It can be set on a top-level volume, or on a service volume mount
+	ChownExtension = "x-chown"
+	// ChownImage is used to run the helper container setting volumes ownership
+	ChownImage = "busybox:stable"
+)
+
+var chownPattern = regexp.MustCompile(`^[0-9]+(:[0-9]+)?$`)
+
+// getChown returns the owner declared by ChownExtension, or an empty string
+func getChown(extensions types.Extensions) (string, error) {
+	v, ok := extensions[ChownExtension]
+	if !ok {
+		return "", nil
+	}
+	var owner string
+	switch o := v.(type) {
+	case string:
+		owner = o
+	case int:
+		owner = strconv.Itoa(o)
+	default:
+		return "", fmt.Errorf("invalid %s: expected `uid[:gid]`, got %v", ChownExtension, v)
+	}
+	if !chownPattern.MatchString(owner) {
+		return "", fmt.Errorf("invalid %s %q: expected numeric `uid[:gid]`", ChownExtension, owner)
+	}
+	return owner, nil
+}
+
+// chownTarget is a volume or bind mount which ownership must be set
+type chownTarget struct {
+	mountType mount.Type
+	source    string
+	owner     string
+}
+
+// getChownTargets collects volumes and bind mounts declaring ChownExtension, either on a top-level volume
+// or on a service volume mount. Mount-level declaration has precedence.
+func getChownTargets(project *types.Project) ([]chownTarget, error) {
+	owners := map[string]chownTarget{}
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		for _, v := range service.Volumes {
+			owner, err := getChown(v.Extensions)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", name, err)
+			}
+			var target chownTarget
+			switch v.Type {
+			case types.VolumeTypeBind:
+				target = chownTarget{mountType: mount.TypeBind, source: v.Source, owner: owner}
+			case types.VolumeTypeVolume:
+				vol, ok := project.Volumes[v.Source]
+				if !ok {
+					if owner != "" {
+						return nil, fmt.Errorf("service %q: %s can't be set on anonymous volume %s", name, ChownExtension, v.Target)
+					}
+					continue
+				}
+				if owner == "" {
+					owner, err = getChown(vol.Extensions)
+					if err != nil {
+						return nil, fmt.Errorf("volume %q: %w", v.Source, err)
+					}
+				}
+				target = chownTarget{mountType: mount.TypeVolume, source: vol.Name, owner: owner}
+			default:
+				if owner != "" {
+					return nil, fmt.Errorf("service %q: %s can't be set on %s mount %s", name, ChownExtension, v.Type, v.Target)
+				}
+				continue
+			}
+			if target.owner == "" {
+				continue
+			}
+			if other, ok := owners[target.source]; ok && other.owner != target.owner {
+				return nil, fmt.Errorf("conflicting %s for %s: %s and %s", ChownExtension, target.source, other.owner, target.owner)
+			}
+			owners[target.source] = target
+		}
+	}
+	var targets []chownTarget
+	for _, source := range slices.Sorted(maps.Keys(owners)) {
+		targets = append(targets, owners[source])
+	}
+	return targets, nil
+}
+
+// chownScript produces a shell script setting ownership of /chown/<index> mounts, only when the mount root
+// doesn't have the expected owner already, and reporting index of the updated ones.
+func chownScript(targets []chownTarget) string {
+	var sb strings.Builder
+	sb.WriteString("set -e\n")
+	for i, t := range targets {
+		// when only uid is set, group is left unchanged
+		format := "%u"
+		if strings.Contains(t.owner, ":") {
+			format = "%u:%g"
+		}
+		fmt.Fprintf(&sb, "if [ \"$(stat -c %[1]s /chown/%[2]d)\" != \"%[3]s\" ]; then chown -R %[3]s /chown/%[2]d; echo %[2]d; fi\n", format, i, t.owner)
+	}
+	return sb.String()
+}
+
+// ensureOwnership sets ownership of volumes and bind mounts declaring ChownExtension, using a helper container
+func (s *composeService) ensureOwnership(ctx context.Context, project *types.Project) error {
+	targets, err := getChownTargets(project)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	if s.dryRun {
+		for _, t := range targets {
+			s.events.On(newEvent(chownEventName(t), api.Done, "Owner set to "+t.owner))
+		}
+		return nil
+	}
+	if err := s.ensureHelperImage(ctx, ChownImage); err != nil {
+		return err
+	}
+
+	var (
+		mounts []mount.Mount
+		binds  []string
+	)
+	for i, t := range targets {
+		target := fmt.Sprintf("/chown/%d", i)
+		if t.mountType == mount.TypeBind {
+			// legacy binds syntax creates missing host path, as engine would do for service container
+			binds = append(binds, t.source+":"+target)
+			continue
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:   t.mountType,
+			Source: t.source,
+			Target: target,
+		})
+	}
+	output, exitCode, err := s.runHelper(ctx, &container.Config{
+		Image:      ChownImage,
+		User:       "0:0",
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{chownScript(targets)},
+	}, &container.HostConfig{
+		Binds:       binds,
+		Mounts:      mounts,
+		NetworkMode: "none",
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("failed to set %s ownership: exit %d", ChownExtension, exitCode)
+	}
+	for _, line := range strings.Fields(output) {
+		i, err := strconv.Atoi(line)
+		if err != nil || i < 0 || i >= len(targets) {
+			continue
+		}
+		s.events.On(newEvent(chownEventName(targets[i]), api.Done, "Owner set to "+targets[i].owner))
+	}
+	return nil
+}
+
+func chownEventName(t chownTarget) string {
+	if t.mountType == mount.TypeVolume {
+		return "Volume " + t.source
+	}
+	return "Bind mount " + t.source
+}
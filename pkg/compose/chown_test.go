@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetChownTargets(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: "/src", Target: "/app", Extensions: types.Extensions{ChownExtension: "1000:1000"}},
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"},
+					{Type: types.VolumeTypeVolume, Source: "cache", Target: "/cache"},
+				},
+			},
+			"worker": {
+				Name: "worker",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "cache", Target: "/cache", Extensions: types.Extensions{ChownExtension: 1001}},
+				},
+			},
+		},
+		Volumes: types.Volumes{
+			"data":  {Name: "demo_data", Extensions: types.Extensions{ChownExtension: "999"}},
+			"cache": {Name: "demo_cache"},
+		},
+	}
+	targets, err := getChownTargets(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, targets, []chownTarget{
+		{mountType: mount.TypeBind, source: "/src", owner: "1000:1000"},
+		{mountType: mount.TypeVolume, source: "demo_cache", owner: "1001"},
+		{mountType: mount.TypeVolume, source: "demo_data", owner: "999"},
+	}, cmp.AllowUnexported(chownTarget{}))
+}
+
+func TestGetChownTargetsInvalid(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: "/src", Target: "/app", Extensions: types.Extensions{ChownExtension: "www-data"}},
+				},
+			},
+		},
+	}
+	_, err := getChownTargets(project)
+	assert.ErrorContains(t, err, `invalid x-chown "www-data"`)
+
+	project.Services["app"] = types.ServiceConfig{
+		Name: "app",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: types.VolumeTypeVolume, Target: "/app", Extensions: types.Extensions{ChownExtension: "1000"}},
+		},
+	}
+	_, err = getChownTargets(project)
+	assert.ErrorContains(t, err, "can't be set on anonymous volume")
+}
+
+func TestChownScript(t *testing.T) {
+	script := chownScript([]chownTarget{
+		{mountType: mount.TypeBind, source: "/src", owner: "1000:1000"},
+		{mountType: mount.TypeVolume, source: "demo_data", owner: "999"},
+	})
+	assert.Equal(t, script, `set -e
+if [ "$(stat -c %u:%g /chown/0)" != "1000:1000" ]; then chown -R 1000:1000 /chown/0; echo 0; fi
+if [ "$(stat -c %u /chown/1)" != "999" ]; then chown -R 999 /chown/1; echo 1; fi
+`)
+}
@@ -19,8 +19,10 @@ package compose
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/docker/api/types/container"
 )
@@ -34,11 +36,70 @@ func (s *composeService) Commit(ctx context.Context, projectName string, options
 func (s *composeService) commit(ctx context.Context, projectName string, options api.CommitOptions) error {
 	projectName = strings.ToLower(projectName)
 
+	if options.Service == "" {
+		return s.commitServices(ctx, projectName, options)
+	}
+
 	ctr, err := s.getSpecifiedContainer(ctx, projectName, oneOffInclude, false, options.Service, options.Index)
 	if err != nil {
 		return err
 	}
+	return s.commitContainer(ctx, ctr, options.Reference, options)
+}
+
+// commitServices commits a container for each of the selected services, or all services, as `project/service:tag`
+// images, then writes an override pinning services to the committed images
+func (s *composeService) commitServices(ctx context.Context, projectName string, options api.CommitOptions) error {
+	tag := options.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+	services := slices.Clone(options.Services)
+	if len(services) == 0 {
+		containers, err := s.getContainers(ctx, projectName, oneOffExclude, true)
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			if service := c.Labels[api.ServiceLabel]; !slices.Contains(services, service) {
+				services = append(services, service)
+			}
+		}
+		if len(services) == 0 {
+			return fmt.Errorf("no container to commit for project %q", projectName)
+		}
+	}
+	slices.Sort(services)
+
+	override := types.Project{
+		Services: types.Services{},
+	}
+	for _, service := range services {
+		ctr, err := s.getSpecifiedContainer(ctx, projectName, oneOffExclude, true, service, options.Index)
+		if err != nil {
+			return err
+		}
+		reference := fmt.Sprintf("%s/%s:%s", projectName, service, tag)
+		if err := s.commitContainer(ctx, ctr, reference, options); err != nil {
+			return err
+		}
+		override.Services[service] = types.ServiceConfig{
+			Image: reference,
+		}
+	}
+
+	if options.Override == nil {
+		return nil
+	}
+	content, err := override.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	_, err = options.Override.Write(content)
+	return err
+}
 
+func (s *composeService) commitContainer(ctx context.Context, ctr container.Summary, reference string, options api.CommitOptions) error {
 	name := getCanonicalContainerName(ctr)
 
 	s.events.On(api.Resource{
@@ -58,7 +119,7 @@ func (s *composeService) commit(ctx context.Context, projectName string, options
 	}
 
 	response, err := s.apiClient().ContainerCommit(ctx, ctr.ID, container.CommitOptions{
-		Reference: options.Reference,
+		Reference: reference,
 		Comment:   options.Comment,
 		Author:    options.Author,
 		Changes:   options.Changes.GetSlice(),
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	compose "github.com/docker/compose/v5/pkg/api"
+)
+
+func TestCommitServices(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &recordingEvents{}}
+
+	containers := []container.Summary{
+		testContainer("web", "123", false),
+		testContainer("db", "456", false),
+	}
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+			var selected []container.Summary
+			for _, c := range containers {
+				service := compose.ServiceLabel + "=" + c.Labels[compose.ServiceLabel]
+				if !slices.ContainsFunc(options.Filters.Get("label"), isServiceFilter) || options.Filters.ExactMatch("label", service) {
+					selected = append(selected, c)
+				}
+			}
+			return selected, nil
+		}).AnyTimes()
+	apiClient.EXPECT().ContainerCommit(gomock.Any(), "456", container.CommitOptions{Reference: "testproject/db:snapshot"}).
+		Return(container.CommitResponse{ID: "sha256:db"}, nil)
+	apiClient.EXPECT().ContainerCommit(gomock.Any(), "123", container.CommitOptions{Reference: "testproject/web:snapshot"}).
+		Return(container.CommitResponse{ID: "sha256:web"}, nil)
+
+	var override bytes.Buffer
+	err := tested.commit(t.Context(), strings.ToLower(testProject), compose.CommitOptions{
+		Tag:      "snapshot",
+		Override: &override,
+		Changes:  opts.NewListOpts(nil),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, override.String(), `services:
+  db:
+    image: testproject/db:snapshot
+  web:
+    image: testproject/web:snapshot
+`)
+}
+
+func isServiceFilter(label string) bool {
+	return strings.HasPrefix(label, compose.ServiceLabel+"=")
+}
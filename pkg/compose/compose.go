@@ -42,6 +42,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
 )
 
 type Option func(service *composeService) error
@@ -66,7 +67,32 @@ type Option func(service *composeService) error
 //
 //	service := NewComposeService(dockerCli,
 //	    WithStreams(customOut, customErr, customIn))
+//
+// Library consumers can also create the service without a Docker CLI, passing nil and
+// providing their own Docker API client:
+//
+//	apiClient, _ := client.NewClientWithOpts(client.FromEnv)
+//	service := NewComposeService(nil,
+//	    WithAPIClient(apiClient),
+//	    WithStreams(customOut, customErr, customIn),
+//	    WithEventProcessorFactory(newProgressWriter))
+//
+// When alternate backends have been registered with RegisterBackend, the one selected by the current Docker context
+// metadata is created instead of the Docker backend.
 func NewComposeService(dockerCli command.Cli, options ...Option) (api.Compose, error) {
+	factory, err := selectBackend(dockerCli)
+	if err != nil {
+		return nil, err
+	}
+	if factory != nil {
+		return factory(dockerCli, options...)
+	}
+	return NewDockerBackend(dockerCli, options...)
+}
+
+// NewDockerBackend creates a Compose service managing resources with the Docker engine, regardless of the backend
+// selected by Docker context. Alternate backends use it to delegate operations they don't handle.
+func NewDockerBackend(dockerCli command.Cli, options ...Option) (api.Compose, error) {
 	s := &composeService{
 		dockerCli:      dockerCli,
 		clock:          clockwork.NewRealClock(),
@@ -78,6 +104,28 @@ func NewComposeService(dockerCli command.Cli, options ...Option) (api.Compose, e
 			return nil, err
 		}
 	}
+	switch {
+	case s.customClient != nil && s.dockerCli == nil:
+		cli, err := newClientOnlyCli(s.customClient)
+		if err != nil {
+			return nil, err
+		}
+		s.dockerCli = cli
+	case s.customClient != nil:
+		s.dockerCli = &clientOverrideWrapper{Cli: s.dockerCli, client: s.customClient}
+	}
+	if s.dryRun {
+		if err := s.wrapDockerCliForDryRun(); err != nil {
+			return nil, err
+		}
+	}
+	if s.features == nil {
+		set, err := features.Load()
+		if err != nil {
+			return nil, err
+		}
+		s.features = set
+	}
 	if s.prompt == nil {
 		s.prompt = func(message string, defaultValue bool) (bool, error) {
 			fmt.Println(message)
@@ -85,18 +133,77 @@ func NewComposeService(dockerCli command.Cli, options ...Option) (api.Compose, e
 			return defaultValue, nil
 		}
 	}
-	if s.events == nil {
-		s.events = &ignore{}
-	}
 
 	// If custom streams were provided, wrap the Docker CLI to use them
 	if s.outStream != nil || s.errStream != nil || s.inStream != nil {
-		s.dockerCli = s.wrapDockerCliWithStreams(dockerCli)
+		s.dockerCli = s.wrapDockerCliWithStreams(s.dockerCli)
 	}
 
+	if s.events == nil && s.eventsFactory != nil && s.dockerCli != nil {
+		s.events = s.eventsFactory(s.stdout(), s.stderr())
+	}
+	if s.events == nil {
+		s.events = &ignore{}
+	}
+	if len(s.metricsHooks) > 0 && !s.metricsDisabled {
+		s.events = newMetricsProcessor(s.events, s.clock, s.metricsHooks)
+	}
+	if len(s.timingsHooks) > 0 {
+		s.events = newTimingsProcessor(s.events, s.clock, s.timingsHooks)
+	}
 	return s, nil
 }
 
+// WithAPIClient sets the Docker API client used by Compose. This allows library consumers to create
+// a Compose service without a Docker CLI, passing nil to NewComposeService.
+func WithAPIClient(apiClient client.APIClient) Option {
+	return func(s *composeService) error {
+		s.customClient = apiClient
+		return nil
+	}
+}
+
+// EventProcessorFactory creates the component notified on Compose operation and progress events,
+// writing to the output streams configured for the Compose service
+type EventProcessorFactory func(out, err io.Writer) api.EventProcessor
+
+// WithEventProcessorFactory configures a factory for the component notified on Compose operation and
+// progress events, which is created once streams are set. Ignored if WithEventProcessor is also set.
+func WithEventProcessorFactory(factory EventProcessorFactory) Option {
+	return func(s *composeService) error {
+		s.eventsFactory = factory
+		return nil
+	}
+}
+
+// newClientOnlyCli creates a Docker CLI relying on apiClient to access engine, for library consumers
+// which don't have a Docker CLI set up
+func newClientOnlyCli(apiClient client.APIClient) (command.Cli, error) {
+	cli, err := command.NewDockerCli()
+	if err != nil {
+		return nil, err
+	}
+	options := flags.NewClientOptions()
+	options.Hosts = []string{apiClient.DaemonHost()}
+	err = cli.Initialize(options, command.WithInitializeClient(func(*command.DockerCli) (client.APIClient, error) {
+		return apiClient, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
+
+// clientOverrideWrapper wraps command.Cli to override the Docker API client
+type clientOverrideWrapper struct {
+	command.Cli
+	client client.APIClient
+}
+
+func (w *clientOverrideWrapper) Client() client.APIClient {
+	return w.client
+}
+
 // WithStreams sets custom I/O streams for output and interaction
 func WithStreams(out, err io.Writer, in io.Reader) Option {
 	return func(s *composeService) error {
@@ -166,6 +273,11 @@ func WithMaxConcurrency(maxConcurrency int) Option {
 // WithDryRun configure Compose to run without actually applying changes
 func WithDryRun(s *composeService) error {
 	s.dryRun = true
+	return nil
+}
+
+// wrapDockerCliForDryRun replaces the Docker API client with one which doesn't apply changes
+func (s *composeService) wrapDockerCliForDryRun() error {
 	cli, err := command.NewDockerCli()
 	if err != nil {
 		return err
@@ -183,6 +295,24 @@ func WithDryRun(s *composeService) error {
 	return nil
 }
 
+// WithPolicy configures a policy evaluated against project before up, down and run, which can deny the operation.
+// Policy is either a rego file, evaluated by `opa` for `data.compose.deny` rules, or a command reading
+// operation and project as JSON on stdin, and reporting violations as JSON on stdout.
+func WithPolicy(policy string) Option {
+	return func(s *composeService) error {
+		s.policy = policy
+		return nil
+	}
+}
+
+// WithFeatures sets the features state, instead of loading it from the features configuration file and environment
+func WithFeatures(set *features.Set) Option {
+	return func(s *composeService) error {
+		s.features = set
+		return nil
+	}
+}
+
 type Prompt func(message string, defaultValue bool) (bool, error)
 
 // AlwaysOkPrompt returns a Prompt implementation that always returns true without user interaction.
@@ -207,17 +337,31 @@ type composeService struct {
 	prompt Prompt
 	// eventBus collects tasks execution events
 	events api.EventProcessor
+	// eventsFactory creates events processor once streams are set, see WithEventProcessorFactory
+	eventsFactory EventProcessorFactory
+	// metricsHooks are notified with telemetry as operations complete, see WithMetricsHook
+	metricsHooks []api.MetricsHook
+	// metricsDisabled turns off operation metrics and tracing, see WithoutMetrics
+	metricsDisabled bool
+	// timingsHooks are notified with services timings as up and down operations complete, see WithServiceTimings
+	timingsHooks []api.ServiceTimingsHook
 
 	// Optional overrides for specific components (for SDK users)
-	outStream   io.Writer
-	errStream   io.Writer
-	inStream    io.Reader
-	contextInfo api.ContextInfo
-	proxyConfig map[string]string
+	outStream io.Writer
+	errStream io.Writer
+	inStream  io.Reader
+	// customClient is the Docker API client set by WithAPIClient
+	customClient client.APIClient
+	contextInfo  api.ContextInfo
+	proxyConfig  map[string]string
 
 	clock          clockwork.Clock
 	maxConcurrency int
 	dryRun         bool
+	// policy is evaluated against project before mutating operations, see WithPolicy
+	policy string
+	// features holds optional behaviors state, see WithFeatures
+	features *features.Set
 }
 
 // Close releases any connections/resources held by the underlying clients.
@@ -236,6 +380,19 @@ func (s *composeService) apiClient() client.APIClient {
 	return s.dockerCli.Client()
 }
 
+// featureEnabled reports whether feature name is enabled, taking project overrides into account
+func (s *composeService) featureEnabled(project *types.Project, name string) (bool, error) {
+	set := s.features
+	if set == nil {
+		set = features.Defaults()
+	}
+	set, err := set.ForProject(project)
+	if err != nil {
+		return false, err
+	}
+	return set.Enabled(name), nil
+}
+
 func (s *composeService) configFile() *configfile.ConfigFile {
 	return s.dockerCli.ConfigFile()
 }
@@ -400,6 +557,9 @@ func (s *composeService) projectFromName(containers Containers, projectName stri
 					if len(dcArr) > 2 {
 						restart, _ = strconv.ParseBool(dcArr[2])
 					}
+					if len(dcArr) > 3 {
+						required, _ = strconv.ParseBool(dcArr[3])
+					}
 				}
 				service.DependsOn[dependency] = types.ServiceDependency{Condition: condition, Restart: restart, Required: required}
 			}
@@ -497,6 +657,7 @@ func (s *composeService) isSWarmEnabled(ctx context.Context) (bool, error) {
 type runtimeVersionCache struct {
 	once sync.Once
 	val  string
+	os   string
 	err  error
 }
 
@@ -509,6 +670,15 @@ func (s *composeService) RuntimeVersion(ctx context.Context) (string, error) {
 			runtimeVersion.err = err
 		}
 		runtimeVersion.val = version.APIVersion
+		runtimeVersion.os = version.Os
 	})
 	return runtimeVersion.val, runtimeVersion.err
 }
+
+// runtimeOS returns the operating system of the containers run by the engine, as reported alongside RuntimeVersion
+func (s *composeService) runtimeOS(ctx context.Context) (string, error) {
+	if _, err := s.RuntimeVersion(ctx); err != nil {
+		return "", err
+	}
+	return runtimeVersion.os, nil
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	ctypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func TestNewComposeServiceWithAPIClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	apiClient.EXPECT().DaemonHost().Return("unix:///var/run/docker.sock").AnyTimes()
+	apiClient.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, nil).AnyTimes()
+	apiClient.EXPECT().NegotiateAPIVersionPing(gomock.Any()).AnyTimes()
+
+	out, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	var progress io.Writer
+	service, err := NewComposeService(nil,
+		WithAPIClient(apiClient),
+		WithStreams(out, stderr, nil),
+		WithEventProcessorFactory(func(_, err io.Writer) api.EventProcessor {
+			progress = err
+			return &ignore{}
+		}))
+	assert.NilError(t, err)
+	s := service.(*composeService)
+	assert.Equal(t, s.apiClient(), apiClient)
+	assert.Equal(t, s.dockerCli.DockerEndpoint().Host, "unix:///var/run/docker.sock")
+
+	_, _ = s.stdout().Write([]byte("hello"))
+	assert.Equal(t, out.String(), "hello")
+	_, _ = progress.Write([]byte("progress"))
+	assert.Equal(t, stderr.String(), "progress")
+}
+
+func TestProjectFromNameDependencies(t *testing.T) {
+	containers := Containers{
+		{Labels: map[string]string{api.ServiceLabel: "db"}},
+		{Labels: map[string]string{api.ServiceLabel: "cache"}},
+		{Labels: map[string]string{
+			api.ServiceLabel:      "web",
+			api.DependenciesLabel: "db:service_healthy:true:true,cache:service_healthy:false:false",
+		}},
+	}
+	project, err := (&composeService{}).projectFromName(containers, "myproject")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["web"].DependsOn, ctypes.DependsOnConfig{
+		"db":    {Condition: ctypes.ServiceConditionHealthy, Restart: true, Required: true},
+		"cache": {Condition: ctypes.ServiceConditionHealthy, Restart: false, Required: false},
+	})
+
+	// labels set by older releases don't store whether dependency is required
+	containers[2].Labels[api.DependenciesLabel] = "db:service_healthy:true"
+	project, err = (&composeService{}).projectFromName(containers, "myproject")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["web"].DependsOn, ctypes.DependsOnConfig{
+		"db": {Condition: ctypes.ServiceConditionHealthy, Restart: true, Required: true},
+	})
+}
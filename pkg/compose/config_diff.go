@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// diffImageDigest is reported when service image has been updated since container was created
+	diffImageDigest = "image digest"
+	// diffUnknown is reported when container configuration changed, but labels don't tell which aspect
+	diffUnknown = "config"
+)
+
+// configDiff lists the aspects of service configuration which changed since container was created.
+// Containers created with labels schema v1 only store a global config hash, so changes can't be detailed.
+func configDiff(expected types.ServiceConfig, actual container.Summary) ([]string, error) {
+	var diff []string
+	if digest, ok := expected.CustomLabels[api.ImageDigestLabel]; ok && actual.Labels[api.ImageDigestLabel] != digest {
+		diff = append(diff, diffImageDigest)
+	}
+
+	hash, err := ServiceHash(expected)
+	if err != nil {
+		return nil, err
+	}
+	if actual.Labels[api.ConfigHashLabel] == hash {
+		return diff, nil
+	}
+
+	label, ok := actual.Labels[api.ConfigHashComponentsLabel]
+	if !ok || actual.Labels[api.LabelsSchemaLabel] != api.LabelsSchemaVersion {
+		return append(diff, diffUnknown), nil
+	}
+	stored := parseHashComponents(label)
+	components, err := ServiceHashComponents(expected)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		if stored[name] != components[name] {
+			diff = append(diff, name)
+			changed = true
+		}
+	}
+	if !changed {
+		diff = append(diff, diffUnknown)
+	}
+	return diff, nil
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestConfigDiff(t *testing.T) {
+	service := types.ServiceConfig{
+		Name:    "foo",
+		Image:   "bar",
+		Command: types.ShellCommand{"run"},
+		CustomLabels: types.Labels{
+			api.ImageDigestLabel: "sha256:1",
+		},
+	}
+	labels, err := (&composeService{}).prepareLabels(types.Labels{api.ImageDigestLabel: "sha256:1"}, service, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, labels[api.LabelsSchemaLabel], api.LabelsSchemaVersion)
+	ctr := container.Summary{Labels: labels}
+
+	diff, err := configDiff(service, ctr)
+	assert.NilError(t, err)
+	assert.Assert(t, diff == nil)
+
+	updated := service
+	updated.Command = types.ShellCommand{"serve"}
+	updated.Ports = []types.ServicePortConfig{{Target: 80}}
+	updated.CustomLabels = types.Labels{api.ImageDigestLabel: "sha256:2"}
+	diff, err = configDiff(updated, ctr)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diff, []string{diffImageDigest, hashComponentCommand, hashComponentNetworks})
+}
+
+func TestConfigDiffLabelsSchemaV1(t *testing.T) {
+	service := types.ServiceConfig{Name: "foo", Image: "bar"}
+	hash, err := ServiceHash(service)
+	assert.NilError(t, err)
+	// container created by an older compose release
+	ctr := container.Summary{Labels: map[string]string{api.ConfigHashLabel: hash}}
+
+	diff, err := configDiff(service, ctr)
+	assert.NilError(t, err)
+	assert.Assert(t, diff == nil)
+
+	service.User = "nobody"
+	diff, err = configDiff(service, ctr)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diff, []string{diffUnknown})
+}
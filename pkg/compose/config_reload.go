@@ -0,0 +1,239 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// configReloadInterval is the delay between checks for compose files changes
+var configReloadInterval = time.Second
+
+// configReloader detects changes to compose files while up is attached, and applies the reloaded model
+// on demand, recreating only the affected services
+type configReloader struct {
+	s       *composeService
+	options api.UpOptions
+	logTo   api.LogConsumer
+	// notify tells if changes are waiting to be applied
+	notify func(pending bool)
+
+	mu      sync.Mutex
+	current *types.Project
+	next    *types.Project
+	diff    projectDiff
+}
+
+func newConfigReloader(s *composeService, project *types.Project, options api.UpOptions, logTo api.LogConsumer, notify func(bool)) *configReloader {
+	return &configReloader{
+		s:       s,
+		options: options,
+		logTo:   logTo,
+		notify:  notify,
+		current: project,
+	}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFiles(paths []string) map[string]fileState {
+	states := map[string]fileState{}
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			// stdin, remote resource, or file being replaced by an editor
+			continue
+		}
+		states[path] = fileState{modTime: fi.ModTime(), size: fi.Size()}
+	}
+	return states
+}
+
+// watch polls compose files until ctx is done, and reloads project when any has changed
+func (r *configReloader) watch(ctx context.Context) {
+	files := r.current.ComposeFiles
+	states := statFiles(files)
+	ticker := time.NewTicker(configReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			latest := statFiles(files)
+			if !maps.Equal(latest, states) {
+				states = latest
+				r.check(ctx)
+			}
+		}
+	}
+}
+
+// check reloads project and reports services which would be affected by applying it
+func (r *configReloader) check(ctx context.Context) {
+	next, err := r.options.Reload(ctx)
+	if err != nil {
+		r.logTo.Err(api.WatchLogger, fmt.Sprintf("Failed to reload compose files: %v", err))
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	diff, err := diffProjects(r.current, next, r.options.Create.Services)
+	if err != nil {
+		r.logTo.Err(api.WatchLogger, fmt.Sprintf("Failed to compare compose files: %v", err))
+		return
+	}
+	if diff.empty() {
+		r.next = nil
+		r.notify(false)
+		return
+	}
+	r.next, r.diff = next, diff
+	r.logTo.Log(api.WatchLogger, fmt.Sprintf("Compose files changed (%s), press r to apply", diff))
+	r.notify(true)
+}
+
+// apply converges the application to the reloaded project
+func (r *configReloader) apply(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next == nil {
+		return nil
+	}
+	next, diff := r.next, r.diff
+	r.logTo.Log(api.WatchLogger, fmt.Sprintf("Applying compose files changes (%s)...", diff))
+	err := r.s.create(ctx, next, api.CreateOptions{
+		Build:                r.options.Create.Build,
+		Services:             r.options.Create.Services,
+		RemoveOrphans:        len(diff.removed) > 0,
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		Inherit:              true,
+		Timeout:              r.options.Create.Timeout,
+		SkipBindChecks:       r.options.Create.SkipBindChecks,
+	})
+	if err != nil {
+		r.logTo.Err(api.WatchLogger, fmt.Sprintf("Failed to apply compose files changes: %v", err))
+		r.notify(true)
+		return err
+	}
+
+	services := append(slices.Clone(diff.added), diff.changed...)
+	if len(services) > 0 {
+		p, err := next.WithSelectedServices(services, types.IncludeDependents)
+		if err != nil {
+			return err
+		}
+		err = r.s.start(ctx, next.Name, api.StartOptions{
+			Project:  p,
+			Services: services,
+			AttachTo: services,
+		}, nil)
+		if err != nil {
+			r.logTo.Err(api.WatchLogger, fmt.Sprintf("Application failed to start after compose files changes: %v", err))
+			r.notify(true)
+			return err
+		}
+	}
+	r.current, r.next = next, nil
+	r.logTo.Log(api.WatchLogger, "Compose files changes applied")
+	r.notify(false)
+	return nil
+}
+
+// projectDiff lists services affected by a project reload
+type projectDiff struct {
+	added   []string
+	removed []string
+	changed []string
+}
+
+func (d projectDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+func (d projectDiff) String() string {
+	var parts []string
+	if len(d.added) > 0 {
+		parts = append(parts, "added: "+strings.Join(d.added, ", "))
+	}
+	if len(d.changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(d.changed, ", "))
+	}
+	if len(d.removed) > 0 {
+		parts = append(parts, "removed: "+strings.Join(d.removed, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// diffProjects compares services configuration, restricted to selected ones if set
+func diffProjects(current, next *types.Project, selected []string) (projectDiff, error) {
+	inScope := func(name string) bool {
+		return len(selected) == 0 || slices.Contains(selected, name)
+	}
+	var diff projectDiff
+	for _, name := range next.ServiceNames() {
+		if !inScope(name) {
+			continue
+		}
+		before, ok := current.Services[name]
+		if !ok {
+			diff.added = append(diff.added, name)
+			continue
+		}
+		changed, err := serviceChanged(before, next.Services[name])
+		if err != nil {
+			return diff, err
+		}
+		if changed {
+			diff.changed = append(diff.changed, name)
+		}
+	}
+	for _, name := range current.ServiceNames() {
+		if _, ok := next.Services[name]; !ok && inScope(name) {
+			diff.removed = append(diff.removed, name)
+		}
+	}
+	return diff, nil
+}
+
+func serviceChanged(before, after types.ServiceConfig) (bool, error) {
+	a, err := json.Marshal(before)
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(after)
+	if err != nil {
+		return false, err
+	}
+	return string(a) != string(b), nil
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDiffProjects(t *testing.T) {
+	current := &types.Project{Services: types.Services{
+		"web":    {Name: "web", Image: "nginx:1.27"},
+		"db":     {Name: "db", Image: "postgres"},
+		"worker": {Name: "worker", Image: "worker"},
+	}}
+	next := &types.Project{Services: types.Services{
+		"web":   {Name: "web", Image: "nginx:1.28"},
+		"db":    {Name: "db", Image: "postgres"},
+		"cache": {Name: "cache", Image: "redis"},
+	}}
+
+	diff, err := diffProjects(current, next, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diff, projectDiff{
+		added:   []string{"cache"},
+		removed: []string{"worker"},
+		changed: []string{"web"},
+	}, cmp.AllowUnexported(projectDiff{}))
+	assert.Equal(t, diff.String(), "added: cache; changed: web; removed: worker")
+
+	diff, err = diffProjects(current, next, []string{"db", "worker"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diff, projectDiff{removed: []string{"worker"}}, cmp.AllowUnexported(projectDiff{}))
+
+	diff, err = diffProjects(current, current, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, diff.empty())
+}
+
+func TestConfigReloaderCheck(t *testing.T) {
+	current := &types.Project{Services: types.Services{
+		"web": {Name: "web", Image: "nginx:1.27"},
+	}}
+	next := &types.Project{Services: types.Services{
+		"web": {Name: "web", Image: "nginx:1.28"},
+	}}
+	var (
+		reloaded  *types.Project
+		reloadErr error
+		pending   bool
+	)
+	logs := &testLogConsumer{}
+	r := newConfigReloader(nil, current, api.UpOptions{
+		Reload: func(context.Context) (*types.Project, error) {
+			return reloaded, reloadErr
+		},
+	}, logs, func(p bool) { pending = p })
+
+	reloaded = next
+	r.check(t.Context())
+	assert.Assert(t, pending)
+	assert.Equal(t, r.next, next)
+
+	reloaded = current
+	r.check(t.Context())
+	assert.Assert(t, !pending)
+	assert.Assert(t, r.next == nil)
+
+	reloadErr = errors.New("invalid compose file")
+	r.check(t.Context())
+	assert.DeepEqual(t, logs.LogsForContainer(api.WatchLogger), []string{
+		"Compose files changed (changed: web), press r to apply",
+		"Failed to reload compose files: invalid compose file",
+	})
+}
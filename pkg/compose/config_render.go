@@ -0,0 +1,141 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// namedSections are top-level sections declaring resources by name, which are kept by minify even when empty
+var namedSections = []string{"services", "networks", "volumes", "configs", "secrets", "models"}
+
+// MarshalProject renders project in the format set by options
+func MarshalProject(project *types.Project, options api.ConfigOptions) ([]byte, error) {
+	if !options.Minify && !options.SortKeys {
+		switch options.Format {
+		case "json":
+			return project.MarshalJSON()
+		case "yaml":
+			return project.MarshalYAML()
+		default:
+			return nil, fmt.Errorf("unsupported format %q", options.Format)
+		}
+	}
+	// a generic model is rendered with sorted keys. Going through YAML keeps integers, which JSON would convert to floats
+	content, err := project.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	var model map[string]any
+	if err := yaml.Unmarshal(content, &model); err != nil {
+		return nil, err
+	}
+	return MarshalModel(model, options)
+}
+
+// MarshalModel renders a generic compose model in the format set by options, mapping keys being always sorted
+func MarshalModel(model map[string]any, options api.ConfigOptions) ([]byte, error) {
+	if options.Minify {
+		minifyModel(model)
+	}
+	switch options.Format {
+	case "json":
+		return json.MarshalIndent(model, "", "  ")
+	case "yaml":
+		buf := bytes.NewBuffer([]byte{})
+		encoder := yaml.NewEncoder(buf)
+		encoder.SetIndent(2)
+		err := encoder.Encode(model)
+		return buf.Bytes(), err
+	default:
+		return nil, fmt.Errorf("unsupported format %q", options.Format)
+	}
+}
+
+// minifyModel removes null and empty values, but keeps resources declared by name and service networks
+// which are meaningful even without attributes
+func minifyModel(model map[string]any) {
+	for key, value := range model {
+		section, ok := value.(map[string]any)
+		if !ok || !slices.Contains(namedSections, key) {
+			minifyEntry(model, key, value)
+			continue
+		}
+		for _, resource := range section {
+			r, ok := resource.(map[string]any)
+			if !ok {
+				continue
+			}
+			for k, v := range r {
+				networks, ok := v.(map[string]any)
+				if key == "services" && k == "networks" && ok {
+					// a service network without attribute is still an attachment
+					for n, attachment := range networks {
+						networks[n] = minifyValue(attachment)
+					}
+					continue
+				}
+				minifyEntry(r, k, v)
+			}
+		}
+	}
+}
+
+// minifyEntry sets minified value in m, or removes key when value is null or empty
+func minifyEntry(m map[string]any, key string, value any) {
+	if value = minifyValue(value); value == nil {
+		delete(m, key)
+	} else {
+		m[key] = value
+	}
+}
+
+// minifyValue returns value without null and empty nested values, or nil if value itself is null or empty.
+// Empty strings are kept, as an empty environment variable differs from an unset one.
+func minifyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, e := range v {
+			minifyEntry(v, k, e)
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []any:
+		var items []any
+		for _, e := range v {
+			if e = minifyValue(e); e != nil {
+				items = append(items, e)
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestMinifyModel(t *testing.T) {
+	model := map[string]any{
+		"name": "test",
+		"services": map[string]any{
+			"web": map[string]any{
+				"image":       "nginx",
+				"command":     nil,
+				"labels":      map[string]any{},
+				"environment": map[string]any{"EMPTY": "", "UNSET": nil},
+				"networks":    map[string]any{"front": nil, "back": map[string]any{"aliases": []any{}}},
+				"ports":       []any{},
+			},
+		},
+		"volumes": map[string]any{"data": map[string]any{}},
+		"configs": nil,
+	}
+	minifyModel(model)
+	assert.DeepEqual(t, model, map[string]any{
+		"name": "test",
+		"services": map[string]any{
+			"web": map[string]any{
+				"image":       "nginx",
+				"environment": map[string]any{"EMPTY": ""},
+				"networks":    map[string]any{"front": nil, "back": nil},
+			},
+		},
+		"volumes": map[string]any{"data": map[string]any{}},
+	})
+}
+
+func TestMarshalProjectSortKeys(t *testing.T) {
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"web": {Name: "web", Image: "nginx", Scale: intPtr(2)},
+		},
+	}
+	content, err := MarshalProject(project, api.ConfigOptions{Format: "yaml", SortKeys: true})
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), `name: test
+services:
+  web:
+    image: nginx
+    scale: 2
+`)
+
+	_, err = MarshalProject(project, api.ConfigOptions{Format: "toml", Minify: true})
+	assert.ErrorContains(t, err, `unsupported format "toml"`)
+}
@@ -132,15 +132,27 @@ func isService(services ...string) containerPredicate {
 func isOrphaned(project *types.Project) containerPredicate {
 	services := append(project.ServiceNames(), project.DisabledServiceNames()...)
 	return func(c container.Summary) bool {
-		// One-off container
-		v, ok := c.Labels[api.OneoffLabel]
-		if ok && v == "True" {
-			return c.State == container.StateExited || c.State == container.StateDead
+		return orphanReason(services, c) != ""
+	}
+}
+
+// orphanReason tells why a container is an orphan given the services declared by project, or returns
+// an empty string if it's not
+func orphanReason(services []string, c container.Summary) string {
+	// One-off container. Init job containers are kept once exited, to record job completed
+	v, ok := c.Labels[api.OneoffLabel]
+	if ok && v == "True" && c.Labels[api.InitJobLabel] == "" {
+		if c.State == container.StateExited || c.State == container.StateDead {
+			return "one-off container has exited"
 		}
-		// Service that is not defined in the compose model
-		service := c.Labels[api.ServiceLabel]
-		return !slices.Contains(services, service)
+		return ""
+	}
+	// Service that is not defined in the compose model
+	service := c.Labels[api.ServiceLabel]
+	if !slices.Contains(services, service) {
+		return fmt.Sprintf("service %q is not declared by project", service)
 	}
+	return ""
 }
 
 func isNotOneOff(c container.Summary) bool {
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"regexp"
 	"slices"
 	"sort"
 	"strconv"
@@ -56,11 +57,16 @@ const (
 // Cross services dependencies are managed by creating services in expected order and updating `service:xx` reference
 // when a service has converged, so dependent ones can be managed with resolved containers references.
 type convergence struct {
-	compose    *composeService
-	services   map[string]Containers
-	networks   map[string]string
-	volumes    map[string]string
-	stateMutex sync.Mutex
+	compose   *composeService
+	services  map[string]Containers
+	networks  map[string]string
+	volumes   map[string]string
+	recreated map[string]bool
+	// scaleDown selects replicas removed when services are scaled down
+	scaleDown api.ScaleDownOptions
+	// preserveAnonymousVolumes re-attaches all anonymous volumes of recreated containers
+	preserveAnonymousVolumes bool
+	stateMutex               sync.Mutex
 }
 
 func (c *convergence) getObservedState(serviceName string) Containers {
@@ -75,6 +81,18 @@ func (c *convergence) setObservedState(serviceName string, containers Containers
 	c.services[serviceName] = containers
 }
 
+func (c *convergence) setRecreated(serviceName string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.recreated[serviceName] = true
+}
+
+func (c *convergence) isRecreated(serviceName string) bool {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.recreated[serviceName]
+}
+
 func newConvergence(services []string, state Containers, networks map[string]string, volumes map[string]string, s *composeService) *convergence {
 	observedState := map[string]Containers{}
 	for _, s := range services {
@@ -85,10 +103,11 @@ func newConvergence(services []string, state Containers, networks map[string]str
 		observedState[service] = append(observedState[service], c)
 	}
 	return &convergence{
-		compose:  s,
-		services: observedState,
-		networks: networks,
-		volumes:  volumes,
+		compose:   s,
+		services:  observedState,
+		networks:  networks,
+		volumes:   volumes,
+		recreated: map[string]bool{},
 	}
 }
 
@@ -104,7 +123,19 @@ func (c *convergence) apply(ctx context.Context, project *types.Project, options
 			if slices.Contains(options.Services, name) {
 				strategy = options.Recreate
 			}
-			return c.ensureService(ctx, project, service, strategy, options.Inherit, options.Timeout)
+			if override, ok := options.RecreateServices[name]; ok {
+				strategy = override
+			}
+			if primary := service.CustomLabels[api.SidecarOfLabel]; primary != "" && c.isRecreated(primary) {
+				// sidecar is recreated together with its primary service
+				strategy = api.RecreateForce
+			}
+			notifyService(ctx, name, api.ServiceCreating, nil)
+			err := c.ensureService(ctx, project, service, strategy, options.Inherit, options.Timeout)
+			if err != nil {
+				notifyService(ctx, name, api.ServiceFailed, err)
+			}
+			return err
 		})(ctx)
 	})
 }
@@ -113,6 +144,10 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 	if service.Provider != nil {
 		return c.compose.runPlugin(ctx, project, service, "up")
 	}
+	if isExternalService(service) {
+		// container is managed outside compose
+		return nil
+	}
 	expected, err := getScale(service)
 	if err != nil {
 		return err
@@ -128,7 +163,19 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		return err
 	}
 
+	stable, err := getStableReplicas(service)
+	if err != nil {
+		return err
+	}
+
 	sort.Slice(containers, func(i, j int) bool {
+		if stable != nil {
+			// stable replicas are removed by highest number as we scale down, so numbers don't get gaps
+			ni, _ := strconv.Atoi(containers[i].Labels[api.ContainerNumberLabel])
+			nj, _ := strconv.Atoi(containers[j].Labels[api.ContainerNumberLabel])
+			return ni > nj
+		}
+
 		// select obsolete containers first, so they get removed as we scale down
 		if obsolete, _ := c.mustRecreate(service, containers[i], recreate); obsolete {
 			// i is obsolete, so must be first in the list
@@ -151,11 +198,18 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 	})
 
 	slices.Reverse(containers)
+	reasons, err := selectScaleDownVictims(service.Name, containers, expected, c.scaleDown)
+	if err != nil {
+		return err
+	}
 	for i, ctr := range containers {
 		if i >= expected {
 			// Scale Down
-			// As we sorted containers, obsolete ones and/or highest number will be removed
+			// As we sorted containers, obsolete ones and/or highest number will be removed, unless a policy is set
 			ctr := ctr
+			if reason := reasons[ctr.ID]; reason != "" {
+				c.compose.events.On(newEvent(getContainerProgressName(ctr), api.Working, "Scaling down", reason))
+			}
 			traceOpts := append(tracing.ServiceOptions(service), tracing.ContainerOptions(ctr)...)
 			eg.Go(tracing.SpanWrapFuncForErrGroup(ctx, "service/scale/down", traceOpts, func(ctx context.Context) error {
 				return c.compose.stopAndRemoveContainer(ctx, ctr, &service, timeout, false)
@@ -168,6 +222,7 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 			return err
 		}
 		if mustRecreate {
+			c.setRecreated(service.Name)
 			err := c.stopDependentContainers(ctx, project, service)
 			if err != nil {
 				return err
@@ -175,7 +230,7 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 
 			i, ctr := i, ctr
 			eg.Go(tracing.SpanWrapFuncForErrGroup(ctx, "container/recreate", tracing.ContainerOptions(ctr), func(ctx context.Context) error {
-				recreated, err := c.compose.recreateContainer(ctx, project, service, ctr, inherit, timeout)
+				recreated, err := c.compose.recreateContainer(ctx, project, service, ctr, inherit, c.preserveAnonymousVolumes, timeout)
 				updated[i] = recreated
 				return err
 			}))
@@ -199,10 +254,17 @@ func (c *convergence) ensureService(ctx context.Context, project *types.Project,
 		updated[i] = ctr
 	}
 
+	var numbers []int
+	if stable != nil {
+		numbers = stableReplicaNumbers(containers, expected-actual)
+	}
 	next := nextContainerNumber(containers)
 	for i := 0; i < expected-actual; i++ {
 		// Scale UP
 		number := next + i
+		if stable != nil {
+			number = numbers[i]
+		}
 		name := getContainerName(project.Name, service, number)
 		eventOpts := tracing.SpanOptions{trace.WithAttributes(attribute.String("container.name", name))}
 		eg.Go(tracing.EventWrapFuncForErrGroup(ctx, "service/scale/up", eventOpts, func(ctx context.Context) error {
@@ -272,6 +334,8 @@ func (c *convergence) resolveServiceReferences(service *types.ServiceConfig) err
 	if err != nil {
 		return err
 	}
+
+	c.resolveExtraHosts(service)
 	return nil
 }
 
@@ -340,6 +404,9 @@ func (c *convergence) mustRecreate(expected types.ServiceConfig, actual containe
 	configChanged := actual.Labels[api.ConfigHashLabel] != configHash
 	imageUpdated := actual.Labels[api.ImageDigestLabel] != expected.CustomLabels[api.ImageDigestLabel]
 	if configChanged || imageUpdated {
+		if diff, err := configDiff(expected, actual); err == nil {
+			logrus.Debugf("container %s must be recreated, changed: %s", getCanonicalContainerName(actual), strings.Join(diff, ", "))
+		}
 		return true, nil
 	}
 
@@ -472,6 +539,8 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 		eg.Go(func() error {
 			ticker := time.NewTicker(500 * time.Millisecond)
 			defer ticker.Stop()
+			// states tracks the last reported state of dependency containers
+			states := map[string]string{}
 			for {
 				select {
 				case <-ticker.C:
@@ -488,10 +557,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 							logrus.Warnf("optional dependency %q is not running or is unhealthy: %s", dep, err.Error())
 							return nil
 						}
-						return err
+						return api.WithErrCode(err, api.ErrCodeDependencyFailed)
 					}
 					if isHealthy {
 						s.events.On(containerEvents(waitingFor, healthy)...)
+						notifyService(ctx, dep, api.ServiceHealthy, nil)
 						return nil
 					}
 				case types.ServiceConditionHealthy:
@@ -506,18 +576,20 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 						s.events.On(containerEvents(waitingFor, func(s string) api.Resource {
 							return errorEventf(s, "dependency %s failed to start", dep)
 						})...)
-						return fmt.Errorf("dependency failed to start: %w", err)
+						notifyService(ctx, dep, api.ServiceFailed, err)
+						return api.WithErrCode(fmt.Errorf("dependency failed to start: %w", err), api.ErrCodeDependencyFailed)
 					}
 					if isHealthy {
 						s.events.On(containerEvents(waitingFor, healthy)...)
+						notifyService(ctx, dep, api.ServiceHealthy, nil)
 						return nil
 					}
 				case types.ServiceConditionCompletedSuccessfully:
-					isExited, code, err := s.isServiceCompleted(ctx, waitingFor)
+					exitedCtr, code, err := s.isServiceCompleted(ctx, waitingFor, states)
 					if err != nil {
 						return err
 					}
-					if isExited {
+					if exitedCtr != nil {
 						if code == 0 {
 							s.events.On(containerEvents(waitingFor, exited)...)
 							return nil
@@ -536,7 +608,11 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 						s.events.On(containerEvents(waitingFor, func(s string) api.Resource {
 							return errorEventf(s, "service %s", messageSuffix)
 						})...)
-						return errors.New(msg)
+						if lines := s.lastLogLines(ctx, exitedCtr, completedLogLines); len(lines) > 0 {
+							s.events.On(logLinesEvents(getContainerProgressName(*exitedCtr), lines)...)
+							msg += "\n" + strings.Join(lines, "\n")
+						}
+						return api.WithErrCode(errors.New(msg), api.ErrCodeDependencyFailed)
 					}
 				default:
 					logrus.Warnf("unsupported depends_on condition: %s", config.Condition)
@@ -547,7 +623,7 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 	}
 	err := eg.Wait()
 	if errors.Is(err, context.DeadlineExceeded) {
-		return fmt.Errorf("timeout waiting for dependencies")
+		return api.WithErrCode(errors.New("timeout waiting for dependencies"), api.ErrCodeHealthTimeout)
 	}
 	return err
 }
@@ -615,7 +691,7 @@ func (s *composeService) createContainer(ctx context.Context, project *types.Pro
 }
 
 func (s *composeService) recreateContainer(ctx context.Context, project *types.Project, service types.ServiceConfig,
-	replaced container.Summary, inherit bool, timeout *time.Duration,
+	replaced container.Summary, inherit bool, preserve bool, timeout *time.Duration,
 ) (created container.Summary, err error) {
 	eventName := getContainerProgressName(replaced)
 	s.events.On(newEvent(eventName, api.Working, "Recreate"))
@@ -634,8 +710,14 @@ func (s *composeService) recreateContainer(ctx context.Context, project *types.P
 		return created, err
 	}
 
-	var inherited *container.Summary
-	if inherit {
+	var (
+		inherited *container.Summary
+		preserved []types.ServiceVolumeConfig
+	)
+	switch {
+	case preserve:
+		preserved = preservedAnonymousVolumes(service, replaced)
+	case inherit:
 		inherited = &replaced
 	}
 
@@ -643,37 +725,50 @@ func (s *composeService) recreateContainer(ctx context.Context, project *types.P
 	if replacedContainerName == "" {
 		replacedContainerName = service.Name + api.Separator + strconv.Itoa(number)
 	}
-	name := getContainerName(project.Name, service, number)
-	tmpName := fmt.Sprintf("%s_%s", replaced.ID[:12], name)
 	opts := createOptions{
 		AutoRemove:        false,
 		AttachStdin:       false,
 		UseNetworkAliases: true,
 		Labels:            mergeLabels(service.Labels, service.CustomLabels).Add(api.ContainerReplaceLabel, replacedContainerName),
+		AnonymousVolumes:  preserved,
 	}
-	created, err = s.createMobyContainer(ctx, project, service, tmpName, number, inherited, opts)
+	created, err = s.replaceContainer(ctx, project, service, replaced, number, inherited, opts, timeout)
 	if err != nil {
 		return created, err
 	}
 
-	timeoutInSecond := utils.DurationSecondToInt(timeout)
-	err = s.apiClient().ContainerStop(ctx, replaced.ID, container.StopOptions{Timeout: timeoutInSecond})
+	if len(preserved) > 0 {
+		s.events.On(newEvent(eventName, api.Done, "Recreated", describePreservedVolumes(preserved)))
+		return created, nil
+	}
+	s.events.On(newEvent(eventName, api.Done, "Recreated"))
+	return created, err
+}
+
+// replaceContainer creates a container for service with a temporary name, then removes replaced container
+// and renames the new one
+func (s *composeService) replaceContainer(ctx context.Context, project *types.Project, service types.ServiceConfig,
+	replaced container.Summary, number int, inherited *container.Summary, opts createOptions, timeout *time.Duration,
+) (container.Summary, error) {
+	name := getContainerName(project.Name, service, number)
+	tmpName := fmt.Sprintf("%s_%s", replaced.ID[:12], name)
+	created, err := s.createMobyContainer(ctx, project, service, tmpName, number, inherited, opts)
 	if err != nil {
 		return created, err
 	}
 
-	err = s.apiClient().ContainerRemove(ctx, replaced.ID, container.RemoveOptions{})
+	timeoutInSecond := utils.DurationSecondToInt(timeout)
+	err = s.apiClient().ContainerStop(ctx, replaced.ID, container.StopOptions{Timeout: timeoutInSecond})
 	if err != nil {
 		return created, err
 	}
 
-	err = s.apiClient().ContainerRename(ctx, tmpName, name)
+	err = s.apiClient().ContainerRemove(ctx, replaced.ID, container.RemoveOptions{})
 	if err != nil {
 		return created, err
 	}
 
-	s.events.On(newEvent(eventName, api.Done, "Recreated"))
-	return created, err
+	return created, s.apiClient().ContainerRename(ctx, tmpName, name)
 }
 
 // force sequential calls to ContainerStart to prevent race condition in engine assigning ports from ranges
@@ -685,12 +780,23 @@ func (s *composeService) startContainer(ctx context.Context, ctr container.Summa
 	defer startMx.Unlock()
 	err := s.apiClient().ContainerStart(ctx, ctr.ID, container.StartOptions{})
 	if err != nil {
-		return err
+		return withStartErrCode(err)
 	}
 	s.events.On(newEvent(getContainerProgressName(ctr), api.Done, "Restarted"))
 	return nil
 }
 
+// portConflictPattern matches engine errors starting a container which published port is already in use
+var portConflictPattern = regexp.MustCompile(`(?i)(port is already allocated|address already in use)`)
+
+// withStartErrCode classifies errors starting a container
+func withStartErrCode(err error) error {
+	if err != nil && portConflictPattern.MatchString(err.Error()) {
+		return api.WithErrCode(err, api.ErrCodePortConflict)
+	}
+	return err
+}
+
 func (s *composeService) createMobyContainer(ctx context.Context, project *types.Project, service types.ServiceConfig,
 	name string, number int, inherit *container.Summary, opts createOptions,
 ) (container.Summary, error) {
@@ -855,19 +961,80 @@ func (s *composeService) isServiceHealthy(ctx context.Context, containers Contai
 	return true, nil
 }
 
-func (s *composeService) isServiceCompleted(ctx context.Context, containers Containers) (bool, int, error) {
+// completedLogLines is the number of log lines reported when a dependency didn't complete successfully
+const completedLogLines = 10
+
+// isServiceCompleted returns the first container which exited, with its exit code. Until then, live state of
+// containers is reported as they change, so user knows what a service is waiting for.
+func (s *composeService) isServiceCompleted(ctx context.Context, containers Containers, states map[string]string) (*container.Summary, int, error) {
 	for _, c := range containers {
 		ctr, err := s.apiClient().ContainerInspect(ctx, c.ID)
 		if err != nil {
-			return false, 0, err
+			return nil, 0, err
+		}
+		if ctr.State == nil {
+			continue
+		}
+		if ctr.State.Status == container.StateExited {
+			return &c, ctr.State.ExitCode, nil
 		}
-		if ctr.State != nil && ctr.State.Status == container.StateExited {
-			return true, ctr.State.ExitCode, nil
+		if states[c.ID] != ctr.State.Status {
+			states[c.ID] = ctr.State.Status
+			s.events.On(newEvent(getContainerProgressName(c), api.Working, api.StatusWaiting, string(ctr.State.Status)))
 		}
 	}
-	return false, 0, nil
+	return nil, 0, nil
+}
+
+// lastLogLines collects the last lines of container logs, ignoring errors as logs are only informative
+func (s *composeService) lastLogLines(ctx context.Context, ctr *container.Summary, n int) []string {
+	inspect, err := s.apiClient().ContainerInspect(ctx, ctr.ID)
+	if err != nil {
+		return nil
+	}
+	r, err := s.openContainerLogs(ctx, ctr.ID, api.LogOptions{Tail: strconv.Itoa(n)})
+	if err != nil {
+		logrus.Debugf("failed to collect logs for %s: %v", getCanonicalContainerName(*ctr), err)
+		return nil
+	}
+	defer r.Close() //nolint:errcheck
+	var lines []string
+	consumer := &linesConsumer{add: func(line string) {
+		lines = append(lines, line)
+	}}
+	_ = copyLogs(consumer, "", r, inspect.Config != nil && inspect.Config.Tty)
+	return lines
+}
+
+// logLinesEvents reports log lines as child resources of the container progress
+func logLinesEvents(parent string, lines []string) []api.Resource {
+	events := make([]api.Resource, 0, len(lines))
+	for i, line := range lines {
+		events = append(events, api.Resource{
+			ID:       fmt.Sprintf("%s log %d", parent, i+1),
+			ParentID: parent,
+			Status:   api.Error,
+			Details:  line,
+		})
+	}
+	return events
+}
+
+// linesConsumer is an api.LogConsumer collecting log and error lines
+type linesConsumer struct {
+	add func(line string)
+}
+
+func (l *linesConsumer) Log(_, message string) {
+	l.add(message)
+}
+
+func (l *linesConsumer) Err(_, message string) {
+	l.add(message)
 }
 
+func (l *linesConsumer) Status(_, _ string) {}
+
 func (s *composeService) startService(ctx context.Context,
 	project *types.Project, service types.ServiceConfig,
 	containers Containers, listener api.ContainerEventListener,
@@ -877,6 +1044,10 @@ func (s *composeService) startService(ctx context.Context,
 		return nil
 	}
 
+	if isExternalService(service) {
+		return s.checkExternalService(ctx, service)
+	}
+
 	err := s.waitDependencies(ctx, project, service.Name, service.DependsOn, containers, timeout)
 	if err != nil {
 		return err
@@ -889,9 +1060,21 @@ func (s *composeService) startService(ctx context.Context,
 		return fmt.Errorf("service %q has no container to start", service.Name)
 	}
 
-	for _, ctr := range containers.filter(isService(service.Name)) {
-		if ctr.State == container.StateRunning {
-			continue
+	toStart := containers.filter(isService(service.Name), func(c container.Summary) bool {
+		return c.State != container.StateRunning
+	})
+	notifyService(ctx, service.Name, api.ServiceStarting, nil)
+	if len(toStart) > 0 {
+		err = s.runInitContainers(ctx, project, service, listener)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ctr := range toStart {
+		ctr, err = s.refreshExtraHosts(ctx, project, service, ctr)
+		if err != nil {
+			return err
 		}
 
 		err = s.injectSecrets(ctx, project, service, ctr.ID)
@@ -908,7 +1091,7 @@ func (s *composeService) startService(ctx context.Context,
 		s.events.On(startingEvent(eventName))
 		err = s.apiClient().ContainerStart(ctx, ctr.ID, container.StartOptions{})
 		if err != nil {
-			return err
+			return withStartErrCode(err)
 		}
 
 		for _, hook := range service.PostStart {
@@ -920,6 +1103,12 @@ func (s *composeService) startService(ctx context.Context,
 
 		s.events.On(startedEvent(eventName))
 	}
+
+	err = s.runInitJobs(ctx, project, service, containers, listener, timeout)
+	if err != nil {
+		return err
+	}
+	notifyService(ctx, service.Name, api.ServiceStarted, nil)
 	return nil
 }
 
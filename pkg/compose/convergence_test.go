@@ -18,7 +18,9 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
@@ -62,6 +64,15 @@ func intPtr(i int) *int {
 	return &i
 }
 
+func TestWithStartErrCode(t *testing.T) {
+	err := withStartErrCode(errors.New("driver failed programming external connectivity: Bind for 0.0.0.0:80 failed: port is already allocated"))
+	assert.Equal(t, api.ErrorCode(err), api.ErrCodePortConflict)
+
+	err = withStartErrCode(errors.New("no such file or directory"))
+	assert.Equal(t, api.ErrorCode(err), api.ErrCode(""))
+	assert.NilError(t, withStartErrCode(nil))
+}
+
 func TestServiceLinks(t *testing.T) {
 	const dbContainerName = "/" + testProject + "-db-1"
 	const webContainerName = "/" + testProject + "-web-1"
@@ -250,6 +261,48 @@ func TestWaitDependencies(t *testing.T) {
 	})
 }
 
+func TestWaitCompletedDependencyFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	events := &recordingEvents{}
+	tested := &composeService{dockerCli: cli, events: events}
+
+	project := types.Project{Name: strings.ToLower(testProject), Services: types.Services{
+		"migrate": {Name: "migrate", Scale: intPtr(1)},
+	}}
+	dependencies := types.DependsOnConfig{
+		"migrate": {Condition: types.ServiceConditionCompletedSuccessfully, Required: true},
+	}
+	ctr := testContainer("migrate", "123", false)
+
+	gomock.InOrder(
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{Status: container.StateRunning}},
+		}, nil),
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{Status: container.StateExited, ExitCode: 1}},
+		}, nil),
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{ID: "123"},
+			Config:            &container.Config{Tty: true},
+		}, nil),
+	)
+	apiClient.EXPECT().ContainerLogs(gomock.Any(), "123", container.LogsOptions{ShowStdout: true, ShowStderr: true, Tail: "10"}).
+		Return(io.NopCloser(strings.NewReader("applying 0042\nERROR: relation exists\n")), nil)
+
+	err := tested.waitDependencies(t.Context(), &project, "app", dependencies, Containers{ctr}, 0)
+	assert.Error(t, err, "service \"migrate\" didn't complete successfully: exit 1\napplying 0042\nERROR: relation exists")
+
+	name := getContainerProgressName(ctr)
+	assert.DeepEqual(t, events.resources, []api.Resource{
+		waiting(name),
+		newEvent(name, api.Working, api.StatusWaiting, "running"),
+		errorEvent(name, "service \"migrate\" didn't complete successfully: exit 1"),
+		{ID: name + " log 1", ParentID: name, Status: api.Error, Details: "applying 0042"},
+		{ID: name + " log 2", ParentID: name, Status: api.Error, Details: "ERROR: relation exists"},
+	})
+}
+
 func TestCreateMobyContainer(t *testing.T) {
 	t.Run("connects container networks one by one if API <1.44", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
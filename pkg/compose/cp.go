@@ -278,6 +278,11 @@ func (s *composeService) copyFromContainer(ctx context.Context, containerID, src
 	}
 	defer content.Close() //nolint:errcheck
 
+	if s.dryRun {
+		// source exists in container, don't write to local filesystem
+		return nil
+	}
+
 	if dstPath == "-" {
 		_, err = io.Copy(s.stdout(), content)
 		return err
@@ -50,6 +50,8 @@ type createOptions struct {
 	AttachStdin       bool
 	UseNetworkAliases bool
 	Labels            types.Labels
+	// AnonymousVolumes are the anonymous volumes of a replaced container, to be re-attached
+	AnonymousVolumes []types.ServiceVolumeConfig
 }
 
 type createConfigs struct {
@@ -60,8 +62,11 @@ type createConfigs struct {
 }
 
 func (s *composeService) Create(ctx context.Context, project *types.Project, createOpts api.CreateOptions) error {
+	ctx = s.withOperationServices(ctx, project, createOpts.Services)
 	return Run(ctx, func(ctx context.Context) error {
-		return s.create(ctx, project, createOpts)
+		return withDeadline(ctx, createOpts.Deadline, "create", func(ctx context.Context) error {
+			return s.create(ctx, project, createOpts)
+		})
 	}, "create", s.events)
 }
 
@@ -75,6 +80,34 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
+	// project might not have been loaded by LoadProject
+	err = validateExternalServices(project)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkKernelSettings(ctx, project, options.Services)
+	if err != nil {
+		return err
+	}
+
+	if !options.SkipBindChecks {
+		err = s.checkBindMounts(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.ensureCertificates(project)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkIngressPort(ctx, project)
+	if err != nil {
+		return err
+	}
+
 	err = s.ensureImagesExists(ctx, project, options.Build, options.QuietPull)
 	if err != nil {
 		return err
@@ -92,11 +125,22 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
+	// scale may have been updated since project was loaded
+	_, err = applyStableReplicas(project)
+	if err != nil {
+		return err
+	}
+
 	volumes, err := s.ensureProjectVolumes(ctx, project)
 	if err != nil {
 		return err
 	}
 
+	err = s.ensureOwnership(ctx, project)
+	if err != nil {
+		return err
+	}
+
 	var observedState Containers
 	observedState, err = s.getContainers(ctx, project.Name, oneOffInclude, true)
 	if err != nil {
@@ -123,7 +167,10 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
-	return newConvergence(options.Services, observedState, networks, volumes, s).apply(ctx, project, options)
+	c := newConvergence(options.Services, observedState, networks, volumes, s)
+	c.scaleDown = options.ScaleDown
+	c.preserveAnonymousVolumes = options.PreserveAnonymousVolumes
+	return c.apply(ctx, project, options)
 }
 
 func prepareNetworks(project *types.Project) {
@@ -177,6 +224,12 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 	if err != nil {
 		return createConfigs{}, err
 	}
+	// applied after config hash has been computed, so all replicas share the service hash
+	service, err = stableReplicaService(service, number)
+	if err != nil {
+		return createConfigs{}, err
+	}
+	service = withPreservedVolumes(service, opts.AnonymousVolumes)
 
 	var runCmd, entrypoint []string
 	if service.Command != nil {
@@ -323,6 +376,9 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 		hostConfig.MaskedPaths = []string{}
 		hostConfig.ReadonlyPaths = []string{}
 	}
+	if err := s.adaptToRuntimeOS(ctx, service, &hostConfig); err != nil {
+		return createConfigs{}, err
+	}
 
 	cfgs := createConfigs{
 		Container: &containerConfig,
@@ -485,6 +541,12 @@ func (s *composeService) prepareLabels(labels types.Labels, service types.Servic
 		return nil, err
 	}
 	labels[api.ConfigHashLabel] = hash
+	components, err := ServiceHashComponents(service)
+	if err != nil {
+		return nil, err
+	}
+	labels[api.ConfigHashComponentsLabel] = formatHashComponents(components)
+	labels[api.LabelsSchemaLabel] = api.LabelsSchemaVersion
 
 	if number > 0 {
 		// One-off containers are not indexed
@@ -493,7 +555,7 @@ func (s *composeService) prepareLabels(labels types.Labels, service types.Servic
 
 	var dependencies []string
 	for s, d := range service.DependsOn {
-		dependencies = append(dependencies, fmt.Sprintf("%s:%s:%t", s, d.Condition, d.Restart))
+		dependencies = append(dependencies, fmt.Sprintf("%s:%s:%t:%t", s, d.Condition, d.Restart, d.Required))
 	}
 	labels[api.DependenciesLabel] = strings.Join(dependencies, ",")
 	return labels, nil
@@ -1151,6 +1213,11 @@ func isWindowsAbs(p string) bool {
 }
 
 func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.Mount, error) {
+	if volume.Type == types.VolumeTypeBind && isNamedPipe(volume.Source) {
+		// short syntax declares named pipes as bind mounts
+		volume.Type = types.VolumeTypeNamedPipe
+		volume.Bind = nil
+	}
 	source := volume.Source
 	switch volume.Type {
 	case types.VolumeTypeBind:
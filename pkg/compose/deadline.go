@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// withDeadline runs fn with a context canceled once deadline is reached, if set. Errors caused by the deadline
+// are reported as api.ErrTimedOut, so they can't be confused with a user cancellation or engine failure.
+func withDeadline(ctx context.Context, deadline time.Time, operation string, fn func(context.Context) error) error {
+	if deadline.IsZero() {
+		return fn(ctx)
+	}
+	cause := fmt.Errorf("%w during %s", api.ErrTimedOut, operation)
+	ctx, cancel := context.WithDeadlineCause(ctx, deadline, cause)
+	defer cancel()
+	err := fn(ctx)
+	if err != nil && context.Cause(ctx) == cause { //nolint:errorlint
+		return cause
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestWithDeadline(t *testing.T) {
+	t.Run("no deadline", func(t *testing.T) {
+		err := withDeadline(t.Context(), time.Time{}, "pull", func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.Check(t, !ok)
+			return nil
+		})
+		assert.NilError(t, err)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		err := withDeadline(t.Context(), time.Now().Add(10*time.Millisecond), "pull", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.Check(t, api.IsTimedOutError(err))
+		assert.Error(t, err, "operation timed out during pull")
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		err := withDeadline(t.Context(), time.Now().Add(time.Minute), "pull", func(ctx context.Context) error {
+			return errors.New("pull access denied")
+		})
+		assert.Error(t, err, "pull access denied")
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		err := withDeadline(ctx, time.Now().Add(time.Minute), "pull", func(ctx context.Context) error {
+			return ctx.Err()
+		})
+		assert.Check(t, errors.Is(err, context.Canceled))
+		assert.Check(t, !api.IsTimedOutError(err))
+	})
+}
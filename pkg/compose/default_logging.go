@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// DefaultLoggingExtension is the project extension declaring the logging configuration of services. It applies to
+// services which don't declare one, or use the same logging driver, service options taking precedence:
+//
+//	x-default-logging:
+//	  driver: json-file
+//	  options:
+//	    max-size: 10m
+const DefaultLoggingExtension = "x-default-logging"
+
+type defaultLoggingConfig struct {
+	Driver  string         `mapstructure:"driver"`
+	Options map[string]any `mapstructure:"options"`
+}
+
+// applyDefaultLogging sets logging configuration declared by DefaultLoggingExtension on project services
+func applyDefaultLogging(project *types.Project) (*types.Project, error) {
+	var config defaultLoggingConfig
+	ok, err := project.Extensions.Get(DefaultLoggingExtension, &config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", DefaultLoggingExtension, err)
+	}
+	if !ok {
+		return project, nil
+	}
+	defaults := types.LoggingConfig{
+		Driver:  config.Driver,
+		Options: types.Options{},
+	}
+	for k, v := range config.Options {
+		switch v.(type) {
+		case string, int, bool, float64:
+			defaults.Options[k] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("invalid %s option %q: expected a scalar value, got %T", DefaultLoggingExtension, k, v)
+		}
+	}
+
+	for name, service := range project.Services {
+		service.Logging = mergeLogging(defaults, service.Logging)
+		project.Services[name] = service
+	}
+	return project, nil
+}
+
+// mergeLogging applies default logging configuration to a service one. Options of another driver don't apply.
+func mergeLogging(defaults types.LoggingConfig, logging *types.LoggingConfig) *types.LoggingConfig {
+	merged := &types.LoggingConfig{
+		Driver:  defaults.Driver,
+		Options: maps.Clone(defaults.Options),
+	}
+	if logging == nil {
+		return merged
+	}
+	if logging.Driver != "" && logging.Driver != defaults.Driver {
+		return logging
+	}
+	maps.Copy(merged.Options, logging.Options)
+	merged.Extensions = logging.Extensions
+	return merged
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDefaultLogging(t *testing.T) {
+	composeFile := filepath.Join(t.TempDir(), "compose.yaml")
+	assert.NilError(t, os.WriteFile(composeFile, []byte(`
+name: test
+x-default-logging:
+  driver: json-file
+  options:
+    max-size: 10m
+    max-file: 3
+services:
+  inherit:
+    image: alpine
+  merge:
+    image: alpine
+    logging:
+      options:
+        max-size: 1g
+  other:
+    image: alpine
+    logging:
+      driver: syslog
+      options:
+        syslog-address: udp://logs:514
+`), 0o600))
+
+	service, err := NewComposeService(nil)
+	assert.NilError(t, err)
+	project, err := service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, project.Services["inherit"].Logging, &types.LoggingConfig{
+		Driver:  "json-file",
+		Options: types.Options{"max-size": "10m", "max-file": "3"},
+	})
+	assert.DeepEqual(t, project.Services["merge"].Logging, &types.LoggingConfig{
+		Driver:  "json-file",
+		Options: types.Options{"max-size": "1g", "max-file": "3"},
+	})
+	assert.DeepEqual(t, project.Services["other"].Logging, &types.LoggingConfig{
+		Driver:  "syslog",
+		Options: types.Options{"syslog-address": "udp://logs:514"},
+	})
+}
+
+func TestDefaultLoggingInvalid(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{"web": {Name: "web"}},
+		Extensions: types.Extensions{
+			DefaultLoggingExtension: map[string]any{
+				"options": map[string]any{"max-size": []any{"10m"}},
+			},
+		},
+	}
+	_, err := applyDefaultLogging(project)
+	assert.Error(t, err, `invalid x-default-logging option "max-size": expected a scalar value, got []interface {}`)
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Describe(ctx context.Context, projectName string) (*types.Project, error) {
+	projectName = strings.ToLower(projectName)
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, true)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.describe(ctx, containers, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if len(project.Services) == 0 && len(project.Networks) == 0 && len(project.Volumes) == 0 {
+		return nil, fmt.Errorf("no resource found for project %q: %w", projectName, api.ErrNotFound)
+	}
+	describeServiceResources(project, containers)
+	return project, nil
+}
+
+// describe reconstructs project model from labels of containers, networks and volumes
+func (s *composeService) describe(ctx context.Context, containers Containers, projectName string) (*types.Project, error) {
+	project, err := s.projectFromName(containers.filter(isNotOneOff), projectName)
+	if err != nil && !api.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	volumes, err := s.actualVolumes(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	project.Volumes = volumes
+
+	networks, err := s.actualNetworks(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	project.Networks = networks
+	return project, nil
+}
+
+// describeServiceResources sets networks and mounts of services, as attached to their first container
+func describeServiceResources(project *types.Project, containers Containers) {
+	networkKeys := map[string]string{}
+	for key, nw := range project.Networks {
+		networkKeys[nw.Name] = key
+	}
+	volumeKeys := map[string]string{}
+	for key, vol := range project.Volumes {
+		volumeKeys[vol.Name] = key
+	}
+
+	described := map[string]bool{}
+	for _, c := range containers.filter(isNotOneOff).sorted() {
+		name := c.Labels[api.ServiceLabel]
+		service, ok := project.Services[name]
+		if !ok || described[name] {
+			continue
+		}
+		described[name] = true
+
+		if c.NetworkSettings != nil {
+			for nw := range c.NetworkSettings.Networks {
+				key, ok := networkKeys[nw]
+				if !ok {
+					continue
+				}
+				if service.Networks == nil {
+					service.Networks = map[string]*types.ServiceNetworkConfig{}
+				}
+				service.Networks[key] = nil
+			}
+		}
+		for _, m := range c.Mounts {
+			switch m.Type {
+			case mount.TypeVolume:
+				source := volumeKeys[m.Name]
+				if source == "" {
+					// anonymous volume, or not managed by this project
+					continue
+				}
+				service.Volumes = append(service.Volumes, types.ServiceVolumeConfig{
+					Type:     types.VolumeTypeVolume,
+					Source:   source,
+					Target:   m.Destination,
+					ReadOnly: !m.RW,
+				})
+			case mount.TypeBind:
+				service.Volumes = append(service.Volumes, types.ServiceVolumeConfig{
+					Type:     types.VolumeTypeBind,
+					Source:   m.Source,
+					Target:   m.Destination,
+					ReadOnly: !m.RW,
+				})
+			}
+		}
+		slices.SortFunc(service.Volumes, func(a, b types.ServiceVolumeConfig) int {
+			return strings.Compare(a.Target, b.Target)
+		})
+		project.Services[name] = service
+	}
+}
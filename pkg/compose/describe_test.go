@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDescribeServiceResources(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"web": {Name: "web"},
+		},
+		Networks: types.Networks{
+			"front": {Name: "myproject_front"},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "myproject_data"},
+		},
+	}
+	containers := Containers{
+		{
+			Names:  []string{"/myproject-web-1"},
+			Labels: map[string]string{api.ServiceLabel: "web", api.OneoffLabel: "False"},
+			NetworkSettings: &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{
+				"myproject_front": {},
+				"bridge":          {},
+			}},
+			Mounts: []container.MountPoint{
+				{Type: mount.TypeVolume, Name: "myproject_data", Destination: "/data", RW: true},
+				{Type: mount.TypeVolume, Name: "0123456789abcdef", Destination: "/cache", RW: true},
+				{Type: mount.TypeBind, Source: "/srv/config", Destination: "/config"},
+			},
+		},
+	}
+
+	describeServiceResources(project, containers)
+	web := project.Services["web"]
+	assert.DeepEqual(t, web.Networks, map[string]*types.ServiceNetworkConfig{"front": nil})
+	assert.DeepEqual(t, web.Volumes, []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeBind, Source: "/srv/config", Target: "/config", ReadOnly: true},
+		{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"},
+	})
+}
@@ -0,0 +1,282 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// minimumAPIVersion is the oldest engine API version all compose features rely on
+	minimumAPIVersion = "1.44"
+	// minimumBuildxVersion is required to build with bake
+	minimumBuildxVersion = "0.17.0"
+)
+
+func (s *composeService) Doctor(ctx context.Context, project *types.Project) (api.DoctorReport, error) {
+	report := api.DoctorReport{
+		Checks: []api.DoctorCheck{s.checkContext()},
+	}
+	report.Checks = append(report.Checks, credentialsChecks(s.configFile(), exec.LookPath)...)
+
+	version, err := s.apiClient().ServerVersion(ctx)
+	if err != nil {
+		report.Checks = append(report.Checks, api.DoctorCheck{
+			Name:    "daemon",
+			Status:  api.DoctorError,
+			Message: err.Error(),
+			Fix: fmt.Sprintf("Make sure Docker Engine is running and reachable at %s, or select another context with `docker context use`",
+				s.dockerCli.DockerEndpoint().Host),
+		})
+		// other checks require a running engine
+		return report, nil
+	}
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Checks = append(report.Checks, engineCheck(version.Version, version.APIVersion, info))
+	report.Checks = append(report.Checks, s.checkBuildKit())
+	if check, ok := s.checkFileSharing(ctx, project); ok {
+		report.Checks = append(report.Checks, check)
+	}
+	if isRootless(info) {
+		report.Checks = append(report.Checks, rootlessChecks(project, info)...)
+	}
+	if project != nil {
+		report.Checks = append(report.Checks, projectChecks(project, s.isLocalEngine())...)
+	}
+	return report, nil
+}
+
+func (s *composeService) checkContext() api.DoctorCheck {
+	current := s.getContextInfo().CurrentContext()
+	check := api.DoctorCheck{
+		Name:    "context",
+		Status:  api.DoctorOK,
+		Message: fmt.Sprintf("using context %q (%s)", current, s.dockerCli.DockerEndpoint().Host),
+	}
+	if configured := s.configFile().CurrentContext; os.Getenv("DOCKER_HOST") != "" && configured != "" && configured != current {
+		check.Status = api.DoctorWarning
+		check.Message = fmt.Sprintf("DOCKER_HOST overrides context %q selected by `docker context use`", configured)
+		check.Fix = "Unset DOCKER_HOST to use the selected context"
+	}
+	return check
+}
+
+// credentialsChecks verifies configured credential helpers are installed, and credentials are not stored in plain text
+func credentialsChecks(config *configfile.ConfigFile, lookPath func(string) (string, error)) []api.DoctorCheck {
+	helpers := map[string]bool{}
+	if config.CredentialsStore != "" {
+		helpers[config.CredentialsStore] = true
+	}
+	for _, helper := range config.CredentialHelpers {
+		helpers[helper] = true
+	}
+
+	var checks []api.DoctorCheck
+	for _, helper := range slices.Sorted(maps.Keys(helpers)) {
+		binary := "docker-credential-" + helper
+		if _, err := lookPath(binary); err != nil {
+			checks = append(checks, api.DoctorCheck{
+				Name:    "credentials",
+				Status:  api.DoctorError,
+				Message: fmt.Sprintf("credential helper %s not found in PATH", binary),
+				Fix:     fmt.Sprintf("Install %s, or remove it from `credsStore` and `credHelpers` in %s", binary, config.Filename),
+			})
+			continue
+		}
+		checks = append(checks, api.DoctorCheck{
+			Name:    "credentials",
+			Status:  api.DoctorOK,
+			Message: fmt.Sprintf("credential helper %s is installed", binary),
+		})
+	}
+
+	if config.CredentialsStore == "" {
+		for _, registry := range slices.Sorted(maps.Keys(config.AuthConfigs)) {
+			auth := config.AuthConfigs[registry]
+			if auth.Auth == "" && auth.Password == "" && auth.IdentityToken == "" {
+				continue
+			}
+			checks = append(checks, api.DoctorCheck{
+				Name:    "credentials",
+				Status:  api.DoctorWarning,
+				Message: fmt.Sprintf("credentials for %s are stored unencrypted in %s", registry, config.Filename),
+				Fix:     "Configure a credentials store, see https://docs.docker.com/reference/cli/docker/login/#credential-stores",
+			})
+		}
+	}
+	return checks
+}
+
+func engineCheck(version string, apiVersion string, info system.Info) api.DoctorCheck {
+	check := api.DoctorCheck{
+		Name:    "engine",
+		Status:  api.DoctorOK,
+		Message: fmt.Sprintf("Docker Engine %s (API %s) on %s/%s", version, apiVersion, info.OSType, info.Architecture),
+	}
+	if versions.LessThan(apiVersion, minimumAPIVersion) {
+		check.Status = api.DoctorWarning
+		check.Message += fmt.Sprintf(", some features require API %s or later", minimumAPIVersion)
+		check.Fix = "Upgrade Docker Engine to version 25.0 or later"
+	}
+	return check
+}
+
+func (s *composeService) checkBuildKit() api.DoctorCheck {
+	enabled, err := s.getContextInfo().BuildKitEnabled()
+	if err != nil {
+		return api.DoctorCheck{Name: "buildkit", Status: api.DoctorError, Message: err.Error()}
+	}
+	if !enabled {
+		return api.DoctorCheck{
+			Name:    "buildkit",
+			Status:  api.DoctorWarning,
+			Message: "BuildKit is disabled, images are built with the legacy builder",
+			Fix:     "Unset DOCKER_BUILDKIT=0",
+		}
+	}
+	buildx, err := manager.GetPlugin("buildx", s.dockerCli, &cobra.Command{})
+	if err != nil {
+		return api.DoctorCheck{
+			Name:    "buildkit",
+			Status:  api.DoctorWarning,
+			Message: fmt.Sprintf("buildx plugin is not available: %s", err),
+			Fix:     "Install docker-buildx, see https://github.com/docker/buildx#installing",
+		}
+	}
+	if versions.LessThan(strings.TrimPrefix(buildx.Version, "v"), minimumBuildxVersion) {
+		return api.DoctorCheck{
+			Name:    "buildkit",
+			Status:  api.DoctorWarning,
+			Message: fmt.Sprintf("buildx %s is too old to build with bake", buildx.Version),
+			Fix:     fmt.Sprintf("Upgrade docker-buildx to %s or later", minimumBuildxVersion),
+		}
+	}
+	return api.DoctorCheck{
+		Name:    "buildkit",
+		Status:  api.DoctorOK,
+		Message: fmt.Sprintf("BuildKit enabled, buildx %s", buildx.Version),
+	}
+}
+
+// checkFileSharing verifies project directory is shared with Docker Desktop VM, only relevant with a local Docker Desktop engine
+func (s *composeService) checkFileSharing(ctx context.Context, project *types.Project) (api.DoctorCheck, bool) {
+	if !s.isLocalEngine() {
+		return api.DoctorCheck{}, false
+	}
+	desktop, err := s.isDesktopIntegrationActive(ctx)
+	if err != nil || !desktop {
+		return api.DoctorCheck{}, false
+	}
+	shared := desktopFileSharingDirectories()
+	if shared == nil {
+		return api.DoctorCheck{
+			Name:    "file sharing",
+			Status:  api.DoctorWarning,
+			Message: "can't read Docker Desktop file sharing settings",
+		}, true
+	}
+	if project != nil && !isSharedPath(project.WorkingDir, shared) {
+		return api.DoctorCheck{
+			Name:    "file sharing",
+			Status:  api.DoctorError,
+			Message: fmt.Sprintf("project directory %s is not shared with Docker Desktop", project.WorkingDir),
+			Fix:     "Add it, or a parent directory, to Settings > Resources > File sharing",
+		}, true
+	}
+	return api.DoctorCheck{
+		Name:    "file sharing",
+		Status:  api.DoctorOK,
+		Message: fmt.Sprintf("shared with Docker Desktop: %s", strings.Join(shared, ", ")),
+	}, true
+}
+
+func rootlessChecks(project *types.Project, info system.Info) []api.DoctorCheck {
+	var issues []string
+	if project != nil {
+		issues = rootlessIssues(project, info)
+	}
+	if len(issues) == 0 {
+		return []api.DoctorCheck{{
+			Name:    "rootless",
+			Status:  api.DoctorOK,
+			Message: "engine runs in rootless mode",
+		}}
+	}
+	var checks []api.DoctorCheck
+	for _, issue := range issues {
+		checks = append(checks, api.DoctorCheck{
+			Name:    "rootless",
+			Status:  api.DoctorWarning,
+			Message: issue,
+		})
+	}
+	return checks
+}
+
+// projectChecks reports common issues in the project model, bind mounts are only checked when engine runs locally
+func projectChecks(project *types.Project, localEngine bool) []api.DoctorCheck {
+	checks := []api.DoctorCheck{}
+	for _, issue := range diagnoseNetworks(project, project.ServiceNames()) {
+		checks = append(checks, api.DoctorCheck{
+			Name:    "project",
+			Status:  api.DoctorWarning,
+			Message: issue,
+		})
+	}
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, v := range service.Volumes {
+			if !localEngine || v.Type != types.VolumeTypeBind {
+				continue
+			}
+			if _, err := os.Stat(v.Source); os.IsNotExist(err) {
+				checks = append(checks, api.DoctorCheck{
+					Name:    "project",
+					Status:  api.DoctorWarning,
+					Message: fmt.Sprintf("service %q: bind mount source %s doesn't exist", name, v.Source),
+					Fix:     "Create it before running compose, otherwise it is created as a root-owned directory",
+				})
+			}
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, api.DoctorCheck{
+			Name:    "project",
+			Status:  api.DoctorOK,
+			Message: fmt.Sprintf("no issue detected in project %q", project.Name),
+		})
+	}
+	return checks
+}
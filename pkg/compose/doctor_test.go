@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	clitypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/api/types/system"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestCredentialsChecks(t *testing.T) {
+	lookPath := func(file string) (string, error) {
+		if file == "docker-credential-desktop" {
+			return "/usr/local/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	t.Run("helpers", func(t *testing.T) {
+		config := &configfile.ConfigFile{
+			Filename:          "/home/user/.docker/config.json",
+			CredentialsStore:  "desktop",
+			CredentialHelpers: map[string]string{"gcr.io": "gcloud"},
+		}
+		checks := credentialsChecks(config, lookPath)
+		assert.DeepEqual(t, checks, []api.DoctorCheck{
+			{
+				Name:    "credentials",
+				Status:  api.DoctorOK,
+				Message: "credential helper docker-credential-desktop is installed",
+			},
+			{
+				Name:    "credentials",
+				Status:  api.DoctorError,
+				Message: "credential helper docker-credential-gcloud not found in PATH",
+				Fix:     "Install docker-credential-gcloud, or remove it from `credsStore` and `credHelpers` in /home/user/.docker/config.json",
+			},
+		})
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		config := &configfile.ConfigFile{
+			Filename: "/home/user/.docker/config.json",
+			AuthConfigs: map[string]clitypes.AuthConfig{
+				"https://index.docker.io/v1/": {Auth: "dXNlcjpwYXNz"},
+				"registry.example.com":        {},
+			},
+		}
+		checks := credentialsChecks(config, lookPath)
+		assert.Equal(t, len(checks), 1)
+		assert.Equal(t, checks[0].Status, api.DoctorWarning)
+		assert.Equal(t, checks[0].Message, "credentials for https://index.docker.io/v1/ are stored unencrypted in /home/user/.docker/config.json")
+	})
+}
+
+func TestEngineCheck(t *testing.T) {
+	info := system.Info{OSType: "linux", Architecture: "x86_64"}
+
+	check := engineCheck("28.0.1", "1.48", info)
+	assert.Equal(t, check.Status, api.DoctorOK)
+	assert.Equal(t, check.Message, "Docker Engine 28.0.1 (API 1.48) on linux/x86_64")
+
+	check = engineCheck("24.0.9", "1.43", info)
+	assert.Equal(t, check.Status, api.DoctorWarning)
+	assert.Equal(t, check.Fix, "Upgrade Docker Engine to version 25.0 or later")
+}
+
+func TestProjectChecks(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {
+				Name: "app",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: t.TempDir(), Target: "/src"},
+					{Type: types.VolumeTypeBind, Source: "/does/not/exist", Target: "/data"},
+				},
+			},
+		},
+	}
+
+	checks := projectChecks(project, true)
+	assert.Equal(t, len(checks), 1)
+	assert.Equal(t, checks[0].Message, `service "app": bind mount source /does/not/exist doesn't exist`)
+
+	checks = projectChecks(project, false)
+	assert.DeepEqual(t, checks, []api.DoctorCheck{{
+		Name:    "project",
+		Status:  api.DoctorOK,
+		Message: `no issue detected in project "demo"`,
+	}})
+}
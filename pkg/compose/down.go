@@ -37,13 +37,33 @@ import (
 type downOp func() error
 
 func (s *composeService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
-	return Run(ctx, func(ctx context.Context) error {
-		return s.down(ctx, strings.ToLower(projectName), options)
+	ctx = s.withOperationServices(ctx, options.Project, options.Services)
+	ctx = s.withTimingsProject(ctx, strings.ToLower(projectName), options.Project)
+	err := Run(ctx, func(ctx context.Context) error {
+		if err := s.down(ctx, strings.ToLower(projectName), options); err != nil {
+			return err
+		}
+		if s.dryRun {
+			return s.reportDryRunRemovals(ctx)
+		}
+		return nil
 	}, "down", s.events)
+	if err != nil || len(options.Services) > 0 {
+		return err
+	}
+	// remove hosts file entries set by `up --sync-hosts`, if any
+	if err := s.syncHostsFile(strings.ToLower(projectName), nil); err != nil {
+		logrus.Warnf("failed to remove hosts file entries: %v", err)
+	}
+	return nil
 }
 
 func (s *composeService) down(ctx context.Context, projectName string, options api.DownOptions) error { //nolint:gocyclo
 	resourceToRemove := false
+	if !options.RemoveOrphans {
+		// otherwise, all one-off containers are removed
+		s.removeExpiredOneOffs(ctx, projectName)
+	}
 
 	include := oneOffExclude
 	if options.RemoveOrphans {
@@ -53,6 +73,12 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 	if err != nil {
 		return err
 	}
+	if !options.Volumes {
+		// init job containers record completion for volumes, which are kept
+		containers = containers.filter(func(c containerType.Summary) bool {
+			return c.Labels[api.InitJobLabel] == ""
+		})
+	}
 
 	project := options.Project
 	if project == nil {
@@ -62,6 +88,15 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 		}
 	}
 
+	err = s.checkPolicy(ctx, "down", project)
+	if err != nil {
+		return err
+	}
+	if !s.dryRun {
+		// resources recorded by an interrupted up are about to be removed
+		removeJournal(journalPath(projectName))
+	}
+
 	// Check requested services exists in model
 	services, err := checkSelectedServices(options, project)
 	if err != nil {
@@ -100,6 +135,14 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 		}
 	}
 
+	if options.Volumes && options.Snapshot {
+		// containers have been stopped, so snapshots are consistent
+		err = s.snapshotVolumesDown(ctx, project, options.IncludeCache)
+		if err != nil {
+			return err
+		}
+	}
+
 	ops := s.ensureNetworksDown(ctx, project)
 
 	if options.Images != "" {
@@ -111,7 +154,7 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 	}
 
 	if options.Volumes {
-		ops = append(ops, s.ensureVolumesDown(ctx, project)...)
+		ops = append(ops, s.ensureVolumesDown(ctx, project, options.IncludeCache)...)
 	}
 
 	if !resourceToRemove && len(ops) == 0 {
@@ -142,12 +185,15 @@ func checkSelectedServices(options api.DownOptions, project *types.Project) ([]s
 	return services, nil
 }
 
-func (s *composeService) ensureVolumesDown(ctx context.Context, project *types.Project) []downOp {
+func (s *composeService) ensureVolumesDown(ctx context.Context, project *types.Project, includeCache bool) []downOp {
 	var ops []downOp
 	for _, vol := range project.Volumes {
 		if vol.External {
 			continue
 		}
+		if isCacheVolume(vol) && !includeCache {
+			continue
+		}
 		volumeName := vol.Name
 		ops = append(ops, func() error {
 			return s.removeVolume(ctx, volumeName)
@@ -367,12 +413,11 @@ func (s *composeService) stopAndRemoveContainer(ctx context.Context, ctr contain
 }
 
 func (s *composeService) getProjectWithResources(ctx context.Context, containers Containers, projectName string) (*types.Project, error) {
-	containers = containers.filter(isNotOneOff)
-	p, err := s.projectFromName(containers, projectName)
-	if err != nil && !api.IsNotFoundError(err) {
+	p, err := s.describe(ctx, containers, projectName)
+	if err != nil {
 		return nil, err
 	}
-	project, err := p.WithServicesTransform(func(name string, service types.ServiceConfig) (types.ServiceConfig, error) {
+	return p.WithServicesTransform(func(name string, service types.ServiceConfig) (types.ServiceConfig, error) {
 		for k := range service.DependsOn {
 			if dependency, ok := service.DependsOn[k]; ok {
 				dependency.Required = false
@@ -381,21 +426,4 @@ func (s *composeService) getProjectWithResources(ctx context.Context, containers
 		}
 		return service, nil
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	volumes, err := s.actualVolumes(ctx, projectName)
-	if err != nil {
-		return nil, err
-	}
-	project.Volumes = volumes
-
-	networks, err := s.actualNetworks(ctx, projectName)
-	if err != nil {
-		return nil, err
-	}
-	project.Networks = networks
-
-	return project, nil
 }
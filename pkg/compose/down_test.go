@@ -46,6 +46,7 @@ func TestDown(t *testing.T) {
 	tested, err := NewComposeService(cli)
 	assert.NilError(t, err)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil)
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
 		[]container.Summary{
 			testContainer("service1", "123", false),
@@ -102,6 +103,7 @@ func TestDownWithGivenServices(t *testing.T) {
 	tested, err := NewComposeService(cli)
 	assert.NilError(t, err)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil)
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
 		[]container.Summary{
 			testContainer("service1", "123", false),
@@ -153,6 +155,7 @@ func TestDownWithSpecifiedServiceButTheServicesAreNotRunning(t *testing.T) {
 	tested, err := NewComposeService(cli)
 	assert.NilError(t, err)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil)
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
 		[]container.Summary{
 			testContainer("service1", "123", false),
@@ -239,6 +242,7 @@ func TestDownRemoveVolumes(t *testing.T) {
 	tested, err := NewComposeService(cli)
 	assert.NilError(t, err)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil)
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
 		[]container.Summary{testContainer("service1", "123", false)}, nil)
 	api.EXPECT().VolumeList(
@@ -285,6 +289,7 @@ func TestDownRemoveImages(t *testing.T) {
 	tested, err := NewComposeService(cli)
 	assert.NilError(t, err)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil).AnyTimes()
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).
 		Return([]container.Summary{
 			testContainer("service1", "123", false),
@@ -375,6 +380,7 @@ func TestDownRemoveImages_NoLabel(t *testing.T) {
 
 	ctr := testContainer("service1", "123", false)
 
+	api.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return(nil, nil)
 	api.EXPECT().ContainerList(gomock.Any(), projectFilterListOpt(false)).Return(
 		[]container.Summary{ctr}, nil)
 
@@ -418,3 +424,14 @@ func prepareMocks(mockCtrl *gomock.Controller) (*mocks.MockAPIClient, *mocks.Moc
 	cli.EXPECT().Out().Return(streams.NewOut(os.Stdout)).AnyTimes()
 	return api, cli
 }
+
+func oneOffListOpt() container.ListOptions {
+	return container.ListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(strings.ToLower(testProject)),
+			hasConfigHashLabel(),
+			oneOffFilter(true),
+		),
+		All: true,
+	}
+}
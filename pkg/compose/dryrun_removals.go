@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-units"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/dryrun"
+)
+
+// anonymousVolumeLabel is set by engine on anonymous volumes, which are removed with the container they are attached to
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// reportDryRunRemovals details resources a dry-run operation would have removed, with the disk space they use
+func (s *composeService) reportDryRunRemovals(ctx context.Context) error {
+	client, ok := s.apiClient().(*dryrun.DryRunClient)
+	if !ok {
+		return nil
+	}
+	return s.reportRemovals(ctx, client.Removals())
+}
+
+func (s *composeService) reportRemovals(ctx context.Context, removals []dryrun.Removal) error {
+	if len(removals) == 0 {
+		return nil
+	}
+	var (
+		volumes  map[string]*volume.Volume
+		reported = map[string]bool{}
+		total    int64
+	)
+	report := func(id string, size int64) {
+		if reported[id] {
+			return
+		}
+		reported[id] = true
+		var details string
+		if size >= 0 {
+			details = units.HumanSizeWithPrecision(float64(size), 3)
+			total += size
+		}
+		s.events.On(newEvent(id, api.Done, api.StatusWouldRemove, details))
+	}
+	volumeSize := func(name string) (int64, *volume.Volume, error) {
+		if volumes == nil {
+			usage, err := s.apiClient().DiskUsage(ctx, types.DiskUsageOptions{
+				Types: []types.DiskUsageObject{types.VolumeObject},
+			})
+			if err != nil {
+				return 0, nil, err
+			}
+			volumes = map[string]*volume.Volume{}
+			for _, v := range usage.Volumes {
+				volumes[v.Name] = v
+			}
+		}
+		v, ok := volumes[name]
+		if !ok || v.UsageData == nil || v.UsageData.Size < 0 {
+			return -1, v, nil
+		}
+		return v.UsageData.Size, v, nil
+	}
+
+	for _, r := range removals {
+		switch r.Type {
+		case events.ContainerEventType:
+			inspect, _, err := s.apiClient().ContainerInspectWithRaw(ctx, r.ID, true)
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			size := int64(-1)
+			if inspect.SizeRw != nil {
+				size = *inspect.SizeRw
+			}
+			report("Container "+strings.TrimPrefix(inspect.Name, "/"), size)
+			if !r.Volumes {
+				continue
+			}
+			for _, m := range inspect.Mounts {
+				if m.Type != mount.TypeVolume {
+					continue
+				}
+				size, v, err := volumeSize(m.Name)
+				if err != nil {
+					return err
+				}
+				if v == nil {
+					continue
+				}
+				if _, ok := v.Labels[anonymousVolumeLabel]; ok {
+					report("Volume "+m.Name, size)
+				}
+			}
+		case events.ImageEventType:
+			inspect, err := s.apiClient().ImageInspect(ctx, r.ID)
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			report("Image "+r.ID, inspect.Size)
+		case events.NetworkEventType:
+			nw, err := s.apiClient().NetworkInspect(ctx, r.ID, network.InspectOptions{})
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			report("Network "+nw.Name, -1)
+		case events.VolumeEventType:
+			size, _, err := volumeSize(r.ID)
+			if err != nil {
+				return err
+			}
+			report("Volume "+r.ID, size)
+		}
+	}
+	s.events.On(newEvent("Disk space", api.Done, "Would free", units.HumanSizeWithPrecision(float64(total), 3)))
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/dryrun"
+)
+
+func TestReportRemovals(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	recorder := &recordingEvents{}
+	tested := composeService{dockerCli: mockCli, events: recorder}
+
+	sizeRw := int64(1000)
+	mockApi.EXPECT().ContainerInspectWithRaw(gomock.Any(), "c1", true).Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{Name: "/test-web-1", SizeRw: &sizeRw},
+		Mounts: []container.MountPoint{
+			{Type: mount.TypeVolume, Name: "anonymous"},
+			{Type: mount.TypeVolume, Name: "test_data"},
+			{Type: mount.TypeBind, Source: "/src"},
+		},
+	}, nil, nil)
+	mockApi.EXPECT().DiskUsage(gomock.Any(), types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}}).
+		Return(types.DiskUsage{Volumes: []*volume.Volume{
+			{Name: "anonymous", Labels: map[string]string{anonymousVolumeLabel: ""}, UsageData: &volume.UsageData{Size: 10}},
+			{Name: "test_data", UsageData: &volume.UsageData{Size: 20000}},
+		}}, nil)
+	mockApi.EXPECT().NetworkInspect(gomock.Any(), "n1", network.InspectOptions{}).Return(network.Inspect{Name: "test_default"}, nil)
+	mockApi.EXPECT().ImageInspect(gomock.Any(), "test-web").Return(image.InspectResponse{Size: 3000000}, nil)
+
+	err := tested.reportRemovals(t.Context(), []dryrun.Removal{
+		{Type: events.ContainerEventType, ID: "c1", Volumes: true},
+		{Type: events.NetworkEventType, ID: "n1"},
+		{Type: events.VolumeEventType, ID: "test_data"},
+		{Type: events.ImageEventType, ID: "test-web"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recorder.resources, []api.Resource{
+		{ID: "Container test-web-1", Status: api.Done, Text: api.StatusWouldRemove, Details: "1kB"},
+		{ID: "Volume anonymous", Status: api.Done, Text: api.StatusWouldRemove, Details: "10B"},
+		{ID: "Network test_default", Status: api.Done, Text: api.StatusWouldRemove},
+		{ID: "Volume test_data", Status: api.Done, Text: api.StatusWouldRemove, Details: "20kB"},
+		{ID: "Image test-web", Status: api.Done, Text: api.StatusWouldRemove, Details: "3MB"},
+		{ID: "Disk space", Status: api.Done, Text: "Would free", Details: "3.02MB"},
+	})
+}
@@ -0,0 +1,151 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) DiskUsage(ctx context.Context, projectName string, options api.DiskUsageOptions) (api.DiskUsageReport, error) {
+	projectName = strings.ToLower(projectName)
+	// a single request to the engine computes size of all resources, which is expensive
+	usage, err := s.apiClient().DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return api.DiskUsageReport{}, err
+	}
+
+	var containers Containers
+	for _, c := range usage.Containers {
+		if c.Labels[api.ProjectLabel] == projectName {
+			containers = append(containers, *c)
+		}
+	}
+	if len(options.Services) > 0 {
+		containers = containers.filter(isService(options.Services...))
+	}
+	images := map[string]*image.Summary{}
+	for _, img := range usage.Images {
+		images[img.ID] = img
+	}
+
+	var (
+		report   api.DiskUsageReport
+		services = map[string]int{}
+		counted  = map[string]bool{}
+		mounted  = map[string]bool{}
+		built    bool
+	)
+	for _, c := range containers.sorted() {
+		name := c.Labels[api.ServiceLabel]
+		i, ok := services[name]
+		if !ok {
+			i = len(report.Services)
+			services[name] = i
+			report.Services = append(report.Services, api.ServiceDiskUsage{Service: name, Image: c.Image})
+		}
+		service := &report.Services[i]
+		if img, ok := images[c.ImageID]; ok && !counted[c.ImageID] {
+			counted[c.ImageID] = true
+			service.ImageSize = img.Size
+			report.Total += img.Size
+			built = built || img.Labels[api.ProjectLabel] == projectName
+		}
+		service.WritableSize += c.SizeRw
+		report.Total += c.SizeRw
+
+		if service.LogSize >= 0 {
+			size, err := s.logFileSize(ctx, c.ID)
+			if err != nil {
+				return api.DiskUsageReport{}, err
+			}
+			if size < 0 {
+				service.LogSize = -1
+			} else {
+				service.LogSize += size
+				report.Total += size
+			}
+		}
+		for _, m := range c.Mounts {
+			mounted[m.Name] = true
+		}
+	}
+
+	for _, v := range usage.Volumes {
+		if v.Labels[api.ProjectLabel] != projectName {
+			continue
+		}
+		if len(options.Services) > 0 && !mounted[v.Name] {
+			continue
+		}
+		size := int64(-1)
+		if v.UsageData != nil && v.UsageData.Size >= 0 {
+			size = v.UsageData.Size
+			report.Total += size
+		}
+		report.Volumes = append(report.Volumes, api.VolumeDiskUsage{
+			Volume: v.Labels[api.VolumeLabel],
+			Name:   v.Name,
+			Size:   size,
+		})
+	}
+	slices.SortFunc(report.Volumes, func(a, b api.VolumeDiskUsage) int {
+		return strings.Compare(a.Volume, b.Volume)
+	})
+
+	if len(report.Services) == 0 && len(report.Volumes) == 0 {
+		return api.DiskUsageReport{}, fmt.Errorf("no resources found for project %q: %w", projectName, api.ErrNotFound)
+	}
+
+	if built {
+		for _, record := range usage.BuildCache {
+			if !record.Shared {
+				report.BuildCache += record.Size
+			}
+		}
+	}
+	return report, nil
+}
+
+// logFileSize returns size of a container log file, or -1 if logging driver doesn't write to a file or it can't be
+// accessed from the host compose is running on
+func (s *composeService) logFileSize(ctx context.Context, id string) (int64, error) {
+	if !s.isLocalEngine() {
+		return -1, nil
+	}
+	inspect, err := s.apiClient().ContainerInspect(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if inspect.LogPath == "" {
+		return -1, nil
+	}
+	info, err := os.Stat(inspect.LogPath)
+	if err != nil {
+		// Docker Desktop stores logs inside a VM
+		return -1, nil
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDiskUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	mockCli.EXPECT().DockerEndpoint().Return(docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "unix:///var/run/docker.sock"}}).AnyTimes()
+	tested := composeService{dockerCli: mockCli}
+
+	logPath := filepath.Join(t.TempDir(), "json.log")
+	assert.NilError(t, os.WriteFile(logPath, make([]byte, 100), 0o600))
+
+	serviceContainer := func(id, service, imageID string, number int, sizeRw int64) *container.Summary {
+		c := testContainer(service, id, false)
+		c.Names = []string{fmt.Sprintf("/%s-%s-%d", testProject, service, number)}
+		c.ImageID = imageID
+		c.Image = service + ":latest"
+		c.SizeRw = sizeRw
+		return &c
+	}
+	web1 := serviceContainer("w1", "web", "sha256:web", 1, 10)
+	web1.Mounts = []container.MountPoint{{Name: testProject + "_data"}}
+	web2 := serviceContainer("w2", "web", "sha256:web", 2, 20)
+	db := serviceContainer("d1", "db", "sha256:db", 1, 5)
+	other := &container.Summary{ID: "other", Labels: map[string]string{api.ProjectLabel: "other"}, SizeRw: 1000}
+
+	mockApi.EXPECT().DiskUsage(gomock.Any(), types.DiskUsageOptions{}).Return(types.DiskUsage{
+		Containers: []*container.Summary{web2, other, db, web1},
+		Images: []*image.Summary{
+			{ID: "sha256:web", Size: 1000, Labels: map[string]string{api.ProjectLabel: strings.ToLower(testProject)}},
+			{ID: "sha256:db", Size: 2000},
+		},
+		Volumes: []*volume.Volume{
+			{Name: testProject + "_data", Labels: projectLabels(strings.ToLower(testProject), "data"), UsageData: &volume.UsageData{Size: 300}},
+			{Name: testProject + "_remote", Labels: projectLabels(strings.ToLower(testProject), "remote"), UsageData: &volume.UsageData{Size: -1}},
+			{Name: "other_data", Labels: projectLabels("other", "data"), UsageData: &volume.UsageData{Size: 3000}},
+		},
+		BuildCache: []*build.CacheRecord{
+			{ID: "a", Size: 50},
+			{ID: "b", Size: 70, Shared: true},
+		},
+	}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, id string) (container.InspectResponse, error) {
+		inspect := container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: id}}
+		if id != "d1" {
+			inspect.LogPath = logPath
+		}
+		return inspect, nil
+	}).Times(3)
+
+	report, err := tested.DiskUsage(t.Context(), testProject, api.DiskUsageOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, report, api.DiskUsageReport{
+		Services: []api.ServiceDiskUsage{
+			{Service: "db", Image: "db:latest", ImageSize: 2000, WritableSize: 5, LogSize: -1},
+			{Service: "web", Image: "web:latest", ImageSize: 1000, WritableSize: 30, LogSize: 200},
+		},
+		Volumes: []api.VolumeDiskUsage{
+			{Volume: "data", Name: testProject + "_data", Size: 300},
+			{Volume: "remote", Name: testProject + "_remote", Size: -1},
+		},
+		BuildCache: 50,
+		Total:      2000 + 5 + 1000 + 30 + 200 + 300,
+	})
+}
+
+func projectLabels(project, volume string) map[string]string {
+	return map[string]string{api.ProjectLabel: project, api.VolumeLabel: volume}
+}
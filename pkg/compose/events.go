@@ -18,7 +18,12 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,11 +35,29 @@ import (
 
 func (s *composeService) Events(ctx context.Context, projectName string, options api.EventsOptions) error {
 	projectName = strings.ToLower(projectName)
-	evts, errors := s.apiClient().Events(ctx, events.ListOptions{
+	since := options.Since
+	var after *eventCursor
+	if options.After != "" {
+		if since != "" {
+			return errors.New("events cursor and since can't be combined")
+		}
+		cursor, err := parseEventCursor(options.After)
+		if err != nil {
+			return err
+		}
+		after = &cursor
+		// engine history is replayed from cursor timestamp, events up to the cursor one are then skipped
+		since = cursor.timestamp()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	evts, errs := s.apiClient().Events(ctx, events.ListOptions{
 		Filters: filters.NewArgs(projectFilter(projectName)),
-		Since:   options.Since,
+		Since:   since,
 		Until:   options.Until,
 	})
+	consumed := 0
 	for {
 		select {
 		case event := <-evts:
@@ -43,6 +66,17 @@ func (s *composeService) Events(ctx context.Context, projectName string, options
 				continue
 			}
 
+			cursor := newEventCursor(event)
+			if after != nil {
+				if cursor.nano <= after.nano {
+					if cursor == *after {
+						after = nil
+					}
+					continue
+				}
+				after = nil
+			}
+
 			oneOff := event.Actor.Attributes[api.OneoffLabel]
 			if oneOff == "True" {
 				// ignore
@@ -61,23 +95,67 @@ func (s *composeService) Events(ctx context.Context, projectName string, options
 				attributes[k] = v
 			}
 
-			timestamp := time.Unix(event.Time, 0)
-			if event.TimeNano != 0 {
-				timestamp = time.Unix(0, event.TimeNano)
-			}
 			err := options.Consumer(api.Event{
-				Timestamp:  timestamp,
+				Timestamp:  time.Unix(0, cursor.nano),
 				Service:    service,
 				Container:  event.Actor.ID,
 				Status:     string(event.Action),
 				Attributes: attributes,
+				Cursor:     cursor.String(),
 			})
 			if err != nil {
 				return err
 			}
+			consumed++
+			if options.Limit > 0 && consumed >= options.Limit {
+				return nil
+			}
 
-		case err := <-errors:
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				// engine closes the stream once until is reached
+				return nil
+			}
 			return err
 		}
 	}
 }
+
+// eventCursor identifies an engine event, by its timestamp and a hash of its content, as engine events don't
+// have an ID
+type eventCursor struct {
+	nano int64
+	hash uint32
+}
+
+func newEventCursor(event events.Message) eventCursor {
+	nano := event.TimeNano
+	if nano == 0 {
+		nano = time.Unix(event.Time, 0).UnixNano()
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(event.Actor.ID))
+	_, _ = h.Write([]byte(event.Action))
+	return eventCursor{nano: nano, hash: h.Sum32()}
+}
+
+func parseEventCursor(s string) (eventCursor, error) {
+	nano, hash, ok := strings.Cut(s, "-")
+	if ok {
+		n, err := strconv.ParseInt(nano, 10, 64)
+		h, err2 := strconv.ParseUint(hash, 16, 32)
+		if err == nil && err2 == nil {
+			return eventCursor{nano: n, hash: uint32(h)}, nil
+		}
+	}
+	return eventCursor{}, fmt.Errorf("invalid events cursor %q", s)
+}
+
+func (c eventCursor) String() string {
+	return fmt.Sprintf("%d-%08x", c.nano, c.hash)
+}
+
+// timestamp formats cursor time as engine API `since` parameter
+func (c eventCursor) timestamp() string {
+	return fmt.Sprintf("%d.%09d", c.nano/int64(time.Second), c.nano%int64(time.Second))
+}
@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestEventsResumeAfterCursor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	message := func(id string, action events.Action, nano int64) events.Message {
+		return events.Message{
+			Type:     events.ContainerEventType,
+			Action:   action,
+			Actor:    events.Actor{ID: id, Attributes: map[string]string{api.ServiceLabel: "web"}},
+			TimeNano: nano,
+		}
+	}
+	history := []events.Message{
+		message("123", events.ActionCreate, 1_000_000_001),
+		message("123", events.ActionStart, 1_000_000_001),
+		message("123", events.ActionDie, 1_000_000_001),
+		message("123", events.ActionDestroy, 2_000_000_000),
+		message("456", events.ActionCreate, 3_000_000_000),
+	}
+	cursor := newEventCursor(history[1]).String()
+
+	evtCh := make(chan events.Message)
+	errCh := make(chan error)
+	apiClient.EXPECT().Events(gomock.Any(), events.ListOptions{
+		Filters: filters.NewArgs(projectFilter("test")),
+		Since:   "1.000000001",
+	}).Return(evtCh, errCh)
+	go func() {
+		for _, e := range history {
+			evtCh <- e
+		}
+		errCh <- io.EOF
+	}()
+
+	var actions []string
+	err := tested.Events(context.Background(), "test", api.EventsOptions{
+		After: cursor,
+		Limit: 2,
+		Consumer: func(event api.Event) error {
+			actions = append(actions, event.Status)
+			return nil
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, actions, []string{"die", "destroy"})
+}
+
+func TestEventsUntil(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli}
+
+	errCh := make(chan error, 1)
+	errCh <- io.EOF
+	apiClient.EXPECT().Events(gomock.Any(), gomock.Any()).Return(make(chan events.Message), errCh)
+	err := tested.Events(context.Background(), "test", api.EventsOptions{
+		Since: "1h",
+		Until: "0s",
+		Consumer: func(event api.Event) error {
+			return nil
+		},
+	})
+	assert.NilError(t, err)
+
+	_, err = parseEventCursor("not-a-cursor")
+	assert.ErrorContains(t, err, `invalid events cursor "not-a-cursor"`)
+}
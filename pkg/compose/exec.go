@@ -19,21 +19,35 @@ package compose
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command/container"
 	"github.com/docker/compose/v5/pkg/api"
 	containerType "github.com/docker/docker/api/types/container"
+	"github.com/sirupsen/logrus"
 )
 
-func (s *composeService) Exec(ctx context.Context, projectName string, options api.RunOptions) (int, error) {
+// Exec is the deprecated form of ExecCommand, accepting RunOptions
+func (s *composeService) Exec(ctx context.Context, projectName string, options api.RunOptions) (int, error) { //nolint:staticcheck
+	return s.ExecCommand(ctx, projectName, options.ExecOptions())
+}
+
+func (s *composeService) ExecCommand(ctx context.Context, projectName string, options api.ExecOptions) (int, error) {
 	projectName = strings.ToLower(projectName)
 	target, err := s.getExecTarget(ctx, projectName, options)
 	if err != nil {
 		return 0, err
 	}
 
+	if s.dryRun {
+		return 0, Run(ctx, func(ctx context.Context) error {
+			s.events.On(commandEvent(getCanonicalContainerName(target), options.Command, options.User, options.WorkingDir))
+			return nil
+		}, "exec", s.events)
+	}
+
 	exec := container.NewExecOptions()
 	exec.Interactive = options.Interactive
 	exec.TTY = options.Tty
@@ -49,7 +63,21 @@ func (s *composeService) Exec(ctx context.Context, projectName string, options a
 		}
 	}
 
-	err = container.RunExec(ctx, s.dockerCli, target.ID, exec)
+	dockerCli := s.dockerCli
+	if options.Record != "" {
+		recorder, err := s.recordSession(options.Record, options.Command)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				logrus.Warnf("failed to save session recording %s: %v", options.Record, err)
+			}
+		}()
+		dockerCli = recorder.wrap(dockerCli, options.Tty)
+	}
+
+	err = container.RunExec(ctx, dockerCli, target.ID, exec)
 	var sterr cli.StatusError
 	if errors.As(err, &sterr) {
 		return sterr.StatusCode, err
@@ -57,6 +85,35 @@ func (s *composeService) Exec(ctx context.Context, projectName string, options a
 	return 0, err
 }
 
-func (s *composeService) getExecTarget(ctx context.Context, projectName string, opts api.RunOptions) (containerType.Summary, error) {
+// commandEvent reports, in dry-run mode, the command which would run in a container
+func commandEvent(id string, command []string, user, workingDir string) api.Resource {
+	details := formatCommand(command)
+	if user != "" {
+		details += " as " + user
+	}
+	if workingDir != "" {
+		details += " in " + workingDir
+	}
+	return api.Resource{
+		ID:      id,
+		Status:  api.Done,
+		Text:    api.StatusExecuted,
+		Details: details,
+	}
+}
+
+// formatCommand renders command as a shell command line
+func formatCommand(command []string) string {
+	args := make([]string, len(command))
+	for i, arg := range command {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"$\\") {
+			arg = strconv.Quote(arg)
+		}
+		args[i] = arg
+	}
+	return strings.Join(args, " ")
+}
+
+func (s *composeService) getExecTarget(ctx context.Context, projectName string, opts api.ExecOptions) (containerType.Summary, error) {
 	return s.getSpecifiedContainer(ctx, projectName, oneOffInclude, false, opts.Service, opts.Index)
 }
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestFormatCommand(t *testing.T) {
+	assert.Equal(t, formatCommand([]string{"sh", "-c", "echo $HOME", ""}), `sh -c "echo $HOME" ""`)
+}
+
+type recordingEvents struct {
+	resources []api.Resource
+}
+
+func (r *recordingEvents) Start(context.Context, string) {}
+
+func (r *recordingEvents) Done(string, bool) {}
+
+func (r *recordingEvents) On(resources ...api.Resource) {
+	r.resources = append(r.resources, resources...)
+}
+
+func TestExecDryRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		testContainer("service1", "123", false),
+	}, nil)
+
+	events := &recordingEvents{}
+	tested := &composeService{dockerCli: cli, dryRun: true, events: events}
+	code, err := tested.ExecCommand(context.Background(), strings.ToLower(testProject), api.ExecOptions{
+		Service: "service1",
+		Command: []string{"rake", "db:migrate"},
+		User:    "app",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, code, 0)
+	assert.DeepEqual(t, events.resources, []api.Resource{{
+		ID:      "123",
+		Status:  api.Done,
+		Text:    api.StatusExecuted,
+		Details: "rake db:migrate as app",
+	}})
+}
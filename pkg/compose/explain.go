@@ -0,0 +1,209 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// sensitivePattern matches names of environment variables and labels which values could be secrets
+var sensitivePattern = regexp.MustCompile(`(?i)(passw|secret|token|key|credential|auth|private)`)
+
+const redactedPlaceholder = "<redacted>"
+
+// IsSensitiveName tells if an environment variable or label name suggests its value could be a secret
+func IsSensitiveName(name string) bool {
+	return sensitivePattern.MatchString(name)
+}
+
+func (s *composeService) ExplainRecreate(ctx context.Context, project *types.Project, service string) ([]api.RecreateExplanation, error) {
+	if _, err := project.GetService(service); err != nil {
+		return nil, err
+	}
+	// resolve images digests, so we can detect updated images
+	if _, err := s.getLocalImagesDigests(ctx, project); err != nil {
+		return nil, err
+	}
+	expected := project.Services[service]
+
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, service)
+	if err != nil {
+		return nil, err
+	}
+	var explanations []api.RecreateExplanation
+	for _, ctr := range containers.sorted() {
+		diff, err := configDiff(expected, ctr)
+		if err != nil {
+			return nil, err
+		}
+		explanation := api.RecreateExplanation{
+			Container: getCanonicalContainerName(ctr),
+			Recreate:  len(diff) > 0,
+		}
+		if len(diff) > 0 {
+			inspect, err := s.apiClient().ContainerInspect(ctx, ctr.ID)
+			if err != nil {
+				return nil, err
+			}
+			var imageEnv []string
+			if img, err := s.apiClient().ImageInspect(ctx, inspect.Image); err == nil && img.Config != nil {
+				imageEnv = img.Config.Env
+			}
+			old := containerState{inspect: inspect, imageEnv: envMap(imageEnv)}
+			for _, component := range diff {
+				explanation.Changes = append(explanation.Changes, explainChange(project, expected, component, old)...)
+			}
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// containerState is the actual container configuration a service is compared with
+type containerState struct {
+	inspect  container.InspectResponse
+	imageEnv map[string]string
+}
+
+// explainChange details the attributes which changed within a hash component. When those can't be
+// identified, because container configuration merges image defaults, a change without details is returned
+func explainChange(project *types.Project, service types.ServiceConfig, component string, old containerState) []api.ConfigChange {
+	var changes []api.ConfigChange
+	// environment variables and labels may hold secrets
+	sensitive := component == hashComponentEnvironment || component == hashComponentLabels
+	add := func(attribute, o, n string) {
+		if o == n {
+			return
+		}
+		if sensitive {
+			o, n = redactValue(attribute, o), redactValue(attribute, n)
+		}
+		changes = append(changes, api.ConfigChange{Component: component, Attribute: attribute, Old: o, New: n})
+	}
+	config := old.inspect.Config
+	if config == nil {
+		config = &container.Config{}
+	}
+	switch component {
+	case diffImageDigest:
+		add("", config.Labels[api.ImageDigestLabel], service.CustomLabels[api.ImageDigestLabel])
+	case hashComponentImage:
+		add("image", config.Image, api.GetImageNameOrDefault(service, project.Name))
+	case hashComponentCommand:
+		if service.Entrypoint != nil {
+			add("entrypoint", strings.Join(config.Entrypoint, " "), strings.Join(service.Entrypoint, " "))
+		}
+		if service.Command != nil {
+			add("command", strings.Join(config.Cmd, " "), strings.Join(service.Command, " "))
+		}
+		if service.WorkingDir != "" {
+			add("working_dir", config.WorkingDir, service.WorkingDir)
+		}
+		if service.User != "" {
+			add("user", config.User, service.User)
+		}
+	case hashComponentEnvironment:
+		actual := envMap(config.Env)
+		for _, name := range slices.Sorted(maps.Keys(service.Environment)) {
+			v := service.Environment[name]
+			if v == nil {
+				continue
+			}
+			add(name, actual[name], *v)
+		}
+		for _, name := range slices.Sorted(maps.Keys(actual)) {
+			if _, ok := service.Environment[name]; ok {
+				continue
+			}
+			if v, ok := old.imageEnv[name]; ok && v == actual[name] {
+				continue
+			}
+			add(name, actual[name], "")
+		}
+	case hashComponentMounts:
+		actual := map[string]string{}
+		for _, m := range old.inspect.Mounts {
+			source := m.Source
+			if m.Type == mount.TypeVolume {
+				source = m.Name
+			}
+			actual[m.Destination] = source
+		}
+		for _, v := range service.Volumes {
+			source := v.Source
+			if v.Type == types.VolumeTypeVolume {
+				if source == "" {
+					// anonymous volume
+					continue
+				}
+				if vol, ok := project.Volumes[source]; ok {
+					source = vol.Name
+				}
+			}
+			add(v.Target, actual[v.Target], source)
+		}
+	case hashComponentNetworks:
+		var actual, expected []string
+		if old.inspect.NetworkSettings != nil {
+			actual = slices.Sorted(maps.Keys(old.inspect.NetworkSettings.Networks))
+		}
+		for name := range service.Networks {
+			if nw, ok := project.Networks[name]; ok {
+				name = nw.Name
+			}
+			expected = append(expected, name)
+		}
+		slices.Sort(expected)
+		if service.NetworkMode == "" {
+			add("networks", strings.Join(actual, ","), strings.Join(expected, ","))
+		}
+	case hashComponentLabels:
+		for _, name := range slices.Sorted(maps.Keys(service.Labels)) {
+			add(name, config.Labels[name], service.Labels[name])
+		}
+	}
+	if len(changes) == 0 {
+		return []api.ConfigChange{{Component: component}}
+	}
+	return changes
+}
+
+func envMap(env []string) map[string]string {
+	m := map[string]string{}
+	for _, e := range env {
+		k, v, _ := strings.Cut(e, "=")
+		m[k] = v
+	}
+	return m
+}
+
+func redactValue(name, value string) string {
+	if value != "" && IsSensitiveName(name) {
+		return redactedPlaceholder
+	}
+	return value
+}
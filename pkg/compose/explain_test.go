@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestExplainChange(t *testing.T) {
+	project := &types.Project{Name: "test"}
+	service := types.ServiceConfig{
+		Name:    "app",
+		Image:   "app:2",
+		Command: types.ShellCommand{"serve", "--verbose"},
+		Environment: types.NewMappingWithEquals([]string{
+			"LOG_LEVEL=debug",
+			"DB_PASSWORD=new",
+		}),
+	}
+	old := containerState{
+		inspect: container.InspectResponse{
+			Config: &container.Config{
+				Image: "app:1",
+				Cmd:   []string{"serve"},
+				Env:   []string{"PATH=/usr/bin", "LOG_LEVEL=info", "DB_PASSWORD=old", "LEGACY=1"},
+			},
+		},
+		imageEnv: map[string]string{"PATH": "/usr/bin"},
+	}
+
+	assert.DeepEqual(t, explainChange(project, service, hashComponentImage, old), []api.ConfigChange{
+		{Component: hashComponentImage, Attribute: "image", Old: "app:1", New: "app:2"},
+	})
+	assert.DeepEqual(t, explainChange(project, service, hashComponentCommand, old), []api.ConfigChange{
+		{Component: hashComponentCommand, Attribute: "command", Old: "serve", New: "serve --verbose"},
+	})
+	assert.DeepEqual(t, explainChange(project, service, hashComponentEnvironment, old), []api.ConfigChange{
+		{Component: hashComponentEnvironment, Attribute: "DB_PASSWORD", Old: redactedPlaceholder, New: redactedPlaceholder},
+		{Component: hashComponentEnvironment, Attribute: "LOG_LEVEL", Old: "info", New: "debug"},
+		{Component: hashComponentEnvironment, Attribute: "LEGACY", Old: "1"},
+	})
+	// changes hidden by image defaults can't be detailed
+	assert.DeepEqual(t, explainChange(project, service, hashComponentOther, old), []api.ConfigChange{
+		{Component: hashComponentOther},
+	})
+}
@@ -0,0 +1,215 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"reflect"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// Extension scopes, as the compose file element an extension can be set on
+const (
+	ScopeProject      = "project"
+	ScopeService      = "service"
+	ScopeServiceMount = "service.volumes"
+	ScopeVolume       = "volume"
+	ScopeDevelop      = "service.develop"
+	ScopeWatchRule    = "service.develop.watch"
+)
+
+// ExtensionSchema documents an extension field compose recognizes in the compose file
+type ExtensionSchema struct {
+	Name        string          `json:"name"`
+	Scopes      []string        `json:"scopes"`
+	Description string          `json:"description"`
+	Schema      *api.JSONSchema `json:"schema"`
+}
+
+func extensionSchemaOf(v any) *api.JSONSchema {
+	return api.SchemaOf(reflect.TypeOf(v), "mapstructure")
+}
+
+func stringOrList() *api.JSONSchema {
+	return &api.JSONSchema{OneOf: []*api.JSONSchema{
+		{Type: "string"},
+		{Type: "array", Items: &api.JSONSchema{Type: "string"}},
+	}}
+}
+
+// ExtensionsSchemas returns the extension fields compose recognizes in the compose file, sorted by name
+func ExtensionsSchemas() []ExtensionSchema {
+	return []ExtensionSchema{
+		{
+			Name:        WatchBackendExtension,
+			Scopes:      []string{ScopeWatchRule},
+			Description: "Backend used to receive file events for the watched path",
+			Schema: &api.JSONSchema{Type: "string", Enum: []string{
+				string(watch.BackendAuto), string(watch.BackendFSNotify), string(watch.BackendFSEvents),
+				string(watch.BackendPolling), string(watch.BackendWatchman),
+			}},
+		},
+		{
+			Name:        BuildExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Configuration of images built by compose",
+			Schema: &api.JSONSchema{Type: "object", Properties: map[string]*api.JSONSchema{
+				"tag-template": {Type: "string", Description: "Go template of an additional tag applied to service images"},
+			}},
+		},
+		{
+			Name:        CacheVolumesExtension,
+			Scopes:      []string{ScopeDevelop},
+			Description: "Container paths stored on named cache volumes managed by compose",
+			Schema:      stringOrList(),
+		},
+		{
+			Name:        ChownExtension,
+			Scopes:      []string{ScopeVolume, ScopeServiceMount},
+			Description: "Owner of a volume or bind mount, enforced before services are started",
+			Schema: &api.JSONSchema{OneOf: []*api.JSONSchema{
+				{Type: "string", Pattern: chownPattern.String()},
+				{Type: "integer"},
+			}},
+		},
+		{
+			Name:        DefaultLoggingExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Logging configuration applied to services which don't declare one",
+			Schema:      extensionSchemaOf(defaultLoggingConfig{}),
+		},
+		{
+			Name:        ExposeHostExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Hostnames the service is reachable by from the host, through the compose-managed ingress",
+			Schema:      stringOrList(),
+		},
+		{
+			Name:        ExternalServiceExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Service is an existing container compose doesn't manage",
+			Schema: &api.JSONSchema{OneOf: []*api.JSONSchema{
+				{Type: "boolean"},
+				{Type: "object", Properties: map[string]*api.JSONSchema{
+					"name":   {Type: "string"},
+					"labels": {Type: "object", AdditionalProperties: &api.JSONSchema{Type: "string"}},
+				}},
+			}},
+		},
+		{
+			Name:        features.ProjectExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Features enabled or disabled for the project, see features list",
+			Schema:      &api.JSONSchema{Type: "object", AdditionalProperties: &api.JSONSchema{Type: "boolean"}},
+		},
+		{
+			Name:        HostnamesExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Hostnames added to the host hosts file by up --sync-hosts",
+			Schema:      &api.JSONSchema{Type: "array", Items: &api.JSONSchema{Type: "string"}},
+		},
+		{
+			Name:        IngressPortExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Host port the compose-managed ingress of the project listens to, 80 by default",
+			Schema: &api.JSONSchema{OneOf: []*api.JSONSchema{
+				{Type: "integer"},
+				{Type: "string"},
+			}},
+		},
+		{
+			Name:        InitContainersExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Containers to run to completion before the service starts",
+			Schema:      extensionSchemaOf([]InitContainerConfig{}),
+		},
+		{
+			Name:        InitJobsExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Jobs to run once per lifetime of a service volume, after the service started",
+			Schema:      extensionSchemaOf([]InitJobConfig{}),
+		},
+		{
+			Name:        InterpolationFunctionsExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Enable functions in variable interpolation, like ${VAR | lower}",
+			Schema:      &api.JSONSchema{Type: "boolean"},
+		},
+		{
+			Name:        OneoffTTLExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Delay after which stopped one-off containers are removed",
+			Schema:      &api.JSONSchema{Type: "string", Format: "duration"},
+		},
+		{
+			Name:        PullRetryExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Retries of image pulls",
+			Schema:      extensionSchemaOf(PullRetryConfig{}),
+		},
+		{
+			Name:        WatchRestartExtension,
+			Scopes:      []string{ScopeWatchRule},
+			Description: "How the service is restarted, signal:<SIGNAL> sends a signal to the container main process",
+			Schema:      &api.JSONSchema{Type: "string", Pattern: "^signal:.+$"},
+		},
+		{
+			Name:        SidecarOfExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Service this sidecar shares its lifecycle with",
+			Schema:      &api.JSONSchema{Type: "string"},
+		},
+		{
+			Name:        StableReplicasExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Replicas get stable numbers, hostnames and per-replica volumes which survive recreates",
+			Schema: &api.JSONSchema{OneOf: []*api.JSONSchema{
+				{Type: "boolean"},
+				extensionSchemaOf(stableReplicasConfig{}),
+			}},
+		},
+		{
+			Name:        SyncBackExtension,
+			Scopes:      []string{ScopeWatchRule},
+			Description: "Copy files modified inside container back to the host, requires find and stat commands in the container",
+			Schema:      &api.JSONSchema{Type: "boolean"},
+		},
+		{
+			Name:        SyncBackConflictExtension,
+			Scopes:      []string{ScopeWatchRule},
+			Description: "Side which wins when a file has been modified both on host and inside container",
+			Schema:      &api.JSONSchema{Type: "string", Enum: []string{SyncBackConflictHost, SyncBackConflictContainer}},
+		},
+		{
+			Name:        TLSExtension,
+			Scopes:      []string{ScopeService},
+			Description: "Issue a certificate from the project local CA to the service",
+			Schema:      &api.JSONSchema{Type: "boolean"},
+		},
+		{
+			Name:        VariablesExtension,
+			Scopes:      []string{ScopeProject},
+			Description: "Declaration of the variables used by the compose file",
+			Schema: &api.JSONSchema{
+				Type:                 "object",
+				AdditionalProperties: extensionSchemaOf(VariableDeclaration{}),
+			},
+		},
+	}
+}
@@ -0,0 +1,197 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// ExternalServiceExtension marks a service as managed outside Compose. Compose doesn't create, start or remove
+// its container, but locates an existing one by name and/or labels, and considers it as the service container
+// for ps, logs and depends_on. As compose file validation requires it, service still declares an image,
+// which won't be pulled.
+//
+//	services:
+//	  db:
+//	    image: postgres
+//	    x-external: true # container named after container_name, or service name
+//	  cache:
+//	    image: redis
+//	    x-external:
+//	      name: shared-redis
+//	      labels:
+//	        com.example.role: cache
+const ExternalServiceExtension = "x-external"
+
+// externalService describes how to locate the container of an external service
+type externalService struct {
+	name   string
+	labels map[string]string
+}
+
+// getExternalService returns the container lookup declared by ExternalServiceExtension, or nil if service
+// is not external
+func getExternalService(service types.ServiceConfig) (*externalService, error) {
+	v, ok := service.Extensions[ExternalServiceExtension]
+	if !ok {
+		return nil, nil
+	}
+	defaultName := service.ContainerName
+	if defaultName == "" {
+		defaultName = service.Name
+	}
+	switch e := v.(type) {
+	case bool:
+		if !e {
+			return nil, nil
+		}
+		return &externalService{name: defaultName}, nil
+	case map[string]any:
+		ext := &externalService{labels: map[string]string{}}
+		for key, value := range e {
+			switch key {
+			case "name":
+				ext.name = fmt.Sprint(value)
+			case "labels":
+				labels, ok := value.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("service %q: invalid %s labels, expected a mapping", service.Name, ExternalServiceExtension)
+				}
+				for k, l := range labels {
+					ext.labels[k] = fmt.Sprint(l)
+				}
+			default:
+				return nil, fmt.Errorf("service %q: unsupported %s attribute %q", service.Name, ExternalServiceExtension, key)
+			}
+		}
+		if ext.name == "" && len(ext.labels) == 0 {
+			ext.name = defaultName
+		}
+		return ext, nil
+	default:
+		return nil, fmt.Errorf("service %q: invalid %s, expected a boolean or a mapping", service.Name, ExternalServiceExtension)
+	}
+}
+
+// validateExternalServices checks ExternalServiceExtension is well-formed on all services, so the error is reported
+// when project is loaded, rather than service being silently handled as a regular one
+func validateExternalServices(project *types.Project) error {
+	var errs []error
+	for _, name := range project.ServiceNames() {
+		if _, err := getExternalService(project.Services[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isExternalService tells if service is declared external. A malformed extension is rejected by
+// validateExternalServices, when project is loaded or created, so it's safe to ignore here
+func isExternalService(service types.ServiceConfig) bool {
+	ext, err := getExternalService(service)
+	return err == nil && ext != nil
+}
+
+func (e externalService) String() string {
+	if e.name != "" {
+		return fmt.Sprintf("name %q", e.name)
+	}
+	return fmt.Sprintf("labels %v", e.labels)
+}
+
+// getExternalContainers locates containers of the project external services, selected by services if set.
+// Containers are labeled as belonging to the project service, so they can be processed as service containers
+func (s *composeService) getExternalContainers(ctx context.Context, project *types.Project, all bool, services ...string) (Containers, error) {
+	var containers Containers
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		if len(services) > 0 && !slices.Contains(services, name) {
+			continue
+		}
+		service := project.Services[name]
+		ext, err := getExternalService(service)
+		if err != nil {
+			return nil, err
+		}
+		if ext == nil {
+			continue
+		}
+		found, err := s.findExternalContainers(ctx, *ext, all)
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range found {
+			labels := maps.Clone(c.Labels)
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[api.ProjectLabel] = project.Name
+			labels[api.ServiceLabel] = service.Name
+			labels[api.ContainerNumberLabel] = fmt.Sprint(i + 1)
+			labels[api.OneoffLabel] = "False"
+			c.Labels = labels
+			containers = append(containers, c)
+		}
+	}
+	return containers, nil
+}
+
+func (s *composeService) findExternalContainers(ctx context.Context, ext externalService, all bool) (Containers, error) {
+	var args []filters.KeyValuePair
+	if ext.name != "" {
+		args = append(args, filters.Arg("name", "^/"+regexp.QuoteMeta(ext.name)+"$"))
+	}
+	for k, v := range ext.labels {
+		args = append(args, filters.Arg("label", k+"="+v))
+	}
+	return s.apiClient().ContainerList(ctx, container.ListOptions{
+		All:     all,
+		Filters: filters.NewArgs(args...),
+	})
+}
+
+// checkExternalService verifies the container of an external service is running, as Compose won't start it
+func (s *composeService) checkExternalService(ctx context.Context, service types.ServiceConfig) error {
+	ext, err := getExternalService(service)
+	if err != nil {
+		return err
+	}
+	found, err := s.findExternalContainers(ctx, *ext, true)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("external service %q: no container found with %s", service.Name, ext)
+	}
+	for _, c := range found {
+		if c.State != container.StateRunning {
+			return fmt.Errorf("external service %q: container %s is not running", service.Name, getCanonicalContainerName(c))
+		}
+	}
+	s.events.On(newEvent(getContainerProgressName(found[0]), api.Done, "External"))
+	return nil
+}
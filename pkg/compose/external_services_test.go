@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestGetExternalService(t *testing.T) {
+	ext, err := getExternalService(types.ServiceConfig{Name: "db"})
+	assert.NilError(t, err)
+	assert.Assert(t, ext == nil)
+
+	ext, err = getExternalService(types.ServiceConfig{Name: "db", Extensions: types.Extensions{ExternalServiceExtension: true}})
+	assert.NilError(t, err)
+	assert.Equal(t, ext.name, "db")
+
+	ext, err = getExternalService(types.ServiceConfig{Name: "db", ContainerName: "pg", Extensions: types.Extensions{ExternalServiceExtension: true}})
+	assert.NilError(t, err)
+	assert.Equal(t, ext.name, "pg")
+
+	ext, err = getExternalService(types.ServiceConfig{Name: "cache", Extensions: types.Extensions{ExternalServiceExtension: map[string]any{
+		"labels": map[string]any{"com.example.role": "cache"},
+	}}})
+	assert.NilError(t, err)
+	assert.Equal(t, ext.name, "")
+	assert.DeepEqual(t, ext.labels, map[string]string{"com.example.role": "cache"})
+
+	_, err = getExternalService(types.ServiceConfig{Name: "db", Extensions: types.Extensions{ExternalServiceExtension: "yes"}})
+	assert.Error(t, err, `service "db": invalid x-external, expected a boolean or a mapping`)
+
+	_, err = getExternalService(types.ServiceConfig{Name: "db", Extensions: types.Extensions{ExternalServiceExtension: map[string]any{"id": "123"}}})
+	assert.Error(t, err, `service "db": unsupported x-external attribute "id"`)
+}
+
+func TestGetExternalContainers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	apiClient.EXPECT().ContainerList(gomock.Any(), container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", "^/shared-db$")),
+	}).Return([]container.Summary{{ID: "123", Names: []string{"/shared-db"}, State: container.StateRunning}}, nil)
+
+	project := &types.Project{Name: strings.ToLower(testProject), Services: types.Services{
+		"web": {Name: "web"},
+		"db":  {Name: "db", ContainerName: "shared-db", Extensions: types.Extensions{ExternalServiceExtension: true}},
+	}}
+	containers, err := tested.getExternalContainers(t.Context(), project, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 1)
+	assert.DeepEqual(t, containers[0].Labels, map[string]string{
+		api.ProjectLabel:         strings.ToLower(testProject),
+		api.ServiceLabel:         "db",
+		api.ContainerNumberLabel: "1",
+		api.OneoffLabel:          "False",
+	})
+}
+
+func TestCheckExternalServiceNotRunning(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+	service := types.ServiceConfig{Name: "db", Extensions: types.Extensions{ExternalServiceExtension: true}}
+
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(nil, nil)
+	err := tested.checkExternalService(t.Context(), service)
+	assert.Error(t, err, `external service "db": no container found with name "db"`)
+
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).
+		Return([]container.Summary{{ID: "123", Names: []string{"/db"}, State: container.StateExited}}, nil)
+	err = tested.checkExternalService(t.Context(), service)
+	assert.Error(t, err, `external service "db": container db is not running`)
+}
@@ -0,0 +1,152 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// extraHostTemplate matches an extra_hosts value set as `{{service.<name>.ip}}`, resolved as the IP address
+// of the service container. This allows services using host network, which can't rely on the embedded DNS
+// server, to reach other services.
+var extraHostTemplate = regexp.MustCompile(`^\{\{\s*service\.([a-zA-Z0-9._-]+)\.ip\s*\}\}$`)
+
+// extraHostsReferences returns the services which IP address is used by service extra_hosts
+func extraHostsReferences(service types.ServiceConfig) []string {
+	var refs []string
+	for _, host := range slices.Sorted(maps.Keys(service.ExtraHosts)) {
+		for _, value := range service.ExtraHosts[host] {
+			if m := extraHostTemplate.FindStringSubmatch(value); m != nil && !slices.Contains(refs, m[1]) {
+				refs = append(refs, m[1])
+			}
+		}
+	}
+	return refs
+}
+
+// applyExtraHostsDependencies makes services depend on the services referenced by extra_hosts, so their
+// containers are started first and have an IP address
+func applyExtraHostsDependencies(project *types.Project) (*types.Project, error) {
+	for name, service := range project.Services {
+		for _, ref := range extraHostsReferences(service) {
+			if _, ok := project.Services[ref]; !ok {
+				if _, disabled := project.DisabledServices[ref]; disabled {
+					// service is not enabled by active profiles, extra host won't be resolved
+					continue
+				}
+				return nil, fmt.Errorf("service %q: extra_hosts refers to undefined service %q", name, ref)
+			}
+			if ref == name {
+				return nil, fmt.Errorf("service %q: extra_hosts can't refer to the service itself", name)
+			}
+			if _, ok := service.DependsOn[ref]; ok {
+				continue
+			}
+			if service.DependsOn == nil {
+				service.DependsOn = types.DependsOnConfig{}
+			}
+			service.DependsOn[ref] = types.ServiceDependency{
+				Condition: types.ServiceConditionStarted,
+				Required:  true,
+			}
+		}
+		project.Services[name] = service
+	}
+	return project, nil
+}
+
+// resolveExtraHosts replaces extra_hosts referring to a service by the service container IP address, as
+// returned by lookup. Entries which can't be resolved yet, as the container isn't running, are removed.
+func resolveExtraHosts(service *types.ServiceConfig, lookup func(service string) string) {
+	if len(extraHostsReferences(*service)) == 0 {
+		return
+	}
+	resolved := types.HostsList{}
+	for host, values := range service.ExtraHosts {
+		for _, value := range values {
+			if m := extraHostTemplate.FindStringSubmatch(value); m != nil {
+				value = lookup(m[1])
+				if value == "" {
+					continue
+				}
+			}
+			resolved[host] = append(resolved[host], value)
+		}
+	}
+	service.ExtraHosts = resolved
+}
+
+func (c *convergence) resolveExtraHosts(service *types.ServiceConfig) {
+	resolveExtraHosts(service, func(name string) string {
+		for _, ctr := range c.getObservedState(name).sorted() {
+			if ctr.NetworkSettings == nil {
+				continue
+			}
+			if ip := endpointIPAddress(ctr.NetworkSettings.Networks); ip != "" {
+				return ip
+			}
+		}
+		return ""
+	})
+}
+
+// endpointIPAddress selects the IP address of a container, from the first network it is attached to
+func endpointIPAddress(networks map[string]*network.EndpointSettings) string {
+	for _, name := range slices.Sorted(maps.Keys(networks)) {
+		if settings := networks[name]; settings != nil && settings.IPAddress != "" {
+			return settings.IPAddress
+		}
+	}
+	return ""
+}
+
+// refreshExtraHosts checks container extra hosts still match the IP address of the services they refer to, which
+// might have been started or recreated since container was created. Container is recreated when extra hosts are stale.
+func (s *composeService) refreshExtraHosts(ctx context.Context, project *types.Project, service types.ServiceConfig, ctr container.Summary) (container.Summary, error) {
+	if len(extraHostsReferences(service)) == 0 {
+		return ctr, nil
+	}
+	observedState, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return ctr, err
+	}
+	err = newConvergence(project.ServiceNames(), observedState, nil, nil, s).resolveServiceReferences(&service)
+	if err != nil {
+		return ctr, err
+	}
+
+	inspect, err := s.apiClient().ContainerInspect(ctx, ctr.ID)
+	if err != nil {
+		return ctr, err
+	}
+	expected := service.ExtraHosts.AsList(":")
+	actual := slices.Clone(inspect.HostConfig.ExtraHosts)
+	slices.Sort(expected)
+	slices.Sort(actual)
+	if slices.Equal(expected, actual) {
+		return ctr, nil
+	}
+	return s.recreateContainer(ctx, project, service, ctr, false, false, nil)
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestApplyExtraHostsDependencies(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"db": {Name: "db"},
+			"app": {
+				Name:        "app",
+				NetworkMode: "host",
+				ExtraHosts: types.HostsList{
+					"db-host": {"{{service.db.ip}}"},
+					"static":  {"10.0.0.1"},
+				},
+			},
+		},
+	}
+	project, err := applyExtraHostsDependencies(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services["app"].DependsOn, types.DependsOnConfig{
+		"db": {Condition: types.ServiceConditionStarted, Required: true},
+	})
+	assert.Assert(t, project.Services["db"].DependsOn == nil)
+
+	project.Services["app"] = types.ServiceConfig{
+		Name:       "app",
+		ExtraHosts: types.HostsList{"cache": {"{{ service.cache.ip }}"}},
+	}
+	_, err = applyExtraHostsDependencies(project)
+	assert.Error(t, err, `service "app": extra_hosts refers to undefined service "cache"`)
+}
+
+func TestResolveExtraHosts(t *testing.T) {
+	db := container.Summary{
+		ID:     "123",
+		Labels: containerLabels("db", false),
+		NetworkSettings: &container.NetworkSettingsSummary{
+			Networks: map[string]*network.EndpointSettings{
+				"test_default": {IPAddress: "172.18.0.2"},
+			},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "app",
+		ExtraHosts: types.HostsList{
+			"db-host": {"{{service.db.ip}}"},
+			"web":     {"{{service.web.ip}}"},
+			"static":  {"10.0.0.1"},
+		},
+	}
+	c := newConvergence([]string{"db", "web", "app"}, Containers{db}, nil, nil, nil)
+	assert.NilError(t, c.resolveServiceReferences(&service))
+	// web has no running container yet, so the entry is left out
+	assert.DeepEqual(t, service.ExtraHosts, types.HostsList{
+		"db-host": {"172.18.0.2"},
+		"static":  {"10.0.0.1"},
+	})
+}
+
+func TestRefreshExtraHostsUpToDate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: cli, events: &ignore{}}
+
+	db := container.Summary{
+		ID:     "123",
+		Labels: containerLabels("db", false),
+		NetworkSettings: &container.NetworkSettingsSummary{
+			Networks: map[string]*network.EndpointSettings{
+				"test_default": {IPAddress: "172.18.0.2"},
+			},
+		},
+	}
+	app := container.Summary{ID: "456", Labels: containerLabels("app", false)}
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{db, app}, nil)
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "456").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			HostConfig: &container.HostConfig{ExtraHosts: []string{"db-host:172.18.0.2"}},
+		},
+	}, nil)
+
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"db":  {Name: "db"},
+			"app": {Name: "app", ExtraHosts: types.HostsList{"db-host": {"{{service.db.ip}}"}}},
+		},
+	}
+	ctr, err := tested.refreshExtraHosts(context.Background(), project, project.Services["app"], app)
+	assert.NilError(t, err)
+	assert.Equal(t, ctr.ID, "456")
+	assert.Equal(t, ctr.Labels[api.ServiceLabel], "app")
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// imageVolumeAPIVersion is the engine API version supporting volumes of type image
+const imageVolumeAPIVersion = "1.48"
+
+func (s *composeService) Features(ctx context.Context) ([]api.Feature, error) {
+	features := []api.Feature{
+		// implemented by compose itself, regardless of the engine
+		{Name: "watch", Supported: true},
+		{Name: "publish", Supported: true},
+		{Name: "dry-run", Supported: true},
+		{Name: "provider-services", Supported: true},
+		{Name: "secrets-driver", Reason: "secrets.*.driver is not supported by Docker Compose"},
+		s.pluginFeature("models", "model"),
+		s.bakeFeature(),
+	}
+
+	version, err := s.apiClient().ServerVersion(ctx)
+	if err != nil {
+		reason := fmt.Sprintf("Docker Engine is not reachable: %s", err)
+		for _, name := range []string{"engine-api", "image-volumes", "gpus", "rootless"} {
+			features = append(features, api.Feature{Name: name, Reason: reason})
+		}
+	} else {
+		info, err := s.apiClient().Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, engineFeatures(version.APIVersion, info)...)
+	}
+	sortFeatures(features)
+	return features, nil
+}
+
+// engineFeatures reports features depending on the engine version and configuration
+func engineFeatures(apiVersion string, info system.Info) []api.Feature {
+	features := []api.Feature{
+		apiVersionFeature("engine-api", apiVersion, minimumAPIVersion),
+		apiVersionFeature("image-volumes", apiVersion, imageVolumeAPIVersion),
+	}
+
+	gpus := api.Feature{Name: "gpus", Supported: true}
+	if _, ok := info.Runtimes["nvidia"]; !ok && len(info.CDISpecDirs) == 0 {
+		gpus = api.Feature{Name: "gpus", Reason: "engine has neither nvidia runtime nor CDI configured"}
+	}
+	features = append(features, gpus)
+
+	rootless := api.Feature{Name: "rootless", Supported: isRootless(info)}
+	if !rootless.Supported {
+		rootless.Reason = "engine is not running in rootless mode"
+	}
+	return append(features, rootless)
+}
+
+func apiVersionFeature(name string, apiVersion string, minimum string) api.Feature {
+	if versions.LessThan(apiVersion, minimum) {
+		return api.Feature{Name: name, Reason: fmt.Sprintf("requires engine API %s or later, engine has %s", minimum, apiVersion)}
+	}
+	return api.Feature{Name: name, Supported: true}
+}
+
+// pluginFeature reports a feature relying on a Docker CLI plugin
+func (s *composeService) pluginFeature(name string, plugin string) api.Feature {
+	if _, err := manager.GetPlugin(plugin, s.dockerCli, &cobra.Command{}); err != nil {
+		return api.Feature{Name: name, Reason: fmt.Sprintf("docker %s plugin is not available", plugin)}
+	}
+	return api.Feature{Name: name, Supported: true}
+}
+
+func (s *composeService) bakeFeature() api.Feature {
+	check := s.checkBuildKit()
+	if check.Status != api.DoctorOK {
+		return api.Feature{Name: "bake", Reason: check.Message}
+	}
+	return api.Feature{Name: "bake", Supported: true}
+}
+
+// sortFeatures sorts features by name, so report is stable
+func sortFeatures(features []api.Feature) {
+	slices.SortFunc(features, func(a, b api.Feature) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
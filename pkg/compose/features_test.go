@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestEngineFeatures(t *testing.T) {
+	features := engineFeatures("1.48", system.Info{
+		Runtimes: map[string]system.RuntimeWithStatus{"nvidia": {}},
+	})
+	assert.DeepEqual(t, features, []api.Feature{
+		{Name: "engine-api", Supported: true},
+		{Name: "image-volumes", Supported: true},
+		{Name: "gpus", Supported: true},
+		{Name: "rootless", Reason: "engine is not running in rootless mode"},
+	})
+
+	features = engineFeatures("1.43", system.Info{
+		SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless"},
+	})
+	assert.DeepEqual(t, features, []api.Feature{
+		{Name: "engine-api", Reason: "requires engine API 1.44 or later, engine has 1.43"},
+		{Name: "image-volumes", Reason: "requires engine API 1.48 or later, engine has 1.43"},
+		{Name: "gpus", Reason: "engine has neither nvidia runtime nor CDI configured"},
+		{Name: "rootless", Supported: true},
+	})
+}
@@ -31,6 +31,10 @@ func serviceFilter(serviceName string) filters.KeyValuePair {
 	return filters.Arg("label", fmt.Sprintf("%s=%s", api.ServiceLabel, serviceName))
 }
 
+func volumeFilter(name string) filters.KeyValuePair {
+	return filters.Arg("label", fmt.Sprintf("%s=%s", api.VolumeLabel, name))
+}
+
 func networkFilter(name string) filters.KeyValuePair {
 	return filters.Arg("label", fmt.Sprintf("%s=%s", api.NetworkLabel, name))
 }
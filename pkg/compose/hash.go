@@ -18,6 +18,9 @@ package compose
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/opencontainers/go-digest"
@@ -25,6 +28,15 @@ import (
 
 // ServiceHash computes the configuration hash for a service.
 func ServiceHash(o types.ServiceConfig) (string, error) {
+	bytes, err := json.Marshal(hashableService(o))
+	if err != nil {
+		return "", err
+	}
+	return digest.SHA256.FromBytes(bytes).Encoded(), nil
+}
+
+// hashableService removes attributes which don't impact the container configuration
+func hashableService(o types.ServiceConfig) types.ServiceConfig {
 	// remove the Build config when generating the service hash
 	o.Build = nil
 	o.PullPolicy = ""
@@ -34,12 +46,71 @@ func ServiceHash(o types.ServiceConfig) (string, error) {
 	}
 	o.DependsOn = nil
 	o.Profiles = nil
+	return o
+}
 
-	bytes, err := json.Marshal(o)
-	if err != nil {
-		return "", err
+// Service configuration aspects hashed separately, so we can tell which one changed
+const (
+	hashComponentImage       = "image"
+	hashComponentCommand     = "command"
+	hashComponentEnvironment = "environment"
+	hashComponentMounts      = "mounts"
+	hashComponentNetworks    = "networks"
+	hashComponentLabels      = "labels"
+	hashComponentOther       = "other"
+)
+
+// ServiceHashComponents computes a configuration hash for each distinct aspect of a service configuration.
+// Attributes not covered by a dedicated component are hashed as "other".
+func ServiceHashComponents(o types.ServiceConfig) (map[string]string, error) {
+	o = hashableService(o)
+	components := map[string]any{
+		hashComponentImage:       []any{o.Image, o.Platform},
+		hashComponentCommand:     []any{o.Entrypoint, o.Command, o.WorkingDir, o.User},
+		hashComponentEnvironment: []any{o.Environment},
+		hashComponentMounts:      []any{o.Volumes, o.Tmpfs, o.Configs, o.Secrets, o.VolumesFrom},
+		hashComponentNetworks:    []any{o.Networks, o.NetworkMode, o.Ports, o.Expose, o.Links, o.ExternalLinks, o.ExtraHosts, o.DNS, o.DNSOpts, o.DNSSearch, o.MacAddress},
+		hashComponentLabels:      []any{o.Labels, o.Annotations},
 	}
-	return digest.SHA256.FromBytes(bytes).Encoded(), nil
+	o.Image, o.Platform = "", ""
+	o.Entrypoint, o.Command, o.WorkingDir, o.User = nil, nil, "", ""
+	o.Environment = nil
+	o.Volumes, o.Tmpfs, o.Configs, o.Secrets, o.VolumesFrom = nil, nil, nil, nil, nil
+	o.Networks, o.NetworkMode, o.Ports, o.Expose, o.Links, o.ExternalLinks, o.ExtraHosts, o.DNS, o.DNSOpts, o.DNSSearch, o.MacAddress = nil, "", nil, nil, nil, nil, nil, nil, nil, nil, ""
+	o.Labels, o.Annotations = nil, nil
+	components[hashComponentOther] = o
+
+	hashes := map[string]string{}
+	for name, v := range components {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		// a short hash is enough to detect a change, and keeps label value readable
+		hashes[name] = digest.SHA256.FromBytes(bytes).Encoded()[:12]
+	}
+	return hashes, nil
+}
+
+// formatHashComponents serializes hash components as ConfigHashComponentsLabel value
+func formatHashComponents(components map[string]string) string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		parts = append(parts, name+"="+components[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseHashComponents parses ConfigHashComponentsLabel value
+func parseHashComponents(label string) map[string]string {
+	components := map[string]string{}
+	for _, part := range strings.Split(label, ",") {
+		name, hash, ok := strings.Cut(part, "=")
+		if ok {
+			components[name] = hash
+		}
+	}
+	return components
 }
 
 // NetworkHash computes the configuration hash for a network.
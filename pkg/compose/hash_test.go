@@ -41,3 +41,27 @@ func serviceConfig(replicas int) types.ServiceConfig {
 		Image: "bar",
 	}
 }
+
+func TestServiceHashComponents(t *testing.T) {
+	base := types.ServiceConfig{
+		Name:        "foo",
+		Image:       "bar",
+		Environment: types.NewMappingWithEquals([]string{"FOO=bar"}),
+	}
+	components, err := ServiceHashComponents(base)
+	assert.NilError(t, err)
+
+	updated := base
+	updated.Environment = types.NewMappingWithEquals([]string{"FOO=baz"})
+	changed, err := ServiceHashComponents(updated)
+	assert.NilError(t, err)
+	for name, hash := range components {
+		if name == hashComponentEnvironment {
+			assert.Assert(t, changed[name] != hash)
+		} else {
+			assert.Equal(t, changed[name], hash, name)
+		}
+	}
+
+	assert.DeepEqual(t, parseHashComponents(formatHashComponents(components)), components)
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ensureHelperImage pulls image used to run a compose-managed helper container, if missing
+func (s *composeService) ensureHelperImage(ctx context.Context, image string) error {
+	_, err := s.apiClient().ImageInspect(ctx, image)
+	if err == nil {
+		return nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return err
+	}
+	_, err = s.pullServiceImage(ctx, types.ServiceConfig{Name: image, Image: image}, true, "", defaultPullRetryPolicy)
+	return err
+}
+
+// runHelper runs a short-lived helper container to completion, and returns its standard output and exit code
+func (s *composeService) runHelper(ctx context.Context, config *container.Config, hostConfig *container.HostConfig) (string, int64, error) {
+	created, err := s.apiClient().ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() {
+		_ = s.apiClient().ContainerRemove(context.WithoutCancel(ctx), created.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if err := s.apiClient().ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return "", 0, err
+	}
+	var exitCode int64
+	waitC, errC := s.apiClient().ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case <-ctx.Done():
+		return "", 0, ctx.Err()
+	case err := <-errC:
+		return "", 0, err
+	case res := <-waitC:
+		exitCode = res.StatusCode
+	}
+
+	logs, err := s.apiClient().ContainerLogs(ctx, created.ID, container.LogsOptions{ShowStdout: true})
+	if err != nil {
+		return "", exitCode, err
+	}
+	defer logs.Close() //nolint:errcheck
+	var out bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &bytes.Buffer{}, logs); err != nil {
+		return "", exitCode, err
+	}
+	return out.String(), exitCode, nil
+}
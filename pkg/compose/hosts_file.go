@@ -0,0 +1,247 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config"
+	"github.com/gofrs/flock"
+	"github.com/sirupsen/logrus"
+)
+
+// HostnamesExtension declares the hostnames a service with published ports is reachable by from the host,
+// maintained in the hosts file by `up --sync-hosts` and removed by `down`
+const HostnamesExtension = "x-hostnames"
+
+// hostsFile is the path to the system hosts file
+var hostsFile = defaultHostsFile()
+
+func defaultHostsFile() string {
+	if runtime.GOOS == "windows" {
+		root := os.Getenv("SystemRoot")
+		if root == "" {
+			root = `C:\Windows`
+		}
+		return filepath.Join(root, "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// hostsEntry maps a hostname to the address a service publishes ports on
+type hostsEntry struct {
+	address  string
+	hostname string
+}
+
+func (e hostsEntry) String() string {
+	return e.address + " " + e.hostname
+}
+
+// getHostsEntries collects hostnames declared by services with HostnamesExtension. Hostnames resolve to the
+// host IP ports are published on, or to the loopback address when published on all interfaces.
+func getHostsEntries(project *types.Project) ([]hostsEntry, error) {
+	var entries []hostsEntry
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		var hostnames []string
+		ok, err := service.Extensions.Get(HostnamesExtension, &hostnames)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s: %w", name, HostnamesExtension, err)
+		}
+		if !ok || len(hostnames) == 0 {
+			continue
+		}
+		if len(service.Ports) == 0 {
+			logrus.Warnf("service %q declares %s but doesn't publish any port, hosts file entries are ignored", name, HostnamesExtension)
+			continue
+		}
+		address := "127.0.0.1"
+		if ip := service.Ports[0].HostIP; ip != "" && ip != "0.0.0.0" && ip != "::" {
+			address = ip
+		}
+		for _, hostname := range hostnames {
+			if hostname == "" || strings.ContainsAny(hostname, " \t\n#") {
+				return nil, fmt.Errorf("service %q: invalid hostname %q in %s", name, hostname, HostnamesExtension)
+			}
+			entries = append(entries, hostsEntry{address: address, hostname: hostname})
+		}
+	}
+	return entries, nil
+}
+
+func hostsBlockStart(projectName string) string {
+	return "# BEGIN docker compose project " + projectName
+}
+
+func hostsBlockEnd(projectName string) string {
+	return "# END docker compose project " + projectName
+}
+
+// renderHostsFile replaces the block of entries managed for project in the hosts file content. Block is removed
+// when there's no entry.
+func renderHostsFile(content string, projectName string, entries []hostsEntry) string {
+	start, end := hostsBlockStart(projectName), hostsBlockEnd(projectName)
+	var (
+		lines   []string
+		inBlock bool
+	)
+	for _, line := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case start:
+			inBlock = true
+			continue
+		case end:
+			inBlock = false
+			continue
+		}
+		if !inBlock && line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines[len(lines)-1] += "\n"
+	}
+	if len(entries) > 0 {
+		lines = append(lines, start+"\n")
+		for _, e := range entries {
+			lines = append(lines, e.String()+"\n")
+		}
+		lines = append(lines, end+"\n")
+	}
+	return strings.Join(lines, "")
+}
+
+// syncProjectHosts sets hosts file entries for the hostnames declared by project services
+func (s *composeService) syncProjectHosts(project *types.Project) error {
+	entries, err := getHostsEntries(project)
+	if err != nil {
+		return err
+	}
+	return s.syncHostsFile(project.Name, entries)
+}
+
+// hostsFileLock is the lock file, relative to docker config directory, compose holds while updating the hosts file,
+// so concurrent commands don't lose each other's entries
+const hostsFileLock = "compose/hosts.lock"
+
+// syncHostsFile updates the hosts file so it contains the entries for project, and only those
+func (s *composeService) syncHostsFile(projectName string, entries []hostsEntry) error {
+	lockFile := filepath.Join(config.Dir(), hostsFileLock)
+	if err := os.MkdirAll(filepath.Dir(lockFile), 0o700); err != nil {
+		return err
+	}
+	lock := flock.New(lockFile)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", lockFile, err)
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	content, err := os.ReadFile(hostsFile)
+	if errors.Is(err, os.ErrNotExist) && len(entries) == 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	updated := renderHostsFile(string(content), projectName, entries)
+	if updated == string(content) {
+		return nil
+	}
+	if s.dryRun {
+		return nil
+	}
+	return s.writeHostsFile(updated, entries)
+}
+
+// writeHostsFile replaces the hosts file, writing content to a temporary file next to it then renaming it, so that
+// resolvers never read a truncated file. When compose isn't allowed to, user is asked to consent to run
+// a privileged helper (sudo) to do the same
+func (s *composeService) writeHostsFile(content string, entries []hostsEntry) error {
+	target, err := filepath.EvalSymlinks(hostsFile)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	err = replaceFile(target, content, info.Mode().Perm())
+	if err == nil || !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("updating %s requires running from an elevated (administrator) terminal: %w", hostsFile, err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Updating %s requires administrator privileges, using sudo.", hostsFile)
+	if len(entries) > 0 {
+		sb.WriteString(" Entries to be set:")
+		for _, e := range entries {
+			sb.WriteString("\n  " + e.String())
+		}
+	} else {
+		sb.WriteString(" Entries set by compose will be removed.")
+	}
+	sb.WriteString("\nContinue?")
+	confirm, err := s.prompt(sb.String(), false)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		logrus.Warnf("%s not updated", hostsFile)
+		return nil
+	}
+	// same as replaceFile, content is read from stdin
+	script := `tmp=$(mktemp "$1.XXXXXX") && cat > "$tmp" && chmod "$2" "$tmp" && mv -f "$tmp" "$1" || { rm -f "$tmp"; exit 1; }`
+	cmd := exec.Command("sudo", "sh", "-c", script, "sh", target, fmt.Sprintf("%o", info.Mode().Perm()))
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = s.stderr()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to update %s: %w", hostsFile, err)
+	}
+	return nil
+}
+
+// replaceFile atomically replaces path with content, using a temporary file in the same directory
+func replaceFile(path string, content string, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	_, err = io.WriteString(tmp, content)
+	if err == nil {
+		err = tmp.Chmod(perm)
+	}
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetHostsEntries(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {
+				Name:       "web",
+				Ports:      []types.ServicePortConfig{{Target: 80, Published: "8080"}},
+				Extensions: types.Extensions{HostnamesExtension: []any{"app.test", "www.app.test"}},
+			},
+			"api": {
+				Name:       "api",
+				Ports:      []types.ServicePortConfig{{Target: 80, Published: "8081", HostIP: "127.0.0.2"}},
+				Extensions: types.Extensions{HostnamesExtension: []any{"api.test"}},
+			},
+			"db": {
+				Name:       "db",
+				Extensions: types.Extensions{HostnamesExtension: []any{"db.test"}},
+			},
+		},
+	}
+	entries, err := getHostsEntries(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, entries, []hostsEntry{
+		{address: "127.0.0.2", hostname: "api.test"},
+		{address: "127.0.0.1", hostname: "app.test"},
+		{address: "127.0.0.1", hostname: "www.app.test"},
+	}, cmp.AllowUnexported(hostsEntry{}))
+
+	project.Services["web"] = types.ServiceConfig{
+		Name:       "web",
+		Ports:      []types.ServicePortConfig{{Target: 80}},
+		Extensions: types.Extensions{HostnamesExtension: []any{"app test"}},
+	}
+	_, err = getHostsEntries(project)
+	assert.Error(t, err, `service "web": invalid hostname "app test" in x-hostnames`)
+}
+
+func TestRenderHostsFile(t *testing.T) {
+	content := "127.0.0.1 localhost\n\n# BEGIN docker compose project other\n127.0.0.1 other.test\n# END docker compose project other\n"
+	entries := []hostsEntry{{address: "127.0.0.1", hostname: "app.test"}}
+
+	updated := renderHostsFile(content, "demo", entries)
+	assert.Equal(t, updated, content+"# BEGIN docker compose project demo\n127.0.0.1 app.test\n# END docker compose project demo\n")
+
+	// rendering again replaces the project block
+	entries = append(entries, hostsEntry{address: "127.0.0.1", hostname: "www.app.test"})
+	assert.Equal(t, renderHostsFile(updated, "demo", entries),
+		content+"# BEGIN docker compose project demo\n127.0.0.1 app.test\n127.0.0.1 www.app.test\n# END docker compose project demo\n")
+
+	assert.Equal(t, renderHostsFile(updated, "demo", nil), content)
+	assert.Equal(t, renderHostsFile("127.0.0.1 localhost", "demo", entries[:1]),
+		"127.0.0.1 localhost\n# BEGIN docker compose project demo\n127.0.0.1 app.test\n# END docker compose project demo\n")
+}
+
+func TestSyncHostsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "hosts")
+	assert.NilError(t, os.WriteFile(file, []byte("127.0.0.1 localhost\n"), 0o640))
+	defaultFile := hostsFile
+	hostsFile = file
+	previousDir := config.Dir()
+	config.SetDir(t.TempDir())
+	t.Cleanup(func() {
+		hostsFile = defaultFile
+		config.SetDir(previousDir)
+	})
+
+	tested := composeService{}
+	assert.NilError(t, tested.syncHostsFile("demo", []hostsEntry{{address: "127.0.0.1", hostname: "app.test"}}))
+	content, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "127.0.0.1 localhost\n# BEGIN docker compose project demo\n127.0.0.1 app.test\n# END docker compose project demo\n")
+
+	assert.NilError(t, tested.syncHostsFile("demo", nil))
+	content, err = os.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "127.0.0.1 localhost\n")
+
+	// file is replaced with the same permissions, and no temporary file is left
+	info, err := os.Stat(file)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o640))
+	entries, err := os.ReadDir(filepath.Dir(file))
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+
+	// concurrent updates for distinct projects don't lose each other's entries
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Check(t, tested.syncHostsFile(fmt.Sprintf("demo%d", i), []hostsEntry{{address: "127.0.0.1", hostname: fmt.Sprintf("app%d.test", i)}}))
+		}()
+	}
+	wg.Wait()
+	content, err = os.ReadFile(file)
+	assert.NilError(t, err)
+	for i := range 10 {
+		assert.Check(t, strings.Contains(string(content), fmt.Sprintf("127.0.0.1 app%d.test\n", i)))
+	}
+}
@@ -100,6 +100,11 @@ func (s *composeService) Images(ctx context.Context, projectName string, options
 				created = &t
 			}
 
+			var labels map[string]string
+			if image.Config != nil {
+				labels = image.Config.Labels
+			}
+
 			mux.Lock()
 			defer mux.Unlock()
 			summary[getCanonicalContainerName(c)] = api.ImageSummary{
@@ -115,6 +120,8 @@ func (s *composeService) Images(ctx context.Context, projectName string, options
 				Size:        image.Size,
 				Created:     created,
 				LastTagTime: image.Metadata.LastTagTime,
+				Revision:    labels[api.GitRevisionLabel],
+				Branch:      labels[api.GitBranchLabel],
 			}
 			return nil
 		})
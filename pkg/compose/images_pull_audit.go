@@ -0,0 +1,134 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) ImagesPullAudit(ctx context.Context, project *types.Project, options api.ImagesOptions) ([]api.ImagePullAudit, error) {
+	var (
+		mu     sync.Mutex
+		audits []api.ImagePullAudit
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(s.maxConcurrency)
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if len(options.Services) > 0 && !slices.Contains(options.Services, name) {
+			continue
+		}
+		if service.Provider != nil || isExternalService(service) {
+			continue
+		}
+		eg.Go(func() error {
+			audit, err := s.auditServiceImage(ctx, project, service)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			audits = append(audits, audit)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(audits, func(a, b api.ImagePullAudit) int {
+		return strings.Compare(a.Service, b.Service)
+	})
+	return audits, nil
+}
+
+func (s *composeService) auditServiceImage(ctx context.Context, project *types.Project, service types.ServiceConfig) (api.ImagePullAudit, error) {
+	audit := api.ImagePullAudit{
+		Service:    service.Name,
+		Image:      api.GetImageNameOrDefault(service, project.Name),
+		PullPolicy: service.PullPolicy,
+	}
+	if audit.PullPolicy == "" {
+		audit.PullPolicy = types.PullPolicyMissing
+		if service.Image == "" {
+			// image can only be built
+			audit.PullPolicy = types.PullPolicyBuild
+		}
+	}
+
+	images := map[string]api.ImageSummary{}
+	inspect, err := s.apiClient().ImageInspect(ctx, audit.Image)
+	switch {
+	case errdefs.IsNotFound(err):
+	case err != nil:
+		return audit, err
+	default:
+		audit.Local = true
+		audit.LocalDigest = repoDigest(audit.Image, inspect.RepoDigests)
+		images[service.Image] = api.ImageSummary{ID: inspect.ID, LastTagTime: inspect.Metadata.LastTagTime}
+	}
+
+	willPull, err := mustPull(service, images)
+	if err != nil {
+		return audit, err
+	}
+	audit.WillPull = willPull
+
+	if service.Image == "" {
+		return audit, nil
+	}
+	named, err := reference.ParseDockerRef(service.Image)
+	if err != nil {
+		return audit, err
+	}
+	remote, err := ImageDigestResolver(ctx, s.configFile(), s.apiClient())(named)
+	if err != nil {
+		// image may not be published, or registry not reachable
+		audit.Error = err.Error()
+		return audit, nil
+	}
+	audit.RemoteDigest = remote.String()
+	audit.Stale = audit.LocalDigest != "" && audit.LocalDigest != audit.RemoteDigest
+	return audit, nil
+}
+
+// repoDigest returns the digest image has been pulled with from the repository of reference
+func repoDigest(ref string, repoDigests []string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+	for _, rd := range repoDigests {
+		canonical, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		if digested, ok := canonical.(reference.Digested); ok && canonical.Name() == named.Name() {
+			return digested.Digest().String()
+		}
+	}
+	return ""
+}
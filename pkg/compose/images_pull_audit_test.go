@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestImagesPullAudit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	tested := &composeService{dockerCli: cli, maxConcurrency: -1}
+
+	const (
+		current  = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		previous = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "nginx").Return(image.InspectResponse{
+		ID:          "sha256:nginx",
+		RepoDigests: []string{"nginx@" + previous},
+	}, nil)
+	apiClient.EXPECT().DistributionInspect(gomock.Any(), "docker.io/library/nginx:latest", gomock.Any()).
+		Return(registry.DistributionInspect{Descriptor: v1.Descriptor{Digest: current}}, nil)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "redis:7").Return(image.InspectResponse{
+		ID:          "sha256:redis",
+		RepoDigests: []string{"docker.io/library/redis@" + current},
+	}, nil)
+	apiClient.EXPECT().DistributionInspect(gomock.Any(), "docker.io/library/redis:7", gomock.Any()).
+		Return(registry.DistributionInspect{Descriptor: v1.Descriptor{Digest: current}}, nil)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "acme/api").Return(image.InspectResponse{}, errdefs.ErrNotFound)
+	apiClient.EXPECT().DistributionInspect(gomock.Any(), "docker.io/acme/api:latest", gomock.Any()).
+		Return(registry.DistributionInspect{}, errors.New("registry unavailable"))
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "test-worker").Return(image.InspectResponse{}, errdefs.ErrNotFound)
+
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"proxy":  {Name: "proxy", Image: "nginx", PullPolicy: types.PullPolicyAlways},
+			"cache":  {Name: "cache", Image: "redis:7"},
+			"api":    {Name: "api", Image: "acme/api"},
+			"worker": {Name: "worker", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	audits, err := tested.ImagesPullAudit(t.Context(), project, api.ImagesOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, audits, []api.ImagePullAudit{
+		{
+			Service:    "api",
+			Image:      "acme/api",
+			PullPolicy: types.PullPolicyMissing,
+			WillPull:   true,
+			Error:      "failed to resolve digest for docker.io/acme/api:latest: registry unavailable",
+		},
+		{
+			Service:      "cache",
+			Image:        "redis:7",
+			PullPolicy:   types.PullPolicyMissing,
+			Local:        true,
+			LocalDigest:  current,
+			RemoteDigest: current,
+		},
+		{
+			Service:      "proxy",
+			Image:        "nginx",
+			PullPolicy:   types.PullPolicyAlways,
+			Local:        true,
+			LocalDigest:  previous,
+			RemoteDigest: current,
+			Stale:        true,
+			WillPull:     true,
+		},
+		{
+			Service:    "worker",
+			Image:      "test-worker",
+			PullPolicy: types.PullPolicyBuild,
+		},
+	})
+}
@@ -0,0 +1,184 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) ImagesTree(ctx context.Context, projectName string, options api.ImagesOptions) (api.ImageTree, error) {
+	containers, err := s.getContainers(ctx, strings.ToLower(projectName), oneOffInclude, true, options.Services...)
+	if err != nil {
+		return api.ImageTree{}, err
+	}
+
+	// index services by the image they use, as each image must be inspected once
+	var (
+		ids      []string
+		names    = map[string]string{}
+		services = map[string][]string{}
+	)
+	for _, c := range containers.sorted() {
+		if _, ok := names[c.ImageID]; !ok {
+			ids = append(ids, c.ImageID)
+			names[c.ImageID] = c.Image
+		}
+		service := c.Labels[api.ServiceLabel]
+		if !slices.Contains(services[c.ImageID], service) {
+			services[c.ImageID] = append(services[c.ImageID], service)
+		}
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		return strings.Compare(names[a], names[b])
+	})
+
+	var (
+		tree   api.ImageTree
+		images []layeredImage
+		sizes  = map[string]int64{}
+	)
+	for _, id := range ids {
+		inspect, err := s.apiClient().ImageInspect(ctx, id)
+		if err != nil {
+			return api.ImageTree{}, err
+		}
+		history, err := s.apiClient().ImageHistory(ctx, id)
+		if err != nil {
+			return api.ImageTree{}, err
+		}
+		for layer, size := range layerSizes(inspect.RootFS.Layers, history) {
+			sizes[layer] = size
+		}
+		images = append(images, layeredImage{
+			name:     names[id],
+			services: services[id],
+			layers:   inspect.RootFS.Layers,
+		})
+		tree.TotalSize += inspect.Size
+	}
+	tree.Roots, tree.UniqueSize = buildImageTree(images, sizes)
+	return tree, nil
+}
+
+type layeredImage struct {
+	name     string
+	services []string
+	layers   []string
+}
+
+// layerSizes maps layers of an image to their size, as reported by image history. History has entries for
+// instructions which don't create a layer, those are ignored as they have no size. If a layer has zero size
+// it can't be matched with a history entry, then sizes are unknown.
+func layerSizes(layers []string, history []image.HistoryResponseItem) map[string]int64 {
+	var sizes []int64
+	// history lists most recent entries first
+	for _, h := range slices.Backward(history) {
+		if h.Size > 0 {
+			sizes = append(sizes, h.Size)
+		}
+	}
+	if len(sizes) != len(layers) {
+		return nil
+	}
+	m := map[string]int64{}
+	for i, layer := range layers {
+		m[layer] = sizes[i]
+	}
+	return m
+}
+
+// layerNode is a layer in a tree of images layers, identified by the chain of layers from the root
+type layerNode struct {
+	layer    string
+	children []*layerNode
+	image    string
+	services []string
+}
+
+func (n *layerNode) child(layer string) *layerNode {
+	for _, c := range n.children {
+		if c.layer == layer {
+			return c
+		}
+	}
+	c := &layerNode{layer: layer}
+	n.children = append(n.children, c)
+	return c
+}
+
+// buildImageTree organizes images as a tree of shared layers, and computes storage they consume
+func buildImageTree(images []layeredImage, sizes map[string]int64) ([]api.ImageTreeNode, int64) {
+	root := &layerNode{}
+	for _, img := range images {
+		if len(img.layers) == 0 {
+			continue
+		}
+		n := root
+		for _, layer := range img.layers {
+			n = n.child(layer)
+		}
+		n.image = img.name
+		n.services = append(n.services, img.services...)
+	}
+
+	var (
+		roots  []api.ImageTreeNode
+		unique int64
+	)
+	for _, c := range root.children {
+		node, size := toImageTreeNode(c, sizes)
+		roots = append(roots, node)
+		unique += size
+	}
+	return roots, unique
+}
+
+// toImageTreeNode merges a sequence of layers up to the next image or branch into a single node, and returns
+// the size of the whole subtree
+func toImageTreeNode(n *layerNode, sizes map[string]int64) (api.ImageTreeNode, int64) {
+	var node api.ImageTreeNode
+	for {
+		node.Layers++
+		size, ok := sizes[n.layer]
+		node.Size += size
+		if !ok {
+			node.SizeUnknown = true
+		}
+		if n.image != "" || len(n.children) != 1 {
+			break
+		}
+		n = n.children[0]
+	}
+	node.Image = n.image
+	node.Services = slices.Clone(n.services)
+	total := node.Size
+	for _, c := range n.children {
+		child, size := toImageTreeNode(c, sizes)
+		node.Children = append(node.Children, child)
+		node.Services = append(node.Services, child.Services...)
+		total += size
+	}
+	slices.Sort(node.Services)
+	node.Services = slices.Compact(node.Services)
+	return node, total
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestLayerSizes(t *testing.T) {
+	history := []image.HistoryResponseItem{
+		{CreatedBy: "CMD [\"app\"]"},
+		{CreatedBy: "COPY . /app", Size: 30},
+		{CreatedBy: "ENV A=b"},
+		{CreatedBy: "/bin/sh -c #(nop) ADD file:base", Size: 100},
+	}
+	assert.DeepEqual(t, layerSizes([]string{"base", "app"}, history), map[string]int64{"base": 100, "app": 30})
+	// a zero-size layer can't be matched with history
+	assert.Check(t, layerSizes([]string{"base", "empty", "app"}, history) == nil)
+}
+
+func TestBuildImageTree(t *testing.T) {
+	images := []layeredImage{
+		{name: "myproject-web", services: []string{"web"}, layers: []string{"os", "runtime", "web"}},
+		{name: "myproject-worker", services: []string{"worker"}, layers: []string{"os", "runtime", "worker1", "worker2"}},
+		{name: "postgres:16", services: []string{"db"}, layers: []string{"debian", "pg"}},
+	}
+	sizes := map[string]int64{"os": 50, "runtime": 25, "web": 100, "worker1": 10, "worker2": 20, "debian": 80}
+
+	roots, unique := buildImageTree(images, sizes)
+	assert.Equal(t, unique, int64(285))
+	assert.DeepEqual(t, roots, []api.ImageTreeNode{
+		{
+			Services: []string{"web", "worker"},
+			Layers:   2,
+			Size:     75,
+			Children: []api.ImageTreeNode{
+				{Image: "myproject-web", Services: []string{"web"}, Layers: 1, Size: 100},
+				{Image: "myproject-worker", Services: []string{"worker"}, Layers: 2, Size: 30},
+			},
+		},
+		{Image: "postgres:16", Services: []string{"db"}, Layers: 2, Size: 80, SizeUnknown: true},
+	})
+}
@@ -0,0 +1,292 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// ExposeHostExtension declares hostname(s) a service should be reachable by from the host,
+	// through the compose-managed ingress helper
+	ExposeHostExtension = "x-expose-host"
+	// IngressServiceName is the name of the compose-managed ingress service
+	IngressServiceName = "compose-ingress"
+	// IngressImage is the reverse-proxy image used by the ingress service, can be overridden by IngressImageEnvVar
+	IngressImage = "traefik:v3.1"
+	// IngressImageEnvVar selects the reverse-proxy image used by the ingress service, rather than IngressImage
+	IngressImageEnvVar = "COMPOSE_INGRESS_IMAGE"
+	// IngressPortExtension sets the host port the ingress service of the project listens to, so multiple projects
+	// exposing hosts can run side by side
+	IngressPortExtension = "x-ingress-port"
+	// IngressPort is the default host port the ingress service listens to, can be overridden by IngressPortExtension
+	// or IngressPortEnvVar
+	IngressPort = "80"
+	// IngressPortEnvVar selects the host port the ingress service listens to, with precedence over IngressPortExtension
+	IngressPortEnvVar = "COMPOSE_INGRESS_PORT"
+	// DefaultDockerSocket is the path engine socket is exposed by to containers on a default install and by Docker Desktop
+	DefaultDockerSocket = "/var/run/docker.sock"
+)
+
+// getIngressPort returns the host port ingress service listens to. IngressPortEnvVar has precedence over
+// IngressPortExtension, so a user can resolve a conflict without editing the compose file.
+func getIngressPort(project *types.Project) (string, error) {
+	if v, ok := project.Environment[IngressPortEnvVar]; ok && v != "" {
+		return validIngressPort(v, IngressPortEnvVar)
+	}
+	v, ok := project.Extensions[IngressPortExtension]
+	if !ok {
+		return IngressPort, nil
+	}
+	switch p := v.(type) {
+	case int:
+		return validIngressPort(strconv.Itoa(p), IngressPortExtension)
+	case string:
+		return validIngressPort(p, IngressPortExtension)
+	default:
+		return "", fmt.Errorf("invalid %s: expected a port number, got %v", IngressPortExtension, v)
+	}
+}
+
+func validIngressPort(port string, source string) (string, error) {
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return "", fmt.Errorf("invalid %s %q: expected a port number", source, port)
+	}
+	return port, nil
+}
+
+// getExposedHosts returns the hostnames declared by service with ExposeHostExtension, as a single value or a list
+func getExposedHosts(service types.ServiceConfig) ([]string, error) {
+	v, ok := service.Extensions[ExposeHostExtension]
+	if !ok {
+		return nil, nil
+	}
+	switch h := v.(type) {
+	case string:
+		return []string{h}, nil
+	case []any:
+		var hosts []string
+		for _, e := range h {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("service %q has invalid %s: expected string, got %T", service.Name, ExposeHostExtension, e)
+			}
+			hosts = append(hosts, s)
+		}
+		return hosts, nil
+	case []string:
+		return h, nil
+	default:
+		return nil, fmt.Errorf("service %q has invalid %s: expected string or list of strings", service.Name, ExposeHostExtension)
+	}
+}
+
+// applyIngress configures routing labels on services declaring ExposeHostExtension and adds the ingress service
+// routing requests to them. Exposed services depend on the ingress service, so its lifecycle is tied to them.
+// dockerSocket is only invoked when ingress service is needed, to get the engine socket it discovers services by.
+func applyIngress(project *types.Project, dockerSocket func() (string, error)) (*types.Project, error) {
+	var exposed []string
+	for name, service := range project.Services {
+		hosts, err := getExposedHosts(service)
+		if err != nil {
+			return nil, err
+		}
+		if len(hosts) == 0 {
+			continue
+		}
+		if name == IngressServiceName {
+			return nil, fmt.Errorf("service name %q is reserved for ingress", IngressServiceName)
+		}
+		port, err := getIngressTargetPort(service)
+		if err != nil {
+			return nil, err
+		}
+
+		var rules []string
+		for _, h := range hosts {
+			rules = append(rules, fmt.Sprintf("Host(`%s`)", h))
+			if !isLoopbackHostname(h) {
+				logrus.Infof("%s is exposed as %s, you may have to add \"127.0.0.1 %s\" to your hosts file", name, h, h)
+			}
+		}
+		router := project.Name + "-" + name
+		service.Labels = service.Labels.
+			Add("traefik.enable", "true").
+			Add(fmt.Sprintf("traefik.http.routers.%s.rule", router), strings.Join(rules, " || ")).
+			Add(fmt.Sprintf("traefik.http.routers.%s.entrypoints", router), "web").
+			Add(fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port", router), strconv.Itoa(int(port)))
+		networks := service.NetworksByPriority()
+		if len(networks) > 0 {
+			if nw, ok := project.Networks[networks[0]]; ok && nw.Name != "" {
+				service.Labels = service.Labels.Add("traefik.docker.network", nw.Name)
+			}
+		}
+		if service.DependsOn == nil {
+			service.DependsOn = types.DependsOnConfig{}
+		}
+		service.DependsOn[IngressServiceName] = types.ServiceDependency{
+			Condition: types.ServiceConditionStarted,
+			Required:  true,
+		}
+		project.Services[name] = service
+		exposed = append(exposed, name)
+	}
+	if len(exposed) == 0 {
+		return project, nil
+	}
+
+	port, err := getIngressPort(project)
+	if err != nil {
+		return nil, err
+	}
+	socket, err := dockerSocket()
+	if err != nil {
+		return nil, err
+	}
+	project.Services[IngressServiceName] = ingressService(project, exposed, port, socket)
+	return project, nil
+}
+
+// ingressDockerSocket returns the engine socket to be bind mounted in the ingress container, derived from the Docker
+// context endpoint. Docker Desktop, reached by a named pipe on Windows or by the desktop-linux context, exposes
+// engine to containers by DefaultDockerSocket. Remote engines are not supported, as there's no way to know where
+// engine socket is on the remote host.
+func (s *composeService) ingressDockerSocket() (string, error) {
+	if s.dockerCli == nil {
+		return DefaultDockerSocket, nil
+	}
+	contextName := s.dockerCli.CurrentContext()
+	if contextName == "desktop-linux" {
+		return DefaultDockerSocket, nil
+	}
+	host := s.dockerCli.DockerEndpoint().Host
+	switch {
+	case host == "", strings.HasPrefix(host, "npipe://"):
+		return DefaultDockerSocket, nil
+	case strings.HasPrefix(host, "unix://"):
+		return strings.TrimPrefix(host, "unix://"), nil
+	default:
+		return "", fmt.Errorf("%s requires a local Docker engine, but docker context %q uses %s", ExposeHostExtension, contextName, host)
+	}
+}
+
+func ingressService(project *types.Project, exposed []string, port string, socket string) types.ServiceConfig {
+	image := IngressImage
+	if v, ok := project.Environment[IngressImageEnvVar]; ok && v != "" {
+		image = v
+	}
+
+	// ingress must be connected to all networks exposed services are attached to
+	networks := map[string]*types.ServiceNetworkConfig{}
+	for _, name := range exposed {
+		service := project.Services[name]
+		if len(service.Networks) == 0 {
+			networks["default"] = nil
+		}
+		for nw := range service.Networks {
+			networks[nw] = nil
+		}
+	}
+
+	return types.ServiceConfig{
+		Name:  IngressServiceName,
+		Image: image,
+		Command: []string{
+			"--providers.docker=true",
+			"--providers.docker.exposedbydefault=false",
+			fmt.Sprintf("--providers.docker.constraints=Label(`%s`,`%s`)", api.ProjectLabel, project.Name),
+			"--entrypoints.web.address=:80",
+		},
+		Ports: []types.ServicePortConfig{{
+			Mode:      "ingress",
+			Target:    80,
+			Published: port,
+			Protocol:  "tcp",
+		}},
+		Volumes: []types.ServiceVolumeConfig{{
+			Type:     types.VolumeTypeBind,
+			Source:   socket,
+			Target:   DefaultDockerSocket,
+			ReadOnly: true,
+		}},
+		Networks: networks,
+		Restart:  types.RestartPolicyUnlessStopped,
+	}
+}
+
+// getIngressTargetPort selects the container port ingress routes requests to
+func getIngressTargetPort(service types.ServiceConfig) (uint32, error) {
+	if len(service.Ports) > 0 {
+		return service.Ports[0].Target, nil
+	}
+	for _, e := range service.Expose {
+		p, _, _ := strings.Cut(e, "/")
+		p, _, _ = strings.Cut(p, "-")
+		port, err := strconv.ParseUint(p, 10, 32)
+		if err == nil {
+			return uint32(port), nil
+		}
+	}
+	return 0, fmt.Errorf("service %q declares %s but no port to route requests to, use `ports` or `expose`", service.Name, ExposeHostExtension)
+}
+
+// isLoopbackHostname checks a hostname is resolved to loopback address by most systems resolvers (RFC 6761)
+func isLoopbackHostname(host string) bool {
+	return host == "localhost" || strings.HasSuffix(host, ".localhost")
+}
+
+// checkIngressPort reports an actionable error when the ingress port is already used by the ingress of another
+// project, rather than the engine failing to start the ingress container
+func (s *composeService) checkIngressPort(ctx context.Context, project *types.Project) error {
+	ingress, ok := project.Services[IngressServiceName]
+	if !ok || len(ingress.Ports) == 0 {
+		return nil
+	}
+	port, err := strconv.ParseUint(ingress.Ports[0].Published, 10, 16)
+	if err != nil {
+		return nil
+	}
+	containers, err := s.apiClient().ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(serviceFilter(IngressServiceName)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		other := c.Labels[api.ProjectLabel]
+		if other == project.Name {
+			continue
+		}
+		for _, p := range c.Ports {
+			if uint64(p.PublicPort) == port {
+				return fmt.Errorf("port %d is already used by the ingress of project %q, set %s in the compose file "+
+					"or %s to select another one", port, other, IngressPortExtension, IngressPortEnvVar)
+			}
+		}
+	}
+	return nil
+}
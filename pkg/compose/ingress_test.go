@@ -0,0 +1,172 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/context/docker"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func defaultDockerSocket() (string, error) {
+	return DefaultDockerSocket, nil
+}
+
+func TestApplyIngress(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				Ports:      []types.ServicePortConfig{{Target: 8080, Published: "8080"}},
+				Networks:   map[string]*types.ServiceNetworkConfig{"default": nil},
+				Extensions: types.Extensions{ExposeHostExtension: "app.localhost"},
+			},
+			"api": {
+				Name:       "api",
+				Expose:     types.StringOrNumberList{"3000/tcp"},
+				Networks:   map[string]*types.ServiceNetworkConfig{"back": nil},
+				Extensions: types.Extensions{ExposeHostExtension: []any{"api.localhost", "api.test"}},
+			},
+			"db": {
+				Name: "db",
+			},
+		},
+		Networks: types.Networks{
+			"default": {Name: "demo_default"},
+			"back":    {Name: "demo_back"},
+		},
+	}
+	project, err := applyIngress(project, defaultDockerSocket)
+	assert.NilError(t, err)
+
+	app := project.Services["app"]
+	assert.Equal(t, app.Labels["traefik.enable"], "true")
+	assert.Equal(t, app.Labels["traefik.http.routers.demo-app.rule"], "Host(`app.localhost`)")
+	assert.Equal(t, app.Labels["traefik.http.services.demo-app.loadbalancer.server.port"], "8080")
+	assert.Equal(t, app.Labels["traefik.docker.network"], "demo_default")
+	assert.Equal(t, app.DependsOn[IngressServiceName].Condition, types.ServiceConditionStarted)
+
+	api := project.Services["api"]
+	assert.Equal(t, api.Labels["traefik.http.routers.demo-api.rule"], "Host(`api.localhost`) || Host(`api.test`)")
+	assert.Equal(t, api.Labels["traefik.http.services.demo-api.loadbalancer.server.port"], "3000")
+
+	_, ok := project.Services["db"].Labels["traefik.enable"]
+	assert.Assert(t, !ok)
+
+	ingress, ok := project.Services[IngressServiceName]
+	assert.Assert(t, ok)
+	assert.Equal(t, ingress.Image, IngressImage)
+	assert.Equal(t, ingress.Ports[0].Published, IngressPort)
+	assert.Equal(t, ingress.Volumes[0].Source, DefaultDockerSocket)
+	assert.DeepEqual(t, ingress.NetworksByPriority(), []string{"back", "default"})
+}
+
+func TestApplyIngressWithoutPort(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				Extensions: types.Extensions{ExposeHostExtension: "app.localhost"},
+			},
+		},
+	}
+	_, err := applyIngress(project, defaultDockerSocket)
+	assert.ErrorContains(t, err, "no port to route requests to")
+}
+
+func TestApplyIngressNotUsed(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {Name: "app"},
+		},
+	}
+	project, err := applyIngress(project, func() (string, error) {
+		return "", errors.New("engine socket is not needed")
+	})
+	assert.NilError(t, err)
+	_, ok := project.Services[IngressServiceName]
+	assert.Assert(t, !ok)
+}
+
+func TestIngressPort(t *testing.T) {
+	project := &types.Project{
+		Name:       "demo",
+		Extensions: types.Extensions{IngressPortExtension: 8080},
+		Services: types.Services{
+			"app": {
+				Name:       "app",
+				Ports:      []types.ServicePortConfig{{Target: 8080}},
+				Extensions: types.Extensions{ExposeHostExtension: "app.localhost"},
+			},
+		},
+	}
+	project, err := applyIngress(project, defaultDockerSocket)
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services[IngressServiceName].Ports[0].Published, "8080")
+
+	project.Environment = types.Mapping{IngressPortEnvVar: "9090"}
+	port, err := getIngressPort(project)
+	assert.NilError(t, err)
+	assert.Equal(t, port, "9090")
+
+	project.Environment = nil
+	project.Extensions[IngressPortExtension] = "http"
+	_, err = getIngressPort(project)
+	assert.Error(t, err, `invalid x-ingress-port "http": expected a port number`)
+}
+
+func TestIngressDockerSocket(t *testing.T) {
+	tests := []struct {
+		context string
+		host    string
+		socket  string
+		err     string
+	}{
+		{context: "default", host: "unix:///var/run/docker.sock", socket: "/var/run/docker.sock"},
+		{context: "rootless", host: "unix:///run/user/1000/docker.sock", socket: "/run/user/1000/docker.sock"},
+		{context: "desktop-linux", host: "unix:///Users/me/.docker/run/docker.sock", socket: DefaultDockerSocket},
+		{context: "default", host: "npipe:////./pipe/docker_engine", socket: DefaultDockerSocket},
+		{context: "remote", host: "ssh://me@remote", err: `x-expose-host requires a local Docker engine, but docker context "remote" uses ssh://me@remote`},
+		{context: "default", host: "tcp://remote:2376", err: `x-expose-host requires a local Docker engine, but docker context "default" uses tcp://remote:2376`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			cli := mocks.NewMockCli(mockCtrl)
+			cli.EXPECT().CurrentContext().Return(tt.context).AnyTimes()
+			cli.EXPECT().DockerEndpoint().Return(docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: tt.host}}).AnyTimes()
+			tested := composeService{dockerCli: cli}
+
+			socket, err := tested.ingressDockerSocket()
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, socket, tt.socket)
+		})
+	}
+}
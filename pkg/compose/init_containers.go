@@ -0,0 +1,213 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// InitContainersExtension is the service extension declaring containers to run to completion before service starts
+const InitContainersExtension = "x-init-containers"
+
+// InitContainerConfig describes a short-lived container sharing service volumes and networks,
+// which must complete successfully before the service container is started
+type InitContainerConfig struct {
+	Name string `mapstructure:"name"`
+	// Image to run, defaults to the service image
+	Image       string            `mapstructure:"image"`
+	Command     []string          `mapstructure:"command"`
+	Entrypoint  []string          `mapstructure:"entrypoint"`
+	Environment map[string]string `mapstructure:"environment"`
+	User        string            `mapstructure:"user"`
+	WorkingDir  string            `mapstructure:"working_dir"`
+}
+
+// getInitContainers returns the init containers declared by service, with defaults applied
+func getInitContainers(service types.ServiceConfig) ([]InitContainerConfig, error) {
+	var inits []InitContainerConfig
+	if _, err := service.Extensions.Get(InitContainersExtension, &inits); err != nil {
+		return nil, fmt.Errorf("service %q has invalid %s: %w", service.Name, InitContainersExtension, err)
+	}
+	names := map[string]bool{}
+	for i, init := range inits {
+		if init.Name == "" {
+			init.Name = strconv.Itoa(i + 1)
+		}
+		if names[init.Name] {
+			return nil, fmt.Errorf("service %q declares init container %q more than once", service.Name, init.Name)
+		}
+		names[init.Name] = true
+		inits[i] = init
+	}
+	return inits, nil
+}
+
+// toInitService derives the ServiceConfig used to create an init container. Volumes, networks
+// and resources are inherited from service, while lifecycle related attributes are reset.
+func toInitService(service types.ServiceConfig, init InitContainerConfig) types.ServiceConfig {
+	initService := service
+	if init.Image != "" {
+		initService.Image = init.Image
+	}
+	initService.ContainerName = ""
+	initService.Entrypoint = init.Entrypoint
+	initService.Command = init.Command
+	if init.Entrypoint != nil && init.Command == nil {
+		initService.Command = []string{}
+	}
+	if init.User != "" {
+		initService.User = init.User
+	}
+	if init.WorkingDir != "" {
+		initService.WorkingDir = init.WorkingDir
+	}
+	env := types.MappingWithEquals{}
+	for k, v := range service.Environment {
+		env[k] = v
+	}
+	for k, v := range init.Environment {
+		env[k] = &v
+	}
+	initService.Environment = env
+	initService.Ports = nil
+	initService.Expose = nil
+	initService.HealthCheck = &types.HealthCheckConfig{Disable: true}
+	initService.Restart = ""
+	initService.PostStart = nil
+	initService.PreStop = nil
+	initService.Tty = false
+	initService.StdinOpen = false
+	if service.Deploy != nil {
+		deploy := *service.Deploy
+		deploy.RestartPolicy = nil
+		initService.Deploy = &deploy
+	}
+	return initService
+}
+
+func getInitContainerName(projectName string, service types.ServiceConfig, init InitContainerConfig) string {
+	return strings.Join([]string{projectName, service.Name, "init", init.Name}, api.Separator)
+}
+
+// runInitContainers runs service's init containers sequentially, each one being required to complete successfully
+func (s *composeService) runInitContainers(ctx context.Context, project *types.Project, service types.ServiceConfig, listener api.ContainerEventListener) error {
+	inits, err := getInitContainers(service)
+	if err != nil {
+		return err
+	}
+	for _, init := range inits {
+		if err := s.runInitContainer(ctx, project, service, init, listener); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *composeService) runInitContainer(ctx context.Context, project *types.Project, service types.ServiceConfig, init InitContainerConfig, listener api.ContainerEventListener) error {
+	name := getInitContainerName(project.Name, service, init)
+	return s.runToCompletion(ctx, project, service, init, name, api.InitContainerLabel, listener, nil)
+}
+
+// runToCompletion runs a one-off container derived from service by init, labeled with label, and waits for it
+// to complete successfully. When retain is set, container is kept once it completed successfully, with these
+// additional labels, so completion can be checked by later runs
+func (s *composeService) runToCompletion(ctx context.Context, project *types.Project, service types.ServiceConfig, init InitContainerConfig,
+	name string, label string, listener api.ContainerEventListener, retain types.Labels,
+) (err error) {
+	eventName := "Container " + name
+
+	// remove leftover from a previous interrupted run
+	stale, err := s.getContainers(ctx, project.Name, oneOffOnly, true, service.Name)
+	if err != nil {
+		return err
+	}
+	for _, ctr := range stale.filter(hasLabelValue(label, init.Name)) {
+		if err := s.apiClient().ContainerRemove(ctx, ctr.ID, container.RemoveOptions{Force: true}); err != nil {
+			return err
+		}
+	}
+
+	initService := toInitService(service, init)
+	labels := mergeLabels(service.Labels, service.CustomLabels, retain).
+		Add(api.OneoffLabel, "True").
+		Add(label, init.Name)
+	ctr, err := s.createContainer(ctx, project, initService, name, -1, createOptions{Labels: labels})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retain != nil && err == nil {
+			return
+		}
+		_ = s.apiClient().ContainerRemove(context.WithoutCancel(ctx), ctr.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if listener != nil {
+		err = s.doAttachContainer(ctx, service.Name, ctr.ID, strings.TrimPrefix(name, project.Name+api.Separator), listener)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.events.On(startingEvent(eventName))
+	err = s.apiClient().ContainerStart(ctx, ctr.ID, container.StartOptions{})
+	if err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	if s.dryRun {
+		s.events.On(exited(eventName))
+		return nil
+	}
+
+	waitC, errC := s.apiClient().ContainerWait(ctx, ctr.ID, container.WaitConditionNotRunning)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errC:
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	case res := <-waitC:
+		if res.StatusCode != 0 {
+			s.events.On(errorEventf(eventName, "exited with code %d", res.StatusCode))
+			return fmt.Errorf("service %q %s %q didn't complete successfully: exit %d", service.Name, initKind(label), init.Name, res.StatusCode)
+		}
+	}
+	s.events.On(exited(eventName))
+	return nil
+}
+
+func hasLabelValue(label string, value string) containerPredicate {
+	return func(c container.Summary) bool {
+		return c.Labels[label] == value
+	}
+}
+
+func initKind(label string) string {
+	if label == api.InitJobLabel {
+		return "init job"
+	}
+	return "init container"
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetInitContainers(t *testing.T) {
+	service := types.ServiceConfig{
+		Name:  "app",
+		Image: "app:latest",
+		Extensions: types.Extensions{
+			InitContainersExtension: []any{
+				map[string]any{
+					"name":    "migrate",
+					"image":   "migrate:latest",
+					"command": []any{"migrate", "up"},
+				},
+				map[string]any{
+					"command": []any{"touch", "/data/ready"},
+				},
+			},
+		},
+	}
+	inits, err := getInitContainers(service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(inits), 2)
+	assert.Equal(t, inits[0].Name, "migrate")
+	assert.DeepEqual(t, inits[0].Command, []string{"migrate", "up"})
+	assert.Equal(t, inits[1].Name, "2")
+	assert.Equal(t, inits[1].Image, "")
+}
+
+func TestGetInitContainersDuplicateName(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "app",
+		Extensions: types.Extensions{
+			InitContainersExtension: []any{
+				map[string]any{"name": "setup"},
+				map[string]any{"name": "setup"},
+			},
+		},
+	}
+	_, err := getInitContainers(service)
+	assert.ErrorContains(t, err, `declares init container "setup" more than once`)
+}
+
+func TestToInitService(t *testing.T) {
+	value := "bar"
+	service := types.ServiceConfig{
+		Name:        "app",
+		Image:       "app:latest",
+		Command:     []string{"serve"},
+		Restart:     types.RestartPolicyAlways,
+		Environment: types.MappingWithEquals{"FOO": &value},
+		Ports:       []types.ServicePortConfig{{Target: 80}},
+		Volumes:     []types.ServiceVolumeConfig{{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"}},
+	}
+	initService := toInitService(service, InitContainerConfig{
+		Name:        "seed",
+		Entrypoint:  []string{"/seed.sh"},
+		Environment: map[string]string{"SEED": "true"},
+	})
+	assert.Equal(t, initService.Image, "app:latest")
+	assert.DeepEqual(t, []string(initService.Entrypoint), []string{"/seed.sh"})
+	assert.DeepEqual(t, []string(initService.Command), []string{})
+	assert.Equal(t, initService.Restart, "")
+	assert.Equal(t, len(initService.Ports), 0)
+	assert.Equal(t, len(initService.Volumes), 1)
+	assert.Equal(t, *initService.Environment["FOO"], "bar")
+	assert.Equal(t, *initService.Environment["SEED"], "true")
+	assert.Assert(t, initService.HealthCheck.Disable)
+	// service environment must not be altered
+	_, ok := service.Environment["SEED"]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// InitJobsExtension is the service extension declaring jobs to run once per volume lifetime, after service started,
+// typically to seed a database
+const InitJobsExtension = "x-init-jobs"
+
+// InitJobConfig describes a one-off container run after service started, once per lifetime of the tracked volume
+type InitJobConfig struct {
+	InitContainerConfig `mapstructure:",squash"`
+	// Volume tracks job completion, job runs again once volume has been re-created.
+	// Defaults to the first named volume mounted by service
+	Volume string `mapstructure:"volume"`
+}
+
+// getInitJobs returns the init jobs declared by service, with defaults applied
+func getInitJobs(project *types.Project, service types.ServiceConfig) ([]InitJobConfig, error) {
+	var jobs []InitJobConfig
+	if _, err := service.Extensions.Get(InitJobsExtension, &jobs); err != nil {
+		return nil, fmt.Errorf("service %q has invalid %s: %w", service.Name, InitJobsExtension, err)
+	}
+	names := map[string]bool{}
+	for i, job := range jobs {
+		if job.Name == "" {
+			job.Name = strconv.Itoa(i + 1)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("service %q declares init job %q more than once", service.Name, job.Name)
+		}
+		names[job.Name] = true
+		if job.Volume == "" {
+			for _, v := range service.Volumes {
+				if _, ok := project.Volumes[v.Source]; ok && v.Type == types.VolumeTypeVolume {
+					job.Volume = v.Source
+					break
+				}
+			}
+			if job.Volume == "" {
+				return nil, fmt.Errorf("service %q init job %q requires service to mount a named volume", service.Name, job.Name)
+			}
+		}
+		if _, ok := project.Volumes[job.Volume]; !ok {
+			return nil, fmt.Errorf("service %q init job %q refers to undefined volume %q", service.Name, job.Name, job.Volume)
+		}
+		jobs[i] = job
+	}
+	return jobs, nil
+}
+
+func getInitJobName(projectName string, service types.ServiceConfig, job InitJobConfig) string {
+	return strings.Join([]string{projectName, service.Name, "job", job.Name}, api.Separator)
+}
+
+// runInitJobs runs service's init jobs which haven't completed yet for the current tracked volume, once service is
+// running, or healthy if it declares a healthcheck
+func (s *composeService) runInitJobs(ctx context.Context, project *types.Project, service types.ServiceConfig,
+	containers Containers, listener api.ContainerEventListener, timeout time.Duration,
+) error {
+	jobs, err := getInitJobs(project, service)
+	if err != nil || len(jobs) == 0 {
+		return err
+	}
+	var pending []InitJobConfig
+	volumes := map[string]string{}
+	for _, job := range jobs {
+		name := getInitJobName(project.Name, service, job)
+		volume, err := s.initJobVolume(ctx, project, job)
+		if err != nil {
+			return err
+		}
+		volumes[job.Name] = volume
+		done, err := s.isInitJobCompleted(ctx, project, service, job, volume)
+		if err != nil {
+			return err
+		}
+		if done {
+			s.events.On(newEvent("Container "+name, api.Done, "Already initialized", "volume "+job.Volume))
+			continue
+		}
+		pending = append(pending, job)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err = s.waitDependencies(ctx, project, service.Name+" init jobs", types.DependsOnConfig{
+		service.Name: {Condition: ServiceConditionRunningOrHealthy, Required: true},
+	}, containers, timeout)
+	if err != nil {
+		return err
+	}
+	for _, job := range pending {
+		name := getInitJobName(project.Name, service, job)
+		// job container is retained once completed, recording the volume it initialized
+		retain := types.Labels{api.InitJobVolumeLabel: volumes[job.Name]}
+		if err := s.runToCompletion(ctx, project, service, job.InitContainerConfig, name, api.InitJobLabel, listener, retain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initJobVolume identifies the current instance of the volume tracked by job, by name and creation date, so a job
+// runs again once volume has been re-created
+func (s *composeService) initJobVolume(ctx context.Context, project *types.Project, job InitJobConfig) (string, error) {
+	name := project.Volumes[job.Volume].Name
+	if s.dryRun {
+		return name, nil
+	}
+	volume, err := s.apiClient().VolumeInspect(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return name + "@" + volume.CreatedAt, nil
+}
+
+// isInitJobCompleted checks for a container retained by a successful run of job for the current tracked volume
+func (s *composeService) isInitJobCompleted(ctx context.Context, project *types.Project, service types.ServiceConfig, job InitJobConfig, volume string) (bool, error) {
+	if s.dryRun {
+		return false, nil
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffOnly, true, service.Name)
+	if err != nil {
+		return false, err
+	}
+	for _, ctr := range containers.filter(hasLabelValue(api.InitJobLabel, job.Name), hasLabelValue(api.InitJobVolumeLabel, volume)) {
+		inspect, err := s.apiClient().ContainerInspect(ctx, ctr.ID)
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		// container might have been left by an interrupted run
+		if inspect.State != nil && inspect.State.Status == container.StateExited && inspect.State.ExitCode == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestGetInitJobs(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Volumes: types.Volumes{
+			"data":  {Name: "demo_data"},
+			"seeds": {Name: "demo_seeds"},
+		},
+	}
+	service := types.ServiceConfig{
+		Name:  "db",
+		Image: "postgres",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: types.VolumeTypeBind, Source: "/src", Target: "/src"},
+			{Type: types.VolumeTypeVolume, Source: "data", Target: "/var/lib/postgresql/data"},
+			{Type: types.VolumeTypeVolume, Source: "seeds", Target: "/seeds"},
+		},
+		Extensions: types.Extensions{
+			InitJobsExtension: []any{
+				map[string]any{
+					"name":    "seed",
+					"command": []any{"psql", "-h", "db", "-f", "/seeds/seed.sql"},
+				},
+				map[string]any{
+					"image":  "busybox",
+					"volume": "seeds",
+				},
+			},
+		},
+	}
+	jobs, err := getInitJobs(project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(jobs), 2)
+	assert.Equal(t, jobs[0].Name, "seed")
+	assert.DeepEqual(t, jobs[0].Command, []string{"psql", "-h", "db", "-f", "/seeds/seed.sql"})
+	assert.Equal(t, jobs[0].Volume, "data")
+	assert.Equal(t, jobs[1].Name, "2")
+	assert.Equal(t, jobs[1].Image, "busybox")
+	assert.Equal(t, jobs[1].Volume, "seeds")
+
+	assert.Equal(t, getInitJobName(project.Name, service, jobs[0]), "demo-db-job-seed")
+}
+
+func TestGetInitJobsInvalid(t *testing.T) {
+	project := &types.Project{
+		Volumes: types.Volumes{"data": {Name: "demo_data"}},
+	}
+	tests := []struct {
+		name    string
+		service types.ServiceConfig
+		err     string
+	}{
+		{
+			name: "no named volume",
+			service: types.ServiceConfig{
+				Name: "db",
+				Extensions: types.Extensions{
+					InitJobsExtension: []any{map[string]any{"name": "seed"}},
+				},
+			},
+			err: `init job "seed" requires service to mount a named volume`,
+		},
+		{
+			name: "undefined volume",
+			service: types.ServiceConfig{
+				Name: "db",
+				Extensions: types.Extensions{
+					InitJobsExtension: []any{map[string]any{"name": "seed", "volume": "other"}},
+				},
+			},
+			err: `init job "seed" refers to undefined volume "other"`,
+		},
+		{
+			name: "duplicate name",
+			service: types.ServiceConfig{
+				Name: "db",
+				Extensions: types.Extensions{
+					InitJobsExtension: []any{
+						map[string]any{"name": "seed", "volume": "data"},
+						map[string]any{"name": "seed", "volume": "data"},
+					},
+				},
+			},
+			err: `declares init job "seed" more than once`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getInitJobs(project, tt.service)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestIsInitJobCompleted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	project := &types.Project{Name: testProject}
+	service := types.ServiceConfig{Name: "db"}
+	job := InitJobConfig{InitContainerConfig: InitContainerConfig{Name: "seed"}, Volume: "data"}
+
+	seeded := testContainer("db", "job1", true)
+	seeded.Labels[api.InitJobLabel] = "seed"
+	seeded.Labels[api.InitJobVolumeLabel] = "demo_data@2026-10-01T10:00:00Z"
+	interrupted := testContainer("db", "job2", true)
+	interrupted.Labels[api.InitJobLabel] = "seed"
+	interrupted.Labels[api.InitJobVolumeLabel] = "demo_data@2026-10-02T10:00:00Z"
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).
+		Return([]container.Summary{seeded, interrupted}, nil).AnyTimes()
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "job1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    "job1",
+			State: &container.State{Status: container.StateExited, ExitCode: 0},
+		},
+	}, nil).AnyTimes()
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "job2").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    "job2",
+			State: &container.State{Status: container.StateExited, ExitCode: 137},
+		},
+	}, nil).AnyTimes()
+
+	done, err := tested.isInitJobCompleted(t.Context(), project, service, job, "demo_data@2026-10-01T10:00:00Z")
+	assert.NilError(t, err)
+	assert.Check(t, done)
+
+	done, err = tested.isInitJobCompleted(t.Context(), project, service, job, "demo_data@2026-10-02T10:00:00Z")
+	assert.NilError(t, err)
+	assert.Check(t, !done, "job interrupted before completion must run again")
+
+	done, err = tested.isInitJobCompleted(t.Context(), project, service, job, "demo_data@2026-10-03T10:00:00Z")
+	assert.NilError(t, err)
+	assert.Check(t, !done, "job must run again once volume has been re-created")
+}
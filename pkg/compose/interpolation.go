@@ -0,0 +1,334 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/template"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// InterpolationFunctionsExtension is the project extension enabling functions in variable interpolation:
+//
+//	x-interpolation-functions: true
+//	services:
+//	  app:
+//	    image: app:${TAG | lower}
+//	    environment:
+//	      TOKEN: ${TOKEN | file(./secrets/token) | trim | b64encode}
+const InterpolationFunctionsExtension = "x-interpolation-functions"
+
+// interpolationFunctions are the functions available to interpolation, applied to the value on their left
+var interpolationFunctions = map[string]func(value string, arg string, dir string) (string, error){
+	"lower": func(value string, _ string, _ string) (string, error) {
+		return strings.ToLower(value), nil
+	},
+	"upper": func(value string, _ string, _ string) (string, error) {
+		return strings.ToUpper(value), nil
+	},
+	"trim": func(value string, _ string, _ string) (string, error) {
+		return strings.TrimSpace(value), nil
+	},
+	"b64encode": func(value string, _ string, _ string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(value)), nil
+	},
+	// file reads the default value from a file, when value is unset or empty
+	"file": func(value string, path string, dir string) (string, error) {
+		if value != "" {
+			return value, nil
+		}
+		if path == "" {
+			return "", fmt.Errorf("file() requires a path")
+		}
+		if !filepath.IsAbs(path) {
+			if dir == "" {
+				return "", fmt.Errorf("file(): relative path %q can't be used by a remote compose file", path)
+			}
+			path = filepath.Join(dir, path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("file(): %w", err)
+		}
+		return string(content), nil
+	},
+}
+
+// interpolationFunctionsWithArg are the functions which require an argument
+var interpolationFunctionsWithArg = []string{"file"}
+
+// interpolationDirKey is looked up in the interpolation mapping of compose files set by project options, to get the
+// directory file() resolves relative paths against. It isn't a valid variable name, so can't be set by user.
+const interpolationDirKey = "compose:interpolation-dir"
+
+// WithInterpolationFunctions enables functions in interpolation when a local compose file declares
+// InterpolationFunctionsExtension. Otherwise, using them is reported as an error explaining how to enable them.
+func WithInterpolationFunctions(o *cli.ProjectOptions) error {
+	enabled := slices.ContainsFunc(localModels(o), func(model map[string]any) bool {
+		v, ok := model[InterpolationFunctionsExtension].(bool)
+		return ok && v
+	})
+	dir := o.WorkingDir
+	if dir == "" && len(o.ConfigPaths) > 0 {
+		dir = filepath.Dir(o.ConfigPaths[0])
+	}
+	return cli.WithLoadOptions(func(options *loader.Options) {
+		if options.Interpolate == nil {
+			return
+		}
+		substitute := options.Interpolate.Substitute
+		if substitute == nil {
+			substitute = template.Substitute
+		}
+		lookup := options.Interpolate.LookupValue
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		options.Interpolate.LookupValue = func(key string) (string, bool) {
+			if key == interpolationDirKey {
+				return dir, true
+			}
+			return lookup(key)
+		}
+		// included files are interpolated with their own mapping, right after loader notified about the include
+		var includeDir string
+		options.Listeners = append(options.Listeners, func(event string, metadata map[string]any) {
+			if event == "include" {
+				includeDir = includedFileDir(metadata, options.RemoteResourceLoaders())
+			}
+		})
+		options.Interpolate.Substitute = func(s string, mapping template.Mapping) (string, error) {
+			fileDir, ok := mapping(interpolationDirKey)
+			if !ok {
+				fileDir = includeDir
+			}
+			resolved, err := resolveInterpolationFunctions(s, mapping, substitute, fileDir, enabled)
+			if err != nil {
+				return "", err
+			}
+			return substitute(resolved, mapping)
+		}
+	})(o)
+}
+
+// includedFileDir returns the directory of the main file of an include, from loader event metadata, or an empty
+// string for a remote resource
+func includedFileDir(metadata map[string]any, remoteLoaders []loader.ResourceLoader) string {
+	paths, _ := metadata["path"].(types.StringList)
+	if len(paths) == 0 {
+		return ""
+	}
+	path := paths[0]
+	for _, l := range remoteLoaders {
+		if l.Accept(path) {
+			return ""
+		}
+	}
+	if !filepath.IsAbs(path) {
+		workingDir, _ := metadata["workingdir"].(string)
+		path = filepath.Join(workingDir, path)
+	}
+	return filepath.Dir(path)
+}
+
+// resolveInterpolationFunctions evaluates `${...}` expressions using functions, so they are replaced by their
+// (escaped) value before standard interpolation applies to s
+func resolveInterpolationFunctions(s string, mapping template.Mapping, substitute func(string, template.Mapping) (string, error),
+	dir string, enabled bool,
+) (string, error) {
+	if !strings.Contains(s, "|") && !strings.Contains(s, "file(") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == '$' {
+			// escaped, left to standard interpolation
+			sb.WriteString("$$")
+			i++
+			continue
+		}
+		if s[i+1] != '{' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		end := closingBrace(s, i+1)
+		if end < 0 {
+			sb.WriteString(s[i:])
+			break
+		}
+		expr := s[i+2 : end]
+		segments := splitPipeline(expr)
+		head := strings.TrimSpace(segments[0])
+		if len(segments) == 1 && !isFunctionCall(head) {
+			sb.WriteString(s[i : end+1])
+			i = end
+			continue
+		}
+		if !enabled {
+			if !isPipeline(segments) {
+				// `|` is part of a default value
+				sb.WriteString(s[i : end+1])
+				i = end
+				continue
+			}
+			return "", fmt.Errorf("invalid interpolation %q: functions require `%s: true` to be declared by the compose file",
+				s[i:end+1], InterpolationFunctionsExtension)
+		}
+		value := ""
+		if isFunctionCall(head) {
+			segments = append([]string{""}, segments...)
+		} else {
+			var (
+				v   string
+				err error
+			)
+			if hasFileDefault(segments) {
+				// variable is expected to be unset, so don't warn about it
+				v, err = template.SubstituteWithOptions("${"+head+"}", mapping, template.WithoutLogging)
+			} else {
+				v, err = substitute("${"+head+"}", mapping)
+			}
+			if err != nil {
+				return "", err
+			}
+			value = v
+		}
+		for _, call := range segments[1:] {
+			name, arg, err := parseFunctionCall(call)
+			if err != nil {
+				return "", fmt.Errorf("invalid interpolation %q: %w", s[i:end+1], err)
+			}
+			value, err = interpolationFunctions[name](value, arg, dir)
+			if err != nil {
+				return "", fmt.Errorf("invalid interpolation %q: %w", s[i:end+1], err)
+			}
+		}
+		sb.WriteString(strings.ReplaceAll(value, "$", "$$"))
+		i = end
+	}
+	return sb.String(), nil
+}
+
+// closingBrace returns the index of the brace closing the one at index open, or -1
+func closingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitPipeline splits expr on `|` which are not nested in braces, parenthesis or quotes
+func splitPipeline(expr string) []string {
+	var (
+		segments []string
+		depth    int
+		quote    byte
+		start    int
+	)
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '(':
+			depth++
+		case c == '}' || c == ')':
+			depth--
+		case c == '|' && depth == 0:
+			segments = append(segments, expr[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, expr[start:])
+}
+
+// isPipeline checks segments following the head of an expression are all valid function calls
+func isPipeline(segments []string) bool {
+	if isFunctionCall(strings.TrimSpace(segments[0])) {
+		return true
+	}
+	for _, call := range segments[1:] {
+		if _, _, err := parseFunctionCall(call); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func hasFileDefault(segments []string) bool {
+	return slices.ContainsFunc(segments[1:], func(call string) bool {
+		name, _, _ := parseFunctionCall(call)
+		return name == "file"
+	})
+}
+
+func isFunctionCall(s string) bool {
+	name, _, ok := strings.Cut(s, "(")
+	return ok && strings.HasSuffix(s, ")") && interpolationFunctions[name] != nil
+}
+
+// parseFunctionCall parses `name` or `name(arg)`, arg being optionally quoted
+func parseFunctionCall(call string) (string, string, error) {
+	call = strings.TrimSpace(call)
+	name, arg, hasArg := strings.Cut(call, "(")
+	name = strings.TrimSpace(name)
+	if _, ok := interpolationFunctions[name]; !ok {
+		return "", "", fmt.Errorf("unknown function %q, supported functions are %s", name, strings.Join(slices.Sorted(maps.Keys(interpolationFunctions)), ", "))
+	}
+	if hasArg {
+		if !strings.HasSuffix(arg, ")") {
+			return "", "", fmt.Errorf("missing closing parenthesis in %q", call)
+		}
+		arg = strings.TrimSpace(strings.TrimSuffix(arg, ")"))
+		if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] {
+			arg = arg[1 : len(arg)-1]
+		}
+	}
+	if slices.Contains(interpolationFunctionsWithArg, name) != hasArg {
+		if hasArg {
+			return "", "", fmt.Errorf("function %q doesn't accept an argument", name)
+		}
+		return "", "", fmt.Errorf("function %q requires an argument", name)
+	}
+	return name, arg, nil
+}
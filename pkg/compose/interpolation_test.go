@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/template"
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveInterpolationFunctions(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "token"), []byte(" s3cr3t\n"), 0o600))
+	mapping := func(name string) (string, bool) {
+		switch name {
+		case "TAG":
+			return "V1.2", true
+		case "PRICE":
+			return "$5", true
+		}
+		return "", false
+	}
+	substitute := func(s string) (string, error) {
+		resolved, err := resolveInterpolationFunctions(s, mapping, template.Substitute, dir, true)
+		if err != nil {
+			return "", err
+		}
+		return template.Substitute(resolved, mapping)
+	}
+
+	tests := []struct {
+		template string
+		expected string
+		err      string
+	}{
+		{template: "app:${TAG | lower}", expected: "app:v1.2"},
+		{template: "${MISSING:-Default | upper}", expected: "DEFAULT"},
+		{template: "${TOKEN | file(./token) | trim}", expected: "s3cr3t"},
+		{template: "${TAG | file(./token)}", expected: "V1.2"},
+		{template: `${file("token") | trim | b64encode}`, expected: "czNjcjN0"},
+		{template: "${PRICE | trim}", expected: "$5"},
+		{template: "$${TAG | lower} ${TAG}", expected: "${TAG | lower} V1.2"},
+		{template: "${TAG | reverse}", err: `unknown function "reverse"`},
+		{template: "${TAG | lower(x)}", err: `function "lower" doesn't accept an argument`},
+		{template: "${TAG | file}", err: `function "file" requires an argument`},
+		{template: "${TAG | file(./missing)}", expected: "V1.2"},
+		{template: "${MISSING | file(./missing)}", err: "file(): open"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			actual, err := substitute(tt.template)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, actual, tt.expected)
+		})
+	}
+}
+
+func TestResolveInterpolationFunctionsDisabled(t *testing.T) {
+	mapping := func(string) (string, bool) { return "", false }
+
+	_, err := resolveInterpolationFunctions("${TAG | lower}", mapping, template.Substitute, "", false)
+	assert.ErrorContains(t, err, "functions require `x-interpolation-functions: true`")
+
+	// `|` in a default value isn't mistaken for a function
+	resolved, err := resolveInterpolationFunctions("${CMD:-a|b}", mapping, template.Substitute, "", false)
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, "${CMD:-a|b}")
+}
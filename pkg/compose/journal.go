@@ -0,0 +1,228 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const journalDirectory = "compose/journal"
+
+// journal records steps completed by an Up operation, so it can be resumed after an interruption without
+// pulling or building images again. It is saved after each step, and removed once operation completed.
+type journal struct {
+	mu   sync.Mutex
+	path string
+
+	Started time.Time         `json:"started"`
+	Options journalOptions    `json:"options"`
+	Steps   map[string]string `json:"steps"`
+}
+
+// journalOptions are the Up options required to resume operation
+type journalOptions struct {
+	Services             []string          `json:"services,omitempty"`
+	Build                bool              `json:"build,omitempty"`
+	Rebuild              []string          `json:"rebuild,omitempty"`
+	Recreate             string            `json:"recreate,omitempty"`
+	RecreateDependencies string            `json:"recreate_dependencies,omitempty"`
+	RecreateServices     map[string]string `json:"recreate_services,omitempty"`
+	RemoveOrphans        bool              `json:"remove_orphans,omitempty"`
+	QuietPull            bool              `json:"quiet_pull,omitempty"`
+	Wait                 bool              `json:"wait,omitempty"`
+}
+
+type journalKey struct{}
+
+func withJournal(ctx context.Context, j *journal) context.Context {
+	return context.WithValue(ctx, journalKey{}, j)
+}
+
+// getJournal returns the journal recording current operation, or nil
+func getJournal(ctx context.Context) *journal {
+	j, _ := ctx.Value(journalKey{}).(*journal)
+	return j
+}
+
+func journalPath(projectName string) string {
+	return filepath.Join(config.Dir(), journalDirectory, projectName+".json")
+}
+
+func newJournal(path string, project *types.Project, options api.UpOptions) (*journal, error) {
+	var rebuild []string
+	for _, name := range project.ServiceNames() {
+		if project.Services[name].PullPolicy == types.PullPolicyBuild {
+			rebuild = append(rebuild, name)
+		}
+	}
+	j := &journal{
+		path:    path,
+		Started: time.Now(),
+		Options: journalOptions{
+			Services:             options.Create.Services,
+			Build:                options.Create.Build != nil,
+			Rebuild:              rebuild,
+			Recreate:             options.Create.Recreate,
+			RecreateDependencies: options.Create.RecreateDependencies,
+			RecreateServices:     options.Create.RecreateServices,
+			RemoveOrphans:        options.Create.RemoveOrphans,
+			QuietPull:            options.Create.QuietPull,
+			Wait:                 options.Start.Wait,
+		},
+		Steps: map[string]string{},
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	return j, j.save()
+}
+
+func loadJournal(path string) (*journal, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	j := &journal{path: path}
+	if err := json.Unmarshal(content, j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", path, err)
+	}
+	if j.Steps == nil {
+		j.Steps = map[string]string{}
+	}
+	return j, nil
+}
+
+// save writes journal to a temporary file then renames it, so an interruption never leaves a truncated journal
+func (j *journal) save() error {
+	content, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// done records a completed step with the resulting resource ID
+func (j *journal) done(step string, id string) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Steps[step] = id
+	if err := j.save(); err != nil {
+		logrus.Debugf("failed to save journal: %v", err)
+	}
+}
+
+// completed returns the resource ID recorded for a step completed by the interrupted operation
+func (j *journal) completed(step string) (string, bool) {
+	if j == nil {
+		return "", false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	id, ok := j.Steps[step]
+	return id, ok
+}
+
+func (j *journal) remove() {
+	if j == nil {
+		return
+	}
+	removeJournal(j.path)
+}
+
+func removeJournal(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logrus.Debugf("failed to remove journal: %v", err)
+	}
+}
+
+// startJournal records Up operation in a journal, unless already resuming one
+func (s *composeService) startJournal(ctx context.Context, project *types.Project, options api.UpOptions) (context.Context, *journal) {
+	if s.dryRun {
+		return ctx, nil
+	}
+	if j := getJournal(ctx); j != nil {
+		return ctx, j
+	}
+	j, err := newJournal(journalPath(project.Name), project, options)
+	if err != nil {
+		logrus.Debugf("failed to create journal, operation won't be resumable: %v", err)
+		return ctx, nil
+	}
+	return withJournal(ctx, j), j
+}
+
+func (s *composeService) Resume(ctx context.Context, project *types.Project) error {
+	j, err := loadJournal(journalPath(project.Name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no interrupted operation to resume for project %q: %w", project.Name, api.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("resuming operation started at %s, %d steps completed", j.Started.Format(time.RFC3339), len(j.Steps))
+
+	for _, name := range j.Options.Rebuild {
+		if service, ok := project.Services[name]; ok && service.Build != nil {
+			service.PullPolicy = types.PullPolicyBuild
+			project.Services[name] = service
+		}
+	}
+	var build *api.BuildOptions
+	if j.Options.Build {
+		build = &api.BuildOptions{
+			Services: j.Options.Services,
+		}
+	}
+	services := slices.Clone(j.Options.Services)
+	return s.Up(withJournal(ctx, j), project, api.UpOptions{
+		Create: api.CreateOptions{
+			Build:                build,
+			Services:             services,
+			RemoveOrphans:        j.Options.RemoveOrphans,
+			Recreate:             j.Options.Recreate,
+			RecreateDependencies: j.Options.RecreateDependencies,
+			RecreateServices:     j.Options.RecreateServices,
+			QuietPull:            j.Options.QuietPull,
+		},
+		Start: api.StartOptions{
+			Project:  project,
+			Services: services,
+			Wait:     j.Options.Wait,
+		},
+	})
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal", "demo.json")
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"app": {Name: "app", Build: &types.BuildConfig{Context: "."}, PullPolicy: types.PullPolicyBuild},
+			"db":  {Name: "db", Image: "postgres"},
+		},
+	}
+	j, err := newJournal(path, project, api.UpOptions{
+		Create: api.CreateOptions{
+			Build:         &api.BuildOptions{},
+			Services:      []string{"app", "db"},
+			RemoveOrphans: true,
+		},
+		Start: api.StartOptions{Wait: true},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, j.Options, journalOptions{
+		Services:      []string{"app", "db"},
+		Build:         true,
+		Rebuild:       []string{"app"},
+		RemoveOrphans: true,
+		Wait:          true,
+	})
+
+	ctx := withJournal(context.Background(), j)
+	getJournal(ctx).done("pull:postgres", "sha256:1234")
+	getJournal(ctx).done("network:default", "net1")
+
+	loaded, err := loadJournal(path)
+	assert.NilError(t, err)
+	id, ok := loaded.completed("pull:postgres")
+	assert.Check(t, ok)
+	assert.Equal(t, id, "sha256:1234")
+	_, ok = loaded.completed("build:demo-app")
+	assert.Check(t, !ok)
+	assert.DeepEqual(t, loaded.Options, j.Options)
+
+	loaded.remove()
+	_, err = os.Stat(path)
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestNoJournal(t *testing.T) {
+	j := getJournal(context.Background())
+	assert.Check(t, j == nil)
+	// a nil journal is a no-op, so steps don't need to check an operation is being recorded
+	j.done("pull:postgres", "sha256:1234")
+	_, ok := j.completed("pull:postgres")
+	assert.Check(t, !ok)
+	j.remove()
+}
@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+)
+
+// knownUlimits are the resource limits supported by the engine
+var knownUlimits = []string{
+	"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice",
+	"nofile", "nproc", "rss", "rtprio", "rttime", "sigpending", "stack",
+}
+
+// ipcSysctls are the namespaced kernel parameters which belong to the IPC namespace
+var ipcSysctls = []string{
+	"kernel.msgmax", "kernel.msgmnb", "kernel.msgmni", "kernel.sem",
+	"kernel.shmall", "kernel.shmmax", "kernel.shmmni", "kernel.shm_rmid_forced",
+}
+
+// utsSysctls are the namespaced kernel parameters which belong to the UTS namespace
+var utsSysctls = []string{"kernel.domainname", "kernel.hostname"}
+
+// runcRuntimes are the OCI runtimes enforcing runc rules on sysctls. Other runtimes, like sandboxed ones running
+// a dedicated kernel, accept sysctls runc would reject, so they are left to the runtime to validate.
+var runcRuntimes = []string{"runc", "crun", "io.containerd.runc.v2"}
+
+var deviceCgroupRulePattern = regexp.MustCompile(`^([acb]) ([0-9]+|\*):([0-9]+|\*) ([rwm]{1,3})$`)
+
+// checkKernelSettings validates services sysctls, ulimits, device cgroup rules, user namespace and seccomp settings
+// are supported by the engine, so all issues are reported before any resource gets created, rather than Up failing
+// on the first container.
+func (s *composeService) checkKernelSettings(ctx context.Context, project *types.Project, services []string) error {
+	if !slices.ContainsFunc(services, func(name string) bool {
+		service := project.Services[name]
+		return len(service.Sysctls) > 0 || len(service.Ulimits) > 0 || len(service.DeviceCgroupRules) > 0 ||
+			service.UserNSMode != "" || service.Privileged || seccompProfile(service) != ""
+	}) {
+		return nil
+	}
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, name := range services {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		if issues := kernelSettingsIssues(service, info); len(issues) > 0 {
+			errs = append(errs, fmt.Errorf("service %q:\n  - %s", name, strings.Join(issues, "\n  - ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// kernelSettingsIssues lists sysctls, ulimits, device cgroup rules, user namespace and seccomp settings declared by
+// service the engine won't accept
+func kernelSettingsIssues(service types.ServiceConfig, info system.Info) []string {
+	var issues []string
+	if info.OSType == "windows" {
+		if len(service.Sysctls) > 0 || len(service.Ulimits) > 0 || len(service.DeviceCgroupRules) > 0 {
+			issues = append(issues, "sysctls, ulimits and device_cgroup_rules are not supported by Windows containers")
+		}
+		return issues
+	}
+	rootless := isRootless(info)
+	runtime := service.Runtime
+	if runtime == "" {
+		runtime = info.DefaultRuntime
+	}
+	runc := runtime == "" || slices.Contains(runcRuntimes, runtime)
+
+	for _, key := range slices.Sorted(maps.Keys(service.Sysctls)) {
+		switch {
+		case strings.HasPrefix(key, "net."):
+			// enforced by the engine, whatever the runtime
+			if service.NetworkMode == "host" {
+				issues = append(issues, fmt.Sprintf("sysctl %s can't be set with network_mode: host", key))
+			}
+		case !runc:
+			continue
+		case slices.Contains(ipcSysctls, key) || strings.HasPrefix(key, "fs.mqueue."):
+			if service.Ipc == "host" {
+				issues = append(issues, fmt.Sprintf("sysctl %s can't be set with ipc: host", key))
+			}
+		case slices.Contains(utsSysctls, key):
+			if service.Uts == "host" {
+				issues = append(issues, fmt.Sprintf("sysctl %s can't be set with uts: host", key))
+			}
+		default:
+			issues = append(issues, fmt.Sprintf("sysctl %s is not namespaced, and can only be set on the host", key))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(service.Ulimits)) {
+		ulimit := service.Ulimits[name]
+		if !slices.Contains(knownUlimits, name) {
+			issues = append(issues, fmt.Sprintf("unknown ulimit %q, supported ones are %s", name, strings.Join(knownUlimits, ", ")))
+			continue
+		}
+		if ulimit != nil && ulimit.Single == 0 && ulimit.Soft > ulimit.Hard {
+			issues = append(issues, fmt.Sprintf("ulimit %s soft limit %d exceeds hard limit %d", name, ulimit.Soft, ulimit.Hard))
+		}
+	}
+
+	for _, rule := range service.DeviceCgroupRules {
+		if !deviceCgroupRulePattern.MatchString(rule) {
+			issues = append(issues, fmt.Sprintf("invalid device_cgroup_rule %q, expected `type major:minor permissions`, e.g. `c 1:3 mr`", rule))
+		}
+	}
+	if len(service.DeviceCgroupRules) > 0 && rootless {
+		issues = append(issues, "device_cgroup_rules are not supported by rootless Docker, which can't manage the devices cgroup")
+	}
+
+	issues = append(issues, usernsIssues(service, info)...)
+
+	if profile := seccompProfile(service); profile != "" && profile != "unconfined" && !hasSecurityOption(info, "seccomp") {
+		issues = append(issues, "seccomp profile can't be set, as seccomp is not enabled in the engine kernel")
+	}
+	return issues
+}
+
+// usernsIssues lists settings the engine rejects according to service userns_mode and daemon user namespace remapping
+func usernsIssues(service types.ServiceConfig, info system.Info) []string {
+	var issues []string
+	if service.UserNSMode != "" && service.UserNSMode != "host" {
+		issues = append(issues, fmt.Sprintf("invalid userns_mode %q, only host is supported", service.UserNSMode))
+	}
+	if !hasSecurityOption(info, "userns") || service.UserNSMode == "host" {
+		return issues
+	}
+	if service.Privileged {
+		issues = append(issues, "privileged mode is incompatible with user namespaces remapping, set userns_mode: host")
+	}
+	return issues
+}
+
+// seccompProfile returns the seccomp profile set by service security_opt, if any
+func seccompProfile(service types.ServiceConfig) string {
+	for _, opt := range service.SecurityOpt {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			k, v, ok = strings.Cut(opt, ":")
+		}
+		if ok && k == "seccomp" {
+			return v
+		}
+	}
+	return ""
+}
+
+// hasSecurityOption tells if engine reports the named security option as enabled
+func hasSecurityOption(info system.Info, name string) bool {
+	return slices.ContainsFunc(info.SecurityOptions, func(opt string) bool {
+		return slices.Contains(strings.Split(opt, ","), "name="+name)
+	})
+}
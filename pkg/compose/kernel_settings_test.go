@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"gotest.tools/v3/assert"
+)
+
+func TestKernelSettingsIssues(t *testing.T) {
+	linux := system.Info{OSType: "linux"}
+
+	service := types.ServiceConfig{
+		Name: "app",
+		Sysctls: types.Mapping{
+			"net.core.somaxconn": "1024",
+			"kernel.shmmax":      "68719476736",
+		},
+		Ulimits: map[string]*types.UlimitsConfig{
+			"nofile": {Soft: 20000, Hard: 40000},
+			"nproc":  {Single: 65535},
+		},
+		DeviceCgroupRules: []string{"c 1:3 mr", "a *:* rwm"},
+	}
+	assert.Equal(t, len(kernelSettingsIssues(service, linux)), 0)
+
+	service = types.ServiceConfig{
+		Name:        "app",
+		NetworkMode: "host",
+		Sysctls: types.Mapping{
+			"net.core.somaxconn": "1024",
+			"vm.swappiness":      "10",
+		},
+		Ulimits: map[string]*types.UlimitsConfig{
+			"nofile":  {Soft: 40000, Hard: 20000},
+			"openfds": {Single: 10},
+		},
+		DeviceCgroupRules: []string{"c 1:3"},
+	}
+	assert.DeepEqual(t, kernelSettingsIssues(service, system.Info{OSType: "linux", SecurityOptions: []string{"name=rootless"}}), []string{
+		"sysctl net.core.somaxconn can't be set with network_mode: host",
+		"sysctl vm.swappiness is not namespaced, and can only be set on the host",
+		"ulimit nofile soft limit 40000 exceeds hard limit 20000",
+		`unknown ulimit "openfds", supported ones are core, cpu, data, fsize, locks, memlock, msgqueue, nice, nofile, nproc, rss, rtprio, rttime, sigpending, stack`,
+		"invalid device_cgroup_rule \"c 1:3\", expected `type major:minor permissions`, e.g. `c 1:3 mr`",
+		"device_cgroup_rules are not supported by rootless Docker, which can't manage the devices cgroup",
+	})
+
+	// UTS namespaced sysctls, and sysctls left to a sandboxed runtime to validate
+	assert.Equal(t, len(kernelSettingsIssues(types.ServiceConfig{
+		Sysctls: types.Mapping{"kernel.domainname": "example.com", "kernel.hostname": "app"},
+	}, linux)), 0)
+	assert.DeepEqual(t, kernelSettingsIssues(types.ServiceConfig{
+		Uts:     "host",
+		Sysctls: types.Mapping{"kernel.hostname": "app"},
+	}, linux), []string{"sysctl kernel.hostname can't be set with uts: host"})
+	assert.Equal(t, len(kernelSettingsIssues(types.ServiceConfig{
+		Runtime: "runsc",
+		Sysctls: types.Mapping{"vm.swappiness": "10"},
+	}, linux)), 0)
+	assert.Equal(t, len(kernelSettingsIssues(types.ServiceConfig{
+		Sysctls: types.Mapping{"vm.swappiness": "10"},
+	}, system.Info{OSType: "linux", DefaultRuntime: "kata"})), 0)
+
+	remapped := system.Info{OSType: "linux", SecurityOptions: []string{"name=apparmor", "name=userns"}}
+	assert.DeepEqual(t, kernelSettingsIssues(types.ServiceConfig{
+		Privileged:  true,
+		SecurityOpt: []string{"seccomp=profile.json"},
+	}, remapped), []string{
+		"privileged mode is incompatible with user namespaces remapping, set userns_mode: host",
+		"seccomp profile can't be set, as seccomp is not enabled in the engine kernel",
+	})
+	assert.Equal(t, len(kernelSettingsIssues(types.ServiceConfig{
+		Privileged:  true,
+		UserNSMode:  "host",
+		SecurityOpt: []string{"seccomp:unconfined"},
+	}, remapped)), 0)
+	assert.DeepEqual(t, kernelSettingsIssues(types.ServiceConfig{
+		UserNSMode: "private",
+	}, system.Info{OSType: "linux", SecurityOptions: []string{"name=seccomp,profile=builtin"}}), []string{
+		`invalid userns_mode "private", only host is supported`,
+	})
+
+	assert.DeepEqual(t, kernelSettingsIssues(types.ServiceConfig{
+		Sysctls: types.Mapping{"net.core.somaxconn": "1024"},
+	}, system.Info{OSType: "windows"}), []string{
+		"sysctls, ulimits and device_cgroup_rules are not supported by Windows containers",
+	})
+}
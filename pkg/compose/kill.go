@@ -27,6 +27,7 @@ import (
 )
 
 func (s *composeService) Kill(ctx context.Context, projectName string, options api.KillOptions) error {
+	ctx = s.withOperationServices(ctx, options.Project, options.Services)
 	return Run(ctx, func(ctx context.Context) error {
 		return s.kill(ctx, strings.ToLower(projectName), options)
 	}, "kill", s.events)
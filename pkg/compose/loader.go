@@ -27,6 +27,7 @@ import (
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/remote"
+	"gopkg.in/yaml.v3"
 )
 
 // LoadProject implements api.Compose.LoadProject
@@ -35,6 +36,11 @@ func (s *composeService) LoadProject(ctx context.Context, options api.ProjectLoa
 	// Setup remote loaders (Git, OCI)
 	remoteLoaders := s.createRemoteLoaders(options)
 
+	options, err := resolveRemoteProjectDir(ctx, options, remoteLoaders)
+	if err != nil {
+		return nil, err
+	}
+
 	projectOptions, err := s.buildProjectOptions(options, remoteLoaders)
 	if err != nil {
 		return nil, err
@@ -53,13 +59,13 @@ func (s *composeService) LoadProject(ctx context.Context, options api.ProjectLoa
 
 	project, err := projectOptions.LoadProject(ctx)
 	if err != nil {
-		return nil, err
+		return nil, api.WithErrCode(err, api.ErrCodeInvalidConfig)
 	}
 
 	// Post-processing: service selection, environment resolution, etc.
 	project, err = s.postProcessProject(project, options)
 	if err != nil {
-		return nil, err
+		return nil, api.WithErrCode(err, api.ErrCodeInvalidConfig)
 	}
 
 	return project, nil
@@ -70,11 +76,22 @@ func (s *composeService) createRemoteLoaders(options api.ProjectLoadOptions) []l
 	if options.Offline {
 		return nil
 	}
-	git := remote.NewGitRemoteLoader(s.dockerCli, options.Offline)
+	git := remote.NewGitRemoteLoader(s.dockerCli, options.Offline, options.Git)
 	oci := remote.NewOCIRemoteLoader(s.dockerCli, options.Offline, options.OCI)
 	return []loader.ResourceLoader{git, oci}
 }
 
+// resolveRemoteProjectDir checks out a remote project directory, or the directory of a remote root compose file,
+// so that relative paths declared by the compose model are resolved within the local checkout
+func resolveRemoteProjectDir(ctx context.Context, options api.ProjectLoadOptions, remoteLoaders []loader.ResourceLoader) (api.ProjectLoadOptions, error) {
+	dir, err := remote.ResolveProjectDir(ctx, options.WorkingDir, options.ConfigPaths, remoteLoaders)
+	if err != nil {
+		return options, err
+	}
+	options.WorkingDir = dir
+	return options, nil
+}
+
 // buildProjectOptions constructs compose-go ProjectOptions from API options
 func (s *composeService) buildProjectOptions(options api.ProjectLoadOptions, remoteLoaders []loader.ResourceLoader) (*cli.ProjectOptions, error) {
 	opts := []cli.ProjectOptionsFn{
@@ -109,6 +126,8 @@ func (s *composeService) buildProjectOptions(options api.ProjectLoadOptions, rem
 		// eventually COMPOSE_PROFILES should have been set
 		cli.WithDefaultProfiles(options.Profiles...),
 		cli.WithName(options.ProjectName),
+		WithVariableDefaults,
+		WithInterpolationFunctions,
 	)
 
 	return cli.NewProjectOptions(options.ConfigPaths, append(options.ProjectOptionsFns, opts...)...)
@@ -120,11 +139,44 @@ func (s *composeService) postProcessProject(project *types.Project, options api.
 		return nil, errors.New("project name can't be empty. Use ProjectName option to set a valid name")
 	}
 
+	if err := validateVariables(project); err != nil {
+		return nil, err
+	}
+
+	if err := validateExternalServices(project); err != nil {
+		return nil, err
+	}
+
 	project, err := project.WithServicesEnabled(options.Services...)
 	if err != nil {
 		return nil, err
 	}
 
+	project, err = applyIngress(project, s.ingressDockerSocket)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = applyCacheVolumes(project)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = applyStableReplicas(project)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = applyDefaultLogging(project)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = applyExtraHostsDependencies(project)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add custom labels
 	for name, s := range project.Services {
 		s.CustomLabels = map[string]string{
@@ -141,7 +193,12 @@ func (s *composeService) postProcessProject(project *types.Project, options api.
 		project.Services[name] = s
 	}
 
-	project, err = project.WithSelectedServices(options.Services)
+	project, err = applySidecars(project)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = project.WithSelectedServices(withSidecars(project, options.Services))
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +210,21 @@ func (s *composeService) postProcessProject(project *types.Project, options api.
 
 	return project, nil
 }
+
+// localModels returns the raw top-level model of local compose files set by o, to check project extensions before
+// project is loaded. Stdin, remote resources and invalid files, which will be reported by the loader, are skipped.
+func localModels(o *cli.ProjectOptions) []map[string]any {
+	var models []map[string]any
+	for _, path := range o.ConfigPaths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var model map[string]any
+		if err := yaml.Unmarshal(content, &model); err != nil {
+			continue
+		}
+		models = append(models, model)
+	}
+	return models
+}
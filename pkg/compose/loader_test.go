@@ -20,9 +20,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/loader"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -325,6 +327,7 @@ this is not valid yaml: [[[
 
 	require.Error(t, err)
 	assert.Nil(t, project)
+	assert.Equal(t, api.ErrCodeInvalidConfig, api.ErrorCode(err))
 }
 
 func TestLoadProject_MissingComposeFile(t *testing.T) {
@@ -341,3 +344,147 @@ func TestLoadProject_MissingComposeFile(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, project)
 }
+
+type fakeDirLoader struct {
+	dir string
+}
+
+func (f fakeDirLoader) Accept(path string) bool {
+	return strings.HasPrefix(path, "git://")
+}
+
+func (f fakeDirLoader) Load(_ context.Context, path string) (string, error) {
+	return filepath.Join(f.dir, strings.TrimPrefix(path, "git://")), nil
+}
+
+func (f fakeDirLoader) Dir(path string) string {
+	return f.dir
+}
+
+func (f fakeDirLoader) LoadDir(_ context.Context, path string) (string, error) {
+	return filepath.Join(f.dir, strings.TrimPrefix(path, "git://")), nil
+}
+
+func TestResolveRemoteProjectDir(t *testing.T) {
+	checkout := t.TempDir()
+	loaders := []loader.ResourceLoader{fakeDirLoader{dir: checkout}}
+
+	// remote project directory
+	options, err := resolveRemoteProjectDir(t.Context(), api.ProjectLoadOptions{
+		WorkingDir: "git://stack",
+	}, loaders)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(checkout, "stack"), options.WorkingDir)
+
+	// remote root compose file
+	options, err = resolveRemoteProjectDir(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{"git://stack/compose.yaml", "compose.override.yaml"},
+	}, loaders)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(checkout, "stack"), options.WorkingDir)
+
+	// explicit local project directory
+	options, err = resolveRemoteProjectDir(t.Context(), api.ProjectLoadOptions{
+		WorkingDir:  "/src/stack",
+		ConfigPaths: []string{"git://stack/compose.yaml"},
+	}, loaders)
+	require.NoError(t, err)
+	assert.Equal(t, "/src/stack", options.WorkingDir)
+}
+
+func TestLoadProject_WithVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	composeFile := filepath.Join(tmpDir, "compose.yaml")
+	composeContent := `
+name: test-project
+x-variables:
+  HTTP_PORT:
+    type: int
+    default: 8080
+  LOG_LEVEL:
+    type: enum
+    values: [debug, info]
+services:
+  web:
+    image: nginx:latest
+    ports:
+      - "${HTTP_PORT}:80"
+    environment:
+      LOG_LEVEL: ${LOG_LEVEL:-info}
+`
+	err := os.WriteFile(composeFile, []byte(composeContent), 0o644)
+	require.NoError(t, err)
+
+	service, err := NewComposeService(nil)
+	require.NoError(t, err)
+
+	project, err := service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "8080", project.Services["web"].Ports[0].Published)
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	_, err = service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	require.EqualError(t, err, `variable LOG_LEVEL must be one of debug, info, got "verbose"`)
+}
+
+func TestLoadProject_InvalidExternalService(t *testing.T) {
+	tmpDir := t.TempDir()
+	composeFile := filepath.Join(tmpDir, "compose.yaml")
+	composeContent := `
+name: test-project
+services:
+  db:
+    image: postgres:latest
+    x-external: "yes"
+`
+	err := os.WriteFile(composeFile, []byte(composeContent), 0o644)
+	require.NoError(t, err)
+
+	service, err := NewComposeService(nil)
+	require.NoError(t, err)
+
+	_, err = service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	require.EqualError(t, err, `service "db": invalid x-external, expected a boolean or a mapping`)
+}
+
+func TestLoadProject_InterpolationFunctionsInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "api"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "token"), []byte("main-token"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "api", "token"), []byte("api-token"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "api", "compose.yaml"), []byte(`
+services:
+  api:
+    image: alpine
+    environment:
+      TOKEN: ${API_TOKEN | file(./token)}
+`), 0o644))
+	composeFile := filepath.Join(tmpDir, "compose.yaml")
+	require.NoError(t, os.WriteFile(composeFile, []byte(`
+name: test-project
+x-interpolation-functions: true
+include:
+  - api/compose.yaml
+services:
+  web:
+    image: nginx:latest
+    environment:
+      TOKEN: ${WEB_TOKEN | file(./token)}
+`), 0o644))
+
+	service, err := NewComposeService(nil)
+	require.NoError(t, err)
+
+	project, err := service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "main-token", *project.Services["web"].Environment["TOKEN"])
+	assert.Equal(t, "api-token", *project.Services["api"].Environment["TOKEN"])
+}
@@ -19,6 +19,8 @@ package compose
 import (
 	"context"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
@@ -30,15 +32,26 @@ import (
 	"github.com/docker/compose/v5/pkg/utils"
 )
 
+// logsAttachConcurrency is the default number of log streams established concurrently
+const logsAttachConcurrency = 32
+
 func (s *composeService) Logs(
 	ctx context.Context,
 	projectName string,
 	consumer api.LogConsumer,
 	options api.LogOptions,
 ) error {
-	var containers Containers
-	var err error
+	consumer, flush, err := newBufferedLogConsumer(consumer, options)
+	if err != nil {
+		return err
+	}
+	defer flush()
 
+	var containers Containers
+	oneOff := oneOffExclude
+	if options.OneOff {
+		oneOff = oneOffInclude
+	}
 	if options.Index > 0 {
 		ctr, err := s.getSpecifiedContainer(ctx, projectName, oneOffExclude, true, options.Services[0], options.Index)
 		if err != nil {
@@ -46,7 +59,7 @@ func (s *composeService) Logs(
 		}
 		containers = append(containers, ctr)
 	} else {
-		containers, err = s.getContainers(ctx, projectName, oneOffExclude, true, options.Services...)
+		containers, err = s.getContainers(ctx, projectName, oneOff, true, options.Services...)
 		if err != nil {
 			return err
 		}
@@ -57,16 +70,27 @@ func (s *composeService) Logs(
 		options.Services = options.Project.ServiceNames()
 		containers = containers.filter(isService(options.Services...))
 	}
+	if options.Project != nil && options.Index == 0 {
+		external, err := s.getExternalContainers(ctx, options.Project, true, options.Services...)
+		if err != nil {
+			return err
+		}
+		containers = append(containers, external...)
+	}
 
 	eg, ctx := errgroup.WithContext(ctx)
+	streams := s.newLogStreams(consumer)
+	// when following logs, a stream ends when container is removed or engine restarts. It is resumed
+	// by hot-join once a container is started again, so this doesn't stop collecting logs
+	streams.resilient = options.Follow
 	for _, ctr := range containers {
+		if !streams.add(ctr.ID) {
+			continue
+		}
 		eg.Go(func() error {
-			err := s.logContainer(ctx, consumer, ctr, options)
-			if errdefs.IsNotImplemented(err) {
-				logrus.Warnf("Can't retrieve logs for %q: %s", getCanonicalContainerName(ctr), err.Error())
-				return nil
-			}
-			return err
+			return streams.follow(ctx, ctr.ID, getContainerLogName(ctr), func(inspect container.InspectResponse) api.LogOptions {
+				return options
+			})
 		})
 	}
 
@@ -79,28 +103,53 @@ func (s *composeService) Logs(
 		} else if options.Project != nil {
 			monitor.withServices(options.Project.ServiceNames())
 		}
-		monitor.withListener(printer.HandleEvent)
-		monitor.withListener(func(event api.ContainerEvent) {
-			if event.Type == api.ContainerEventStarted {
-				eg.Go(func() error {
-					ctr, err := s.apiClient().ContainerInspect(ctx, event.ID)
-					if err != nil {
-						return err
+		if options.OneOff {
+			monitor.withOneOff()
+		}
+		// hot-join containers started after logs were first collected
+		hotJoin := func(id string, name string) {
+			ended, resumed := streams.endedAt(id)
+			if !streams.add(id) {
+				return
+			}
+			if resumed {
+				consumer.Status(name, "log stream resumed")
+			}
+			eg.Go(func() error {
+				return streams.follow(ctx, id, name, func(inspect container.InspectResponse) api.LogOptions {
+					since := inspect.State.StartedAt
+					if started, err := time.Parse(time.RFC3339Nano, since); resumed && err == nil && started.Before(ended) {
+						// container kept running while stream was interrupted, e.g. by an engine restart with live-restore
+						since = ended.Format(time.RFC3339Nano)
 					}
-
-					err = s.doLogContainer(ctx, consumer, event.Source, ctr, api.LogOptions{
+					return api.LogOptions{
 						Follow:     options.Follow,
-						Since:      ctr.State.StartedAt,
+						Since:      since,
 						Until:      options.Until,
 						Tail:       options.Tail,
 						Timestamps: options.Timestamps,
-					})
-					if errdefs.IsNotImplemented(err) {
-						// ignore
-						return nil
 					}
-					return err
 				})
+			})
+		}
+		monitor.withSubscribed(func() {
+			// containers started before monitor subscribed to engine events would be missed
+			running, err := s.getContainers(ctx, projectName, oneOff, false, options.Services...)
+			if err != nil {
+				logrus.Debugf("failed to list containers to follow: %v", err)
+				return
+			}
+			for _, ctr := range running {
+				hotJoin(ctr.ID, getContainerLogName(ctr))
+			}
+		})
+		monitor.withReconnect(func(err error) {
+			logrus.Warnf("Following logs interrupted, %v. Waiting for engine to be back", err)
+		})
+		monitor.withListener(printer.HandleEvent)
+		monitor.withListener(func(event api.ContainerEvent) {
+			if event.Type == api.ContainerEventStarted {
+				hotJoin(event.ID, event.Source)
 			}
 		})
 		eg.Go(func() error {
@@ -112,17 +161,109 @@ func (s *composeService) Logs(
 	return eg.Wait()
 }
 
-func (s *composeService) logContainer(ctx context.Context, consumer api.LogConsumer, c container.Summary, options api.LogOptions) error {
-	ctr, err := s.apiClient().ContainerInspect(ctx, c.ID)
+// logStreams tracks containers which logs are being followed, so a container is only streamed once at a time,
+// and bounds the number of streams being established concurrently
+type logStreams struct {
+	service  *composeService
+	consumer api.LogConsumer
+	sem      chan struct{}
+	mu       sync.Mutex
+	active   utils.Set[string]
+	// ended tracks when logs streams ended, so they can be resumed without duplicates
+	ended map[string]time.Time
+	// resilient streams don't report failures, as container logs are streamed again once restarted
+	resilient bool
+}
+
+func (s *composeService) newLogStreams(consumer api.LogConsumer) *logStreams {
+	limit := logsAttachConcurrency
+	if s.maxConcurrency > 0 {
+		limit = s.maxConcurrency
+	}
+	return &logStreams{
+		service:  s,
+		consumer: consumer,
+		sem:      make(chan struct{}, limit),
+		active:   utils.Set[string]{},
+		ended:    map[string]time.Time{},
+	}
+}
+
+// add registers container as being streamed, and returns false if it already is
+func (l *logStreams) add(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active.Has(id) {
+		return false
+	}
+	l.active.Add(id)
+	return true
+}
+
+// endedAt returns the time a previous logs stream for container ended, if any
+func (l *logStreams) endedAt(id string) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.ended[id]
+	return t, ok
+}
+
+func (l *logStreams) remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active.Remove(id)
+	l.ended[id] = time.Now()
+}
+
+// follow streams container logs until the stream ends. Only the stream setup uses a slot from the pool,
+// so hundreds of followed containers don't wait for each other to start
+func (l *logStreams) follow(ctx context.Context, id string, name string, options func(container.InspectResponse) api.LogOptions) error {
+	defer l.remove(id)
+	r, tty, err := l.open(ctx, id, options)
+	if errdefs.IsNotImplemented(err) {
+		logrus.Warnf("Can't retrieve logs for %q: %s", name, err.Error())
+		return nil
+	}
+	if err == nil {
+		defer r.Close() //nolint:errcheck
+		err = copyLogs(l.consumer, name, r, tty)
+	}
+	if err != nil && l.resilient && ctx.Err() == nil {
+		logrus.Debugf("log stream for %s interrupted: %v", name, err)
+		return nil
+	}
+	return err
+}
+
+func (l *logStreams) open(ctx context.Context, id string, options func(container.InspectResponse) api.LogOptions) (io.ReadCloser, bool, error) {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+	defer func() { <-l.sem }()
+	ctr, err := l.service.apiClient().ContainerInspect(ctx, id)
 	if err != nil {
-		return err
+		return nil, false, err
+	}
+	r, err := l.service.openContainerLogs(ctx, ctr.ID, options(ctr))
+	if err != nil {
+		return nil, false, err
 	}
-	name := getContainerNameWithoutProject(c)
-	return s.doLogContainer(ctx, consumer, name, ctr, options)
+	return r, ctr.Config.Tty, nil
 }
 
 func (s *composeService) doLogContainer(ctx context.Context, consumer api.LogConsumer, name string, ctr container.InspectResponse, options api.LogOptions) error {
-	r, err := s.apiClient().ContainerLogs(ctx, ctr.ID, container.LogsOptions{
+	r, err := s.openContainerLogs(ctx, ctr.ID, options)
+	if err != nil {
+		return err
+	}
+	defer r.Close() //nolint:errcheck
+	return copyLogs(consumer, name, r, ctr.Config.Tty)
+}
+
+func (s *composeService) openContainerLogs(ctx context.Context, id string, options api.LogOptions) (io.ReadCloser, error) {
+	return s.apiClient().ContainerLogs(ctx, id, container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     options.Follow,
@@ -131,15 +272,15 @@ func (s *composeService) doLogContainer(ctx context.Context, consumer api.LogCon
 		Tail:       options.Tail,
 		Timestamps: options.Timestamps,
 	})
-	if err != nil {
-		return err
-	}
-	defer r.Close() //nolint:errcheck
+}
 
+// copyLogs forwards log stream to consumer, demultiplexing stdout and stderr when container has no tty
+func copyLogs(consumer api.LogConsumer, name string, r io.Reader, tty bool) error {
 	w := utils.GetWriter(func(line string) {
 		consumer.Log(name, line)
 	})
-	if ctr.Config.Tty {
+	var err error
+	if tty {
 		_, err = io.Copy(w, r)
 	} else {
 		_, err = stdcopy.StdCopy(w, w, r)
@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// defaultLogBufferSize is used to buffer log lines when dropping lines is requested without a buffer size
+const defaultLogBufferSize = 1024
+
+type logEntry struct {
+	kind      int
+	container string
+	message   string
+}
+
+const (
+	logEntryLog = iota
+	logEntryErr
+	logEntryStatus
+)
+
+// bufferedLogConsumer decouples log streams from a slow api.LogConsumer, using a bounded buffer
+// so memory doesn't grow, and either blocking or dropping log lines when buffer is full
+type bufferedLogConsumer struct {
+	consumer api.LogConsumer
+	policy   api.LogOverflowPolicy
+	entries  chan logEntry
+	done     chan struct{}
+	mu       sync.Mutex
+	dropped  map[string]int
+}
+
+// newBufferedLogConsumer wraps consumer according to options. The returned func must be called once
+// log streams are closed, to flush buffered lines and report dropped ones.
+func newBufferedLogConsumer(consumer api.LogConsumer, options api.LogOptions) (api.LogConsumer, func(), error) {
+	policy := options.Overflow
+	if policy == "" {
+		policy = api.LogOverflowBlock
+	}
+	if policy != api.LogOverflowBlock && policy != api.LogOverflowDrop {
+		return nil, nil, fmt.Errorf("invalid log overflow policy %q, must be one of %q or %q", policy, api.LogOverflowBlock, api.LogOverflowDrop)
+	}
+	if options.BufferSize < 0 {
+		return nil, nil, fmt.Errorf("invalid log buffer size %d", options.BufferSize)
+	}
+	size := options.BufferSize
+	if size == 0 {
+		if policy == api.LogOverflowBlock {
+			return consumer, func() {}, nil
+		}
+		size = defaultLogBufferSize
+	}
+	b := &bufferedLogConsumer{
+		consumer: consumer,
+		policy:   policy,
+		entries:  make(chan logEntry, size),
+		done:     make(chan struct{}),
+		dropped:  map[string]int{},
+	}
+	go b.run()
+	return b, b.close, nil
+}
+
+func (b *bufferedLogConsumer) run() {
+	defer close(b.done)
+	for e := range b.entries {
+		switch e.kind {
+		case logEntryLog:
+			b.consumer.Log(e.container, e.message)
+		case logEntryErr:
+			b.consumer.Err(e.container, e.message)
+		case logEntryStatus:
+			b.consumer.Status(e.container, e.message)
+		}
+	}
+}
+
+func (b *bufferedLogConsumer) Log(containerName, message string) {
+	b.push(logEntry{kind: logEntryLog, container: containerName, message: message})
+}
+
+func (b *bufferedLogConsumer) Err(containerName, message string) {
+	b.push(logEntry{kind: logEntryErr, container: containerName, message: message})
+}
+
+// Status reports containers lifecycle, which is never dropped
+func (b *bufferedLogConsumer) Status(container, msg string) {
+	b.entries <- logEntry{kind: logEntryStatus, container: container, message: msg}
+}
+
+func (b *bufferedLogConsumer) push(e logEntry) {
+	if b.policy == api.LogOverflowBlock {
+		b.entries <- e
+		return
+	}
+	select {
+	case b.entries <- e:
+	default:
+		b.mu.Lock()
+		b.dropped[e.container]++
+		b.mu.Unlock()
+	}
+}
+
+// close flushes buffered log lines to consumer and reports dropped ones
+func (b *bufferedLogConsumer) close() {
+	close(b.entries)
+	<-b.done
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, name := range slices.Sorted(maps.Keys(b.dropped)) {
+		logrus.Warnf("%d log lines from %s were dropped as output couldn't keep up", b.dropped[name], name)
+	}
+}
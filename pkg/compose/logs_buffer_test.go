@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+type blockingLogConsumer struct {
+	testLogConsumer
+	picked  chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLogConsumer) Log(containerName, message string) {
+	b.picked <- struct{}{}
+	<-b.release
+	b.testLogConsumer.Log(containerName, message)
+}
+
+func TestBufferedLogConsumerDrop(t *testing.T) {
+	consumer := &blockingLogConsumer{picked: make(chan struct{}, 10), release: make(chan struct{})}
+	buffered, flush, err := newBufferedLogConsumer(consumer, api.LogOptions{
+		BufferSize: 2,
+		Overflow:   api.LogOverflowDrop,
+	})
+	assert.NilError(t, err)
+
+	// first line is picked by consumer, 2 next ones are buffered, others are dropped
+	buffered.Log("foo", "1")
+	<-consumer.picked
+	for _, line := range []string{"2", "3", "4", "5"} {
+		buffered.Log("foo", line)
+	}
+	close(consumer.release)
+	flush()
+
+	assert.DeepEqual(t, consumer.LogsForContainer("foo"), []string{"1", "2", "3"})
+	assert.DeepEqual(t, buffered.(*bufferedLogConsumer).dropped, map[string]int{"foo": 2})
+}
+
+func TestBufferedLogConsumerBlock(t *testing.T) {
+	consumer := &testLogConsumer{}
+	buffered, flush, err := newBufferedLogConsumer(consumer, api.LogOptions{BufferSize: 1})
+	assert.NilError(t, err)
+	for _, line := range []string{"1", "2", "3"} {
+		buffered.Log("foo", line)
+	}
+	flush()
+	assert.DeepEqual(t, consumer.LogsForContainer("foo"), []string{"1", "2", "3"})
+}
+
+func TestBufferedLogConsumerDirect(t *testing.T) {
+	consumer := &testLogConsumer{}
+	buffered, _, err := newBufferedLogConsumer(consumer, api.LogOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, buffered, api.LogConsumer(consumer))
+
+	_, _, err = newBufferedLogConsumer(consumer, api.LogOptions{Overflow: "oops"})
+	assert.ErrorContains(t, err, `invalid log overflow policy "oops"`)
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	containerType "github.com/docker/docker/api/types/container"
@@ -166,6 +167,66 @@ func TestComposeService_Logs_ServiceFiltering(t *testing.T) {
 	require.Equal(t, []string{"hello c4"}, consumer.LogsForContainer("c4"))
 }
 
+func TestComposeService_Logs_BoundedAttach(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	api, cli := prepareMocks(mockCtrl)
+	tested, err := NewComposeService(cli, WithMaxConcurrency(2))
+	require.NoError(t, err)
+
+	name := strings.ToLower(testProject)
+
+	ctx := context.Background()
+	ids := []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+	var containers []containerType.Summary
+	for _, id := range ids {
+		containers = append(containers, testContainer("service", id, id == "c6"))
+	}
+	// one-off containers are included, so list isn't filtered by oneoff label
+	api.EXPECT().ContainerList(ctx, containerType.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(projectFilter(name), hasConfigHashLabel()),
+	}).Return(containers, nil)
+
+	var (
+		mu          sync.Mutex
+		inflight    int
+		maxInflight int
+	)
+	for _, id := range ids {
+		api.EXPECT().
+			ContainerInspect(anyCancellableContext(), id).
+			DoAndReturn(func(_ context.Context, id string) (containerType.InspectResponse, error) {
+				mu.Lock()
+				inflight++
+				maxInflight = max(maxInflight, inflight)
+				mu.Unlock()
+				return containerType.InspectResponse{
+					ContainerJSONBase: &containerType.ContainerJSONBase{ID: id},
+					Config:            &containerType.Config{Tty: true},
+				}, nil
+			})
+		api.EXPECT().ContainerLogs(anyCancellableContext(), id, gomock.Any()).
+			DoAndReturn(func(_ context.Context, id string, _ containerType.LogsOptions) (io.ReadCloser, error) {
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				inflight--
+				mu.Unlock()
+				return io.NopCloser(strings.NewReader("hello " + id + "\n")), nil
+			})
+	}
+
+	consumer := &testLogConsumer{}
+	err = tested.Logs(ctx, name, consumer, compose.LogOptions{OneOff: true})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, maxInflight, 2)
+	for _, id := range ids {
+		require.Equal(t, []string{"hello " + id}, consumer.LogsForContainer(id))
+	}
+}
+
 type testLogConsumer struct {
 	mu sync.Mutex
 	// logs is keyed by container ID; values are log lines
@@ -0,0 +1,255 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	logDriverJSONFile = "json-file"
+	logDriverLocal    = "local"
+	logMaxSizeOption  = "max-size"
+	// chattyLogSize is the log file size from which a service without log size limit is reported at Up
+	chattyLogSize = 100 * units.MiB
+)
+
+func (s *composeService) TruncateLogs(ctx context.Context, project *types.Project, options api.TruncateLogsOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.truncateLogs(ctx, project, options)
+	}, "logs", s.events)
+}
+
+func (s *composeService) truncateLogs(ctx context.Context, project *types.Project, options api.TruncateLogsOptions) error {
+	services := options.Services
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	if options.MaxSize != "" {
+		// logging options can't be updated on a container, so those have to be recreated
+		for _, name := range services {
+			service, err := project.GetService(name)
+			if err != nil {
+				return err
+			}
+			if err := setLogMaxSize(&service, options.MaxSize); err != nil {
+				return err
+			}
+			project.Services[name] = service
+		}
+		return s.recreateForLogs(ctx, project, services)
+	}
+
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, services...)
+	if err != nil {
+		return err
+	}
+	var (
+		recreate []string
+		errs     []error
+	)
+	for _, c := range containers.sorted() {
+		eventName := getContainerProgressName(c)
+		inspect, err := s.apiClient().ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		switch driver := inspect.HostConfig.LogConfig.Type; driver {
+		case logDriverJSONFile:
+			if s.dryRun {
+				s.events.On(newEvent(eventName, api.Done, "Logs truncated"))
+				continue
+			}
+			err = errRemoteLogFile
+			if s.isLocalEngine() {
+				err = truncateLogFile(inspect.LogPath)
+			}
+			if err == nil {
+				s.events.On(newEvent(eventName, api.Done, "Logs truncated"))
+				continue
+			}
+		case logDriverLocal:
+			err = errLocalLogDriver
+		default:
+			s.events.On(skippedEvent(eventName, fmt.Sprintf("logs are managed by %s logging driver", driver)))
+			continue
+		}
+		if !options.Recreate {
+			s.events.On(errorEventf(eventName, "can't truncate logs: %v", err))
+			errs = append(errs, fmt.Errorf("container %s: %w", getCanonicalContainerName(c), err))
+			continue
+		}
+		if service := c.Labels[api.ServiceLabel]; !slices.Contains(recreate, service) {
+			recreate = append(recreate, service)
+		}
+	}
+	if len(recreate) > 0 {
+		if err := s.recreateForLogs(ctx, project, recreate); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w\nlogs can be discarded by recreating containers, using --recreate", errors.Join(errs...))
+	}
+	return nil
+}
+
+var (
+	// errRemoteLogFile reports a json-file log file can't be accessed, as engine runs remotely or in a VM
+	errRemoteLogFile = errors.New("log file is not accessible from this host")
+	// errLocalLogDriver reports local logging driver, which log files are compressed and managed by engine
+	errLocalLogDriver = errors.New("logs are managed by local logging driver")
+)
+
+// setLogMaxSize sets a log size limit on service, using the default logging driver if none is set
+func setLogMaxSize(service *types.ServiceConfig, maxSize string) error {
+	if _, err := units.RAMInBytes(maxSize); err != nil {
+		return fmt.Errorf("invalid log max size %q: %w", maxSize, err)
+	}
+	if service.Logging == nil {
+		service.Logging = &types.LoggingConfig{}
+	}
+	switch service.Logging.Driver {
+	case "", logDriverJSONFile, logDriverLocal:
+	default:
+		return fmt.Errorf("service %q uses %s logging driver, which doesn't support %s", service.Name, service.Logging.Driver, logMaxSizeOption)
+	}
+	if service.Logging.Options == nil {
+		service.Logging.Options = map[string]string{}
+	}
+	service.Logging.Options[logMaxSizeOption] = maxSize
+	return nil
+}
+
+// truncateLogFile empties a json-file log file, and removes rotated ones
+func truncateLogFile(path string) error {
+	if path == "" {
+		return os.ErrNotExist
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return err
+	}
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return err
+	}
+	for _, f := range rotated {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recreateForLogs recreates service containers, so they get a new log file, and restarts the ones which were running
+func (s *composeService) recreateForLogs(ctx context.Context, project *types.Project, services []string) error {
+	running, err := s.getContainers(ctx, project.Name, oneOffExclude, false, services...)
+	if err != nil {
+		return err
+	}
+	err = s.create(ctx, project, api.CreateOptions{
+		Services:             services,
+		Inherit:              true,
+		Recreate:             api.RecreateForce,
+		RecreateDependencies: api.RecreateNever,
+		SkipBindChecks:       true,
+	})
+	if err != nil {
+		return err
+	}
+	var restart []string
+	for _, c := range running {
+		if service := c.Labels[api.ServiceLabel]; !slices.Contains(restart, service) {
+			restart = append(restart, service)
+		}
+	}
+	if len(restart) == 0 {
+		return nil
+	}
+	return s.start(ctx, project.Name, api.StartOptions{
+		Project:  project,
+		Services: restart,
+	}, nil)
+}
+
+// hasLogSizeLimit tells if service logs are bounded, as unknown logging drivers are assumed to manage log storage
+func hasLogSizeLimit(service types.ServiceConfig) bool {
+	if service.Logging == nil {
+		return false
+	}
+	switch service.Logging.Driver {
+	case "", logDriverJSONFile:
+		_, ok := service.Logging.Options[logMaxSizeOption]
+		return ok
+	default:
+		// local driver has a 100MB default limit
+		return true
+	}
+}
+
+// warnUnboundedLogs reports services with large log files and no log size limit, before those fill the disk
+func (s *composeService) warnUnboundedLogs(ctx context.Context, project *types.Project) {
+	if !s.isLocalEngine() {
+		return
+	}
+	var services []string
+	for _, name := range project.ServiceNames() {
+		if !hasLogSizeLimit(project.Services[name]) {
+			services = append(services, name)
+		}
+	}
+	if len(services) == 0 {
+		return
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, services...)
+	if err != nil {
+		logrus.Debugf("failed to list containers: %v", err)
+		return
+	}
+	sizes := map[string]int64{}
+	for _, c := range containers {
+		inspect, err := s.apiClient().ContainerInspect(ctx, c.ID)
+		if err != nil {
+			logrus.Debugf("failed to inspect container %s: %v", c.ID, err)
+			continue
+		}
+		// daemon log options are set on containers, so this also checks daemon has no default limit
+		if inspect.HostConfig.LogConfig.Type != logDriverJSONFile || inspect.HostConfig.LogConfig.Config[logMaxSizeOption] != "" {
+			continue
+		}
+		if info, err := os.Stat(inspect.LogPath); err == nil {
+			sizes[c.Labels[api.ServiceLabel]] += info.Size()
+		}
+	}
+	for _, name := range services {
+		if size := sizes[name]; size >= chattyLogSize {
+			logrus.Warnf("service %q has %s of logs and no log size limit. Set `logging.options.max-size`, "+
+				"or run `compose logs --truncate` to discard them", name, units.HumanSizeWithPrecision(float64(size), 3))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestSetLogMaxSize(t *testing.T) {
+	service := types.ServiceConfig{Name: "web"}
+	assert.NilError(t, setLogMaxSize(&service, "10m"))
+	assert.DeepEqual(t, service.Logging, &types.LoggingConfig{Options: types.Options{"max-size": "10m"}})
+
+	service = types.ServiceConfig{Name: "web", Logging: &types.LoggingConfig{Driver: "local", Options: map[string]string{"max-file": "3"}}}
+	assert.NilError(t, setLogMaxSize(&service, "1g"))
+	assert.DeepEqual(t, service.Logging.Options, types.Options{"max-file": "3", "max-size": "1g"})
+
+	service = types.ServiceConfig{Name: "web", Logging: &types.LoggingConfig{Driver: "syslog"}}
+	assert.Error(t, setLogMaxSize(&service, "10m"), `service "web" uses syslog logging driver, which doesn't support max-size`)
+
+	assert.ErrorContains(t, setLogMaxSize(&service, "ten"), `invalid log max size "ten"`)
+}
+
+func TestHasLogSizeLimit(t *testing.T) {
+	assert.Check(t, !hasLogSizeLimit(types.ServiceConfig{}))
+	assert.Check(t, !hasLogSizeLimit(types.ServiceConfig{Logging: &types.LoggingConfig{Driver: "json-file"}}))
+	assert.Check(t, hasLogSizeLimit(types.ServiceConfig{Logging: &types.LoggingConfig{Options: map[string]string{"max-size": "1m"}}}))
+	assert.Check(t, hasLogSizeLimit(types.ServiceConfig{Logging: &types.LoggingConfig{Driver: "local"}}))
+}
+
+func TestTruncateLogs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	mockCli.EXPECT().DockerEndpoint().Return(docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "unix:///var/run/docker.sock"}}).AnyTimes()
+	tested := composeService{dockerCli: mockCli, events: &ignore{}}
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "json.log")
+	assert.NilError(t, os.WriteFile(logPath, []byte("log\n"), 0o600))
+	assert.NilError(t, os.WriteFile(logPath+".1", []byte("rotated\n"), 0o600))
+
+	web := testContainer("web", "w1", false)
+	syslog := testContainer("syslog", "s1", false)
+	mockApi.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{web, syslog}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "w1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			LogPath:    logPath,
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "json-file"}},
+		},
+	}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "s1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "syslog"}},
+		},
+	}, nil)
+
+	project := &types.Project{Name: testProject, Services: types.Services{
+		"web":    {Name: "web"},
+		"syslog": {Name: "syslog"},
+	}}
+	err := tested.truncateLogs(t.Context(), project, api.TruncateLogsOptions{})
+	assert.NilError(t, err)
+
+	info, err := os.Stat(logPath)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Size(), int64(0))
+	_, err = os.Stat(logPath + ".1")
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestTruncateLogsRequiresRecreateConsent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	mockCli.EXPECT().DockerEndpoint().Return(docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "tcp://remote:2376"}}).AnyTimes()
+	tested := composeService{dockerCli: mockCli, events: &ignore{}}
+
+	web := testContainer("web", "w1", false)
+	db := testContainer("db", "d1", false)
+	mockApi.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{db, web}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "w1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			LogPath:    "/var/lib/docker/containers/w1/w1-json.log",
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "json-file"}},
+		},
+	}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "d1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "local"}},
+		},
+	}, nil)
+
+	project := &types.Project{Name: testProject, Services: types.Services{
+		"web": {Name: "web"},
+		"db":  {Name: "db"},
+	}}
+	// containers are not recreated without consent, ContainerCreate would fail as unexpected
+	err := tested.truncateLogs(t.Context(), project, api.TruncateLogsOptions{})
+	assert.Error(t, err, `container d1: logs are managed by local logging driver
+container w1: log file is not accessible from this host
+logs can be discarded by recreating containers, using --recreate`)
+}
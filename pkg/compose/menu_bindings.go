@@ -0,0 +1,195 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// ValidateKeyBindings checks custom navigation menu entries are bound to distinct keys, and target project services.
+// Up also checks bindings before it creates any resource, callers can use it to report errors earlier.
+func ValidateKeyBindings(project *types.Project, bindings []api.MenuKeyBinding) error {
+	keys := map[rune]bool{}
+	for _, b := range bindings {
+		key, size := utf8.DecodeRuneInString(b.Key)
+		if size == 0 || size != len(b.Key) {
+			return fmt.Errorf("invalid key binding %q: key must be a single character", b.Key)
+		}
+		if strings.ContainsRune(formatter.ReservedKeys, key) {
+			return fmt.Errorf("invalid key binding %q: key is reserved by the navigation menu", b.Key)
+		}
+		if keys[key] {
+			return fmt.Errorf("invalid key binding %q: key is bound more than once", b.Key)
+		}
+		keys[key] = true
+		switch b.Action {
+		case api.MenuActionRestart:
+		case api.MenuActionExec, api.MenuActionToggleLogs:
+			if b.Service == "" {
+				return fmt.Errorf("invalid key binding %q: %s action requires a service", b.Key, b.Action)
+			}
+		default:
+			return fmt.Errorf("invalid key binding %q: unsupported action %q", b.Key, b.Action)
+		}
+		if b.Service != "" {
+			if _, err := project.GetService(b.Service); err != nil {
+				return fmt.Errorf("invalid key binding %q: %w", b.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addKeyBindings registers custom navigation menu entries running the configured actions
+func (s *composeService) addKeyBindings(project *types.Project, bindings []api.MenuKeyBinding, menu *formatter.LogKeyboard, logs *logToggles, logTo api.LogConsumer) {
+	for _, b := range bindings {
+		key, _ := utf8.DecodeRuneInString(b.Key)
+		menu.AddKeyBinding(formatter.KeyBinding{
+			Key:         key,
+			Description: keyBindingDescription(b),
+			Run: func(ctx context.Context) error {
+				switch b.Action {
+				case api.MenuActionRestart:
+					var services []string
+					if b.Service != "" {
+						services = []string{b.Service}
+					}
+					return s.restart(ctx, project.Name, api.RestartOptions{
+						Project:  project,
+						Services: services,
+					})
+				case api.MenuActionExec:
+					command := b.Command
+					if len(command) == 0 {
+						command = []string{"sh"}
+					}
+					resume := menu.Suspend()
+					_, err := s.ExecCommand(ctx, project.Name, api.ExecOptions{
+						Service:     b.Service,
+						Command:     command,
+						Tty:         true,
+						Interactive: true,
+					})
+					if rerr := resume(); rerr != nil {
+						return rerr
+					}
+					var status cli.StatusError
+					if errors.As(err, &status) {
+						// command exit code isn't a failure of the menu action
+						return nil
+					}
+					return err
+				case api.MenuActionToggleLogs:
+					if logs.toggle(b.Service) {
+						logTo.Log(api.WatchLogger, fmt.Sprintf("Logs of service %q are hidden", b.Service))
+					} else {
+						logTo.Log(api.WatchLogger, fmt.Sprintf("Logs of service %q are displayed", b.Service))
+					}
+				}
+				return nil
+			},
+		})
+	}
+}
+
+func keyBindingDescription(b api.MenuKeyBinding) string {
+	if b.Description != "" {
+		return b.Description
+	}
+	switch b.Action {
+	case api.MenuActionRestart:
+		if b.Service == "" {
+			return "Restart"
+		}
+		return "Restart " + b.Service
+	case api.MenuActionExec:
+		return "Shell " + b.Service
+	default:
+		return "Toggle " + b.Service + " logs"
+	}
+}
+
+// logToggles is a LogConsumer hiding logs of selected services
+type logToggles struct {
+	api.LogConsumer
+	// containers indexes services by custom container name
+	containers map[string]string
+	mu         sync.Mutex
+	hidden     map[string]bool
+}
+
+func newLogToggles(consumer api.LogConsumer, project *types.Project) *logToggles {
+	containers := map[string]string{}
+	for name, service := range project.Services {
+		if service.ContainerName != "" {
+			containers[service.ContainerName] = name
+		}
+	}
+	return &logToggles{
+		LogConsumer: consumer,
+		containers:  containers,
+		hidden:      map[string]bool{},
+	}
+}
+
+// toggle hides, or displays again, logs of service and tells if they're now hidden
+func (l *logToggles) toggle(service string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hidden[service] = !l.hidden[service]
+	return l.hidden[service]
+}
+
+func (l *logToggles) isHidden(source string) bool {
+	service, ok := l.containers[source]
+	if !ok {
+		// source is the container name without project prefix, i.e. service-number
+		service = source
+		if i := strings.LastIndex(source, api.Separator); i > 0 {
+			if _, err := strconv.Atoi(source[i+1:]); err == nil {
+				service = source[:i]
+			}
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.hidden[service]
+}
+
+func (l *logToggles) Log(containerName, message string) {
+	if !l.isHidden(containerName) {
+		l.LogConsumer.Log(containerName, message)
+	}
+}
+
+func (l *logToggles) Err(containerName, message string) {
+	if !l.isHidden(containerName) {
+		l.LogConsumer.Err(containerName, message)
+	}
+}
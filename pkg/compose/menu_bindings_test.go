@@ -0,0 +1,139 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestValidateKeyBindings(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web": {Name: "web"},
+			"db":  {Name: "db"},
+		},
+	}
+	tests := []struct {
+		name     string
+		bindings []api.MenuKeyBinding
+		err      string
+	}{
+		{
+			name: "valid",
+			bindings: []api.MenuKeyBinding{
+				{Key: "R", Action: api.MenuActionRestart},
+				{Key: "s", Action: api.MenuActionExec, Service: "web"},
+				{Key: "l", Action: api.MenuActionToggleLogs, Service: "db"},
+			},
+		},
+		{
+			name:     "multiple characters",
+			bindings: []api.MenuKeyBinding{{Key: "ab", Action: api.MenuActionRestart}},
+			err:      `invalid key binding "ab": key must be a single character`,
+		},
+		{
+			name:     "reserved key",
+			bindings: []api.MenuKeyBinding{{Key: "w", Action: api.MenuActionRestart}},
+			err:      `invalid key binding "w": key is reserved by the navigation menu`,
+		},
+		{
+			name: "duplicate key",
+			bindings: []api.MenuKeyBinding{
+				{Key: "x", Action: api.MenuActionRestart},
+				{Key: "x", Action: api.MenuActionExec, Service: "web"},
+			},
+			err: `invalid key binding "x": key is bound more than once`,
+		},
+		{
+			name:     "unsupported action",
+			bindings: []api.MenuKeyBinding{{Key: "x", Action: "kill"}},
+			err:      `invalid key binding "x": unsupported action "kill"`,
+		},
+		{
+			name:     "missing service",
+			bindings: []api.MenuKeyBinding{{Key: "x", Action: api.MenuActionExec}},
+			err:      `invalid key binding "x": exec action requires a service`,
+		},
+		{
+			name:     "unknown service",
+			bindings: []api.MenuKeyBinding{{Key: "x", Action: api.MenuActionToggleLogs, Service: "cache"}},
+			err:      `invalid key binding "x": no such service: cache: not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKeyBindings(project, tt.bindings)
+			if tt.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.err)
+		})
+	}
+}
+
+func TestUpRejectsInvalidKeyBindings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	// no call is expected on the engine API, as bindings are checked before any resource is created
+	_, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	project := &types.Project{
+		Name:     "test",
+		Services: types.Services{"web": {Name: "web"}},
+	}
+	err := tested.Up(t.Context(), project, api.UpOptions{
+		Start: api.StartOptions{
+			NavigationMenu: true,
+			KeyBindings:    []api.MenuKeyBinding{{Key: "x", Action: "kill"}},
+		},
+	})
+	assert.Error(t, err, `invalid key binding "x": unsupported action "kill"`)
+}
+
+func TestLogToggles(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			"web":     {Name: "web"},
+			"web-api": {Name: "web-api", ContainerName: "api"},
+		},
+	}
+	consumer := &testLogConsumer{}
+	toggles := newLogToggles(consumer, project)
+
+	assert.Equal(t, toggles.toggle("web"), true)
+	toggles.Log("web-1", "hidden")
+	toggles.Log("web-api-1", "displayed")
+	toggles.Log("api", "displayed")
+	assert.DeepEqual(t, consumer.LogsForContainer("web-1"), []string(nil))
+	assert.DeepEqual(t, consumer.LogsForContainer("web-api-1"), []string{"displayed"})
+	assert.DeepEqual(t, consumer.LogsForContainer("api"), []string{"displayed"})
+
+	assert.Equal(t, toggles.toggle("web-api"), true)
+	toggles.Log("api", "hidden")
+	assert.DeepEqual(t, consumer.LogsForContainer("api"), []string{"displayed"})
+
+	assert.Equal(t, toggles.toggle("web"), false)
+	toggles.Log("web-1", "displayed")
+	assert.DeepEqual(t, consumer.LogsForContainer("web-1"), []string{"displayed"})
+}
@@ -0,0 +1,128 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/docker/compose/v5/internal/tracing"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// WithMetricsHook registers a hook to get notified with telemetry about each Compose operation:
+// operation name, duration, result and number of services. Can be set multiple times.
+func WithMetricsHook(hook api.MetricsHook) Option {
+	return func(s *composeService) error {
+		s.metricsHooks = append(s.metricsHooks, hook)
+		return nil
+	}
+}
+
+// WithoutMetrics disables telemetry: hooks set by WithMetricsHook are not notified, and Compose operations don't
+// record tracing spans nor events, including navigation menu usage, whatever the OpenTelemetry exporters configured
+// by environment variables or the Docker context (Docker Desktop) are
+func WithoutMetrics(s *composeService) error {
+	s.metricsDisabled = true
+	return nil
+}
+
+type operationServicesKey struct{}
+
+// withOperationServices declares the number of services the next Compose operation applies to,
+// which is the explicitly selected services, or all the project services.
+// When metrics are disabled, it also disables tracing for the operation
+func (s *composeService) withOperationServices(ctx context.Context, project *types.Project, services []string) context.Context {
+	if s.metricsDisabled {
+		return tracing.Disable(ctx)
+	}
+	if len(s.metricsHooks) == 0 {
+		return ctx
+	}
+	n := len(services)
+	if n == 0 && project != nil {
+		n = len(project.Services)
+	}
+	return context.WithValue(ctx, operationServicesKey{}, n)
+}
+
+type operationRecord struct {
+	ctx      context.Context
+	start    time.Time
+	services int
+}
+
+// metricsProcessor decorates an api.EventProcessor to notify metrics hooks as operations complete
+type metricsProcessor struct {
+	api.EventProcessor
+	hooks   []api.MetricsHook
+	clock   clockwork.Clock
+	mu      sync.Mutex
+	running map[string][]operationRecord
+}
+
+func newMetricsProcessor(bus api.EventProcessor, clock clockwork.Clock, hooks []api.MetricsHook) *metricsProcessor {
+	return &metricsProcessor{
+		EventProcessor: bus,
+		hooks:          hooks,
+		clock:          clock,
+		running:        map[string][]operationRecord{},
+	}
+}
+
+func (m *metricsProcessor) Start(ctx context.Context, operation string) {
+	services, _ := ctx.Value(operationServicesKey{}).(int)
+	m.mu.Lock()
+	m.running[operation] = append(m.running[operation], operationRecord{
+		ctx:      ctx,
+		start:    m.clock.Now(),
+		services: services,
+	})
+	m.mu.Unlock()
+	m.EventProcessor.Start(ctx, operation)
+}
+
+func (m *metricsProcessor) Done(operation string, success bool) {
+	m.EventProcessor.Done(operation, success)
+	m.mu.Lock()
+	stack := m.running[operation]
+	if len(stack) == 0 {
+		m.mu.Unlock()
+		return
+	}
+	record := stack[len(stack)-1]
+	if len(stack) == 1 {
+		delete(m.running, operation)
+	} else {
+		m.running[operation] = stack[:len(stack)-1]
+	}
+	m.mu.Unlock()
+
+	metrics := api.OperationMetrics{
+		Operation: operation,
+		Duration:  m.clock.Since(record.start),
+		Success:   success,
+		Services:  record.services,
+	}
+	for _, hook := range m.hooks {
+		hook(record.ctx, metrics)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestMetricsHook(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	var got []api.OperationMetrics
+	bus := newMetricsProcessor(&ignore{}, clock, []api.MetricsHook{
+		func(_ context.Context, m api.OperationMetrics) {
+			got = append(got, m)
+		},
+	})
+	s := &composeService{metricsHooks: bus.hooks}
+	project := &types.Project{Services: types.Services{
+		"foo": {Name: "foo"},
+		"bar": {Name: "bar"},
+	}}
+
+	ctx := s.withOperationServices(t.Context(), project, nil)
+	err := Run(ctx, func(ctx context.Context) error {
+		clock.Advance(2 * time.Second)
+		return nil
+	}, "up", bus)
+	assert.NilError(t, err)
+
+	ctx = s.withOperationServices(t.Context(), project, []string{"foo"})
+	err = Run(ctx, func(ctx context.Context) error {
+		clock.Advance(time.Second)
+		return errors.New("boom")
+	}, "down", bus)
+	assert.Error(t, err, "boom")
+
+	err = Run(t.Context(), func(ctx context.Context) error {
+		return nil
+	}, "logs", bus)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, got, []api.OperationMetrics{
+		{Operation: "up", Duration: 2 * time.Second, Success: true, Services: 2},
+		{Operation: "down", Duration: time.Second, Success: false, Services: 1},
+		{Operation: "logs", Success: true},
+	})
+	assert.Equal(t, len(bus.running), 0)
+}
+
+func TestWithoutMetrics(t *testing.T) {
+	called := false
+	s, err := NewComposeService(nil,
+		WithAPIClient(nil),
+		WithMetricsHook(func(context.Context, api.OperationMetrics) {
+			called = true
+		}),
+		WithoutMetrics)
+	assert.NilError(t, err)
+	err = Run(t.Context(), func(ctx context.Context) error {
+		return nil
+	}, "up", s.(*composeService).events)
+	assert.NilError(t, err)
+	assert.Assert(t, !called)
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Migrate(ctx context.Context, projectName string, target *types.Project, options api.MigrateOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.migrate(ctx, strings.ToLower(projectName), target, options)
+	}, "migrate", s.events)
+}
+
+// volumeMigration is a named volume which data must be copied to the target project
+type volumeMigration struct {
+	key    string
+	source string
+	target string
+}
+
+func (s *composeService) migrate(ctx context.Context, projectName string, target *types.Project, options api.MigrateOptions) error {
+	if projectName == target.Name {
+		return fmt.Errorf("project is already named %q", projectName)
+	}
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return err
+	}
+	networks, err := s.actualNetworks(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	volumes, err := s.actualVolumes(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 && len(networks) == 0 && len(volumes) == 0 {
+		return fmt.Errorf("no resource found for project %q: %w", projectName, api.ErrNotFound)
+	}
+
+	existing, err := s.getContainers(ctx, target.Name, oneOffInclude, true)
+	if err != nil {
+		return err
+	}
+	existingVolumes, err := s.actualVolumes(ctx, target.Name)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(existingVolumes) > 0 {
+		return fmt.Errorf("project %q already exists: %w", target.Name, api.ErrAlreadyExists)
+	}
+
+	migrations := planVolumeMigrations(volumes, target)
+	for _, key := range slices.Sorted(maps.Keys(volumes)) {
+		if _, ok := target.Volumes[key]; !ok {
+			logrus.Warnf("volume %s is not declared by the Compose file and is left unchanged", volumes[key].Name)
+		}
+	}
+
+	msg := fmt.Sprintf("Project %q will be stopped, and its containers and networks recreated as project %q.", projectName, target.Name)
+	if len(migrations) > 0 {
+		msg += fmt.Sprintf(" Data of %d volume(s) will be copied before source volumes are removed.", len(migrations))
+	}
+	confirm, err := s.prompt(msg+"\nContinue?", false)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return nil
+	}
+
+	// services are restarted in the target project only if they were running
+	var running []string
+	for _, c := range containers.filter(isNotOneOff) {
+		service := c.Labels[api.ServiceLabel]
+		if c.State == container.StateRunning && !slices.Contains(running, service) {
+			running = append(running, service)
+		}
+	}
+
+	// containers and networks can't be relabeled, so they get removed and recreated by the target project
+	if err := s.removeContainers(ctx, containers, nil, options.Timeout, false); err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(networks)) {
+		if err := s.removeNetwork(ctx, key, projectName, networks[key].Name); err != nil {
+			return err
+		}
+	}
+
+	if len(migrations) > 0 {
+		if _, err := s.ensureProjectVolumes(ctx, target); err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if err := s.copyVolume(ctx, m.source, m.target); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.create(ctx, target, api.CreateOptions{Timeout: options.Timeout}); err != nil {
+		return err
+	}
+	if len(running) > 0 {
+		slices.Sort(running)
+		err = s.start(ctx, target.Name, api.StartOptions{
+			Project:  target,
+			Services: running,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// source volumes are only removed once target project has been created successfully
+	for _, m := range migrations {
+		if err := s.removeVolume(ctx, m.source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planVolumeMigrations selects source volumes which data must be copied into a volume of the target project.
+// Volumes with an explicit name are shared by source and target projects, so they don't need a copy.
+func planVolumeMigrations(volumes types.Volumes, target *types.Project) []volumeMigration {
+	var migrations []volumeMigration
+	for _, key := range slices.Sorted(maps.Keys(volumes)) {
+		vol, ok := target.Volumes[key]
+		if !ok || bool(vol.External) || vol.Name == volumes[key].Name {
+			continue
+		}
+		migrations = append(migrations, volumeMigration{
+			key:    key,
+			source: volumes[key].Name,
+			target: vol.Name,
+		})
+	}
+	return migrations
+}
+
+// copyVolume copies content of a volume into another one, preserving ownership and permissions, using a helper container
+func (s *composeService) copyVolume(ctx context.Context, source string, target string) error {
+	eventName := "Volume " + target
+	s.events.On(newEvent(eventName, api.Working, "Copying from "+source))
+	if s.dryRun {
+		s.events.On(newEvent(eventName, api.Done, "Copied from "+source))
+		return nil
+	}
+	if err := s.ensureHelperImage(ctx, ChownImage); err != nil {
+		return err
+	}
+	_, exitCode, err := s.runHelper(ctx, &container.Config{
+		Image:      ChownImage,
+		User:       "0:0",
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{"cp -a /from/. /to/"},
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: source, Target: "/from", ReadOnly: true},
+			{Type: mount.TypeVolume, Source: target, Target: "/to"},
+		},
+		NetworkMode: "none",
+	})
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("failed to copy volume %s to %s: exit %d", source, target, exitCode)
+	}
+	if err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	s.events.On(newEvent(eventName, api.Done, "Copied from "+source))
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestPlanVolumeMigrations(t *testing.T) {
+	volumes := types.Volumes{
+		"data":     {Name: "old_data"},
+		"shared":   {Name: "shared"},
+		"obsolete": {Name: "old_obsolete"},
+		"logs":     {Name: "old_logs"},
+	}
+	target := &types.Project{
+		Name: "new",
+		Volumes: types.Volumes{
+			"data":   {Name: "new_data"},
+			"shared": {Name: "shared"},
+			"logs":   {Name: "logs", External: true},
+			"cache":  {Name: "new_cache"},
+		},
+	}
+	assert.DeepEqual(t, planVolumeMigrations(volumes, target), []volumeMigration{
+		{key: "data", source: "old_data", target: "new_data"},
+	}, cmp.AllowUnexported(volumeMigration{}))
+}
+
+func TestMigrateTargetExists(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	ctx := context.Background()
+	web := container.Summary{
+		Labels: map[string]string{api.ServiceLabel: "web", api.OneoffLabel: "False"},
+		State:  container.StateRunning,
+	}
+	mockApi.EXPECT().ContainerList(ctx, gomock.Any()).Times(2).Return([]container.Summary{web}, nil)
+	mockApi.EXPECT().NetworkList(ctx, gomock.Any()).Return([]network.Summary{}, nil)
+	mockApi.EXPECT().VolumeList(ctx, gomock.Any()).Times(2).Return(volume.ListResponse{}, nil)
+
+	err := tested.migrate(ctx, "old", &types.Project{Name: "new"}, api.MigrateOptions{})
+	assert.Check(t, api.IsAlreadyExistsError(err))
+}
+
+func TestMigrateSameName(t *testing.T) {
+	err := (&composeService{}).migrate(t.Context(), "same", &types.Project{Name: "same"}, api.MigrateOptions{})
+	assert.ErrorContains(t, err, `project is already named "same"`)
+}
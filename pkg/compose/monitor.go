@@ -18,7 +18,10 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
@@ -35,10 +38,21 @@ type monitor struct {
 	apiClient client.APIClient
 	project   string
 	// services tells us which service to consider and those we can ignore, maybe ran by a concurrent compose command
-	services  map[string]bool
-	listeners []api.ContainerEventListener
+	services map[string]bool
+	// oneOff tells us to also consider one-off containers created by `compose run`
+	oneOff     bool
+	listeners  []api.ContainerEventListener
+	subscribed []func()
+	// disconnected is set to survive engine connection loss, and is notified before monitor waits for engine to be back
+	disconnected func(err error)
 }
 
+// errEngineConnection reports monitor lost the connection to engine
+var errEngineConnection = errors.New("lost connection to engine")
+
+// monitorReconnectInterval is the delay between attempts to reach engine after connection loss
+var monitorReconnectInterval = time.Second
+
 func newMonitor(apiClient client.APIClient, project string) *monitor {
 	return &monitor{
 		apiClient: apiClient,
@@ -53,21 +67,62 @@ func (c *monitor) withServices(services []string) {
 	}
 }
 
+func (c *monitor) withOneOff() {
+	c.oneOff = true
+}
+
+// withSubscribed registers a callback invoked once monitor subscribed to engine events
+func (c *monitor) withSubscribed(fn func()) {
+	c.subscribed = append(c.subscribed, fn)
+}
+
+// withReconnect makes monitor survive engine restarts. Subscribed callbacks are invoked again once monitor
+// subscribed to the restarted engine, so they can resolve containers again.
+func (c *monitor) withReconnect(disconnected func(err error)) {
+	c.disconnected = disconnected
+}
+
 // Start runs monitor to detect application events and return after termination
-//
-//nolint:gocyclo
 func (c *monitor) Start(ctx context.Context) error {
+	for {
+		err := c.watch(ctx)
+		if c.disconnected == nil || ctx.Err() != nil || !errors.Is(err, errEngineConnection) {
+			return err
+		}
+		c.disconnected(err)
+		if err := c.waitEngine(ctx); err != nil {
+			return nil
+		}
+	}
+}
+
+// waitEngine waits for engine to answer again after connection loss
+func (c *monitor) waitEngine(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(monitorReconnectInterval):
+		}
+		if _, err := c.apiClient.Ping(ctx); err == nil {
+			return nil
+		}
+	}
+}
+
+//nolint:gocyclo
+func (c *monitor) watch(ctx context.Context) error {
 	// collect initial application container
+	f := []filters.KeyValuePair{projectFilter(c.project), hasConfigHashLabel()}
+	if !c.oneOff {
+		f = append(f, oneOffFilter(false))
+	}
 	initialState, err := c.apiClient.ContainerList(ctx, container.ListOptions{
-		All: true,
-		Filters: filters.NewArgs(
-			projectFilter(c.project),
-			oneOffFilter(false),
-			hasConfigHashLabel(),
-		),
+		All:     true,
+		Filters: filters.NewArgs(f...),
 	})
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", errEngineConnection, err)
 	}
 
 	// containers is the set if container IDs the application is based on
@@ -84,6 +139,9 @@ func (c *monitor) Start(ctx context.Context) error {
 			filters.Arg("type", "container"),
 			projectFilter(c.project)),
 	})
+	for _, fn := range c.subscribed {
+		fn()
+	}
 	for {
 		if len(containers) == 0 {
 			return nil
@@ -92,11 +150,14 @@ func (c *monitor) Start(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case err := <-errCh:
-			return err
+			return fmt.Errorf("%w: %w", errEngineConnection, err)
 		case event := <-evtCh:
 			if len(c.services) > 0 && !c.services[event.Actor.Attributes[api.ServiceLabel]] {
 				continue
 			}
+			if !c.oneOff && event.Actor.Attributes[api.OneoffLabel] == "True" {
+				continue
+			}
 			ctr, err := c.getContainerSummary(event)
 			if err != nil {
 				return err
@@ -176,6 +237,7 @@ func newContainerEvent(timeNano int64, ctr *api.ContainerSummary, eventType int,
 		// remove project- prefix
 		name = name[len(ctr.Project)+1:]
 	}
+	name = withSidecarPrefix(name, ctr.Labels)
 
 	event := api.ContainerEvent{
 		Type:      eventType,
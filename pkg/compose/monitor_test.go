@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestMonitorReconnect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, _ := prepareMocks(mockCtrl)
+	monitorReconnectInterval = 0
+	t.Cleanup(func() {
+		monitorReconnectInterval = time.Second
+	})
+
+	ctr := container.Summary{
+		ID: "123",
+		Labels: map[string]string{
+			api.ProjectLabel:         "test",
+			api.ServiceLabel:         "web",
+			api.ContainerNumberLabel: "1",
+		},
+	}
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{ctr}, nil).Times(2)
+	apiClient.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, nil)
+
+	// engine restarts, so the first events stream fails
+	lost := make(chan error, 1)
+	lost <- io.ErrUnexpectedEOF
+	apiClient.EXPECT().Events(gomock.Any(), gomock.Any()).Return(make(chan events.Message), lost)
+
+	evtCh := make(chan events.Message, 1)
+	evtCh <- events.Message{
+		Action: events.ActionDie,
+		Actor: events.Actor{
+			ID: "123",
+			Attributes: map[string]string{
+				api.ServiceLabel:         "web",
+				api.ContainerNumberLabel: "1",
+				"name":                   "test-web-1",
+				"exitCode":               "0",
+			},
+		},
+	}
+	apiClient.EXPECT().Events(gomock.Any(), gomock.Any()).Return(evtCh, make(chan error))
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "123").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{}},
+	}, nil)
+
+	m := newMonitor(apiClient, "test")
+	var (
+		disconnected []error
+		subscribed   int
+		exited       []string
+	)
+	m.withReconnect(func(err error) {
+		disconnected = append(disconnected, err)
+	})
+	m.withSubscribed(func() {
+		subscribed++
+	})
+	m.withListener(func(event api.ContainerEvent) {
+		if event.Type == api.ContainerEventExited {
+			exited = append(exited, event.Source)
+		}
+	})
+
+	assert.NilError(t, m.Start(context.Background()))
+	assert.Equal(t, len(disconnected), 1)
+	assert.Assert(t, errors.Is(disconnected[0], errEngineConnection))
+	assert.Assert(t, errors.Is(disconnected[0], io.ErrUnexpectedEOF))
+	assert.Equal(t, subscribed, 2)
+	assert.DeepEqual(t, exited, []string{"web-1"})
+}
+
+func TestMonitorWithoutReconnect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, _ := prepareMocks(mockCtrl)
+
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{{ID: "123"}}, nil)
+	lost := make(chan error, 1)
+	lost <- io.ErrUnexpectedEOF
+	apiClient.EXPECT().Events(gomock.Any(), gomock.Any()).Return(make(chan events.Message), lost)
+
+	err := newMonitor(apiClient, "test").Start(context.Background())
+	assert.Assert(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
@@ -0,0 +1,255 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Move(ctx context.Context, project *types.Project, options api.MoveOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.move(ctx, project, options)
+	}, "move", s.events)
+}
+
+func (s *composeService) move(ctx context.Context, project *types.Project, options api.MoveOptions) error {
+	if options.Context == s.dockerCli.CurrentContext() {
+		return fmt.Errorf("project %q is already on context %q", project.Name, options.Context)
+	}
+	target, err := s.onContext(ctx, options.Context)
+	if err != nil {
+		return err
+	}
+
+	existing, err := target.getContainers(ctx, project.Name, oneOffInclude, true)
+	if err != nil {
+		return err
+	}
+	existingVolumes, err := target.actualVolumes(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 || len(existingVolumes) > 0 {
+		return fmt.Errorf("project %q already exists on context %q: %w", project.Name, options.Context, api.ErrAlreadyExists)
+	}
+
+	if options.Push {
+		err = s.push(ctx, project, api.PushOptions{})
+	} else {
+		err = s.transferImages(ctx, target, project)
+	}
+	if err != nil {
+		return err
+	}
+
+	// source project is stopped before volumes are copied, so data is consistent
+	if options.Cutover {
+		if err := s.stop(ctx, project.Name, api.StopOptions{Project: project}, nil); err != nil {
+			return err
+		}
+	}
+
+	volumes, err := s.actualVolumes(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	var transfers []string
+	for _, key := range project.VolumeNames() {
+		if _, ok := volumes[key]; ok && !bool(project.Volumes[key].External) {
+			transfers = append(transfers, key)
+		}
+	}
+	if len(transfers) > 0 {
+		if _, err := target.ensureProjectVolumes(ctx, project); err != nil {
+			return err
+		}
+		for _, key := range transfers {
+			if err := s.transferVolume(ctx, target, volumes[key].Name, project.Volumes[key].Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := target.create(ctx, project, api.CreateOptions{}); err != nil {
+		return err
+	}
+	return target.start(ctx, project.Name, api.StartOptions{
+		Project:     project,
+		Wait:        true,
+		WaitTimeout: options.WaitTimeout,
+	}, nil)
+}
+
+// onContext returns a copy of the service, operating on the engine of another Docker context
+func (s *composeService) onContext(ctx context.Context, name string) (*composeService, error) {
+	cli, err := command.NewDockerCli(
+		command.WithInputStream(s.stdin()),
+		command.WithOutputStream(s.stdout()),
+		command.WithErrorStream(s.stderr()))
+	if err != nil {
+		return nil, err
+	}
+	options := flags.NewClientOptions()
+	options.Context = name
+	if err := cli.Initialize(options); err != nil {
+		return nil, fmt.Errorf("failed to initialize context %q: %w", name, err)
+	}
+	target := *s
+	target.dockerCli = cli
+	if s.dryRun {
+		if err := target.wrapDockerCliForDryRun(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := target.apiClient().Ping(ctx); err != nil {
+		return nil, fmt.Errorf("can't connect to context %q: %w", name, err)
+	}
+	return &target, nil
+}
+
+// transferImages copies images used by the project from the source engine into the target one.
+// Images not available on source engine are ignored, as target engine will pull them on create.
+func (s *composeService) transferImages(ctx context.Context, target *composeService, project *types.Project) error {
+	var images []string
+	for _, name := range project.ServiceNames() {
+		image := api.GetImageNameOrDefault(project.Services[name], project.Name)
+		if slices.Contains(images, image) {
+			continue
+		}
+		if _, err := s.apiClient().ImageInspect(ctx, image); err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		images = append(images, image)
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	for _, image := range images {
+		s.events.On(newEvent("Image "+image, api.Working, "Transferring"))
+	}
+	err := s.copyImages(ctx, target, images)
+	for _, image := range images {
+		if err != nil {
+			s.events.On(errorEvent("Image "+image, err.Error()))
+			continue
+		}
+		s.events.On(newEvent("Image "+image, api.Done, "Transferred"))
+	}
+	return err
+}
+
+func (s *composeService) copyImages(ctx context.Context, target *composeService, images []string) error {
+	saved, err := s.apiClient().ImageSave(ctx, images)
+	if err != nil {
+		return err
+	}
+	defer saved.Close() //nolint:errcheck
+
+	loaded, err := target.apiClient().ImageLoad(ctx, saved, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return err
+	}
+	defer loaded.Body.Close() //nolint:errcheck
+	if !loaded.JSON {
+		_, err = io.Copy(io.Discard, loaded.Body)
+		return err
+	}
+	return jsonmessage.DisplayJSONMessagesStream(loaded.Body, io.Discard, 0, false, nil)
+}
+
+// transferVolume copies content of a volume into a volume of the target engine, streaming a tar archive
+// between helper containers created, but not started, on both engines
+func (s *composeService) transferVolume(ctx context.Context, target *composeService, source string, name string) error {
+	eventName := "Volume " + name
+	s.events.On(newEvent(eventName, api.Working, "Copying from "+source))
+	if s.dryRun {
+		s.events.On(newEvent(eventName, api.Done, "Copied from "+source))
+		return nil
+	}
+	err := s.copyVolumeTo(ctx, target, source, name)
+	if err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	s.events.On(newEvent(eventName, api.Done, "Copied from "+source))
+	return nil
+}
+
+func (s *composeService) copyVolumeTo(ctx context.Context, target *composeService, source string, name string) error {
+	from, err := s.createVolumeHelper(ctx, source, true)
+	if err != nil {
+		return err
+	}
+	defer s.removeHelper(ctx, from)
+
+	to, err := target.createVolumeHelper(ctx, name, false)
+	if err != nil {
+		return err
+	}
+	defer target.removeHelper(ctx, to)
+
+	content, _, err := s.apiClient().CopyFromContainer(ctx, from, "/volume")
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+	// archive entries are prefixed by `volume/`, so they get extracted into the volume mounted by target helper
+	return target.apiClient().CopyToContainer(ctx, to, "/", content, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+}
+
+// createVolumeHelper creates a helper container with volume mounted as /volume, to access its content with copy API
+func (s *composeService) createVolumeHelper(ctx context.Context, volume string, readOnly bool) (string, error) {
+	if err := s.ensureHelperImage(ctx, ChownImage); err != nil {
+		return "", err
+	}
+	created, err := s.apiClient().ContainerCreate(ctx, &container.Config{
+		Image: ChownImage,
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: volume, Target: "/volume", ReadOnly: readOnly},
+		},
+		NetworkMode: "none",
+	}, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (s *composeService) removeHelper(ctx context.Context, id string) {
+	_ = s.apiClient().ContainerRemove(context.WithoutCancel(ctx), id, container.RemoveOptions{Force: true})
+}
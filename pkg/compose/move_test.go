@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/image"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestMoveSameContext(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	_, mockCli := prepareMocks(mockCtrl)
+	mockCli.EXPECT().CurrentContext().Return("prod").AnyTimes()
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	err := tested.move(t.Context(), &types.Project{Name: "myproject"}, api.MoveOptions{Context: "prod"})
+	assert.ErrorContains(t, err, `project "myproject" is already on context "prod"`)
+}
+
+func TestTransferImagesSkipsMissing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"web": {Name: "web", Image: "nginx"},
+			"app": {Name: "app", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	mockApi.EXPECT().ImageInspect(gomock.Any(), "nginx").Return(image.InspectResponse{}, errdefs.ErrNotFound)
+	mockApi.EXPECT().ImageInspect(gomock.Any(), "myproject-app").Return(image.InspectResponse{}, errdefs.ErrNotFound)
+
+	// no image to transfer, target engine is not used
+	err := tested.transferImages(t.Context(), nil, project)
+	assert.NilError(t, err)
+}
@@ -0,0 +1,358 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+const (
+	// DefaultNetworkDoctorImage is used to run network probes, as it provides both nslookup and nc
+	DefaultNetworkDoctorImage = "busybox:stable"
+	defaultProbeTimeout       = 2 * time.Second
+)
+
+func (s *composeService) NetworkDoctor(ctx context.Context, project *types.Project, options api.NetworkDoctorOptions) (api.NetworkDiagnosis, error) {
+	if options.Image == "" {
+		options.Image = DefaultNetworkDoctorImage
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultProbeTimeout
+	}
+	services := slices.Clone(options.Services)
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	slices.Sort(services)
+
+	diagnosis := api.NetworkDiagnosis{
+		Issues: diagnoseNetworks(project, services),
+	}
+
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, false, services...)
+	if err != nil {
+		return diagnosis, err
+	}
+	if len(containers) > 0 && !s.dryRun {
+		if err := s.ensureHelperImage(ctx, options.Image); err != nil {
+			return diagnosis, err
+		}
+	}
+
+	probes := map[string]map[string]api.NetworkProbe{}
+	for _, from := range services {
+		targets := networkPeers(project, from, services)
+		if len(targets) == 0 {
+			continue
+		}
+		results, err := s.probeFrom(ctx, project, from, containers.filter(isService(from)).sorted(), targets, options)
+		if err != nil {
+			return diagnosis, err
+		}
+		probes[from] = results
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(project.Networks)) {
+		report := api.NetworkReport{
+			Network:  name,
+			Internal: project.Networks[name].Internal,
+		}
+		for _, service := range services {
+			if slices.Contains(serviceNetworks(project, service), name) {
+				report.Services = append(report.Services, service)
+			}
+		}
+		if len(report.Services) == 0 {
+			continue
+		}
+		for _, from := range report.Services {
+			for _, to := range report.Services {
+				if probe, ok := probes[from][to]; ok {
+					report.Probes = append(report.Probes, probe)
+				}
+			}
+		}
+		diagnosis.Networks = append(diagnosis.Networks, report)
+	}
+
+	for _, from := range services {
+		for _, to := range services {
+			probe, ok := probes[from][to]
+			if !ok || probe.Error != "" || s.dryRun {
+				continue
+			}
+			if len(probe.Addresses) == 0 {
+				diagnosis.Issues = append(diagnosis.Issues, fmt.Sprintf("service %q can't resolve %q", from, to))
+				continue
+			}
+			for _, port := range probe.Ports {
+				if !port.Reachable {
+					diagnosis.Issues = append(diagnosis.Issues, fmt.Sprintf("service %q can't reach %q on port %d", from, to, port.Port))
+				}
+			}
+		}
+	}
+	return diagnosis, nil
+}
+
+// networkPeers returns the services sharing at least one network with service
+func networkPeers(project *types.Project, service string, services []string) []string {
+	networks := serviceNetworks(project, service)
+	var peers []string
+	for _, other := range services {
+		if other == service {
+			continue
+		}
+		for _, nw := range serviceNetworks(project, other) {
+			if slices.Contains(networks, nw) {
+				peers = append(peers, other)
+				break
+			}
+		}
+	}
+	return peers
+}
+
+func serviceNetworks(project *types.Project, name string) []string {
+	service := project.Services[name]
+	return service.NetworksByPriority()
+}
+
+// servicePorts returns the TCP ports a service is expected to listen to
+func servicePorts(service types.ServiceConfig) []uint32 {
+	var ports []uint32
+	for _, p := range service.Ports {
+		if p.Protocol == "" || p.Protocol == "tcp" {
+			ports = append(ports, p.Target)
+		}
+	}
+	for _, e := range service.Expose {
+		p, proto, _ := strings.Cut(e, "/")
+		if proto != "" && proto != "tcp" {
+			continue
+		}
+		p, _, _ = strings.Cut(p, "-")
+		if port, err := strconv.ParseUint(p, 10, 32); err == nil {
+			ports = append(ports, uint32(port))
+		}
+	}
+	slices.Sort(ports)
+	return slices.Compact(ports)
+}
+
+// probeFrom runs DNS and connectivity checks to targets, within the network namespace of a service container
+func (s *composeService) probeFrom(ctx context.Context, project *types.Project, from string, containers Containers, targets []string, options api.NetworkDoctorOptions) (map[string]api.NetworkProbe, error) {
+	results := map[string]api.NetworkProbe{}
+	ports := map[string][]uint32{}
+	for _, target := range targets {
+		ports[target] = servicePorts(project.Services[target])
+		probe := api.NetworkProbe{
+			From: from,
+			To:   target,
+		}
+		if len(containers) == 0 {
+			probe.Error = "service is not running"
+		}
+		results[target] = probe
+	}
+	if len(containers) == 0 || s.dryRun {
+		return results, nil
+	}
+
+	output, err := s.runProbe(ctx, containers[0].ID, options.Image, probeScript(targets, ports, options.Timeout))
+	if err != nil {
+		return nil, err
+	}
+	for target, probe := range parseProbeOutput(output) {
+		if r, ok := results[target]; ok {
+			r.Addresses = probe.Addresses
+			r.Ports = probe.Ports
+			results[target] = r
+		}
+	}
+	return results, nil
+}
+
+// runProbe runs script in a short-lived container sharing the network namespace of container id, and returns its output
+func (s *composeService) runProbe(ctx context.Context, id string, image string, script string) (string, error) {
+	output, _, err := s.runHelper(ctx, &container.Config{
+		Image:      image,
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{script},
+	}, &container.HostConfig{
+		NetworkMode: container.NetworkMode("container:" + id),
+	})
+	return output, err
+}
+
+// probeScript produces a shell script reporting, for each target, resolved addresses and reachable ports as:
+//
+//	dns <target> <address>...
+//	tcp <target> <port> ok|fail
+func probeScript(targets []string, ports map[string][]uint32, timeout time.Duration) string {
+	seconds := int(math.Ceil(timeout.Seconds()))
+	var sb strings.Builder
+	for _, target := range targets {
+		fmt.Fprintf(&sb, `echo "dns %[1]s $(nslookup %[1]s 2>/dev/null | awk '/^Address: / && !/:53$/ {print $2}' | tr '\n' ' ')"`+"\n", target)
+		for _, port := range ports[target] {
+			fmt.Fprintf(&sb, `if nc -z -w %[3]d %[1]s %[2]d; then echo "tcp %[1]s %[2]d ok"; else echo "tcp %[1]s %[2]d fail"; fi`+"\n", target, port, seconds)
+		}
+	}
+	return sb.String()
+}
+
+func parseProbeOutput(output string) map[string]api.NetworkProbe {
+	probes := map[string]api.NetworkProbe{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		target := fields[1]
+		probe := probes[target]
+		switch fields[0] {
+		case "dns":
+			for _, addr := range fields[2:] {
+				if net.ParseIP(addr) != nil {
+					probe.Addresses = append(probe.Addresses, addr)
+				}
+			}
+		case "tcp":
+			if len(fields) != 4 {
+				continue
+			}
+			port, err := strconv.ParseUint(fields[2], 10, 32)
+			if err != nil {
+				continue
+			}
+			probe.Ports = append(probe.Ports, api.PortProbe{
+				Port:      uint32(port),
+				Reachable: fields[3] == "ok",
+			})
+		default:
+			continue
+		}
+		probes[target] = probe
+	}
+	return probes
+}
+
+// diagnoseNetworks detects common network misconfigurations in the project model
+func diagnoseNetworks(project *types.Project, services []string) []string {
+	var issues []string
+	for _, name := range services {
+		service := project.Services[name]
+		if service.NetworkMode != "" {
+			continue
+		}
+		networks := service.NetworksByPriority()
+		names := resolvableNames(project, networks)
+		for _, link := range service.Links {
+			if _, alias, ok := strings.Cut(link, ":"); ok {
+				names = append(names, alias)
+			}
+		}
+
+		for _, dep := range slices.Sorted(maps.Keys(service.DependsOn)) {
+			other, ok := project.Services[dep]
+			if !ok || other.NetworkMode != "" {
+				continue
+			}
+			if !slices.ContainsFunc(other.NetworksByPriority(), func(nw string) bool {
+				return slices.Contains(networks, nw)
+			}) {
+				issues = append(issues, fmt.Sprintf("service %q depends on %q but they share no network", name, dep))
+			}
+		}
+
+		if len(service.Ports) > 0 && len(networks) > 0 && !slices.ContainsFunc(networks, func(nw string) bool {
+			return !project.Networks[nw].Internal
+		}) {
+			issues = append(issues, fmt.Sprintf("service %q publishes ports but is only attached to internal networks, they won't be reachable from host", name))
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(service.Environment)) {
+			value := service.Environment[key]
+			if value == nil || !isHostVariable(key) {
+				continue
+			}
+			host := hostFromValue(*value)
+			if host == "" || host == "localhost" || net.ParseIP(host) != nil || strings.Contains(host, ".") {
+				continue
+			}
+			if !slices.Contains(names, host) {
+				issues = append(issues, fmt.Sprintf("service %q refers to host %q in %s, which is not a service name or alias on its networks", name, host, key))
+			}
+		}
+	}
+	return issues
+}
+
+func isHostVariable(key string) bool {
+	key = strings.ToUpper(key)
+	return strings.HasSuffix(key, "_HOST") || strings.HasSuffix(key, "_HOSTNAME")
+}
+
+// hostFromValue extracts the host part of a `host`, `host:port` or URL value
+func hostFromValue(value string) string {
+	if _, rest, ok := strings.Cut(value, "://"); ok {
+		value = rest
+		if _, after, ok := strings.Cut(value, "@"); ok {
+			value = after
+		}
+		value, _, _ = strings.Cut(value, "/")
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return host
+	}
+	return value
+}
+
+// resolvableNames returns the names which can be resolved by the embedded DNS server on networks
+func resolvableNames(project *types.Project, networks []string) []string {
+	var names []string
+	for name, service := range project.Services {
+		for nw, config := range service.Networks {
+			if !slices.Contains(networks, nw) {
+				continue
+			}
+			names = append(names, name)
+			if service.ContainerName != "" {
+				names = append(names, service.ContainerName)
+			}
+			if config != nil {
+				names = append(names, config.Aliases...)
+			}
+		}
+	}
+	return names
+}
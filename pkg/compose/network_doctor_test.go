@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDiagnoseNetworks(t *testing.T) {
+	dbHost := "database:5432"
+	cacheURL := "redis://cache:6379/0"
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"web": {
+				Name:      "web",
+				Ports:     []types.ServicePortConfig{{Target: 80}},
+				Networks:  map[string]*types.ServiceNetworkConfig{"front": nil},
+				DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionStarted}},
+				Environment: types.MappingWithEquals{
+					"DB_HOST":    &dbHost,
+					"CACHE_HOST": &cacheURL,
+				},
+			},
+			"db": {
+				Name:     "db",
+				Networks: map[string]*types.ServiceNetworkConfig{"back": {Aliases: []string{"database"}}},
+			},
+			"cache": {
+				Name:     "cache",
+				Ports:    []types.ServicePortConfig{{Target: 6379}},
+				Networks: map[string]*types.ServiceNetworkConfig{"back": nil},
+			},
+		},
+		Networks: types.Networks{
+			"front": {Name: "demo_front"},
+			"back":  {Name: "demo_back", Internal: true},
+		},
+	}
+	issues := diagnoseNetworks(project, project.ServiceNames())
+	assert.DeepEqual(t, issues, []string{
+		`service "cache" publishes ports but is only attached to internal networks, they won't be reachable from host`,
+		`service "web" depends on "db" but they share no network`,
+		`service "web" refers to host "cache" in CACHE_HOST, which is not a service name or alias on its networks`,
+		`service "web" refers to host "database" in DB_HOST, which is not a service name or alias on its networks`,
+	})
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	output := `dns db 172.18.0.2 
+tcp db 5432 ok
+tcp db 8080 fail
+dns cache 
+`
+	probes := parseProbeOutput(output)
+	assert.DeepEqual(t, probes, map[string]api.NetworkProbe{
+		"db": {
+			Addresses: []string{"172.18.0.2"},
+			Ports: []api.PortProbe{
+				{Port: 5432, Reachable: true},
+				{Port: 8080, Reachable: false},
+			},
+		},
+		"cache": {},
+	})
+}
+
+func TestHostFromValue(t *testing.T) {
+	assert.Equal(t, hostFromValue("db"), "db")
+	assert.Equal(t, hostFromValue("db:5432"), "db")
+	assert.Equal(t, hostFromValue("postgres://user:secret@db:5432/app"), "db")
+}
@@ -0,0 +1,266 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Networks(ctx context.Context, projectName string, options api.NetworksOptions) ([]api.NetworkSummary, error) {
+	networks, err := s.apiClient().NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(projectFilter(projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	containers, err := s.getContainers(ctx, projectName, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// index services by the networks their containers are attached to
+	attached := map[string][]string{}
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		service := c.Labels[api.ServiceLabel]
+		for name := range c.NetworkSettings.Networks {
+			if !slices.Contains(attached[name], service) {
+				attached[name] = append(attached[name], service)
+			}
+		}
+	}
+
+	var summaries []api.NetworkSummary
+	for _, nw := range networks {
+		services := attached[nw.Name]
+		slices.Sort(services)
+		if len(options.Services) > 0 && !slices.ContainsFunc(options.Services, func(s string) bool {
+			return slices.Contains(services, s)
+		}) {
+			continue
+		}
+		summaries = append(summaries, api.NetworkSummary{
+			Network:  nw.Labels[api.NetworkLabel],
+			ID:       nw.ID,
+			Name:     nw.Name,
+			Driver:   nw.Driver,
+			Scope:    nw.Scope,
+			Internal: nw.Internal,
+			Services: services,
+		})
+	}
+	slices.SortFunc(summaries, func(a, b api.NetworkSummary) int {
+		return strings.Compare(a.Network, b.Network)
+	})
+	return summaries, nil
+}
+
+func (s *composeService) NetworkInspect(ctx context.Context, projectName string, name string) (api.NetworkInspect, error) {
+	nw, err := s.getProjectNetwork(ctx, projectName, name)
+	if err != nil {
+		return api.NetworkInspect{}, err
+	}
+	return s.apiClient().NetworkInspect(ctx, nw.ID, network.InspectOptions{Verbose: true})
+}
+
+// getProjectNetwork looks up a network created by compose for project
+func (s *composeService) getProjectNetwork(ctx context.Context, projectName string, name string) (network.Summary, error) {
+	networks, err := s.apiClient().NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(projectFilter(projectName), networkFilter(name)),
+	})
+	if err != nil {
+		return network.Summary{}, err
+	}
+	if len(networks) == 0 {
+		return network.Summary{}, fmt.Errorf("no such network %q in project %q: %w", name, projectName, api.ErrNotFound)
+	}
+	return networks[0], nil
+}
+
+func (s *composeService) NetworkConnect(ctx context.Context, project *types.Project, options api.NetworkConnectOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.networkConnect(ctx, project, options)
+	}, "network", s.events)
+}
+
+func (s *composeService) networkConnect(ctx context.Context, project *types.Project, options api.NetworkConnectOptions) error {
+	prepareNetworks(project)
+	nw, ok := project.Networks[options.Network]
+	if !ok {
+		return fmt.Errorf("network %q is not declared by project %q", options.Network, project.Name)
+	}
+	if _, err := s.ensureNetwork(ctx, project, options.Network, &nw); err != nil {
+		return err
+	}
+
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, options.Services...)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers.sorted() {
+		if isAttached(c, nw.Name) {
+			continue
+		}
+		service, err := project.GetService(c.Labels[api.ServiceLabel])
+		if err != nil {
+			return err
+		}
+		if service.NetworkMode != "" {
+			return fmt.Errorf("service %q uses network_mode %q and can't be connected to network %q", service.Name, service.NetworkMode, options.Network)
+		}
+		number, _ := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+		endpoint := createEndpointSettings(project, service, number, options.Network, nil, true)
+		endpoint.Aliases = append(endpoint.Aliases, options.Aliases...)
+
+		eventName := getContainerProgressName(c)
+		if err := s.apiClient().NetworkConnect(ctx, nw.Name, c.ID, endpoint); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return err
+		}
+		s.events.On(newEvent(eventName, api.Done, "Connected to "+nw.Name))
+	}
+	return nil
+}
+
+func (s *composeService) NetworkDisconnect(ctx context.Context, projectName string, options api.NetworkDisconnectOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.networkDisconnect(ctx, projectName, options)
+	}, "network", s.events)
+}
+
+func (s *composeService) networkDisconnect(ctx context.Context, projectName string, options api.NetworkDisconnectOptions) error {
+	nw, err := s.getProjectNetwork(ctx, projectName, options.Network)
+	if err != nil {
+		return err
+	}
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, true, options.Services...)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers.sorted() {
+		if !isAttached(c, nw.Name) {
+			continue
+		}
+		eventName := getContainerProgressName(c)
+		if err := s.apiClient().NetworkDisconnect(ctx, nw.ID, c.ID, options.Force); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return err
+		}
+		s.events.On(newEvent(eventName, api.Done, "Disconnected from "+nw.Name))
+	}
+	return nil
+}
+
+func (s *composeService) NetworkRecreate(ctx context.Context, project *types.Project, options api.NetworkRecreateOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.networkRecreate(ctx, project, options)
+	}, "network", s.events)
+}
+
+func (s *composeService) networkRecreate(ctx context.Context, project *types.Project, options api.NetworkRecreateOptions) error {
+	prepareNetworks(project)
+	nw, ok := project.Networks[options.Network]
+	if !ok {
+		return fmt.Errorf("network %q is not declared by project %q", options.Network, project.Name)
+	}
+	if nw.External {
+		return fmt.Errorf("network %q is external and can't be recreated", options.Network)
+	}
+
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return err
+	}
+	containers = containers.filter(func(c container.Summary) bool {
+		return isAttached(c, nw.Name)
+	}).sorted()
+	running := containers.filter(func(c container.Summary) bool {
+		return c.State == container.StateRunning
+	})
+
+	// containers must be stopped so they can be disconnected and the network removed
+	for _, c := range running {
+		eventName := getContainerProgressName(c)
+		s.events.On(stoppingEvent(eventName))
+		if err := s.apiClient().ContainerStop(ctx, c.ID, container.StopOptions{}); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return err
+		}
+		s.events.On(stoppedEvent(eventName))
+	}
+	if err := s.disconnectNetwork(ctx, nw.Name, containers); err != nil {
+		return err
+	}
+	eventName := fmt.Sprintf("Network %s", nw.Name)
+	s.events.On(removingEvent(eventName))
+	if err := s.apiClient().NetworkRemove(ctx, nw.Name); err != nil && !errdefs.IsNotFound(err) {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	s.events.On(removedEvent(eventName))
+
+	if _, err := s.ensureNetwork(ctx, project, options.Network, &nw); err != nil {
+		return err
+	}
+	project.Networks[options.Network] = nw
+
+	for _, c := range containers {
+		service, err := project.GetService(c.Labels[api.ServiceLabel])
+		if err != nil {
+			// service has been removed from the compose file, container still gets reconnected without custom settings
+			service = types.ServiceConfig{Name: c.Labels[api.ServiceLabel]}
+		}
+		number, _ := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+		endpoint := createEndpointSettings(project, service, number, options.Network, nil, true)
+		if err := s.apiClient().NetworkConnect(ctx, nw.Name, c.ID, endpoint); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range running {
+		eventName := getContainerProgressName(c)
+		s.events.On(startingEvent(eventName))
+		if err := s.apiClient().ContainerStart(ctx, c.ID, container.StartOptions{}); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return err
+		}
+		s.events.On(startedEvent(eventName))
+	}
+	return nil
+}
+
+func isAttached(c container.Summary, networkName string) bool {
+	if c.NetworkSettings == nil {
+		return false
+	}
+	_, ok := c.NetworkSettings.Networks[networkName]
+	return ok
+}
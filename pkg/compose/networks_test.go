@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestNetworks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	front := network.Summary{ID: "1", Name: testProject + "_front", Driver: "bridge", Labels: map[string]string{api.NetworkLabel: "front"}}
+	back := network.Summary{ID: "2", Name: testProject + "_back", Driver: "bridge", Internal: true, Labels: map[string]string{api.NetworkLabel: "back"}}
+
+	web := container.Summary{
+		Labels: map[string]string{api.ServiceLabel: "web"},
+		NetworkSettings: &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{
+			testProject + "_front": {},
+			testProject + "_back":  {},
+		}},
+	}
+	db := container.Summary{
+		Labels: map[string]string{api.ServiceLabel: "db"},
+		NetworkSettings: &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{
+			testProject + "_back": {},
+		}},
+	}
+
+	ctx := context.Background()
+	mockApi.EXPECT().NetworkList(ctx, gomock.Any()).Times(2).Return([]network.Summary{front, back}, nil)
+	mockApi.EXPECT().ContainerList(ctx, gomock.Any()).Times(2).Return([]container.Summary{web, db}, nil)
+
+	networks, err := tested.Networks(ctx, testProject, api.NetworksOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, networks, []api.NetworkSummary{
+		{Network: "back", ID: "2", Name: testProject + "_back", Driver: "bridge", Internal: true, Services: []string{"db", "web"}},
+		{Network: "front", ID: "1", Name: testProject + "_front", Driver: "bridge", Services: []string{"web"}},
+	})
+
+	networks, err = tested.Networks(ctx, testProject, api.NetworksOptions{Services: []string{"db"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(networks), 1)
+	assert.Equal(t, networks[0].Network, "back")
+}
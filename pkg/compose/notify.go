@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sync"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// serviceNotifier forwards service lifecycle events to api.UpOptions.Notify, skipping repeated ones
+// as a service being waited for by multiple dependants would otherwise be reported healthy many times
+type serviceNotifier struct {
+	mu     sync.Mutex
+	notify func(api.ServiceEvent)
+	last   map[string]api.ServiceEventStatus
+}
+
+type serviceNotifierKey struct{}
+
+func withServiceNotifier(ctx context.Context, notify func(api.ServiceEvent)) context.Context {
+	if notify == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, serviceNotifierKey{}, &serviceNotifier{
+		notify: notify,
+		last:   map[string]api.ServiceEventStatus{},
+	})
+}
+
+// notifyService reports service reached status, if a notifier has been set for current operation
+func notifyService(ctx context.Context, service string, status api.ServiceEventStatus, err error) {
+	n, _ := ctx.Value(serviceNotifierKey{}).(*serviceNotifier)
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.last[service] == status {
+		return
+	}
+	n.last[service] = status
+	n.notify(api.ServiceEvent{Service: service, Status: status, Error: err})
+}
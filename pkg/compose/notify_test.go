@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestNotifyService(t *testing.T) {
+	var events []api.ServiceEvent
+	ctx := withServiceNotifier(t.Context(), func(e api.ServiceEvent) {
+		events = append(events, e)
+	})
+	failure := errors.New("boom")
+
+	notifyService(ctx, "db", api.ServiceStarting, nil)
+	notifyService(ctx, "db", api.ServiceHealthy, nil)
+	// reported again as another service waits for db
+	notifyService(ctx, "db", api.ServiceHealthy, nil)
+	notifyService(ctx, "web", api.ServiceFailed, failure)
+
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[0], api.ServiceEvent{Service: "db", Status: api.ServiceStarting})
+	assert.Equal(t, events[1], api.ServiceEvent{Service: "db", Status: api.ServiceHealthy})
+	assert.Equal(t, events[2], api.ServiceEvent{Service: "web", Status: api.ServiceFailed, Error: failure})
+}
+
+func TestNotifyServiceWithoutNotifier(t *testing.T) {
+	ctx := withServiceNotifier(t.Context(), nil)
+	// must not panic
+	notifyService(ctx, "db", api.ServiceStarting, nil)
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// OneoffTTLExtension is the project extension setting the duration after which one-off containers, once stopped,
+// are removed by Up or Down. `run --rm-after` takes precedence:
+//
+//	x-oneoff_ttl: 24h
+const OneoffTTLExtension = "x-oneoff_ttl"
+
+// getOneoffTTL returns the duration after which a one-off container can be removed, 0 if it must be kept
+func getOneoffTTL(project *types.Project, opts api.RunOneOffOptions) (time.Duration, error) {
+	if opts.AutoRemove {
+		return 0, nil
+	}
+	if opts.RemoveAfter > 0 {
+		return opts.RemoveAfter, nil
+	}
+	v, ok := project.Extensions[OneoffTTLExtension]
+	if !ok {
+		return 0, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s: expected a duration, got %v", OneoffTTLExtension, v)
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", OneoffTTLExtension, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid %s %s: must be positive", OneoffTTLExtension, s)
+	}
+	return ttl, nil
+}
+
+// hasOneoffTTL selects stopped one-off containers with a time to live set by OneoffTTLLabel
+func hasOneoffTTL(c container.Summary) bool {
+	switch c.State {
+	case container.StateRunning, container.StatePaused, container.StateRestarting, container.StateCreated:
+		return false
+	}
+	_, ok := c.Labels[api.OneoffTTLLabel]
+	return ok
+}
+
+// isExpiredOneOff tells if one-off container stopped for longer than its time to live
+func isExpiredOneOff(inspect container.InspectResponse, now time.Time) bool {
+	if inspect.Config == nil || inspect.State == nil {
+		return false
+	}
+	ttl, err := time.ParseDuration(inspect.Config.Labels[api.OneoffTTLLabel])
+	if err != nil {
+		return false
+	}
+	finished, err := time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+	if err != nil || finished.IsZero() {
+		return false
+	}
+	return now.After(finished.Add(ttl))
+}
+
+// removeExpiredOneOffs garbage collects one-off containers left by `run`. Failures are not fatal, as those
+// containers will be removed by a later pass.
+func (s *composeService) removeExpiredOneOffs(ctx context.Context, projectName string) {
+	containers, err := s.getContainers(ctx, projectName, oneOffOnly, true)
+	if err != nil {
+		logrus.Warnf("failed to list one-off containers: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, c := range containers.filter(hasOneoffTTL) {
+		// expiration is computed from the time container stopped, which isn't known until then
+		inspect, err := s.apiClient().ContainerInspect(ctx, c.ID)
+		if err != nil {
+			if !errdefs.IsNotFound(err) {
+				logrus.Warnf("failed to inspect one-off container %s: %v", getCanonicalContainerName(c), err)
+			}
+			continue
+		}
+		if !isExpiredOneOff(inspect, now) {
+			continue
+		}
+		eventName := getContainerProgressName(c)
+		s.events.On(removingEvent(eventName))
+		err = s.apiClient().ContainerRemove(ctx, c.ID, container.RemoveOptions{RemoveVolumes: true})
+		if err != nil && !errdefs.IsNotFound(err) {
+			logrus.Warnf("failed to remove expired one-off container %s: %v", getCanonicalContainerName(c), err)
+			s.events.On(newEvent(eventName, api.Warning, "Not removed"))
+			continue
+		}
+		s.events.On(removedEvent(eventName))
+	}
+}
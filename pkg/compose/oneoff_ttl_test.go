@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestGetOneoffTTL(t *testing.T) {
+	project := &types.Project{Extensions: types.Extensions{OneoffTTLExtension: "24h"}}
+
+	ttl, err := getOneoffTTL(project, api.RunOneOffOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, 24*time.Hour)
+
+	ttl, err = getOneoffTTL(project, api.RunOneOffOptions{RemoveAfter: time.Hour})
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, time.Hour)
+
+	ttl, err = getOneoffTTL(project, api.RunOneOffOptions{AutoRemove: true})
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, time.Duration(0))
+
+	ttl, err = getOneoffTTL(&types.Project{}, api.RunOneOffOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, time.Duration(0))
+
+	_, err = getOneoffTTL(&types.Project{Extensions: types.Extensions{OneoffTTLExtension: "-1h"}}, api.RunOneOffOptions{})
+	assert.Error(t, err, "invalid x-oneoff_ttl -1h: must be positive")
+}
+
+func TestRemoveExpiredOneOffs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: mockCli, events: &ignore{}}
+
+	oneOff := func(id string, state container.ContainerState, ttl string) container.Summary {
+		c := testContainer("service1", id, true)
+		c.State = state
+		if ttl != "" {
+			c.Labels[api.OneoffTTLLabel] = ttl
+		}
+		return c
+	}
+	inspect := func(c container.Summary, finished time.Time) container.InspectResponse {
+		return container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				ID:    c.ID,
+				State: &container.State{FinishedAt: finished.UTC().Format(time.RFC3339Nano)},
+			},
+			Config: &container.Config{Labels: c.Labels},
+		}
+	}
+	// created long ago, but stopped recently: TTL applies from the time container stopped
+	expired := oneOff("expired", container.StateExited, "1h")
+	pending := oneOff("pending", container.StateExited, "1h")
+	mockApi.EXPECT().ContainerList(gomock.Any(), oneOffListOpt()).Return([]container.Summary{
+		expired,
+		oneOff("running", container.StateRunning, "1h"),
+		pending,
+		oneOff("kept", container.StateExited, ""),
+	}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "expired").Return(inspect(expired, time.Now().Add(-2*time.Hour)), nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "pending").Return(inspect(pending, time.Now().Add(-time.Minute)), nil)
+	mockApi.EXPECT().ContainerRemove(gomock.Any(), "expired", container.RemoveOptions{RemoveVolumes: true}).Return(nil)
+
+	tested.removeExpiredOneOffs(t.Context(), strings.ToLower(testProject))
+}
@@ -0,0 +1,131 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Orphans(ctx context.Context, project *types.Project) ([]api.OrphanSummary, error) {
+	containers, err := s.getContainers(ctx, project.Name, oneOffInclude, true)
+	if err != nil {
+		return nil, err
+	}
+	services := append(project.ServiceNames(), project.DisabledServiceNames()...)
+	var orphans []api.OrphanSummary
+	for _, c := range containers.sorted() {
+		reason := orphanReason(services, c)
+		if reason == "" {
+			continue
+		}
+		orphans = append(orphans, api.OrphanSummary{
+			ID:      c.ID,
+			Name:    getCanonicalContainerName(c),
+			Service: c.Labels[api.ServiceLabel],
+			Image:   c.Image,
+			State:   c.State,
+			Reason:  reason,
+		})
+	}
+	return orphans, nil
+}
+
+func (s *composeService) Adopt(ctx context.Context, project *types.Project, options api.AdoptOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.adopt(ctx, project, options)
+	}, "adopt", s.events)
+}
+
+func (s *composeService) adopt(ctx context.Context, project *types.Project, options api.AdoptOptions) error {
+	service, err := project.GetService(options.Service)
+	if err != nil {
+		return err
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return err
+	}
+	orphan, ok := findContainer(containers, options.Container)
+	if !ok {
+		return fmt.Errorf("no such container %q in project %q: %w", options.Container, project.Name, api.ErrNotFound)
+	}
+	services := append(project.ServiceNames(), project.DisabledServiceNames()...)
+	if orphanReason(services, orphan) == "" {
+		return fmt.Errorf("container %s is not an orphan, it belongs to service %q", getCanonicalContainerName(orphan), orphan.Labels[api.ServiceLabel])
+	}
+	if err := s.checkAdoptable(ctx, project, service, orphan); err != nil {
+		return err
+	}
+
+	existing := containers.filter(isService(service.Name))
+	if service.ContainerName != "" && len(existing) > 0 {
+		return fmt.Errorf("service %q already has container %s, as declared by container_name", service.Name, service.ContainerName)
+	}
+
+	eventName := getContainerProgressName(orphan)
+	s.events.On(newEvent(eventName, api.Working, "Adopting"))
+	opts := createOptions{
+		UseNetworkAliases: true,
+		Labels:            mergeLabels(service.Labels, service.CustomLabels),
+	}
+	// orphan container is replaced by one with service labels, inheriting its anonymous volumes
+	created, err := s.replaceContainer(ctx, project, service, orphan, nextContainerNumber(existing), &orphan, opts, options.Timeout)
+	if err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	if orphan.State == container.StateRunning {
+		if err := s.apiClient().ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return err
+		}
+	}
+	s.events.On(newEvent(eventName, api.Done, "Adopted by "+service.Name))
+	return nil
+}
+
+// findContainer selects a container by name or ID prefix
+func findContainer(containers Containers, nameOrID string) (container.Summary, bool) {
+	for _, c := range containers {
+		if getCanonicalContainerName(c) == nameOrID || nameOrID != "" && strings.HasPrefix(c.ID, nameOrID) {
+			return c, true
+		}
+	}
+	return container.Summary{}, false
+}
+
+// checkAdoptable verifies orphan container runs the image service is configured with, so it can be replaced
+// by a service container without unexpected changes
+func (s *composeService) checkAdoptable(ctx context.Context, project *types.Project, service types.ServiceConfig, orphan container.Summary) error {
+	imageName := api.GetImageNameOrDefault(service, project.Name)
+	if orphan.Image == imageName {
+		return nil
+	}
+	img, err := s.apiClient().ImageInspect(ctx, imageName)
+	if err == nil && img.ID == orphan.ImageID {
+		return nil
+	}
+	return fmt.Errorf("container %s doesn't match service %q: it runs image %s, service uses %s",
+		getCanonicalContainerName(orphan), service.Name, orphan.Image, imageName)
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestOrphans(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	exited := testContainer("web", "run1", true)
+	running := testContainer("web", "run2", true)
+	running.State = container.StateRunning
+	// init jobs containers are kept once exited, unless their service is removed
+	job := testContainer("web", "job1", true)
+	job.Labels[api.InitJobLabel] = "seed"
+	removedJob := testContainer("worker", "job2", true)
+	removedJob.Labels[api.InitJobLabel] = "seed"
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		testContainer("web", "web1", false),
+		testContainer("worker", "worker1", false),
+		exited,
+		running,
+		job,
+		removedJob,
+	}, nil)
+
+	project := &types.Project{Name: strings.ToLower(testProject), Services: types.Services{
+		"web": {Name: "web"},
+	}}
+	orphans, err := tested.Orphans(t.Context(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, orphans, []api.OrphanSummary{
+		{ID: "job2", Name: "job2", Service: "worker", State: container.StateExited, Reason: `service "worker" is not declared by project`},
+		{ID: "run1", Name: "run1", Service: "web", State: container.StateExited, Reason: "one-off container has exited"},
+		{ID: "worker1", Name: "worker1", Service: "worker", State: container.StateExited, Reason: `service "worker" is not declared by project`},
+	})
+}
+
+func TestIsOrphanedInitJob(t *testing.T) {
+	job := testContainer("db", "job1", true)
+	job.Labels[api.InitJobLabel] = "seed"
+	project := &types.Project{Name: strings.ToLower(testProject), Services: types.Services{
+		"db": {Name: "db"},
+	}}
+	// up --remove-orphans and apply --prune must not remove the record of a completed init job
+	assert.Check(t, !isOrphaned(project)(job))
+	assert.Check(t, isOrphaned(project)(testContainer("db", "run1", true)))
+}
+
+func TestAdoptImageMismatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	orphan := testContainer("worker", "worker1", false)
+	orphan.Image = "alpine"
+	orphan.ImageID = "sha256:alpine"
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		testContainer("web", "web1", false),
+		orphan,
+	}, nil)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "nginx").Return(image.InspectResponse{ID: "sha256:nginx"}, nil)
+
+	project := &types.Project{Name: strings.ToLower(testProject), Services: types.Services{
+		"web": {Name: "web", Image: "nginx"},
+	}}
+	err := tested.adopt(t.Context(), project, api.AdoptOptions{Container: "worker1", Service: "web"})
+	assert.Error(t, err, `container worker1 doesn't match service "web": it runs image alpine, service uses nginx`)
+
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		testContainer("web", "web1", false),
+	}, nil)
+	err = tested.adopt(t.Context(), project, api.AdoptOptions{Container: "web1", Service: "web"})
+	assert.Error(t, err, `container web1 is not an orphan, it belongs to service "web"`)
+}
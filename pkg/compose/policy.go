@@ -0,0 +1,162 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/mattn/go-shellwords"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// policyInput is sent to policy for evaluation
+type policyInput struct {
+	Operation string         `json:"operation"`
+	Project   *types.Project `json:"project"`
+}
+
+// checkPolicy evaluates the configured policy against project, returning api.PolicyDeniedError
+// when operation is denied
+func (s *composeService) checkPolicy(ctx context.Context, operation string, project *types.Project) error {
+	if s.policy == "" || project == nil {
+		return nil
+	}
+	input, err := json.Marshal(policyInput{
+		Operation: operation,
+		Project:   project,
+	})
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if strings.HasSuffix(s.policy, ".rego") {
+		cmd = exec.CommandContext(ctx, "opa", "eval", "--format", "json", "--stdin-input", "--data", s.policy, "data.compose.deny")
+	} else {
+		args, err := shellwords.Parse(s.policy)
+		if err != nil {
+			return fmt.Errorf("invalid policy command %q: %w", s.policy, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("invalid policy command %q", s.policy)
+		}
+		cmd = exec.CommandContext(ctx, args[0], args[1:]...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var violations []api.PolicyViolation
+	if strings.HasSuffix(s.policy, ".rego") {
+		violations, err = parseOpaViolations(stdout.Bytes())
+	} else {
+		violations, err = parsePolicyViolations(stdout.Bytes())
+	}
+	if err != nil || (runErr != nil && len(violations) == 0) {
+		return fmt.Errorf("failed to evaluate policy %s: %w", s.policy, errors.Join(runErr, err, stderrError(stderr)))
+	}
+	if len(violations) > 0 {
+		return &api.PolicyDeniedError{
+			Operation:  operation,
+			Violations: violations,
+		}
+	}
+	return nil
+}
+
+func stderrError(stderr bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
+
+// parsePolicyViolations parses violations reported by a policy command, as a list of violations or
+// an object with a `violations` attribute. Empty output means operation is allowed.
+func parsePolicyViolations(out []byte) ([]api.PolicyViolation, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var report struct {
+		Violations []any `json:"violations"`
+	}
+	if out[0] == '[' {
+		if err := json.Unmarshal(out, &report.Violations); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(out, &report); err != nil {
+		return nil, err
+	}
+	return toViolations(report.Violations)
+}
+
+// parseOpaViolations parses `opa eval --format json` output, evaluating `deny` rules as a set of messages or objects
+func parseOpaViolations(out []byte) ([]api.PolicyViolation, error) {
+	var result struct {
+		Result []struct {
+			Expressions []struct {
+				Value []any `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var values []any
+	for _, r := range result.Result {
+		for _, e := range r.Expressions {
+			values = append(values, e.Value...)
+		}
+	}
+	return toViolations(values)
+}
+
+// toViolations converts violations reported as plain messages or objects
+func toViolations(values []any) ([]api.PolicyViolation, error) {
+	var violations []api.PolicyViolation
+	for _, v := range values {
+		switch value := v.(type) {
+		case string:
+			violations = append(violations, api.PolicyViolation{Message: value})
+		case map[string]any:
+			b, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			var violation api.PolicyViolation
+			if err := json.Unmarshal(b, &violation); err != nil {
+				return nil, err
+			}
+			violations = append(violations, violation)
+		default:
+			return nil, fmt.Errorf("unexpected policy violation %v", v)
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("policy test command relies on a POSIX shell")
+	}
+	script := filepath.Join(t.TempDir(), "policy.sh")
+	err := os.WriteFile(script, []byte(`#!/bin/sh
+input=$(cat)
+if echo "$input" | grep -q '"operation":"up"' && echo "$input" | grep -q 'latest'; then
+  echo '{"violations": [{"rule": "no-latest", "service": "web", "message": "image uses latest tag"}]}'
+fi
+`), 0o755)
+	assert.NilError(t, err)
+
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"web": {Name: "web", Image: "nginx:latest"},
+		},
+	}
+	s := &composeService{policy: script}
+	err = s.checkPolicy(context.Background(), "up", project)
+	var denied *api.PolicyDeniedError
+	assert.Check(t, errors.As(err, &denied))
+	assert.Check(t, errors.Is(err, api.ErrForbidden))
+	assert.DeepEqual(t, denied.Violations, []api.PolicyViolation{
+		{Rule: "no-latest", Service: "web", Message: "image uses latest tag"},
+	})
+	assert.Equal(t, err.Error(), "up denied by policy:\n - [no-latest] service \"web\": image uses latest tag")
+
+	err = s.checkPolicy(context.Background(), "down", project)
+	assert.NilError(t, err)
+
+	s.policy = "false"
+	err = s.checkPolicy(context.Background(), "up", project)
+	assert.ErrorContains(t, err, "failed to evaluate policy false")
+}
+
+func TestParseOpaViolations(t *testing.T) {
+	violations, err := parseOpaViolations([]byte(`{"result":[{"expressions":[{"value":[
+		"privileged containers are not allowed",
+		{"rule":"no-latest","service":"web","message":"image uses latest tag"}
+	],"text":"data.compose.deny"}]}]}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, violations, []api.PolicyViolation{
+		{Message: "privileged containers are not allowed"},
+		{Rule: "no-latest", Service: "web", Message: "image uses latest tag"},
+	})
+}
@@ -25,10 +25,12 @@ import (
 
 type progressFunc func(context.Context) error
 
+// Run runs pf as operation, reporting to bus it started and whether it succeeded, so displays can report
+// resources state when operation failed or was interrupted
 func Run(ctx context.Context, pf progressFunc, operation string, bus api.EventProcessor) error {
 	bus.Start(ctx, operation)
 	err := pf(ctx)
-	bus.Done(operation, err != nil)
+	bus.Done(operation, err == nil)
 	return err
 }
 
@@ -147,7 +149,7 @@ func skippedEvent(id string, reason string) api.Resource {
 	return api.Resource{
 		ID:     id,
 		Status: api.Warning,
-		Text:   "Skipped: " + reason,
+		Text:   api.StatusSkipped + ": " + reason,
 	}
 }
 
@@ -18,8 +18,11 @@ package compose
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"golang.org/x/sync/errgroup"
@@ -38,9 +41,24 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 		return nil, err
 	}
 
+	if options.Project != nil {
+		external, err := s.getExternalContainers(ctx, options.Project, options.All, options.Services...)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, external...)
+	}
+
 	if len(options.Services) != 0 {
 		containers = containers.filter(isService(options.Services...))
 	}
+	diff := options.Diff && options.Project != nil
+	if diff {
+		// resolve images digests, so we can detect updated images
+		if _, err := s.getLocalImagesDigests(ctx, options.Project); err != nil {
+			return nil, err
+		}
+	}
 	summary := make([]api.ContainerSummary, len(containers))
 	eg, ctx := errgroup.WithContext(ctx)
 	for i, ctr := range containers {
@@ -64,14 +82,20 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 			}
 
 			var (
-				health   container.HealthStatus
-				exitCode int
+				health      container.HealthStatus
+				healthSince time.Time
+				exitCode    int
+				startedAt   time.Time
+				finishedAt  time.Time
 			)
 			if inspect.State != nil {
+				startedAt = parseStateTime(inspect.State.StartedAt)
+				finishedAt = parseStateTime(inspect.State.FinishedAt)
 				switch inspect.State.Status {
 				case container.StateRunning:
 					if inspect.State.Health != nil {
 						health = inspect.State.Health.Status
+						healthSince = getHealthSince(inspect.State.Health, startedAt)
 					}
 				case container.StateExited, container.StateDead:
 					exitCode = inspect.State.ExitCode
@@ -117,12 +141,90 @@ func (s *composeService) Ps(ctx context.Context, projectName string, options api
 				Mounts:       mounts,
 				LocalVolumes: local,
 				Networks:     networks,
+				Endpoints:    containerEndpoints(inspect),
 				Health:       health,
+				HealthSince:  healthSince,
 				ExitCode:     exitCode,
+				StartedAt:    startedAt,
+				FinishedAt:   finishedAt,
+				RestartCount: inspect.RestartCount,
 				Publishers:   publishers,
 			}
 			return nil
 		})
 	}
-	return summary, eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	if diff {
+		for i, ctr := range containers {
+			service, ok := options.Project.Services[ctr.Labels[api.ServiceLabel]]
+			if !ok || ctr.Labels[api.OneoffLabel] == "True" {
+				continue
+			}
+			summary[i].Diff, err = configDiff(service, ctr)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return summary, nil
+}
+
+// parseStateTime parses a container state timestamp, engine reporting zero time for unset ones
+func parseStateTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.IsZero() {
+		return time.Time{}
+	}
+	return t
+}
+
+// getHealthSince approximates the time health reached its current status, as the end of the first of the latest
+// consecutive checks matching this status. Failed checks not yet reaching retries are ignored. Engine only keeps
+// the last checks, so this is an upper bound when all of them match.
+func getHealthSince(health *container.Health, startedAt time.Time) time.Time {
+	if health.Status != container.Healthy && health.Status != container.Unhealthy {
+		return startedAt
+	}
+	var since time.Time
+	for i := len(health.Log) - 1; i >= 0; i-- {
+		check := health.Log[i]
+		if check == nil {
+			continue
+		}
+		if (check.ExitCode == 0) == (health.Status == container.Healthy) {
+			since = check.End
+		} else if !since.IsZero() {
+			break
+		}
+	}
+	if since.IsZero() {
+		return startedAt
+	}
+	return since
+}
+
+// containerEndpoints lists the addresses and names of container on the networks it is attached to
+func containerEndpoints(inspect container.InspectResponse) api.ContainerEndpoints {
+	if inspect.NetworkSettings == nil {
+		return nil
+	}
+	var endpoints api.ContainerEndpoints
+	for _, name := range slices.Sorted(maps.Keys(inspect.NetworkSettings.Networks)) {
+		settings := inspect.NetworkSettings.Networks[name]
+		if settings == nil {
+			continue
+		}
+		endpoints = append(endpoints, api.ContainerEndpoint{
+			Network:     name,
+			IPAddress:   settings.IPAddress,
+			IPv6Address: settings.GlobalIPv6Address,
+			Aliases:     settings.Aliases,
+			DNSNames:    settings.DNSNames,
+			Links:       settings.Links,
+		})
+	}
+	return endpoints
 }
@@ -20,9 +20,11 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	containerType "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
 
@@ -114,3 +116,61 @@ func containerDetails(service string, id string, status containerType.ContainerS
 	}
 	return ctr, inspect
 }
+
+func TestContainerEndpoints(t *testing.T) {
+	inspect := containerType.InspectResponse{
+		NetworkSettings: &containerType.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"test_front": {
+					IPAddress: "172.18.0.2",
+					Aliases:   []string{"web", "www"},
+					DNSNames:  []string{"test-web-1", "web", "www", "123"},
+				},
+				"test_back": {
+					IPAddress:         "172.19.0.2",
+					GlobalIPv6Address: "fd00::2",
+					DNSNames:          []string{"test-web-1", "123"},
+					Links:             []string{"test-db-1:db"},
+				},
+			},
+		},
+	}
+	endpoints := containerEndpoints(inspect)
+	assert.DeepEqual(t, endpoints, compose.ContainerEndpoints{
+		{
+			Network:     "test_back",
+			IPAddress:   "172.19.0.2",
+			IPv6Address: "fd00::2",
+			DNSNames:    []string{"test-web-1", "123"},
+			Links:       []string{"test-db-1:db"},
+		},
+		{
+			Network:   "test_front",
+			IPAddress: "172.18.0.2",
+			Aliases:   []string{"web", "www"},
+			DNSNames:  []string{"test-web-1", "web", "www", "123"},
+		},
+	})
+	assert.Equal(t, endpoints.String(), "test_back:172.19.0.2(test-web-1 123),test_front:172.18.0.2(test-web-1 web www 123)")
+}
+
+func TestGetHealthSince(t *testing.T) {
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	check := func(minutes int, exitCode int) *containerType.HealthcheckResult {
+		return &containerType.HealthcheckResult{End: started.Add(time.Duration(minutes) * time.Minute), ExitCode: exitCode}
+	}
+	health := &containerType.Health{
+		Status: containerType.Healthy,
+		Log:    []*containerType.HealthcheckResult{check(1, 1), check(2, 0), check(3, 0), check(4, 1)},
+	}
+	assert.Equal(t, getHealthSince(health, started), started.Add(2*time.Minute))
+
+	health.Status = containerType.Unhealthy
+	health.Log = []*containerType.HealthcheckResult{check(1, 0), check(2, 1), check(3, 1)}
+	assert.Equal(t, getHealthSince(health, started), started.Add(2*time.Minute))
+
+	health.Status = containerType.Starting
+	assert.Equal(t, getHealthSince(health, started), started)
+
+	assert.Equal(t, parseStateTime("0001-01-01T00:00:00Z"), time.Time{})
+}
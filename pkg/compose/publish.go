@@ -60,6 +60,13 @@ func (s *composeService) publish(ctx context.Context, project *types.Project, re
 	if !accept {
 		return nil
 	}
+	if len(options.Platforms) > 0 {
+		err = s.checkPublishPlatforms(ctx, project, options.Platforms)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = s.Push(ctx, project, api.PushOptions{IgnoreFailures: true, ImageMandatory: true})
 	if err != nil {
 		return err
@@ -121,14 +128,18 @@ func (s *composeService) publish(ctx context.Context, project *types.Project, re
 			}
 
 			descriptor.Data = nil
+			annotations := map[string]string{
+				"com.docker.compose.version": api.ComposeVersion,
+			}
+			if len(options.Platforms) > 0 {
+				annotations["com.docker.compose.platforms"] = strings.Join(options.Platforms, ",")
+			}
 			index, err := json.Marshal(v1.Index{
-				Versioned: specs.Versioned{SchemaVersion: 2},
-				MediaType: v1.MediaTypeImageIndex,
-				Manifests: manifests,
-				Subject:   &descriptor,
-				Annotations: map[string]string{
-					"com.docker.compose.version": api.ComposeVersion,
-				},
+				Versioned:   specs.Versioned{SchemaVersion: 2},
+				MediaType:   v1.MediaTypeImageIndex,
+				Manifests:   manifests,
+				Subject:     &descriptor,
+				Annotations: annotations,
 			})
 			if err != nil {
 				return err
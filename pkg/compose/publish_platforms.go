@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// checkPublishPlatforms verifies every service image supports all platforms requested for publication, before
+// anything is pushed. Images built by compose are checked against build.platforms, or the local image platform
+// if not set, others are resolved from registry. Failure reports the services/platforms support matrix.
+func (s *composeService) checkPublishPlatforms(ctx context.Context, project *types.Project, requested []string) error {
+	var wanted []v1.Platform
+	for _, p := range requested {
+		platform, err := platforms.Parse(p)
+		if err != nil {
+			return fmt.Errorf("invalid platform %q: %w", p, err)
+		}
+		wanted = append(wanted, platform)
+	}
+
+	var (
+		mu        sync.Mutex
+		supported = map[string][]v1.Platform{}
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(s.maxConcurrency)
+	for name, service := range project.Services {
+		eg.Go(func() error {
+			available, err := s.imagePlatforms(ctx, project, service)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			supported[name] = available
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if matrix, ok := platformsMatrix(project.ServiceNames(), wanted, supported); !ok {
+		return fmt.Errorf("service images don't support all requested platforms:\n%s", matrix)
+	}
+	return nil
+}
+
+// imagePlatforms returns platforms supported by service image, as it will be pushed on publish
+func (s *composeService) imagePlatforms(ctx context.Context, project *types.Project, service types.ServiceConfig) ([]v1.Platform, error) {
+	if service.Build != nil {
+		if len(service.Build.Platforms) > 0 {
+			var available []v1.Platform
+			for _, p := range service.Build.Platforms {
+				platform, err := platforms.Parse(p)
+				if err != nil {
+					return nil, fmt.Errorf("service %q: invalid build platform %q: %w", service.Name, p, err)
+				}
+				available = append(available, platform)
+			}
+			return available, nil
+		}
+		inspect, err := s.apiClient().ImageInspect(ctx, api.GetImageNameOrDefault(service, project.Name))
+		if err != nil {
+			return nil, err
+		}
+		return []v1.Platform{{OS: inspect.Os, Architecture: inspect.Architecture, Variant: inspect.Variant}}, nil
+	}
+
+	named, err := reference.ParseDockerRef(service.Image)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := encodedAuth(named, s.configFile())
+	if err != nil {
+		return nil, err
+	}
+	inspect, err := s.apiClient().DistributionInspect(ctx, named.String(), auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve manifest for %s: %w", named.String(), err)
+	}
+	return inspect.Platforms, nil
+}
+
+// platformsMatrix renders platforms support by service images, and reports if all requested platforms are supported
+func platformsMatrix(services []string, wanted []v1.Platform, supported map[string][]v1.Platform) (string, bool) {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 4, 1, 2, ' ', 0)
+	header := []string{"SERVICE"}
+	for _, p := range wanted {
+		header = append(header, platforms.Format(p))
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	ok := true
+	for _, service := range services {
+		row := []string{service}
+		for _, p := range wanted {
+			if slices.ContainsFunc(supported[service], platforms.NewMatcher(p).Match) {
+				row = append(row, "yes")
+				continue
+			}
+			ok = false
+			row = append(row, "missing")
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	return sb.String(), ok
+}
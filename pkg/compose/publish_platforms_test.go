@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckPublishPlatforms(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	tested := &composeService{dockerCli: cli, maxConcurrency: -1}
+
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+	apiClient.EXPECT().DistributionInspect(gomock.Any(), "docker.io/library/nginx:latest", gomock.Any()).
+		Return(registry.DistributionInspect{Platforms: []v1.Platform{amd64, arm64}}, nil).AnyTimes()
+	apiClient.EXPECT().DistributionInspect(gomock.Any(), "docker.io/acme/api:1.0", gomock.Any()).
+		Return(registry.DistributionInspect{Platforms: []v1.Platform{amd64}}, nil).AnyTimes()
+
+	project := &types.Project{
+		Services: types.Services{
+			"api":   {Name: "api", Image: "acme/api:1.0"},
+			"proxy": {Name: "proxy", Image: "nginx"},
+		},
+	}
+
+	err := tested.checkPublishPlatforms(t.Context(), project, []string{"linux/amd64"})
+	assert.NilError(t, err)
+
+	err = tested.checkPublishPlatforms(t.Context(), project, []string{"linux/amd64", "linux/arm64"})
+	assert.Error(t, err, `service images don't support all requested platforms:
+SERVICE  linux/amd64  linux/arm64
+api      yes          missing
+proxy    yes          yes
+`)
+
+	err = tested.checkPublishPlatforms(t.Context(), project, []string{"linux/not an arch"})
+	assert.ErrorContains(t, err, "invalid platform")
+}
+
+func TestCheckPublishPlatformsBuiltImages(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, maxConcurrency: -1}
+
+	// built images are not resolved from registry, as they have not been pushed yet
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "myproject-worker").
+		Return(image.InspectResponse{Os: "linux", Architecture: "amd64"}, nil).AnyTimes()
+
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"api": {Name: "api", Image: "acme/api:1.0", Build: &types.BuildConfig{
+				Context:   ".",
+				Platforms: []string{"linux/amd64", "linux/arm64"},
+			}},
+			"worker": {Name: "worker", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+
+	err := tested.checkPublishPlatforms(t.Context(), project, []string{"linux/amd64"})
+	assert.NilError(t, err)
+
+	err = tested.checkPublishPlatforms(t.Context(), project, []string{"linux/arm64"})
+	assert.Error(t, err, `service images don't support all requested platforms:
+SERVICE  linux/arm64
+api      yes
+worker   missing
+`)
+}
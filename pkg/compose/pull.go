@@ -43,8 +43,11 @@ import (
 )
 
 func (s *composeService) Pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
+	ctx = s.withOperationServices(ctx, project, nil)
 	return Run(ctx, func(ctx context.Context) error {
-		return s.pull(ctx, project, options)
+		return withDeadline(ctx, options.Deadline, "pull", func(ctx context.Context) error {
+			return s.pull(ctx, project, options)
+		})
 	}, "pull", s.events)
 }
 
@@ -113,7 +116,11 @@ func (s *composeService) pull(ctx context.Context, project *types.Project, opts
 
 		idx := i
 		eg.Go(func() error {
-			_, err := s.pullServiceImage(ctx, service, opts.Quiet, project.Environment["DOCKER_DEFAULT_PLATFORM"])
+			retry, err := getPullRetryPolicy(project, service.Image)
+			if err != nil {
+				return err
+			}
+			_, err = s.pullServiceImage(ctx, service, opts.Quiet, project.Environment["DOCKER_DEFAULT_PLATFORM"], retry)
 			if err != nil {
 				pullErrors[idx] = err
 				if service.Build != nil {
@@ -171,7 +178,7 @@ func getUnwrappedErrorMessage(err error) string {
 	return err.Error()
 }
 
-func (s *composeService) pullServiceImage(ctx context.Context, service types.ServiceConfig, quietPull bool, defaultPlatform string) (string, error) {
+func (s *composeService) pullServiceImage(ctx context.Context, service types.ServiceConfig, quietPull bool, defaultPlatform string, retry pullRetryPolicy) (string, error) {
 	resource := "Image " + service.Image
 	s.events.On(pullingEvent(service.Image))
 	ref, err := reference.ParseNormalizedNamed(service.Image)
@@ -188,23 +195,44 @@ func (s *composeService) pullServiceImage(ctx context.Context, service types.Ser
 	if platform == "" {
 		platform = defaultPlatform
 	}
-
-	stream, err := s.apiClient().ImagePull(ctx, service.Image, image.PullOptions{
+	options := image.PullOptions{
 		RegistryAuth: encodedAuth,
 		Platform:     platform,
-	})
+	}
 
-	if ctx.Err() != nil {
+	backoff := retry.backoff
+	for attempt := 1; ; attempt++ {
+		err = s.pullImage(ctx, resource, service.Image, options, quietPull, retry.timeout)
+		if ctx.Err() != nil {
+			s.events.On(api.Resource{
+				ID:     resource,
+				Status: api.Warning,
+				Text:   "Interrupted",
+			})
+			return "", nil
+		}
+		if err == nil || attempt >= retry.attempts || !isTransientPullError(err) {
+			break
+		}
 		s.events.On(api.Resource{
-			ID:     resource,
-			Status: api.Warning,
-			Text:   "Interrupted",
+			ID:      resource,
+			Status:  api.Working,
+			Text:    api.StatusRetrying,
+			Details: fmt.Sprintf("%s, attempt %d/%d in %s", getUnwrappedErrorMessage(err), attempt+1, retry.attempts, backoff),
 		})
-		return "", nil
+		select {
+		case <-ctx.Done():
+			continue
+		case <-s.clock.After(backoff):
+		}
+		backoff *= 2
 	}
 
 	// check if has error and the service has a build section
 	// then the status should be warning instead of error
+	if isPullDeniedError(err) {
+		err = api.WithErrCode(err, api.ErrCodePullDenied)
+	}
 	if err != nil && service.Build != nil {
 		s.events.On(api.Resource{
 			ID:     resource,
@@ -218,30 +246,46 @@ func (s *composeService) pullServiceImage(ctx context.Context, service types.Ser
 		s.events.On(errorEvent(resource, getUnwrappedErrorMessage(err)))
 		return "", err
 	}
+	s.events.On(pulledEvent(service.Image))
+
+	inspected, err := s.apiClient().ImageInspect(ctx, service.Image)
+	if err != nil {
+		return "", err
+	}
+	return inspected.ID, nil
+}
+
+// pullImage runs a single pull attempt, aborted after timeout if set
+func (s *composeService) pullImage(ctx context.Context, resource string, img string, options image.PullOptions, quietPull bool, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	stream, err := s.apiClient().ImagePull(ctx, img, options)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
 
 	dec := json.NewDecoder(stream)
 	for {
 		var jm jsonmessage.JSONMessage
 		if err := dec.Decode(&jm); err != nil {
 			if errors.Is(err, io.EOF) {
-				break
+				return nil
 			}
-			return "", err
+			return err
 		}
 		if jm.Error != nil {
-			return "", errors.New(jm.Error.Message)
+			return errors.New(jm.Error.Message)
 		}
 		if !quietPull {
 			toPullProgressEvent(resource, jm, s.events)
 		}
 	}
-	s.events.On(pulledEvent(service.Image))
-
-	inspected, err := s.apiClient().ImageInspect(ctx, service.Image)
-	if err != nil {
-		return "", err
-	}
-	return inspected.ID, nil
 }
 
 // ImageDigestResolver creates a func able to resolve image digest from a docker ref,
@@ -280,6 +324,11 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 		if err != nil {
 			return err
 		}
+		if _, pulled := getJournal(ctx).completed("pull:" + service.Image); pulled {
+			// already pulled by the interrupted operation being resumed
+			_, present := images[service.Image]
+			pull = pull && !present
+		}
 		if pull {
 			needPull[name] = service
 		}
@@ -295,7 +344,21 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 				}
 			}
 		}
-
+		inits, err := getInitContainers(service)
+		if err != nil {
+			return err
+		}
+		for _, init := range inits {
+			if _, ok := images[init.Image]; !ok && init.Image != "" {
+				// Same hack as image volumes, so we pull missing init container image
+				n := fmt.Sprintf("%s:init %s", name, init.Name)
+				needPull[n] = types.ServiceConfig{
+					Name:     n,
+					Image:    init.Image,
+					Platform: service.Platform,
+				}
+			}
+		}
 	}
 	if len(needPull) == 0 {
 		return nil
@@ -307,7 +370,18 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 	var mutex sync.Mutex
 	for name, service := range needPull {
 		eg.Go(func() error {
-			id, err := s.pullServiceImage(ctx, service, quietPull, project.Environment["DOCKER_DEFAULT_PLATFORM"])
+			retry, err := getPullRetryPolicy(project, service.Image)
+			if err != nil {
+				return err
+			}
+			_, isService := project.Services[name]
+			if isService {
+				notifyService(ctx, name, api.ServicePulling, nil)
+			}
+			id, err := s.pullServiceImage(ctx, service, quietPull, project.Environment["DOCKER_DEFAULT_PLATFORM"], retry)
+			if err == nil && id != "" {
+				getJournal(ctx).done("pull:"+service.Image, id)
+			}
 			mutex.Lock()
 			defer mutex.Unlock()
 			pulledImages[name] = api.ImageSummary{
@@ -319,6 +393,9 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 				// image can be built, so we can ignore pull failure
 				return nil
 			}
+			if err != nil && isService {
+				notifyService(ctx, name, api.ServiceFailed, err)
+			}
 			return err
 		})
 	}
@@ -332,7 +409,7 @@ func (s *composeService) pullRequiredImages(ctx context.Context, project *types.
 }
 
 func mustPull(service types.ServiceConfig, images map[string]api.ImageSummary) (bool, error) {
-	if service.Provider != nil {
+	if service.Provider != nil || isExternalService(service) {
 		return false, nil
 	}
 	if service.Image == "" {
@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+)
+
+// PullRetryExtension is the project extension configuring how image pulls are retried on transient
+// registry errors. Registries can override the default configuration by domain:
+//
+//	x-pull_retry:
+//	  attempts: 5
+//	  backoff: 2s
+//	  registries:
+//	    ghcr.io:
+//	      timeout: 10m
+const PullRetryExtension = "x-pull_retry"
+
+// PullRetryConfig configures retries of image pulls
+type PullRetryConfig struct {
+	// Attempts is the maximum number of pull attempts, including the first one
+	Attempts int `mapstructure:"attempts"`
+	// Backoff is the delay before first retry, doubled on each subsequent retry
+	Backoff string `mapstructure:"backoff"`
+	// Timeout aborts a single pull attempt, which is then retried
+	Timeout string `mapstructure:"timeout"`
+	// Registries overrides the default configuration for a registry domain
+	Registries map[string]PullRetryConfig `mapstructure:"registries"`
+}
+
+type pullRetryPolicy struct {
+	attempts int
+	backoff  time.Duration
+	timeout  time.Duration
+}
+
+var defaultPullRetryPolicy = pullRetryPolicy{
+	attempts: 3,
+	backoff:  time.Second,
+}
+
+// transientPullErrorPattern matches registry errors worth a retry: server errors, rate limiting and network failures
+var transientPullErrorPattern = regexp.MustCompile(`(?i)((status|HTTP)[: ]+5\d\d|too many requests|toomanyrequests|service unavailable|bad gateway|` +
+	`gateway time-?out|connection reset|connection refused|i/o timeout|tls handshake timeout|unexpected EOF|temporary failure)`)
+
+// getPullRetryPolicy returns the retry policy for pulls of image, as configured by PullRetryExtension
+func getPullRetryPolicy(project *types.Project, image string) (pullRetryPolicy, error) {
+	var config PullRetryConfig
+	if _, err := project.Extensions.Get(PullRetryExtension, &config); err != nil {
+		return pullRetryPolicy{}, fmt.Errorf("invalid %s: %w", PullRetryExtension, err)
+	}
+	policy, err := applyPullRetryConfig(defaultPullRetryPolicy, config)
+	if err != nil {
+		return pullRetryPolicy{}, err
+	}
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		// invalid reference will be reported by pull
+		return policy, nil
+	}
+	if registry, ok := config.Registries[reference.Domain(ref)]; ok {
+		return applyPullRetryConfig(policy, registry)
+	}
+	return policy, nil
+}
+
+func applyPullRetryConfig(policy pullRetryPolicy, config PullRetryConfig) (pullRetryPolicy, error) {
+	if config.Attempts < 0 {
+		return policy, fmt.Errorf("invalid %s attempts %d: must be positive", PullRetryExtension, config.Attempts)
+	}
+	if config.Attempts > 0 {
+		policy.attempts = config.Attempts
+	}
+	if config.Backoff != "" {
+		backoff, err := time.ParseDuration(config.Backoff)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s backoff: %w", PullRetryExtension, err)
+		}
+		policy.backoff = backoff
+	}
+	if config.Timeout != "" {
+		timeout, err := time.ParseDuration(config.Timeout)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s timeout: %w", PullRetryExtension, err)
+		}
+		policy.timeout = timeout
+	}
+	return policy, nil
+}
+
+func isTransientPullError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errdefs.IsUnavailable(err) || errdefs.IsDeadlineExceeded(err) {
+		return true
+	}
+	return transientPullErrorPattern.MatchString(err.Error())
+}
+
+// pullDeniedPattern matches registry errors for an image which doesn't exist or requires authentication
+var pullDeniedPattern = regexp.MustCompile(`(?i)(denied|unauthorized|authentication required|forbidden)`)
+
+func isPullDeniedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errdefs.IsUnauthorized(err) || errdefs.IsPermissionDenied(err) {
+		return true
+	}
+	return pullDeniedPattern.MatchString(err.Error())
+}
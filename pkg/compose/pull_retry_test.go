@@ -0,0 +1,128 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types/image"
+	"github.com/jonboulle/clockwork"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestGetPullRetryPolicy(t *testing.T) {
+	project := &types.Project{
+		Extensions: types.Extensions{
+			PullRetryExtension: map[string]any{
+				"attempts": 5,
+				"backoff":  "2s",
+				"registries": map[string]any{
+					"ghcr.io": map[string]any{
+						"attempts": 2,
+						"timeout":  "10m",
+					},
+				},
+			},
+		},
+	}
+
+	policy, err := getPullRetryPolicy(project, "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, policy, pullRetryPolicy{attempts: 5, backoff: 2 * time.Second})
+
+	policy, err = getPullRetryPolicy(project, "ghcr.io/org/app:1.0")
+	assert.NilError(t, err)
+	assert.Equal(t, policy, pullRetryPolicy{attempts: 2, backoff: 2 * time.Second, timeout: 10 * time.Minute})
+
+	policy, err = getPullRetryPolicy(&types.Project{}, "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, policy, defaultPullRetryPolicy)
+
+	_, err = getPullRetryPolicy(&types.Project{
+		Extensions: types.Extensions{PullRetryExtension: map[string]any{"backoff": "soon"}},
+	}, "nginx")
+	assert.ErrorContains(t, err, "invalid x-pull_retry backoff")
+}
+
+func TestIsTransientPullError(t *testing.T) {
+	assert.Check(t, isTransientPullError(errors.New("received unexpected HTTP status: 503 Service Unavailable")))
+	assert.Check(t, isTransientPullError(errors.New("toomanyrequests: You have reached your pull rate limit")))
+	assert.Check(t, isTransientPullError(errors.New("read tcp 10.0.0.1:5000: connection reset by peer")))
+	assert.Check(t, !isTransientPullError(errors.New("pull access denied for foo, repository does not exist")))
+	assert.Check(t, !isTransientPullError(errors.New("manifest for nginx:5000 not found")))
+}
+
+func TestPullServiceImageRetry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	events := &recordingEvents{}
+	tested := &composeService{dockerCli: cli, events: events, clock: clockwork.NewRealClock()}
+
+	gomock.InOrder(
+		apiClient.EXPECT().ImagePull(gomock.Any(), "nginx", gomock.Any()).
+			Return(nil, errors.New("received unexpected HTTP status: 503 Service Unavailable")),
+		apiClient.EXPECT().ImagePull(gomock.Any(), "nginx", gomock.Any()).
+			Return(io.NopCloser(strings.NewReader(`{"errorDetail":{"message":"unexpected EOF"}}`)), nil),
+		apiClient.EXPECT().ImagePull(gomock.Any(), "nginx", gomock.Any()).
+			Return(io.NopCloser(strings.NewReader(`{"status":"Pull complete"}`)), nil),
+	)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "nginx").Return(image.InspectResponse{ID: "sha256:1234"}, nil)
+
+	id, err := tested.pullServiceImage(t.Context(), types.ServiceConfig{Name: "web", Image: "nginx"}, true, "",
+		pullRetryPolicy{attempts: 3, backoff: time.Millisecond})
+	assert.NilError(t, err)
+	assert.Equal(t, id, "sha256:1234")
+
+	var retries int
+	for _, r := range events.resources {
+		if r.Text == api.StatusRetrying {
+			retries++
+		}
+	}
+	assert.Equal(t, retries, 2)
+}
+
+func TestPullServiceImageNoRetry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	tested := &composeService{dockerCli: cli, events: &recordingEvents{}, clock: clockwork.NewRealClock()}
+
+	apiClient.EXPECT().ImagePull(gomock.Any(), "nginx", gomock.Any()).
+		Return(nil, errors.New("pull access denied for nginx")).Times(1)
+
+	_, err := tested.pullServiceImage(t.Context(), types.ServiceConfig{Name: "web", Image: "nginx"}, true, "", defaultPullRetryPolicy)
+	assert.ErrorContains(t, err, "pull access denied")
+	assert.Equal(t, api.ErrorCode(err), api.ErrCodePullDenied)
+}
+
+func TestIsPullDeniedError(t *testing.T) {
+	assert.Check(t, isPullDeniedError(errors.New("pull access denied for foo, repository does not exist")))
+	assert.Check(t, isPullDeniedError(errors.New("unauthorized: authentication required")))
+	assert.Check(t, !isPullDeniedError(errors.New("manifest for nginx:5000 not found")))
+	assert.Check(t, !isPullDeniedError(nil))
+}
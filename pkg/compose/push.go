@@ -39,12 +39,18 @@ func (s *composeService) Push(ctx context.Context, project *types.Project, optio
 	if options.Quiet {
 		return s.push(ctx, project, options)
 	}
+	ctx = s.withOperationServices(ctx, project, nil)
 	return Run(ctx, func(ctx context.Context) error {
 		return s.push(ctx, project, options)
 	}, "push", s.events)
 }
 
 func (s *composeService) push(ctx context.Context, project *types.Project, options api.PushOptions) error {
+	tmpl, err := getTagTemplate(project)
+	if err != nil {
+		return err
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.SetLimit(s.maxConcurrency)
 
@@ -64,6 +70,21 @@ func (s *composeService) push(ctx context.Context, project *types.Project, optio
 		if service.Build != nil {
 			tags = append(tags, service.Build.Tags...)
 		}
+		if tmpl != nil {
+			inspect, err := s.apiClient().ImageInspect(ctx, service.Image)
+			if err != nil {
+				return err
+			}
+			var labels map[string]string
+			if inspect.Config != nil {
+				labels = inspect.Config.Labels
+			}
+			tag, err := templateTagFromLabels(tmpl, project.Name, service.Name, service.Image, labels)
+			if err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
 
 		for _, tag := range tags {
 			eg.Go(func() error {
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"slices"
+
+	"github.com/moby/buildkit/util/progress/progressui"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// creationTexts are the progress texts reported as resources get created during the create phase of Up
+var creationTexts = []string{
+	api.StatusCreating,
+	api.StatusCreated,
+	api.StatusRunning,
+	"Recreate",
+	"Recreated",
+}
+
+// quietCreateProcessor decorates an api.EventProcessor to drop progress of resources creation, errors and
+// warnings are still reported
+type quietCreateProcessor struct {
+	api.EventProcessor
+}
+
+func (q quietCreateProcessor) On(events ...api.Resource) {
+	events = slices.DeleteFunc(events, func(e api.Resource) bool {
+		return e.Status != api.Error && e.Status != api.Warning && slices.Contains(creationTexts, e.Text)
+	})
+	if len(events) > 0 {
+		q.EventProcessor.On(events...)
+	}
+}
+
+// withQuietPhases applies options.QuietPhases to the create options, and returns the service to run the create
+// phase with, which doesn't report resources creation when QuietPhaseCreate is set
+func (s *composeService) withQuietPhases(options *api.UpOptions) *composeService {
+	phases := options.QuietPhases
+	if phases.Has(api.QuietPhasePull) {
+		options.Create.QuietPull = true
+	}
+	if phases.Has(api.QuietPhaseBuild) && options.Create.Build != nil {
+		build := *options.Create.Build
+		build.Quiet = true
+		build.Progress = string(progressui.QuietMode)
+		options.Create.Build = &build
+	}
+	if !phases.Has(api.QuietPhaseCreate) {
+		return s
+	}
+	quiet := *s
+	quiet.events = quietCreateProcessor{EventProcessor: s.events}
+	return &quiet
+}
@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestWithQuietPhases(t *testing.T) {
+	events := &recordingEvents{}
+	s := &composeService{events: events}
+
+	options := api.UpOptions{
+		Create: api.CreateOptions{Build: &api.BuildOptions{Progress: "auto"}},
+	}
+	assert.Equal(t, s.withQuietPhases(&options), s)
+	assert.Check(t, !options.Create.QuietPull)
+	assert.Check(t, !options.Create.Build.Quiet)
+
+	options.QuietPhases = api.QuietPhasePull | api.QuietPhaseBuild | api.QuietPhaseCreate
+	creator := s.withQuietPhases(&options)
+	assert.Check(t, options.Create.QuietPull)
+	assert.Check(t, options.Create.Build.Quiet)
+	assert.Equal(t, options.Create.Build.Progress, "quiet")
+
+	creator.events.On(
+		creatingEvent("Container test-web-1"),
+		createdEvent("Network test_default"),
+		newEvent("Container test-db-1", api.Working, "Recreate"),
+		pullingEvent("nginx"),
+		errorEvent("Container test-web-1", "boom"),
+	)
+	creator.events.On(createdEvent("Container test-web-1"))
+	assert.DeepEqual(t, events.resources, []api.Resource{
+		pullingEvent("nginx"),
+		errorEvent("Container test-web-1", "boom"),
+	})
+
+	// start phase is run by the original service, which reports all events
+	s.events.On(startedEvent("Container test-web-1"))
+	assert.Equal(t, len(events.resources), 3)
+}
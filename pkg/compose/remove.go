@@ -17,10 +17,15 @@
 package compose
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/docker/api/types/container"
 	"golang.org/x/sync/errgroup"
@@ -29,7 +34,7 @@ import (
 func (s *composeService) Remove(ctx context.Context, projectName string, options api.RemoveOptions) error {
 	projectName = strings.ToLower(projectName)
 
-	if options.Stop {
+	if options.Stop && options.KeepLast == 0 {
 		err := s.Stop(ctx, projectName, api.StopOptions{
 			Services: options.Services,
 			Project:  options.Project,
@@ -52,6 +57,19 @@ func (s *composeService) Remove(ctx context.Context, projectName string, options
 		containers = containers.filter(isService(options.Project.ServiceNames()...))
 	}
 
+	if options.KeepLast > 0 {
+		containers = withoutLastReplicas(containers, options.KeepLast)
+		if options.Stop {
+			// only replicas to be removed are stopped
+			err := Run(ctx, func(ctx context.Context) error {
+				return s.stopReplicas(ctx, options.Project, containers)
+			}, "stop", s.events)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	var stoppedContainers Containers
 	for _, ctr := range containers {
 		// We have to inspect containers, as State reported by getContainers suffers a race condition
@@ -90,7 +108,13 @@ func (s *composeService) Remove(ctx context.Context, projectName string, options
 		}
 	}
 	return Run(ctx, func(ctx context.Context) error {
-		return s.remove(ctx, stoppedContainers, options)
+		if err := s.remove(ctx, stoppedContainers, options); err != nil {
+			return err
+		}
+		if s.dryRun {
+			return s.reportDryRunRemovals(ctx)
+		}
+		return nil
 	}, "remove", s.events)
 }
 
@@ -112,3 +136,47 @@ func (s *composeService) remove(ctx context.Context, containers Containers, opti
 	}
 	return eg.Wait()
 }
+
+// withoutLastReplicas excludes, for each service, the keep containers with the highest replica number
+func withoutLastReplicas(containers Containers, keep int) Containers {
+	byService := map[string]Containers{}
+	for _, ctr := range containers {
+		service := ctr.Labels[api.ServiceLabel]
+		byService[service] = append(byService[service], ctr)
+	}
+	var result Containers
+	for _, service := range slices.Sorted(maps.Keys(byService)) {
+		replicas := byService[service]
+		slices.SortFunc(replicas, func(a, b container.Summary) int {
+			return cmp.Compare(replicaNumber(b), replicaNumber(a))
+		})
+		if len(replicas) > keep {
+			result = append(result, replicas[keep:]...)
+		}
+	}
+	return result
+}
+
+func replicaNumber(ctr container.Summary) int {
+	number, _ := strconv.Atoi(ctr.Labels[api.ContainerNumberLabel])
+	return number
+}
+
+func (s *composeService) stopReplicas(ctx context.Context, project *types.Project, containers Containers) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, ctr := range containers {
+		if ctr.State != container.StateRunning {
+			continue
+		}
+		var service *types.ServiceConfig
+		if project != nil {
+			if svc, err := project.GetService(ctr.Labels[api.ServiceLabel]); err == nil {
+				service = &svc
+			}
+		}
+		eg.Go(func() error {
+			return s.stopContainer(ctx, service, ctr, nil, nil)
+		})
+	}
+	return eg.Wait()
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func replica(service string, number int) container.Summary {
+	c := testContainer(service, service+strconv.Itoa(number), false)
+	c.Labels[api.ContainerNumberLabel] = strconv.Itoa(number)
+	return c
+}
+
+func TestWithoutLastReplicas(t *testing.T) {
+	containers := Containers{
+		replica("web", 1),
+		replica("web", 3),
+		replica("db", 1),
+		replica("web", 10),
+		replica("web", 2),
+	}
+	assert.DeepEqual(t, withoutLastReplicas(containers, 2).names(), []string{"web2", "web1"})
+	assert.DeepEqual(t, withoutLastReplicas(containers, 1).names(), []string{"web3", "web2", "web1"})
+	assert.Equal(t, len(withoutLastReplicas(containers, 4)), 0)
+}
+
+func TestRemoveKeepLast(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{dockerCli: mockCli, events: &ignore{}}
+
+	mockApi.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		replica("web", 1), replica("web", 2), replica("web", 3),
+	}, nil)
+	mockApi.EXPECT().ContainerInspect(gomock.Any(), "web1").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{}},
+	}, nil)
+	mockApi.EXPECT().ContainerRemove(gomock.Any(), "web1", container.RemoveOptions{Force: true}).Return(nil)
+
+	err := tested.Remove(t.Context(), strings.ToLower(testProject), api.RemoveOptions{
+		Services: []string{"web"},
+		Force:    true,
+		KeepLast: 2,
+	})
+	assert.NilError(t, err)
+}
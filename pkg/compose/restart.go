@@ -28,6 +28,7 @@ import (
 )
 
 func (s *composeService) Restart(ctx context.Context, projectName string, options api.RestartOptions) error {
+	ctx = s.withOperationServices(ctx, options.Project, options.Services)
 	return Run(ctx, func(ctx context.Context) error {
 		return s.restart(ctx, strings.ToLower(projectName), options)
 	}, "restart", s.events)
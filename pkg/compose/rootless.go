@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/sirupsen/logrus"
+)
+
+// unprivilegedPortStart is the default lowest port a rootless daemon can bind, see net.ipv4.ip_unprivileged_port_start
+const unprivilegedPortStart = 1024
+
+func isRootless(info system.Info) bool {
+	return slices.ContainsFunc(info.SecurityOptions, func(opt string) bool {
+		return strings.Contains(opt, "name=rootless")
+	})
+}
+
+// warnRootless reports project settings which won't behave as expected with a rootless daemon
+func (s *composeService) warnRootless(ctx context.Context, project *types.Project) {
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		logrus.Debugf("failed to get engine info: %v", err)
+		return
+	}
+	for _, issue := range rootlessIssues(project, info) {
+		logrus.Warn(issue)
+	}
+}
+
+// rootlessIssues lists project settings which a rootless daemon silently ignores or fails to apply
+func rootlessIssues(project *types.Project, info system.Info) []string {
+	if !isRootless(info) {
+		return nil
+	}
+	var issues []string
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, p := range service.Ports {
+			if port := lowestPublishedPort(p.Published); port > 0 && port < unprivilegedPortStart {
+				issues = append(issues, fmt.Sprintf("service %q publishes privileged port %d, which rootless Docker can't bind. "+
+					"Use a port >= %d, or run `sudo sysctl net.ipv4.ip_unprivileged_port_start=%d`", name, port, unprivilegedPortStart, port))
+			}
+		}
+
+		resources := getDeployResources(service)
+		var limits []string
+		if resources.Memory != 0 && !info.MemoryLimit {
+			limits = append(limits, "memory")
+		}
+		if (resources.NanoCPUs != 0 || resources.CPUQuota != 0) && !info.CPUCfsQuota {
+			limits = append(limits, "cpu")
+		}
+		if resources.PidsLimit != nil && !info.PidsLimit {
+			limits = append(limits, "pids")
+		}
+		if len(limits) > 0 {
+			issues = append(issues, fmt.Sprintf("service %q sets %s limits which won't be applied: cgroup controllers are not delegated to the rootless daemon. "+
+				"See https://docs.docker.com/engine/security/rootless/#limiting-resources", name, strings.Join(limits, ", ")))
+		}
+
+		if service.NetworkMode == "host" {
+			issues = append(issues, fmt.Sprintf("service %q uses host network, which with rootless Docker is the network namespace of the daemon, not the host one", name))
+		}
+
+		if service.User != "" && !isRootUser(service.User) && slices.ContainsFunc(service.Volumes, func(v types.ServiceVolumeConfig) bool {
+			return v.Type == types.VolumeTypeBind
+		}) {
+			issues = append(issues, fmt.Sprintf("service %q runs as user %s with bind mounts: files it creates will be owned on host by a subordinate uid (see /etc/subuid), "+
+				"while root in container maps to your own user", name, service.User))
+		}
+	}
+	return issues
+}
+
+// lowestPublishedPort returns the lowest host port of a published port or range, 0 if not set
+func lowestPublishedPort(published string) int {
+	start, _, _ := strings.Cut(published, "-")
+	port, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return port
+}
+
+func isRootUser(user string) bool {
+	uid, _, _ := strings.Cut(user, ":")
+	return uid == "root" || uid == "0"
+}
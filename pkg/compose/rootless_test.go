@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestRootlessIssues(t *testing.T) {
+	pids := int64(100)
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"db": {
+				Name:      "db",
+				MemLimit:  types.UnitBytes(512 * 1024 * 1024),
+				PidsLimit: pids,
+				User:      "999",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: "/data", Target: "/var/lib/db"},
+				},
+			},
+			"proxy": {
+				Name:        "proxy",
+				NetworkMode: "host",
+				Ports: []types.ServicePortConfig{
+					{Target: 80, Published: "80"},
+					{Target: 8080, Published: "8080-8081"},
+				},
+			},
+			"web": {
+				Name: "web",
+				User: "root",
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: "/src", Target: "/app"},
+				},
+			},
+		},
+	}
+
+	t.Run("rootful", func(t *testing.T) {
+		issues := rootlessIssues(project, system.Info{SecurityOptions: []string{"name=seccomp,profile=builtin"}})
+		assert.Equal(t, len(issues), 0)
+	})
+
+	t.Run("rootless", func(t *testing.T) {
+		issues := rootlessIssues(project, system.Info{
+			SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless", "name=cgroupns"},
+			CPUCfsQuota:     true,
+		})
+		assert.Equal(t, len(issues), 4)
+		assert.Assert(t, cmp.Contains(issues[0], `service "db" sets memory, pids limits which won't be applied`))
+		assert.Assert(t, cmp.Contains(issues[1], `service "db" runs as user 999 with bind mounts`))
+		assert.Assert(t, cmp.Contains(issues[2], `service "proxy" publishes privileged port 80`))
+		assert.Assert(t, cmp.Contains(issues[3], `service "proxy" uses host network`))
+	})
+
+	t.Run("cgroup delegated", func(t *testing.T) {
+		issues := rootlessIssues(project, system.Info{
+			SecurityOptions: []string{"name=rootless"},
+			MemoryLimit:     true,
+			CPUCfsQuota:     true,
+			PidsLimit:       true,
+		})
+		assert.Equal(t, len(issues), 3)
+	})
+}
@@ -29,14 +29,32 @@ import (
 	cmd "github.com/docker/cli/cli/command/container"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/sirupsen/logrus"
 )
 
-func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
+// RunOneOffContainer is the deprecated form of RunOneOff, accepting RunOptions
+func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) { //nolint:staticcheck
+	return s.RunOneOff(ctx, project, opts.RunOneOffOptions())
+}
+
+func (s *composeService) RunOneOff(ctx context.Context, project *types.Project, opts api.RunOneOffOptions) (int, error) {
+	ctx = s.withOperationServices(ctx, project, []string{opts.Service})
+	if err := s.checkPolicy(ctx, "run", project); err != nil {
+		return 0, err
+	}
+
 	containerID, err := s.prepareRun(ctx, project, opts)
 	if err != nil {
 		return 0, err
 	}
 
+	if s.dryRun {
+		return 0, Run(ctx, func(ctx context.Context) error {
+			s.events.On(commandEvent(containerID, runCommand(project, opts), opts.User, opts.WorkingDir))
+			return nil
+		}, "run", s.events)
+	}
+
 	// remove cancellable context signal handler so we can forward signals to container without compose to exit
 	signal.Reset()
 
@@ -45,7 +63,25 @@ func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.
 	go cmd.ForwardAllSignals(ctx, s.apiClient(), containerID, sigc)
 	defer signal.Stop(sigc)
 
-	err = cmd.RunStart(ctx, s.dockerCli, &cmd.StartOptions{
+	dockerCli := s.dockerCli
+	if opts.Record != "" {
+		command := opts.Command
+		if service, err := project.GetService(opts.Service); err == nil && len(command) == 0 {
+			command = service.Command
+		}
+		recorder, err := s.recordSession(opts.Record, command)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				logrus.Warnf("failed to save session recording %s: %v", opts.Record, err)
+			}
+		}()
+		dockerCli = recorder.wrap(dockerCli, opts.Tty)
+	}
+
+	err = cmd.RunStart(ctx, dockerCli, &cmd.StartOptions{
 		OpenStdin:  !opts.Detach && opts.Interactive,
 		Attach:     !opts.Detach,
 		Containers: []string{containerID},
@@ -58,7 +94,7 @@ func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.
 	return 0, err
 }
 
-func (s *composeService) prepareRun(ctx context.Context, project *types.Project, opts api.RunOptions) (string, error) {
+func (s *composeService) prepareRun(ctx context.Context, project *types.Project, opts api.RunOneOffOptions) (string, error) {
 	// Temporary implementation of use_api_socket until we get actual support inside docker engine
 	project, err := s.useAPISocket(project)
 	if err != nil {
@@ -96,6 +132,13 @@ func (s *composeService) prepareRun(ctx context.Context, project *types.Project,
 	service.CustomLabels = service.CustomLabels.
 		Add(api.SlugLabel, slug).
 		Add(api.OneoffLabel, "True")
+	ttl, err := getOneoffTTL(project, opts)
+	if err != nil {
+		return "", err
+	}
+	if ttl > 0 {
+		service.CustomLabels = service.CustomLabels.Add(api.OneoffTTLLabel, ttl.String())
+	}
 
 	// Only ensure image exists for the target service, dependencies were already handled by startDependencies
 	buildOpts := prepareBuildOptions(opts)
@@ -149,7 +192,18 @@ func (s *composeService) prepareRun(ctx context.Context, project *types.Project,
 	return created.ID, err
 }
 
-func prepareBuildOptions(opts api.RunOptions) *api.BuildOptions {
+// runCommand returns the command a one-off container runs, as set by user or declared by service
+func runCommand(project *types.Project, opts api.RunOneOffOptions) []string {
+	if len(opts.Command) > 0 {
+		return opts.Command
+	}
+	if service, err := project.GetService(opts.Service); err == nil && len(service.Command) > 0 {
+		return service.Command
+	}
+	return []string{"<image default command>"}
+}
+
+func prepareBuildOptions(opts api.RunOneOffOptions) *api.BuildOptions {
 	if opts.Build == nil {
 		return nil
 	}
@@ -159,7 +213,7 @@ func prepareBuildOptions(opts api.RunOptions) *api.BuildOptions {
 	return &buildOptsCopy
 }
 
-func applyRunOptions(project *types.Project, service *types.ServiceConfig, opts api.RunOptions) {
+func applyRunOptions(project *types.Project, service *types.ServiceConfig, opts api.RunOneOffOptions) {
 	service.Tty = opts.Tty
 	service.StdinOpen = opts.Interactive
 	service.ContainerName = opts.Name
@@ -204,7 +258,7 @@ func applyRunOptions(project *types.Project, service *types.ServiceConfig, opts
 	}
 }
 
-func (s *composeService) startDependencies(ctx context.Context, project *types.Project, options api.RunOptions) error {
+func (s *composeService) startDependencies(ctx context.Context, project *types.Project, options api.RunOneOffOptions) error {
 	project = project.WithServicesDisabled(options.Service)
 
 	err := s.Create(ctx, project, api.CreateOptions{
@@ -16,19 +16,99 @@ limitations under the License.
 package compose
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
 	"github.com/docker/compose/v5/internal/tracing"
 	"github.com/docker/compose/v5/pkg/api"
 )
 
 func (s *composeService) Scale(ctx context.Context, project *types.Project, options api.ScaleOptions) error {
+	ctx = s.withOperationServices(ctx, project, options.Services)
 	return Run(ctx, tracing.SpanWrapFunc("project/scale", tracing.ProjectOptions(ctx, project), func(ctx context.Context) error {
-		err := s.create(ctx, project, api.CreateOptions{Services: options.Services})
+		err := s.create(ctx, project, api.CreateOptions{Services: options.Services, ScaleDown: options.ScaleDown})
 		if err != nil {
 			return err
 		}
 		return s.start(ctx, project.Name, api.StartOptions{Project: project, Services: options.Services}, nil)
 	}), "scale", s.events)
 }
+
+// selectScaleDownVictims reorders containers, so the replicas selected by options come last and get removed as
+// service is scaled down to expected replicas. It returns, by container ID, the reason victims were selected for.
+func selectScaleDownVictims(service string, containers Containers, expected int, options api.ScaleDownOptions) (map[string]string, error) {
+	indexes := options.Indexes[service]
+	remove := max(len(containers)-expected, 0)
+	if len(indexes) > remove {
+		return nil, fmt.Errorf("service %q: %d replicas selected for removal, but scaling to %d removes %d", service, len(indexes), expected, remove)
+	}
+	if remove == 0 || (len(indexes) == 0 && options.Policy == api.ScaleDownDefault) {
+		return nil, nil
+	}
+
+	selected := map[string]int{}
+	for _, index := range indexes {
+		i := slices.IndexFunc(containers, func(ctr container.Summary) bool {
+			return ctr.Labels[api.ContainerNumberLabel] == strconv.Itoa(index)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("service %q has no replica with index %d", service, index)
+		}
+		selected[containers[i].ID] = index
+	}
+
+	var compare func(a, b container.Summary) int
+	switch options.Policy {
+	case api.ScaleDownDefault:
+		compare = func(a, b container.Summary) int { return 0 }
+	case api.ScaleDownOldest:
+		compare = func(a, b container.Summary) int { return cmp.Compare(b.Created, a.Created) }
+	case api.ScaleDownNewest:
+		compare = func(a, b container.Summary) int { return cmp.Compare(a.Created, b.Created) }
+	case api.ScaleDownUnhealthyFirst:
+		compare = func(a, b container.Summary) int { return cmp.Compare(healthRank(b), healthRank(a)) }
+	default:
+		return nil, fmt.Errorf("unsupported scale down policy %q", options.Policy)
+	}
+	// containers kept come first, default order applies to ties
+	slices.SortStableFunc(containers, func(a, b container.Summary) int {
+		_, sa := selected[a.ID]
+		_, sb := selected[b.ID]
+		switch {
+		case sa && !sb:
+			return 1
+		case sb && !sa:
+			return -1
+		}
+		return compare(a, b)
+	})
+
+	reasons := map[string]string{}
+	for _, ctr := range containers[len(containers)-remove:] {
+		if index, ok := selected[ctr.ID]; ok {
+			reasons[ctr.ID] = fmt.Sprintf("index %d", index)
+		} else if options.Policy != api.ScaleDownDefault {
+			reasons[ctr.ID] = string(options.Policy)
+		}
+	}
+	return reasons, nil
+}
+
+// healthRank ranks a container from not running or unhealthy (0), to starting (1), and running (2)
+func healthRank(ctr container.Summary) int {
+	switch {
+	case ctr.State != container.StateRunning, strings.Contains(ctr.Status, "(unhealthy)"):
+		return 0
+	case strings.Contains(ctr.Status, "(health: starting)"):
+		return 1
+	default:
+		return 2
+	}
+}
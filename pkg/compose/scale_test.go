@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestSelectScaleDownVictims(t *testing.T) {
+	replica := func(number int, created int64, status string) container.Summary {
+		return container.Summary{
+			ID:      strconv.Itoa(number),
+			Created: created,
+			State:   container.StateRunning,
+			Status:  status,
+			Labels:  map[string]string{api.ContainerNumberLabel: strconv.Itoa(number)},
+		}
+	}
+	// default order, as sorted by convergence: lowest container numbers are kept
+	replicas := func() Containers {
+		return Containers{
+			replica(1, 300, "Up 1 minute (unhealthy)"),
+			replica(2, 100, "Up 5 minutes (healthy)"),
+			replica(3, 200, "Up 2 minutes (health: starting)"),
+		}
+	}
+	ids := func(containers Containers) []string {
+		var ids []string
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		options  api.ScaleDownOptions
+		expected []string
+		reasons  map[string]string
+		err      string
+	}{
+		{
+			name:     "default",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "oldest",
+			options:  api.ScaleDownOptions{Policy: api.ScaleDownOldest},
+			expected: []string{"1", "3", "2"},
+			reasons:  map[string]string{"2": "oldest"},
+		},
+		{
+			name:     "newest",
+			options:  api.ScaleDownOptions{Policy: api.ScaleDownNewest},
+			expected: []string{"2", "3", "1"},
+			reasons:  map[string]string{"1": "newest"},
+		},
+		{
+			name:     "unhealthy first",
+			options:  api.ScaleDownOptions{Policy: api.ScaleDownUnhealthyFirst},
+			expected: []string{"2", "3", "1"},
+			reasons:  map[string]string{"1": "unhealthy-first"},
+		},
+		{
+			name:     "index",
+			options:  api.ScaleDownOptions{Indexes: map[string][]int{"web": {2}}},
+			expected: []string{"1", "3", "2"},
+			reasons:  map[string]string{"2": "index 2"},
+		},
+		{
+			name:    "unknown index",
+			options: api.ScaleDownOptions{Indexes: map[string][]int{"web": {4}}},
+			err:     `service "web" has no replica with index 4`,
+		},
+		{
+			name:    "too many indexes",
+			options: api.ScaleDownOptions{Indexes: map[string][]int{"web": {1, 2}}},
+			err:     `service "web": 2 replicas selected for removal, but scaling to 2 removes 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers := replicas()
+			reasons, err := selectScaleDownVictims("web", containers, 2, tt.options)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, ids(containers), tt.expected)
+			if tt.reasons != nil {
+				assert.DeepEqual(t, reasons, tt.reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// severityPenalty is the score decrease for a finding of a given severity
+var severityPenalty = map[api.Severity]int{
+	api.SeverityHigh:   20,
+	api.SeverityMedium: 10,
+	api.SeverityLow:    3,
+}
+
+// dangerousCapabilities are capabilities granting (almost) full control over the host
+var dangerousCapabilities = []string{"ALL", "SYS_ADMIN", "SYS_MODULE", "SYS_PTRACE", "SYS_RAWIO", "DAC_READ_SEARCH", "NET_ADMIN", "BPF"}
+
+func (s *composeService) SecurityAudit(_ context.Context, project *types.Project, options api.SecurityAuditOptions) (api.SecurityReport, error) {
+	project, err := project.WithSelectedServices(options.Services)
+	if err != nil {
+		return api.SecurityReport{}, err
+	}
+	report := api.SecurityReport{
+		Score:    100,
+		Findings: []api.SecurityFinding{},
+	}
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		for _, f := range auditService(service) {
+			report.Findings = append(report.Findings, f)
+			report.Score -= severityPenalty[f.Severity]
+		}
+	}
+	report.Score = max(report.Score, 0)
+	return report, nil
+}
+
+// auditService checks a service configuration for insecure settings
+func auditService(service types.ServiceConfig) []api.SecurityFinding {
+	var findings []api.SecurityFinding
+	add := func(check string, severity api.Severity, message, remediation string) {
+		findings = append(findings, api.SecurityFinding{
+			Service:     service.Name,
+			Check:       check,
+			Severity:    severity,
+			Message:     message,
+			Remediation: remediation,
+		})
+	}
+
+	if service.Privileged {
+		add("privileged", api.SeverityHigh, "container runs in privileged mode, with full access to host devices",
+			"remove `privileged: true` and grant only required capabilities with `cap_add`")
+	}
+	for _, c := range service.CapAdd {
+		capability := strings.TrimPrefix(strings.ToUpper(c), "CAP_")
+		severity := api.SeverityMedium
+		if slices.Contains(dangerousCapabilities, capability) {
+			severity = api.SeverityHigh
+		}
+		add("cap_add", severity, fmt.Sprintf("capability %s is added", capability),
+			"remove capability if not required, or use `cap_drop: [ALL]` and only add the ones needed")
+	}
+	if service.NetworkMode == "host" {
+		add("network_mode", api.SeverityHigh, "container shares host network stack",
+			"use a project network and publish required ports")
+	}
+	if service.Pid == "host" {
+		add("pid", api.SeverityHigh, "container shares host PID namespace and can see all host processes",
+			"remove `pid: host`")
+	}
+	if service.Ipc == "host" {
+		add("ipc", api.SeverityMedium, "container shares host IPC namespace",
+			"remove `ipc: host`, or use `ipc: shareable` with services that need it")
+	}
+	for _, v := range service.Volumes {
+		if v.Type == types.VolumeTypeBind && isDockerSocket(v.Source) {
+			add("docker_socket", api.SeverityHigh, fmt.Sprintf("docker socket %s is mounted, giving control over the Docker engine", v.Source),
+				"avoid mounting the docker socket, or use a socket proxy restricting allowed API calls")
+		}
+	}
+	for _, opt := range service.SecurityOpt {
+		if o := strings.ReplaceAll(opt, "=", ":"); o == "seccomp:unconfined" || o == "apparmor:unconfined" || o == "label:disable" {
+			add("security_opt", api.SeverityMedium, fmt.Sprintf("security profile is disabled by %s", opt),
+				"remove the security_opt, or use a custom profile allowing only required syscalls")
+		}
+	}
+	switch service.User {
+	case "":
+		add("user", api.SeverityLow, "no user is set, container runs with the image default user which often is root",
+			"set `user` to a non-root uid")
+	case "root", "0", "root:root", "0:0":
+		add("user", api.SeverityMedium, "container explicitly runs as root",
+			"set `user` to a non-root uid")
+	}
+	if !service.ReadOnly {
+		add("read_only", api.SeverityLow, "container root filesystem is writable",
+			"set `read_only: true` and use volumes or tmpfs for writable paths")
+	}
+	return findings
+}
+
+func isDockerSocket(source string) bool {
+	return strings.HasSuffix(source, "/docker.sock") || source == `\\.\pipe\docker_engine`
+}
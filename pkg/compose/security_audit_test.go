@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestSecurityAudit(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"safe": {
+				Name:     "safe",
+				User:     "1000",
+				ReadOnly: true,
+			},
+			"unsafe": {
+				Name:        "unsafe",
+				User:        "root",
+				ReadOnly:    true,
+				Privileged:  true,
+				CapAdd:      []string{"NET_BIND_SERVICE", "CAP_SYS_ADMIN"},
+				NetworkMode: "host",
+				SecurityOpt: []string{"seccomp=unconfined"},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+				},
+			},
+		},
+	}
+	s := &composeService{}
+	report, err := s.SecurityAudit(context.Background(), project, api.SecurityAuditOptions{})
+	assert.NilError(t, err)
+
+	var checks []string
+	for _, f := range report.Findings {
+		assert.Equal(t, f.Service, "unsafe")
+		checks = append(checks, string(f.Severity)+":"+f.Check)
+	}
+	assert.DeepEqual(t, checks, []string{
+		"high:privileged",
+		"medium:cap_add",
+		"high:cap_add",
+		"high:network_mode",
+		"high:docker_socket",
+		"medium:security_opt",
+		"medium:user",
+	})
+	assert.Equal(t, report.Score, 0)
+
+	report, err = s.SecurityAudit(context.Background(), project, api.SecurityAuditOptions{Services: []string{"safe"}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Findings), 0)
+	assert.Equal(t, report.Score, 100)
+}
@@ -0,0 +1,232 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// asciicastHeader is the first line of an asciicast v2 file, see https://docs.asciinema.org/manual/asciicast/v2/
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint              `json:"width"`
+	Height    uint              `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// sessionRecorder captures the output of an attached session and terminal resizes as asciicast events
+type sessionRecorder struct {
+	mu    sync.Mutex
+	out   io.WriteCloser
+	start time.Time
+	// pending holds an incomplete UTF-8 sequence, completed by next output
+	pending []byte
+	err     error
+}
+
+func newSessionRecorder(out io.WriteCloser, width, height uint, command []string) (*sessionRecorder, error) {
+	if width == 0 || height == 0 {
+		width, height = 80, 24
+	}
+	r := &sessionRecorder{
+		out:   out,
+		start: time.Now(),
+	}
+	header, err := json.Marshal(asciicastHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Command:   formatCommand(command),
+		Env:       map[string]string{"TERM": os.Getenv("TERM")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", header); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// recordSession creates file to record a session running command, sized as the current terminal
+func (s *composeService) recordSession(file string, command []string) (*sessionRecorder, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session recording: %w", err)
+	}
+	height, width := s.stdout().GetTtySize()
+	r, err := newSessionRecorder(f, width, height, command)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write records p as an output event. Recording failures are reported by Close, so they don't break the session.
+func (r *sessionRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data := append(r.pending, p...)
+	// hold back a trailing incomplete rune, so events are valid UTF-8 strings
+	end := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+	r.pending = append([]byte(nil), data[end:]...)
+	if end > 0 {
+		r.event("o", string(data[:end]))
+	}
+	return len(p), nil
+}
+
+// resize records a terminal size change
+func (r *sessionRecorder) resize(width, height uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *sessionRecorder) event(kind string, data string) {
+	if r.err != nil {
+		return
+	}
+	elapsed := time.Since(r.start).Round(time.Microsecond).Seconds()
+	line, err := json.Marshal([]any{elapsed, kind, data})
+	if err == nil {
+		_, err = fmt.Fprintf(r.out, "%s\n", line)
+	}
+	r.err = err
+}
+
+// Close flushes pending output and closes the recording file
+func (r *sessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) > 0 {
+		r.event("o", string(r.pending))
+		r.pending = nil
+	}
+	return errors.Join(r.err, r.out.Close())
+}
+
+// wrap returns a command.Cli which API client feeds the recorder with attached streams and terminal resizes
+func (r *sessionRecorder) wrap(dockerCli command.Cli, tty bool) command.Cli {
+	return &clientOverrideWrapper{
+		Cli: dockerCli,
+		client: &recordingClient{
+			APIClient: dockerCli.Client(),
+			recorder:  r,
+			tty:       tty,
+		},
+	}
+}
+
+// recordingClient tees the hijacked connection of attached containers and execs to a sessionRecorder
+type recordingClient struct {
+	client.APIClient
+	recorder *sessionRecorder
+	tty      bool
+}
+
+func (c *recordingClient) ContainerAttach(ctx context.Context, ctr string, options container.AttachOptions) (types.HijackedResponse, error) {
+	resp, err := c.APIClient.ContainerAttach(ctx, ctr, options)
+	return c.tee(resp), err
+}
+
+func (c *recordingClient) ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+	resp, err := c.APIClient.ContainerExecAttach(ctx, execID, options)
+	return c.tee(resp), err
+}
+
+func (c *recordingClient) ContainerResize(ctx context.Context, ctr string, options container.ResizeOptions) error {
+	c.recorder.resize(options.Width, options.Height)
+	return c.APIClient.ContainerResize(ctx, ctr, options)
+}
+
+func (c *recordingClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	c.recorder.resize(options.Width, options.Height)
+	return c.APIClient.ContainerExecResize(ctx, execID, options)
+}
+
+func (c *recordingClient) tee(resp types.HijackedResponse) types.HijackedResponse {
+	if resp.Reader == nil {
+		return resp
+	}
+	var w io.Writer = c.recorder
+	if !c.tty {
+		w = &streamDemuxer{out: c.recorder}
+	}
+	resp.Reader = bufio.NewReader(io.TeeReader(resp.Reader, w))
+	return resp
+}
+
+// streamDemuxer extracts payload from the multiplexed stdout/stderr stream sent by engine when no TTY is allocated
+type streamDemuxer struct {
+	out io.Writer
+	// header accumulates the 8 bytes frame header, which announces payload size
+	header    []byte
+	remaining int
+}
+
+func (d *streamDemuxer) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if d.remaining == 0 {
+			need := 8 - len(d.header)
+			if len(p) < need {
+				d.header = append(d.header, p...)
+				return n, nil
+			}
+			d.header = append(d.header, p[:need]...)
+			p = p[need:]
+			d.remaining = int(binary.BigEndian.Uint32(d.header[4:]))
+			d.header = d.header[:0]
+			continue
+		}
+		chunk := min(d.remaining, len(p))
+		if _, err := d.out.Write(p[:chunk]); err != nil {
+			return n, err
+		}
+		d.remaining -= chunk
+		p = p[chunk:]
+	}
+	return n, nil
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestSessionRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	recorder, err := newSessionRecorder(nopWriteCloser{&buf}, 0, 0, []string{"sh", "-c", "echo héllo"})
+	assert.NilError(t, err)
+
+	// "é" is split across writes, and must not be recorded as an invalid sequence
+	_, err = recorder.Write([]byte("h\xc3"))
+	assert.NilError(t, err)
+	_, err = recorder.Write([]byte("\xa9llo\r\n"))
+	assert.NilError(t, err)
+	recorder.resize(120, 40)
+	assert.NilError(t, recorder.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 4)
+
+	var header asciicastHeader
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, header.Version, 2)
+	assert.Equal(t, header.Width, uint(80))
+	assert.Equal(t, header.Height, uint(24))
+	assert.Equal(t, header.Command, `sh -c "echo héllo"`)
+
+	var events [][]any
+	for _, line := range lines[1:] {
+		var event []any
+		assert.NilError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event[1:])
+	}
+	assert.DeepEqual(t, events, [][]any{
+		{"o", "h"},
+		{"o", "éllo\r\n"},
+		{"r", "120x40"},
+	})
+}
+
+func TestStreamDemuxer(t *testing.T) {
+	frame := func(stream byte, payload string) []byte {
+		header := make([]byte, 8)
+		header[0] = stream
+		binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+		return append(header, payload...)
+	}
+	stream := append(frame(1, "stdout\n"), frame(2, "stderr\n")...)
+
+	var buf bytes.Buffer
+	demuxer := &streamDemuxer{out: &buf}
+	// feed the stream in small chunks, so frame headers are split
+	for i := 0; i < len(stream); i += 3 {
+		n, err := demuxer.Write(stream[i:min(i+3, len(stream))])
+		assert.NilError(t, err)
+		assert.Equal(t, n, min(3, len(stream)-i))
+	}
+	assert.Equal(t, buf.String(), "stdout\nstderr\n")
+}
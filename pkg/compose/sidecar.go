@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// SidecarOfExtension declares a service as a sidecar of another (primary) service. A sidecar starts after,
+// stops before and is recreated together with its primary service.
+const SidecarOfExtension = "x-sidecar-of"
+
+// getSidecarOf returns the name of the primary service, or an empty string if service isn't a sidecar
+func getSidecarOf(service types.ServiceConfig) (string, error) {
+	var primary string
+	if _, err := service.Extensions.Get(SidecarOfExtension, &primary); err != nil {
+		return "", fmt.Errorf("service %q has invalid %s: %w", service.Name, SidecarOfExtension, err)
+	}
+	return primary, nil
+}
+
+// applySidecars makes sidecar services depend on their primary service, so they share its lifecycle.
+// Sidecars of a disabled service are disabled as well.
+func applySidecars(project *types.Project) (*types.Project, error) {
+	var disabled []string
+	for name, service := range project.Services {
+		primary, err := getSidecarOf(service)
+		if err != nil {
+			return nil, err
+		}
+		if primary == "" {
+			continue
+		}
+		if primary == name {
+			return nil, fmt.Errorf("service %q can't be a sidecar of itself", name)
+		}
+		if _, ok := project.DisabledServices[primary]; ok {
+			disabled = append(disabled, name)
+			continue
+		}
+		p, ok := project.Services[primary]
+		if !ok {
+			return nil, fmt.Errorf("service %q is a sidecar of undefined service %q", name, primary)
+		}
+		if other, _ := getSidecarOf(p); other != "" {
+			return nil, fmt.Errorf("service %q is a sidecar of %q, which is itself a sidecar of %q", name, primary, other)
+		}
+		if service.DependsOn == nil {
+			service.DependsOn = types.DependsOnConfig{}
+		}
+		service.DependsOn[primary] = types.ServiceDependency{
+			Condition: types.ServiceConditionStarted,
+			Restart:   true,
+			Required:  true,
+		}
+		service.CustomLabels = service.CustomLabels.Add(api.SidecarOfLabel, primary)
+		project.Services[name] = service
+	}
+	if len(disabled) > 0 {
+		return project.WithServicesDisabled(disabled...), nil
+	}
+	return project, nil
+}
+
+// withSidecars extends a selection of services with their sidecars
+func withSidecars(project *types.Project, services []string) []string {
+	if len(services) == 0 {
+		return services
+	}
+	selected := slices.Clone(services)
+	for name, service := range project.Services {
+		primary, _ := getSidecarOf(service)
+		if primary != "" && slices.Contains(services, primary) && !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// withSidecarPrefix groups sidecar container names with their primary service, typically used as log prefix
+func withSidecarPrefix(name string, labels map[string]string) string {
+	if primary := labels[api.SidecarOfLabel]; primary != "" {
+		return primary + "/" + name
+	}
+	return name
+}
+
+// getContainerLogName returns the name used to prefix container logs
+func getContainerLogName(c container.Summary) string {
+	return withSidecarPrefix(getContainerNameWithoutProject(c), c.Labels)
+}
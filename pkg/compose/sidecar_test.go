@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestApplySidecars(t *testing.T) {
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"app": {Name: "app", Image: "app"},
+			"proxy": {
+				Name:       "proxy",
+				Image:      "envoy",
+				Extensions: types.Extensions{SidecarOfExtension: "app"},
+			},
+		},
+	}
+	project, err := applySidecars(project)
+	assert.NilError(t, err)
+	proxy := project.Services["proxy"]
+	assert.DeepEqual(t, proxy.DependsOn["app"], types.ServiceDependency{
+		Condition: types.ServiceConditionStarted,
+		Restart:   true,
+		Required:  true,
+	})
+	assert.Equal(t, proxy.CustomLabels[api.SidecarOfLabel], "app")
+	assert.DeepEqual(t, withSidecars(project, []string{"app"}), []string{"app", "proxy"})
+	assert.Equal(t, len(withSidecars(project, nil)), 0)
+}
+
+func TestApplySidecarsUndefinedPrimary(t *testing.T) {
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"proxy": {
+				Name:       "proxy",
+				Extensions: types.Extensions{SidecarOfExtension: "app"},
+			},
+		},
+	}
+	_, err := applySidecars(project)
+	assert.ErrorContains(t, err, `sidecar of undefined service "app"`)
+}
+
+func TestApplySidecarsDisabledPrimary(t *testing.T) {
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"proxy": {
+				Name:       "proxy",
+				Extensions: types.Extensions{SidecarOfExtension: "app"},
+			},
+		},
+		DisabledServices: types.Services{
+			"app": {Name: "app"},
+		},
+	}
+	project, err := applySidecars(project)
+	assert.NilError(t, err)
+	assert.Equal(t, len(project.Services), 0)
+}
+
+func TestWithSidecarPrefix(t *testing.T) {
+	assert.Equal(t, withSidecarPrefix("proxy-1", map[string]string{api.SidecarOfLabel: "app"}), "app/proxy-1")
+	assert.Equal(t, withSidecarPrefix("app-1", map[string]string{}), "app-1")
+}
@@ -0,0 +1,355 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// SnapshotsDirEnvVar sets the directory volume snapshots are stored in
+const SnapshotsDirEnvVar = "COMPOSE_SNAPSHOTS_DIR"
+
+const (
+	snapshotsDirectory = "compose/snapshots"
+	snapshotExtension  = ".tar"
+	snapshotTimeFormat = "20060102T150405Z"
+)
+
+// snapshotsDir returns the directory snapshots of a project volume are stored in
+func snapshotsDir(dir string, project string, volume string) string {
+	if dir == "" {
+		dir = os.Getenv(SnapshotsDirEnvVar)
+	}
+	if dir == "" {
+		dir = filepath.Join(config.Dir(), snapshotsDirectory)
+	}
+	return filepath.Join(dir, project, volume)
+}
+
+func (s *composeService) VolumeSnapshot(ctx context.Context, project string, options api.VolumeSnapshotOptions) (api.VolumeSnapshot, error) {
+	var snapshot api.VolumeSnapshot
+	err := Run(ctx, func(ctx context.Context) error {
+		vol, err := s.getProjectVolume(ctx, project, options.Volume)
+		if err != nil {
+			return err
+		}
+		dir := snapshotsDir(options.Dir, project, options.Volume)
+		snapshot, err = s.snapshotVolume(ctx, dir, options.Volume, vol.Name)
+		if err != nil {
+			return err
+		}
+		removed, err := applySnapshotRetention(dir, options.Volume, options.Keep, options.MaxAge, time.Now())
+		for _, r := range removed {
+			s.events.On(newEvent("Snapshot "+r.Name, api.Done, "Removed", "retention policy"))
+		}
+		return err
+	}, "snapshot", s.events)
+	return snapshot, err
+}
+
+// snapshotVolume saves the content of a volume as a tar archive in dir
+func (s *composeService) snapshotVolume(ctx context.Context, dir string, key string, volume string) (api.VolumeSnapshot, error) {
+	eventName := "Volume " + volume
+	s.events.On(newEvent(eventName, api.Working, "Snapshotting"))
+	if s.dryRun {
+		s.events.On(newEvent(eventName, api.Done, "Snapshotted"))
+		return api.VolumeSnapshot{Volume: key}, nil
+	}
+	snapshot, err := s.writeSnapshot(ctx, dir, key, volume)
+	if err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return snapshot, err
+	}
+	s.events.On(newEvent(eventName, api.Done, "Snapshotted", snapshot.Name))
+	return snapshot, nil
+}
+
+func (s *composeService) writeSnapshot(ctx context.Context, dir string, key string, volume string) (api.VolumeSnapshot, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	id, err := s.createVolumeHelper(ctx, volume, true)
+	if err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	defer s.removeHelper(ctx, id)
+
+	content, _, err := s.apiClient().CopyFromContainer(ctx, id, "/volume")
+	if err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	defer content.Close() //nolint:errcheck
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := io.Copy(tmp, content); err != nil {
+		_ = tmp.Close()
+		return api.VolumeSnapshot{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+
+	name := newSnapshotName(dir, time.Now())
+	path := filepath.Join(dir, name+snapshotExtension)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return api.VolumeSnapshot{}, err
+	}
+	return snapshotOf(key, path, info), nil
+}
+
+// newSnapshotName names a snapshot after its creation time, with a suffix if another one has been created the same second
+func newSnapshotName(dir string, now time.Time) string {
+	base := now.UTC().Format(snapshotTimeFormat)
+	name := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, name+snapshotExtension)); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func snapshotOf(volume string, path string, info os.FileInfo) api.VolumeSnapshot {
+	return api.VolumeSnapshot{
+		Volume:  volume,
+		Name:    strings.TrimSuffix(info.Name(), snapshotExtension),
+		Created: info.ModTime(),
+		Size:    info.Size(),
+		Path:    path,
+	}
+}
+
+// listSnapshots returns the snapshots stored in dir, oldest first
+func listSnapshots(dir string, volume string) ([]api.VolumeSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []api.VolumeSnapshot
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), snapshotExtension) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshotOf(volume, filepath.Join(dir, e.Name()), info))
+	}
+	slices.SortFunc(snapshots, func(a, b api.VolumeSnapshot) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return snapshots, nil
+}
+
+// applySnapshotRetention removes snapshots older than maxAge, then the oldest ones so at most keep snapshots remain.
+// Zero values disable the respective rule.
+func applySnapshotRetention(dir string, volume string, keep int, maxAge time.Duration, now time.Time) ([]api.VolumeSnapshot, error) {
+	if keep <= 0 && maxAge <= 0 {
+		return nil, nil
+	}
+	snapshots, err := listSnapshots(dir, volume)
+	if err != nil {
+		return nil, err
+	}
+	var removed []api.VolumeSnapshot
+	for i, snapshot := range snapshots {
+		expired := maxAge > 0 && now.Sub(snapshot.Created) > maxAge
+		exceeding := keep > 0 && i < len(snapshots)-keep
+		if !expired && !exceeding {
+			continue
+		}
+		if err := os.Remove(snapshot.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, snapshot)
+	}
+	return removed, nil
+}
+
+func (s *composeService) VolumeSnapshots(ctx context.Context, project string, options api.VolumeSnapshotsOptions) ([]api.VolumeSnapshot, error) {
+	if options.Volume != "" {
+		return listSnapshots(snapshotsDir(options.Dir, project, options.Volume), options.Volume)
+	}
+	root := snapshotsDir(options.Dir, project, "")
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []api.VolumeSnapshot
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		volume, err := listSnapshots(filepath.Join(root, e.Name()), e.Name())
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, volume...)
+	}
+	return snapshots, nil
+}
+
+func (s *composeService) VolumeRestore(ctx context.Context, project string, options api.VolumeRestoreOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.volumeRestore(ctx, project, options)
+	}, "restore", s.events)
+}
+
+func (s *composeService) volumeRestore(ctx context.Context, project string, options api.VolumeRestoreOptions) error {
+	vol, err := s.getProjectVolume(ctx, project, options.Volume)
+	if err != nil {
+		return err
+	}
+	snapshots, err := listSnapshots(snapshotsDir(options.Dir, project, options.Volume), options.Volume)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no snapshot of volume %q: %w", options.Volume, api.ErrNotFound)
+	}
+	snapshot := snapshots[len(snapshots)-1]
+	if options.Snapshot != "" {
+		i := slices.IndexFunc(snapshots, func(s api.VolumeSnapshot) bool {
+			return s.Name == options.Snapshot
+		})
+		if i < 0 {
+			return fmt.Errorf("no such snapshot %q of volume %q: %w", options.Snapshot, options.Volume, api.ErrNotFound)
+		}
+		snapshot = snapshots[i]
+	}
+
+	running, err := s.apiClient().ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(projectFilter(project), filters.Arg("volume", vol.Name)),
+	})
+	if err != nil {
+		return err
+	}
+	if len(running) > 0 {
+		var names []string
+		for _, c := range Containers(running).sorted() {
+			names = append(names, getCanonicalContainerName(c))
+		}
+		return fmt.Errorf("volume %q is used by running containers %s, stop them before restoring a snapshot",
+			options.Volume, strings.Join(names, ", "))
+	}
+
+	eventName := "Volume " + vol.Name
+	s.events.On(newEvent(eventName, api.Working, "Restoring", snapshot.Name))
+	if s.dryRun {
+		s.events.On(newEvent(eventName, api.Done, "Restored", snapshot.Name))
+		return nil
+	}
+	if err := s.restoreSnapshot(ctx, vol.Name, snapshot); err != nil {
+		s.events.On(errorEvent(eventName, err.Error()))
+		return err
+	}
+	s.events.On(newEvent(eventName, api.Done, "Restored", snapshot.Name))
+	return nil
+}
+
+// restoreSnapshot removes the volume content, then extracts the snapshot archive into it
+func (s *composeService) restoreSnapshot(ctx context.Context, volume string, snapshot api.VolumeSnapshot) error {
+	if err := s.ensureHelperImage(ctx, ChownImage); err != nil {
+		return err
+	}
+	_, exitCode, err := s.runHelper(ctx, &container.Config{
+		Image:      ChownImage,
+		User:       "0:0",
+		Entrypoint: []string{"find", "/volume", "-mindepth", "1", "-delete"},
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: volume, Target: "/volume"},
+		},
+		NetworkMode: "none",
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("failed to clear volume %s: exit %d", volume, exitCode)
+	}
+
+	archive, err := os.Open(snapshot.Path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close() //nolint:errcheck
+
+	id, err := s.createVolumeHelper(ctx, volume, false)
+	if err != nil {
+		return err
+	}
+	defer s.removeHelper(ctx, id)
+	// archive entries are prefixed by `volume/`, so they get extracted into the volume mounted by helper
+	return s.apiClient().CopyToContainer(ctx, id, "/", archive, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+}
+
+// snapshotVolumesDown saves a snapshot of project volumes about to be removed by down
+func (s *composeService) snapshotVolumesDown(ctx context.Context, project *types.Project, includeCache bool) error {
+	for _, key := range slices.Sorted(maps.Keys(project.Volumes)) {
+		vol := project.Volumes[key]
+		if bool(vol.External) || (isCacheVolume(vol) && !includeCache) {
+			continue
+		}
+		if _, err := s.apiClient().VolumeInspect(ctx, vol.Name); errdefs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if _, err := s.snapshotVolume(ctx, snapshotsDir("", project.Name, key), key, vol.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func writeTestSnapshot(t *testing.T, dir string, name string, created time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name+snapshotExtension)
+	assert.NilError(t, os.WriteFile(path, []byte("content"), 0o600))
+	assert.NilError(t, os.Chtimes(path, created, created))
+}
+
+func snapshotNames(snapshots []api.VolumeSnapshot) []string {
+	var names []string
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestSnapshotsDir(t *testing.T) {
+	t.Setenv(SnapshotsDirEnvVar, "/snapshots")
+	assert.Equal(t, snapshotsDir("", "demo", "data"), filepath.Join("/snapshots", "demo", "data"))
+	assert.Equal(t, snapshotsDir("/custom", "demo", "data"), filepath.Join("/custom", "demo", "data"))
+}
+
+func TestListSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeTestSnapshot(t, dir, "b", now.Add(-time.Hour))
+	writeTestSnapshot(t, dir, "a", now)
+	writeTestSnapshot(t, dir, "c", now.Add(-2*time.Hour))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".snapshot-123"), nil, 0o600))
+
+	snapshots, err := listSnapshots(dir, "data")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, snapshotNames(snapshots), []string{"c", "b", "a"})
+	assert.Equal(t, snapshots[0].Volume, "data")
+	assert.Equal(t, snapshots[0].Size, int64(len("content")))
+
+	snapshots, err = listSnapshots(filepath.Join(dir, "missing"), "data")
+	assert.NilError(t, err)
+	assert.Equal(t, len(snapshots), 0)
+}
+
+func TestApplySnapshotRetention(t *testing.T) {
+	now := time.Now()
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for i, name := range []string{"s1", "s2", "s3", "s4"} {
+			writeTestSnapshot(t, dir, name, now.Add(-time.Duration(4-i)*24*time.Hour))
+		}
+		return dir
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		dir := setup(t)
+		removed, err := applySnapshotRetention(dir, "data", 2, 0, now)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, snapshotNames(removed), []string{"s1", "s2"})
+		remaining, err := listSnapshots(dir, "data")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, snapshotNames(remaining), []string{"s3", "s4"})
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		dir := setup(t)
+		removed, err := applySnapshotRetention(dir, "data", 0, 60*time.Hour, now)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, snapshotNames(removed), []string{"s1", "s2"})
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		dir := setup(t)
+		removed, err := applySnapshotRetention(dir, "data", 0, 0, now)
+		assert.NilError(t, err)
+		assert.Equal(t, len(removed), 0)
+	})
+}
+
+func TestNewSnapshotName(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, newSnapshotName(dir, now), "20240501T103000Z")
+	writeTestSnapshot(t, dir, "20240501T103000Z", now)
+	assert.Equal(t, newSnapshotName(dir, now), "20240501T103000Z-2")
+}
@@ -0,0 +1,186 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"text/template"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// StableReplicasExtension gives each replica of a scaled service a stable identity: replicas are numbered from 1
+// without gaps, get a `<service>-<index>` hostname, and can mount a dedicated volume rendered from a template
+// like `data-{{.Index}}`. A replica keeps its index, hostname and volumes when recreated.
+const StableReplicasExtension = "x-stable-replicas"
+
+// stableReplicasConfig is the configuration declared by StableReplicasExtension
+type stableReplicasConfig struct {
+	// Volumes maps a project volume mounted by the service to the name template of the per-replica volumes
+	Volumes map[string]string `mapstructure:"volumes" yaml:"volumes,omitempty" json:"volumes,omitempty"`
+}
+
+// stableReplicaTemplateData is the data available to per-replica volume name templates
+type stableReplicaTemplateData struct {
+	Service string
+	Index   int
+}
+
+// getStableReplicas returns the configuration declared by StableReplicasExtension, or nil if service doesn't use it
+func getStableReplicas(service types.ServiceConfig) (*stableReplicasConfig, error) {
+	v, ok := service.Extensions[StableReplicasExtension]
+	if !ok {
+		return nil, nil
+	}
+	switch e := v.(type) {
+	case bool:
+		if !e {
+			return nil, nil
+		}
+		return &stableReplicasConfig{}, nil
+	case map[string]any:
+		var config stableReplicasConfig
+		if _, err := service.Extensions.Get(StableReplicasExtension, &config); err != nil {
+			return nil, fmt.Errorf("service %q has invalid %s: %w", service.Name, StableReplicasExtension, err)
+		}
+		return &config, nil
+	default:
+		return nil, fmt.Errorf("service %q has invalid %s: expected boolean or mapping, got %T", service.Name, StableReplicasExtension, v)
+	}
+}
+
+// stableReplicaVolume renders the per-replica volume key for a replica index
+func stableReplicaVolume(service string, volume string, tmpl string, index int) (string, error) {
+	t, err := template.New(volume).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("service %q: invalid %s volume template %q: %w", service, StableReplicasExtension, tmpl, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, stableReplicaTemplateData{Service: service, Index: index}); err != nil {
+		return "", fmt.Errorf("service %q: invalid %s volume template %q: %w", service, StableReplicasExtension, tmpl, err)
+	}
+	return buf.String(), nil
+}
+
+// applyStableReplicas validates StableReplicasExtension and declares the per-replica volumes of each replica
+// up to the service scale, as top-level project volumes. This is idempotent, so it can run again once scale has
+// been updated.
+func applyStableReplicas(project *types.Project) (*types.Project, error) {
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		config, err := getStableReplicas(service)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			continue
+		}
+		for _, volume := range slices.Sorted(maps.Keys(config.Volumes)) {
+			tmpl := config.Volumes[volume]
+			source, ok := project.Volumes[volume]
+			if !ok {
+				return nil, fmt.Errorf("service %q: %s refers to undefined volume %q", name, StableReplicasExtension, volume)
+			}
+			if source.External {
+				return nil, fmt.Errorf("service %q: %s can't use external volume %q as a template", name, StableReplicasExtension, volume)
+			}
+			if !slices.ContainsFunc(service.Volumes, func(v types.ServiceVolumeConfig) bool {
+				return v.Type == types.VolumeTypeVolume && v.Source == volume
+			}) {
+				return nil, fmt.Errorf("service %q: %s volume %q is not mounted by the service", name, StableReplicasExtension, volume)
+			}
+			first, err := stableReplicaVolume(name, volume, tmpl, 1)
+			if err != nil {
+				return nil, err
+			}
+			second, err := stableReplicaVolume(name, volume, tmpl, 2)
+			if err != nil {
+				return nil, err
+			}
+			if first == second {
+				return nil, fmt.Errorf("service %q: %s volume template %q must depend on {{.Index}}", name, StableReplicasExtension, tmpl)
+			}
+
+			for index := 1; index <= service.GetScale(); index++ {
+				key, err := stableReplicaVolume(name, volume, tmpl, index)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := project.Volumes[key]; ok {
+					continue
+				}
+				replica := source
+				replica.Name = fmt.Sprintf("%s_%s", project.Name, key)
+				project.Volumes[key] = replica
+			}
+		}
+	}
+	return project, nil
+}
+
+// stableReplicaService returns the configuration of a stable replica, with its own hostname and volumes.
+// One-off containers, which don't have a replica index, use the service configuration unchanged.
+func stableReplicaService(service types.ServiceConfig, number int) (types.ServiceConfig, error) {
+	config, err := getStableReplicas(service)
+	if err != nil || config == nil || number < 1 {
+		return service, err
+	}
+	if service.Hostname == "" {
+		service.Hostname = fmt.Sprintf("%s-%d", service.Name, number)
+	}
+	if len(config.Volumes) == 0 {
+		return service, nil
+	}
+	volumes := make([]types.ServiceVolumeConfig, len(service.Volumes))
+	for i, v := range service.Volumes {
+		if tmpl, ok := config.Volumes[v.Source]; ok && v.Type == types.VolumeTypeVolume {
+			key, err := stableReplicaVolume(service.Name, v.Source, tmpl, number)
+			if err != nil {
+				return service, err
+			}
+			v.Source = key
+		}
+		volumes[i] = v
+	}
+	service.Volumes = volumes
+	return service, nil
+}
+
+// stableReplicaNumbers returns the lowest replica numbers not used by containers, so scaling up fills gaps
+// left by removed replicas
+func stableReplicaNumbers(containers []container.Summary, count int) []int {
+	used := map[int]bool{}
+	for _, c := range containers {
+		if n, err := strconv.Atoi(c.Labels[api.ContainerNumberLabel]); err == nil {
+			used[n] = true
+		}
+	}
+	var numbers []int
+	for n := 1; len(numbers) < count; n++ {
+		if !used[n] {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func stableReplicasProject(extension any) *types.Project {
+	scale := 3
+	return &types.Project{
+		Name: "demo",
+		Services: types.Services{
+			"db": {
+				Name:  "db",
+				Scale: &scale,
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"},
+				},
+				Extensions: types.Extensions{
+					StableReplicasExtension: extension,
+				},
+			},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "demo_data", Driver: "local"},
+		},
+	}
+}
+
+func TestApplyStableReplicas(t *testing.T) {
+	project, err := applyStableReplicas(stableReplicasProject(map[string]any{
+		"volumes": map[string]any{"data": "data-{{.Index}}"},
+	}))
+	assert.NilError(t, err)
+	assert.Equal(t, len(project.Volumes), 4)
+	for _, key := range []string{"data-1", "data-2", "data-3"} {
+		vol, ok := project.Volumes[key]
+		assert.Check(t, ok, key)
+		assert.Equal(t, vol.Name, "demo_"+key)
+		assert.Equal(t, vol.Driver, "local")
+	}
+
+	service, err := stableReplicaService(project.Services["db"], 2)
+	assert.NilError(t, err)
+	assert.Equal(t, service.Hostname, "db-2")
+	assert.Equal(t, service.Volumes[0].Source, "data-2")
+	// service config is left unchanged
+	assert.Equal(t, project.Services["db"].Volumes[0].Source, "data")
+
+	oneoff, err := stableReplicaService(project.Services["db"], -1)
+	assert.NilError(t, err)
+	assert.Equal(t, oneoff.Hostname, "")
+	assert.Equal(t, oneoff.Volumes[0].Source, "data")
+}
+
+func TestApplyStableReplicasInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		extension any
+		err       string
+	}{
+		{
+			name:      "invalid type",
+			extension: "yes",
+			err:       "expected boolean or mapping",
+		},
+		{
+			name:      "undefined volume",
+			extension: map[string]any{"volumes": map[string]any{"other": "other-{{.Index}}"}},
+			err:       `refers to undefined volume "other"`,
+		},
+		{
+			name:      "constant template",
+			extension: map[string]any{"volumes": map[string]any{"data": "data"}},
+			err:       "must depend on {{.Index}}",
+		},
+		{
+			name:      "unknown field",
+			extension: map[string]any{"volumes": map[string]any{"data": "data-{{.Number}}"}},
+			err:       "invalid x-stable-replicas volume template",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := applyStableReplicas(stableReplicasProject(tt.extension))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestStableReplicaNumbers(t *testing.T) {
+	containers := []container.Summary{
+		{Labels: map[string]string{api.ContainerNumberLabel: "1"}},
+		{Labels: map[string]string{api.ContainerNumberLabel: "3"}},
+		{Labels: map[string]string{api.ContainerNumberLabel: "5"}},
+	}
+	assert.DeepEqual(t, stableReplicaNumbers(containers, 3), []int{2, 4, 6})
+}
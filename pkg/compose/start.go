@@ -30,8 +30,11 @@ import (
 )
 
 func (s *composeService) Start(ctx context.Context, projectName string, options api.StartOptions) error {
+	ctx = s.withOperationServices(ctx, options.Project, options.Services)
 	return Run(ctx, func(ctx context.Context) error {
-		return s.start(ctx, strings.ToLower(projectName), options, nil)
+		return withDeadline(ctx, options.Deadline, "start", func(ctx context.Context) error {
+			return s.start(ctx, strings.ToLower(projectName), options, nil)
+		})
 	}, "start", s.events)
 }
 
@@ -61,6 +64,11 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 	if err != nil {
 		return err
 	}
+	external, err := s.getExternalContainers(ctx, project, true)
+	if err != nil {
+		return err
+	}
+	containers = append(containers, external...)
 
 	err = InDependencyOrder(ctx, project, func(c context.Context, name string) error {
 		service, err := project.GetService(name)
@@ -68,7 +76,11 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 			return err
 		}
 
-		return s.startService(ctx, project, service, containers, listener, options.WaitTimeout)
+		err = s.startService(ctx, project, service, containers, listener, options.WaitTimeout)
+		if err != nil {
+			notifyService(ctx, name, api.ServiceFailed, err)
+		}
+		return err
 	})
 	if err != nil {
 		return err
@@ -91,7 +103,7 @@ func (s *composeService) start(ctx context.Context, projectName string, options
 		err = s.waitDependencies(ctx, project, project.Name, depends, containers, 0)
 		if err != nil {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-				return fmt.Errorf("application not healthy after %s", options.WaitTimeout)
+				return api.WithErrCode(fmt.Errorf("application not healthy after %s", options.WaitTimeout), api.ErrCodeHealthTimeout)
 			}
 			return err
 		}
@@ -25,8 +25,11 @@ import (
 )
 
 func (s *composeService) Stop(ctx context.Context, projectName string, options api.StopOptions) error {
+	ctx = s.withOperationServices(ctx, options.Project, options.Services)
 	return Run(ctx, func(ctx context.Context) error {
-		return s.stop(ctx, strings.ToLower(projectName), options, nil)
+		return withDeadline(ctx, options.Deadline, "stop", func(ctx context.Context) error {
+			return s.stop(ctx, strings.ToLower(projectName), options, nil)
+		})
 	}, "stop", s.events)
 }
 
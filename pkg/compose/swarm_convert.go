@@ -0,0 +1,437 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// StackNamespaceLabel is set by `docker stack` on stack resources. Compose sets it on resources deployed
+// to swarm, so the stack can also be managed with `docker stack` commands
+const StackNamespaceLabel = "com.docker.stack.namespace"
+
+// swarmResources maps project networks, secrets and configs to the swarm resources they have been deployed as
+type swarmResources struct {
+	networks map[string]string
+	secrets  map[string]swarmFileObject
+	configs  map[string]swarmFileObject
+}
+
+type swarmFileObject struct {
+	id   string
+	name string
+}
+
+// swarmServiceName is the name of a service deployed to swarm, as `docker stack` would name it
+func swarmServiceName(projectName string, service string) string {
+	return projectName + "_" + service
+}
+
+func stackLabels(projectName string, labels types.Labels) map[string]string {
+	result := maps.Clone(labels)
+	if result == nil {
+		result = map[string]string{}
+	}
+	result[StackNamespaceLabel] = projectName
+	return result
+}
+
+// warnSwarmUnsupported reports service attributes ignored when deployed as a swarm service
+func warnSwarmUnsupported(service types.ServiceConfig) {
+	var unsupported []string
+	if service.Build != nil {
+		unsupported = append(unsupported, "build")
+	}
+	if service.ContainerName != "" {
+		unsupported = append(unsupported, "container_name")
+	}
+	if len(service.DependsOn) > 0 {
+		unsupported = append(unsupported, "depends_on")
+	}
+	if len(service.Devices) > 0 {
+		unsupported = append(unsupported, "devices")
+	}
+	if len(service.Links) > 0 {
+		unsupported = append(unsupported, "links")
+	}
+	if service.NetworkMode != "" {
+		unsupported = append(unsupported, "network_mode")
+	}
+	if service.Privileged {
+		unsupported = append(unsupported, "privileged")
+	}
+	if len(service.SecurityOpt) > 0 {
+		unsupported = append(unsupported, "security_opt")
+	}
+	if len(unsupported) > 0 {
+		logrus.Warnf("service %q: ignoring options not supported by swarm: %v", service.Name, unsupported)
+	}
+}
+
+// toSwarmServiceSpec converts a compose service into a swarm service spec
+func (s *composeService) toSwarmServiceSpec(ctx context.Context, project *types.Project, service types.ServiceConfig, resources swarmResources) (swarm.ServiceSpec, error) {
+	var mounts []mount.Mount
+	for _, v := range service.Volumes {
+		m, err := buildMount(*project, v)
+		if err != nil {
+			return swarm.ServiceSpec{}, err
+		}
+		mounts = append(mounts, m)
+	}
+	healthcheck, err := s.ToMobyHealthCheck(ctx, service.HealthCheck)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	secrets, err := toSwarmSecretReferences(service, resources.secrets)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	configs, err := toSwarmConfigReferences(service, resources.configs)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+	ports, err := toSwarmPorts(service.Ports)
+	if err != nil {
+		return swarm.ServiceSpec{}, fmt.Errorf("service %q: %w", service.Name, err)
+	}
+	env := ToMobyEnv(service.Environment)
+	slices.Sort(env)
+
+	var stopGracePeriod *time.Duration
+	if service.StopGracePeriod != nil {
+		d := time.Duration(*service.StopGracePeriod)
+		stopGracePeriod = &d
+	}
+	var dnsConfig *swarm.DNSConfig
+	if len(service.DNS) > 0 || len(service.DNSSearch) > 0 || len(service.DNSOpts) > 0 {
+		dnsConfig = &swarm.DNSConfig{
+			Nameservers: service.DNS,
+			Search:      service.DNSSearch,
+			Options:     service.DNSOpts,
+		}
+	}
+
+	deploy := service.Deploy
+	if deploy == nil {
+		deploy = &types.DeployConfig{}
+	}
+	mode, err := toSwarmServiceMode(service, *deploy)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name:   swarmServiceName(project.Name, service.Name),
+			Labels: stackLabels(project.Name, deploy.Labels),
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image:           api.GetImageNameOrDefault(service, project.Name),
+				Labels:          stackLabels(project.Name, service.Labels),
+				Command:         service.Entrypoint,
+				Args:            service.Command,
+				Hostname:        service.Hostname,
+				Env:             env,
+				Dir:             service.WorkingDir,
+				User:            service.User,
+				Groups:          service.GroupAdd,
+				Mounts:          mounts,
+				StopGracePeriod: stopGracePeriod,
+				StopSignal:      service.StopSignal,
+				TTY:             service.Tty,
+				OpenStdin:       service.StdinOpen,
+				ReadOnly:        service.ReadOnly,
+				Healthcheck:     healthcheck,
+				Hosts:           service.ExtraHosts.AsList(" "),
+				DNSConfig:       dnsConfig,
+				Secrets:         secrets,
+				Configs:         configs,
+				Init:            service.Init,
+				Sysctls:         service.Sysctls,
+				CapabilityAdd:   service.CapAdd,
+				CapabilityDrop:  service.CapDrop,
+				Ulimits:         toUlimits(service.Ulimits),
+			},
+			Resources:     toSwarmResources(deploy.Resources),
+			RestartPolicy: toSwarmRestartPolicy(service),
+			Placement:     toSwarmPlacement(deploy.Placement),
+			Networks:      toSwarmNetworks(service, resources.networks),
+			LogDriver:     toSwarmLogDriver(service.Logging),
+		},
+		Mode:           mode,
+		UpdateConfig:   toSwarmUpdateConfig(deploy.UpdateConfig),
+		RollbackConfig: toSwarmUpdateConfig(deploy.RollbackConfig),
+		EndpointSpec: &swarm.EndpointSpec{
+			Mode:  swarm.ResolutionMode(deploy.EndpointMode),
+			Ports: ports,
+		},
+	}
+	return spec, nil
+}
+
+func toSwarmServiceMode(service types.ServiceConfig, deploy types.DeployConfig) (swarm.ServiceMode, error) {
+	replicas := uint64(1)
+	if deploy.Replicas != nil {
+		replicas = uint64(*deploy.Replicas)
+	} else if service.Scale != nil {
+		replicas = uint64(*service.Scale)
+	}
+	switch deploy.Mode {
+	case "", "replicated":
+		return swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}, nil
+	case "global":
+		return swarm.ServiceMode{Global: &swarm.GlobalService{}}, nil
+	case "replicated-job":
+		return swarm.ServiceMode{ReplicatedJob: &swarm.ReplicatedJob{MaxConcurrent: &replicas, TotalCompletions: &replicas}}, nil
+	case "global-job":
+		return swarm.ServiceMode{GlobalJob: &swarm.GlobalJob{}}, nil
+	default:
+		return swarm.ServiceMode{}, fmt.Errorf("service %q: unsupported deploy mode %q", service.Name, deploy.Mode)
+	}
+}
+
+func toSwarmResources(resources types.Resources) *swarm.ResourceRequirements {
+	convert := func(r *types.Resource) *swarm.Resources {
+		if r == nil {
+			return nil
+		}
+		return &swarm.Resources{
+			NanoCPUs:    int64(r.NanoCPUs * 1e9),
+			MemoryBytes: int64(r.MemoryBytes),
+		}
+	}
+	requirements := &swarm.ResourceRequirements{
+		Reservations: convert(resources.Reservations),
+	}
+	if resources.Limits != nil {
+		requirements.Limits = &swarm.Limit{
+			NanoCPUs:    int64(resources.Limits.NanoCPUs * 1e9),
+			MemoryBytes: int64(resources.Limits.MemoryBytes),
+			Pids:        resources.Limits.Pids,
+		}
+	}
+	return requirements
+}
+
+// toSwarmRestartPolicy converts deploy.restart_policy, or restart as a fallback
+func toSwarmRestartPolicy(service types.ServiceConfig) *swarm.RestartPolicy {
+	if service.Deploy != nil && service.Deploy.RestartPolicy != nil {
+		policy := service.Deploy.RestartPolicy
+		restart := &swarm.RestartPolicy{
+			Condition:   swarm.RestartPolicyCondition(policy.Condition),
+			MaxAttempts: policy.MaxAttempts,
+		}
+		if policy.Delay != nil {
+			delay := time.Duration(*policy.Delay)
+			restart.Delay = &delay
+		}
+		if policy.Window != nil {
+			window := time.Duration(*policy.Window)
+			restart.Window = &window
+		}
+		return restart
+	}
+	if service.Restart == "" {
+		return nil
+	}
+	policy := getRestartPolicy(service)
+	switch policy.Name {
+	case container.RestartPolicyDisabled:
+		return &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionNone}
+	case container.RestartPolicyOnFailure:
+		restart := &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionOnFailure}
+		if policy.MaximumRetryCount > 0 {
+			attempts := uint64(policy.MaximumRetryCount)
+			restart.MaxAttempts = &attempts
+		}
+		return restart
+	default:
+		return &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionAny}
+	}
+}
+
+func toSwarmPlacement(placement types.Placement) *swarm.Placement {
+	var preferences []swarm.PlacementPreference
+	for _, p := range placement.Preferences {
+		preferences = append(preferences, swarm.PlacementPreference{
+			Spread: &swarm.SpreadOver{SpreadDescriptor: p.Spread},
+		})
+	}
+	return &swarm.Placement{
+		Constraints: placement.Constraints,
+		Preferences: preferences,
+		MaxReplicas: placement.MaxReplicas,
+	}
+}
+
+func toSwarmNetworks(service types.ServiceConfig, networks map[string]string) []swarm.NetworkAttachmentConfig {
+	var attachments []swarm.NetworkAttachmentConfig
+	for _, key := range slices.Sorted(maps.Keys(service.Networks)) {
+		aliases := []string{service.Name}
+		if cfg := service.Networks[key]; cfg != nil {
+			aliases = append(aliases, cfg.Aliases...)
+		}
+		attachments = append(attachments, swarm.NetworkAttachmentConfig{
+			Target:  networks[key],
+			Aliases: aliases,
+		})
+	}
+	return attachments
+}
+
+func toSwarmLogDriver(logging *types.LoggingConfig) *swarm.Driver {
+	if logging == nil || logging.Driver == "" {
+		return nil
+	}
+	return &swarm.Driver{
+		Name:    logging.Driver,
+		Options: logging.Options,
+	}
+}
+
+func toSwarmUpdateConfig(update *types.UpdateConfig) *swarm.UpdateConfig {
+	if update == nil {
+		return nil
+	}
+	parallelism := uint64(1)
+	if update.Parallelism != nil {
+		parallelism = *update.Parallelism
+	}
+	return &swarm.UpdateConfig{
+		Parallelism:     parallelism,
+		Delay:           time.Duration(update.Delay),
+		FailureAction:   update.FailureAction,
+		Monitor:         time.Duration(update.Monitor),
+		MaxFailureRatio: update.MaxFailureRatio,
+		Order:           update.Order,
+	}
+}
+
+func toSwarmPorts(ports []types.ServicePortConfig) ([]swarm.PortConfig, error) {
+	var result []swarm.PortConfig
+	for _, p := range ports {
+		var published uint32
+		if p.Published != "" {
+			i, err := strconv.ParseUint(p.Published, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid published port %q: swarm doesn't support port ranges", p.Published)
+			}
+			published = uint32(i)
+		}
+		mode := swarm.PortConfigPublishModeIngress
+		if p.Mode == "host" {
+			mode = swarm.PortConfigPublishModeHost
+		}
+		protocol := swarm.PortConfigProtocolTCP
+		if p.Protocol != "" {
+			protocol = swarm.PortConfigProtocol(p.Protocol)
+		}
+		result = append(result, swarm.PortConfig{
+			Name:          p.Name,
+			Protocol:      protocol,
+			TargetPort:    p.Target,
+			PublishedPort: published,
+			PublishMode:   mode,
+		})
+	}
+	return result, nil
+}
+
+func fileReferenceMode(ref types.FileReferenceConfig) os.FileMode {
+	if ref.Mode != nil {
+		return os.FileMode(*ref.Mode)
+	}
+	return 0o444
+}
+
+func fileReferenceOwner(ref types.FileReferenceConfig) (string, string) {
+	uid, gid := ref.UID, ref.GID
+	if uid == "" {
+		uid = "0"
+	}
+	if gid == "" {
+		gid = "0"
+	}
+	return uid, gid
+}
+
+func toSwarmSecretReferences(service types.ServiceConfig, secrets map[string]swarmFileObject) ([]*swarm.SecretReference, error) {
+	var refs []*swarm.SecretReference
+	for _, ref := range service.Secrets {
+		secret, ok := secrets[ref.Source]
+		if !ok {
+			return nil, fmt.Errorf("service %q refers to undefined secret %q", service.Name, ref.Source)
+		}
+		target := ref.Target
+		if target == "" {
+			target = ref.Source
+		}
+		uid, gid := fileReferenceOwner(types.FileReferenceConfig(ref))
+		refs = append(refs, &swarm.SecretReference{
+			File: &swarm.SecretReferenceFileTarget{
+				Name: target,
+				UID:  uid,
+				GID:  gid,
+				Mode: fileReferenceMode(types.FileReferenceConfig(ref)),
+			},
+			SecretID:   secret.id,
+			SecretName: secret.name,
+		})
+	}
+	return refs, nil
+}
+
+func toSwarmConfigReferences(service types.ServiceConfig, configs map[string]swarmFileObject) ([]*swarm.ConfigReference, error) {
+	var refs []*swarm.ConfigReference
+	for _, ref := range service.Configs {
+		config, ok := configs[ref.Source]
+		if !ok {
+			return nil, fmt.Errorf("service %q refers to undefined config %q", service.Name, ref.Source)
+		}
+		target := ref.Target
+		if target == "" {
+			target = "/" + ref.Source
+		}
+		uid, gid := fileReferenceOwner(types.FileReferenceConfig(ref))
+		refs = append(refs, &swarm.ConfigReference{
+			File: &swarm.ConfigReferenceFileTarget{
+				Name: target,
+				UID:  uid,
+				GID:  gid,
+				Mode: fileReferenceMode(types.FileReferenceConfig(ref)),
+			},
+			ConfigID:   config.id,
+			ConfigName: config.name,
+		})
+	}
+	return refs, nil
+}
@@ -0,0 +1,433 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) SwarmDeploy(ctx context.Context, project *types.Project, options api.SwarmDeployOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.swarmDeploy(ctx, project, options)
+	}, "deploy", s.events)
+}
+
+func (s *composeService) swarmDeploy(ctx context.Context, project *types.Project, options api.SwarmDeployOptions) error {
+	info, err := s.apiClient().Info(ctx)
+	if err != nil {
+		return err
+	}
+	if !info.Swarm.ControlAvailable {
+		return errors.New(`this node is not a swarm manager. Use "docker swarm init" or "docker swarm join" to connect this node to swarm and try again`)
+	}
+
+	var resources swarmResources
+	resources.networks, err = s.ensureSwarmNetworks(ctx, project)
+	if err != nil {
+		return err
+	}
+	resources.secrets, err = s.ensureSwarmSecrets(ctx, project)
+	if err != nil {
+		return err
+	}
+	resources.configs, err = s.ensureSwarmConfigs(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.getStackServices(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	if options.Prune {
+		if err := s.pruneStackServices(ctx, project, existing); err != nil {
+			return err
+		}
+	}
+
+	var deployed []string
+	for _, name := range slices.Sorted(maps.Keys(project.Services)) {
+		service := project.Services[name]
+		warnSwarmUnsupported(service)
+		spec, err := s.toSwarmServiceSpec(ctx, project, service, resources)
+		if err != nil {
+			return err
+		}
+		if err := s.deploySwarmService(ctx, spec, existing, options); err != nil {
+			return err
+		}
+		deployed = append(deployed, spec.Name)
+	}
+
+	if !options.Wait {
+		return nil
+	}
+	if options.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.WaitTimeout)
+		defer cancel()
+	}
+	return s.waitStackConvergence(ctx, project.Name, deployed)
+}
+
+func stackFilter(projectName string) filters.KeyValuePair {
+	return filters.Arg("label", fmt.Sprintf("%s=%s", StackNamespaceLabel, projectName))
+}
+
+func (s *composeService) ensureSwarmNetworks(ctx context.Context, project *types.Project) (map[string]string, error) {
+	existing, err := s.apiClient().NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(stackFilter(project.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	networks := map[string]string{}
+	for _, key := range slices.Sorted(maps.Keys(project.Networks)) {
+		nw := project.Networks[key]
+		networks[key] = nw.Name
+		if nw.External {
+			if _, err := s.apiClient().NetworkInspect(ctx, nw.Name, network.InspectOptions{}); err != nil {
+				return nil, fmt.Errorf("network %q is declared as external, but could not be found: %w", nw.Name, err)
+			}
+			continue
+		}
+		if slices.ContainsFunc(existing, func(n network.Summary) bool {
+			return n.Name == nw.Name
+		}) {
+			continue
+		}
+		driver := nw.Driver
+		if driver == "" {
+			driver = "overlay"
+		}
+		var ipam *network.IPAM
+		if nw.Ipam.Config != nil {
+			var config []network.IPAMConfig
+			for _, pool := range nw.Ipam.Config {
+				config = append(config, network.IPAMConfig{
+					Subnet:     pool.Subnet,
+					IPRange:    pool.IPRange,
+					Gateway:    pool.Gateway,
+					AuxAddress: pool.AuxiliaryAddresses,
+				})
+			}
+			ipam = &network.IPAM{
+				Driver: nw.Ipam.Driver,
+				Config: config,
+			}
+		}
+		eventName := fmt.Sprintf("Network %s", nw.Name)
+		s.events.On(creatingEvent(eventName))
+		_, err := s.apiClient().NetworkCreate(ctx, nw.Name, network.CreateOptions{
+			Driver:     driver,
+			Scope:      "swarm",
+			Internal:   nw.Internal,
+			Attachable: nw.Attachable,
+			IPAM:       ipam,
+			Options:    nw.DriverOpts,
+			Labels:     stackLabels(project.Name, nw.Labels),
+		})
+		if err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return nil, fmt.Errorf("failed to create network %s: %w", nw.Name, err)
+		}
+		s.events.On(createdEvent(eventName))
+	}
+	return networks, nil
+}
+
+// readFileObject returns the content of a secret or config declared by the compose file
+func (s *composeService) readFileObject(project *types.Project, source types.FileObjectConfig, mountType mountType) ([]byte, error) {
+	if source.File != "" {
+		return os.ReadFile(source.File)
+	}
+	content, err := s.resolveFileContent(project, source, mountType)
+	return []byte(content), err
+}
+
+func (s *composeService) ensureSwarmSecrets(ctx context.Context, project *types.Project) (map[string]swarmFileObject, error) {
+	secrets := map[string]swarmFileObject{}
+	for _, key := range slices.Sorted(maps.Keys(project.Secrets)) {
+		secret := project.Secrets[key]
+		if secret.External {
+			inspect, _, err := s.apiClient().SecretInspectWithRaw(ctx, secret.Name)
+			if err != nil {
+				return nil, fmt.Errorf("secret %q is declared as external, but could not be found: %w", secret.Name, err)
+			}
+			secrets[key] = swarmFileObject{id: inspect.ID, name: secret.Name}
+			continue
+		}
+		data, err := s.readFileObject(project, types.FileObjectConfig(secret), secretMount)
+		if err != nil {
+			return nil, err
+		}
+		spec := swarm.SecretSpec{
+			Annotations: swarm.Annotations{
+				Name:   secret.Name,
+				Labels: stackLabels(project.Name, secret.Labels),
+			},
+			Data: data,
+		}
+		eventName := fmt.Sprintf("Secret %s", secret.Name)
+		inspect, _, err := s.apiClient().SecretInspectWithRaw(ctx, secret.Name)
+		if err == nil {
+			// swarm rejects update of secret data, a secret has to be renamed when content changes
+			if err := s.apiClient().SecretUpdate(ctx, inspect.ID, inspect.Version, spec); err != nil {
+				s.events.On(errorEvent(eventName, err.Error()))
+				return nil, fmt.Errorf("failed to update secret %s: %w", secret.Name, err)
+			}
+			secrets[key] = swarmFileObject{id: inspect.ID, name: secret.Name}
+			continue
+		}
+		s.events.On(creatingEvent(eventName))
+		created, err := s.apiClient().SecretCreate(ctx, spec)
+		if err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return nil, fmt.Errorf("failed to create secret %s: %w", secret.Name, err)
+		}
+		s.events.On(createdEvent(eventName))
+		secrets[key] = swarmFileObject{id: created.ID, name: secret.Name}
+	}
+	return secrets, nil
+}
+
+func (s *composeService) ensureSwarmConfigs(ctx context.Context, project *types.Project) (map[string]swarmFileObject, error) {
+	configs := map[string]swarmFileObject{}
+	for _, key := range slices.Sorted(maps.Keys(project.Configs)) {
+		config := project.Configs[key]
+		if config.External {
+			inspect, _, err := s.apiClient().ConfigInspectWithRaw(ctx, config.Name)
+			if err != nil {
+				return nil, fmt.Errorf("config %q is declared as external, but could not be found: %w", config.Name, err)
+			}
+			configs[key] = swarmFileObject{id: inspect.ID, name: config.Name}
+			continue
+		}
+		data, err := s.readFileObject(project, types.FileObjectConfig(config), configMount)
+		if err != nil {
+			return nil, err
+		}
+		spec := swarm.ConfigSpec{
+			Annotations: swarm.Annotations{
+				Name:   config.Name,
+				Labels: stackLabels(project.Name, config.Labels),
+			},
+			Data: data,
+		}
+		eventName := fmt.Sprintf("Config %s", config.Name)
+		inspect, _, err := s.apiClient().ConfigInspectWithRaw(ctx, config.Name)
+		if err == nil {
+			if err := s.apiClient().ConfigUpdate(ctx, inspect.ID, inspect.Version, spec); err != nil {
+				s.events.On(errorEvent(eventName, err.Error()))
+				return nil, fmt.Errorf("failed to update config %s: %w", config.Name, err)
+			}
+			configs[key] = swarmFileObject{id: inspect.ID, name: config.Name}
+			continue
+		}
+		s.events.On(creatingEvent(eventName))
+		created, err := s.apiClient().ConfigCreate(ctx, spec)
+		if err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return nil, fmt.Errorf("failed to create config %s: %w", config.Name, err)
+		}
+		s.events.On(createdEvent(eventName))
+		configs[key] = swarmFileObject{id: created.ID, name: config.Name}
+	}
+	return configs, nil
+}
+
+func (s *composeService) getStackServices(ctx context.Context, projectName string) (map[string]swarm.Service, error) {
+	services, err := s.apiClient().ServiceList(ctx, swarm.ServiceListOptions{
+		Filters: filters.NewArgs(stackFilter(projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]swarm.Service{}
+	for _, service := range services {
+		existing[service.Spec.Name] = service
+	}
+	return existing, nil
+}
+
+func (s *composeService) pruneStackServices(ctx context.Context, project *types.Project, existing map[string]swarm.Service) error {
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		declared := slices.ContainsFunc(project.ServiceNames(), func(service string) bool {
+			return swarmServiceName(project.Name, service) == name
+		})
+		if declared {
+			continue
+		}
+		eventName := fmt.Sprintf("Service %s", name)
+		s.events.On(removingEvent(eventName))
+		if err := s.apiClient().ServiceRemove(ctx, existing[name].ID); err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return fmt.Errorf("failed to remove service %s: %w", name, err)
+		}
+		s.events.On(removedEvent(eventName))
+	}
+	return nil
+}
+
+// deploySwarmService creates service, or updates it if it already exists in the stack
+func (s *composeService) deploySwarmService(ctx context.Context, spec swarm.ServiceSpec, existing map[string]swarm.Service, options api.SwarmDeployOptions) error {
+	image := spec.TaskTemplate.ContainerSpec.Image
+	current, update := existing[spec.Name]
+
+	queryRegistry := false
+	switch options.ResolveImage {
+	case api.ResolveImageAlways, "":
+		queryRegistry = true
+	case api.ResolveImageChanged:
+		// image reference in existing spec has been pinned to a digest
+		queryRegistry = !update || !strings.HasPrefix(current.Spec.TaskTemplate.ContainerSpec.Image, image+"@")
+		if !queryRegistry {
+			spec.TaskTemplate.ContainerSpec.Image = current.Spec.TaskTemplate.ContainerSpec.Image
+		}
+	}
+	var registryAuth string
+	if named, err := reference.ParseNormalizedNamed(image); err == nil {
+		registryAuth, err = encodedAuth(named, s.configFile())
+		if err != nil {
+			return err
+		}
+	}
+
+	eventName := fmt.Sprintf("Service %s", spec.Name)
+	var warnings []string
+	if update {
+		// keep force update counter, so engine doesn't restart tasks for services without changes
+		spec.TaskTemplate.ForceUpdate = current.Spec.TaskTemplate.ForceUpdate
+		s.events.On(newEvent(eventName, api.Working, "Updating"))
+		response, err := s.apiClient().ServiceUpdate(ctx, current.ID, current.Version, spec, swarm.ServiceUpdateOptions{
+			EncodedRegistryAuth: registryAuth,
+			QueryRegistry:       queryRegistry,
+		})
+		if err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return fmt.Errorf("failed to update service %s: %w", spec.Name, err)
+		}
+		warnings = response.Warnings
+		s.events.On(newEvent(eventName, api.Done, "Updated"))
+	} else {
+		s.events.On(creatingEvent(eventName))
+		response, err := s.apiClient().ServiceCreate(ctx, spec, swarm.ServiceCreateOptions{
+			EncodedRegistryAuth: registryAuth,
+			QueryRegistry:       queryRegistry,
+		})
+		if err != nil {
+			s.events.On(errorEvent(eventName, err.Error()))
+			return fmt.Errorf("failed to create service %s: %w", spec.Name, err)
+		}
+		warnings = response.Warnings
+		s.events.On(createdEvent(eventName))
+	}
+	for _, warning := range warnings {
+		logrus.Warn(warning)
+	}
+	return nil
+}
+
+// waitStackConvergence waits for deployed services to have all their tasks running, or jobs completed,
+// reporting progress as tasks get scheduled
+func (s *composeService) waitStackConvergence(ctx context.Context, projectName string, services []string) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	reported := map[string]string{}
+	for {
+		list, err := s.apiClient().ServiceList(ctx, swarm.ServiceListOptions{
+			Filters: filters.NewArgs(stackFilter(projectName)),
+			Status:  true,
+		})
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, service := range list {
+			if !slices.Contains(services, service.Spec.Name) {
+				continue
+			}
+			eventName := fmt.Sprintf("Service %s", service.Spec.Name)
+			converged, status, err := serviceConvergence(service)
+			if err != nil {
+				s.events.On(errorEvent(eventName, err.Error()))
+				return fmt.Errorf("service %s failed to converge: %w", service.Spec.Name, err)
+			}
+			if !converged {
+				pending++
+			}
+			if reported[service.Spec.Name] == status {
+				continue
+			}
+			reported[service.Spec.Name] = status
+			if converged {
+				s.events.On(newEvent(eventName, api.Done, status))
+			} else {
+				s.events.On(newEvent(eventName, api.Working, status))
+			}
+		}
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timeout waiting for %d service(s) to converge", pending)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceConvergence tells if a swarm service has converged, with a human-readable status
+func serviceConvergence(service swarm.Service) (bool, string, error) {
+	if update := service.UpdateStatus; update != nil {
+		switch update.State {
+		case swarm.UpdateStatePaused, swarm.UpdateStateRollbackPaused, swarm.UpdateStateRollbackCompleted:
+			return false, "", fmt.Errorf("update %s: %s", update.State, update.Message)
+		case swarm.UpdateStateUpdating, swarm.UpdateStateRollbackStarted:
+			return false, string(update.State), nil
+		}
+	}
+	status := service.ServiceStatus
+	if status == nil {
+		return false, "Pending", nil
+	}
+	if service.Spec.Mode.ReplicatedJob != nil || service.Spec.Mode.GlobalJob != nil {
+		text := fmt.Sprintf("%d/%d tasks completed", status.CompletedTasks, status.DesiredTasks)
+		return status.RunningTasks == 0 && status.CompletedTasks >= status.DesiredTasks, text, nil
+	}
+	text := fmt.Sprintf("%d/%d tasks running", status.RunningTasks, status.DesiredTasks)
+	return status.RunningTasks >= status.DesiredTasks, text, nil
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func swarmTestProject() *types.Project {
+	replicas := 3
+	grace := types.Duration(5 * time.Second)
+	return &types.Project{
+		Name: "test",
+		Services: types.Services{
+			"web": {
+				Name:            "web",
+				Image:           "nginx:alpine",
+				Restart:         "on-failure:2",
+				StopGracePeriod: &grace,
+				Environment:     types.NewMappingWithEquals([]string{"B=2", "A=1"}),
+				Ports:           []types.ServicePortConfig{{Target: 80, Published: "8080", Protocol: "tcp"}},
+				Networks:        map[string]*types.ServiceNetworkConfig{"default": {Aliases: []string{"www"}}},
+				Secrets:         []types.ServiceSecretConfig{{Source: "token"}},
+				Deploy: &types.DeployConfig{
+					Replicas: &replicas,
+					Labels:   types.Labels{"tier": "front"},
+				},
+			},
+		},
+		Networks: types.Networks{
+			"default": {Name: "test_default"},
+		},
+		Secrets: types.Secrets{
+			"token": {Name: "test_token", Content: "s3cr3t"},
+		},
+	}
+}
+
+func TestToSwarmServiceSpec(t *testing.T) {
+	project := swarmTestProject()
+	tested := &composeService{}
+	spec, err := tested.toSwarmServiceSpec(context.TODO(), project, project.Services["web"], swarmResources{
+		networks: map[string]string{"default": "test_default"},
+		secrets:  map[string]swarmFileObject{"token": {id: "secret-id", name: "test_token"}},
+	})
+	assert.NilError(t, err)
+
+	assert.Equal(t, spec.Name, "test_web")
+	assert.DeepEqual(t, spec.Labels, map[string]string{"tier": "front", StackNamespaceLabel: "test"})
+	assert.Equal(t, *spec.Mode.Replicated.Replicas, uint64(3))
+
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	assert.Equal(t, containerSpec.Image, "nginx:alpine")
+	assert.DeepEqual(t, containerSpec.Env, []string{"A=1", "B=2"})
+	assert.Equal(t, *containerSpec.StopGracePeriod, 5*time.Second)
+	assert.Equal(t, len(containerSpec.Secrets), 1)
+	assert.Equal(t, containerSpec.Secrets[0].SecretID, "secret-id")
+	assert.Equal(t, containerSpec.Secrets[0].File.Name, "token")
+
+	assert.Equal(t, spec.TaskTemplate.RestartPolicy.Condition, swarm.RestartPolicyConditionOnFailure)
+	assert.Equal(t, *spec.TaskTemplate.RestartPolicy.MaxAttempts, uint64(2))
+	assert.DeepEqual(t, spec.TaskTemplate.Networks, []swarm.NetworkAttachmentConfig{
+		{Target: "test_default", Aliases: []string{"web", "www"}},
+	})
+	assert.DeepEqual(t, spec.EndpointSpec.Ports, []swarm.PortConfig{{
+		Protocol:      swarm.PortConfigProtocolTCP,
+		TargetPort:    80,
+		PublishedPort: 8080,
+		PublishMode:   swarm.PortConfigPublishModeIngress,
+	}})
+}
+
+func TestToSwarmPortsRejectsRange(t *testing.T) {
+	_, err := toSwarmPorts([]types.ServicePortConfig{{Target: 80, Published: "8080-8081"}})
+	assert.ErrorContains(t, err, "swarm doesn't support port ranges")
+}
+
+func TestSwarmDeploy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	apiClient.EXPECT().Info(gomock.Any()).Return(system.Info{Swarm: swarm.Info{ControlAvailable: true}}, nil)
+	apiClient.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(nil, nil)
+	apiClient.EXPECT().NetworkCreate(gomock.Any(), "test_default", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, options network.CreateOptions) (network.CreateResponse, error) {
+			assert.Equal(t, options.Driver, "overlay")
+			assert.Equal(t, options.Labels[StackNamespaceLabel], "test")
+			return network.CreateResponse{ID: "net-id"}, nil
+		})
+	apiClient.EXPECT().SecretInspectWithRaw(gomock.Any(), "test_token").Return(swarm.Secret{}, nil, api.ErrNotFound)
+	apiClient.EXPECT().SecretCreate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, spec swarm.SecretSpec) (swarm.SecretCreateResponse, error) {
+			assert.Equal(t, string(spec.Data), "s3cr3t")
+			return swarm.SecretCreateResponse{ID: "secret-id"}, nil
+		})
+	apiClient.EXPECT().ServiceList(gomock.Any(), gomock.Any()).Return([]swarm.Service{{
+		ID:   "old-id",
+		Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "test_old"}},
+	}}, nil)
+	apiClient.EXPECT().ServiceRemove(gomock.Any(), "old-id").Return(nil)
+	apiClient.EXPECT().ServiceCreate(gomock.Any(), gomock.Any(), swarm.ServiceCreateOptions{
+		EncodedRegistryAuth: "e30=",
+		QueryRegistry:       false,
+	}).DoAndReturn(func(_ context.Context, spec swarm.ServiceSpec, _ swarm.ServiceCreateOptions) (swarm.ServiceCreateResponse, error) {
+		assert.Equal(t, spec.Name, "test_web")
+		return swarm.ServiceCreateResponse{ID: "web-id"}, nil
+	})
+
+	err := tested.swarmDeploy(context.TODO(), swarmTestProject(), api.SwarmDeployOptions{
+		Prune:        true,
+		ResolveImage: api.ResolveImageNever,
+	})
+	assert.NilError(t, err)
+}
+
+func TestSwarmDeployRequiresManager(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	apiClient, cli := prepareMocks(mockCtrl)
+	tested := &composeService{dockerCli: cli, events: &ignore{}}
+
+	apiClient.EXPECT().Info(gomock.Any()).Return(system.Info{}, nil)
+	err := tested.swarmDeploy(context.TODO(), swarmTestProject(), api.SwarmDeployOptions{})
+	assert.ErrorContains(t, err, "not a swarm manager")
+}
+
+func TestServiceConvergence(t *testing.T) {
+	replicas := uint64(2)
+	service := swarm.Service{
+		Spec:          swarm.ServiceSpec{Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}},
+		ServiceStatus: &swarm.ServiceStatus{RunningTasks: 1, DesiredTasks: 2},
+	}
+	converged, status, err := serviceConvergence(service)
+	assert.NilError(t, err)
+	assert.Check(t, !converged)
+	assert.Equal(t, status, "1/2 tasks running")
+
+	service.ServiceStatus.RunningTasks = 2
+	converged, _, err = serviceConvergence(service)
+	assert.NilError(t, err)
+	assert.Check(t, converged)
+
+	service.UpdateStatus = &swarm.UpdateStatus{State: swarm.UpdateStateRollbackCompleted, Message: "task failed"}
+	_, _, err = serviceConvergence(service)
+	assert.ErrorContains(t, err, "task failed")
+}
@@ -0,0 +1,190 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// BuildExtension configures images built by compose. Its `tag-template` attribute sets an additional tag applied
+// to service images, as a Go template. A template without `:` sets the tag of the service image, otherwise a full
+// image reference:
+//
+//	x-build:
+//	  tag-template: "{{.GitShortSHA}}-{{.BuildTime}}"
+//
+// Template fields are Project, Service, GitSHA, GitShortSHA, GitBranch and BuildTime.
+const BuildExtension = "x-build"
+
+// buildTimeFormat is used to render BuildTime in a tag, as `:` isn't allowed
+const buildTimeFormat = "20060102T150405Z"
+
+// tagTemplateData is the data tag-template is rendered with
+type tagTemplateData struct {
+	Project     string
+	Service     string
+	GitSHA      string
+	GitShortSHA string
+	// GitBranch has characters not allowed in a tag replaced by `-`
+	GitBranch string
+	BuildTime string
+}
+
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// getTagTemplate returns the tag-template set by BuildExtension, or nil
+func getTagTemplate(project *types.Project) (*template.Template, error) {
+	ext, ok := project.Extensions[BuildExtension]
+	if !ok {
+		return nil, nil
+	}
+	m, ok := ext.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: expected a mapping, got %T", BuildExtension, ext)
+	}
+	v, ok := m["tag-template"]
+	if !ok {
+		return nil, nil
+	}
+	text, ok := v.(string)
+	if !ok || text == "" {
+		return nil, fmt.Errorf("invalid %s.tag-template: expected a string, got %v", BuildExtension, v)
+	}
+	tmpl, err := template.New("tag-template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s.tag-template: %w", BuildExtension, err)
+	}
+	return tmpl, nil
+}
+
+// gitMetadata reads current commit and branch of the git repository dir belongs to.
+// Branch is empty when HEAD is detached.
+func gitMetadata(ctx context.Context, dir string) (string, string, error) {
+	sha, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("%s.tag-template requires git metadata, failed to read commit in %s: %w", BuildExtension, dir, err)
+	}
+	branch, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("%s.tag-template requires git metadata, failed to read branch in %s: %w", BuildExtension, dir, err)
+	}
+	b := strings.TrimSpace(string(branch))
+	if b == "HEAD" {
+		b = ""
+	}
+	return strings.TrimSpace(string(sha)), b, nil
+}
+
+func newTagTemplateData(project, service, sha, branch string, buildTime time.Time) tagTemplateData {
+	short := sha
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	return tagTemplateData{
+		Project:     project,
+		Service:     service,
+		GitSHA:      sha,
+		GitShortSHA: short,
+		GitBranch:   invalidTagChars.ReplaceAllString(branch, "-"),
+		BuildTime:   buildTime.UTC().Format(buildTimeFormat),
+	}
+}
+
+// renderTag renders tag-template as a full image reference, based on the service image when template only sets a tag
+func renderTag(tmpl *template.Template, image string, data tagTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s.tag-template for service %q: %w", BuildExtension, data.Service, err)
+	}
+	tag := buf.String()
+	if !strings.Contains(tag, ":") {
+		named, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			return "", err
+		}
+		tag = reference.FamiliarName(named) + ":" + tag
+	}
+	if _, err := reference.ParseNormalizedNamed(tag); err != nil {
+		return "", fmt.Errorf("%s.tag-template for service %q rendered an invalid image reference %q: %w", BuildExtension, data.Service, tag, err)
+	}
+	return tag, nil
+}
+
+// applyTagTemplate labels images to be built with git metadata and build time, and adds the tag rendered by
+// BuildExtension tag-template. Both services and project are updated, so the builder gets the same definition
+// for a service whatever it reads it from: project must be a copy owned by the caller, as the one Build derives
+// with WithSelectedServices. Build sections are copied, so services sharing one aren't affected.
+func applyTagTemplate(ctx context.Context, project *types.Project, services types.Services, now time.Time) error {
+	tmpl, err := getTagTemplate(project)
+	if err != nil || tmpl == nil {
+		return err
+	}
+	sha, branch, err := gitMetadata(ctx, project.WorkingDir)
+	if err != nil {
+		return err
+	}
+	for name, service := range services {
+		if service.Build == nil {
+			continue
+		}
+		image := api.GetImageNameOrDefault(service, project.Name)
+		tag, err := renderTag(tmpl, image, newTagTemplateData(project.Name, name, sha, branch, now))
+		if err != nil {
+			return err
+		}
+		build := *service.Build
+		build.Labels = maps.Clone(build.Labels)
+		if build.Labels == nil {
+			build.Labels = types.Labels{}
+		}
+		build.Labels[api.GitRevisionLabel] = sha
+		if branch != "" {
+			build.Labels[api.GitBranchLabel] = branch
+		}
+		build.Labels[api.BuildTimeLabel] = now.UTC().Format(time.RFC3339)
+		build.Tags = append(append(types.StringList{}, build.Tags...), tag)
+		service.Build = &build
+		services[name] = service
+		if _, ok := project.Services[name]; ok {
+			project.Services[name] = service
+		}
+	}
+	return nil
+}
+
+// templateTagFromLabels renders the tag-template of an image previously built, based on its labels,
+// so the tag pushed is the one applied by build
+func templateTagFromLabels(tmpl *template.Template, projectName, service, image string, labels map[string]string) (string, error) {
+	created, err := time.Parse(time.RFC3339, labels[api.BuildTimeLabel])
+	if err != nil {
+		return "", fmt.Errorf("image %s has no build time label, it must be built again to apply %s.tag-template", image, BuildExtension)
+	}
+	return renderTag(tmpl, image, newTagTemplateData(projectName, service, labels[api.GitRevisionLabel], labels[api.GitBranchLabel], created))
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func gitRepository(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "feature/tags"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		out, err := cmd.CombinedOutput()
+		assert.NilError(t, err, string(out))
+	}
+	sha, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	assert.NilError(t, err)
+	return dir, strings.TrimSpace(string(sha))
+}
+
+func TestApplyTagTemplate(t *testing.T) {
+	dir, sha := gitRepository(t)
+	project := &types.Project{
+		Name:       "myproject",
+		WorkingDir: dir,
+		Services: types.Services{
+			"web": {Name: "web", Build: &types.BuildConfig{Context: "."}},
+			"api": {Name: "api", Image: "registry.example.com/api:latest", Build: &types.BuildConfig{Context: "api"}},
+		},
+		Extensions: types.Extensions{
+			BuildExtension: map[string]any{"tag-template": "{{.GitBranch}}-{{.GitShortSHA}}-{{.BuildTime}}"},
+		},
+	}
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	services := types.Services{"web": project.Services["web"], "api": project.Services["api"]}
+	assert.NilError(t, applyTagTemplate(t.Context(), project, services, now))
+
+	suffix := ":feature-tags-" + sha[:7] + "-20261016T123000Z"
+	assert.DeepEqual(t, []string(project.Services["web"].Build.Tags), []string{"myproject-web" + suffix})
+	assert.DeepEqual(t, []string(services["api"].Build.Tags), []string{"registry.example.com/api" + suffix})
+	assert.DeepEqual(t, project.Services["api"].Build.Labels, types.Labels{
+		api.GitRevisionLabel: sha,
+		api.GitBranchLabel:   "feature/tags",
+		api.BuildTimeLabel:   "2026-10-16T12:30:00Z",
+	})
+
+	// tag pushed is rendered again from labels set by build
+	tmpl, err := getTagTemplate(project)
+	assert.NilError(t, err)
+	tag, err := templateTagFromLabels(tmpl, "myproject", "api", "registry.example.com/api:latest", project.Services["api"].Build.Labels)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "registry.example.com/api"+suffix)
+}
+
+func TestTagTemplateFullReference(t *testing.T) {
+	project := &types.Project{Extensions: types.Extensions{
+		BuildExtension: map[string]any{"tag-template": "{{.Service}}:{{.GitShortSHA}}"},
+	}}
+	tmpl, err := getTagTemplate(project)
+	assert.NilError(t, err)
+	tag, err := renderTag(tmpl, "myproject-web", newTagTemplateData("myproject", "web", "0123456789abcdef", "", time.Now()))
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "web:0123456")
+
+	_, err = renderTag(tmpl, "myproject-web", newTagTemplateData("myproject", "Web", "", "", time.Now()))
+	assert.ErrorContains(t, err, `rendered an invalid image reference "Web:"`)
+}
+
+func TestInvalidTagTemplate(t *testing.T) {
+	_, err := getTagTemplate(&types.Project{Extensions: types.Extensions{BuildExtension: "tag"}})
+	assert.Error(t, err, "invalid x-build: expected a mapping, got string")
+	_, err = getTagTemplate(&types.Project{Extensions: types.Extensions{
+		BuildExtension: map[string]any{"tag-template": "{{.Unknown"},
+	}})
+	assert.ErrorContains(t, err, "invalid x-build.tag-template")
+}
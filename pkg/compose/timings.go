@@ -0,0 +1,272 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// WithServiceTimings registers a hook to get notified with time spent on each service by up and down
+// operations, per phase, as measured from progress events. Can be set multiple times.
+func WithServiceTimings(hook api.ServiceTimingsHook) Option {
+	return func(s *composeService) error {
+		s.timingsHooks = append(s.timingsHooks, hook)
+		return nil
+	}
+}
+
+type timingsProjectKey struct{}
+
+// timingsProject is the project services timings are reported for, project is nil when the compose file
+// isn't available
+type timingsProject struct {
+	name    string
+	project *types.Project
+}
+
+// withTimingsProject declares the project the next Compose operation applies to, so events can be
+// attributed to services
+func (s *composeService) withTimingsProject(ctx context.Context, projectName string, project *types.Project) context.Context {
+	if len(s.timingsHooks) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, timingsProjectKey{}, timingsProject{name: projectName, project: project})
+}
+
+type timingPhase int
+
+const (
+	phasePull timingPhase = iota
+	phaseBuild
+	phaseCreate
+	phaseStart
+	phaseHealthWait
+	phaseStop
+	phaseRemove
+)
+
+// timingPhases maps the text of the progress event a resource emits as it enters a phase
+var timingPhases = map[string]timingPhase{
+	api.StatusPulling:  phasePull,
+	api.StatusBuilding: phaseBuild,
+	api.StatusCreating: phaseCreate,
+	"Recreate":         phaseCreate,
+	api.StatusStarting: phaseStart,
+	api.StatusWaiting:  phaseHealthWait,
+	api.StatusStopping: phaseStop,
+	api.StatusRemoving: phaseRemove,
+}
+
+func (p timingPhase) duration(t *api.ServiceTimings) *time.Duration {
+	switch p {
+	case phasePull:
+		return &t.Pull
+	case phaseBuild:
+		return &t.Build
+	case phaseCreate:
+		return &t.Create
+	case phaseStart:
+		return &t.Start
+	case phaseHealthWait:
+		return &t.HealthWait
+	case phaseStop:
+		return &t.Stop
+	default:
+		return &t.Remove
+	}
+}
+
+type phaseRecord struct {
+	phase timingPhase
+	start time.Time
+}
+
+// timingsRecord collects phases durations of the resources an operation applies to
+type timingsRecord struct {
+	operation string
+	target    timingsProject
+	running   map[string]phaseRecord
+	durations map[string]map[timingPhase]time.Duration
+}
+
+// timingsProcessor decorates an api.EventProcessor to measure time spent by resources in each phase,
+// reported to hooks by service as operation completes
+type timingsProcessor struct {
+	api.EventProcessor
+	hooks  []api.ServiceTimingsHook
+	clock  clockwork.Clock
+	mu     sync.Mutex
+	record *timingsRecord
+}
+
+func newTimingsProcessor(bus api.EventProcessor, clock clockwork.Clock, hooks []api.ServiceTimingsHook) *timingsProcessor {
+	return &timingsProcessor{
+		EventProcessor: bus,
+		hooks:          hooks,
+		clock:          clock,
+	}
+}
+
+func (t *timingsProcessor) Start(ctx context.Context, operation string) {
+	if target, ok := ctx.Value(timingsProjectKey{}).(timingsProject); ok {
+		t.mu.Lock()
+		// nested operations are measured as part of the outer one
+		if t.record == nil {
+			t.record = &timingsRecord{
+				operation: operation,
+				target:    target,
+				running:   map[string]phaseRecord{},
+				durations: map[string]map[timingPhase]time.Duration{},
+			}
+		}
+		t.mu.Unlock()
+	}
+	t.EventProcessor.Start(ctx, operation)
+}
+
+func (t *timingsProcessor) On(events ...api.Resource) {
+	t.mu.Lock()
+	if t.record != nil {
+		now := t.clock.Now()
+		for _, e := range events {
+			t.record.on(e, now)
+		}
+	}
+	t.mu.Unlock()
+	t.EventProcessor.On(events...)
+}
+
+func (r *timingsRecord) on(e api.Resource, now time.Time) {
+	running, isRunning := r.running[e.ID]
+	phase, isPhase := timingPhases[e.Text]
+	if e.Status == api.Working && (!isPhase || isRunning && running.phase == phase) {
+		return
+	}
+	if isRunning {
+		r.end(e.ID, now)
+	}
+	if e.Status == api.Working {
+		r.running[e.ID] = phaseRecord{phase: phase, start: now}
+	}
+}
+
+func (r *timingsRecord) end(id string, now time.Time) {
+	running := r.running[id]
+	delete(r.running, id)
+	if r.durations[id] == nil {
+		r.durations[id] = map[timingPhase]time.Duration{}
+	}
+	r.durations[id][running.phase] += now.Sub(running.start)
+}
+
+func (t *timingsProcessor) Done(operation string, success bool) {
+	t.EventProcessor.Done(operation, success)
+	t.mu.Lock()
+	record := t.record
+	if record == nil || record.operation != operation {
+		t.mu.Unlock()
+		return
+	}
+	t.record = nil
+	t.mu.Unlock()
+
+	now := t.clock.Now()
+	for id := range record.running {
+		record.end(id, now)
+	}
+	timings := record.servicesTimings()
+	for _, hook := range t.hooks {
+		hook(operation, timings)
+	}
+}
+
+// servicesTimings aggregates resources phases durations by service. Containers of a service run in
+// parallel, so the longest duration is reported for each phase
+func (r *timingsRecord) servicesTimings() []api.ServiceTimings {
+	services := map[string]*api.ServiceTimings{}
+	for id, phases := range r.durations {
+		for _, name := range r.target.services(id) {
+			timings, ok := services[name]
+			if !ok {
+				timings = &api.ServiceTimings{Service: name}
+				services[name] = timings
+			}
+			for phase, d := range phases {
+				if current := phase.duration(timings); d > *current {
+					*current = d
+				}
+			}
+		}
+	}
+	result := make([]api.ServiceTimings, 0, len(services))
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		result = append(result, *services[name])
+	}
+	return result
+}
+
+// services returns the services a progress event resource ID relates to
+func (p timingsProject) services(id string) []string {
+	if image, ok := strings.CutPrefix(id, "Image "); ok {
+		if p.project == nil {
+			return nil
+		}
+		var services []string
+		for _, name := range slices.Sorted(maps.Keys(p.project.Services)) {
+			service := p.project.Services[name]
+			if service.Image == image || api.GetImageNameOrDefault(service, p.name) == image {
+				services = append(services, name)
+			}
+		}
+		return services
+	}
+	name, ok := strings.CutPrefix(id, "Container ")
+	if !ok {
+		return nil
+	}
+	if p.project != nil {
+		for _, service := range p.project.Services {
+			if service.ContainerName != "" && service.ContainerName == name {
+				return []string{service.Name}
+			}
+		}
+	}
+	// default container name is <project>-<service>-<number>, see getDefaultContainerName
+	rest, ok := strings.CutPrefix(name, p.name+api.Separator)
+	if !ok {
+		return nil
+	}
+	i := strings.LastIndex(rest, api.Separator)
+	if i <= 0 {
+		return nil
+	}
+	if _, err := strconv.Atoi(rest[i+len(api.Separator):]); err != nil {
+		return nil
+	}
+	return []string{rest[:i]}
+}
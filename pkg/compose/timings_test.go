@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestServiceTimings(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	var operation string
+	var got []api.ServiceTimings
+	bus := newTimingsProcessor(&ignore{}, clock, []api.ServiceTimingsHook{
+		func(op string, timings []api.ServiceTimings) {
+			operation = op
+			got = timings
+		},
+	})
+	s := &composeService{timingsHooks: bus.hooks}
+	project := &types.Project{Name: "demo", Services: types.Services{
+		"web": {Name: "web", Scale: intPtr(2)},
+		"db":  {Name: "db", Image: "postgres", ContainerName: "database"},
+	}}
+
+	ctx := s.withTimingsProject(t.Context(), project.Name, project)
+	err := Run(ctx, func(ctx context.Context) error {
+		bus.On(pullingEvent("postgres"), buildingEvent("demo-web"))
+		clock.Advance(3 * time.Second)
+		bus.On(pulledEvent("postgres"))
+		clock.Advance(time.Second)
+		bus.On(builtEvent("demo-web"), creatingEvent("Container database"),
+			creatingEvent("Container demo-web-1"), creatingEvent("Container demo-web-2"))
+		clock.Advance(time.Second)
+		bus.On(createdEvent("Container database"), createdEvent("Container demo-web-1"), startingEvent("Container database"))
+		clock.Advance(time.Second)
+		bus.On(createdEvent("Container demo-web-2"), startedEvent("Container database"), waiting("Container database"))
+		clock.Advance(2 * time.Second)
+		// repeated waiting events don't reset the phase
+		bus.On(newEvent("Container database", api.Working, api.StatusWaiting, "starting"))
+		clock.Advance(2 * time.Second)
+		bus.On(healthy("Container database"), startingEvent("Container demo-web-1"))
+		clock.Advance(time.Second)
+		return nil
+	}, "up", bus)
+	assert.NilError(t, err)
+
+	assert.Equal(t, operation, "up")
+	assert.DeepEqual(t, got, []api.ServiceTimings{
+		{Service: "db", Pull: 3 * time.Second, Create: time.Second, Start: time.Second, HealthWait: 4 * time.Second},
+		{Service: "web", Build: 4 * time.Second, Create: 2 * time.Second, Start: time.Second},
+	})
+	assert.Equal(t, got[0].Total(), 9*time.Second)
+
+	// operations without a project are not reported
+	got = nil
+	err = Run(t.Context(), func(ctx context.Context) error {
+		bus.On(stoppingEvent("Container demo-web-1"))
+		return nil
+	}, "stop", bus)
+	assert.NilError(t, err)
+	assert.Assert(t, got == nil)
+}
+
+func TestTimingsProjectServices(t *testing.T) {
+	p := timingsProject{name: "demo"}
+	assert.DeepEqual(t, p.services("Container demo-my-app-3"), []string{"my-app"})
+	assert.Assert(t, p.services("Container demo-app-run-0a1b2c") == nil)
+	assert.Assert(t, p.services("Container other-app-1") == nil)
+	assert.Assert(t, p.services("Image nginx") == nil)
+	assert.Assert(t, p.services("Network demo_default") == nil)
+}
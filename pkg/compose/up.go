@@ -38,20 +38,65 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// reportSkipped notifies about services which didn't get any container, as up has been interrupted
+func (s *composeService) reportSkipped(ctx context.Context, project *types.Project, services []string) {
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true)
+	if err != nil {
+		return
+	}
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	for _, name := range slices.Sorted(slices.Values(services)) {
+		if len(containers.filter(isService(name))) == 0 {
+			s.events.On(skippedEvent("Service "+name, "operation interrupted"))
+		}
+	}
+}
+
 func (s *composeService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error { //nolint:gocyclo
-	err := Run(ctx, tracing.SpanWrapFunc("project/up", tracing.ProjectOptions(ctx, project), func(ctx context.Context) error {
-		err := s.create(ctx, project, options.Create)
-		if err != nil {
+	if options.Start.NavigationMenu {
+		if err := ValidateKeyBindings(project, options.Start.KeyBindings); err != nil {
 			return err
 		}
-		if options.Start.Attach == nil {
-			return s.start(ctx, project.Name, options.Start, nil)
+	}
+	err := s.checkPolicy(ctx, "up", project)
+	if err != nil {
+		return err
+	}
+	s.warnRootless(ctx, project)
+	s.warnUnboundedLogs(ctx, project)
+	ctx, j := s.startJournal(ctx, project, options)
+	ctx = withServiceNotifier(ctx, options.Notify)
+	ctx = s.withOperationServices(ctx, project, options.Create.Services)
+	ctx = s.withTimingsProject(ctx, project.Name, project)
+	creator := s.withQuietPhases(&options)
+
+	err = Run(ctx, tracing.SpanWrapFunc("project/up", tracing.ProjectOptions(ctx, project), func(ctx context.Context) error {
+		s.removeExpiredOneOffs(ctx, project.Name)
+		err := withDeadline(ctx, options.Create.Deadline, "create", func(ctx context.Context) error {
+			return creator.create(ctx, project, options.Create)
+		})
+		if err == nil && options.Start.Attach == nil {
+			err = withDeadline(ctx, options.Start.Deadline, "start", func(ctx context.Context) error {
+				return s.start(ctx, project.Name, options.Start, nil)
+			})
+		}
+		if err != nil && ctx.Err() != nil {
+			s.reportSkipped(context.WithoutCancel(ctx), project, options.Create.Services)
 		}
-		return nil
+		return err
 	}), "up", s.events)
 	if err != nil {
 		return err
 	}
+	j.remove()
+
+	if options.SyncHosts {
+		if err := s.syncProjectHosts(project); err != nil {
+			return err
+		}
+	}
 
 	if options.Start.Attach == nil {
 		return err
@@ -88,7 +133,15 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 			}
 			tracing.KeyboardMetrics(ctx, options.Start.NavigationMenu, isDockerDesktopActive)
 			navigationMenu = formatter.NewKeyboardManager(isDockerDesktopActive, signalChan)
-			logConsumer = navigationMenu.Decorate(logConsumer)
+			if len(options.Start.KeyBindings) > 0 {
+				toggles := newLogToggles(logConsumer, project)
+				options.Start.Attach = toggles
+				logConsumer = navigationMenu.Decorate(toggles)
+				s.addKeyBindings(project, options.Start.KeyBindings, navigationMenu, toggles, logConsumer)
+			} else {
+				logConsumer = navigationMenu.Decorate(logConsumer)
+			}
+			kEvents = navigationMenu.Listen(ctx, kEvents)
 		}
 	}
 
@@ -125,6 +178,15 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 		}
 	}
 
+	if navigationMenu != nil && options.Reload != nil {
+		reloader := newConfigReloader(s, project, options, logConsumer, navigationMenu.SetReloadPending)
+		navigationMenu.EnableReload(reloader.apply)
+		eg.Go(func() error {
+			reloader.watch(globalCtx)
+			return nil
+		})
+	}
+
 	eg.Go(func() error {
 		first := true
 		gracefulTeardown := func() {
@@ -282,7 +344,9 @@ func (s *composeService) Up(ctx context.Context, project *types.Project, options
 	})
 
 	// We use the parent context without cancellation as we manage sigterm to stop the stack
-	err = s.start(context.WithoutCancel(ctx), project.Name, options.Start, printer.HandleEvent)
+	err = withDeadline(context.WithoutCancel(ctx), options.Start.Deadline, "start", func(ctx context.Context) error {
+		return s.start(ctx, project.Name, options.Start, printer.HandleEvent)
+	})
 	if err != nil && !isTerminated.Load() { // Ignore error if the process is terminated
 		cancel()
 		_ = eg.Wait()
@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// VariablesExtension is the project extension declaring variables the model expects. Values are validated
+// once project is loaded, and declared defaults apply to interpolation:
+//
+//	x-variables:
+//	  HTTP_PORT:
+//	    type: int
+//	    default: 8080
+//	    description: Port the frontend is published on
+//	  LOG_LEVEL:
+//	    type: enum
+//	    values: [debug, info, warn]
+//	  API_URL:
+//	    type: url
+//	    required: true
+const VariablesExtension = "x-variables"
+
+// Supported types of declared variables
+const (
+	VariableTypeString = "string"
+	VariableTypeInt    = "int"
+	VariableTypeBool   = "bool"
+	VariableTypeEnum   = "enum"
+	VariableTypeURL    = "url"
+)
+
+// VariableDeclaration describes a variable declared by VariablesExtension
+type VariableDeclaration struct {
+	// Type of the variable value, string if not set
+	Type string `mapstructure:"type"`
+	// Default value used when variable is not set
+	Default any `mapstructure:"default"`
+	// Description documents the variable
+	Description string `mapstructure:"description"`
+	// Required reports an error when variable is not set and has no default value
+	Required bool `mapstructure:"required"`
+	// Values lists allowed values for an enum variable
+	Values []string `mapstructure:"values"`
+}
+
+// DefaultValue returns the declared default value as a string, or an empty string
+func (d VariableDeclaration) DefaultValue() string {
+	if d.Default == nil {
+		return ""
+	}
+	return fmt.Sprint(d.Default)
+}
+
+// Validate checks value is set if required, and conforms to the declared type
+func (d VariableDeclaration) Validate(name, value string) error {
+	if value == "" {
+		if d.Required {
+			if d.Description != "" {
+				return fmt.Errorf("variable %s is required: %s", name, d.Description)
+			}
+			return fmt.Errorf("variable %s is required", name)
+		}
+		return nil
+	}
+	switch d.Type {
+	case "", VariableTypeString:
+	case VariableTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("variable %s must be an integer, got %q", name, value)
+		}
+	case VariableTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("variable %s must be a boolean, got %q", name, value)
+		}
+	case VariableTypeEnum:
+		if !slices.Contains(d.Values, value) {
+			return fmt.Errorf("variable %s must be one of %s, got %q", name, strings.Join(d.Values, ", "), value)
+		}
+	case VariableTypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("variable %s must be an absolute URL, got %q", name, value)
+		}
+	}
+	return nil
+}
+
+// GetVariableDeclarations returns variables declared by VariablesExtension
+func GetVariableDeclarations(extensions types.Extensions) (map[string]VariableDeclaration, error) {
+	var declarations map[string]VariableDeclaration
+	if _, err := extensions.Get(VariablesExtension, &declarations); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", VariablesExtension, err)
+	}
+	for name, d := range declarations {
+		switch d.Type {
+		case "", VariableTypeString, VariableTypeInt, VariableTypeBool, VariableTypeURL:
+		case VariableTypeEnum:
+			if len(d.Values) == 0 {
+				return nil, fmt.Errorf("invalid %s: enum variable %s must declare values", VariablesExtension, name)
+			}
+		default:
+			return nil, fmt.Errorf("invalid %s: unsupported type %q for variable %s", VariablesExtension, d.Type, name)
+		}
+		if d.Default != nil {
+			if err := d.Validate(name, d.DefaultValue()); err != nil {
+				return nil, fmt.Errorf("invalid %s default: %w", VariablesExtension, err)
+			}
+		}
+	}
+	return declarations, nil
+}
+
+// validateVariables checks project environment against variables declared by VariablesExtension
+func validateVariables(project *types.Project) error {
+	declarations, err := GetVariableDeclarations(project.Extensions)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(declarations)) {
+		if err := declarations[name].Validate(name, project.Environment[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithVariableDefaults sets defaults declared by VariablesExtension in local compose files for unset
+// variables, so they apply to interpolation
+func WithVariableDefaults(o *cli.ProjectOptions) error {
+	if o.Environment == nil {
+		o.Environment = types.Mapping{}
+	}
+	for _, model := range localModels(o) {
+		declarations, err := GetVariableDeclarations(model)
+		if err != nil {
+			return err
+		}
+		for name, d := range declarations {
+			if _, ok := o.Environment[name]; !ok && d.Default != nil {
+				o.Environment[name] = d.DefaultValue()
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestVariableDeclarationValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		declaration VariableDeclaration
+		value       string
+		err         string
+	}{
+		{name: "unset", declaration: VariableDeclaration{Type: VariableTypeInt}},
+		{name: "required", declaration: VariableDeclaration{Required: true, Description: "API token"}, err: "variable VAR is required: API token"},
+		{name: "int", declaration: VariableDeclaration{Type: VariableTypeInt}, value: "42"},
+		{name: "not an int", declaration: VariableDeclaration{Type: VariableTypeInt}, value: "4.2", err: `variable VAR must be an integer, got "4.2"`},
+		{name: "bool", declaration: VariableDeclaration{Type: VariableTypeBool}, value: "true"},
+		{name: "not a bool", declaration: VariableDeclaration{Type: VariableTypeBool}, value: "yes", err: `variable VAR must be a boolean, got "yes"`},
+		{name: "enum", declaration: VariableDeclaration{Type: VariableTypeEnum, Values: []string{"a", "b"}}, value: "b"},
+		{name: "not in enum", declaration: VariableDeclaration{Type: VariableTypeEnum, Values: []string{"a", "b"}}, value: "c", err: `variable VAR must be one of a, b, got "c"`},
+		{name: "url", declaration: VariableDeclaration{Type: VariableTypeURL}, value: "https://example.com/api"},
+		{name: "relative url", declaration: VariableDeclaration{Type: VariableTypeURL}, value: "example.com", err: `variable VAR must be an absolute URL, got "example.com"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.declaration.Validate("VAR", tt.value)
+			if tt.err == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGetVariableDeclarations(t *testing.T) {
+	declarations, err := GetVariableDeclarations(types.Extensions{
+		VariablesExtension: map[string]any{
+			"PORT": map[string]any{"type": "int", "default": 8080, "description": "published port"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, declarations["PORT"].DefaultValue(), "8080")
+	assert.Equal(t, declarations["PORT"].Description, "published port")
+
+	_, err = GetVariableDeclarations(types.Extensions{
+		VariablesExtension: map[string]any{"MODE": map[string]any{"type": "enum"}},
+	})
+	assert.Error(t, err, "invalid x-variables: enum variable MODE must declare values")
+
+	_, err = GetVariableDeclarations(types.Extensions{
+		VariablesExtension: map[string]any{"RATIO": map[string]any{"type": "float"}},
+	})
+	assert.Error(t, err, `invalid x-variables: unsupported type "float" for variable RATIO`)
+
+	_, err = GetVariableDeclarations(types.Extensions{
+		VariablesExtension: map[string]any{"PORT": map[string]any{"type": "int", "default": "http"}},
+	})
+	assert.Error(t, err, `invalid x-variables default: variable PORT must be an integer, got "http"`)
+}
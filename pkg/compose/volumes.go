@@ -18,14 +18,28 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
 	"slices"
 
+	"github.com/docker/cli/cli"
+	cmd "github.com/docker/cli/cli/command/container"
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/volume"
 )
 
+// DefaultVolumeBrowseImage is used to browse volumes when no image is set
+const DefaultVolumeBrowseImage = "busybox:stable"
+
+// volumeBrowseTarget is the path volume is mounted to in browse container
+const volumeBrowseTarget = "/volume"
+
 func (s *composeService) Volumes(ctx context.Context, project string, options api.VolumesOptions) ([]api.VolumesSummary, error) {
 	allContainers, err := s.apiClient().ContainerList(ctx, container.ListOptions{
 		Filters: filters.NewArgs(projectFilter(project)),
@@ -55,6 +69,11 @@ func (s *composeService) Volumes(ctx context.Context, project string, options ap
 	}
 
 	projectVolumes := volumesResponse.Volumes
+	if options.Usage {
+		if err := s.volumesUsage(ctx, projectVolumes); err != nil {
+			return nil, err
+		}
+	}
 
 	if len(options.Services) == 0 {
 		return projectVolumes, nil
@@ -80,3 +99,125 @@ func (s *composeService) Volumes(ctx context.Context, project string, options ap
 
 	return volumes, nil
 }
+
+// volumesUsage sets disk usage data on volumes, as computed by the engine
+func (s *composeService) volumesUsage(ctx context.Context, volumes []*volume.Volume) error {
+	usage, err := s.apiClient().DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		for _, u := range usage.Volumes {
+			if u.Name == v.Name {
+				v.UsageData = u.UsageData
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *composeService) VolumeInspect(ctx context.Context, project string, name string) (api.VolumeInspect, error) {
+	vol, err := s.getProjectVolume(ctx, project, name)
+	if err != nil {
+		return api.VolumeInspect{}, err
+	}
+	inspect := api.VolumeInspect{
+		Volume: vol,
+	}
+
+	containers, err := s.apiClient().ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(projectFilter(project), filters.Arg("volume", vol.Name)),
+	})
+	if err != nil {
+		return inspect, err
+	}
+	for _, c := range Containers(containers).sorted() {
+		inspect.Containers = append(inspect.Containers, getCanonicalContainerName(c))
+		service := c.Labels[api.ServiceLabel]
+		if !slices.Contains(inspect.Services, service) {
+			inspect.Services = append(inspect.Services, service)
+		}
+	}
+	slices.Sort(inspect.Services)
+	return inspect, nil
+}
+
+// getProjectVolume looks up a volume created by compose for project
+func (s *composeService) getProjectVolume(ctx context.Context, project string, name string) (volume.Volume, error) {
+	volumes, err := s.apiClient().VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(projectFilter(project), volumeFilter(name)),
+	})
+	if err != nil {
+		return volume.Volume{}, err
+	}
+	if len(volumes.Volumes) == 0 {
+		return volume.Volume{}, fmt.Errorf("no such volume %q in project %q: %w", name, project, api.ErrNotFound)
+	}
+	return s.apiClient().VolumeInspect(ctx, volumes.Volumes[0].Name)
+}
+
+func (s *composeService) VolumeBrowse(ctx context.Context, project string, options api.VolumeBrowseOptions) (int, error) {
+	vol, err := s.getProjectVolume(ctx, project, options.Volume)
+	if err != nil {
+		return 0, err
+	}
+	image := options.Image
+	if image == "" {
+		image = DefaultVolumeBrowseImage
+	}
+	command := options.Command
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+	if err := s.ensureHelperImage(ctx, image); err != nil {
+		return 0, err
+	}
+
+	tty := s.stdin().IsTerminal()
+	created, err := s.apiClient().ContainerCreate(ctx, &container.Config{
+		Image:        image,
+		Cmd:          command,
+		WorkingDir:   volumeBrowseTarget,
+		Tty:          tty,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}, &container.HostConfig{
+		AutoRemove: true,
+		Mounts: []mount.Mount{{
+			Type:     mount.TypeVolume,
+			Source:   vol.Name,
+			Target:   volumeBrowseTarget,
+			ReadOnly: options.ReadOnly,
+		}},
+	}, nil, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// remove cancellable context signal handler so we can forward signals to container without compose to exit
+	signal.Reset()
+
+	sigc := make(chan os.Signal, 128)
+	signal.Notify(sigc)
+	go cmd.ForwardAllSignals(ctx, s.apiClient(), created.ID, sigc)
+	defer signal.Stop(sigc)
+
+	err = cmd.RunStart(ctx, s.dockerCli, &cmd.StartOptions{
+		OpenStdin:  true,
+		Attach:     true,
+		Containers: []string{created.ID},
+		DetachKeys: s.configFile().DetachKeys,
+	})
+	var stErr cli.StatusError
+	if errors.As(err, &stErr) {
+		return stErr.StatusCode, nil
+	}
+	return 0, err
+}
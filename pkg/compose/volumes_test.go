@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
@@ -85,3 +86,58 @@ func TestVolumes(t *testing.T) {
 	assert.NilError(t, err)
 	assert.DeepEqual(t, volumes, expected)
 }
+
+func TestVolumesUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	vol1 := &volume.Volume{Name: testProject + "_vol1"}
+	ctx := context.Background()
+	mockApi.EXPECT().ContainerList(ctx, gomock.Any()).Return(nil, nil)
+	mockApi.EXPECT().VolumeList(ctx, gomock.Any()).Return(volume.ListResponse{Volumes: []*volume.Volume{vol1}}, nil)
+	mockApi.EXPECT().DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}}).
+		Return(types.DiskUsage{Volumes: []*volume.Volume{
+			{Name: "other", UsageData: &volume.UsageData{Size: 1, RefCount: 1}},
+			{Name: testProject + "_vol1", UsageData: &volume.UsageData{Size: 42, RefCount: 2}},
+		}}, nil)
+
+	volumes, err := tested.Volumes(ctx, testProject, api.VolumesOptions{Usage: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(volumes), 1)
+	assert.DeepEqual(t, volumes[0].UsageData, &volume.UsageData{Size: 42, RefCount: 2})
+}
+
+func TestVolumeInspect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockApi, mockCli := prepareMocks(mockCtrl)
+	tested := composeService{
+		dockerCli: mockCli,
+	}
+
+	ctx := context.Background()
+	name := testProject + "_data"
+	mockApi.EXPECT().VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(projectFilter(testProject), volumeFilter("data")),
+	}).Return(volume.ListResponse{Volumes: []*volume.Volume{{Name: name}}}, nil)
+	mockApi.EXPECT().VolumeInspect(ctx, name).Return(volume.Volume{Name: name, Driver: "local"}, nil)
+	mockApi.EXPECT().ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(projectFilter(testProject), filters.Arg("volume", name)),
+	}).Return([]container.Summary{
+		testContainer("db", "1", false),
+		testContainer("backup", "2", false),
+	}, nil)
+
+	inspect, err := tested.VolumeInspect(ctx, testProject, "data")
+	assert.NilError(t, err)
+	assert.Equal(t, inspect.Volume.Driver, "local")
+	assert.DeepEqual(t, inspect.Services, []string{"backup", "db"})
+	assert.Equal(t, len(inspect.Containers), 2)
+}
@@ -20,11 +20,11 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
-	"strconv"
 	"strings"
 	gsync "sync"
 	"time"
@@ -33,6 +33,7 @@ import (
 	"github.com/docker/compose/v5/internal/sync"
 	"github.com/docker/compose/v5/internal/tracing"
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
 	cutils "github.com/docker/compose/v5/pkg/utils"
 	"github.com/docker/compose/v5/pkg/watch"
 	"github.com/moby/buildkit/util/progress/progressui"
@@ -118,11 +119,9 @@ func (w *Watcher) Stop() error {
 // Currently, an implementation that batches files and transfers them using
 // the Moby `Untar` API.
 func (s *composeService) getSyncImplementation(project *types.Project) (sync.Syncer, error) {
-	var useTar bool
-	if useTarEnv, ok := os.LookupEnv("COMPOSE_EXPERIMENTAL_WATCH_TAR"); ok {
-		useTar, _ = strconv.ParseBool(useTarEnv)
-	} else {
-		useTar = true
+	useTar, err := s.featureEnabled(project, features.WatchTar)
+	if err != nil {
+		return nil, err
 	}
 	if !useTar {
 		return nil, errors.New("no available sync implementation")
@@ -132,6 +131,7 @@ func (s *composeService) getSyncImplementation(project *types.Project) (sync.Syn
 }
 
 func (s *composeService) Watch(ctx context.Context, project *types.Project, options api.WatchOptions) error {
+	ctx = s.withOperationServices(ctx, project, options.Services)
 	wait, err := s.watch(ctx, project, options)
 	if err != nil {
 		return err
@@ -144,6 +144,8 @@ type watchRule struct {
 	include watch.PathMatcher
 	ignore  watch.PathMatcher
 	service string
+	// signal is sent to service containers instead of restarting them, see WatchRestartExtension
+	signal string
 }
 
 func (r watchRule) Matches(event watch.FileEvent) *sync.PathMapping {
@@ -198,9 +200,15 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 	}
 	eg, ctx := errgroup.WithContext(ctx)
 
+	defaultBackend, err := watch.DefaultBackend()
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		rules []watchRule
-		paths []string
+		rules     []watchRule
+		syncBacks []syncBackRule
+		paths     = map[watch.Backend][]string{}
 	)
 	for serviceName, service := range project.Services {
 		config, err := loadDevelopmentConfig(service, project)
@@ -255,7 +263,11 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 					}
 				}
 			}
-			paths = append(paths, trigger.Path)
+			backend, err := getWatchBackend(trigger, defaultBackend)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", service.Name, err)
+			}
+			paths[backend] = append(paths[backend], trigger.Path)
 		}
 
 		serviceWatchRules, err := getWatchRules(config, service)
@@ -263,13 +275,23 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 			return nil, err
 		}
 		rules = append(rules, serviceWatchRules...)
+
+		for _, rule := range serviceWatchRules {
+			conflict, err := getSyncBack(rule.Trigger)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", service.Name, err)
+			}
+			if conflict != "" {
+				syncBacks = append(syncBacks, syncBackRule{watchRule: rule, conflict: conflict})
+			}
+		}
 	}
 
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("none of the selected services is configured for watch, consider setting a 'develop' section")
 	}
 
-	watcher, err := watch.NewWatcher(paths)
+	watcher, err := newWatcher(paths)
 	if err != nil {
 		return nil, err
 	}
@@ -282,6 +304,11 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 	eg.Go(func() error {
 		return s.watchEvents(ctx, project, options, watcher, syncer, rules)
 	})
+	for _, rule := range syncBacks {
+		eg.Go(func() error {
+			return s.syncBack(ctx, project, rule, options)
+		})
+	}
 	options.LogTo.Log(api.WatchLogger, "Watch enabled")
 
 	return func() error {
@@ -293,6 +320,31 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 	}, nil
 }
 
+// WatchBackendExtension selects, on a watch rule, the backend used to receive file events for the watched path
+const WatchBackendExtension = "x-backend"
+
+func getWatchBackend(trigger types.Trigger, defaultBackend watch.Backend) (watch.Backend, error) {
+	var backend string
+	if ok, err := trigger.Extensions.Get(WatchBackendExtension, &backend); err != nil || !ok {
+		return defaultBackend, err
+	}
+	return watch.ParseBackend(backend)
+}
+
+// newWatcher creates a watcher for each backend selected by watch rules, and merges their events
+func newWatcher(paths map[watch.Backend][]string) (watch.Notify, error) {
+	var watchers []watch.Notify
+	for _, backend := range slices.Sorted(maps.Keys(paths)) {
+		w, err := watch.NewWatcherWithBackend(paths[backend], backend)
+		if err != nil {
+			return nil, err
+		}
+		logrus.Debugf("watching %d path(s) using %s backend", len(paths[backend]), backend)
+		watchers = append(watchers, w)
+	}
+	return watch.NewCompositeNotify(watchers...), nil
+}
+
 func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]watchRule, error) {
 	var rules []watchRule
 
@@ -325,8 +377,14 @@ func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]
 			}
 		}
 
+		signal, err := getRestartSignal(trigger)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", service.Name, err)
+		}
+
 		rules = append(rules, watchRule{
 			Trigger: trigger,
+			signal:  signal,
 			include: include,
 			ignore: watch.NewCompositeMatcher(
 				dockerIgnores,
@@ -340,6 +398,26 @@ func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]
 	return rules, nil
 }
 
+// WatchRestartExtension selects, on a restart or sync+restart watch rule, how the service is restarted.
+// `signal:SIGHUP` sends a signal to the container main process instead of restarting container, so
+// servers supporting configuration hot-reload don't have to be restarted.
+const WatchRestartExtension = "x-restart"
+
+func getRestartSignal(trigger types.Trigger) (string, error) {
+	var restart string
+	if ok, err := trigger.Extensions.Get(WatchRestartExtension, &restart); err != nil || !ok {
+		return "", err
+	}
+	if trigger.Action != types.WatchActionRestart && trigger.Action != types.WatchActionSyncRestart {
+		return "", fmt.Errorf("%s can only be set on %s or %s watch rules", WatchRestartExtension, types.WatchActionRestart, types.WatchActionSyncRestart)
+	}
+	signal, ok := strings.CutPrefix(restart, "signal:")
+	if !ok || signal == "" {
+		return "", fmt.Errorf("invalid %s %q, expected `signal:<SIGNAL>`", WatchRestartExtension, restart)
+	}
+	return signal, nil
+}
+
 func isSync(trigger types.Trigger) bool {
 	return trigger.Action == types.WatchActionSync || trigger.Action == types.WatchActionSyncRestart
 }
@@ -357,6 +435,10 @@ func (s *composeService) watchEvents(ctx context.Context, project *types.Project
 			options.LogTo.Log(api.WatchLogger, "Watch disabled")
 			return nil
 		case err, open := <-watcher.Errors():
+			if errors.Is(err, watch.ErrEventsDropped) {
+				logrus.Warnf("%v, some changes may have been missed. Consider setting %s: polling or watchman on watch rules for large source trees", err, WatchBackendExtension)
+				continue
+			}
 			if err != nil {
 				options.LogTo.Err(api.WatchLogger, "Watch disabled with errors: "+err.Error())
 			}
@@ -364,7 +446,12 @@ func (s *composeService) watchEvents(ctx context.Context, project *types.Project
 				continue
 			}
 			return err
-		case batch := <-batchEvents:
+		case batch, open := <-batchEvents:
+			if !open {
+				// watcher has been closed, wait for errors channel to report termination
+				batchEvents = nil
+				continue
+			}
 			start := time.Now()
 			logrus.Debugf("batch start: count[%d]", len(batch))
 			err := s.handleWatchBatch(ctx, project, options, batch, rules, syncer)
@@ -511,6 +598,7 @@ func (t tarDockerClient) Untar(ctx context.Context, id string, archive io.ReadCl
 func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Project, options api.WatchOptions, batch []watch.FileEvent, rules []watchRule, syncer sync.Syncer) error {
 	var (
 		restart   = map[string]bool{}
+		signals   = map[string]string{}
 		syncfiles = map[string][]*sync.PathMapping{}
 		exec      = map[string][]int{}
 		rebuild   = map[string]bool{}
@@ -528,10 +616,10 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 			case types.WatchActionSync:
 				syncfiles[rule.service] = append(syncfiles[rule.service], mapping)
 			case types.WatchActionRestart:
-				restart[rule.service] = true
+				restartOrSignal(restart, signals, rule)
 			case types.WatchActionSyncRestart:
 				syncfiles[rule.service] = append(syncfiles[rule.service], mapping)
-				restart[rule.service] = true
+				restartOrSignal(restart, signals, rule)
 			case types.WatchActionSyncExec:
 				syncfiles[rule.service] = append(syncfiles[rule.service], mapping)
 				// We want to run exec hooks only once after syncfiles if multiple file events match
@@ -541,7 +629,12 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 		}
 	}
 
-	logrus.Debugf("watch actions: rebuild %d sync %d restart %d", len(rebuild), len(syncfiles), len(restart))
+	for service := range restart {
+		// restart has precedence over signal when both are triggered by distinct rules
+		delete(signals, service)
+	}
+
+	logrus.Debugf("watch actions: rebuild %d sync %d restart %d signal %d", len(rebuild), len(syncfiles), len(restart), len(signals))
 
 	if len(rebuild) > 0 {
 		err := s.rebuild(ctx, project, utils.MapKeys(rebuild), options)
@@ -571,6 +664,13 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 			api.WatchLogger,
 			fmt.Sprintf("service(s) %q restarted", services))
 	}
+	for service, signal := range signals {
+		err := s.signalService(ctx, project.Name, service, signal)
+		if err != nil {
+			return err
+		}
+		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("service %q sent %s", service, signal))
+	}
 
 	eg, ctx := errgroup.WithContext(ctx)
 	for service, rulesToExec := range exec {
@@ -585,6 +685,28 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 	return eg.Wait()
 }
 
+func restartOrSignal(restart map[string]bool, signals map[string]string, rule watchRule) {
+	if rule.signal != "" {
+		signals[rule.service] = rule.signal
+		return
+	}
+	restart[rule.service] = true
+}
+
+// signalService sends signal to the main process of running service containers
+func (s *composeService) signalService(ctx context.Context, projectName string, serviceName string, signal string) error {
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, false, serviceName)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if err := s.apiClient().ContainerKill(ctx, c.ID, signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *composeService) exec(ctx context.Context, project *types.Project, serviceName string, x types.ServiceHook, eg *errgroup.Group) error {
 	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, false, serviceName)
 	if err != nil {
@@ -643,6 +765,8 @@ func (s *composeService) rebuild(ctx context.Context, project *types.Project, se
 		Services: services,
 		Inherit:  true,
 		Recreate: api.RecreateForce,
+		// bind mounts have already been checked when project was started
+		SkipBindChecks: true,
 	})
 	if err != nil {
 		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Failed to recreate services after update. Error: %v", err))
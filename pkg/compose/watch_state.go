@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// driftCheckDelay lets a burst of engine events settle before drift is checked
+const driftCheckDelay = 500 * time.Millisecond
+
+func (s *composeService) WatchState(ctx context.Context, project *types.Project, options api.WatchStateOptions) error {
+	var previous *api.DriftReport
+	check := func() error {
+		report, err := s.driftReport(ctx, project)
+		if err != nil {
+			return err
+		}
+		if previous != nil && slices.Equal(previous.Drifts, report.Drifts) {
+			return nil
+		}
+		previous = &report
+		return options.Consumer(report)
+	}
+	if err := check(); err != nil || options.Once {
+		return err
+	}
+
+	evts, errs := s.apiClient().Events(ctx, events.ListOptions{
+		Filters: filters.NewArgs(projectFilter(project.Name)),
+	})
+	var tick <-chan time.Time
+	if options.Interval > 0 {
+		ticker := time.NewTicker(options.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	delay := time.NewTimer(driftCheckDelay)
+	delay.Stop()
+	defer delay.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-evts:
+			delay.Reset(driftCheckDelay)
+		case <-delay.C:
+			if err := check(); err != nil {
+				return err
+			}
+		case <-tick:
+			if err := check(); err != nil {
+				return err
+			}
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// driftReport checks differences between actual resources and the project model
+func (s *composeService) driftReport(ctx context.Context, project *types.Project) (api.DriftReport, error) {
+	changes, err := s.planApply(ctx, project, true)
+	if err != nil {
+		return api.DriftReport{}, err
+	}
+	return api.DriftReport{
+		Project:   project.Name,
+		Timestamp: time.Now(),
+		Drifts:    toDrifts(changes),
+	}, nil
+}
+
+// toDrifts converts changes required to converge a project into the drifts they fix
+func toDrifts(changes []api.ApplyChange) []api.Drift {
+	drifts := []api.Drift{}
+	for _, c := range changes {
+		drift := api.Drift{Resource: c.Resource, Name: c.Name, Details: c.Reason}
+		switch c.Action {
+		case api.ApplyCreate:
+			drift.Kind = api.DriftMissing
+		case api.ApplyRecreate:
+			drift.Kind = api.DriftChanged
+		case api.ApplyStart:
+			drift.Kind = api.DriftStopped
+		case api.ApplyRemove:
+			drift.Kind = api.DriftUnexpected
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestToDrifts(t *testing.T) {
+	assert.DeepEqual(t, toDrifts(nil), []api.Drift{})
+
+	drifts := toDrifts([]api.ApplyChange{
+		{Action: api.ApplyCreate, Resource: "volume", Name: "myproject_data"},
+		{Action: api.ApplyRecreate, Resource: "container", Name: "myproject-web-1", Reason: "environment"},
+		{Action: api.ApplyStart, Resource: "container", Name: "myproject-db-1"},
+		{Action: api.ApplyRemove, Resource: "network", Name: "myproject_old"},
+	})
+	assert.DeepEqual(t, drifts, []api.Drift{
+		{Kind: api.DriftMissing, Resource: "volume", Name: "myproject_data"},
+		{Kind: api.DriftChanged, Resource: "container", Name: "myproject-web-1", Details: "environment"},
+		{Kind: api.DriftStopped, Resource: "container", Name: "myproject-db-1"},
+		{Kind: api.DriftUnexpected, Resource: "network", Name: "myproject_old"},
+	})
+}
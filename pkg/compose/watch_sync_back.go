@@ -0,0 +1,295 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+const (
+	// SyncBackExtension enables, on a sync watch rule, copying files modified inside container back to the host
+	SyncBackExtension = "x-sync_back"
+	// SyncBackConflictExtension selects which side wins when a file has been modified both on host and inside container
+	SyncBackConflictExtension = "x-sync_back_conflict"
+	// SyncBackConflictHost keeps the host file, container change being ignored
+	SyncBackConflictHost = "host"
+	// SyncBackConflictContainer overwrites the host file with container content
+	SyncBackConflictContainer = "container"
+)
+
+// syncBackInterval is the delay between two scans of the container files to be synced back
+var syncBackInterval = 2 * time.Second
+
+// syncBackRule is a watch rule with files to be synced back from container
+type syncBackRule struct {
+	watchRule
+	conflict string
+}
+
+// getSyncBack returns the conflict policy when trigger enables SyncBackExtension, or an empty string
+func getSyncBack(trigger types.Trigger) (string, error) {
+	var enabled bool
+	if ok, err := trigger.Extensions.Get(SyncBackExtension, &enabled); err != nil || !ok || !enabled {
+		return "", err
+	}
+	if !isSync(trigger) || trigger.Target == "" {
+		return "", fmt.Errorf("%s can only be set on %s or %s watch rules with a target", SyncBackExtension, types.WatchActionSync, types.WatchActionSyncRestart)
+	}
+	conflict := SyncBackConflictHost
+	if _, err := trigger.Extensions.Get(SyncBackConflictExtension, &conflict); err != nil {
+		return "", err
+	}
+	switch conflict {
+	case SyncBackConflictHost, SyncBackConflictContainer:
+		return conflict, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q, expected %s or %s", SyncBackConflictExtension, conflict, SyncBackConflictHost, SyncBackConflictContainer)
+	}
+}
+
+// syncBack periodically copies files modified inside the service container back to the host.
+// Files removed inside container are not removed from the host.
+// Container files are listed with their size and modification time, so only the modified ones are copied.
+func (s *composeService) syncBack(ctx context.Context, project *types.Project, rule syncBackRule, options api.WatchOptions) error {
+	ticker := s.clock.NewTicker(syncBackInterval)
+	defer ticker.Stop()
+
+	// baseline stores size and modification time of container files as of last scan, so we only sync back files
+	// modified since
+	var (
+		baseline map[string]syncBackEntry
+		lastScan time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.Chan():
+			ctr, err := s.syncBackContainer(ctx, project.Name, rule)
+			if err != nil {
+				logrus.Debugf("sync back %s from service %s: %v", rule.Target, rule.service, err)
+				continue
+			}
+			scan := time.Now()
+			entries, err := s.listSyncBackFiles(ctx, ctr, rule)
+			if err != nil {
+				var exitErr syncBackListingError
+				if errors.As(err, &exitErr) {
+					options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Sync back from service %q disabled: %v", rule.service, err))
+					return nil
+				}
+				logrus.Debugf("sync back %s from service %s: %v", rule.Target, rule.service, err)
+				continue
+			}
+			if baseline != nil {
+				files := map[string]syncBackFile{}
+				for _, hostPath := range changedSyncBackFiles(entries, baseline) {
+					f, err := s.readSyncBackFile(ctx, ctr, entries[hostPath].containerPath)
+					if err != nil {
+						logrus.Debugf("sync back %s from service %s: %v", hostPath, rule.service, err)
+						continue
+					}
+					files[hostPath] = f
+				}
+				s.applySyncBack(rule, files, lastScan, options)
+			}
+			baseline = entries
+			lastScan = scan
+		}
+	}
+}
+
+// syncBackEntry is a file listed inside container, with a signature changed by any modification
+type syncBackEntry struct {
+	containerPath string
+	signature     string
+}
+
+type syncBackFile struct {
+	content []byte
+	mode    os.FileMode
+	hash    string
+}
+
+// syncBackListingError reports container lacks the tools required to list files
+type syncBackListingError struct {
+	error
+}
+
+// changedSyncBackFiles returns host paths of the files created or modified since baseline
+func changedSyncBackFiles(entries, baseline map[string]syncBackEntry) []string {
+	var changed []string
+	for hostPath, e := range entries {
+		if previous, ok := baseline[hostPath]; !ok || previous.signature != e.signature {
+			changed = append(changed, hostPath)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// applySyncBack writes files modified inside container to the host. A host file modified since the last scan is
+// a conflict, resolved according to rule policy.
+func (s *composeService) applySyncBack(rule syncBackRule, files map[string]syncBackFile, lastScan time.Time, options api.WatchOptions) {
+	for _, hostPath := range slices.Sorted(maps.Keys(files)) {
+		f := files[hostPath]
+		hostContent, err := os.ReadFile(hostPath)
+		if err == nil && contentHash(hostContent) == f.hash {
+			continue
+		}
+		rel, _ := filepath.Rel(rule.Path, hostPath)
+		if info, err := os.Stat(hostPath); err == nil && info.ModTime().After(lastScan) {
+			// host file has also been modified since last scan
+			if rule.conflict == SyncBackConflictHost {
+				options.LogTo.Err(api.WatchLogger, fmt.Sprintf("%s modified both on host and in service %q, keeping host version", rel, rule.service))
+				continue
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(hostPath), 0o755); err != nil {
+			logrus.Warnf("sync back %s: %v", hostPath, err)
+			continue
+		}
+		if err := os.WriteFile(hostPath, f.content, f.mode); err != nil {
+			logrus.Warnf("sync back %s: %v", hostPath, err)
+			continue
+		}
+		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Synced back %s from service %q", rel, rule.service))
+	}
+}
+
+func (s *composeService) syncBackContainer(ctx context.Context, projectName string, rule syncBackRule) (string, error) {
+	containers, err := s.getContainers(ctx, projectName, oneOffExclude, false, rule.service)
+	if err != nil {
+		return "", err
+	}
+	if len(containers) == 0 {
+		return "", errors.New("no running container")
+	}
+	return containers.sorted()[0].ID, nil
+}
+
+// listSyncBackFiles lists files from rule target inside the service container, indexed by host path.
+// Relies on `find` and `stat`, available in most images, including busybox based ones.
+func (s *composeService) listSyncBackFiles(ctx context.Context, containerID string, rule syncBackRule) (map[string]syncBackEntry, error) {
+	exec, err := s.apiClient().ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          []string{"find", rule.Target, "-type", "f", "-exec", "stat", "-c", "%s|%Y|%n", "{}", "+"},
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	attach, err := s.apiClient().ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer attach.Close()
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+		return nil, err
+	}
+	inspected, err := s.apiClient().ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch inspected.ExitCode {
+	case 0:
+	case 126, 127:
+		return nil, syncBackListingError{fmt.Errorf("container requires find and stat commands: %s", strings.TrimSpace(stderr.String()))}
+	default:
+		return nil, fmt.Errorf("listing files failed with exit code %d: %s", inspected.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return parseSyncBackListing(stdout.String(), rule), nil
+}
+
+// parseSyncBackListing parses `size|mtime|path` lines, ignoring files not matched by rule
+func parseSyncBackListing(output string, rule syncBackRule) map[string]syncBackEntry {
+	entries := map[string]syncBackEntry{}
+	for _, line := range strings.Split(output, "\n") {
+		size, rest, ok := strings.Cut(line, "|")
+		if !ok {
+			continue
+		}
+		mtime, containerPath, ok := strings.Cut(rest, "|")
+		if !ok {
+			continue
+		}
+		rel, ok := strings.CutPrefix(containerPath, strings.TrimSuffix(rule.Target, "/")+"/")
+		if !ok {
+			continue
+		}
+		hostPath := filepath.Join(rule.Path, filepath.FromSlash(rel))
+		if rule.Matches(watch.NewFileEvent(hostPath)) == nil {
+			continue
+		}
+		entries[hostPath] = syncBackEntry{containerPath: containerPath, signature: size + "|" + mtime}
+	}
+	return entries
+}
+
+// readSyncBackFile copies a single file from the service container
+func (s *composeService) readSyncBackFile(ctx context.Context, containerID string, containerPath string) (syncBackFile, error) {
+	content, _, err := s.apiClient().CopyFromContainer(ctx, containerID, containerPath)
+	if err != nil {
+		return syncBackFile{}, err
+	}
+	defer content.Close() //nolint:errcheck
+
+	reader := tar.NewReader(content)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			return syncBackFile{}, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(reader)
+		if err != nil {
+			return syncBackFile{}, err
+		}
+		return syncBackFile{
+			content: b,
+			mode:    header.FileInfo().Mode().Perm(),
+			hash:    contentHash(b),
+		}, nil
+	}
+}
+
+func contentHash(b []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
@@ -0,0 +1,136 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+func TestGetSyncBack(t *testing.T) {
+	conflict, err := getSyncBack(types.Trigger{Action: types.WatchActionSync, Target: "/app"})
+	assert.NilError(t, err)
+	assert.Equal(t, conflict, "")
+
+	conflict, err = getSyncBack(types.Trigger{
+		Action:     types.WatchActionSync,
+		Target:     "/app",
+		Extensions: types.Extensions{SyncBackExtension: true},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, conflict, SyncBackConflictHost)
+
+	conflict, err = getSyncBack(types.Trigger{
+		Action:     types.WatchActionSync,
+		Target:     "/app",
+		Extensions: types.Extensions{SyncBackExtension: true, SyncBackConflictExtension: "container"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, conflict, SyncBackConflictContainer)
+
+	_, err = getSyncBack(types.Trigger{
+		Action:     types.WatchActionRebuild,
+		Extensions: types.Extensions{SyncBackExtension: true},
+	})
+	assert.ErrorContains(t, err, "x-sync_back can only be set on sync or sync+restart watch rules")
+
+	_, err = getSyncBack(types.Trigger{
+		Action:     types.WatchActionSync,
+		Target:     "/app",
+		Extensions: types.Extensions{SyncBackExtension: true, SyncBackConflictExtension: "newest"},
+	})
+	assert.ErrorContains(t, err, `invalid x-sync_back_conflict "newest"`)
+}
+
+func TestApplySyncBack(t *testing.T) {
+	dir := t.TempDir()
+	unchanged := filepath.Join(dir, "unchanged.sql")
+	generated := filepath.Join(dir, "generated.sql")
+	conflicting := filepath.Join(dir, "conflicting.sql")
+	lastScan := time.Now().Add(-time.Minute)
+	assert.NilError(t, os.WriteFile(unchanged, []byte("v1"), 0o644))
+	assert.NilError(t, os.WriteFile(conflicting, []byte("host"), 0o644))
+	assert.NilError(t, os.Chtimes(unchanged, lastScan.Add(-time.Minute), lastScan.Add(-time.Minute)))
+
+	file := func(content string) syncBackFile {
+		return syncBackFile{content: []byte(content), mode: 0o644, hash: contentHash([]byte(content))}
+	}
+	rule := syncBackRule{
+		watchRule: watchRule{
+			Trigger: types.Trigger{Path: dir, Target: "/app"},
+			include: watch.AnyMatcher{},
+			ignore:  watch.EmptyMatcher{},
+			service: "app",
+		},
+		conflict: SyncBackConflictHost,
+	}
+	files := map[string]syncBackFile{
+		unchanged:   file("v1"),
+		generated:   file("generated"),
+		conflicting: file("container"),
+	}
+	s := &composeService{}
+	s.applySyncBack(rule, files, lastScan, api.WatchOptions{LogTo: stdLogger{}})
+
+	b, err := os.ReadFile(generated)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "generated")
+	b, err = os.ReadFile(conflicting)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "host")
+
+	rule.conflict = SyncBackConflictContainer
+	s.applySyncBack(rule, files, lastScan, api.WatchOptions{LogTo: stdLogger{}})
+	b, err = os.ReadFile(conflicting)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "container")
+}
+
+func TestSyncBackListing(t *testing.T) {
+	rule := syncBackRule{
+		watchRule: watchRule{
+			Trigger: types.Trigger{Path: "/src", Target: "/app/"},
+			include: watch.AnyMatcher{},
+			ignore:  watch.EmptyMatcher{},
+			service: "app",
+		},
+	}
+	entries := parseSyncBackListing("12|1700000000|/app/schema.sql\n3|1700000001|/app/gen/a b.txt\n\n", rule)
+	assert.DeepEqual(t, entries, map[string]syncBackEntry{
+		filepath.Join("/src", "schema.sql"):     {containerPath: "/app/schema.sql", signature: "12|1700000000"},
+		filepath.Join("/src", "gen", "a b.txt"): {containerPath: "/app/gen/a b.txt", signature: "3|1700000001"},
+	}, cmp.AllowUnexported(syncBackEntry{}))
+
+	baseline := map[string]syncBackEntry{
+		filepath.Join("/src", "schema.sql"): {containerPath: "/app/schema.sql", signature: "12|1700000000"},
+	}
+	assert.DeepEqual(t, changedSyncBackFiles(entries, baseline), []string{filepath.Join("/src", "gen", "a b.txt")})
+	entries[filepath.Join("/src", "schema.sql")] = syncBackEntry{containerPath: "/app/schema.sql", signature: "14|1700000005"}
+	assert.DeepEqual(t, changedSyncBackFiles(entries, baseline), []string{
+		filepath.Join("/src", "gen", "a b.txt"),
+		filepath.Join("/src", "schema.sql"),
+	})
+}
@@ -185,3 +185,73 @@ func (f *fakeSyncer) Sync(ctx context.Context, service string, paths []*sync.Pat
 	f.synced <- paths
 	return nil
 }
+
+func TestGetWatchBackend(t *testing.T) {
+	backend, err := getWatchBackend(types.Trigger{}, watch.BackendAuto)
+	assert.NilError(t, err)
+	assert.Equal(t, backend, watch.BackendAuto)
+
+	backend, err = getWatchBackend(types.Trigger{
+		Extensions: types.Extensions{WatchBackendExtension: "polling"},
+	}, watch.BackendAuto)
+	assert.NilError(t, err)
+	assert.Equal(t, backend, watch.BackendPolling)
+
+	_, err = getWatchBackend(types.Trigger{
+		Extensions: types.Extensions{WatchBackendExtension: "unknown"},
+	}, watch.BackendAuto)
+	assert.ErrorContains(t, err, `unsupported watch backend "unknown"`)
+}
+
+func TestGetRestartSignal(t *testing.T) {
+	signal, err := getRestartSignal(types.Trigger{Action: types.WatchActionRestart})
+	assert.NilError(t, err)
+	assert.Equal(t, signal, "")
+
+	signal, err = getRestartSignal(types.Trigger{
+		Action:     types.WatchActionSyncRestart,
+		Extensions: types.Extensions{WatchRestartExtension: "signal:SIGHUP"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, signal, "SIGHUP")
+
+	_, err = getRestartSignal(types.Trigger{
+		Action:     types.WatchActionSync,
+		Extensions: types.Extensions{WatchRestartExtension: "signal:SIGHUP"},
+	})
+	assert.ErrorContains(t, err, "x-restart can only be set on restart or sync+restart watch rules")
+
+	_, err = getRestartSignal(types.Trigger{
+		Action:     types.WatchActionRestart,
+		Extensions: types.Extensions{WatchRestartExtension: "SIGHUP"},
+	})
+	assert.ErrorContains(t, err, `invalid x-restart "SIGHUP"`)
+}
+
+func TestWatch_RestartSignal(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	cli := mocks.NewMockCli(mockCtrl)
+	apiClient := mocks.NewMockAPIClient(mockCtrl)
+	cli.EXPECT().Client().Return(apiClient).AnyTimes()
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return([]container.Summary{
+		testContainer("test", "123", false),
+	}, nil)
+	apiClient.EXPECT().ContainerKill(gomock.Any(), "123", "SIGHUP").Return(nil)
+
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{
+			{
+				Path:       "/config",
+				Action:     types.WatchActionRestart,
+				Extensions: types.Extensions{WatchRestartExtension: "signal:SIGHUP"},
+			},
+		},
+	}, types.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+
+	service := composeService{dockerCli: cli}
+	err = service.handleWatchBatch(context.Background(), &types.Project{Name: "myProjectName"}, api.WatchOptions{
+		LogTo: stdLogger{},
+	}, []watch.FileEvent{watch.NewFileEvent("/config/nginx.conf")}, rules, newFakeSyncer())
+	assert.NilError(t, err)
+}
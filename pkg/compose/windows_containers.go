@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/sirupsen/logrus"
+)
+
+// linuxOnlyOption is a container setting the engine rejects when running Windows containers
+type linuxOnlyOption struct {
+	name  string
+	isSet func(*container.HostConfig) bool
+	reset func(*container.HostConfig)
+}
+
+var linuxOnlyOptions = []linuxOnlyOption{
+	{
+		name:  "cap_add",
+		isSet: func(h *container.HostConfig) bool { return len(h.CapAdd) > 0 },
+		reset: func(h *container.HostConfig) { h.CapAdd = nil },
+	},
+	{
+		name:  "cap_drop",
+		isSet: func(h *container.HostConfig) bool { return len(h.CapDrop) > 0 },
+		reset: func(h *container.HostConfig) { h.CapDrop = nil },
+	},
+	{
+		name:  "sysctls",
+		isSet: func(h *container.HostConfig) bool { return len(h.Sysctls) > 0 },
+		reset: func(h *container.HostConfig) { h.Sysctls = nil },
+	},
+	{
+		name:  "security_opt",
+		isSet: func(h *container.HostConfig) bool { return len(h.SecurityOpt) > 0 || h.MaskedPaths != nil },
+		reset: func(h *container.HostConfig) {
+			h.SecurityOpt = nil
+			h.MaskedPaths = nil
+			h.ReadonlyPaths = nil
+		},
+	},
+	{
+		name:  "tmpfs",
+		isSet: func(h *container.HostConfig) bool { return len(h.Tmpfs) > 0 },
+		reset: func(h *container.HostConfig) { h.Tmpfs = nil },
+	},
+	{
+		name:  "oom_score_adj",
+		isSet: func(h *container.HostConfig) bool { return h.OomScoreAdj != 0 },
+		reset: func(h *container.HostConfig) { h.OomScoreAdj = 0 },
+	},
+	{
+		name:  "group_add",
+		isSet: func(h *container.HostConfig) bool { return len(h.GroupAdd) > 0 },
+		reset: func(h *container.HostConfig) { h.GroupAdd = nil },
+	},
+	{
+		name:  "userns_mode",
+		isSet: func(h *container.HostConfig) bool { return h.UsernsMode != "" },
+		reset: func(h *container.HostConfig) { h.UsernsMode = "" },
+	},
+	{
+		name:  "cgroup",
+		isSet: func(h *container.HostConfig) bool { return h.CgroupnsMode != "" },
+		reset: func(h *container.HostConfig) { h.CgroupnsMode = "" },
+	},
+}
+
+// adaptToRuntimeOS validates service configuration against the OS of the containers run by the engine,
+// and drops Linux-only settings with a warning when targeting Windows containers
+func (s *composeService) adaptToRuntimeOS(ctx context.Context, service types.ServiceConfig, hostConfig *container.HostConfig) error {
+	osType, err := s.runtimeOS(ctx)
+	if err != nil {
+		return err
+	}
+	if osType == "" {
+		// engine didn't report its OS, let it validate the configuration
+		return nil
+	}
+	if err := checkIsolation(service, osType); err != nil {
+		return err
+	}
+	if err := checkNamedPipes(service, osType); err != nil {
+		return err
+	}
+	if osType == "windows" {
+		dropLinuxOnlyOptions(service.Name, hostConfig)
+	}
+	return nil
+}
+
+func checkIsolation(service types.ServiceConfig, osType string) error {
+	isolation := container.Isolation(service.Isolation)
+	if isolation.IsDefault() {
+		return nil
+	}
+	if osType != "windows" {
+		return fmt.Errorf("service %q: isolation %q is only supported by Windows containers, engine runs %s containers", service.Name, service.Isolation, osType)
+	}
+	if !isolation.IsProcess() && !isolation.IsHyperV() {
+		return fmt.Errorf("service %q: invalid isolation %q, must be one of default, process or hyperv", service.Name, service.Isolation)
+	}
+	return nil
+}
+
+func checkNamedPipes(service types.ServiceConfig, osType string) error {
+	if osType == "windows" {
+		return nil
+	}
+	for _, v := range service.Volumes {
+		if v.Type == types.VolumeTypeNamedPipe || (v.Type == types.VolumeTypeBind && isNamedPipe(v.Source)) {
+			return fmt.Errorf("service %q: named pipe mount %s is only supported by Windows containers, engine runs %s containers", service.Name, v.Source, osType)
+		}
+	}
+	return nil
+}
+
+func dropLinuxOnlyOptions(service string, hostConfig *container.HostConfig) {
+	for _, o := range linuxOnlyOptions {
+		if o.isSet(hostConfig) {
+			logrus.Warnf("service %q: %s is not supported by Windows containers and will be ignored", service, o.name)
+			o.reset(hostConfig)
+		}
+	}
+}
+
+// isNamedPipe checks path is a Windows named pipe, like `\\.\pipe\docker_engine`
+func isNamedPipe(path string) bool {
+	p := strings.ToLower(strings.ReplaceAll(path, "/", `\`))
+	return strings.HasPrefix(p, `\\.\pipe\`)
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckIsolation(t *testing.T) {
+	tests := []struct {
+		isolation string
+		osType    string
+		err       string
+	}{
+		{isolation: "", osType: "linux"},
+		{isolation: "default", osType: "linux"},
+		{isolation: "process", osType: "windows"},
+		{isolation: "HyperV", osType: "windows"},
+		{isolation: "hyperv", osType: "linux", err: `service "test": isolation "hyperv" is only supported by Windows containers, engine runs linux containers`},
+		{isolation: "sandbox", osType: "windows", err: `service "test": invalid isolation "sandbox", must be one of default, process or hyperv`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.isolation+"/"+tt.osType, func(t *testing.T) {
+			err := checkIsolation(types.ServiceConfig{Name: "test", Isolation: tt.isolation}, tt.osType)
+			if tt.err == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckNamedPipes(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "test",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: types.VolumeTypeBind, Source: `\\.\pipe\docker_engine`, Target: `\\.\pipe\docker_engine`},
+		},
+	}
+	assert.NilError(t, checkNamedPipes(service, "windows"))
+	assert.Error(t, checkNamedPipes(service, "linux"),
+		`service "test": named pipe mount \\.\pipe\docker_engine is only supported by Windows containers, engine runs linux containers`)
+
+	service.Volumes = []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeBind, Source: `C:\data`, Target: `C:\data`},
+	}
+	assert.NilError(t, checkNamedPipes(service, "linux"))
+}
+
+func TestDropLinuxOnlyOptions(t *testing.T) {
+	hostConfig := container.HostConfig{
+		CapAdd:        []string{"NET_ADMIN"},
+		Sysctls:       map[string]string{"net.core.somaxconn": "1024"},
+		MaskedPaths:   []string{},
+		ReadonlyPaths: []string{},
+		Isolation:     container.IsolationProcess,
+	}
+	dropLinuxOnlyOptions("test", &hostConfig)
+	assert.DeepEqual(t, hostConfig, container.HostConfig{Isolation: container.IsolationProcess})
+}
+
+func TestBuildMountNamedPipe(t *testing.T) {
+	m, err := buildMount(types.Project{}, types.ServiceVolumeConfig{
+		Type:   types.VolumeTypeBind,
+		Source: `\\.\pipe\docker_engine`,
+		Target: `\\.\pipe\docker_engine`,
+		Bind:   &types.ServiceVolumeBind{CreateHostPath: true},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m, mount.Mount{
+		Type:   mount.TypeNamedPipe,
+		Source: `\\.\pipe\docker_engine`,
+		Target: `\\.\pipe\docker_engine`,
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"net"
 	"net/http"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 
@@ -58,6 +59,17 @@ type DryRunClient struct {
 	containers []containerType.Summary
 	execs      sync.Map
 	resolver   *imagetools.Resolver
+	mu         sync.Mutex
+	removals   []Removal
+}
+
+// Removal is a resource DryRunClient has been requested to remove
+type Removal struct {
+	Type events.Type
+	// ID is the resource ID or name, as passed to the API
+	ID string
+	// Volumes is set when anonymous volumes attached to a container are removed with it
+	Volumes bool
 }
 
 type execDetails struct {
@@ -83,6 +95,19 @@ func NewDryRunClient(apiClient client.APIClient, cli command.Cli) (*DryRunClient
 	}, nil
 }
 
+// Removals lists resources which would have been removed, in order removal has been requested
+func (d *DryRunClient) Removals() []Removal {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.removals)
+}
+
+func (d *DryRunClient) recordRemoval(r Removal) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.removals = append(d.removals, r)
+}
+
 func getCallingFunction() string {
 	pc, _, _, _ := runtime.Caller(2)
 	fullName := runtime.FuncForPC(pc).Name()
@@ -162,6 +187,7 @@ func (d *DryRunClient) ContainerPause(ctx context.Context, container string) err
 }
 
 func (d *DryRunClient) ContainerRemove(ctx context.Context, container string, options containerType.RemoveOptions) error {
+	d.recordRemoval(Removal{Type: events.ContainerEventType, ID: container, Volumes: options.RemoveVolumes})
 	return nil
 }
 
@@ -256,6 +282,7 @@ func (d *DryRunClient) ImagePush(ctx context.Context, ref string, options image.
 }
 
 func (d *DryRunClient) ImageRemove(ctx context.Context, imageName string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	d.recordRemoval(Removal{Type: events.ImageEventType, ID: imageName})
 	return nil, nil
 }
 
@@ -275,6 +302,7 @@ func (d *DryRunClient) NetworkDisconnect(ctx context.Context, networkName, conta
 }
 
 func (d *DryRunClient) NetworkRemove(ctx context.Context, networkName string) error {
+	d.recordRemoval(Removal{Type: events.NetworkEventType, ID: networkName})
 	return nil
 }
 
@@ -289,6 +317,7 @@ func (d *DryRunClient) VolumeCreate(ctx context.Context, options volume.CreateOp
 }
 
 func (d *DryRunClient) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	d.recordRemoval(Removal{Type: events.VolumeEventType, ID: volumeID})
 	return nil
 }
 
@@ -320,7 +349,7 @@ func (d *DryRunClient) ConfigList(ctx context.Context, options swarm.ConfigListO
 }
 
 func (d *DryRunClient) ConfigCreate(ctx context.Context, config swarm.ConfigSpec) (swarm.ConfigCreateResponse, error) {
-	return d.apiClient.ConfigCreate(ctx, config)
+	return swarm.ConfigCreateResponse{ID: config.Name}, nil
 }
 
 func (d *DryRunClient) ConfigRemove(ctx context.Context, id string) error {
@@ -332,7 +361,7 @@ func (d *DryRunClient) ConfigInspectWithRaw(ctx context.Context, name string) (s
 }
 
 func (d *DryRunClient) ConfigUpdate(ctx context.Context, id string, version swarm.Version, config swarm.ConfigSpec) error {
-	return d.apiClient.ConfigUpdate(ctx, id, version, config)
+	return nil
 }
 
 func (d *DryRunClient) ContainerCommit(ctx context.Context, container string, options containerType.CommitOptions) (containerType.CommitResponse, error) {
@@ -520,7 +549,7 @@ func (d *DryRunClient) PluginCreate(ctx context.Context, createContext io.Reader
 }
 
 func (d *DryRunClient) ServiceCreate(ctx context.Context, service swarm.ServiceSpec, options swarm.ServiceCreateOptions) (swarm.ServiceCreateResponse, error) {
-	return d.apiClient.ServiceCreate(ctx, service, options)
+	return swarm.ServiceCreateResponse{ID: service.Name}, nil
 }
 
 func (d *DryRunClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options swarm.ServiceInspectOptions) (swarm.Service, []byte, error) {
@@ -532,11 +561,11 @@ func (d *DryRunClient) ServiceList(ctx context.Context, options swarm.ServiceLis
 }
 
 func (d *DryRunClient) ServiceRemove(ctx context.Context, serviceID string) error {
-	return d.apiClient.ServiceRemove(ctx, serviceID)
+	return nil
 }
 
 func (d *DryRunClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options swarm.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
-	return d.apiClient.ServiceUpdate(ctx, serviceID, version, service, options)
+	return swarm.ServiceUpdateResponse{}, nil
 }
 
 func (d *DryRunClient) ServiceLogs(ctx context.Context, serviceID string, options containerType.LogsOptions) (io.ReadCloser, error) {
@@ -588,7 +617,7 @@ func (d *DryRunClient) SecretList(ctx context.Context, options swarm.SecretListO
 }
 
 func (d *DryRunClient) SecretCreate(ctx context.Context, secret swarm.SecretSpec) (swarm.SecretCreateResponse, error) {
-	return d.apiClient.SecretCreate(ctx, secret)
+	return swarm.SecretCreateResponse{ID: secret.Name}, nil
 }
 
 func (d *DryRunClient) SecretRemove(ctx context.Context, id string) error {
@@ -600,7 +629,7 @@ func (d *DryRunClient) SecretInspectWithRaw(ctx context.Context, name string) (s
 }
 
 func (d *DryRunClient) SecretUpdate(ctx context.Context, id string, version swarm.Version, secret swarm.SecretSpec) error {
-	return d.apiClient.SecretUpdate(ctx, id, version, secret)
+	return nil
 }
 
 func (d *DryRunClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
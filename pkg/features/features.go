@@ -0,0 +1,326 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package features controls optional and experimental behaviors of Compose. A feature state is resolved, by
+// increasing precedence, from its default, the features configuration file, the project `x-features`
+// extension and the environment. Setting COMPOSE_EXPERIMENTAL to a falsy value opts out of all experimental features
+// which are not explicitly enabled.
+package features
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvVar sets features state as a comma-separated list of feature names, disabled when prefixed by `-`
+	EnvVar = "COMPOSE_FEATURES"
+	// FileEnvVar overrides the path of the features configuration file
+	FileEnvVar = "COMPOSE_FEATURES_FILE"
+	// ProjectExtension sets features state for a project, as a map of feature names to booleans
+	ProjectExtension = "x-features"
+	// experimentalEnvVar can be set to a falsy value (e.g. 0, false) to globally opt-out of experimental features,
+	// unless explicitly enabled
+	experimentalEnvVar = "COMPOSE_EXPERIMENTAL"
+)
+
+// Feature names
+const (
+	// Bake builds images with `docker buildx bake` when buildx is available
+	Bake = "bake"
+	// GitRemote allows compose files and includes to reference a git repository
+	GitRemote = "git-remote"
+	// OCIRemote allows compose files and includes to reference an OCI artifact
+	OCIRemote = "oci-remote"
+	// WatchTar syncs watched files into containers as tar archives
+	WatchTar = "watch-tar"
+)
+
+// Stage is the maturity of a feature
+type Stage string
+
+const (
+	// Experimental features can change or be removed without notice
+	Experimental Stage = "experimental"
+	// Stable features are supported, the flag allows to opt out
+	Stable Stage = "stable"
+	// Deprecated flags will be removed, setting them reports a warning
+	Deprecated Stage = "deprecated"
+)
+
+// Sources of a feature state
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceProject = "project"
+	SourceEnv     = "env"
+)
+
+// Flag declares a feature which can be enabled or disabled
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Stage       Stage  `json:"stage"`
+	// Since is the Compose version the feature reached its current stage
+	Since   string `json:"since"`
+	Default bool   `json:"default"`
+	// Project reports the feature can be set by the project ProjectExtension
+	Project bool `json:"project"`
+	// LegacyEnv is the environment variable formerly controlling the feature, still honored
+	LegacyEnv string `json:"legacyEnv,omitempty"`
+}
+
+// Flags are the features Compose supports, sorted by name
+var Flags = []Flag{
+	{
+		Name:        Bake,
+		Description: "Build images with buildx bake",
+		Stage:       Stable,
+		Since:       "v2.37.0",
+		Default:     true,
+		Project:     true,
+	},
+	{
+		Name:        GitRemote,
+		Description: "Load compose files and includes from git repositories",
+		Stage:       Stable,
+		Since:       "v2.20.0",
+		Default:     true,
+		LegacyEnv:   "COMPOSE_EXPERIMENTAL_GIT_REMOTE",
+	},
+	{
+		Name:        OCIRemote,
+		Description: "Load compose files and includes from OCI artifacts",
+		Stage:       Stable,
+		Since:       "v2.34.0",
+		Default:     true,
+		LegacyEnv:   "COMPOSE_EXPERIMENTAL_OCI_REMOTE",
+	},
+	{
+		Name:        WatchTar,
+		Description: "Sync watched files as tar archives, the only sync implementation",
+		Stage:       Deprecated,
+		Since:       "v5.0.0",
+		Default:     true,
+		Project:     true,
+		LegacyEnv:   "COMPOSE_EXPERIMENTAL_WATCH_TAR",
+	},
+}
+
+// Feature is the resolved state of a feature flag
+type Feature struct {
+	Flag
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+}
+
+// Set holds features state set by each source
+type Set struct {
+	experimental bool
+	file         map[string]bool
+	project      map[string]bool
+	env          map[string]bool
+}
+
+// Defaults returns a Set with all features in their default state
+func Defaults() *Set {
+	return &Set{experimental: true}
+}
+
+// Load reads features state from the features configuration file and environment
+func Load() (*Set, error) {
+	s := Defaults()
+	if v := os.Getenv(experimentalEnvVar); v != "" {
+		s.experimental, _ = strconv.ParseBool(v)
+	}
+
+	var err error
+	if s.file, err = loadFile(); err != nil {
+		return nil, err
+	}
+
+	s.env = map[string]bool{}
+	for _, f := range Flags {
+		if f.LegacyEnv == "" || os.Getenv(f.LegacyEnv) == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(os.Getenv(f.LegacyEnv))
+		if err != nil {
+			return nil, fmt.Errorf("%s environment variable expects boolean value: %w", f.LegacyEnv, err)
+		}
+		warnOnce(f.LegacyEnv, "%s is deprecated, set %s=%s instead", f.LegacyEnv, EnvVar, envValue(f.Name, enabled))
+		s.env[f.Name] = enabled
+	}
+	for _, v := range strings.Split(os.Getenv(EnvVar), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		name, disabled := strings.CutPrefix(v, "-")
+		if _, err := lookup(name); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvVar, err)
+		}
+		s.env[name] = !disabled
+	}
+	warnDeprecated(s.env)
+	return s, nil
+}
+
+// file returns the features configuration file, and whether it has been explicitly set by user
+func file() (string, bool) {
+	if f := os.Getenv(FileEnvVar); f != "" {
+		return f, true
+	}
+	return filepath.Join(config.Dir(), "compose", "features.yaml"), false
+}
+
+// loadFile reads the features configuration file. Default file is optional.
+func loadFile() (map[string]bool, error) {
+	path, explicit := file()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Features map[string]bool `yaml:"features"`
+	}
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for name := range cfg.Features {
+		if _, err := lookup(name); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+	warnDeprecated(cfg.Features)
+	return cfg.Features, nil
+}
+
+// ForProject returns a copy of s with features state set by project ProjectExtension
+func (s *Set) ForProject(project *types.Project) (*Set, error) {
+	if project == nil {
+		return s, nil
+	}
+	var values map[string]bool
+	ok, err := project.Extensions.Get(ProjectExtension, &values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ProjectExtension, err)
+	}
+	if !ok {
+		return s, nil
+	}
+	for name := range values {
+		f, err := lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ProjectExtension, err)
+		}
+		if !f.Project {
+			return nil, fmt.Errorf("invalid %s: feature %q can't be set by project", ProjectExtension, name)
+		}
+	}
+	warnDeprecated(values)
+	withProject := *s
+	withProject.project = values
+	return &withProject, nil
+}
+
+// Get returns the state of feature name, which must be one of Flags
+func (s *Set) Get(name string) Feature {
+	f, err := lookup(name)
+	if err != nil {
+		panic(err)
+	}
+	for _, src := range []struct {
+		name   string
+		values map[string]bool
+	}{
+		{SourceEnv, s.env},
+		{SourceProject, s.project},
+		{SourceFile, s.file},
+	} {
+		if enabled, ok := src.values[name]; ok {
+			return Feature{Flag: f, Enabled: enabled, Source: src.name}
+		}
+	}
+	enabled := f.Default
+	if f.Stage == Experimental && !s.experimental {
+		enabled = false
+	}
+	return Feature{Flag: f, Enabled: enabled, Source: SourceDefault}
+}
+
+// Enabled reports whether feature name is enabled
+func (s *Set) Enabled(name string) bool {
+	return s.Get(name).Enabled
+}
+
+// List returns the state of all features, sorted by name
+func (s *Set) List() []Feature {
+	features := make([]Feature, 0, len(Flags))
+	for _, f := range Flags {
+		features = append(features, s.Get(f.Name))
+	}
+	return features
+}
+
+func lookup(name string) (Flag, error) {
+	i := slices.IndexFunc(Flags, func(f Flag) bool {
+		return f.Name == name
+	})
+	if i < 0 {
+		return Flag{}, fmt.Errorf("unknown feature %q", name)
+	}
+	return Flags[i], nil
+}
+
+func envValue(name string, enabled bool) string {
+	if enabled {
+		return name
+	}
+	return "-" + name
+}
+
+// warned records warnings already reported, as features state is loaded by each operation
+var warned sync.Map
+
+func warnOnce(key string, format string, args ...any) {
+	if _, loaded := warned.LoadOrStore(key, true); !loaded {
+		logrus.Warnf(format, args...)
+	}
+}
+
+func warnDeprecated(values map[string]bool) {
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if f, _ := lookup(name); f.Stage == Deprecated {
+			warnOnce(name, "feature %q is deprecated and will be removed", name)
+		}
+	}
+}
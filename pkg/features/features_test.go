@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package features
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestFlagsSorted(t *testing.T) {
+	assert.Assert(t, slices.IsSortedFunc(Flags, func(a, b Flag) int {
+		return strings.Compare(a.Name, b.Name)
+	}))
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "features.yaml")
+	assert.NilError(t, os.WriteFile(file, []byte("features:\n  bake: false\n  oci-remote: false\n"), 0o600))
+	t.Setenv(FileEnvVar, file)
+	t.Setenv(EnvVar, "oci-remote, -watch-tar")
+	t.Setenv("COMPOSE_EXPERIMENTAL_GIT_REMOTE", "false")
+
+	set, err := Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, set.Get(Bake), Feature{Flag: Flags[0], Enabled: false, Source: SourceFile})
+	assert.DeepEqual(t, set.Get(GitRemote), Feature{Flag: Flags[1], Enabled: false, Source: SourceEnv})
+	assert.DeepEqual(t, set.Get(OCIRemote), Feature{Flag: Flags[2], Enabled: true, Source: SourceEnv})
+	assert.DeepEqual(t, set.Get(WatchTar), Feature{Flag: Flags[3], Enabled: false, Source: SourceEnv})
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Setenv(FileEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err := Load()
+	assert.ErrorContains(t, err, "missing.yaml")
+
+	t.Setenv(FileEnvVar, "")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	t.Setenv(EnvVar, "-unknown")
+	_, err = Load()
+	assert.ErrorContains(t, err, `invalid COMPOSE_FEATURES: unknown feature "unknown"`)
+
+	t.Setenv(EnvVar, "")
+	t.Setenv("COMPOSE_EXPERIMENTAL_OCI_REMOTE", "maybe")
+	_, err = Load()
+	assert.ErrorContains(t, err, "COMPOSE_EXPERIMENTAL_OCI_REMOTE environment variable expects boolean value")
+}
+
+func TestExperimentalOptOut(t *testing.T) {
+	previous := Flags
+	Flags = append(slices.Clone(Flags), Flag{Name: "zz-experiment", Stage: Experimental, Default: true})
+	t.Cleanup(func() {
+		Flags = previous
+	})
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	set, err := Load()
+	assert.NilError(t, err)
+	assert.Check(t, set.Enabled("zz-experiment"))
+
+	t.Setenv(experimentalEnvVar, "0")
+	set, err = Load()
+	assert.NilError(t, err)
+	assert.Check(t, !set.Enabled("zz-experiment"))
+	// remote loaders were never controlled by COMPOSE_EXPERIMENTAL
+	assert.Check(t, set.Enabled(GitRemote))
+	assert.Check(t, set.Enabled(OCIRemote))
+	assert.Check(t, set.Enabled(Bake))
+
+	t.Setenv(EnvVar, "zz-experiment")
+	set, err = Load()
+	assert.NilError(t, err)
+	assert.Check(t, set.Enabled("zz-experiment"))
+}
+
+func TestForProject(t *testing.T) {
+	set := &Set{experimental: true, env: map[string]bool{WatchTar: true}}
+	project := &types.Project{Extensions: types.Extensions{
+		ProjectExtension: map[string]any{Bake: false, WatchTar: false},
+	}}
+	withProject, err := set.ForProject(project)
+	assert.NilError(t, err)
+	assert.Equal(t, withProject.Get(Bake).Source, SourceProject)
+	assert.Check(t, !withProject.Enabled(Bake))
+	// environment has precedence over project
+	assert.Check(t, withProject.Enabled(WatchTar))
+	// original set is left unchanged
+	assert.Check(t, set.Enabled(Bake))
+
+	project.Extensions[ProjectExtension] = map[string]any{GitRemote: false}
+	_, err = set.ForProject(project)
+	assert.ErrorContains(t, err, `feature "git-remote" can't be set by project`)
+}
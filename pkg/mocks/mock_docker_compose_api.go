@@ -41,6 +41,35 @@ func (m *MockCompose) EXPECT() *MockComposeMockRecorder {
 	return m.recorder
 }
 
+// Adopt mocks base method.
+func (m *MockCompose) Adopt(ctx context.Context, project *types.Project, options api.AdoptOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adopt", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adopt indicates an expected call of Adopt.
+func (mr *MockComposeMockRecorder) Adopt(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockCompose)(nil).Adopt), ctx, project, options)
+}
+
+// Apply mocks base method.
+func (m *MockCompose) Apply(ctx context.Context, project *types.Project, options api.ApplyOptions) ([]api.ApplyChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, project, options)
+	ret0, _ := ret[0].([]api.ApplyChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockComposeMockRecorder) Apply(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockCompose)(nil).Apply), ctx, project, options)
+}
+
 // Attach mocks base method.
 func (m *MockCompose) Attach(ctx context.Context, projectName string, options api.AttachOptions) error {
 	m.ctrl.T.Helper()
@@ -111,6 +140,51 @@ func (mr *MockComposeMockRecorder) Create(ctx, project, options any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCompose)(nil).Create), ctx, project, options)
 }
 
+// Describe mocks base method.
+func (m *MockCompose) Describe(ctx context.Context, projectName string) (*types.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Describe", ctx, projectName)
+	ret0, _ := ret[0].(*types.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Describe indicates an expected call of Describe.
+func (mr *MockComposeMockRecorder) Describe(ctx, projectName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockCompose)(nil).Describe), ctx, projectName)
+}
+
+// DiskUsage mocks base method.
+func (m *MockCompose) DiskUsage(ctx context.Context, projectName string, options api.DiskUsageOptions) (api.DiskUsageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskUsage", ctx, projectName, options)
+	ret0, _ := ret[0].(api.DiskUsageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiskUsage indicates an expected call of DiskUsage.
+func (mr *MockComposeMockRecorder) DiskUsage(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskUsage", reflect.TypeOf((*MockCompose)(nil).DiskUsage), ctx, projectName, options)
+}
+
+// Doctor mocks base method.
+func (m *MockCompose) Doctor(ctx context.Context, project *types.Project) (api.DoctorReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Doctor", ctx, project)
+	ret0, _ := ret[0].(api.DoctorReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Doctor indicates an expected call of Doctor.
+func (mr *MockComposeMockRecorder) Doctor(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doctor", reflect.TypeOf((*MockCompose)(nil).Doctor), ctx, project)
+}
+
 // Down mocks base method.
 func (m *MockCompose) Down(ctx context.Context, projectName string, options api.DownOptions) error {
 	m.ctrl.T.Helper()
@@ -154,6 +228,36 @@ func (mr *MockComposeMockRecorder) Exec(ctx, projectName, options any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockCompose)(nil).Exec), ctx, projectName, options)
 }
 
+// ExecCommand mocks base method.
+func (m *MockCompose) ExecCommand(ctx context.Context, projectName string, options api.ExecOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecCommand", ctx, projectName, options)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecCommand indicates an expected call of ExecCommand.
+func (mr *MockComposeMockRecorder) ExecCommand(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecCommand", reflect.TypeOf((*MockCompose)(nil).ExecCommand), ctx, projectName, options)
+}
+
+// ExplainRecreate mocks base method.
+func (m *MockCompose) ExplainRecreate(ctx context.Context, project *types.Project, service string) ([]api.RecreateExplanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainRecreate", ctx, project, service)
+	ret0, _ := ret[0].([]api.RecreateExplanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainRecreate indicates an expected call of ExplainRecreate.
+func (mr *MockComposeMockRecorder) ExplainRecreate(ctx, project, service any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainRecreate", reflect.TypeOf((*MockCompose)(nil).ExplainRecreate), ctx, project, service)
+}
+
 // Export mocks base method.
 func (m *MockCompose) Export(ctx context.Context, projectName string, options api.ExportOptions) error {
 	m.ctrl.T.Helper()
@@ -168,6 +272,21 @@ func (mr *MockComposeMockRecorder) Export(ctx, projectName, options any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockCompose)(nil).Export), ctx, projectName, options)
 }
 
+// Features mocks base method.
+func (m *MockCompose) Features(ctx context.Context) ([]api.Feature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Features", ctx)
+	ret0, _ := ret[0].([]api.Feature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Features indicates an expected call of Features.
+func (mr *MockComposeMockRecorder) Features(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Features", reflect.TypeOf((*MockCompose)(nil).Features), ctx)
+}
+
 // Generate mocks base method.
 func (m *MockCompose) Generate(ctx context.Context, options api.GenerateOptions) (*types.Project, error) {
 	m.ctrl.T.Helper()
@@ -198,6 +317,36 @@ func (mr *MockComposeMockRecorder) Images(ctx, projectName, options any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Images", reflect.TypeOf((*MockCompose)(nil).Images), ctx, projectName, options)
 }
 
+// ImagesPullAudit mocks base method.
+func (m *MockCompose) ImagesPullAudit(ctx context.Context, project *types.Project, options api.ImagesOptions) ([]api.ImagePullAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImagesPullAudit", ctx, project, options)
+	ret0, _ := ret[0].([]api.ImagePullAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImagesPullAudit indicates an expected call of ImagesPullAudit.
+func (mr *MockComposeMockRecorder) ImagesPullAudit(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagesPullAudit", reflect.TypeOf((*MockCompose)(nil).ImagesPullAudit), ctx, project, options)
+}
+
+// ImagesTree mocks base method.
+func (m *MockCompose) ImagesTree(ctx context.Context, projectName string, options api.ImagesOptions) (api.ImageTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImagesTree", ctx, projectName, options)
+	ret0, _ := ret[0].(api.ImageTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImagesTree indicates an expected call of ImagesTree.
+func (mr *MockComposeMockRecorder) ImagesTree(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagesTree", reflect.TypeOf((*MockCompose)(nil).ImagesTree), ctx, projectName, options)
+}
+
 // Kill mocks base method.
 func (m *MockCompose) Kill(ctx context.Context, projectName string, options api.KillOptions) error {
 	m.ctrl.T.Helper()
@@ -256,6 +405,136 @@ func (mr *MockComposeMockRecorder) Logs(ctx, projectName, consumer, options any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockCompose)(nil).Logs), ctx, projectName, consumer, options)
 }
 
+// Migrate mocks base method.
+func (m *MockCompose) Migrate(ctx context.Context, projectName string, target *types.Project, options api.MigrateOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Migrate", ctx, projectName, target, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Migrate indicates an expected call of Migrate.
+func (mr *MockComposeMockRecorder) Migrate(ctx, projectName, target, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockCompose)(nil).Migrate), ctx, projectName, target, options)
+}
+
+// Move mocks base method.
+func (m *MockCompose) Move(ctx context.Context, project *types.Project, options api.MoveOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockComposeMockRecorder) Move(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCompose)(nil).Move), ctx, project, options)
+}
+
+// NetworkConnect mocks base method.
+func (m *MockCompose) NetworkConnect(ctx context.Context, project *types.Project, options api.NetworkConnectOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkConnect", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetworkConnect indicates an expected call of NetworkConnect.
+func (mr *MockComposeMockRecorder) NetworkConnect(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkConnect", reflect.TypeOf((*MockCompose)(nil).NetworkConnect), ctx, project, options)
+}
+
+// NetworkDisconnect mocks base method.
+func (m *MockCompose) NetworkDisconnect(ctx context.Context, projectName string, options api.NetworkDisconnectOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkDisconnect", ctx, projectName, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetworkDisconnect indicates an expected call of NetworkDisconnect.
+func (mr *MockComposeMockRecorder) NetworkDisconnect(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkDisconnect", reflect.TypeOf((*MockCompose)(nil).NetworkDisconnect), ctx, projectName, options)
+}
+
+// NetworkDoctor mocks base method.
+func (m *MockCompose) NetworkDoctor(ctx context.Context, project *types.Project, options api.NetworkDoctorOptions) (api.NetworkDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkDoctor", ctx, project, options)
+	ret0, _ := ret[0].(api.NetworkDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetworkDoctor indicates an expected call of NetworkDoctor.
+func (mr *MockComposeMockRecorder) NetworkDoctor(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkDoctor", reflect.TypeOf((*MockCompose)(nil).NetworkDoctor), ctx, project, options)
+}
+
+// NetworkInspect mocks base method.
+func (m *MockCompose) NetworkInspect(ctx context.Context, projectName, network string) (api.NetworkInspect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkInspect", ctx, projectName, network)
+	ret0, _ := ret[0].(api.NetworkInspect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetworkInspect indicates an expected call of NetworkInspect.
+func (mr *MockComposeMockRecorder) NetworkInspect(ctx, projectName, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkInspect", reflect.TypeOf((*MockCompose)(nil).NetworkInspect), ctx, projectName, network)
+}
+
+// NetworkRecreate mocks base method.
+func (m *MockCompose) NetworkRecreate(ctx context.Context, project *types.Project, options api.NetworkRecreateOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkRecreate", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetworkRecreate indicates an expected call of NetworkRecreate.
+func (mr *MockComposeMockRecorder) NetworkRecreate(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkRecreate", reflect.TypeOf((*MockCompose)(nil).NetworkRecreate), ctx, project, options)
+}
+
+// Networks mocks base method.
+func (m *MockCompose) Networks(ctx context.Context, projectName string, options api.NetworksOptions) ([]api.NetworkSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Networks", ctx, projectName, options)
+	ret0, _ := ret[0].([]api.NetworkSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Networks indicates an expected call of Networks.
+func (mr *MockComposeMockRecorder) Networks(ctx, projectName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Networks", reflect.TypeOf((*MockCompose)(nil).Networks), ctx, projectName, options)
+}
+
+// Orphans mocks base method.
+func (m *MockCompose) Orphans(ctx context.Context, project *types.Project) ([]api.OrphanSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orphans", ctx, project)
+	ret0, _ := ret[0].([]api.OrphanSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Orphans indicates an expected call of Orphans.
+func (mr *MockComposeMockRecorder) Orphans(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orphans", reflect.TypeOf((*MockCompose)(nil).Orphans), ctx, project)
+}
+
 // Pause mocks base method.
 func (m *MockCompose) Pause(ctx context.Context, projectName string, options api.PauseOptions) error {
 	m.ctrl.T.Helper()
@@ -357,6 +636,20 @@ func (mr *MockComposeMockRecorder) Remove(ctx, projectName, options any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockCompose)(nil).Remove), ctx, projectName, options)
 }
 
+// RenewCertificates mocks base method.
+func (m *MockCompose) RenewCertificates(ctx context.Context, project *types.Project, options api.CertificatesOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewCertificates", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewCertificates indicates an expected call of RenewCertificates.
+func (mr *MockComposeMockRecorder) RenewCertificates(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewCertificates", reflect.TypeOf((*MockCompose)(nil).RenewCertificates), ctx, project, options)
+}
+
 // Restart mocks base method.
 func (m *MockCompose) Restart(ctx context.Context, projectName string, options api.RestartOptions) error {
 	m.ctrl.T.Helper()
@@ -371,6 +664,35 @@ func (mr *MockComposeMockRecorder) Restart(ctx, projectName, options any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restart", reflect.TypeOf((*MockCompose)(nil).Restart), ctx, projectName, options)
 }
 
+// Resume mocks base method.
+func (m *MockCompose) Resume(ctx context.Context, project *types.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockComposeMockRecorder) Resume(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCompose)(nil).Resume), ctx, project)
+}
+
+// RunOneOff mocks base method.
+func (m *MockCompose) RunOneOff(ctx context.Context, project *types.Project, opts api.RunOneOffOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunOneOff", ctx, project, opts)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunOneOff indicates an expected call of RunOneOff.
+func (mr *MockComposeMockRecorder) RunOneOff(ctx, project, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOneOff", reflect.TypeOf((*MockCompose)(nil).RunOneOff), ctx, project, opts)
+}
+
 // RunOneOffContainer mocks base method.
 func (m *MockCompose) RunOneOffContainer(ctx context.Context, project *types.Project, opts api.RunOptions) (int, error) {
 	m.ctrl.T.Helper()
@@ -400,6 +722,21 @@ func (mr *MockComposeMockRecorder) Scale(ctx, project, options any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockCompose)(nil).Scale), ctx, project, options)
 }
 
+// SecurityAudit mocks base method.
+func (m *MockCompose) SecurityAudit(ctx context.Context, project *types.Project, options api.SecurityAuditOptions) (api.SecurityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityAudit", ctx, project, options)
+	ret0, _ := ret[0].(api.SecurityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SecurityAudit indicates an expected call of SecurityAudit.
+func (mr *MockComposeMockRecorder) SecurityAudit(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityAudit", reflect.TypeOf((*MockCompose)(nil).SecurityAudit), ctx, project, options)
+}
+
 // Start mocks base method.
 func (m *MockCompose) Start(ctx context.Context, projectName string, options api.StartOptions) error {
 	m.ctrl.T.Helper()
@@ -428,6 +765,20 @@ func (mr *MockComposeMockRecorder) Stop(ctx, projectName, options any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCompose)(nil).Stop), ctx, projectName, options)
 }
 
+// SwarmDeploy mocks base method.
+func (m *MockCompose) SwarmDeploy(ctx context.Context, project *types.Project, options api.SwarmDeployOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwarmDeploy", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SwarmDeploy indicates an expected call of SwarmDeploy.
+func (mr *MockComposeMockRecorder) SwarmDeploy(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwarmDeploy", reflect.TypeOf((*MockCompose)(nil).SwarmDeploy), ctx, project, options)
+}
+
 // Top mocks base method.
 func (m *MockCompose) Top(ctx context.Context, projectName string, services []string) ([]api.ContainerProcSummary, error) {
 	m.ctrl.T.Helper()
@@ -443,6 +794,20 @@ func (mr *MockComposeMockRecorder) Top(ctx, projectName, services any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Top", reflect.TypeOf((*MockCompose)(nil).Top), ctx, projectName, services)
 }
 
+// TruncateLogs mocks base method.
+func (m *MockCompose) TruncateLogs(ctx context.Context, project *types.Project, options api.TruncateLogsOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TruncateLogs", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TruncateLogs indicates an expected call of TruncateLogs.
+func (mr *MockComposeMockRecorder) TruncateLogs(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateLogs", reflect.TypeOf((*MockCompose)(nil).TruncateLogs), ctx, project, options)
+}
+
 // UnPause mocks base method.
 func (m *MockCompose) UnPause(ctx context.Context, projectName string, options api.PauseOptions) error {
 	m.ctrl.T.Helper()
@@ -486,6 +851,80 @@ func (mr *MockComposeMockRecorder) Viz(ctx, project, options any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Viz", reflect.TypeOf((*MockCompose)(nil).Viz), ctx, project, options)
 }
 
+// VolumeBrowse mocks base method.
+func (m *MockCompose) VolumeBrowse(ctx context.Context, project string, options api.VolumeBrowseOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeBrowse", ctx, project, options)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeBrowse indicates an expected call of VolumeBrowse.
+func (mr *MockComposeMockRecorder) VolumeBrowse(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeBrowse", reflect.TypeOf((*MockCompose)(nil).VolumeBrowse), ctx, project, options)
+}
+
+// VolumeInspect mocks base method.
+func (m *MockCompose) VolumeInspect(ctx context.Context, project, volume string) (api.VolumeInspect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeInspect", ctx, project, volume)
+	ret0, _ := ret[0].(api.VolumeInspect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeInspect indicates an expected call of VolumeInspect.
+func (mr *MockComposeMockRecorder) VolumeInspect(ctx, project, volume any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeInspect", reflect.TypeOf((*MockCompose)(nil).VolumeInspect), ctx, project, volume)
+}
+
+// VolumeRestore mocks base method.
+func (m *MockCompose) VolumeRestore(ctx context.Context, project string, options api.VolumeRestoreOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeRestore", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VolumeRestore indicates an expected call of VolumeRestore.
+func (mr *MockComposeMockRecorder) VolumeRestore(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeRestore", reflect.TypeOf((*MockCompose)(nil).VolumeRestore), ctx, project, options)
+}
+
+// VolumeSnapshot mocks base method.
+func (m *MockCompose) VolumeSnapshot(ctx context.Context, project string, options api.VolumeSnapshotOptions) (api.VolumeSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeSnapshot", ctx, project, options)
+	ret0, _ := ret[0].(api.VolumeSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeSnapshot indicates an expected call of VolumeSnapshot.
+func (mr *MockComposeMockRecorder) VolumeSnapshot(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeSnapshot", reflect.TypeOf((*MockCompose)(nil).VolumeSnapshot), ctx, project, options)
+}
+
+// VolumeSnapshots mocks base method.
+func (m *MockCompose) VolumeSnapshots(ctx context.Context, project string, options api.VolumeSnapshotsOptions) ([]api.VolumeSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeSnapshots", ctx, project, options)
+	ret0, _ := ret[0].([]api.VolumeSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeSnapshots indicates an expected call of VolumeSnapshots.
+func (mr *MockComposeMockRecorder) VolumeSnapshots(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeSnapshots", reflect.TypeOf((*MockCompose)(nil).VolumeSnapshots), ctx, project, options)
+}
+
 // Volumes mocks base method.
 func (m *MockCompose) Volumes(ctx context.Context, project string, options api.VolumesOptions) ([]api.VolumesSummary, error) {
 	m.ctrl.T.Helper()
@@ -530,6 +969,20 @@ func (mr *MockComposeMockRecorder) Watch(ctx, project, options any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockCompose)(nil).Watch), ctx, project, options)
 }
 
+// WatchState mocks base method.
+func (m *MockCompose) WatchState(ctx context.Context, project *types.Project, options api.WatchStateOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchState", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchState indicates an expected call of WatchState.
+func (mr *MockComposeMockRecorder) WatchState(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchState", reflect.TypeOf((*MockCompose)(nil).WatchState), ctx, project, options)
+}
+
 // MockLogConsumer is a mock of LogConsumer interface.
 type MockLogConsumer struct {
 	ctrl     *gomock.Controller
@@ -20,12 +20,13 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/compose-spec/compose-go/v2/cli"
@@ -33,27 +34,62 @@ import (
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
+	"github.com/gofrs/flock"
 	gitutil "github.com/moby/buildkit/frontend/dockerfile/dfgitutil"
 	"github.com/sirupsen/logrus"
 )
 
+// Deprecated: use features.EnvVar to control features.GitRemote
 const GIT_REMOTE_ENABLED = "COMPOSE_EXPERIMENTAL_GIT_REMOTE"
 
 func gitRemoteLoaderEnabled() (bool, error) {
-	if v := os.Getenv(GIT_REMOTE_ENABLED); v != "" {
-		enabled, err := strconv.ParseBool(v)
-		if err != nil {
-			return false, fmt.Errorf("COMPOSE_EXPERIMENTAL_GIT_REMOTE environment variable expects boolean value: %w", err)
+	set, err := features.Load()
+	if err != nil {
+		return false, err
+	}
+	return set.Enabled(features.GitRemote), nil
+}
+
+// DirLoader is a ResourceLoader which can also check out a remote directory, used as project directory
+type DirLoader interface {
+	loader.ResourceLoader
+	LoadDir(ctx context.Context, path string) (string, error)
+}
+
+// ResolveProjectDir checks out a remote project directory, or the directory of a remote root compose file, and
+// returns the local checkout, so that relative paths declared by the compose model are resolved within it.
+// workingDir is returned unchanged if neither is remote.
+func ResolveProjectDir(ctx context.Context, workingDir string, configPaths []string, loaders []loader.ResourceLoader) (string, error) {
+	for _, l := range loaders {
+		switch {
+		case workingDir != "" && l.Accept(workingDir):
+			dl, ok := l.(DirLoader)
+			if !ok {
+				return "", fmt.Errorf("project directory %s can't be loaded from a remote resource", workingDir)
+			}
+			return dl.LoadDir(ctx, workingDir)
+		case workingDir == "" && len(configPaths) > 0 && l.Accept(configPaths[0]):
+			if _, ok := l.(DirLoader); !ok {
+				// OCI artifacts don't have a directory structure to resolve relative paths
+				continue
+			}
+			file, err := l.Load(ctx, configPaths[0])
+			if err != nil || file == "" {
+				return workingDir, err
+			}
+			return filepath.Dir(file), nil
 		}
-		return enabled, err
 	}
-	return true, nil
+	return workingDir, nil
 }
 
-func NewGitRemoteLoader(dockerCli command.Cli, offline bool) loader.ResourceLoader {
+func NewGitRemoteLoader(dockerCli command.Cli, offline bool, options api.GitOptions) DirLoader {
 	return gitRemoteLoader{
 		dockerCli: dockerCli,
 		offline:   offline,
+		pin:       options.Pin,
+		refresh:   options.Refresh,
 		known:     map[string]string{},
 	}
 }
@@ -61,6 +97,8 @@ func NewGitRemoteLoader(dockerCli command.Cli, offline bool) loader.ResourceLoad
 type gitRemoteLoader struct {
 	dockerCli command.Cli
 	offline   bool
+	pin       bool
+	refresh   bool
 	known     map[string]string
 }
 
@@ -72,12 +110,48 @@ func (g gitRemoteLoader) Accept(path string) bool {
 var commitSHA = regexp.MustCompile(`^[a-f0-9]{40}$`)
 
 func (g gitRemoteLoader) Load(ctx context.Context, path string) (string, error) {
+	local, err := g.checkoutPath(ctx, path)
+	if err != nil || local == "" {
+		return local, err
+	}
+	stat, err := os.Stat(local)
+	if err != nil {
+		return "", err
+	}
+	if stat.IsDir() {
+		local, err = findFile(cli.DefaultFileNames, local)
+	}
+	return local, err
+}
+
+// LoadDir checks out a git repository and returns the local path of the directory designated by path
+func (g gitRemoteLoader) LoadDir(ctx context.Context, path string) (string, error) {
+	local, err := g.checkoutPath(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if local == "" {
+		return "", fmt.Errorf("%s is not available offline", path)
+	}
+	stat, err := os.Stat(local)
+	if err != nil {
+		return "", err
+	}
+	if !stat.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", path)
+	}
+	return local, nil
+}
+
+// checkoutPath checks out the repository path refers to, as a shallow clone cached by commit,
+// and returns the local path of the resource. An empty string is returned if resource isn't available offline.
+func (g gitRemoteLoader) checkoutPath(ctx context.Context, path string) (string, error) {
 	enabled, err := gitRemoteLoaderEnabled()
 	if err != nil {
 		return "", err
 	}
 	if !enabled {
-		return "", fmt.Errorf("git remote resource is disabled by %q", GIT_REMOTE_ENABLED)
+		return "", fmt.Errorf("git remote resource is disabled by feature %q", features.GitRemote)
 	}
 
 	ref, _, err := gitutil.ParseGitRef(path)
@@ -119,14 +193,7 @@ func (g gitRemoteLoader) Load(ctx context.Context, path string) (string, error)
 		}
 		local = filepath.Join(local, ref.SubDir)
 	}
-	stat, err := os.Stat(local)
-	if err != nil {
-		return "", err
-	}
-	if stat.IsDir() {
-		local, err = findFile(cli.DefaultFileNames, local)
-	}
-	return local, err
+	return local, nil
 }
 
 func (g gitRemoteLoader) Dir(path string) string {
@@ -169,28 +236,122 @@ func validateGitSubDir(base, subDir string) error {
 }
 
 func (g gitRemoteLoader) resolveGitRef(ctx context.Context, path string, ref *gitutil.GitRef) error {
-	if !commitSHA.MatchString(ref.Ref) {
-		cmd := exec.CommandContext(ctx, "git", "ls-remote", "--exit-code", ref.Remote, ref.Ref)
-		cmd.Env = g.gitCommandEnv()
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			if cmd.ProcessState.ExitCode() == 2 {
-				return fmt.Errorf("repository does not contain ref %s, output: %q: %w", path, string(out), err)
-			}
-			return fmt.Errorf("failed to access repository at %s:\n %s", ref.Remote, out)
-		}
-		if len(out) < 40 {
-			return fmt.Errorf("unexpected git command output: %q", string(out))
-		}
-		sha := string(out[:40])
-		if !commitSHA.MatchString(sha) {
-			return fmt.Errorf("invalid commit sha %q", sha)
-		}
+	if commitSHA.MatchString(ref.Ref) {
+		return nil
+	}
+	if !g.pin {
+		return g.lsRemote(ctx, path, ref)
+	}
+	pinsFile, err := gitPinsPath()
+	if err != nil {
+		return err
+	}
+	pins, err := loadGitPins(pinsFile)
+	if err != nil {
+		return err
+	}
+	key := ref.Remote + "#" + ref.Ref
+	if sha, ok := pins[key]; ok && (!g.refresh || g.offline) {
+		logrus.Debugf("using commit %s pinned for %s", sha, key)
 		ref.Ref = sha
+		return nil
+	}
+	if err := g.lsRemote(ctx, path, ref); err != nil {
+		return err
+	}
+	return pinGitRef(pinsFile, key, ref.Ref)
+}
+
+// lsRemote resolves ref to the commit it currently designates in the remote repository
+func (g gitRemoteLoader) lsRemote(ctx context.Context, path string, ref *gitutil.GitRef) error {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--exit-code", ref.Remote, ref.Ref)
+	cmd.Env = g.gitCommandEnv()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if cmd.ProcessState.ExitCode() == 2 {
+			return fmt.Errorf("repository does not contain ref %s, output: %q: %w", path, string(out), err)
+		}
+		return fmt.Errorf("failed to access repository at %s:\n %s", ref.Remote, out)
+	}
+	if len(out) < 40 {
+		return fmt.Errorf("unexpected git command output: %q", string(out))
+	}
+	sha := string(out[:40])
+	if !commitSHA.MatchString(sha) {
+		return fmt.Errorf("invalid commit sha %q", sha)
 	}
+	ref.Ref = sha
 	return nil
 }
 
+// gitPinsFile records the commit git refs resolved to when first checked out, relative to the cache directory
+const gitPinsFile = "git-pins.json"
+
+func gitPinsPath() (string, error) {
+	cache, err := cacheDir()
+	if err != nil {
+		return "", fmt.Errorf("initializing remote resource cache: %w", err)
+	}
+	return filepath.Join(cache, gitPinsFile), nil
+}
+
+// loadGitPins reads pinned commits indexed by `remote#ref`
+func loadGitPins(path string) (map[string]string, error) {
+	pins := map[string]string{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &pins); err != nil {
+		return nil, fmt.Errorf("invalid git pins file %s: %w", path, err)
+	}
+	return pins, nil
+}
+
+// pinGitRef records the commit a `remote#ref` key resolved to. As concurrent commands may pin other refs, pins
+// file is read again and updated under a lock
+func pinGitRef(path string, key string, sha string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking git pins file: %w", err)
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	pins, err := loadGitPins(path)
+	if err != nil {
+		return err
+	}
+	pins[key] = sha
+	return saveGitPins(path, pins)
+}
+
+// saveGitPins writes pins to a temporary file then renames it, so readers never get a partially written file
+func saveGitPins(path string, pins map[string]string) error {
+	content, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), gitPinsFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 func (g gitRemoteLoader) checkout(ctx context.Context, path string, ref *gitutil.GitRef) error {
 	err := os.MkdirAll(path, 0o700)
 	if err != nil {
@@ -17,8 +17,15 @@
 package remote
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
+	gitutil "github.com/moby/buildkit/frontend/dockerfile/dfgitutil"
 	"gotest.tools/v3/assert"
 )
 
@@ -173,3 +180,75 @@ func TestValidateGitSubDirSecurityScenarios(t *testing.T) {
 		assert.NilError(t, err)
 	})
 }
+
+func TestResolveGitRefPinned(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	pinsFile, err := gitPinsPath()
+	assert.NilError(t, err)
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	assert.NilError(t, pinGitRef(pinsFile, "https://example.com/stack.git#main", sha))
+
+	g := gitRemoteLoader{known: map[string]string{}, pin: true}
+	ref := &gitutil.GitRef{Remote: "https://example.com/stack.git", Ref: "main"}
+	assert.NilError(t, g.resolveGitRef(t.Context(), "https://example.com/stack.git#main", ref))
+	assert.Equal(t, ref.Ref, sha)
+
+	// pinned commit is still used offline, even when refresh is requested
+	g = gitRemoteLoader{known: map[string]string{}, pin: true, refresh: true, offline: true}
+	ref = &gitutil.GitRef{Remote: "https://example.com/stack.git", Ref: "main"}
+	assert.NilError(t, g.resolveGitRef(t.Context(), "https://example.com/stack.git#main", ref))
+	assert.Equal(t, ref.Ref, sha)
+}
+
+func TestResolveGitRefUnpinned(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", repo},
+		{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		assert.NilError(t, err, string(out))
+	}
+	out, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	assert.NilError(t, err)
+	head := strings.TrimSpace(string(out))
+
+	pinsFile, err := gitPinsPath()
+	assert.NilError(t, err)
+	assert.NilError(t, pinGitRef(pinsFile, repo+"#main", strings.Repeat("a", 40)))
+
+	// branch is resolved live unless pinning is requested
+	g := gitRemoteLoader{known: map[string]string{}}
+	ref := &gitutil.GitRef{Remote: repo, Ref: "main"}
+	assert.NilError(t, g.resolveGitRef(t.Context(), repo+"#main", ref))
+	assert.Equal(t, ref.Ref, head)
+}
+
+func TestPinGitRefConcurrently(t *testing.T) {
+	pinsFile := filepath.Join(t.TempDir(), "cache", gitPinsFile)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Check(t, pinGitRef(pinsFile, fmt.Sprintf("https://example.com/stack.git#v%d", i), strings.Repeat("a", 40)))
+		}()
+	}
+	wg.Wait()
+
+	// no pin got lost by a concurrent read-modify-write, and no temporary file is left
+	pins, err := loadGitPins(pinsFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(pins), 20)
+	entries, err := os.ReadDir(filepath.Dir(pinsFile))
+	assert.NilError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.DeepEqual(t, names, []string{gitPinsFile, gitPinsFile + ".lock"})
+}
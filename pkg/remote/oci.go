@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/compose-spec/compose-go/v2/loader"
@@ -32,10 +31,12 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v5/internal/oci"
 	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/features"
 	spec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 const (
+	// Deprecated: use features.EnvVar to control features.OCIRemote
 	OCI_REMOTE_ENABLED = "COMPOSE_EXPERIMENTAL_OCI_REMOTE"
 	OciPrefix          = "oci://"
 )
@@ -67,14 +68,11 @@ func validatePathInBase(base, unsafePath string) error {
 }
 
 func ociRemoteLoaderEnabled() (bool, error) {
-	if v := os.Getenv(OCI_REMOTE_ENABLED); v != "" {
-		enabled, err := strconv.ParseBool(v)
-		if err != nil {
-			return false, fmt.Errorf("COMPOSE_EXPERIMENTAL_OCI_REMOTE environment variable expects boolean value: %w", err)
-		}
-		return enabled, err
+	set, err := features.Load()
+	if err != nil {
+		return false, err
 	}
-	return true, nil
+	return set.Enabled(features.OCIRemote), nil
 }
 
 func NewOCIRemoteLoader(dockerCli command.Cli, offline bool, options api.OCIOptions) loader.ResourceLoader {
@@ -104,7 +102,7 @@ func (g ociRemoteLoader) Load(ctx context.Context, path string) (string, error)
 		return "", err
 	}
 	if !enabled {
-		return "", fmt.Errorf("OCI remote resource is disabled by %q", OCI_REMOTE_ENABLED)
+		return "", fmt.Errorf("OCI remote resource is disabled by feature %q", features.OCIRemote)
 	}
 
 	if g.offline {
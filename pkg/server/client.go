@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client connects to a Compose API server
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a Client for the server listening on address
+func NewClient(address string) (*Client, error) {
+	if _, err := socketPath(address); err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the server
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func method(name string) string {
+	return "/" + ServiceName + "/" + name
+}
+
+// Ps lists containers of a project
+func (c *Client) Ps(ctx context.Context, req PsRequest) (PsResponse, error) {
+	var resp PsResponse
+	err := c.conn.Invoke(ctx, method("Ps"), &req, &resp)
+	return resp, err
+}
+
+// List lists running projects
+func (c *Client) List(ctx context.Context, req ListRequest) (ListResponse, error) {
+	var resp ListResponse
+	err := c.conn.Invoke(ctx, method("List"), &req, &resp)
+	return resp, err
+}
+
+// Up creates and starts project containers, progress is reported to fn
+func (c *Client) Up(ctx context.Context, req UpRequest, fn func(Progress)) error {
+	return receive(ctx, c, "Up", &req, fn)
+}
+
+// Down stops and removes project resources, progress is reported to fn
+func (c *Client) Down(ctx context.Context, req DownRequest, fn func(Progress)) error {
+	return receive(ctx, c, "Down", &req, fn)
+}
+
+// Stop stops project containers, progress is reported to fn
+func (c *Client) Stop(ctx context.Context, req StopRequest, fn func(Progress)) error {
+	return receive(ctx, c, "Stop", &req, fn)
+}
+
+// Restart restarts project containers, progress is reported to fn
+func (c *Client) Restart(ctx context.Context, req StopRequest, fn func(Progress)) error {
+	return receive(ctx, c, "Restart", &req, fn)
+}
+
+// Logs streams project containers logs to fn
+func (c *Client) Logs(ctx context.Context, req LogsRequest, fn func(LogMessage)) error {
+	return receive(ctx, c, "Logs", &req, fn)
+}
+
+// Events streams project containers events to fn
+func (c *Client) Events(ctx context.Context, req EventsRequest, fn func(Event)) error {
+	return receive(ctx, c, "Events", &req, fn)
+}
+
+// receive calls a server-streaming method and passes each message to fn until the stream ends
+func receive[Msg any](ctx context.Context, c *Client, name string, req any, fn func(Msg)) error {
+	stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, method(name))
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		var msg Msg
+		err := stream.RecvMsg(&msg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fn(msg)
+	}
+}
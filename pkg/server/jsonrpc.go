@@ -0,0 +1,270 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/status"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCServerError    = -32000
+)
+
+// cancelMethod cancels the running request which ID is passed as params `id`
+const cancelMethod = "$/cancel"
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Data is the gRPC status code name, so clients can check the error kind
+	Data string `json:"data,omitempty"`
+}
+
+type jsonRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// NotificationParams are the params of notifications sent as a request runs, with ID of the request
+type NotificationParams struct {
+	ID      json.RawMessage `json:"id"`
+	Message any             `json:"message"`
+}
+
+// jsonRPCMethod runs a request, sending notifications to the client, and returns the result
+type jsonRPCMethod func(s *Server, ctx context.Context, params json.RawMessage, send func(msg any)) (any, error)
+
+var jsonRPCMethods = map[string]jsonRPCMethod{
+	"Ps":      jsonRPCUnary((*Server).ps),
+	"List":    jsonRPCUnary((*Server).list),
+	"Up":      jsonRPCStream((*Server).up),
+	"Down":    jsonRPCStream((*Server).down),
+	"Stop":    jsonRPCStream((*Server).stop),
+	"Restart": jsonRPCStream((*Server).restart),
+	"Logs":    jsonRPCStream((*Server).logs),
+	"Events":  jsonRPCStream((*Server).events),
+}
+
+var errInvalidParams = errors.New("invalid params")
+
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidParams, err)
+	}
+	return nil
+}
+
+func jsonRPCUnary[Req, Resp any](fn func(*Server, context.Context, *Req) (*Resp, error)) jsonRPCMethod {
+	return func(s *Server, ctx context.Context, params json.RawMessage, _ func(msg any)) (any, error) {
+		req := new(Req)
+		if err := decodeParams(params, req); err != nil {
+			return nil, err
+		}
+		return fn(s, ctx, req)
+	}
+}
+
+func jsonRPCStream[Req any](fn streamMethod[Req]) jsonRPCMethod {
+	return func(s *Server, ctx context.Context, params json.RawMessage, send func(msg any)) (any, error) {
+		req := new(Req)
+		if err := decodeParams(params, req); err != nil {
+			return nil, err
+		}
+		if err := fn(s, ctx, req, send); err != nil {
+			return nil, err
+		}
+		return &Empty{}, nil
+	}
+}
+
+// notificationMethod is the JSON-RPC method of notifications for a message streamed by a request
+func notificationMethod(msg any) string {
+	switch msg.(type) {
+	case *LogMessage:
+		return "log"
+	case *Event:
+		return "event"
+	default:
+		return "progress"
+	}
+}
+
+// jsonRPCSession serves line-delimited JSON-RPC requests, each one running concurrently until canceled
+type jsonRPCSession struct {
+	server *Server
+	out    io.Writer
+
+	mu      sync.Mutex // guards out and running
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// ServeJSONRPC reads line-delimited JSON-RPC 2.0 requests from in and writes responses and notifications to out,
+// until in is closed, then cancels running requests and waits for them to complete. Progress, logs and events are sent as `progress`, `log` and `event`
+// notifications with ID of the request, and a running request can be canceled with a `$/cancel` notification.
+func (s *Server) ServeJSONRPC(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	session := &jsonRPCSession{
+		server:  s,
+		out:     out,
+		running: map[string]context.CancelFunc{},
+	}
+	defer func() {
+		cancel()
+		session.wg.Wait()
+	}()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req jsonRPCRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			session.reply(nil, nil, &jsonRPCError{Code: jsonRPCParseError, Message: err.Error()})
+			continue
+		}
+		session.handle(ctx, req)
+	}
+	return scanner.Err()
+}
+
+func (j *jsonRPCSession) handle(ctx context.Context, req jsonRPCRequest) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		j.reply(req.ID, nil, &jsonRPCError{Code: jsonRPCInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+		return
+	}
+	if req.Method == cancelMethod {
+		var params struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := decodeParams(req.Params, &params); err == nil {
+			j.cancel(params.ID)
+		}
+		return
+	}
+	method, ok := jsonRPCMethods[req.Method]
+	if !ok {
+		j.reply(req.ID, nil, &jsonRPCError{Code: jsonRPCMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	if len(req.ID) > 0 {
+		j.mu.Lock()
+		_, inFlight := j.running[string(req.ID)]
+		if !inFlight {
+			j.running[string(req.ID)] = cancel
+		}
+		j.mu.Unlock()
+		if inFlight {
+			cancel()
+			j.reply(req.ID, nil, &jsonRPCError{Code: jsonRPCInvalidRequest, Message: fmt.Sprintf("request %s is already running", req.ID)})
+			return
+		}
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer cancel()
+		result, err := method(j.server, ctx, req.Params, func(msg any) {
+			j.write(jsonRPCNotification{
+				JSONRPC: "2.0",
+				Method:  notificationMethod(msg),
+				Params:  NotificationParams{ID: req.ID, Message: msg},
+			})
+		})
+		// release ID before replying, so client can reuse it as soon as it gets the response
+		j.cancel(req.ID)
+		if len(req.ID) == 0 {
+			// request sent as a notification doesn't expect a response
+			return
+		}
+		j.reply(req.ID, result, toJSONRPCError(err))
+	}()
+}
+
+func (j *jsonRPCSession) cancel(id json.RawMessage) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if cancel, ok := j.running[string(id)]; ok {
+		cancel()
+		delete(j.running, string(id))
+	}
+}
+
+func (j *jsonRPCSession) reply(id json.RawMessage, result any, err *jsonRPCError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if err != nil {
+		result = nil
+	}
+	j.write(jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result, Error: err})
+}
+
+func (j *jsonRPCSession) write(msg any) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// Encoder terminates each message with a newline
+	_ = json.NewEncoder(j.out).Encode(msg)
+}
+
+func toJSONRPCError(err error) *jsonRPCError {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errInvalidParams) {
+		return &jsonRPCError{Code: jsonRPCInvalidParams, Message: err.Error()}
+	}
+	st := status.Convert(toStatus(err))
+	return &jsonRPCError{Code: jsonRPCServerError, Message: st.Message(), Data: st.Code().String()}
+}
@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		ID      json.RawMessage `json:"id"`
+		Message map[string]any  `json:"message"`
+	} `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
+}
+
+func serveJSONRPC(t *testing.T, backend api.Compose, events *api.EventProcessor, requests ...string) []rpcMessage {
+	t.Helper()
+	server := NewServer(func(ep api.EventProcessor) (api.Compose, error) {
+		if events != nil {
+			*events = ep
+		}
+		return backend, nil
+	})
+	var out bytes.Buffer
+	err := server.ServeJSONRPC(t.Context(), strings.NewReader(strings.Join(requests, "\n")), &out)
+	assert.NilError(t, err)
+
+	var messages []rpcMessage
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var m rpcMessage
+		assert.NilError(t, dec.Decode(&m))
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+func TestJSONRPCList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().List(gomock.Any(), api.ListOptions{}).Return([]api.Stack{{Name: "myproject"}}, nil)
+
+	messages := serveJSONRPC(t, backend, nil, `{"jsonrpc":"2.0","id":1,"method":"List"}`)
+	assert.Equal(t, len(messages), 1)
+	assert.Equal(t, string(messages[0].ID), "1")
+	assert.Assert(t, messages[0].Error == nil)
+	var resp ListResponse
+	assert.NilError(t, json.Unmarshal(messages[0].Result, &resp))
+	assert.Equal(t, resp.Stacks[0].Name, "myproject")
+}
+
+func TestJSONRPCProgressNotifications(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	var events api.EventProcessor
+	backend.EXPECT().Stop(gomock.Any(), "myproject", api.StopOptions{}).
+		DoAndReturn(func(context.Context, string, api.StopOptions) error {
+			events.On(api.Resource{ID: "Container myproject-web-1", Status: api.Done, Text: "Stopped"})
+			return nil
+		})
+
+	messages := serveJSONRPC(t, backend, &events,
+		`{"jsonrpc":"2.0","id":"stop","method":"Stop","params":{"project":{"project_name":"myproject"}}}`)
+	assert.Equal(t, len(messages), 2)
+	assert.Equal(t, messages[0].Method, "progress")
+	assert.Equal(t, string(messages[0].Params.ID), `"stop"`)
+	assert.Equal(t, messages[0].Params.Message["text"], "Stopped")
+	assert.Equal(t, string(messages[1].ID), `"stop"`)
+	assert.Equal(t, string(messages[1].Result), "{}")
+}
+
+func TestJSONRPCCancel(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ api.LogConsumer, _ api.LogOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	messages := serveJSONRPC(t, backend, nil,
+		`{"jsonrpc":"2.0","id":7,"method":"Logs","params":{"project":{"project_name":"myproject"},"follow":true}}`,
+		`{"jsonrpc":"2.0","method":"$/cancel","params":{"id":7}}`)
+	assert.Equal(t, len(messages), 1)
+	assert.Equal(t, string(messages[0].ID), "7")
+	assert.Equal(t, messages[0].Error.Data, "Canceled")
+}
+
+func TestJSONRPCCancelOnEOF(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ api.LogConsumer, _ api.LogOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	// input is closed while logs are followed, running request must be canceled rather than waited for
+	messages := serveJSONRPC(t, backend, nil,
+		`{"jsonrpc":"2.0","id":7,"method":"Logs","params":{"project":{"project_name":"myproject"},"follow":true}}`)
+	assert.Equal(t, len(messages), 1)
+	assert.Equal(t, string(messages[0].ID), "7")
+	assert.Equal(t, messages[0].Error.Data, "Canceled")
+}
+
+func TestJSONRPCDuplicateID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ api.LogConsumer, _ api.LogOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Times(1)
+
+	logs := `{"jsonrpc":"2.0","id":7,"method":"Logs","params":{"project":{"project_name":"myproject"},"follow":true}}`
+	messages := serveJSONRPC(t, backend, nil, logs, logs)
+	assert.Equal(t, len(messages), 2)
+	assert.Equal(t, string(messages[0].ID), "7")
+	assert.Equal(t, messages[0].Error.Code, jsonRPCInvalidRequest)
+	assert.Equal(t, messages[0].Error.Message, "request 7 is already running")
+	assert.Equal(t, messages[1].Error.Data, "Canceled")
+}
+
+func TestJSONRPCErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+
+	messages := serveJSONRPC(t, backend, nil,
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"Unknown"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"Ps","params":{"all":"yes"}}`,
+	)
+	assert.Equal(t, len(messages), 3)
+	codes := map[string]int{}
+	for _, m := range messages {
+		codes[string(m.ID)] = m.Error.Code
+	}
+	assert.DeepEqual(t, codes, map[string]int{
+		"null": jsonRPCParseError,
+		"1":    jsonRPCMethodNotFound,
+		"2":    jsonRPCInvalidParams,
+	})
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+const unixScheme = "unix://"
+
+// Serve listens on address and serves requests until ctx is done.
+// Only unix sockets are supported: access is restricted to the user running the server by socket file permissions.
+func (s *Server) Serve(ctx context.Context, address string) error {
+	listener, err := listen(address)
+	if err != nil {
+		return err
+	}
+	g := grpc.NewServer(grpc.ForceServerCodec(jsonCodec{}))
+	s.register(g)
+
+	go func() {
+		<-ctx.Done()
+		g.Stop()
+	}()
+	logrus.Debugf("serving Compose API on %s", address)
+	err = g.Serve(listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// socketPath returns the path of the unix socket set by address
+func socketPath(address string) (string, error) {
+	path, ok := strings.CutPrefix(address, unixScheme)
+	if !ok || path == "" {
+		return "", fmt.Errorf("unsupported listen address %q, expected %s<path>", address, unixScheme)
+	}
+	return path, nil
+}
+
+func listen(address string) (net.Listener, error) {
+	path, err := socketPath(address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	// remove a stale socket left by a previous server, but never another kind of file
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s already exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	// bind the socket in a private directory, so it is never reachable by other users before its permissions
+	// are restricted, then move it in place
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".compose-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+	bound := filepath.Join(dir, "sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: bound, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	listener.SetUnlinkOnClose(false)
+	if err := os.Chmod(bound, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(bound, path); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return &socketListener{Listener: listener, path: path}, nil
+}
+
+// socketListener removes the socket file once closed, as it was moved from the path the listener was bound to
+type socketListener struct {
+	net.Listener
+	path string
+}
+
+func (l *socketListener) Close() error {
+	err := l.Listener.Close()
+	if rmErr := os.Remove(l.path); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) && err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// Project identifies the Compose project an operation applies to. When ConfigPaths or WorkingDir
+// are set, project is loaded from compose files, otherwise operation applies to project ProjectName
+type Project struct {
+	ProjectName string   `json:"project_name,omitempty"`
+	ConfigPaths []string `json:"config_paths,omitempty"`
+	WorkingDir  string   `json:"working_dir,omitempty"`
+	EnvFiles    []string `json:"env_files,omitempty"`
+	Profiles    []string `json:"profiles,omitempty"`
+}
+
+// UpRequest is the request of the Up method
+type UpRequest struct {
+	Project       Project  `json:"project"`
+	Services      []string `json:"services,omitempty"`
+	Build         bool     `json:"build,omitempty"`
+	Recreate      string   `json:"recreate,omitempty"`
+	RemoveOrphans bool     `json:"remove_orphans,omitempty"`
+	Wait          bool     `json:"wait,omitempty"`
+	// WaitTimeout in seconds, no timeout if not set
+	WaitTimeout int `json:"wait_timeout,omitempty"`
+}
+
+// DownRequest is the request of the Down method
+type DownRequest struct {
+	Project       Project  `json:"project"`
+	Services      []string `json:"services,omitempty"`
+	RemoveOrphans bool     `json:"remove_orphans,omitempty"`
+	Volumes       bool     `json:"volumes,omitempty"`
+	Images        string   `json:"images,omitempty"`
+}
+
+// StopRequest is the request of the Stop and Restart methods
+type StopRequest struct {
+	Project  Project  `json:"project"`
+	Services []string `json:"services,omitempty"`
+}
+
+// Progress is streamed by the methods applying changes to a project, as operation progresses
+type Progress struct {
+	ID       string `json:"id,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Details  string `json:"details,omitempty"`
+	Current  int64  `json:"current,omitempty"`
+	Total    int64  `json:"total,omitempty"`
+	Percent  int    `json:"percent,omitempty"`
+}
+
+// PsRequest is the request of the Ps method
+type PsRequest struct {
+	Project  Project  `json:"project"`
+	Services []string `json:"services,omitempty"`
+	All      bool     `json:"all,omitempty"`
+}
+
+// PsResponse is the response of the Ps method
+type PsResponse struct {
+	Containers []api.ContainerSummary `json:"containers"`
+}
+
+// ListRequest is the request of the List method
+type ListRequest struct {
+	All bool `json:"all,omitempty"`
+}
+
+// ListResponse is the response of the List method
+type ListResponse struct {
+	Stacks []api.Stack `json:"stacks"`
+}
+
+// LogsRequest is the request of the Logs method
+type LogsRequest struct {
+	Project    Project  `json:"project"`
+	Services   []string `json:"services,omitempty"`
+	Follow     bool     `json:"follow,omitempty"`
+	Tail       string   `json:"tail,omitempty"`
+	Since      string   `json:"since,omitempty"`
+	Timestamps bool     `json:"timestamps,omitempty"`
+}
+
+// LogMessage is streamed by the Logs method
+type LogMessage struct {
+	Container string `json:"container"`
+	// Stream is either stdout, stderr or status
+	Stream  string `json:"stream"`
+	Message string `json:"message"`
+}
+
+// EventsRequest is the request of the Events method
+type EventsRequest struct {
+	Project  Project  `json:"project"`
+	Services []string `json:"services,omitempty"`
+}
+
+// Event is streamed by the Events method
+type Event struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	Service    string            `json:"service"`
+	Container  string            `json:"container"`
+	Status     string            `json:"status"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Empty is the response of methods which don't return data
+type Empty struct{}
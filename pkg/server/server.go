@@ -0,0 +1,370 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// ServiceName is the fully qualified name of the gRPC service exposed by the server
+const ServiceName = "compose.v1.Compose"
+
+// BackendFactory creates the Compose backend used to run a request, reporting progress to events
+type BackendFactory func(events api.EventProcessor) (api.Compose, error)
+
+// Server exposes the Compose API over gRPC
+type Server struct {
+	factory BackendFactory
+}
+
+// NewServer creates a Server running requests with backends created by factory
+func NewServer(factory BackendFactory) *Server {
+	return &Server{factory: factory}
+}
+
+// register adds the Compose service to a gRPC server
+func (s *Server) register(g *grpc.Server) {
+	g.RegisterService(&serviceDesc, s)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Ps", Handler: unaryHandler((*Server).ps)},
+		{MethodName: "List", Handler: unaryHandler((*Server).list)},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Up", Handler: streamHandler((*Server).up), ServerStreams: true},
+		{StreamName: "Down", Handler: streamHandler((*Server).down), ServerStreams: true},
+		{StreamName: "Stop", Handler: streamHandler((*Server).stop), ServerStreams: true},
+		{StreamName: "Restart", Handler: streamHandler((*Server).restart), ServerStreams: true},
+		{StreamName: "Logs", Handler: streamHandler((*Server).logs), ServerStreams: true},
+		{StreamName: "Events", Handler: streamHandler((*Server).events), ServerStreams: true},
+	},
+}
+
+func unaryHandler[Req, Resp any](fn func(*Server, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		resp, err := fn(srv.(*Server), ctx, req)
+		return resp, toStatus(err)
+	}
+}
+
+// streamMethod is a method sending messages to the client as it runs
+type streamMethod[Req any] func(s *Server, ctx context.Context, req *Req, send func(msg any)) error
+
+func streamHandler[Req any](fn streamMethod[Req]) grpc.StreamHandler {
+	return func(srv any, stream grpc.ServerStream) error {
+		req := new(Req)
+		if err := stream.RecvMsg(req); err != nil {
+			return err
+		}
+		sender := &streamSender{stream: stream}
+		return toStatus(fn(srv.(*Server), stream.Context(), req, sender.send))
+	}
+}
+
+// toStatus converts Compose errors into gRPC status, so clients can check the error kind
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := codes.Unknown
+	switch {
+	case api.ErrorCode(err) == api.ErrCodePullDenied:
+		code = codes.PermissionDenied
+	case api.ErrorCode(err) == api.ErrCodeHealthTimeout, api.IsTimedOutError(err):
+		code = codes.DeadlineExceeded
+	case api.ErrorCode(err) == api.ErrCodePortConflict, api.ErrorCode(err) == api.ErrCodeDependencyFailed:
+		code = codes.FailedPrecondition
+	case api.IsNotFoundError(err):
+		code = codes.NotFound
+	case api.IsAlreadyExistsError(err):
+		code = codes.AlreadyExists
+	case api.IsForbiddenError(err):
+		code = codes.PermissionDenied
+	case api.IsErrNotImplemented(err), api.IsErrUnsupportedFlag(err):
+		code = codes.Unimplemented
+	case api.IsErrCanceled(err), errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+// streamSender serializes messages sent on a stream, as backend reports progress from concurrent goroutines
+type streamSender struct {
+	mu     sync.Mutex
+	stream grpc.ServerStream
+}
+
+func (s *streamSender) send(msg any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// a failure to send means the client has gone, which cancels the stream context
+	_ = s.stream.SendMsg(msg)
+}
+
+// progressWriter is an api.EventProcessor forwarding progress to the client
+type progressWriter struct {
+	send func(msg any)
+}
+
+func (p *progressWriter) Start(context.Context, string) {}
+
+func (p *progressWriter) On(events ...api.Resource) {
+	for _, e := range events {
+		p.send(&Progress{
+			ID:       e.ID,
+			ParentID: e.ParentID,
+			Status:   e.StatusText(),
+			Text:     e.Text,
+			Details:  e.Details,
+			Current:  e.Current,
+			Total:    e.Total,
+			Percent:  e.Percent,
+		})
+	}
+}
+
+func (p *progressWriter) Done(string, bool) {}
+
+// quietProcessor is used by requests which don't report progress
+type quietProcessor struct{}
+
+func (quietProcessor) Start(context.Context, string) {}
+func (quietProcessor) On(...api.Resource)            {}
+func (quietProcessor) Done(string, bool)             {}
+
+// loadProject loads the project from compose files, or returns nil when request only set a project name
+func loadProject(ctx context.Context, backend api.Compose, p Project, services []string) (*types.Project, error) {
+	if len(p.ConfigPaths) == 0 && p.WorkingDir == "" {
+		if p.ProjectName == "" {
+			return nil, status.Error(codes.InvalidArgument, "project name or compose files must be set")
+		}
+		return nil, nil
+	}
+	return backend.LoadProject(ctx, api.ProjectLoadOptions{
+		ProjectName: p.ProjectName,
+		ConfigPaths: p.ConfigPaths,
+		WorkingDir:  p.WorkingDir,
+		EnvFiles:    p.EnvFiles,
+		Profiles:    p.Profiles,
+		Services:    services,
+	})
+}
+
+func projectName(project *types.Project, p Project) string {
+	if project != nil {
+		return project.Name
+	}
+	return p.ProjectName
+}
+
+func (s *Server) ps(ctx context.Context, req *PsRequest) (*PsResponse, error) {
+	backend, err := s.factory(quietProcessor{})
+	if err != nil {
+		return nil, err
+	}
+	project, err := loadProject(ctx, backend, req.Project, req.Services)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := backend.Ps(ctx, projectName(project, req.Project), api.PsOptions{
+		Project:  project,
+		All:      req.All,
+		Services: req.Services,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PsResponse{Containers: containers}, nil
+}
+
+func (s *Server) list(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	backend, err := s.factory(quietProcessor{})
+	if err != nil {
+		return nil, err
+	}
+	stacks, err := backend.List(ctx, api.ListOptions{All: req.All})
+	if err != nil {
+		return nil, err
+	}
+	return &ListResponse{Stacks: stacks}, nil
+}
+
+// progressBackend creates a backend reporting progress to the client
+func (s *Server) progressBackend(send func(msg any)) (api.Compose, error) {
+	return s.factory(&progressWriter{send: send})
+}
+
+func (s *Server) up(ctx context.Context, req *UpRequest, send func(msg any)) error {
+	backend, err := s.progressBackend(send)
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, req.Services)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return status.Error(codes.InvalidArgument, "up requires compose files to load project")
+	}
+	var build *api.BuildOptions
+	if req.Build {
+		build = &api.BuildOptions{Services: req.Services}
+	}
+	return backend.Up(ctx, project, api.UpOptions{
+		Create: api.CreateOptions{
+			Build:         build,
+			Services:      req.Services,
+			RemoveOrphans: req.RemoveOrphans,
+			Recreate:      req.Recreate,
+		},
+		Start: api.StartOptions{
+			Project:     project,
+			Services:    req.Services,
+			Wait:        req.Wait,
+			WaitTimeout: time.Duration(req.WaitTimeout) * time.Second,
+		},
+	})
+}
+
+func (s *Server) down(ctx context.Context, req *DownRequest, send func(msg any)) error {
+	backend, err := s.progressBackend(send)
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, nil)
+	if err != nil {
+		return err
+	}
+	return backend.Down(ctx, projectName(project, req.Project), api.DownOptions{
+		Project:       project,
+		Services:      req.Services,
+		RemoveOrphans: req.RemoveOrphans,
+		Volumes:       req.Volumes,
+		Images:        req.Images,
+	})
+}
+
+func (s *Server) stop(ctx context.Context, req *StopRequest, send func(msg any)) error {
+	backend, err := s.progressBackend(send)
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, nil)
+	if err != nil {
+		return err
+	}
+	return backend.Stop(ctx, projectName(project, req.Project), api.StopOptions{
+		Project:  project,
+		Services: req.Services,
+	})
+}
+
+func (s *Server) restart(ctx context.Context, req *StopRequest, send func(msg any)) error {
+	backend, err := s.progressBackend(send)
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, nil)
+	if err != nil {
+		return err
+	}
+	return backend.Restart(ctx, projectName(project, req.Project), api.RestartOptions{
+		Project:  project,
+		Services: req.Services,
+	})
+}
+
+// logWriter is an api.LogConsumer forwarding logs to the client
+type logWriter struct {
+	send func(msg any)
+}
+
+func (l *logWriter) Log(containerName, message string) {
+	l.send(&LogMessage{Container: containerName, Stream: "stdout", Message: message})
+}
+
+func (l *logWriter) Err(containerName, message string) {
+	l.send(&LogMessage{Container: containerName, Stream: "stderr", Message: message})
+}
+
+func (l *logWriter) Status(containerName, message string) {
+	l.send(&LogMessage{Container: containerName, Stream: "status", Message: message})
+}
+
+func (s *Server) logs(ctx context.Context, req *LogsRequest, send func(msg any)) error {
+	backend, err := s.factory(quietProcessor{})
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, req.Services)
+	if err != nil {
+		return err
+	}
+	return backend.Logs(ctx, projectName(project, req.Project), &logWriter{send: send}, api.LogOptions{
+		Project:    project,
+		Services:   req.Services,
+		Follow:     req.Follow,
+		Tail:       req.Tail,
+		Since:      req.Since,
+		Timestamps: req.Timestamps,
+	})
+}
+
+func (s *Server) events(ctx context.Context, req *EventsRequest, send func(msg any)) error {
+	backend, err := s.factory(quietProcessor{})
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(ctx, backend, req.Project, req.Services)
+	if err != nil {
+		return err
+	}
+	return backend.Events(ctx, projectName(project, req.Project), api.EventsOptions{
+		Services: req.Services,
+		Consumer: func(e api.Event) error {
+			send(&Event{
+				Timestamp:  e.Timestamp,
+				Service:    e.Service,
+				Container:  e.Container,
+				Status:     e.Status,
+				Attributes: e.Attributes,
+			})
+			return nil
+		},
+	})
+}
@@ -0,0 +1,198 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func startServer(t *testing.T, backend api.Compose, events *api.EventProcessor) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "run", "compose.sock")
+	address := "unix://" + socket
+	server := NewServer(func(ep api.EventProcessor) (api.Compose, error) {
+		if events != nil {
+			*events = ep
+		}
+		return backend, nil
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, address)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NilError(t, <-done)
+	})
+
+	assert.Assert(t, waitForSocket(socket))
+	fi, err := os.Stat(socket)
+	assert.NilError(t, err)
+	assert.Equal(t, fi.Mode().Perm(), os.FileMode(0o600))
+
+	client, err := NewClient(address)
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+func waitForSocket(path string) bool {
+	for range 100 {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestServerList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().List(gomock.Any(), api.ListOptions{All: true}).Return([]api.Stack{
+		{Name: "myproject", Status: "running(1)"},
+	}, nil)
+
+	client := startServer(t, backend, nil)
+	resp, err := client.List(t.Context(), ListRequest{All: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, resp.Stacks, []api.Stack{{Name: "myproject", Status: "running(1)"}})
+}
+
+func TestServerDownStreamsProgress(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	var events api.EventProcessor
+	backend.EXPECT().Down(gomock.Any(), "myproject", api.DownOptions{Volumes: true}).
+		DoAndReturn(func(context.Context, string, api.DownOptions) error {
+			events.On(api.Resource{ID: "Container myproject-web-1", Status: api.Working, Text: "Removing"})
+			events.On(api.Resource{ID: "Container myproject-web-1", Status: api.Done, Text: "Removed"})
+			return nil
+		})
+
+	client := startServer(t, backend, &events)
+	var progress []Progress
+	err := client.Down(t.Context(), DownRequest{
+		Project: Project{ProjectName: "myproject"},
+		Volumes: true,
+	}, func(p Progress) {
+		progress = append(progress, p)
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, progress, []Progress{
+		{ID: "Container myproject-web-1", Status: "Working", Text: "Removing"},
+		{ID: "Container myproject-web-1", Status: "Done", Text: "Removed"},
+	})
+}
+
+func TestServerLogs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Logs(gomock.Any(), "myproject", gomock.Any(), api.LogOptions{Services: []string{"web"}, Tail: "10"}).
+		DoAndReturn(func(_ context.Context, _ string, consumer api.LogConsumer, _ api.LogOptions) error {
+			consumer.Log("myproject-web-1", "hello")
+			consumer.Err("myproject-web-1", "oops")
+			return nil
+		})
+
+	client := startServer(t, backend, nil)
+	var logs []LogMessage
+	err := client.Logs(t.Context(), LogsRequest{
+		Project:  Project{ProjectName: "myproject"},
+		Services: []string{"web"},
+		Tail:     "10",
+	}, func(m LogMessage) {
+		logs = append(logs, m)
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, logs, []LogMessage{
+		{Container: "myproject-web-1", Stream: "stdout", Message: "hello"},
+		{Container: "myproject-web-1", Stream: "stderr", Message: "oops"},
+	})
+}
+
+func TestServerErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mocks.NewMockCompose(mockCtrl)
+	backend.EXPECT().Ps(gomock.Any(), "unknown", gomock.Any()).Return(nil, fmt.Errorf("no such project: %w", api.ErrNotFound))
+
+	client := startServer(t, backend, nil)
+	_, err := client.Ps(t.Context(), PsRequest{Project: Project{ProjectName: "unknown"}})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	_, err = client.Ps(t.Context(), PsRequest{})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	err = client.Up(t.Context(), UpRequest{Project: Project{ProjectName: "myproject"}}, func(Progress) {})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
+
+func TestToStatusErrCode(t *testing.T) {
+	err := toStatus(api.WithErrCode(fmt.Errorf("pull access denied"), api.ErrCodePullDenied))
+	assert.Equal(t, status.Code(err), codes.PermissionDenied)
+	err = toStatus(api.WithErrCode(fmt.Errorf("application not healthy after 10s"), api.ErrCodeHealthTimeout))
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded)
+	err = toStatus(api.WithErrCode(fmt.Errorf("port is already allocated"), api.ErrCodePortConflict))
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition)
+}
+
+func TestListenRejectsUnsupportedAddress(t *testing.T) {
+	_, err := listen("tcp://127.0.0.1:2375")
+	assert.ErrorContains(t, err, "unsupported listen address")
+
+	file := filepath.Join(t.TempDir(), "compose.sock")
+	assert.NilError(t, os.WriteFile(file, nil, 0o600))
+	_, err = listen("unix://" + file)
+	assert.ErrorContains(t, err, "is not a socket")
+}
+
+func TestListenSocket(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "compose.sock")
+	listener, err := listen("unix://" + socket)
+	assert.NilError(t, err)
+
+	fi, err := os.Stat(socket)
+	assert.NilError(t, err)
+	assert.Equal(t, fi.Mode().Type(), fs.ModeSocket)
+	assert.Equal(t, fi.Mode().Perm(), os.FileMode(0o600))
+	// private directory the socket was bound in has been removed
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+
+	assert.NilError(t, listener.Close())
+	_, err = os.Stat(socket)
+	assert.Assert(t, os.IsNotExist(err))
+}
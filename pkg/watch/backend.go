@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Backend selects the implementation used to receive filesystem events
+type Backend string
+
+const (
+	// BackendAuto selects the native backend for the platform: FSEvents on macOS, fsnotify otherwise
+	BackendAuto Backend = "auto"
+	// BackendFSNotify uses fsnotify (inotify, kqueue or ReadDirectoryChangesW)
+	BackendFSNotify Backend = "fsnotify"
+	// BackendFSEvents uses macOS FSEvents
+	BackendFSEvents Backend = "fsevents"
+	// BackendPolling periodically scans watched paths, slower to report changes but reliable on any filesystem
+	BackendPolling Backend = "polling"
+	// BackendWatchman delegates to a watchman server, which scales to very large source trees
+	BackendWatchman Backend = "watchman"
+)
+
+// BackendEnvVar sets the default backend for watch rules not selecting one explicitly
+const BackendEnvVar = "COMPOSE_WATCH_BACKEND"
+
+// PollIntervalEnvVar sets the interval used by the polling backend to scan watched paths
+const PollIntervalEnvVar = "COMPOSE_WATCH_POLL_INTERVAL"
+
+const defaultPollInterval = time.Second
+
+// ErrEventsDropped is reported on the Errors channel when the backend could not keep up with filesystem events,
+// so some changes have been missed
+var ErrEventsDropped = errors.New("file events were dropped")
+
+// errBackendUnavailable is returned by backends which can't be used on this platform or host
+var errBackendUnavailable = errors.New("watch backend unavailable")
+
+var numberOfDroppedEvents = expvar.NewInt("watch.numberOfDroppedEvents")
+
+// ParseBackend validates a backend name, empty string selecting BackendAuto
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(s); b {
+	case "":
+		return BackendAuto, nil
+	case BackendAuto, BackendFSNotify, BackendFSEvents, BackendPolling, BackendWatchman:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unsupported watch backend %q, expected one of %s, %s, %s, %s or %s",
+			s, BackendAuto, BackendFSNotify, BackendFSEvents, BackendPolling, BackendWatchman)
+	}
+}
+
+// DefaultBackend returns the backend set by BackendEnvVar, or BackendAuto
+func DefaultBackend() (Backend, error) {
+	return ParseBackend(os.Getenv(BackendEnvVar))
+}
+
+func DesiredPollInterval() time.Duration {
+	envVar := os.Getenv(PollIntervalEnvVar)
+	if envVar != "" {
+		interval, err := time.ParseDuration(envVar)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultPollInterval
+}
+
+// NewWatcherWithBackend creates a watcher using the selected backend. When this one is not available,
+// it falls back to the native backend for the platform.
+func NewWatcherWithBackend(paths []string, backend Backend) (Notify, error) {
+	var (
+		w   Notify
+		err error
+	)
+	switch backend {
+	case BackendAuto, "":
+		return newWatcher(paths)
+	case BackendFSNotify:
+		return newNaiveWatcher(paths)
+	case BackendPolling:
+		return newPollingWatcher(paths, DesiredPollInterval())
+	case BackendFSEvents:
+		w, err = newFSEventsWatcher(paths)
+	case BackendWatchman:
+		w, err = newWatchmanWatcher(paths)
+	default:
+		return nil, fmt.Errorf("unsupported watch backend %q", backend)
+	}
+	if errors.Is(err, errBackendUnavailable) {
+		logrus.Warnf("%v, falling back to native file events", err)
+		return newWatcher(paths)
+	}
+	return w, err
+}
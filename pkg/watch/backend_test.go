@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackend(t *testing.T) {
+	b, err := ParseBackend("")
+	require.NoError(t, err)
+	assert.Equal(t, BackendAuto, b)
+
+	b, err = ParseBackend("polling")
+	require.NoError(t, err)
+	assert.Equal(t, BackendPolling, b)
+
+	_, err = ParseBackend("inotify")
+	require.ErrorContains(t, err, `unsupported watch backend "inotify"`)
+}
+
+func TestNewWatcherWithBackendFallback(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("fsevents is available on macOS")
+	}
+	w, err := NewWatcherWithBackend([]string{t.TempDir()}, BackendFSEvents)
+	require.NoError(t, err)
+	_, ok := w.(*naiveNotify)
+	assert.True(t, ok, "expected fallback to fsnotify, got %T", w)
+	require.NoError(t, w.Close())
+
+	if _, err := exec.LookPath("watchman"); err == nil {
+		t.Skip("watchman is installed")
+	}
+	w, err = NewWatcherWithBackend([]string{t.TempDir()}, BackendWatchman)
+	require.NoError(t, err)
+	_, ok = w.(*naiveNotify)
+	assert.True(t, ok, "expected fallback to fsnotify, got %T", w)
+	require.NoError(t, w.Close())
+}
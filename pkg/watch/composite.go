@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"errors"
+	"sync"
+)
+
+// compositeNotify merges events and errors from multiple watchers, typically using distinct backends
+type compositeNotify struct {
+	watchers []Notify
+	events   chan FileEvent
+	errors   chan error
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	closed   sync.Once
+	closeErr error
+}
+
+// NewCompositeNotify creates a Notify merging events from all watchers
+func NewCompositeNotify(watchers ...Notify) Notify {
+	if len(watchers) == 1 {
+		return watchers[0]
+	}
+	return &compositeNotify{
+		watchers: watchers,
+		events:   make(chan FileEvent),
+		errors:   make(chan error),
+		stop:     make(chan struct{}),
+	}
+}
+
+func (c *compositeNotify) Start() error {
+	for _, w := range c.watchers {
+		if err := w.Start(); err != nil {
+			return err
+		}
+		c.wg.Add(2)
+		go c.forwardEvents(w.Events())
+		go c.forwardErrors(w.Errors())
+	}
+	return nil
+}
+
+func (c *compositeNotify) forwardEvents(events chan FileEvent) {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			select {
+			case c.events <- e:
+			case <-c.stop:
+				return
+			}
+		}
+	}
+}
+
+func (c *compositeNotify) forwardErrors(errs chan error) {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			select {
+			case c.errors <- err:
+			case <-c.stop:
+				return
+			}
+		}
+	}
+}
+
+// Close stops all watchers, then closes events and errors channels once nothing can be forwarded anymore,
+// so consumers ranging over them terminate
+func (c *compositeNotify) Close() error {
+	c.closed.Do(func() {
+		close(c.stop)
+		var errs []error
+		for _, w := range c.watchers {
+			errs = append(errs, w.Close())
+		}
+		c.wg.Wait()
+		close(c.events)
+		close(c.errors)
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
+
+func (c *compositeNotify) Events() chan FileEvent {
+	return c.events
+}
+
+func (c *compositeNotify) Errors() chan error {
+	return c.errors
+}
+
+var _ Notify = &compositeNotify{}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotify struct {
+	events chan FileEvent
+	errors chan error
+}
+
+func newFakeNotify() *fakeNotify {
+	return &fakeNotify{events: make(chan FileEvent), errors: make(chan error)}
+}
+
+func (f *fakeNotify) Start() error           { return nil }
+func (f *fakeNotify) Close() error           { return nil }
+func (f *fakeNotify) Events() chan FileEvent { return f.events }
+func (f *fakeNotify) Errors() chan error     { return f.errors }
+
+func TestCompositeNotifyClose(t *testing.T) {
+	first, second := newFakeNotify(), newFakeNotify()
+	w := NewCompositeNotify(first, second)
+	require.NoError(t, w.Start())
+
+	done := make(chan []FileEvent)
+	go func() {
+		var received []FileEvent
+		for e := range w.Events() {
+			received = append(received, e)
+		}
+		done <- received
+	}()
+	second.events <- FileEvent("/foo")
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	select {
+	case received := <-done:
+		assert.Equal(t, []FileEvent{"/foo"}, received)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events channel has not been closed")
+	}
+	_, ok := <-w.Errors()
+	assert.False(t, ok)
+}
@@ -26,6 +26,7 @@ import (
 
 	pathutil "github.com/docker/compose/v5/internal/paths"
 	"github.com/fsnotify/fsevents"
+	"github.com/sirupsen/logrus"
 )
 
 // A file watcher optimized for Darwin.
@@ -52,6 +53,12 @@ func (d *fseventNotify) loop() {
 			for _, e := range events {
 				e.Path = filepath.Join(string(os.PathSeparator), e.Path)
 
+				if e.Flags&(fsevents.MustScanSubDirs|fsevents.UserDropped|fsevents.KernelDropped) != 0 {
+					// events have been coalesced or dropped for this path
+					numberOfDroppedEvents.Add(1)
+					logrus.Warnf("%v for %s, some changes may have been missed", ErrEventsDropped, e.Path)
+				}
+
 				_, isPathWereWatching := d.pathsWereWatching[e.Path]
 				if e.Flags&fsevents.ItemIsDir == fsevents.ItemIsDir && e.Flags&fsevents.ItemCreated == fsevents.ItemCreated && isPathWereWatching {
 					// This is the first create for the path that we're watching. We always get exactly one of these
@@ -109,6 +116,10 @@ func (d *fseventNotify) Errors() chan error {
 }
 
 func newWatcher(paths []string) (Notify, error) {
+	return newFSEventsWatcher(paths)
+}
+
+func newFSEventsWatcher(paths []string) (Notify, error) {
 	dw := &fseventNotify{
 		stream: &fsevents.EventStream{
 			Latency: 50 * time.Millisecond,
@@ -1,5 +1,3 @@
-//go:build !darwin
-
 /*
    Copyright 2020 Docker Compose CLI authors
 
@@ -19,6 +17,7 @@
 package watch
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -32,7 +31,7 @@ import (
 )
 
 // A naive file watcher that uses the plain fsnotify API.
-// Used on all non-Darwin systems (including Windows & Linux).
+// Used by default on all non-Darwin systems (including Windows & Linux).
 //
 // All OS-specific codepaths are handled by fsnotify.
 type naiveNotify struct {
@@ -217,6 +216,18 @@ func (d *naiveNotify) loop() { //nolint:gocyclo
 	}
 }
 
+// forwardErrors reports fsnotify errors, translating event queue overflow into ErrEventsDropped
+func (d *naiveNotify) forwardErrors(errs chan error) {
+	defer close(d.errors)
+	for err := range errs {
+		if errors.Is(err, fsnotify.ErrEventOverflow) {
+			numberOfDroppedEvents.Add(1)
+			err = ErrEventsDropped
+		}
+		d.errors <- err
+	}
+}
+
 func (d *naiveNotify) shouldNotify(path string) bool {
 	if _, ok := d.notifyList[path]; ok {
 		// We generally don't care when directories change at the root of an ADD
@@ -269,7 +280,7 @@ func (d *naiveNotify) add(path string) error {
 	return nil
 }
 
-func newWatcher(paths []string) (Notify, error) {
+func newNaiveWatcher(paths []string) (Notify, error) {
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		if strings.Contains(err.Error(), "too many open files") && runtime.GOOS == "linux" {
@@ -302,9 +313,10 @@ func newWatcher(paths []string) (Notify, error) {
 		watcher:            fsw,
 		events:             fsw.Events,
 		wrappedEvents:      wrappedEvents,
-		errors:             fsw.Errors,
+		errors:             make(chan error),
 		isWatcherRecursive: isWatcherRecursive,
 	}
+	go wmw.forwardErrors(fsw.Errors)
 
 	return wmw, nil
 }
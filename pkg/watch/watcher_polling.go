@@ -0,0 +1,153 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	pathutil "github.com/docker/compose/v5/internal/paths"
+)
+
+// A file watcher periodically scanning watched paths.
+// Slower to report changes than native file events, but doesn't depend on the filesystem
+// or the kernel event queue, so events can't be dropped.
+type pollingNotify struct {
+	paths    []string
+	interval time.Duration
+	state    map[string]fileState
+	events   chan FileEvent
+	errors   chan error
+	stop     chan struct{}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+func newPollingWatcher(paths []string, interval time.Duration) (Notify, error) {
+	var watched []string
+	for _, path := range pathutil.EncompassingPaths(paths) {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("newWatcher: %w", err)
+		}
+		watched = append(watched, path)
+	}
+	return &pollingNotify{
+		paths:    watched,
+		interval: interval,
+		events:   make(chan FileEvent),
+		errors:   make(chan error),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+func (d *pollingNotify) Start() error {
+	if len(d.paths) == 0 {
+		return nil
+	}
+	numberOfWatches.Add(int64(len(d.paths)))
+	d.state = d.scan()
+	go d.loop()
+	return nil
+}
+
+func (d *pollingNotify) loop() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			current := d.scan()
+			for _, path := range changedPaths(d.state, current) {
+				select {
+				case d.events <- NewFileEvent(path):
+				case <-d.stop:
+					return
+				}
+			}
+			d.state = current
+		}
+	}
+}
+
+// scan collects state of all files and directories under watched paths, missing ones being ignored
+func (d *pollingNotify) scan() map[string]fileState {
+	state := map[string]fileState{}
+	for _, root := range d.paths {
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// file removed while walking or not readable, will be reported as removed if it was known
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			state[path] = fileState{
+				modTime: info.ModTime(),
+				size:    info.Size(),
+				mode:    info.Mode(),
+			}
+			return nil
+		})
+	}
+	return state
+}
+
+// changedPaths lists paths created, updated or removed between two scans. As for native watchers,
+// changes to directories are not reported, only the files they contain.
+func changedPaths(previous, current map[string]fileState) []string {
+	var changed []string
+	for path, s := range current {
+		p, ok := previous[path]
+		if ok && (p == s || s.mode.IsDir()) {
+			continue
+		}
+		changed = append(changed, path)
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+func (d *pollingNotify) Close() error {
+	numberOfWatches.Add(int64(-len(d.paths)))
+	close(d.stop)
+	close(d.errors)
+	return nil
+}
+
+func (d *pollingNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *pollingNotify) Errors() chan error {
+	return d.errors
+}
+
+var _ Notify = &pollingNotify{}
@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollingWatcher(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	require.NoError(t, os.WriteFile(existing, []byte("hello"), 0o644))
+
+	w, err := newPollingWatcher([]string{dir}, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, w.Start())
+	defer w.Close() //nolint:errcheck
+
+	created := filepath.Join(dir, "created.txt")
+	require.NoError(t, os.WriteFile(created, []byte("hello"), 0o644))
+	assertPolledEvent(t, w, created)
+
+	require.NoError(t, os.Remove(existing))
+	assertPolledEvent(t, w, existing)
+}
+
+func assertPolledEvent(t *testing.T, w Notify, expected string) {
+	t.Helper()
+	select {
+	case e := <-w.Events():
+		assert.Equal(t, FileEvent(expected), e)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for event on %s", expected)
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"/src":           {modTime: now, mode: os.ModeDir},
+		"/src/unchanged": {modTime: now, size: 1},
+		"/src/updated":   {modTime: now, size: 1},
+		"/src/removed":   {modTime: now, size: 1},
+	}
+	current := map[string]fileState{
+		"/src":           {modTime: now.Add(time.Second), mode: os.ModeDir},
+		"/src/unchanged": {modTime: now, size: 1},
+		"/src/updated":   {modTime: now.Add(time.Second), size: 2},
+		"/src/created":   {modTime: now, size: 1},
+	}
+	assert.ElementsMatch(t, []string{"/src/updated", "/src/created", "/src/removed"}, changedPaths(previous, current))
+}
@@ -0,0 +1,220 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	pathutil "github.com/docker/compose/v5/internal/paths"
+	"github.com/sirupsen/logrus"
+)
+
+// A file watcher delegating to a watchman server, see https://facebook.github.io/watchman/
+// A persistent watchman client is run for each watched root, subscribing to changes.
+type watchmanNotify struct {
+	bin     string
+	paths   []string
+	roots   map[string]watchmanRoot
+	events  chan FileEvent
+	errors  chan error
+	stop    chan struct{}
+	clients []*exec.Cmd
+	wg      sync.WaitGroup
+}
+
+// watchmanRoot is the root of a watchman watch, with the relative path we subscribe to
+type watchmanRoot struct {
+	root     string
+	relative string
+}
+
+// watchmanResponse is the subset of watchman protocol data units we rely on
+type watchmanResponse struct {
+	Watch           string   `json:"watch"`
+	RelativePath    string   `json:"relative_path"`
+	Subscription    string   `json:"subscription"`
+	Root            string   `json:"root"`
+	Files           []string `json:"files"`
+	IsFreshInstance bool     `json:"is_fresh_instance"`
+	Warning         string   `json:"warning"`
+	Error           string   `json:"error"`
+}
+
+const watchmanSubscription = "docker-compose"
+
+func newWatchmanWatcher(paths []string) (Notify, error) {
+	bin, err := exec.LookPath("watchman")
+	if err != nil {
+		return nil, fmt.Errorf("%w: watchman executable not found in PATH", errBackendUnavailable)
+	}
+
+	d := &watchmanNotify{
+		bin:    bin,
+		roots:  map[string]watchmanRoot{},
+		events: make(chan FileEvent),
+		errors: make(chan error),
+		stop:   make(chan struct{}),
+	}
+	for _, path := range pathutil.EncompassingPaths(paths) {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("newWatcher: %w", err)
+		}
+		d.paths = append(d.paths, path)
+
+		// watchman can only watch existing directories
+		existing, err := greatestExistingAncestor(path)
+		if err != nil {
+			return nil, err
+		}
+		out, err := exec.Command(bin, "--no-pretty", "watch-project", existing).Output()
+		if err != nil {
+			return nil, fmt.Errorf("%w: watchman watch-project %s: %v", errBackendUnavailable, existing, err)
+		}
+		var resp watchmanResponse
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return nil, fmt.Errorf("%w: unexpected watchman response: %v", errBackendUnavailable, err)
+		}
+		if resp.Error != "" {
+			return nil, fmt.Errorf("%w: %s", errBackendUnavailable, resp.Error)
+		}
+		root := watchmanRoot{root: resp.Watch, relative: resp.RelativePath}
+		d.roots[filepath.Join(root.root, root.relative)] = root
+	}
+	return d, nil
+}
+
+func (d *watchmanNotify) Start() error {
+	if len(d.roots) == 0 {
+		return nil
+	}
+	numberOfWatches.Add(int64(len(d.paths)))
+	for _, root := range d.roots {
+		if err := d.subscribe(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribe runs a persistent watchman client, subscribing to changes under root
+func (d *watchmanNotify) subscribe(root watchmanRoot) error {
+	query := map[string]any{
+		"fields":    []string{"name"},
+		"defer_vcs": false,
+	}
+	if root.relative != "" {
+		query["relative_root"] = root.relative
+	}
+	command, err := json.Marshal([]any{"subscribe", root.root, watchmanSubscription, query})
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(d.bin, "--persistent", "--json-command", "--server-encoding=json", "--no-pretty")
+	cmd.Stdin = strings.NewReader(string(command) + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting watchman client: %w", err)
+	}
+	d.clients = append(d.clients, cmd)
+
+	d.wg.Add(1)
+	go d.loop(stdout, filepath.Join(root.root, root.relative))
+	return nil
+}
+
+func (d *watchmanNotify) loop(stdout io.Reader, base string) {
+	defer d.wg.Done()
+	decoder := json.NewDecoder(stdout)
+	for {
+		var resp watchmanResponse
+		if err := decoder.Decode(&resp); err != nil {
+			select {
+			case <-d.stop:
+			case d.errors <- fmt.Errorf("watchman client stopped: %w", err):
+			}
+			return
+		}
+		switch {
+		case resp.Error != "":
+			select {
+			case <-d.stop:
+				return
+			case d.errors <- fmt.Errorf("watchman: %s", resp.Error):
+			}
+			continue
+		case resp.Warning != "":
+			logrus.Debugf("watchman: %s", resp.Warning)
+		}
+		if resp.Subscription != watchmanSubscription || resp.IsFreshInstance {
+			// initial subscription response lists all existing files
+			continue
+		}
+		for _, f := range resp.Files {
+			path := filepath.Join(base, filepath.FromSlash(f))
+			if !d.shouldNotify(path) {
+				continue
+			}
+			select {
+			case <-d.stop:
+				return
+			case d.events <- NewFileEvent(path):
+			}
+		}
+	}
+}
+
+func (d *watchmanNotify) shouldNotify(path string) bool {
+	for _, p := range d.paths {
+		if pathutil.IsChild(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *watchmanNotify) Close() error {
+	numberOfWatches.Add(int64(-len(d.paths)))
+	close(d.stop)
+	for _, cmd := range d.clients {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+	d.wg.Wait()
+	close(d.errors)
+	return nil
+}
+
+func (d *watchmanNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *watchmanNotify) Errors() chan error {
+	return d.errors
+}
+
+var _ Notify = &watchmanNotify{}